@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// batchConcurrency is the number of models of a batch generated at once.
+var batchConcurrency int
+
+// batchCmd generates a model for each of a list of users.
+var batchCmd = &cobra.Command{
+	Use:   "batch [file]",
+	Short: "Generate a model for each user listed in a file or on standard input",
+	Long: `Generate a model for each user listed in a file, or on standard input when no
+file or - is given: one username per line, with blank lines and lines starting
+with # ignored.
+
+Each user's model is generated with the same flags and named by --output, with
+{user} replaced by the username and {year} by the years covered. A user whose
+model fails is reported while the others are still generated.`,
+	Example: `  gh skyline batch team.txt --year 2024 --output "models/{user}-{year}.stl"
+  gh api orgs/octo-org/members --jq '.[].login' | gh skyline batch`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: handleBatchCommand,
+}
+
+// init registers the batch command and its flags.
+func init() {
+	flags := batchCmd.Flags()
+	addFetchFlags(flags)
+	flags.BoolVarP(&full, "full", "f", false, "Generate each user's contribution graph from their join year to the current year")
+	flags.StringVarP(&output, "output", "o", "", fmt.Sprintf("Output file path of each model, with %s for the username and %s for the years (optional)", utils.UserPlaceholder, utils.YearPlaceholder))
+	flags.IntVar(&batchConcurrency, "concurrency", skyline.DefaultBatchConcurrency, "Number of models generated at once")
+	flags.BoolVarP(&quiet, "quiet", "q", false, "Print only the paths of the files written, without informational messages")
+	addModelFlags(flags)
+	rootCmd.AddCommand(batchCmd)
+}
+
+// handleBatchCommand is the main execution function for the batch command.
+func handleBatchCommand(cmd *cobra.Command, args []string) error {
+	path := "-"
+	if len(args) > 0 {
+		path = args[0]
+	}
+	usernames, err := readUsernames(path, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	src, err := loadSource()
+	if err != nil {
+		return err
+	}
+
+	startYear, endYear, from, to, err := parsePeriod(yearRange)
+	if err != nil {
+		return err
+	}
+
+	contributionCache, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	opts, err := modelOptions(cmd, skyline.Options{
+		StartYear:      startYear,
+		EndYear:        endYear,
+		From:           from,
+		To:             to,
+		TimeZone:       src.zone,
+		Full:           full,
+		Output:         output,
+		Cache:          contributionCache,
+		Client:         src.client,
+		Offline:        offline,
+		FilterOrg:      filterOrg,
+		Types:          src.kinds,
+		PublicOnly:     publicOnly,
+		IncludePrivate: includePrivate,
+	})
+	if err != nil {
+		return err
+	}
+	if err := quietOptions(cmd, &opts); err != nil {
+		return err
+	}
+	return skyline.GenerateBatch(cmd.Context(), opts, usernames, batchConcurrency)
+}
+
+// readUsernames reads the users of a batch from the file at path, or from
+// stdin when path is "-": one username per line, ignoring blank lines and
+// comments starting with #.
+func readUsernames(path string, stdin io.Reader) ([]string, error) {
+	r := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to open the list of users", err)
+		}
+		defer func() { _ = file.Close() }()
+		r = file
+	}
+
+	var usernames []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		usernames = append(usernames, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(errors.IOError, "failed to read the list of users", err)
+	}
+	return usernames, nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBatchCmd(t *testing.T) {
+	if cmd, _, err := rootCmd.Find([]string{"batch"}); err != nil || cmd != batchCmd {
+		t.Fatalf("expected batch to be a subcommand of the root command, got %v, %v", cmd, err)
+	}
+
+	flags := batchCmd.Flags()
+	for _, flag := range []string{"year", "full", "types", "token", "offline", "output", "concurrency", "format", "base-width", "scale", "future-days", "split-years"} {
+		if flags.Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+	for _, flag := range []string{"user", "org", "merge-users", "input", "web", "art-only", "export-data"} {
+		if flags.Lookup(flag) != nil {
+			t.Errorf("expected flag %s not to be initialized", flag)
+		}
+	}
+}
+
+func TestReadUsernames(t *testing.T) {
+	list := "# Team\nmona\n\n  hubot  \n# Former members\noctocat\n"
+	path := filepath.Join(t.TempDir(), "team.txt")
+	if err := os.WriteFile(path, []byte(list), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"mona", "hubot", "octocat"}
+
+	tests := []struct {
+		name    string
+		path    string
+		stdin   string
+		want    []string
+		wantErr bool
+	}{
+		{"file", path, "", want, false},
+		{"stdin", "-", list, want, false},
+		{"empty stdin", "-", "", nil, false},
+		{"missing file", filepath.Join(t.TempDir(), "missing.txt"), "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readUsernames(tt.path, strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readUsernames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readUsernames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
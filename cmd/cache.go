@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// Flags choosing the cached contributions listed or cleared.
+var (
+	cacheUser string
+	cacheYear int
+)
+
+// cacheCmd groups the commands managing the contribution cache.
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and prune the cached contributions",
+	Long: `Inspect and prune the contributions cached from GitHub. Each user's
+contributions are cached per year, so generating a model of the same years
+again does not need the GitHub API.`,
+	Args: cobra.NoArgs,
+	// The cache is managed even when the config file is broken
+	Annotations: map[string]string{noConfigAnnotation: ""},
+}
+
+// cachePathCmd prints where the cache is kept.
+var cachePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the directory the contributions are cached in",
+	Args:  cobra.NoArgs,
+	RunE:  handleCachePathCommand,
+}
+
+// cacheListCmd lists the cached contributions.
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the cached users and years, with their size and age",
+	Args:  cobra.NoArgs,
+	RunE:  handleCacheListCommand,
+}
+
+// cacheClearCmd removes cached contributions.
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the cached contributions, of every user and year by default",
+	Example: `  gh skyline cache clear
+  gh skyline cache clear --user mona --year 2024`,
+	Args: cobra.NoArgs,
+	RunE: handleCacheClearCommand,
+}
+
+// init registers the cache commands and their flags.
+func init() {
+	for _, cmd := range []*cobra.Command{cacheListCmd, cacheClearCmd} {
+		cmd.Flags().StringVarP(&cacheUser, "user", "u", "", "Only the contributions of this GitHub user")
+		cmd.Flags().IntVarP(&cacheYear, "year", "y", 0, "Only the contributions of this year")
+	}
+	cacheCmd.AddCommand(cachePathCmd, cacheListCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// handleCachePathCommand is the main execution function for the cache path command.
+func handleCachePathCommand(cmd *cobra.Command, _ []string) error {
+	dir, err := cache.Dir()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), dir)
+	return err
+}
+
+// handleCacheListCommand is the main execution function for the cache list command.
+func handleCacheListCommand(cmd *cobra.Command, _ []string) error {
+	store, err := defaultCache()
+	if err != nil {
+		return err
+	}
+	items, err := store.List(cacheUser, cacheYear)
+	if err != nil {
+		return err
+	}
+	return writeCacheItems(cmd.OutOrStdout(), items, time.Now())
+}
+
+// handleCacheClearCommand is the main execution function for the cache clear command.
+func handleCacheClearCommand(cmd *cobra.Command, _ []string) error {
+	store, err := defaultCache()
+	if err != nil {
+		return err
+	}
+	removed, err := store.Remove(cacheUser, cacheYear)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Removed %s (%s)\n", pluralize(len(removed), "cached year"), formatSize(totalSize(removed)))
+	return err
+}
+
+// defaultCache returns the cache in the user's cache directory, whether or not
+// caching is turned off for generating models.
+func defaultCache() (*cache.Cache, error) {
+	if cacheYear < 0 {
+		return nil, errors.New(errors.ValidationError, "year cannot be negative", nil)
+	}
+	dir, err := cache.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(dir, cache.DefaultTTL)
+}
+
+// writeCacheItems writes a table of cached contributions to w, with how long
+// before now each was fetched, followed by their number and total size.
+func writeCacheItems(w io.Writer, items []cache.Item, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "HOST\tUSER\tYEAR\tSIZE\tFETCHED")
+	for _, item := range items {
+		fetched := "unreadable"
+		if !item.FetchedAt.IsZero() {
+			fetched = formatAge(now.Sub(item.FetchedAt)) + " ago"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", item.Host, item.Username, item.Year, formatSize(item.Size), fetched)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to write the cached contributions", err)
+	}
+	_, err := fmt.Fprintf(w, "%s, %s\n", pluralize(len(items), "cached year"), formatSize(totalSize(items)))
+	return err
+}
+
+// totalSize returns the combined size of items in bytes.
+func totalSize(items []cache.Item) int64 {
+	var total int64
+	for _, item := range items {
+		total += item.Size
+	}
+	return total
+}
+
+// formatSize returns a size in bytes in the largest unit it is at least one
+// of, such as 12.3 KB.
+func formatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / 1024
+	unit := "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if value < 1024 {
+			break
+		}
+		value /= 1024
+		unit = next
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
+
+// formatAge returns a duration rounded to its largest whole unit, such as 3d.
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+// pluralize returns count followed by noun, pluralized unless count is one.
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+	"github.com/spf13/cobra"
+)
+
+func TestCacheCmd(t *testing.T) {
+	for _, name := range []string{"path", "list", "clear"} {
+		if cmd, _, err := rootCmd.Find([]string{"cache", name}); err != nil || cmd.Name() != name {
+			t.Errorf("expected cache %s to be a subcommand, got %v, %v", name, cmd, err)
+		}
+	}
+	for _, cmd := range []*cobra.Command{cacheListCmd, cacheClearCmd} {
+		for _, flag := range []string{"user", "year"} {
+			if cmd.Flags().Lookup(flag) == nil {
+				t.Errorf("expected flag %s of cache %s to be initialized", flag, cmd.Name())
+			}
+		}
+	}
+}
+
+func TestHandleCacheCommands(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func(user string, year int) { cacheUser, cacheYear = user, year }(cacheUser, cacheYear)
+
+	dir, err := cache.Dir()
+	if err != nil {
+		t.Fatalf("cache.Dir() error = %v", err)
+	}
+	store, err := cache.New(dir, cache.DefaultTTL)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+	for _, year := range []int{2023, 2024} {
+		if err := store.Put("github.com", "mona", year, fixtures.GenerateContributionsResponse("mona", year)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	run := func(handle func(*cobra.Command, []string) error, cmd *cobra.Command) string {
+		t.Helper()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		defer cmd.SetOut(nil)
+		if err := handle(cmd, nil); err != nil {
+			t.Fatalf("%s error = %v", cmd.Name(), err)
+		}
+		return buf.String()
+	}
+
+	if got := run(handleCachePathCommand, cachePathCmd); strings.TrimSpace(got) != dir {
+		t.Errorf("cache path printed %q, want %q", got, dir)
+	}
+	cacheUser, cacheYear = "", 0
+	if got := run(handleCacheListCommand, cacheListCmd); !strings.Contains(got, "github.com  mona  2023") || !strings.Contains(got, "2 cached years") {
+		t.Errorf("cache list printed:\n%s", got)
+	}
+	cacheUser, cacheYear = "Mona", 2023
+	if got := run(handleCacheClearCommand, cacheClearCmd); !strings.HasPrefix(got, "Removed 1 cached year (") {
+		t.Errorf("cache clear printed %q", got)
+	}
+	cacheUser, cacheYear = "", 0
+	if got := run(handleCacheListCommand, cacheListCmd); strings.Contains(got, "2023") || !strings.Contains(got, "1 cached year,") {
+		t.Errorf("cache list after clearing 2023 printed:\n%s", got)
+	}
+}
+
+func TestWriteCacheItems(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	items := []cache.Item{
+		{Host: "github.com", Username: "mona", Year: 2024, Size: 2048, FetchedAt: now.Add(-3 * time.Hour)},
+		{Host: "github.com", Username: "mona", Year: 2023, Size: 100},
+	}
+	var buf bytes.Buffer
+	if err := writeCacheItems(&buf, items, now); err != nil {
+		t.Fatalf("writeCacheItems() error = %v", err)
+	}
+	for _, want := range []string{"HOST", "2.0 KB  3h ago", "100 B   unreadable", "2 cached years, 2.1 KB"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("writeCacheItems() output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{5 << 20, "5.0 MB"},
+		{3 << 30, "3.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.size); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{30 * time.Second, "30s"},
+		{90 * time.Minute, "1h"},
+		{5 * time.Minute, "5m"},
+		{50 * time.Hour, "2d"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
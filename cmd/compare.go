@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// compareModel is set to write a model of the two skylines being compared.
+var compareModel bool
+
+// compareCmd sets two users, or two years of one user, side by side.
+var compareCmd = &cobra.Command{
+	Use:   "compare <user> <user> | <year> <year>",
+	Short: "Compare the contributions of two users, or two years of one user",
+	Long: `Compare two contribution histories: two users in the year given with --year,
+or two years of the user given with --user (or the authenticated user).
+
+The ASCII previews of the two are printed side by side, followed by their
+statistics and how much the second differs from the first. With --model, a
+model of the two skylines in rows, the first behind the second, is written too.`,
+	Example: `  gh skyline compare mona hubot --year 2024
+  gh skyline compare 2023 2024 --user mona --model`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeCompareArgs,
+	RunE:              handleCompareCommand,
+}
+
+// init registers the compare command and its flags.
+func init() {
+	flags := compareCmd.Flags()
+	addUserFlags(flags)
+	flags.BoolVar(&compareModel, "model", false, "Also write a model of the two skylines in rows, for printing the comparison")
+	flags.StringVarP(&output, "output", "o", "", "Output file path of the model (optional)")
+	flags.StringVar(&format, "format", string(stl.FormatSTL), fmt.Sprintf("Output file format of the model (%s)", strings.Join(stl.Formats(), ", ")))
+	addThemeFlag(flags)
+	rootCmd.AddCommand(compareCmd)
+}
+
+// handleCompareCommand is the main execution function for the compare command.
+func handleCompareCommand(cmd *cobra.Command, args []string) error {
+	sides, err := commandSides(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	src, err := loadSource()
+	if err != nil {
+		return err
+	}
+
+	outputFormat, err := stl.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+
+	palette, err := themePalette()
+	if err != nil {
+		return err
+	}
+	style := previewStyle(cmd.OutOrStdout())
+	style.Palette = palette
+	renderOpts := render.DefaultOptions()
+	renderOpts.Palette = palette
+
+	contributionCache, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	return skyline.Compare(progressContext(cmd, os.Stdout), skyline.Options{
+		TimeZone:       src.zone,
+		ArtOnly:        !compareModel,
+		Output:         output,
+		Format:         outputFormat,
+		Metadata:       generationMetadata(cmd.Flags()),
+		Cache:          contributionCache,
+		Client:         src.client,
+		Offline:        offline,
+		FilterOrg:      filterOrg,
+		Types:          src.kinds,
+		PublicOnly:     publicOnly,
+		IncludePrivate: includePrivate,
+		Render:         renderOpts,
+		PreviewStyle:   style,
+	}, sides, cmd.OutOrStdout())
+}
+
+// commandSides reads the two sides of the comparison cmd was run for from its
+// arguments and flags. Only flags given on the command line conflict with the
+// arguments, so defaults from the config file do not get in their way.
+func commandSides(cmd *cobra.Command, args []string) ([2]skyline.Side, error) {
+	return compareSides(args, users, yearRange, givenOnCommandLine(cmd.Flags(), "user"), givenOnCommandLine(cmd.Flags(), "year"))
+}
+
+// compareSides reads the two sides of a comparison from its arguments: two
+// years of the user given with --user, or two users in the year given with
+// --year. userSet and yearSet tell whether --user and --year were given on the
+// command line, rather than being defaults.
+func compareSides(args, usernames []string, years string, userSet, yearSet bool) ([2]skyline.Side, error) {
+	var sides [2]skyline.Side
+	first, second := isYear(args[0]), isYear(args[1])
+	switch {
+	case first && second:
+		if yearSet {
+			return sides, errors.New(errors.ValidationError, "--year cannot be combined with comparing two years", nil)
+		}
+		if len(usernames) > 1 {
+			return sides, errors.New(errors.ValidationError, "two years can only be compared for a single --user", nil)
+		}
+		user, _ := splitUsers(usernames)
+		for i, arg := range args {
+			year, _, err := utils.ParseYearRange(arg)
+			if err != nil {
+				return sides, fmt.Errorf("invalid year: %v", err)
+			}
+			sides[i] = skyline.Side{User: user, Year: year}
+		}
+	case first || second:
+		return sides, errors.New(errors.ValidationError, "compare two users or two years, not a user and a year", nil)
+	default:
+		if userSet {
+			return sides, errors.New(errors.ValidationError, "--user cannot be combined with comparing two users", nil)
+		}
+		if utils.IsMonthRange(years) {
+			return sides, errors.New(errors.ValidationError, "two users can only be compared over a whole year", nil)
+		}
+		startYear, endYear, err := utils.ParseYearRange(years)
+		if err != nil {
+			return sides, fmt.Errorf("invalid year range: %v", err)
+		}
+		if startYear != endYear {
+			return sides, errors.New(errors.ValidationError, "two users can only be compared in a single year", nil)
+		}
+		for i, arg := range args {
+			sides[i] = skyline.Side{User: strings.TrimSpace(arg), Year: startYear}
+		}
+	}
+	return sides, nil
+}
+
+// isYear reports whether an argument of the compare command is a year rather
+// than a login.
+func isYear(arg string) bool {
+	_, err := strconv.Atoi(arg)
+	return len(arg) == 4 && err == nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/spf13/cobra"
+)
+
+func TestCompareCmd(t *testing.T) {
+	if cmd, _, err := rootCmd.Find([]string{"compare"}); err != nil || cmd != compareCmd {
+		t.Fatalf("expected compare to be a subcommand of the root command, got %v, %v", cmd, err)
+	}
+
+	flags := compareCmd.Flags()
+	for _, flag := range []string{"year", "user", "filter-org", "types", "timezone", "token", "no-cache", "offline", "debug", "model", "output", "format"} {
+		if flags.Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+	for _, flag := range []string{"org", "merge-users", "full", "input"} {
+		if flags.Lookup(flag) != nil {
+			t.Errorf("expected flag %s not to be initialized", flag)
+		}
+	}
+}
+
+func TestCompareSides(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		usernames []string
+		years     string
+		userSet   bool
+		yearSet   bool
+		want      [2]skyline.Side
+		wantErr   bool
+	}{
+		{"users", []string{"mona", "hubot"}, nil, "2024", false, true, [2]skyline.Side{{User: "mona", Year: 2024}, {User: "hubot", Year: 2024}}, false},
+		{"years of a user", []string{"2023", "2024"}, []string{"mona"}, "2024", true, false, [2]skyline.Side{{User: "mona", Year: 2023}, {User: "mona", Year: 2024}}, false},
+		{"years of the authenticated user", []string{"2024", "2020"}, nil, "2024", false, false, [2]skyline.Side{{Year: 2024}, {Year: 2020}}, false},
+		{"user and year", []string{"mona", "2024"}, nil, "2024", false, false, [2]skyline.Side{}, true},
+		{"years with --year", []string{"2023", "2024"}, nil, "2024", false, true, [2]skyline.Side{}, true},
+		{"years of a team", []string{"2023", "2024"}, []string{"mona", "hubot"}, "2024", true, false, [2]skyline.Side{}, true},
+		{"year before GitHub", []string{"2001", "2024"}, nil, "2024", false, false, [2]skyline.Side{}, true},
+		{"users with --user", []string{"mona", "hubot"}, []string{"octocat"}, "2024", true, false, [2]skyline.Side{}, true},
+		{"users with a default user", []string{"mona", "hubot"}, []string{"octocat"}, "2024", false, false, [2]skyline.Side{{User: "mona", Year: 2024}, {User: "hubot", Year: 2024}}, false},
+		{"users over a range", []string{"mona", "hubot"}, nil, "2023-2024", false, true, [2]skyline.Side{}, true},
+		{"users over months", []string{"mona", "hubot"}, nil, "2024-01:2024-06", false, true, [2]skyline.Side{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compareSides(tt.args, tt.usernames, tt.years, tt.userSet, tt.yearSet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compareSides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("compareSides() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandSidesConfig(t *testing.T) {
+	defer func(wasUsers []string, wasYear string) { users, yearRange = wasUsers, wasYear }(users, yearRange)
+	writeConfig(t, "year: 2022\nuser: octocat\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    [2]skyline.Side
+		wantErr bool
+	}{
+		{"years of the configured user", []string{"2022", "2023"}, [2]skyline.Side{{User: "octocat", Year: 2022}, {User: "octocat", Year: 2023}}, false},
+		{"users in the configured year", []string{"mona", "hubot"}, [2]skyline.Side{{User: "mona", Year: 2022}, {User: "hubot", Year: 2022}}, false},
+		{"years with --year", []string{"2022", "2023", "--year", "2024"}, [2]skyline.Side{}, true},
+		{"users with --user", []string{"mona", "hubot", "--user", "octocat"}, [2]skyline.Side{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "compare"}
+			addUserFlags(cmd.Flags())
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := applyConfig(cmd, nil); err != nil {
+				t.Fatalf("applyConfig() error = %v", err)
+			}
+
+			got, err := commandSides(cmd, cmd.Flags().Args())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("commandSides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("commandSides() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// userFlags are the flags naming users, completed with the cached usernames.
+var userFlags = []string{"user", "merge-users"}
+
+// registerCompletions completes the users and years of cmd's flags, and of
+// its subcommands', with cached usernames and the years there are
+// contributions of. Cobra's completion command generates the scripts asking
+// for them.
+func registerCompletions(cmd *cobra.Command) {
+	for _, name := range userFlags {
+		if cmd.Flags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, completeCachedUsers)
+		}
+	}
+	if cmd.Flags().Lookup("year") != nil {
+		_ = cmd.RegisterFlagCompletionFunc("year", completeYears)
+	}
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
+
+// completeCachedUsers completes the last of a comma separated list of
+// usernames with the users whose contributions are cached.
+func completeCachedUsers(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	var completions []string
+	for _, username := range cachedUsernames() {
+		if strings.HasPrefix(username, strings.ToLower(toComplete)) {
+			completions = append(completions, prefix+username)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// cachedUsernames returns the users whose contributions are cached, sorted,
+// or none when the cache cannot be read.
+func cachedUsernames() []string {
+	dir, err := cache.Dir()
+	if err != nil {
+		return nil
+	}
+	store, err := cache.New(dir, cache.DefaultTTL)
+	if err != nil {
+		return nil
+	}
+	items, err := store.List("", 0)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, item := range items {
+		if !seen[item.Username] {
+			seen[item.Username] = true
+			usernames = append(usernames, item.Username)
+		}
+	}
+	sort.Strings(usernames)
+	return usernames
+}
+
+// completeYears completes a year, latest first, or the end of a range of
+// years once its first year and a hyphen are typed.
+func completeYears(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	first, prefix := utils.GitHubLaunchYear, ""
+	if start, _, found := strings.Cut(toComplete, "-"); found {
+		year, err := strconv.Atoi(start)
+		if err != nil || year < utils.GitHubLaunchYear {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		first, prefix = year, start+"-"
+	}
+
+	var completions []string
+	for year := time.Now().Year(); year >= first; year-- {
+		completions = append(completions, prefix+strconv.Itoa(year))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeCompareArgs completes the arguments of the compare command with
+// cached usernames or years, matching the first argument once it is given.
+func completeCompareArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) >= 2:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	case len(args) == 1 && isYear(args[0]):
+		return completeYears(cmd, nil, toComplete)
+	case len(args) == 1:
+		return completeCachedUsers(cmd, nil, toComplete)
+	}
+	users, _ := completeCachedUsers(cmd, nil, toComplete)
+	years, _ := completeYears(cmd, nil, toComplete)
+	return append(users, years...), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
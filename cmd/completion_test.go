@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+)
+
+// cacheUsers caches a year of contributions of each of usernames in a
+// temporary cache directory.
+func cacheUsers(t *testing.T, usernames ...string) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir, err := cache.Dir()
+	if err != nil {
+		t.Fatalf("cache.Dir() error = %v", err)
+	}
+	store, err := cache.New(dir, cache.DefaultTTL)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+	for _, username := range usernames {
+		if err := store.Put("github.com", username, 2024, fixtures.GenerateContributionsResponse(username, 2024)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+}
+
+func TestCompleteCachedUsers(t *testing.T) {
+	cacheUsers(t, "mona", "hubot", "Monalisa")
+
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", []string{"hubot", "mona", "monalisa"}},
+		{"Mo", []string{"mona", "monalisa"}},
+		{"mona,h", []string{"mona,hubot"}},
+		{"octo", nil},
+	}
+	for _, tt := range tests {
+		got, _ := completeCachedUsers(nil, nil, tt.toComplete)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completeCachedUsers(%q) = %v, want %v", tt.toComplete, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteYears(t *testing.T) {
+	current := time.Now().Year()
+	tests := []struct {
+		toComplete string
+		wantFirst  string
+		wantLast   string
+		wantLen    int
+	}{
+		{"", fmt.Sprint(current), "2008", current - 2007},
+		{"2020-", fmt.Sprintf("2020-%d", current), "2020-2020", current - 2019},
+		{"1999-", "", "", 0},
+		{"abc-", "", "", 0},
+	}
+	for _, tt := range tests {
+		got, _ := completeYears(nil, nil, tt.toComplete)
+		if len(got) != tt.wantLen {
+			t.Fatalf("completeYears(%q) returned %d years, want %d", tt.toComplete, len(got), tt.wantLen)
+		}
+		if tt.wantLen > 0 && (got[0] != tt.wantFirst || got[len(got)-1] != tt.wantLast) {
+			t.Errorf("completeYears(%q) = %v, want %s to %s", tt.toComplete, got, tt.wantFirst, tt.wantLast)
+		}
+	}
+}
+
+func TestCompleteCompareArgs(t *testing.T) {
+	cacheUsers(t, "mona")
+
+	if got, _ := completeCompareArgs(compareCmd, nil, ""); len(got) < 2 || got[0] != "mona" || got[1] != fmt.Sprint(time.Now().Year()) {
+		t.Errorf("completeCompareArgs() of the first argument = %v, want users then years", got)
+	}
+	if got, _ := completeCompareArgs(compareCmd, []string{"2023"}, ""); len(got) == 0 || got[0] != fmt.Sprint(time.Now().Year()) {
+		t.Errorf("completeCompareArgs() after a year = %v, want years", got)
+	}
+	if got, _ := completeCompareArgs(compareCmd, []string{"hubot"}, ""); !reflect.DeepEqual(got, []string{"mona"}) {
+		t.Errorf("completeCompareArgs() after a user = %v, want users", got)
+	}
+	if got, _ := completeCompareArgs(compareCmd, []string{"mona", "hubot"}, ""); got != nil {
+		t.Errorf("completeCompareArgs() after both arguments = %v, want nothing", got)
+	}
+}
+
+func TestCompletionCommands(t *testing.T) {
+	cacheUsers(t, "mona")
+	registerCompletions(rootCmd)
+	defer rootCmd.SetArgs(nil)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"completion", "bash"}, "__start_skyline"},
+		{[]string{"__complete", "--user", ""}, "mona"},
+		{[]string{"__complete", "stats", "--year", "2024-"}, "2024-2024"},
+		{[]string{"__complete", "cache", "clear", "--user", "m"}, "mona"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(tt.args)
+		err := rootCmd.Execute()
+		rootCmd.SetOut(nil)
+		if err != nil {
+			t.Fatalf("skyline %s error = %v", strings.Join(tt.args, " "), err)
+		}
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("skyline %s printed %q, want it to contain %q", strings.Join(tt.args, " "), buf.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/config"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Settings of the config file that are not flags.
+const (
+	outputDirSetting = "output-dir" // Directory models are written to when --output is not given
+	hostnameSetting  = "hostname"   // GitHub host used when GH_HOST is not set
+)
+
+// configAnnotation marks the flags applyConfig gave a value, telling them
+// apart from those given on the command line, as pflag reports both as
+// changed.
+const configAnnotation = "gh-skyline-config"
+
+// noConfigAnnotation marks the commands, with their subcommands, that run
+// without the config file, so they keep working when it is broken: those
+// printing the version, managing the cache and diagnosing problems, which
+// reports a broken file itself.
+const noConfigAnnotation = "gh-skyline-no-config"
+
+// usesConfig reports whether applyConfig is run for cmd: for every command
+// but those marked noConfigAnnotation and cobra's help and completion
+// commands.
+func usesConfig(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[noConfigAnnotation]; ok || (c.Name() == "completion" && c.Parent() == c.Root()) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyConfig gives the flags of cmd not set on the command line their value
+// from the config file, or from the GH_SKYLINE_ environment variable named
+// after them, which takes precedence over the file. The flags it sets are
+// marked with configAnnotation.
+func applyConfig(cmd *cobra.Command, _ []string) error {
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+	settings, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := validateSettings(settings, path, cmd.Root()); err != nil {
+		return err
+	}
+
+	var applyErr error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		// Hidden flags are those the command rejects, and the token is secret
+		if applyErr != nil || f.Changed || f.Hidden || f.Name == "token" {
+			return
+		}
+		value, ok := settings.Lookup(f.Name)
+		if !ok {
+			return
+		}
+		if err := setFromConfig(cmd.Flags(), f.Name, value); err != nil {
+			applyErr = errors.New(errors.ValidationError, fmt.Sprintf("invalid %s setting %q", f.Name, value), err)
+		}
+	})
+	if applyErr != nil {
+		return applyErr
+	}
+
+	if dir, ok := settings.Lookup(outputDirSetting); ok && dir != "" {
+		if f := cmd.Flags().Lookup("output"); f != nil && !f.Changed && !f.Hidden {
+			dir, err := expandHome(dir)
+			if err != nil {
+				return err
+			}
+			if err := setFromConfig(cmd.Flags(), "output", utils.OutputInDir(dir)); err != nil {
+				return err
+			}
+		}
+	}
+	if host, ok := settings.Lookup(hostnameSetting); ok && host != "" && os.Getenv("GH_HOST") == "" {
+		if err := os.Setenv("GH_HOST", host); err != nil {
+			return errors.New(errors.ValidationError, "failed to set the GitHub host", err)
+		}
+	}
+	return nil
+}
+
+// setFromConfig sets the flag named name to value, marking it as set by the
+// config file.
+func setFromConfig(flags *pflag.FlagSet, name, value string) error {
+	if err := flags.Set(name, value); err != nil {
+		return err
+	}
+	return flags.SetAnnotation(name, configAnnotation, []string{"true"})
+}
+
+// givenOnCommandLine reports whether the flag named name was given on the
+// command line, rather than left to its default or set by the config file.
+func givenOnCommandLine(flags *pflag.FlagSet, name string) bool {
+	f := flags.Lookup(name)
+	if f == nil || !f.Changed {
+		return false
+	}
+	_, fromConfig := f.Annotations[configAnnotation]
+	return !fromConfig
+}
+
+// expandHome replaces a leading ~ in path with the home directory, as a shell
+// would have, since config files are not read by one.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New(errors.IOError, "failed to find the home directory", err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// validateSettings reports the keys of the config file at path that no
+// command under root has a flag for, which would otherwise be ignored
+// silently, and a token kept in it.
+func validateSettings(settings config.Settings, path string, root *cobra.Command) error {
+	known := map[string]bool{outputDirSetting: true, hostnameSetting: true}
+	collectFlagNames(root, known)
+
+	var unknown []string
+	for _, key := range settings.Keys() {
+		if key == "token" {
+			return errors.New(errors.ValidationError, fmt.Sprintf("config file %s must not hold a token: sign in with gh auth login or set %s", path, github.TokenEnv), nil)
+		}
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return errors.New(errors.ValidationError, fmt.Sprintf("unknown settings in config file %s: %s", path, strings.Join(unknown, ", ")), nil)
+	}
+	return nil
+}
+
+// collectFlagNames adds the names of the flags of cmd and its subcommands to
+// names.
+func collectFlagNames(cmd *cobra.Command, names map[string]bool) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		names[f.Name] = true
+	})
+	for _, sub := range cmd.Commands() {
+		collectFlagNames(sub, names)
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// configCommand returns a command with some of the flags settings apply to,
+// parsed from args, and its flags' values.
+func configCommand(t *testing.T, args ...string) (*cobra.Command, *float64, *string, *[]string) {
+	t.Helper()
+	var width float64
+	var path string
+	var kinds []string
+	cmd := &cobra.Command{Use: "skyline"}
+	flags := cmd.Flags()
+	flags.Float64Var(&width, "base-width", 150, "")
+	flags.StringVarP(&path, "output", "o", "", "")
+	flags.StringSliceVar(&kinds, "types", nil, "")
+	flags.String("token", "", "")
+	flags.String("format", "stl", "")
+	if err := flags.MarkHidden("format"); err != nil {
+		t.Fatal(err)
+	}
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd, &width, &path, &kinds
+}
+
+// writeConfig writes a config file holding content and points the CLI at it.
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.FileEnv, path)
+}
+
+func TestApplyConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		env       string
+		args      []string
+		wantWidth float64
+		wantTypes string
+	}{
+		{"defaults", "", "", nil, 150, ""},
+		{"file", "base-width: 120\ntypes: [commits, pulls]\n", "", nil, 120, "commits,pulls"},
+		{"environment over file", "base-width: 120\n", "130", nil, 130, ""},
+		{"flag over environment", "base-width: 120\n", "130", []string{"--base-width", "140"}, 140, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfig(t, tt.content)
+			t.Setenv(config.EnvName("base-width"), tt.env)
+			if tt.env == "" {
+				if err := os.Unsetenv(config.EnvName("base-width")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cmd, width, _, kinds := configCommand(t, tt.args...)
+			if err := applyConfig(cmd, nil); err != nil {
+				t.Fatalf("applyConfig() error = %v", err)
+			}
+			if *width != tt.wantWidth || strings.Join(*kinds, ",") != tt.wantTypes {
+				t.Errorf("applyConfig() set base-width %v and types %v, want %v and %s", *width, *kinds, tt.wantWidth, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestApplyConfigSettings(t *testing.T) {
+	writeConfig(t, "output-dir: models\nhostname: github.example.com\nformat: 3mf\n")
+	t.Setenv("GH_HOST", "")
+	cmd, _, path, _ := configCommand(t)
+	if err := applyConfig(cmd, nil); err != nil {
+		t.Fatalf("applyConfig() error = %v", err)
+	}
+	if want := filepath.Join("models", "{user}-{year}-github-skyline"); *path != want {
+		t.Errorf("applyConfig() set output %q, want %q", *path, want)
+	}
+	if got := os.Getenv("GH_HOST"); got != "github.example.com" {
+		t.Errorf("applyConfig() set GH_HOST %q, want github.example.com", got)
+	}
+	if got, _ := cmd.Flags().GetString("format"); got != "stl" {
+		t.Errorf("applyConfig() set the hidden format flag to %q", got)
+	}
+
+	cmd, _, path, _ = configCommand(t, "--output", "mine.stl")
+	t.Setenv("GH_HOST", "github.com")
+	if err := applyConfig(cmd, nil); err != nil {
+		t.Fatalf("applyConfig() error = %v", err)
+	}
+	if *path != "mine.stl" || os.Getenv("GH_HOST") != "github.com" {
+		t.Errorf("applyConfig() overrode --output %q or GH_HOST %q", *path, os.Getenv("GH_HOST"))
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	t.Setenv("HOME", "/home/mona")
+	tests := map[string]string{
+		"~/models": "/home/mona/models",
+		"~":        "/home/mona",
+		"models":   "models",
+		"/tmp/~/x": "/tmp/~/x",
+		"~hubot/x": "~hubot/x",
+	}
+	for path, want := range tests {
+		if got, err := expandHome(path); err != nil || got != want {
+			t.Errorf("expandHome(%q) = %q, %v, want %q", path, got, err, want)
+		}
+	}
+}
+
+func TestApplyConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "base-widht: 120\n", "base-widht"},
+		{"token", "token: secret\n", "must not hold a token"},
+		{"invalid value", "base-width: wide\n", "invalid base-width setting"},
+		{"invalid file", "- base-width\n", "not a YAML mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfig(t, tt.content)
+			cmd, _, _, _ := configCommand(t)
+			rootCmd.AddCommand(cmd)
+			defer rootCmd.RemoveCommand(cmd)
+			err := applyConfig(cmd, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("applyConfig() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGivenOnCommandLine(t *testing.T) {
+	writeConfig(t, "base-width: 120\noutput-dir: models\n")
+	cmd, _, _, _ := configCommand(t, "--types", "commits")
+	if err := applyConfig(cmd, nil); err != nil {
+		t.Fatalf("applyConfig() error = %v", err)
+	}
+
+	for name, want := range map[string]bool{
+		"types":      true,  // Given on the command line
+		"base-width": false, // Set by the config file
+		"output":     false, // Set by the config file's output directory
+		"token":      false, // Left to its default
+		"missing":    false, // No such flag
+	} {
+		if got := givenOnCommandLine(cmd.Flags(), name); got != want {
+			t.Errorf("givenOnCommandLine(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/github/gh-skyline/internal/config"
+	"github.com/github/gh-skyline/internal/doctor"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// newRESTClient creates the client the GitHub API is checked with, replaced in
+// tests.
+var newRESTClient = func(host, token string) (doctor.Requester, error) {
+	return api.NewRESTClient(api.ClientOptions{Host: host, AuthToken: token})
+}
+
+// tokenSources names where the gh CLI found a token, by the source it reports
+// for it; the other sources are environment variables.
+var tokenSources = map[string]string{
+	"oauth_token": "the gh CLI's configuration",
+	"gh":          "the gh CLI's keyring",
+}
+
+// doctorCmd checks what generating a skyline depends on.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the GitHub credentials, API and output directory models depend on",
+	Long: `Check what generating a skyline depends on, printing how to fix each
+problem found: the config file's settings, a GitHub token for the host, as gh auth status would, the
+token's scopes, whether the GitHub API (including a GitHub Enterprise Server
+named by GH_HOST) can be reached, the embedded fonts and whether the output
+directory is writable.
+
+The command fails when any check does, so it can gate scripts.`,
+	Args: cobra.NoArgs,
+	RunE: handleDoctorCommand,
+	// A broken config file is reported by checkConfig rather than failing
+	Annotations: map[string]string{noConfigAnnotation: ""},
+}
+
+// init registers the doctor command and its flags.
+func init() {
+	flags := doctorCmd.Flags()
+	flags.StringVar(&token, "token", "", fmt.Sprintf("GitHub auth token to check in place of the gh CLI's credentials (or set %s)", github.TokenEnv))
+	flags.StringVarP(&output, "output", "o", "", "Output file path whose directory is checked (defaults to the current directory)")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// handleDoctorCommand is the main execution function for the doctor command.
+func handleDoctorCommand(cmd *cobra.Command, _ []string) error {
+	// The config file may name the host and output directory checked
+	results := []doctor.Result{checkConfig(cmd)}
+
+	host, _ := auth.DefaultHost()
+	authToken, source := resolveToken(token), "--token"
+	if token == "" {
+		source = github.TokenEnv
+	}
+	if authToken == "" {
+		authToken, source = auth.TokenForHost(host)
+		if name, ok := tokenSources[source]; ok {
+			source = name
+		}
+	}
+
+	results = append(results, doctor.CheckAuth(host, authToken, source))
+	if authToken != "" {
+		client, err := newRESTClient(host, authToken)
+		if err != nil {
+			results = append(results, doctor.Result{Name: "GitHub API", Status: doctor.StatusFailed, Detail: err.Error(), Fix: "Check the gh CLI's configuration with `gh auth status`"})
+		} else {
+			results = append(results, doctor.CheckAPI(cmd.Context(), client, host)...)
+		}
+	}
+
+	dir := "."
+	if output != "" {
+		dir = filepath.Dir(output)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	results = append(results, doctor.CheckFonts(), doctor.CheckOutputDir(dir))
+
+	// The report says what failed, so the usage is left out
+	cmd.SilenceUsage = true
+	return doctor.Write(cmd.OutOrStdout(), results)
+}
+
+// checkConfig reports whether the config file holds only valid settings,
+// applying them to cmd when it does, as every command generating models fails
+// on a broken file.
+func checkConfig(cmd *cobra.Command) doctor.Result {
+	result := doctor.Result{Name: "Config file"}
+	path, err := config.Path()
+	if err == nil {
+		err = applyConfig(cmd, nil)
+	}
+	if err != nil {
+		result.Status = doctor.StatusFailed
+		result.Detail = err.Error()
+		result.Fix = fmt.Sprintf("Correct or remove the settings named in the config file, or point %s at another one", config.FileEnv)
+		return result
+	}
+	result.Status = doctor.StatusOK
+	if _, err := os.Stat(path); err != nil {
+		result.Detail = fmt.Sprintf("no settings in %s", path)
+	} else {
+		result.Detail = fmt.Sprintf("%s is valid", path)
+	}
+	return result
+}
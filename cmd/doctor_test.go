@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/doctor"
+)
+
+// doctorRequester answers GET user for mona, with every recommended scope.
+type doctorRequester struct{}
+
+// RequestWithContext implements doctor.Requester.
+func (doctorRequester) RequestWithContext(context.Context, string, string, io.Reader) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("X-OAuth-Scopes", "repo, read:org")
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(`{"login": "mona"}`))}, nil
+}
+
+// clearGitHubAuth hides the user's own gh CLI host and credentials from a test.
+func clearGitHubAuth(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"GH_HOST", "GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GH_SKYLINE_TOKEN"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GH_PATH", "/nonexistent/gh")
+}
+
+func TestHandleDoctorCommand(t *testing.T) {
+	defer func(flag, path string, create func(string, string) (doctor.Requester, error)) {
+		token, output, newRESTClient = flag, path, create
+	}(token, output, newRESTClient)
+	newRESTClient = func(string, string) (doctor.Requester, error) { return doctorRequester{}, nil }
+
+	tests := []struct {
+		name      string
+		config    string
+		token     string
+		output    string
+		want      []string
+		wantError string
+	}{
+		{
+			name:   "healthy",
+			token:  "gho_abc",
+			output: t.TempDir() + "/skyline.stl",
+			want:   []string{"✓ Config file", "✓ Authentication: token for github.com from --token", "✓ GitHub API: signed in to github.com as mona", "✓ Token scopes", "✓ Fonts", "✓ Output directory"},
+		},
+		{
+			name:      "signed out",
+			output:    t.TempDir() + "/skyline.stl",
+			want:      []string{"✗ Authentication: no token for github.com", "Fix: Run `gh auth login --hostname github.com`"},
+			wantError: "1 of 4 checks failed",
+		},
+		{
+			name:      "missing output directory",
+			token:     "gho_abc",
+			output:    t.TempDir() + "/missing/skyline.stl",
+			want:      []string{"✗ Output directory"},
+			wantError: "1 of 6 checks failed",
+		},
+		{
+			name:      "unknown config setting",
+			config:    "bogus: 1\n",
+			token:     "gho_abc",
+			output:    t.TempDir() + "/skyline.stl",
+			want:      []string{"✗ Config file: ", "bogus", "✓ Authentication"},
+			wantError: "1 of 6 checks failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearGitHubAuth(t)
+			writeConfig(t, tt.config)
+			token, output = tt.token, tt.output
+			var buf bytes.Buffer
+			doctorCmd.SetOut(&buf)
+			defer doctorCmd.SetOut(nil)
+
+			err := handleDoctorCommand(doctorCmd, nil)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("handleDoctorCommand() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+			} else if err != nil {
+				t.Errorf("handleDoctorCommand() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("handleDoctorCommand() report lacks %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/theme"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// plain keeps the output free of terminal control sequences, such as the
+// progress bar, as it is when printed to a file or pipe.
+var plain bool
+
+// githubActionsEnv is set to true by GitHub Actions in the steps of a workflow.
+const githubActionsEnv = "GITHUB_ACTIONS"
+
+// setupCommand prepares every command to run: it applies the config file to
+// those using it and, inside a GitHub Actions workflow, logs warnings and
+// errors as annotations.
+func setupCommand(cmd *cobra.Command, args []string) error {
+	if usesConfig(cmd) {
+		if err := applyConfig(cmd, args); err != nil {
+			return err
+		}
+	}
+	if githubActions() {
+		logger.GetLogger().SetAnnotations(true)
+		// Execute logs the error as an annotation in place of cobra
+		cmd.SilenceErrors = true
+	}
+	return nil
+}
+
+// githubActions reports whether the command runs in a GitHub Actions workflow.
+func githubActions() bool {
+	return os.Getenv(githubActionsEnv) == "true"
+}
+
+// decorated reports whether w, where cmd prints, may show terminal control
+// sequences and colors: when it is a terminal and --plain is not given.
+func decorated(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && !plain && term.IsTerminal(f)
+}
+
+// previewStyle returns how the ASCII preview printed to w is drawn: in the
+// colors previewColors picks, and as wide as the terminal when w is one.
+func previewStyle(w io.Writer) ascii.Style {
+	style := ascii.Style{Color: previewColors(w)}
+	if decorated(w) {
+		style.Width = terminalWidth()
+	}
+	return style
+}
+
+// previewColors returns the colors of the ASCII preview printed to w: the most
+// the terminal advertises, or none when w is not a terminal, --plain is given
+// or NO_COLOR is set. CLICOLOR_FORCE colors previews printed to files too.
+func previewColors(w io.Writer) ascii.ColorMode {
+	if plain || term.IsColorDisabled() || (!decorated(w) && !term.IsColorForced()) {
+		return ascii.NoColor
+	}
+	t := term.FromEnv()
+	switch {
+	case t.IsTrueColorSupported():
+		return ascii.TrueColor
+	case t.Is256ColorSupported():
+		return ascii.Color256
+	default:
+		return ascii.Color16
+	}
+}
+
+// terminalWidth returns the number of columns of the terminal, or 0 when it
+// cannot be told, leaving the preview its full width.
+func terminalWidth() int {
+	width, _, err := term.FromEnv().Size()
+	if err != nil || width <= 0 {
+		return 0
+	}
+	return width
+}
+
+// addThemeFlag sets up the flag choosing the colors of the contribution
+// levels, shared by the commands printing a preview or writing models.
+func addThemeFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&themeName, "theme", "", fmt.Sprintf("Colors of the contribution levels in the preview and formats holding colors: %s, or a palette file", strings.Join(theme.Names(), ", ")))
+}
+
+// themePalette returns the palette chosen with --theme, or a zero palette
+// leaving the preview and each format their own colors when it is not given.
+func themePalette() (theme.Palette, error) {
+	if themeName == "" {
+		return theme.Palette{}, nil
+	}
+	name, err := expandHome(themeName)
+	if err != nil {
+		return theme.Palette{}, err
+	}
+	return theme.Parse(name)
+}
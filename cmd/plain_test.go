@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+func TestDecorated(t *testing.T) {
+	defer func(was bool) { plain = was }(plain)
+	plain = false
+
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		name string
+		w    io.Writer
+	}{
+		{"buffer", &bytes.Buffer{}},
+		{"file that is not a terminal", file},
+	}
+	for _, tt := range tests {
+		if decorated(tt.w) {
+			t.Errorf("decorated() of a %s = true, want false", tt.name)
+		}
+	}
+}
+
+func TestSetupCommand(t *testing.T) {
+	defer logger.GetLogger().SetAnnotations(false)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+
+	tests := []struct {
+		name          string
+		actions       string
+		wantSilenced  bool
+		wantAnnotated bool
+	}{
+		{"terminal", "", false, false},
+		{"github actions", "true", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfig(t, "")
+			t.Setenv(githubActionsEnv, tt.actions)
+			cmd, _, _, _ := configCommand(t)
+
+			if err := setupCommand(cmd, nil); err != nil {
+				t.Fatalf("setupCommand() error = %v", err)
+			}
+			var buf bytes.Buffer
+			logger.GetLogger().SetOutput(&buf)
+			if err := logger.GetLogger().Warning("%s", "capped"); err != nil {
+				t.Fatal(err)
+			}
+			if annotated := strings.HasPrefix(buf.String(), "::warning::capped"); annotated != tt.wantAnnotated {
+				t.Errorf("setupCommand() logged the warning %q, want annotated %v", buf.String(), tt.wantAnnotated)
+			}
+			if cmd.SilenceErrors != tt.wantSilenced {
+				t.Errorf("setupCommand() SilenceErrors = %v, want %v", cmd.SilenceErrors, tt.wantSilenced)
+			}
+		})
+	}
+}
+
+func TestSetupCommandBrokenConfig(t *testing.T) {
+	writeConfig(t, "bogus: 1\n")
+	completion := &cobra.Command{Use: "completion"}
+	bash := &cobra.Command{Use: "bash"}
+	completion.AddCommand(bash)
+	rootCmd.AddCommand(completion)
+	defer rootCmd.RemoveCommand(completion)
+
+	for _, cmd := range []*cobra.Command{versionCmd, doctorCmd, cacheListCmd, bash, {Use: cobra.ShellCompRequestCmd}} {
+		if err := setupCommand(cmd, nil); err != nil {
+			t.Errorf("setupCommand(%s) error = %v, want the config file skipped", cmd.Name(), err)
+		}
+	}
+	if err := setupCommand(rootCmd, nil); err == nil || !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("setupCommand(%s) error = %v, want the unknown setting reported", rootCmd.Name(), err)
+	}
+}
+
+func TestPreviewStyle(t *testing.T) {
+	defer func(was bool) { plain = was }(plain)
+
+	tests := []struct {
+		name      string
+		plain     bool
+		noColor   string
+		force     string
+		colorTerm string
+		term      string
+		want      ascii.ColorMode
+	}{
+		{"not a terminal", false, "", "", "truecolor", "xterm", ascii.NoColor},
+		{"forced true color", false, "", "1", "truecolor", "xterm", ascii.TrueColor},
+		{"forced 256 colors", false, "", "1", "", "xterm-256color", ascii.Color256},
+		{"forced 16 colors", false, "", "1", "", "xterm", ascii.Color16},
+		{"NO_COLOR", false, "1", "1", "truecolor", "xterm", ascii.NoColor},
+		{"plain", true, "", "1", "truecolor", "xterm", ascii.NoColor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain = tt.plain
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("CLICOLOR", "")
+			t.Setenv("CLICOLOR_FORCE", tt.force)
+			t.Setenv("COLORTERM", tt.colorTerm)
+			t.Setenv("TERM", tt.term)
+			t.Setenv("GH_FORCE_TTY", "")
+
+			if got := previewStyle(&bytes.Buffer{}); got.Color != tt.want {
+				t.Errorf("previewStyle() = %v, want colors %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThemePalette(t *testing.T) {
+	defer func(was string) { themeName = was }(themeName)
+
+	path := filepath.Join(t.TempDir(), "palette.yml")
+	if err := os.WriteFile(path, []byte("levels: ['#ffee4a', '#ffc501', '#fe9600', '#03001c']\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		theme   string
+		want    theme.Palette
+		wantErr bool
+	}{
+		{"not given", "", theme.Palette{}, false},
+		{"built-in", "winter", theme.Winter, false},
+		{"palette file", path, theme.Halloween, false},
+		{"unknown", "autumn", theme.Palette{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			themeName = tt.theme
+			got, err := themePalette()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("themePalette() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("themePalette() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/browser"
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/viewer"
+	"github.com/spf13/cobra"
+)
+
+// noBrowser leaves the browser closed, only printing the viewer's address.
+var noBrowser bool
+
+// openBrowser opens a URL in the user's browser, replaced in tests.
+var openBrowser = func(url string) error {
+	return browser.New("", os.Stdout, os.Stderr).Browse(url)
+}
+
+// previewFileFlags are the model flags that only affect the written file, so
+// have no effect on a preview.
+var previewFileFlags = []string{"format", "split-parts", "split-years", "profile", "resolution", "background"}
+
+// previewCmd shows a model in the browser in place of writing it.
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Inspect a model in a 3D viewer in the browser before writing it",
+	Long: `Generate a model in memory and show it in a 3D viewer in the browser, served
+from this machine until the command is stopped with Ctrl+C. Drag to turn the
+model around and scroll to zoom.
+
+The preview takes the same flags as generating a model, so the model can be
+tried out before writing it to a file with the same flags.`,
+	Example: `  gh skyline preview --year 2024
+  gh skyline preview --user mona --base-style rounded --stack`,
+	Args: cobra.NoArgs,
+	RunE: handlePreviewCommand,
+}
+
+// init registers the preview command and its flags.
+func init() {
+	flags := previewCmd.Flags()
+	addSourceFlags(flags)
+	flags.BoolVar(&noBrowser, "no-browser", false, "Print the viewer's address without opening the browser")
+	addModelFlags(flags)
+	for _, name := range previewFileFlags {
+		_ = flags.MarkHidden(name)
+	}
+	rootCmd.AddCommand(previewCmd)
+}
+
+// handlePreviewCommand is the main execution function for the preview command.
+func handlePreviewCommand(cmd *cobra.Command, _ []string) error {
+	for _, name := range previewFileFlags {
+		if cmd.Flags().Changed(name) {
+			return errors.New(errors.ValidationError, fmt.Sprintf("--%s only affects written files, so cannot be previewed", name), nil)
+		}
+	}
+
+	src, err := loadSource()
+	if err != nil {
+		return err
+	}
+
+	startYear, endYear, from, to, err := parsePeriod(yearRange)
+	if err != nil {
+		return err
+	}
+
+	contributionCache, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	opts, err := modelOptions(cmd, skyline.Options{
+		StartYear:      startYear,
+		EndYear:        endYear,
+		From:           from,
+		To:             to,
+		TimeZone:       src.zone,
+		User:           src.user,
+		Full:           full,
+		Cache:          contributionCache,
+		Client:         src.client,
+		Offline:        offline,
+		Input:          input,
+		Org:            org,
+		Team:           src.team,
+		MergeUsers:     src.merged,
+		FilterOrg:      filterOrg,
+		Types:          src.kinds,
+		PublicOnly:     publicOnly,
+		IncludePrivate: includePrivate,
+		View:           viewModel,
+		PreviewStyle:   previewStyle(cmd.OutOrStdout()),
+	})
+	if err != nil {
+		return err
+	}
+	return skyline.GenerateSkyline(progressContext(cmd, os.Stdout), opts)
+}
+
+// viewModel serves model to the browser until ctx is done, opening the viewer
+// unless --no-browser is set.
+func viewModel(ctx context.Context, model *types.Model) error {
+	log := logger.GetLogger()
+	return viewer.Serve(ctx, model, func(url string) error {
+		if err := log.Info("Previewing the model at %s, press Ctrl+C to stop", url); err != nil {
+			return err
+		}
+		if noBrowser {
+			return nil
+		}
+		if err := openBrowser(url); err != nil {
+			return log.Warning("Failed to open the browser, open %s instead: %v", url, err)
+		}
+		return nil
+	})
+}
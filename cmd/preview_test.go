@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/dataset"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestPreviewCmd(t *testing.T) {
+	if cmd, _, err := rootCmd.Find([]string{"preview"}); err != nil || cmd != previewCmd {
+		t.Fatalf("expected preview to be a subcommand of the root command, got %v, %v", cmd, err)
+	}
+
+	flags := previewCmd.Flags()
+	for _, flag := range []string{"year", "user", "org", "input", "offline", "no-browser", "base-style", "stack", "no-logo"} {
+		if flags.Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+	for _, flag := range []string{"output", "art-only", "web", "export-data"} {
+		if flags.Lookup(flag) != nil {
+			t.Errorf("expected flag %s not to be initialized", flag)
+		}
+	}
+	for _, flag := range previewFileFlags {
+		if f := flags.Lookup(flag); f == nil || !f.Hidden {
+			t.Errorf("expected file flag %s to be hidden", flag)
+		}
+	}
+}
+
+func TestHandlePreviewCommand(t *testing.T) {
+	defer func(path, years string, disabled, closed bool, open func(string) error) {
+		input, yearRange, noCache, noBrowser, openBrowser = path, years, disabled, closed, open
+	}(input, yearRange, noCache, noBrowser, openBrowser)
+
+	path := filepath.Join(t.TempDir(), "mona.json")
+	days := []types.ContributionDay{
+		{Date: "2024-01-01", ContributionCount: 2},
+		{Date: "2024-01-02", ContributionCount: 3},
+	}
+	if err := dataset.Write(path, "mona", [][][]types.ContributionDay{{days}}); err != nil {
+		t.Fatalf("dataset.Write() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var page string
+	input, yearRange, noCache, noBrowser = path, "2024", true, false
+	openBrowser = func(url string) error {
+		defer cancel()
+		resp, err := http.Get(url)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		page = string(body)
+		return err
+	}
+
+	previewCmd.SetContext(ctx)
+	defer previewCmd.SetContext(context.Background())
+	if err := handlePreviewCommand(previewCmd, nil); err != nil {
+		t.Fatalf("handlePreviewCommand() error = %v", err)
+	}
+	if !strings.Contains(page, "<canvas") {
+		t.Errorf("handlePreviewCommand() did not serve the viewer, got:\n%s", page)
+	}
+}
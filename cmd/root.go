@@ -4,28 +4,115 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"strings"
 	"time"
 
-	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/browser"
 	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/buildinfo"
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/dataset"
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/launch"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/profile"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/transform"
+	"github.com/github/gh-skyline/internal/types"
 	"github.com/github/gh-skyline/internal/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// stdoutOutput is the --output that streams the model to stdout.
+const stdoutOutput = "-"
+
 // Command line variables and root command configuration
 var (
-	yearRange string
-	user      string
-	full      bool
-	debug     bool
-	web       bool
-	artOnly   bool
-	output    string // new output path flag
+	yearRange      string
+	users          []string
+	mergeUsers     []string
+	org            string
+	filterOrg      string
+	contribTypes   []string
+	includePrivate bool
+	publicOnly     bool
+	full           bool
+	skipEmptyYears bool
+	timeZone       string
+	token          string
+	retries        int
+	retryBackoff   time.Duration
+	retryJitter    float64
+	cacheTTL       time.Duration
+	noCache        bool
+	offline        bool
+	input          string
+	debug          bool
+	web            bool
+	artOnly        bool
+	output         string // new output path flag
+	exportData     string
+	openModel      bool
+	outputFormat   string
+	quiet          bool
+	format         string
+	resolution     int
+	background     string
+	themeName      string
+	baseWidth      float64
+	baseDepth      float64
+	baseThickness  float64
+	footprint      float64
+	gap            float64
+	towerShape     string
+	towerSegments  int
+	towerTop       string
+	smoothSurface  bool
+	minHeight      float64
+	maxHeight      float64
+	scale          string
+	normalize      string
+	fit            string
+	unit           string
+	smooth         int
+	capPercentile  float64
+	weekStart      string
+	weekdaysOnly   bool
+	futureDays     string
+	baseStyle      string
+	layoutMode     string
+	cornerRadius   float64
+	chamfer        float64
+	hollow         float64
+	drainHole      float64
+	textStyle      string
+	faceResolution int
+	noText         bool
+	noLogo         bool
+	logoFile       string
+	splitParts     bool
+	splitYears     bool
+	mirror         bool
+	repair         bool
+	decimate       float64
+	profileMode    string
+	qrCode         bool
+	qrURL          string
+	statsOnModel   bool
+	avatar         bool
+	monthLabels    string
+	stack          bool
+	yearLabels     bool
+	yearDividers   bool
+	mold           bool
 )
 
 // rootCmd is the root command for the GitHub Skyline CLI tool.
@@ -48,17 +135,24 @@ ASCII Preview Legend:
 Layout:
 Each column represents one week. Days within each week are reordered vertically
 to create a "building" effect, with empty spaces (no contributions) at the top.`,
-	RunE: handleSkylineCommand,
+	PersistentPreRunE: setupCommand,
+	RunE:              handleSkylineCommand,
 }
 
 // init initializes command line flags for the skyline CLI tool.
 func init() {
 	initFlags()
+	rootCmd.PersistentFlags().BoolVar(&plain, "plain", false, "Plain output for logs and CI: no progress bar or colors, as when printing to a file or pipe")
 }
 
 // Execute initializes and executes the root command for the GitHub Skyline CLI.
-func Execute(_ context.Context) error {
-	if err := rootCmd.Execute(); err != nil {
+// Canceling ctx stops the command's API requests and file writes.
+func Execute(ctx context.Context) error {
+	registerCompletions(rootCmd)
+	if executed, err := rootCmd.ExecuteContextC(ctx); err != nil {
+		if executed.SilenceErrors {
+			_ = logger.GetLogger().Error("%v", err)
+		}
 		return err
 	}
 	return nil
@@ -67,44 +161,630 @@ func Execute(_ context.Context) error {
 // initFlags sets up command line flags for the skyline CLI tool.
 func initFlags() {
 	flags := rootCmd.Flags()
-	flags.StringVarP(&yearRange, "year", "y", fmt.Sprintf("%d", time.Now().Year()), "Year or year range (e.g., 2024 or 2014-2024)")
-	flags.StringVarP(&user, "user", "u", "", "GitHub username (optional, defaults to authenticated user)")
-	flags.BoolVarP(&full, "full", "f", false, "Generate contribution graph from join year to current year")
-	flags.BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
+	addSourceFlags(flags)
 	flags.BoolVarP(&web, "web", "w", false, "Open GitHub profile (authenticated or specified user).")
 	flags.BoolVarP(&artOnly, "art-only", "a", false, "Generate only ASCII preview")
-	flags.StringVarP(&output, "output", "o", "", "Output file path (optional)")
+	flags.StringVarP(&output, "output", "o", "", "Output file path (optional), - to stream the STL model to stdout")
+	flags.StringVar(&exportData, "export-data", "", fmt.Sprintf("Also write the per-day contribution counts to this data file (%s)", strings.Join(dataset.Formats(), ", ")))
+	flags.BoolVar(&openModel, "open", false, "Open the model file written in the default application for its format, such as a slicer or 3D viewer")
+	flags.BoolVarP(&quiet, "quiet", "q", false, "Print only the paths of the files written, without the ASCII preview or informational messages")
+	flags.StringVar(&outputFormat, "output-format", string(skyline.DefaultOutputFormat), fmt.Sprintf("How the outcome is printed: the ASCII preview and messages, or a JSON description of the files written, for scripts (%s)", strings.Join(skyline.OutputFormats(), ", ")))
+	addModelFlags(flags)
+}
+
+// addModelFlags sets up the flags shaping the preview and the model generated
+// from the contributions, shared by the commands generating models.
+func addModelFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&skipEmptyYears, "skip-empty-years", false, "Leave years without contributions out of the preview and model")
+	flags.StringVar(&format, "format", string(stl.FormatSTL), fmt.Sprintf("Output file format (%s)", strings.Join(stl.Formats(), ", ")))
+	flags.StringVar(&unit, "units", string(types.UnitMillimeter), fmt.Sprintf("Unit of dimension flags and the exported model (%s)", strings.Join(stl.Units(), ", ")))
+	flags.Float64Var(&baseWidth, "base-width", 0, "Base width (optional, defaults to fit the contribution grid)")
+	flags.Float64Var(&baseDepth, "base-depth", 0, "Base depth (optional, defaults to fit the contribution grid)")
+	flags.Float64Var(&baseThickness, "base-thickness", geometry.BaseHeight, "Thickness of the base under the towers")
+	flags.Float64Var(&baseThickness, "base-height", geometry.BaseHeight, "Thickness of the base under the towers")
+	_ = flags.MarkDeprecated("base-height", "use --base-thickness instead")
+	flags.StringVar(&baseStyle, "base-style", string(geometry.DefaultBaseStyle), fmt.Sprintf("Shape of the base (%s)", strings.Join(geometry.BaseStyles(), ", ")))
+	flags.BoolVar(&stack, "stack", false, "Raise each earlier year on a tier behind the one in front")
+	flags.BoolVar(&yearLabels, "year-labels", false, "Emboss each year's number beside its towers")
+	flags.BoolVar(&yearDividers, "year-dividers", false, "Add a low ridge between neighboring years")
+	flags.BoolVar(&mold, "mold", false, "Generate a mold for casting the skyline instead of the skyline itself")
+	flags.StringVar(&layoutMode, "layout", string(geometry.DefaultArrangement), fmt.Sprintf("Arrangement of the towers (%s)", strings.Join(geometry.Arrangements(), ", ")))
+	flags.Float64Var(&cornerRadius, "corner-radius", 0, "Radius of the base's vertical corners")
+	flags.Float64Var(&chamfer, "chamfer", 0, "Size of the bevel along the top and bottom edges of the base")
+	flags.Float64Var(&hollow, "hollow", 0, "Hollow out the base leaving walls of this thickness (optional)")
+	flags.Float64Var(&drainHole, "drain-hole", 0, "Diameter of drain holes through the bottom of a hollow base (optional)")
+	flags.Float64Var(&footprint, "footprint", 0, "Width of each day's tower (optional, defaults to fit the base)")
+	flags.Float64Var(&gap, "gap", 0, "Spacing between towers")
+	flags.StringVar(&towerShape, "tower-shape", string(geometry.DefaultTowerShape), fmt.Sprintf("Cross-section of the towers (%s)", strings.Join(geometry.TowerShapes(), ", ")))
+	flags.IntVar(&towerSegments, "tower-segments", geometry.DefaultTowerSegments, "Number of sides of cylinder towers")
+	flags.StringVar(&towerTop, "tower-top", string(geometry.DefaultTowerTop), fmt.Sprintf("Shape of the top of each tower (%s)", strings.Join(geometry.TowerTops(), ", ")))
+	flags.BoolVar(&smoothSurface, "smooth-surface", false, "Build each year as a continuous mountain range surface instead of towers")
+	flags.Float64Var(&minHeight, "min-height", 0, "Minimum tower height for days with contributions (optional)")
+	flags.Float64Var(&maxHeight, "max-height", 0, "Maximum tower height (optional, defaults to scale with the base)")
+	flags.StringVar(&textStyle, "text-style", string(geometry.DefaultTextStyle), fmt.Sprintf("Construction of the username and year (%s)", strings.Join(geometry.TextStyles(), ", ")))
+	flags.IntVar(&faceResolution, "face-resolution", 0, fmt.Sprintf("Voxels across the face for voxel text and statistics (optional, defaults to %d, or %d for svg and png)", geometry.DefaultFaceResolution, geometry.PreviewFaceResolution))
+	flags.BoolVar(&noText, "no-text", false, "Leave the username and year off the model")
+	flags.BoolVar(&noLogo, "no-logo", false, "Leave the GitHub logo off the model")
+	flags.StringVar(&logoFile, "logo", "", "SVG file to emboss in place of the GitHub logo")
+	flags.BoolVar(&qrCode, "qr", false, "Emboss a QR code linking to the GitHub profile on the back of the base")
+	flags.StringVar(&qrURL, "qr-url", "", "URL for the QR code in place of the GitHub profile (implies --qr)")
+	flags.BoolVar(&statsOnModel, "stats-on-model", false, "Emboss total contributions, busiest day and longest streak on the back of the base")
+	flags.BoolVar(&avatar, "avatar", false, "Stand a lithophane of the user's avatar behind the towers")
+	flags.StringVar(&monthLabels, "month-labels", string(geometry.DefaultMonthLabels), fmt.Sprintf("Emboss month labels along the front of the base (%s)", strings.Join(geometry.MonthLabelPlacements(), ", ")))
+	flags.StringVar(&scale, "scale", string(geometry.DefaultScale), fmt.Sprintf("Tower height scaling (%s)", strings.Join(geometry.Scales(), ", ")))
+	flags.StringVar(&normalize, "normalize", string(geometry.DefaultNormalization), fmt.Sprintf("Busiest day a range's towers are scaled against (%s)", strings.Join(geometry.Normalizations(), ", ")))
+	flags.IntVar(&smooth, "smooth", 0, "Average contribution counts over a window of N days for a gentler skyline")
+	flags.Float64Var(&capPercentile, "cap-percentile", 0, "Clamp daily counts above this percentile of the active days, such as 99, so outlying days do not flatten the rest (0 to disable)")
+	flags.StringVar(&weekStart, "week-start", transform.WeekStarts()[0], fmt.Sprintf("First day of each week's column (%s)", strings.Join(transform.WeekStarts(), ", ")))
+	flags.BoolVar(&weekdaysOnly, "weekdays-only", false, "Leave Saturdays and Sundays out of the preview and the model")
+	flags.StringVar(&futureDays, "future-days", string(geometry.DefaultFutureDays), fmt.Sprintf("How the days of the current year still to come are shown (%s)", strings.Join(geometry.FutureDaysModes(), ", ")))
+	flags.BoolVar(&splitParts, "split-parts", false, "Write the base, towers, text and logo to separate files for multi-material printing")
+	flags.BoolVar(&splitYears, "split-years", false, "Write each year of a range to its own file, with matching scale, plus an index")
+	flags.BoolVar(&mirror, "mirror", false, "Mirror the model, text and logo left to right for use as a stamp or mold master")
+	flags.BoolVar(&repair, "repair", false, "Weld nearly coincident vertices, remove degenerate faces and fix inverted faces before writing")
+	flags.Float64Var(&decimate, "decimate", 0, "Simplify the model to this fraction of its triangles (e.g., 0.5), for web and AR viewers")
+	flags.StringVar(&profileMode, "profile", "", fmt.Sprintf("Record a runtime profile of model generation next to the model (%s)", strings.Join(profile.Modes(), ", ")))
+	flags.StringVar(&fit, "fit", "", "Scale the model to fit a print bed of WIDTHxDEPTH (e.g., 220x220)")
+	flags.IntVar(&resolution, "resolution", render.DefaultResolution, "Image width in pixels for the png format")
+	flags.StringVar(&background, "background", "#ffffff", "Background color for the png format (#rrggbb, #rrggbbaa or transparent)")
+	addThemeFlag(flags)
+}
+
+// addSourceFlags sets up the flags choosing the contributions a command reads,
+// shared by the root command and its subcommands.
+func addSourceFlags(flags *pflag.FlagSet) {
+	addUserFlags(flags)
+	flags.StringSliceVar(&mergeUsers, "merge-users", nil, "Accounts of one user, oldest first and comma separated, to sum into one history named after the last")
+	flags.StringVar(&org, "org", "", "GitHub organization to count the commits to the default branches of its repositories of, in place of a user's contributions")
+	flags.BoolVarP(&full, "full", "f", false, "Generate contribution graph from join year to current year")
+	flags.StringVar(&input, "input", "", fmt.Sprintf("Generate from the per-day contribution counts in this data file, without calling the GitHub API (%s)", strings.Join(dataset.Formats(), ", ")))
+}
+
+// addUserFlags sets up the flags choosing a user's contributions and how they
+// are fetched from GitHub, a subset of the source flags for subcommands
+// reading the contributions of a single user.
+func addUserFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVarP(&users, "user", "u", nil, "GitHub username (optional, defaults to authenticated user); several, comma separated or repeated, sum their contributions into one model")
+	addFetchFlags(flags)
+}
+
+// addFetchFlags sets up the flags choosing the period of the contributions
+// read and how they are fetched and counted, whichever users they are of.
+func addFetchFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&yearRange, "year", "y", fmt.Sprintf("%d", time.Now().Year()), "Year, year range or month range (e.g., 2024, 2014-2024 or 2024-01:2024-06)")
+	flags.StringVar(&filterOrg, "filter-org", "", "Count only the user's contributions made in this GitHub organization")
+	flags.StringSliceVar(&contribTypes, "types", nil, fmt.Sprintf("Count only these kinds of contributions, comma separated (%s)", strings.Join(github.ContributionTypes(), ", ")))
+	flags.BoolVar(&includePrivate, "include-private", false, "Count private contributions as the profile does, and report those the token cannot see (default)")
+	flags.BoolVar(&publicOnly, "public-only", false, "Count only contributions to public repositories")
+	flags.StringVar(&timeZone, "timezone", "", "IANA time zone to count contributions on the days of, such as America/Los_Angeles or Local (default UTC, as GitHub does)")
+	flags.StringVar(&token, "token", "", fmt.Sprintf("GitHub auth token to use in place of the gh CLI's credentials (or set %s)", github.TokenEnv))
+	flags.IntVar(&retries, "retries", github.DefaultRetryPolicy().Attempts, "Times a GitHub API request failing with a network or server error is retried")
+	flags.DurationVar(&retryBackoff, "retry-backoff", github.DefaultRetryPolicy().Backoff, "Wait before the first retry, doubled for each one after it")
+	flags.Float64Var(&retryJitter, "retry-jitter", github.DefaultRetryPolicy().Jitter, "Fraction of each wait between retries that is randomized, from 0 to 1")
+	flags.DurationVar(&cacheTTL, "cache-ttl", cache.DefaultTTL, "How long cached contributions of the current year are reused (past years are kept for good)")
+	flags.BoolVar(&noCache, "no-cache", false, "Fetch contributions from GitHub without reading or updating the cache")
+	flags.BoolVar(&offline, "offline", false, "Generate from cached contributions alone, without calling the GitHub API")
+	flags.BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
 }
 
 // executeRootCmd is the main execution function for the root command.
-func handleSkylineCommand(_ *cobra.Command, _ []string) error {
+func handleSkylineCommand(cmd *cobra.Command, _ []string) error {
+	if web && (offline || input != "") {
+		return errors.New(errors.ValidationError, "cannot open the GitHub profile without calling the GitHub API", nil)
+	}
+	src, err := loadSource()
+	if err != nil {
+		return err
+	}
+	user, team, merged, client := src.user, src.team, src.merged, src.client
+
+	if web {
+		b := browser.New("", os.Stdout, os.Stderr)
+		targets := team
+		switch {
+		case org != "":
+			targets = []string{org}
+		case len(merged) > 0:
+			targets = merged[len(merged)-1:]
+		case len(team) == 0:
+			targets = []string{user}
+		}
+		for _, target := range targets {
+			if err := openGitHubProfile(cmd.Context(), target, client, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	startYear, endYear, from, to, err := parsePeriod(yearRange)
+	if err != nil {
+		return err
+	}
+
+	contributionCache, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	if exportData != "" {
+		if _, err := dataset.FormatOf(exportData); err != nil {
+			return err
+		}
+	}
+
+	printed, err := skyline.ParseOutputFormat(outputFormat)
+	if err != nil {
+		return err
+	}
+	if quiet && printed == skyline.OutputJSON {
+		return errors.New(errors.ValidationError, "--quiet cannot be combined with --output-format json, which prints only the result", nil)
+	}
+
+	opts, err := modelOptions(cmd, skyline.Options{
+		StartYear:      startYear,
+		EndYear:        endYear,
+		From:           from,
+		To:             to,
+		TimeZone:       src.zone,
+		User:           user,
+		Full:           full,
+		Output:         output,
+		ArtOnly:        artOnly,
+		Cache:          contributionCache,
+		Client:         client,
+		Offline:        offline,
+		ExportData:     exportData,
+		Input:          input,
+		Org:            org,
+		Team:           team,
+		MergeUsers:     merged,
+		FilterOrg:      filterOrg,
+		Types:          src.kinds,
+		PublicOnly:     publicOnly,
+		IncludePrivate: includePrivate,
+		PreviewStyle:   previewStyle(cmd.OutOrStdout()),
+	})
+	if err != nil {
+		return err
+	}
+	logOut := io.Writer(os.Stdout)
+	if printed == skyline.OutputJSON {
+		// Only the result is printed to stdout, for scripts to parse
+		logOut = cmd.ErrOrStderr()
+		logger.GetLogger().SetOutput(logOut)
+		opts.ResultOut = cmd.OutOrStdout()
+	}
+	if opts.Output == stdoutOutput {
+		if printed == skyline.OutputJSON || quiet {
+			return errors.New(errors.ValidationError, "--output - streams the model to stdout, so cannot be combined with --output-format json or --quiet", nil)
+		}
+		// Only the model is written to stdout, for the program it is piped into
+		logOut = cmd.ErrOrStderr()
+		logger.GetLogger().SetOutput(logOut)
+		opts.Output, opts.ModelOut, opts.PreviewOut = "", cmd.OutOrStdout(), cmd.ErrOrStderr()
+		style := previewStyle(opts.PreviewOut)
+		style.Palette = opts.PreviewStyle.Palette
+		opts.PreviewStyle = style
+	}
+	if openModel {
+		opts.Open = openModelFile
+	}
+	if err := quietOptions(cmd, &opts); err != nil {
+		return err
+	}
+	return skyline.GenerateSkyline(progressContext(cmd, logOut), opts)
+}
+
+// progressContext returns the context of cmd carrying a progress bar drawn on
+// stderr, keeping the log messages written to logOut above it, or the context
+// alone when stderr is not a terminal or --plain, --quiet or --debug is given.
+func progressContext(cmd *cobra.Command, logOut io.Writer) context.Context {
+	stderr := cmd.ErrOrStderr()
+	if !decorated(stderr) || quiet || debug {
+		return cmd.Context()
+	}
+	bar := progress.New(stderr)
+	logger.GetLogger().SetOutput(bar.Writer(logOut))
+	return progress.NewContext(cmd.Context(), bar)
+}
+
+// quietOptions sets opts up to print only the paths of the files written when
+// --quiet is given, logging nothing but warnings and errors, to stderr.
+func quietOptions(cmd *cobra.Command, opts *skyline.Options) error {
+	if !quiet {
+		return nil
+	}
+	if debug {
+		return errors.New(errors.ValidationError, "--quiet cannot be combined with --debug", nil)
+	}
+	log := logger.GetLogger()
+	log.SetLevel(logger.WARNING)
+	log.SetOutput(cmd.ErrOrStderr())
+	opts.PathsOut = cmd.OutOrStdout()
+	return nil
+}
+
+// modelOptions parses the model flags into the settings opts, the contributions
+// to generate from, is completed with.
+func modelOptions(cmd *cobra.Command, opts skyline.Options) (skyline.Options, error) {
+	if smooth < 0 {
+		return skyline.Options{}, errors.New(errors.ValidationError, "smooth window cannot be negative", nil)
+	}
+	if math.IsNaN(capPercentile) || capPercentile < 0 || capPercentile > 100 {
+		return skyline.Options{}, errors.New(errors.ValidationError, "cap percentile must be between 0 and 100", nil)
+	}
+
+	firstDay, err := transform.ParseWeekStart(weekStart)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	outputFormat, err := stl.ParseFormat(format)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	profiling, err := profile.ParseMode(profileMode)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	heightScale, err := geometry.ParseScale(scale)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	normalization, err := geometry.ParseNormalization(normalize)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	future, err := geometry.ParseFutureDays(futureDays)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	style, err := geometry.ParseBaseStyle(baseStyle)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	arrangement, err := geometry.ParseArrangement(layoutMode)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	shape, err := geometry.ParseTowerShape(towerShape)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	top, err := geometry.ParseTowerTop(towerTop)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	months, err := geometry.ParseMonthLabels(monthLabels)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	lettering, err := geometry.ParseTextStyle(textStyle)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+
+	modelUnit, err := stl.ParseUnit(unit)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+	// Dimension flags are given in the selected unit, while their defaults are in millimeters
+	millimeters := func(name string, value float64) float64 {
+		if !cmd.Flags().Changed(name) {
+			return value
+		}
+		return modelUnit.ToMillimeters(value)
+	}
+
+	modelConfig := geometry.Config{
+		BaseWidth:      millimeters("base-width", baseWidth),
+		BaseDepth:      millimeters("base-depth", baseDepth),
+		BaseHeight:     millimeters("base-thickness", baseThickness),
+		BaseStyle:      style,
+		Arrangement:    arrangement,
+		CornerRadius:   millimeters("corner-radius", cornerRadius),
+		Chamfer:        millimeters("chamfer", chamfer),
+		Hollow:         millimeters("hollow", hollow),
+		DrainHole:      millimeters("drain-hole", drainHole),
+		CellSize:       millimeters("footprint", footprint),
+		Gap:            millimeters("gap", gap),
+		TowerShape:     shape,
+		TowerSegments:  towerSegments,
+		TowerTop:       top,
+		MinHeight:      millimeters("min-height", minHeight),
+		MaxHeight:      millimeters("max-height", maxHeight),
+		Scale:          heightScale,
+		Normalize:      normalization,
+		TextStyle:      lettering,
+		FaceResolution: faceResolution,
+		OmitText:       noText,
+		OmitLogo:       noLogo,
+		LogoFile:       logoFile,
+		QRCode:         qrURL,
+		Stats:          statsOnModel,
+		Avatar:         avatar,
+		MonthLabels:    months,
+		Stack:          stack,
+		YearLabels:     yearLabels,
+		YearDividers:   yearDividers,
+		Mold:           mold,
+		SmoothSurface:  smoothSurface,
+		Weekdays:       weekdaysOnly,
+		FutureDays:     future,
+		Unit:           modelUnit,
+	}
+	if cmd.Flags().Changed("base-height") {
+		modelConfig.BaseHeight = modelUnit.ToMillimeters(baseThickness)
+	} else if !cmd.Flags().Changed("base-thickness") {
+		// Left to the layout, which thickens the default base to fit a QR code
+		modelConfig.BaseHeight = 0
+	}
+	if err := modelConfig.Validate(); err != nil {
+		return skyline.Options{}, err
+	}
+
+	bed, err := stl.ParseBed(fit)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+	bed = stl.Bed{Width: modelUnit.ToMillimeters(bed.Width), Depth: modelUnit.ToMillimeters(bed.Depth)}
+
+	backgroundColor, err := render.ParseColor(background)
+	if err != nil {
+		return skyline.Options{}, err
+	}
+	palette, err := themePalette()
+	if err != nil {
+		return skyline.Options{}, err
+	}
+	renderOpts := render.Options{Resolution: resolution, Background: backgroundColor, Palette: palette}
+	if outputFormat == stl.FormatPNG {
+		if err := renderOpts.Validate(); err != nil {
+			return skyline.Options{}, err
+		}
+	}
+
+	opts.SkipEmptyYears = skipEmptyYears
+	opts.Smooth = smooth
+	opts.CapPercentile = capPercentile
+	opts.WeekStart = firstDay
+	opts.Format = outputFormat
+	opts.Fit = bed
+	opts.Unit = modelUnit
+	opts.Split = splitParts
+	opts.SplitYears = splitYears
+	opts.Mirror = mirror
+	opts.Repair = repair
+	opts.Decimate = decimate
+	opts.Profile = profiling
+	opts.QR = qrCode
+	opts.Metadata = generationMetadata(cmd.Flags())
+	opts.Geometry = modelConfig
+	opts.Render = renderOpts
+	opts.PreviewStyle.Palette = palette
+	return opts, nil
+}
+
+// source is where a command reads contributions from, as chosen by the
+// source flags.
+type source struct {
+	user   string                    // Single user given with --user, empty for the authenticated user
+	team   []string                  // Users given with --user to sum, when more than one is
+	merged []string                  // Accounts of one user given with --merge-users
+	kinds  []github.ContributionType // Kinds of contributions to count, empty for all
+	zone   *time.Location            // Time zone to count days in, nil for UTC
+	client *github.Client            // Client to fetch with, nil when reading a data file or the cache alone
+}
+
+// loadSource enables debug logging when asked for, checks that the source
+// flags can be combined and creates the GitHub client, unless contributions
+// are read from a data file or the cache alone.
+func loadSource() (source, error) {
 	log := logger.GetLogger()
 	if debug {
 		log.SetLevel(logger.DEBUG)
 		if err := log.Debug("Debug logging enabled"); err != nil {
-			return err
+			return source{}, err
 		}
 	}
 
-	client, err := github.InitializeGitHubClient()
+	var src source
+	src.user, src.team = splitUsers(users)
+	if org != "" && (len(users) > 0 || offline || input != "") {
+		return source{}, errors.New(errors.ValidationError, "--org cannot be combined with --user, --offline or --input", nil)
+	}
+	if len(mergeUsers) > 0 && (len(users) > 0 || org != "" || input != "") {
+		return source{}, errors.New(errors.ValidationError, "--merge-users cannot be combined with --user, --org or --input", nil)
+	}
+	src.merged = trimUsernames(mergeUsers)
+	if filterOrg != "" && (org != "" || offline || input != "") {
+		return source{}, errors.New(errors.ValidationError, "--filter-org cannot be combined with --org, --offline or --input", nil)
+	}
+	var err error
+	if src.kinds, err = github.ParseContributionTypes(contribTypes); err != nil {
+		return source{}, err
+	}
+	if src.zone, err = loadTimeZone(timeZone); err != nil {
+		return source{}, err
+	}
+	if src.zone != nil && (offline || input != "") {
+		return source{}, errors.New(errors.ValidationError, "--timezone cannot be combined with --offline or --input", nil)
+	}
+	if len(src.kinds) > 0 && (org != "" || offline || input != "") {
+		return source{}, errors.New(errors.ValidationError, "--types cannot be combined with --org, --offline or --input", nil)
+	}
+	if includePrivate && publicOnly {
+		return source{}, errors.New(errors.ValidationError, "--include-private and --public-only cannot be combined", nil)
+	}
+	if (includePrivate || publicOnly) && (org != "" || offline || input != "") {
+		return source{}, errors.New(errors.ValidationError, "--include-private and --public-only cannot be combined with --org, --offline or --input", nil)
+	}
+
+	if input != "" {
+		if _, err := dataset.FormatOf(input); err != nil {
+			return source{}, err
+		}
+		return src, nil
+	}
+	if offline {
+		if noCache {
+			return source{}, errors.New(errors.ValidationError, "offline mode reads the contribution cache, which --no-cache turns off", nil)
+		}
+		return src, nil
+	}
+
+	github.SetAuthToken(resolveToken(token))
+	if src.client, err = github.InitializeGitHubClient(); err != nil {
+		return source{}, errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+	}
+	if err := src.client.SetRetryPolicy(github.RetryPolicy{Attempts: retries, Backoff: retryBackoff, Jitter: retryJitter}); err != nil {
+		return source{}, err
+	}
+	return src, nil
+}
+
+// parsePeriod parses the --year flag into the years it spans and, for a range
+// of months, the first and last day to crop them to.
+func parsePeriod(value string) (startYear, endYear int, from, to time.Time, err error) {
+	if utils.IsMonthRange(value) {
+		if from, to, err = utils.ParseMonthRange(value); err != nil {
+			return 0, 0, time.Time{}, time.Time{}, fmt.Errorf("invalid month range: %v", err)
+		}
+		return from.Year(), to.Year(), from, to, nil
+	}
+	if startYear, endYear, err = utils.ParseYearRange(value); err != nil {
+		return 0, 0, time.Time{}, time.Time{}, fmt.Errorf("invalid year range: %v", err)
+	}
+	return startYear, endYear, time.Time{}, time.Time{}, nil
+}
+
+// resolveToken returns the auth token given by the --token flag, or else the
+// environment, or empty to use the gh CLI's credentials.
+func resolveToken(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	return os.Getenv(github.TokenEnv)
+}
+
+// loadTimeZone returns the time zone named by the --timezone flag, or nil to
+// count days in UTC when none or UTC itself is named.
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	zone, err := time.LoadLocation(name)
 	if err != nil {
-		return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("unknown time zone %q, use an IANA name such as America/Los_Angeles", name), err)
 	}
+	if zone == time.UTC {
+		return nil, nil
+	}
+	return zone, nil
+}
 
-	if web {
-		b := browser.New("", os.Stdout, os.Stderr)
-		if err := openGitHubProfile(user, client, b); err != nil {
-			return err
+// generationMetadata describes how the model is generated for the output
+// file: the tool version, the commit it was built from when known and the
+// flags set on the command line or by the config file, so a model found later
+// can be regenerated.
+func generationMetadata(flags *pflag.FlagSet) []types.Metadata {
+	var settings []string
+	flags.Visit(func(f *pflag.Flag) {
+		if f.Name == "token" {
+			return // Secret, and irrelevant to the model
 		}
+		if f.Value.Type() == "bool" && f.Value.String() == "true" {
+			settings = append(settings, "--"+f.Name)
+			return
+		}
+		value := f.Value.String()
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			// As given on the command line, rather than pflag's [a,b]
+			value = strings.Join(slice.GetSlice(), ",")
+		}
+		settings = append(settings, fmt.Sprintf("--%s=%s", f.Name, value))
+	})
+
+	build := buildinfo.Get()
+	metadata := []types.Metadata{{Key: "version", Value: build.Version}}
+	if commit := build.ShortCommit(); commit != "" {
+		metadata = append(metadata, types.Metadata{Key: "commit", Value: commit})
+	}
+	if len(settings) > 0 {
+		metadata = append(metadata, types.Metadata{Key: "flags", Value: strings.Join(settings, " ")})
+	}
+	return metadata
+}
+
+// splitUsers separates the usernames given with --user into the single user
+// of an individual skyline or the members of a team's, trimming the spaces
+// around each.
+func splitUsers(usernames []string) (string, []string) {
+	switch len(usernames) {
+	case 0:
+		return "", nil
+	case 1:
+		return strings.TrimSpace(usernames[0]), nil
+	}
+	return "", trimUsernames(usernames)
+}
+
+// trimUsernames trims the spaces around each of a list of usernames.
+func trimUsernames(usernames []string) []string {
+	if len(usernames) == 0 {
 		return nil
 	}
+	trimmed := make([]string, len(usernames))
+	for i, username := range usernames {
+		trimmed[i] = strings.TrimSpace(username)
+	}
+	return trimmed
+}
 
-	startYear, endYear, err := utils.ParseYearRange(yearRange)
+// openCache returns the contribution cache in the user's cache directory, or
+// nil when caching is turned off or there is no cache directory to keep it in.
+func openCache() (*cache.Cache, error) {
+	if noCache {
+		return nil, nil
+	}
+	if cacheTTL < 0 {
+		return nil, errors.New(errors.ValidationError, "cache TTL cannot be negative", nil)
+	}
+	dir, err := cache.Dir()
 	if err != nil {
-		return fmt.Errorf("invalid year range: %v", err)
+		return nil, logger.GetLogger().Warning("Not caching contributions: %v", err)
 	}
+	return cache.New(dir, cacheTTL)
+}
+
+// openFile opens a file in the default application for its type, replaced in
+// tests. Launchers print to stderr, keeping stdout for the output of scripts.
+var openFile = func(path string) error {
+	return launch.New(os.Stderr, os.Stderr).Open(path)
+}
 
-	return skyline.GenerateSkyline(startYear, endYear, user, full, output, artOnly)
+// openModelFile opens the model file written at path for --open, warning
+// rather than failing when it cannot, since the model is written.
+func openModelFile(path string) error {
+	log := logger.GetLogger()
+	if err := log.Info("Opening %s", path); err != nil {
+		return err
+	}
+	if err := openFile(path); err != nil {
+		return log.Warning("Failed to open %s, open it yourself instead: %v", path, err)
+	}
+	return nil
 }
 
 // Browser interface matches browser.Browser functionality.
@@ -113,16 +793,14 @@ type Browser interface {
 }
 
 // openGitHubProfile opens the GitHub profile page for the specified user or authenticated user.
-func openGitHubProfile(targetUser string, client skyline.GitHubClientInterface, b Browser) error {
+func openGitHubProfile(ctx context.Context, targetUser string, client skyline.GitHubClientInterface, b Browser) error {
 	if targetUser == "" {
-		username, err := client.GetAuthenticatedUser()
+		username, err := client.GetAuthenticatedUser(ctx)
 		if err != nil {
 			return errors.New(errors.NetworkError, "failed to get authenticated user", err)
 		}
 		targetUser = username
 	}
 
-	hostname, _ := auth.DefaultHost()
-	profileURL := fmt.Sprintf("https://%s/%s", hostname, targetUser)
-	return b.Browse(profileURL)
+	return b.Browse(skyline.ProfileURL(targetUser))
 }
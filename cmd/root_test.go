@@ -1,10 +1,28 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/dataset"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/stl"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/spf13/pflag"
 )
 
 // MockBrowser implements the Browser interface
@@ -34,7 +52,7 @@ func TestRootCmd(t *testing.T) {
 
 func TestInit(t *testing.T) {
 	flags := rootCmd.Flags()
-	expectedFlags := []string{"year", "user", "full", "debug", "web", "art-only", "output"}
+	expectedFlags := []string{"year", "user", "merge-users", "org", "filter-org", "types", "include-private", "public-only", "full", "skip-empty-years", "timezone", "token", "retries", "retry-backoff", "retry-jitter", "cache-ttl", "no-cache", "offline", "input", "debug", "web", "art-only", "output", "export-data", "format", "units", "base-width", "base-depth", "base-thickness", "base-height", "base-style", "stack", "year-labels", "year-dividers", "mold", "layout", "corner-radius", "chamfer", "hollow", "drain-hole", "footprint", "gap", "tower-shape", "tower-segments", "tower-top", "smooth-surface", "min-height", "max-height", "text-style", "face-resolution", "no-text", "no-logo", "logo", "scale", "normalize", "smooth", "cap-percentile", "week-start", "weekdays-only", "future-days", "split-parts", "split-years", "mirror", "repair", "decimate", "profile", "qr", "qr-url", "stats-on-model", "avatar", "month-labels", "fit", "resolution", "background"}
 	for _, flag := range expectedFlags {
 		if flags.Lookup(flag) == nil {
 			t.Errorf("expected flag %s to be initialized", flag)
@@ -83,7 +101,7 @@ func TestOpenGitHubProfile(t *testing.T) {
 			if tt.wantErr {
 				mockBrowser.Err = fmt.Errorf("mock error")
 			}
-			err := openGitHubProfile(tt.targetUser, tt.mockClient, mockBrowser)
+			err := openGitHubProfile(context.Background(), tt.targetUser, tt.mockClient, mockBrowser)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("openGitHubProfile() error = %v, wantErr %v", err, tt.wantErr)
@@ -96,3 +114,390 @@ func TestOpenGitHubProfile(t *testing.T) {
 		})
 	}
 }
+
+// TestGenerationMetadata tests the version and flag settings recorded in output files
+func TestGenerationMetadata(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantFlags string
+	}{
+		{"defaults", nil, ""},
+		{"flags set", []string{"--stack", "--format", "3mf", "--year=2020-2024"}, "--format=3mf --stack --year=2020-2024"},
+		{"flag set to false", []string{"--stack=false"}, "--stack=false"},
+		{"token left out", []string{"--token", "secret", "--stack"}, "--stack"},
+		{"slice flag", []string{"--user", "mona", "--user", "hubot"}, "--user=mona,hubot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("skyline", pflag.ContinueOnError)
+			flags.String("format", "stl", "")
+			flags.String("year", "2024", "")
+			flags.Bool("stack", false, "")
+			flags.String("token", "", "")
+			flags.StringSlice("user", nil, "")
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			metadata := generationMetadata(flags)
+			if metadata[0].Key != "version" || metadata[0].Value == "" {
+				t.Errorf("metadata starts with %v, want the tool version", metadata[0])
+			}
+			flagsSet := ""
+			for _, m := range metadata[1:] {
+				if m.Key == "flags" {
+					flagsSet = m.Value
+				}
+			}
+			if flagsSet != tt.wantFlags {
+				t.Errorf("flags metadata = %q, want %q", flagsSet, tt.wantFlags)
+			}
+		})
+	}
+}
+
+// TestResolveToken tests the --token flag takes precedence over the environment
+func TestResolveToken(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		want string
+	}{
+		{"neither", "", "", ""},
+		{"environment", "", "env-token", "env-token"},
+		{"flag", "flag-token", "", "flag-token"},
+		{"flag over environment", "flag-token", "env-token", "flag-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(github.TokenEnv, tt.env)
+			if got := resolveToken(tt.flag); got != tt.want {
+				t.Errorf("resolveToken(%q) = %q, want %q", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		zone     string
+		wantName string
+		wantErr  bool
+	}{
+		{"none", "", "", false},
+		{"UTC", "UTC", "", false},
+		{"IANA name", "America/Los_Angeles", "America/Los_Angeles", false},
+		{"unknown", "Mars/Olympus_Mons", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := loadTimeZone(tt.zone)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadTimeZone(%q) error = %v, wantErr %v", tt.zone, err, tt.wantErr)
+			}
+			name := ""
+			if zone != nil {
+				name = zone.String()
+			}
+			if name != tt.wantName {
+				t.Errorf("loadTimeZone(%q) = %q, want %q", tt.zone, name, tt.wantName)
+			}
+		})
+	}
+}
+
+// TestSplitUsers tests --user values are told apart as a single user or a team
+func TestSplitUsers(t *testing.T) {
+	tests := []struct {
+		name      string
+		usernames []string
+		wantUser  string
+		wantTeam  []string
+	}{
+		{"none", nil, "", nil},
+		{"one", []string{"mona"}, "mona", nil},
+		{"team", []string{"alice", " bob", "carol "}, "", []string{"alice", "bob", "carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, team := splitUsers(tt.usernames)
+			if user != tt.wantUser || !reflect.DeepEqual(team, tt.wantTeam) {
+				t.Errorf("splitUsers(%v) = %q, %v, want %q, %v", tt.usernames, user, team, tt.wantUser, tt.wantTeam)
+			}
+		})
+	}
+}
+
+func TestOpenCache(t *testing.T) {
+	defer func(ttl time.Duration, disabled bool) { cacheTTL, noCache = ttl, disabled }(cacheTTL, noCache)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		disabled  bool
+		wantCache bool
+		wantErr   bool
+	}{
+		{"default", cache.DefaultTTL, false, true, false},
+		{"no ttl", 0, false, true, false},
+		{"disabled", cache.DefaultTTL, true, false, false},
+		{"negative ttl", -time.Minute, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheTTL, noCache = tt.ttl, tt.disabled
+			got, err := openCache()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openCache() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got != nil) != tt.wantCache {
+				t.Errorf("openCache() = %v, want a cache %v", got, tt.wantCache)
+			}
+		})
+	}
+}
+
+func TestHandleSkylineCommandJSON(t *testing.T) {
+	defer func(path, years, model, printed string, disabled bool) {
+		input, yearRange, output, outputFormat, noCache = path, years, model, printed, disabled
+	}(input, yearRange, output, outputFormat, noCache)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mona.json")
+	days := []types.ContributionDay{{Date: "2024-01-01", ContributionCount: 2}, {Date: "2024-01-02", ContributionCount: 3}}
+	if err := dataset.Write(path, "mona", [][][]types.ContributionDay{{days}}); err != nil {
+		t.Fatalf("dataset.Write() error = %v", err)
+	}
+
+	input, yearRange, output, noCache = path, "2024", filepath.Join(dir, "mona.stl"), true
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetErr(nil)
+	rootCmd.SetContext(context.Background())
+
+	outputFormat = "yaml"
+	if err := handleSkylineCommand(rootCmd, nil); err == nil {
+		t.Error("handleSkylineCommand() accepted an unsupported output format")
+	}
+
+	outputFormat = "json"
+	if err := handleSkylineCommand(rootCmd, nil); err != nil {
+		t.Fatalf("handleSkylineCommand() error = %v", err)
+	}
+	var result skyline.Result
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("handleSkylineCommand() printed more than the result: %v\n%s", err, stdout.String())
+	}
+	if len(result.Files) != 1 || result.Files[0].Path != output || result.Stats.Total != 5 {
+		t.Errorf("handleSkylineCommand() result = %+v, want mona's model at %s", result, output)
+	}
+	if !strings.Contains(stderr.String(), "Model file written successfully") {
+		t.Errorf("handleSkylineCommand() logged %q to stderr, want the log messages", stderr.String())
+	}
+}
+
+func TestHandleSkylineCommandQuiet(t *testing.T) {
+	defer func(path, years, model, printed string, disabled, silent, verbose bool) {
+		input, yearRange, output, outputFormat, noCache, quiet, debug = path, years, model, printed, disabled, silent, verbose
+	}(input, yearRange, output, outputFormat, noCache, quiet, debug)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+	defer logger.GetLogger().SetLevel(logger.INFO)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mona.json")
+	days := []types.ContributionDay{{Date: "2024-01-01", ContributionCount: 2}, {Date: "2024-01-02", ContributionCount: 3}}
+	if err := dataset.Write(path, "mona", [][][]types.ContributionDay{{days}}); err != nil {
+		t.Fatalf("dataset.Write() error = %v", err)
+	}
+
+	input, yearRange, output, noCache, quiet = path, "2024", filepath.Join(dir, "mona.stl"), true, true
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetErr(nil)
+	rootCmd.SetContext(context.Background())
+
+	tests := []struct {
+		name      string
+		printed   string
+		debugging bool
+		wantErr   string
+	}{
+		{"json", "json", false, "--output-format json"},
+		{"debug", "text", true, "--debug"},
+	}
+	for _, tt := range tests {
+		outputFormat, debug = tt.printed, tt.debugging
+		if err := handleSkylineCommand(rootCmd, nil); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("handleSkylineCommand() with --quiet and %s error = %v, want one mentioning %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	outputFormat, debug = "text", false
+	if err := handleSkylineCommand(rootCmd, nil); err != nil {
+		t.Fatalf("handleSkylineCommand() error = %v", err)
+	}
+	if stdout.String() != output+"\n" {
+		t.Errorf("handleSkylineCommand() printed %q, want only the path %s", stdout.String(), output)
+	}
+	if stderr.Len() > 0 {
+		t.Errorf("handleSkylineCommand() logged %q, want no informational messages", stderr.String())
+	}
+}
+
+func TestHandleSkylineCommandStdout(t *testing.T) {
+	defer func(path, years, model, printed string, disabled, silent bool) {
+		input, yearRange, output, outputFormat, noCache, quiet = path, years, model, printed, disabled, silent
+	}(input, yearRange, output, outputFormat, noCache, quiet)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mona.json")
+	days := []types.ContributionDay{{Date: "2024-01-01", ContributionCount: 2}, {Date: "2024-01-02", ContributionCount: 3}}
+	if err := dataset.Write(path, "mona", [][][]types.ContributionDay{{days}}); err != nil {
+		t.Fatalf("dataset.Write() error = %v", err)
+	}
+
+	input, yearRange, output, noCache = path, "2024", "-", true
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetErr(nil)
+	rootCmd.SetContext(context.Background())
+
+	tests := []struct {
+		name    string
+		printed string
+		silent  bool
+	}{
+		{"json", "json", false},
+		{"quiet", "text", true},
+	}
+	for _, tt := range tests {
+		outputFormat, quiet = tt.printed, tt.silent
+		if err := handleSkylineCommand(rootCmd, nil); err == nil || !strings.Contains(err.Error(), "--output -") {
+			t.Errorf("handleSkylineCommand() with --output - and %s error = %v, want it rejected", tt.name, err)
+		}
+	}
+
+	outputFormat, quiet = "text", false
+	if err := handleSkylineCommand(rootCmd, nil); err != nil {
+		t.Fatalf("handleSkylineCommand() error = %v", err)
+	}
+	if _, err := stl.DecodeSTL(&stdout); err != nil {
+		t.Errorf("handleSkylineCommand() printed no STL model to stdout: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "mona") || !strings.Contains(stderr.String(), "streamed successfully") {
+		t.Errorf("handleSkylineCommand() printed %q to stderr, want the preview and log messages", stderr.String())
+	}
+	if _, err := os.Stat("-"); !os.IsNotExist(err) {
+		t.Errorf("handleSkylineCommand() wrote a file named -: %v", err)
+	}
+}
+
+func TestHandleSkylineCommandQR(t *testing.T) {
+	defer func(path, years, model string, disabled, linked bool) {
+		input, yearRange, output, noCache, qrCode = path, years, model, disabled, linked
+	}(input, yearRange, output, noCache, qrCode)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mona.json")
+	days := []types.ContributionDay{{Date: "2024-01-01", ContributionCount: 2}, {Date: "2024-01-02", ContributionCount: 3}}
+	if err := dataset.Write(path, "mona", [][][]types.ContributionDay{{days}}); err != nil {
+		t.Fatalf("dataset.Write() error = %v", err)
+	}
+
+	// The default base is too thin for the code, so it is thickened to fit
+	input, yearRange, output, noCache, qrCode = path, "2024", filepath.Join(dir, "mona.stl"), true, true
+	var stderr bytes.Buffer
+	rootCmd.SetErr(&stderr)
+	defer rootCmd.SetErr(nil)
+	rootCmd.SetContext(context.Background())
+
+	if err := handleSkylineCommand(rootCmd, nil); err != nil {
+		t.Fatalf("handleSkylineCommand() with --qr error = %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("handleSkylineCommand() with --qr wrote no model: %v", err)
+	}
+}
+
+func TestOpenModelFile(t *testing.T) {
+	defer func(open func(string) error) { openFile = open }(openFile)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+
+	tests := []struct {
+		name    string
+		openErr error
+		wantLog string
+	}{
+		{"opened", nil, "Opening mona.stl"},
+		{"no launcher", fmt.Errorf("no launcher"), "Failed to open mona.stl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opened string
+			openFile = func(path string) error {
+				opened = path
+				return tt.openErr
+			}
+			var buf bytes.Buffer
+			logger.GetLogger().SetOutput(&buf)
+
+			if err := openModelFile("mona.stl"); err != nil {
+				t.Errorf("openModelFile() error = %v, want failures to open only warned about", err)
+			}
+			if opened != "mona.stl" {
+				t.Errorf("openModelFile() opened %q, want mona.stl", opened)
+			}
+			if !strings.Contains(buf.String(), tt.wantLog) {
+				t.Errorf("openModelFile() logged %q, want %q", buf.String(), tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestProgressContext(t *testing.T) {
+	defer func(silent, verbose bool) { quiet, debug = silent, verbose }(quiet, debug)
+	quiet, debug = false, false
+
+	file, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		name   string
+		stderr io.Writer
+	}{
+		{"buffer", &bytes.Buffer{}},
+		{"file that is not a terminal", file},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd.SetErr(tt.stderr)
+			defer rootCmd.SetErr(nil)
+			rootCmd.SetContext(context.Background())
+
+			if bar := progress.FromContext(progressContext(rootCmd, os.Stdout)); bar != nil {
+				t.Errorf("progressContext() carries a progress bar, want none when stderr is not a terminal")
+			}
+		})
+	}
+}
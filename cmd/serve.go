@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the serve command.
+var (
+	serveAddress     string
+	serveConcurrency int
+)
+
+// serveCmd serves a web page where models of any user can be downloaded.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a web page where anyone can generate and download a skyline",
+	Long: `Serve a web page where a username and a year or range of years can be
+entered and the model downloaded, so a team can share one installation of the
+extension. The server runs until it is stopped with Ctrl+C.
+
+Contributions are fetched with the server's GitHub credentials and cached as
+for any other model, and every model is generated with the server's model
+flags. --format chooses the format selected on the page.`,
+	Example: `  gh skyline serve
+  gh skyline serve --addr :8080 --base-style rounded --no-logo`,
+	Args: cobra.NoArgs,
+	RunE: handleServeCommand,
+}
+
+// init registers the serve command and its flags.
+func init() {
+	flags := serveCmd.Flags()
+	addFetchFlags(flags)
+	_ = flags.MarkHidden("year")
+	flags.StringVar(&serveAddress, "addr", skyline.DefaultServeAddress, "Address to listen on, such as :8080 to be reachable from other machines")
+	flags.IntVar(&serveConcurrency, "concurrency", skyline.DefaultBatchConcurrency, "Number of models generated at once, with further requests waiting their turn")
+	addModelFlags(flags)
+	rootCmd.AddCommand(serveCmd)
+}
+
+// handleServeCommand is the main execution function for the serve command.
+func handleServeCommand(cmd *cobra.Command, _ []string) error {
+	if cmd.Flags().Changed("year") {
+		return errors.New(errors.ValidationError, "the years of each model are entered on the served page", nil)
+	}
+
+	src, err := loadSource()
+	if err != nil {
+		return err
+	}
+
+	contributionCache, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	opts, err := modelOptions(cmd, skyline.Options{
+		TimeZone:       src.zone,
+		Cache:          contributionCache,
+		Client:         src.client,
+		Offline:        offline,
+		FilterOrg:      filterOrg,
+		Types:          src.kinds,
+		PublicOnly:     publicOnly,
+		IncludePrivate: includePrivate,
+	})
+	if err != nil {
+		return err
+	}
+	return skyline.Serve(cmd.Context(), opts, serveAddress, serveConcurrency)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServeCmd(t *testing.T) {
+	if cmd, _, err := rootCmd.Find([]string{"serve"}); err != nil || cmd != serveCmd {
+		t.Fatalf("expected serve to be a subcommand of the root command, got %v, %v", cmd, err)
+	}
+
+	flags := serveCmd.Flags()
+	for _, flag := range []string{"addr", "concurrency", "filter-org", "timezone", "token", "no-cache", "offline", "format", "base-style"} {
+		if flags.Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+	for _, flag := range []string{"user", "org", "input", "full", "output", "art-only", "export-data"} {
+		if flags.Lookup(flag) != nil {
+			t.Errorf("expected flag %s not to be initialized", flag)
+		}
+	}
+}
+
+func TestHandleServeCommandYear(t *testing.T) {
+	defer func(years string) { yearRange = years }(yearRange)
+	if err := serveCmd.Flags().Set("year", "2024"); err != nil {
+		t.Fatalf("Set(year) error = %v", err)
+	}
+	defer func() { serveCmd.Flags().Lookup("year").Changed = false }()
+
+	if err := handleServeCommand(serveCmd, nil); err == nil || !strings.Contains(err.Error(), "served page") {
+		t.Errorf("handleServeCommand() error = %v, want one about the years entered on the page", err)
+	}
+}
@@ -0,0 +1,133 @@
+package skyline
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/profile"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// DefaultBatchConcurrency is the number of models of a batch generated at once
+// when none is configured, few enough to stay clear of GitHub's secondary rate
+// limit while each user's years are fetched concurrently too.
+const DefaultBatchConcurrency = 2
+
+// GenerateBatch generates a model for each of usernames as GenerateSkyline
+// would with opts, up to concurrency of them at once and without their ASCII
+// previews. opts.Output names each model with utils.UserPlaceholder, which it
+// must hold for more than one user, and utils.YearPlaceholder. A user whose
+// model fails is reported while the others are still generated, and the error
+// returned names every user that failed.
+func GenerateBatch(ctx context.Context, opts Options, usernames []string, concurrency int) error {
+	log := logger.GetLogger()
+	if len(usernames) == 0 {
+		return errors.New(errors.ValidationError, "the batch lists no users", nil)
+	}
+	if err := validateUsernames(usernames, "batch"); err != nil {
+		return err
+	}
+	if concurrency < 1 {
+		return errors.New(errors.ValidationError, "batch concurrency must be at least 1", nil)
+	}
+	if opts.User != "" || len(opts.Team) > 0 || len(opts.MergeUsers) > 0 || opts.Org != "" || opts.Input != "" || opts.ExportData != "" {
+		return errors.New(errors.ValidationError, "a batch generates a model of each of its users' contributions alone", nil)
+	}
+	if len(usernames) > 1 && opts.Output != "" && !strings.Contains(opts.Output, utils.UserPlaceholder) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("the output path of a batch must hold %s, so its models do not overwrite each other", utils.UserPlaceholder), nil)
+	}
+	if opts.Profile != profile.ModeNone {
+		return errors.New(errors.ValidationError, "models generated at once cannot be profiled, profile one user's instead", nil)
+	}
+
+	opts, err := shareClient(opts)
+	if err != nil {
+		return err
+	}
+	opts.ArtOnly, opts.noPreview = false, true
+	if opts.PathsOut != nil {
+		opts.PathsOut = &lockedWriter{w: opts.PathsOut}
+	}
+
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+	results := make([]error, len(usernames))
+	for w := 0; w < min(concurrency, len(usernames)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := int(next.Add(1) - 1); u < len(usernames) && ctx.Err() == nil; u = int(next.Add(1) - 1) {
+				userOpts := opts
+				userOpts.User = usernames[u]
+				results[u] = GenerateSkyline(ctx, userOpts)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return errors.New(errors.GeneralError, "batch canceled", err)
+	}
+	var failed []string
+	var errs []error
+	for u, err := range results {
+		if err == nil {
+			continue
+		}
+		if logErr := log.Error("Failed to generate the model of %s: %v", usernames[u], err); logErr != nil {
+			return logErr
+		}
+		failed = append(failed, usernames[u])
+		errs = append(errs, fmt.Errorf("%s: %w", usernames[u], err))
+	}
+	if len(failed) > 0 {
+		return errors.New(errors.GeneralError, fmt.Sprintf("failed to generate the models of %d of %d users: %s", len(failed), len(usernames), strings.Join(failed, ", ")), stderrors.Join(errs...))
+	}
+	return log.Info("Generated the models of %d users", len(usernames))
+}
+
+// lockedWriter writes to w one write at a time, for the models of a batch
+// generated at once to print their files.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write writes p to the underlying writer once no other write is.
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// shareClient returns opts with a client to be shared by users generated at
+// once: created when there is none, with the time zone set on it once rather
+// than by each user's generation. Cached contributions were counted on the
+// days of UTC, so they are not used in another time zone.
+func shareClient(opts Options) (Options, error) {
+	if opts.Offline {
+		return opts, nil
+	}
+	if opts.Client == nil {
+		client, err := github.InitializeGitHubClient()
+		if err != nil {
+			return opts, errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		}
+		opts.Client = client
+	}
+	if opts.TimeZone != nil {
+		opts.Client.SetTimeZone(opts.TimeZone)
+		opts.TimeZone, opts.Cache = nil, nil
+	}
+	return opts, nil
+}
@@ -0,0 +1,118 @@
+package skyline
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/profile"
+)
+
+func TestGenerateBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        Options
+		usernames   []string
+		concurrency int
+		wantFiles   []string
+		wantError   string
+	}{
+		{
+			name:        "all users",
+			usernames:   []string{"mona", "hubot"},
+			concurrency: 2,
+			wantFiles:   []string{"mona-2024.stl", "hubot-2024.stl"},
+		},
+		{
+			name:        "failing user",
+			usernames:   []string{"mona", "nobody", "hubot"},
+			concurrency: 1,
+			wantFiles:   []string{"mona-2024.stl", "hubot-2024.stl"},
+			wantError:   "1 of 3 users: nobody",
+		},
+		{
+			name:        "no users",
+			concurrency: 1,
+			wantError:   "no users",
+		},
+		{
+			name:        "listed twice",
+			usernames:   []string{"mona", "Mona"},
+			concurrency: 1,
+			wantError:   "listed twice",
+		},
+		{
+			name:      "no concurrency",
+			usernames: []string{"mona"},
+			wantError: "concurrency",
+		},
+		{
+			name:        "output without user",
+			opts:        Options{Output: "skyline.stl"},
+			usernames:   []string{"mona", "hubot"},
+			concurrency: 1,
+			wantError:   "{user}",
+		},
+		{
+			name:        "team",
+			opts:        Options{Team: []string{"mona", "hubot"}},
+			usernames:   []string{"mona"},
+			concurrency: 1,
+			wantError:   "each of its users",
+		},
+		{
+			name:        "profiled",
+			opts:        Options{Profile: profile.ModeCPU},
+			usernames:   []string{"mona"},
+			concurrency: 1,
+			wantError:   "profiled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.opts.StartYear, tt.opts.EndYear = 2024, 2024
+			tt.opts.Client = github.NewClient(compareAPI)
+			if tt.opts.Output == "" {
+				tt.opts.Output = filepath.Join(dir, "{user}-{year}.stl")
+			}
+
+			err := GenerateBatch(context.Background(), tt.opts, tt.usernames, tt.concurrency)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateBatch() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+			} else if err != nil {
+				t.Fatalf("GenerateBatch() error = %v", err)
+			}
+			for _, file := range tt.wantFiles {
+				if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+					t.Errorf("GenerateBatch() did not write %s: %v", file, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBatchPaths(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	opts := Options{StartYear: 2024, EndYear: 2024, Client: github.NewClient(compareAPI), Output: filepath.Join(dir, "{user}-{year}.stl"), PathsOut: &buf}
+	if err := GenerateBatch(context.Background(), opts, []string{"mona", "hubot"}, 2); err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+
+	got := strings.Fields(buf.String())
+	sort.Strings(got)
+	want := []string{filepath.Join(dir, "hubot-2024.stl"), filepath.Join(dir, "mona-2024.stl")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GenerateBatch() printed %q, want the paths %v", buf.String(), want)
+	}
+}
@@ -0,0 +1,138 @@
+package skyline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stats"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// Side is one of the two contribution histories a comparison sets side by
+// side: the contributions of a user in a year.
+type Side struct {
+	User string // GitHub user, defaults to the authenticated user
+	Year int    // Year of the user's contributions
+}
+
+// previewGap is the number of spaces between the two previews of a comparison.
+const previewGap = 4
+
+// Compare fetches the contributions of two sides as GenerateSkyline would
+// with opts, and prints their ASCII previews side by side to out, followed by
+// their statistics and the difference between them. Unless opts.ArtOnly is
+// set, a model with the skylines of the two sides in rows, the first behind
+// the second, is written as well.
+func Compare(ctx context.Context, opts Options, sides [2]Side, out io.Writer) error {
+	if len(opts.Team) > 0 || len(opts.MergeUsers) > 0 || opts.Org != "" || opts.Input != "" || opts.Full {
+		return errors.New(errors.ValidationError, "a comparison is of a user's contributions in a year on each side", nil)
+	}
+
+	var names [2]string
+	var grids [2][][]types.ContributionDay
+	for i, side := range sides {
+		sideOpts := opts
+		sideOpts.User, sideOpts.StartYear, sideOpts.EndYear = side.User, side.Year, side.Year
+		sideOpts.StatsOut = nil
+		sideOpts.collect = func(name string, years [][][]types.ContributionDay) {
+			names[i], grids[i] = name, years[0]
+		}
+		if err := GenerateSkyline(ctx, sideOpts); err != nil {
+			return err
+		}
+	}
+	if names[0] == names[1] && sides[0].Year == sides[1].Year {
+		return errors.New(errors.ValidationError, fmt.Sprintf("both sides are %s in %d, compare two users or two years", names[0], sides[0].Year), nil)
+	}
+
+	// Sides of the same user are told apart by their years, and others by their users
+	labels := names
+	name, period := names[0]+"-vs-"+names[1], strconv.Itoa(sides[0].Year)
+	if names[0] == names[1] {
+		labels = [2]string{strconv.Itoa(sides[0].Year), strconv.Itoa(sides[1].Year)}
+		name, period = names[0], labels[0]+"-vs-"+labels[1]
+	}
+
+	// Each preview gets half of the width, when it is limited
+	style := opts.PreviewStyle
+	if style.Width > 0 {
+		style.Width = max((style.Width-previewGap)/2, 1)
+	}
+	var previews [2]string
+	for i := range sides {
+		preview, err := ascii.GenerateASCIIStyled(grids[i], names[i], strconv.Itoa(sides[i].Year), false, true, style)
+		if err != nil {
+			return err
+		}
+		previews[i] = preview
+	}
+	if _, err := fmt.Fprintln(out, ascii.SideBySide(previews[0], previews[1], previewGap)); err != nil {
+		return err
+	}
+	summaries := [2]stats.Summary{
+		stats.Compute([][][]types.ContributionDay{grids[0]}),
+		stats.Compute([][][]types.ContributionDay{grids[1]}),
+	}
+	if err := writeComparison(out, labels, summaries); err != nil {
+		return err
+	}
+
+	if opts.ArtOnly {
+		return nil
+	}
+	format := opts.Format
+	if format == "" {
+		format = stl.FormatSTL
+	}
+	return stl.GenerateModelContext(ctx, [][][]types.ContributionDay{grids[0], grids[1]}, stl.Options{
+		OutputPath: utils.GenerateOutputFilenameForPeriod(name, period, opts.Output, format.Extension()),
+		Format:     format,
+		Username:   name,
+		StartYear:  min(sides[0].Year, sides[1].Year),
+		EndYear:    max(sides[0].Year, sides[1].Year),
+		Years:      []int{sides[0].Year, sides[1].Year},
+		Period:     period,
+		Fit:        opts.Fit,
+		Unit:       opts.Unit,
+		Metadata:   opts.Metadata,
+		Geometry:   opts.Geometry,
+		Render:     opts.Render,
+	})
+}
+
+// writeComparison prints the statistics of the two sides of a comparison to w
+// in a table, along with how much the second side differs from the first.
+func writeComparison(w io.Writer, labels [2]string, s [2]stats.Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "\t%s\t%s\tdifference\n", labels[0], labels[1])
+	rows := []struct {
+		name   string
+		values [2]int
+		detail func(stats.Summary) string
+	}{
+		{"Total contributions", [2]int{s[0].Total, s[1].Total}, nil},
+		{"Active days", [2]int{s[0].ActiveDays, s[1].ActiveDays}, nil},
+		{"Busiest day", [2]int{s[0].BusiestDay.ContributionCount, s[1].BusiestDay.ContributionCount}, func(s stats.Summary) string { return s.BusiestDay.Date }},
+		{"Busiest week", [2]int{s[0].BusiestWeekTotal, s[1].BusiestWeekTotal}, func(s stats.Summary) string { return s.BusiestWeek }},
+		{"Longest streak", [2]int{s[0].LongestStreak, s[1].LongestStreak}, nil},
+	}
+	for _, row := range rows {
+		fmt.Fprint(tw, row.name)
+		for i, value := range row.values {
+			cell := strconv.Itoa(value)
+			if row.detail != nil && value > 0 {
+				cell = fmt.Sprintf("%d (%s)", value, row.detail(s[i]))
+			}
+			fmt.Fprintf(tw, "\t%s", cell)
+		}
+		fmt.Fprintf(tw, "\t%+d\n", row.values[1]-row.values[0])
+	}
+	return tw.Flush()
+}
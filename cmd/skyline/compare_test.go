@@ -0,0 +1,147 @@
+package skyline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stats"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// compareAPI serves a day of contributions for mona and hubot in 2023 and
+// 2024, with more of them for hubot and in 2024.
+var compareAPI = mocks.GraphQLFunc(func(_ string, variables map[string]interface{}) (string, error) {
+	username, _ := variables["username"].(string)
+	count := map[string]int{"mona": 2, "hubot": 5}[username]
+	if count == 0 {
+		return `{"user": null}`, nil
+	}
+	var years []string
+	for _, year := range []int{2023, 2024} {
+		years = append(years, fmt.Sprintf(`"y%d": {"contributionCalendar": {"totalContributions": %d, "weeks": [{"contributionDays": [{"contributionCount": %d, "date": "%d-01-02"}]}]}}`, year, count+year-2023, count+year-2023, year))
+	}
+	return fmt.Sprintf(`{"user": {"login": %q, %s}}`, username, strings.Join(years, ", ")), nil
+})
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		sides     [2]Side
+		want      []string
+		width     int // Columns no line of the output may exceed, 0 for no limit
+		wantModel string
+		wantError string
+	}{
+		{
+			name:  "users",
+			opts:  Options{ArtOnly: true},
+			sides: [2]Side{{User: "mona", Year: 2024}, {User: "hubot", Year: 2024}},
+			want:  []string{"mona", "hubot", "difference", "Total contributions 3 6 +3"},
+		},
+		{
+			name:  "years",
+			opts:  Options{ArtOnly: true},
+			sides: [2]Side{{User: "mona", Year: 2023}, {User: "mona", Year: 2024}},
+			want:  []string{"2023", "2024", "Total contributions 2 3 +1", "Busiest week 2 (2023-01-01) 3 (2023-12-31) +1"},
+		},
+		{
+			name:  "narrow terminal",
+			opts:  Options{ArtOnly: true, PreviewStyle: ascii.Style{Width: 70}},
+			sides: [2]Side{{User: "mona", Year: 2024}, {User: "hubot", Year: 2024}},
+			want:  []string{"mona", "hubot"},
+			width: 70,
+		},
+		{
+			name:      "users model",
+			sides:     [2]Side{{User: "mona", Year: 2024}, {User: "hubot", Year: 2024}},
+			wantModel: "model.stl",
+		},
+		{
+			name:      "same side",
+			opts:      Options{ArtOnly: true},
+			sides:     [2]Side{{User: "mona", Year: 2024}, {User: "mona", Year: 2024}},
+			wantError: "both sides",
+		},
+		{
+			name:      "team",
+			opts:      Options{ArtOnly: true, Team: []string{"mona", "hubot"}},
+			sides:     [2]Side{{User: "mona", Year: 2023}, {User: "mona", Year: 2024}},
+			wantError: "a user's contributions",
+		},
+		{
+			name:      "no account",
+			opts:      Options{ArtOnly: true},
+			sides:     [2]Side{{User: "mona", Year: 2024}, {User: "nobody", Year: 2024}},
+			wantError: "nobody",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Client = github.NewClient(compareAPI)
+			dir := t.TempDir()
+			if tt.wantModel != "" {
+				tt.opts.Output = filepath.Join(dir, tt.wantModel)
+			}
+
+			var buf bytes.Buffer
+			err := Compare(context.Background(), tt.opts, tt.sides, &buf)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("Compare() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			output := strings.Join(strings.Fields(buf.String()), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("Compare() output lacks %q:\n%s", want, buf.String())
+				}
+			}
+			for _, line := range strings.Split(buf.String(), "\n") {
+				if tt.width > 0 && utf8.RuneCountInString(line) > tt.width {
+					t.Errorf("Compare() output line %q is wider than %d columns", line, tt.width)
+				}
+			}
+			if tt.wantModel != "" {
+				if _, err := os.Stat(tt.opts.Output); err != nil {
+					t.Errorf("Compare() did not write the model: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteComparison(t *testing.T) {
+	summaries := [2]stats.Summary{
+		{Total: 10, ActiveDays: 4, BusiestDay: types.ContributionDay{Date: "2024-01-02", ContributionCount: 5}, BusiestWeek: "2023-12-31", BusiestWeekTotal: 8, LongestStreak: 2, StreakStart: "2024-01-01"},
+		{},
+	}
+	var buf bytes.Buffer
+	if err := writeComparison(&buf, [2]string{"mona", "hubot"}, summaries); err != nil {
+		t.Fatalf("writeComparison() error = %v", err)
+	}
+	for _, want := range []string{
+		"mona hubot difference",
+		"Total contributions 10 0 -10",
+		"Busiest day 5 (2024-01-02) 0 -5",
+		"Longest streak 2 0 -2",
+	} {
+		if !strings.Contains(strings.Join(strings.Fields(buf.String()), " "), want) {
+			t.Errorf("writeComparison() output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
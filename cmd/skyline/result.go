@@ -0,0 +1,178 @@
+package skyline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/stats"
+	"github.com/github/gh-skyline/internal/stl"
+)
+
+// OutputFormat identifies how the outcome of generating a model is printed.
+type OutputFormat string
+
+// Supported output formats.
+const (
+	OutputText OutputFormat = "text" // ASCII preview and log messages, for people
+	OutputJSON OutputFormat = "json" // A JSON Result, for scripts
+)
+
+// DefaultOutputFormat is the output format used when none is given.
+const DefaultOutputFormat = OutputText
+
+// outputFormats lists the supported output formats in the order they are
+// presented to users.
+var outputFormats = []OutputFormat{OutputText, OutputJSON}
+
+// OutputFormats returns the names of all supported output formats.
+func OutputFormats() []string {
+	names := make([]string, len(outputFormats))
+	for i, f := range outputFormats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// ParseOutputFormat converts a user supplied output format name into an
+// OutputFormat. Matching is case-insensitive and an empty string selects
+// DefaultOutputFormat.
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	if name == "" {
+		return DefaultOutputFormat, nil
+	}
+	for _, f := range outputFormats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported output format %q (supported: %s)", name, strings.Join(OutputFormats(), ", ")), nil)
+}
+
+// Result describes a generated model for scripts, written as JSON to
+// Options.ResultOut in place of the preview.
+type Result struct {
+	User      string       `json:"user"`
+	Period    string       `json:"period"` // Years or months covered, such as 2023-24
+	Format    stl.Format   `json:"format"`
+	Files     []ResultFile `json:"files"`
+	Triangles int          `json:"triangles"`
+	Stats     ResultStats  `json:"stats"`
+	Timing    ResultTiming `json:"timing"`
+	Warnings  []string     `json:"warnings"`
+}
+
+// ResultFile is a file written for a model.
+type ResultFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"` // In bytes
+}
+
+// ResultStats are the statistics of the contributions a model was generated
+// from, before any smoothing or capping.
+type ResultStats struct {
+	Total            int    `json:"total"`
+	ActiveDays       int    `json:"activeDays"`
+	BusiestDay       string `json:"busiestDay,omitempty"`
+	BusiestDayTotal  int    `json:"busiestDayTotal"`
+	BusiestWeek      string `json:"busiestWeek,omitempty"` // Sunday starting the week
+	BusiestWeekTotal int    `json:"busiestWeekTotal"`
+	LongestStreak    int    `json:"longestStreak"` // In days
+	StreakStart      string `json:"streakStart,omitempty"`
+	StreakEnd        string `json:"streakEnd,omitempty"`
+	Weekdays         [7]int `json:"weekdays"` // Contributions on each day of the week, from Sunday
+}
+
+// ResultTiming is how long generating a model took, in milliseconds.
+type ResultTiming struct {
+	Fetch    int64 `json:"fetchMs"`    // Fetching or reading the contributions
+	Generate int64 `json:"generateMs"` // Building the model and writing its files
+	Total    int64 `json:"totalMs"`
+}
+
+// newResultStats converts a summary of contributions for a Result.
+func newResultStats(s stats.Summary) ResultStats {
+	return ResultStats{
+		Total:            s.Total,
+		ActiveDays:       s.ActiveDays,
+		BusiestDay:       s.BusiestDay.Date,
+		BusiestDayTotal:  s.BusiestDay.ContributionCount,
+		BusiestWeek:      s.BusiestWeek,
+		BusiestWeekTotal: s.BusiestWeekTotal,
+		LongestStreak:    s.LongestStreak,
+		StreakStart:      s.StreakStart,
+		StreakEnd:        s.StreakEnd,
+		Weekdays:         s.Weekdays,
+	}
+}
+
+// resultFiles describes the files at paths, with their sizes.
+func resultFiles(paths []string) ([]ResultFile, error) {
+	files := make([]ResultFile, len(paths))
+	for i, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.New(errors.IOError, fmt.Sprintf("failed to read the size of %s", path), err)
+		}
+		files[i] = ResultFile{Path: path, Size: info.Size()}
+	}
+	return files, nil
+}
+
+// generateResult generates the skyline as GenerateSkyline does, describing it
+// to opts.ResultOut as JSON, along with the warnings logged meanwhile, once it
+// is generated.
+func generateResult(ctx context.Context, opts Options) error {
+	if opts.ArtOnly {
+		return errors.New(errors.ValidationError, "JSON output describes the model generated, so it cannot be given with only the ASCII preview", nil)
+	}
+	started := time.Now()
+	result := &Result{}
+	opts.result, opts.started = result, started
+
+	stopCollecting := logger.GetLogger().CollectWarnings()
+	err := generateSkyline(ctx, opts)
+	result.Warnings = stopCollecting()
+	if err != nil {
+		return err
+	}
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+	result.Timing.Total = time.Since(started).Milliseconds()
+
+	encoder := json.NewEncoder(opts.ResultOut)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return errors.New(errors.IOError, "failed to write the result", err)
+	}
+	return nil
+}
+
+// generatePaths generates the skyline as GenerateSkyline does, printing only
+// the paths of the files written to opts.PathsOut, one per line, at once.
+func generatePaths(ctx context.Context, opts Options) error {
+	if opts.ArtOnly {
+		return errors.New(errors.ValidationError, "quiet output prints the files generated, so it cannot be given with only the ASCII preview", nil)
+	}
+	result := &Result{}
+	opts.result, opts.started = result, time.Now()
+	if err := generateSkyline(ctx, opts); err != nil {
+		return err
+	}
+
+	var paths strings.Builder
+	for _, file := range result.Files {
+		fmt.Fprintln(&paths, file.Path)
+	}
+	if _, err := io.WriteString(opts.PathsOut, paths.String()); err != nil {
+		return errors.New(errors.IOError, "failed to print the files written", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package skyline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{"", OutputText, false},
+		{"text", OutputText, false},
+		{"JSON", OutputJSON, false},
+		{"yaml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOutputFormat(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseOutputFormat(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestGenerateSkylineResult(t *testing.T) {
+	api := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"user": {"login": "mona", "y2024": {"contributionCalendar": {"totalContributions": 5, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2024-01-01"}, {"contributionCount": 3, "date": "2024-01-02"}]}]}}}}`, nil
+	})
+
+	var buf bytes.Buffer
+	output := filepath.Join(t.TempDir(), "model.stl")
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "mona", Output: output, Format: stl.FormatSTL, ResultOut: &buf, Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+
+	var result Result
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("GenerateSkyline() wrote invalid JSON: %v\n%s", err, buf.String())
+	}
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatalf("GenerateSkyline() wrote no model: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Path != output || result.Files[0].Size != info.Size() {
+		t.Errorf("Result.Files = %+v, want %s of %d bytes", result.Files, output, info.Size())
+	}
+	triangles, err := stl.ReadSTL(output)
+	if err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+	if result.Triangles != len(triangles) {
+		t.Errorf("Result.Triangles = %d, want the %d written", result.Triangles, len(triangles))
+	}
+	if result.User != "mona" || result.Period != "2024" || result.Format != stl.FormatSTL {
+		t.Errorf("Result = %+v, want mona's 2024 STL model", result)
+	}
+	if result.Stats.Total != 5 || result.Stats.LongestStreak != 2 || result.Stats.BusiestDay != "2024-01-02" {
+		t.Errorf("Result.Stats = %+v, want 5 contributions over a 2 day streak", result.Stats)
+	}
+	if result.Timing.Total < result.Timing.Generate || result.Warnings == nil {
+		t.Errorf("Result = %+v, want timings and an empty list of warnings", result)
+	}
+	if !strings.Contains(buf.String(), `"warnings": []`) {
+		t.Errorf("GenerateSkyline() result lacks an empty warnings list:\n%s", buf.String())
+	}
+}
+
+func TestGenerateSkylineResultArtOnly(t *testing.T) {
+	var buf bytes.Buffer
+	err := GenerateSkyline(context.Background(), Options{StartYear: 2024, EndYear: 2024, User: "mona", ArtOnly: true, ResultOut: &buf})
+	if err == nil || !strings.Contains(err.Error(), "ASCII preview") {
+		t.Errorf("GenerateSkyline() error = %v, want JSON output rejected with art only", err)
+	}
+}
+
+func TestGenerateSkylinePaths(t *testing.T) {
+	api := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"user": {"login": "mona", "y2024": {"contributionCalendar": {"totalContributions": 5, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2024-01-01"}, {"contributionCount": 3, "date": "2024-01-02"}]}]}}}}`, nil
+	})
+
+	var buf bytes.Buffer
+	output := filepath.Join(t.TempDir(), "model.stl")
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "mona", Output: output, Split: true, PathsOut: &buf, Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	paths := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(paths) < 2 {
+		t.Fatalf("GenerateSkyline() printed %q, want a path per part", buf.String())
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil || !strings.HasPrefix(filepath.Base(path), "model") {
+			t.Errorf("GenerateSkyline() printed %q, which is not a part written: %v", path, err)
+		}
+	}
+
+	opts.ArtOnly = true
+	if err := GenerateSkyline(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "ASCII preview") {
+		t.Errorf("GenerateSkyline() error = %v, want quiet output rejected with art only", err)
+	}
+}
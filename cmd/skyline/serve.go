@@ -0,0 +1,219 @@
+package skyline
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	stderrors "errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/profile"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// DefaultServeAddress is the address the server listens on when none is
+// configured, reachable from this machine only.
+const DefaultServeAddress = "localhost:8080"
+
+// serveTimeout bounds how long a request's headers take to arrive, and how
+// long requests in flight are waited for once the server is stopped.
+const serveTimeout = 10 * time.Second
+
+// loginPattern matches GitHub logins: alphanumerics and single hyphens, not
+// leading, up to 39 characters. Logins from requests name the files written,
+// so nothing else is accepted.
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:-?[A-Za-z0-9]){0,38}$`)
+
+// servePage is the form a user and years are entered in.
+//
+//go:embed serve.html
+var servePage string
+
+// servePageTemplate renders servePage.
+var servePageTemplate = template.Must(template.New("serve").Parse(servePage))
+
+// servePageData fills in servePage.
+type servePageData struct {
+	Year    int
+	Format  string
+	Formats []string
+}
+
+// NewServeHandler returns an HTTP handler serving a page where a model of any
+// user and years can be requested, generated as GenerateSkyline would with
+// opts and downloaded, up to concurrency of them at once. Requests beyond
+// those wait for their turn.
+func NewServeHandler(opts Options, concurrency int) (http.Handler, error) {
+	if concurrency < 1 {
+		return nil, errors.New(errors.ValidationError, "server concurrency must be at least 1", nil)
+	}
+	if opts.User != "" || len(opts.Team) > 0 || len(opts.MergeUsers) > 0 || opts.Org != "" || opts.Input != "" || opts.ExportData != "" || opts.Output != "" {
+		return nil, errors.New(errors.ValidationError, "the server generates the model of the user each request names", nil)
+	}
+	if opts.Split || opts.SplitYears {
+		return nil, errors.New(errors.ValidationError, "the server downloads each model as one file, so cannot split it", nil)
+	}
+	if opts.Profile != profile.ModeNone {
+		return nil, errors.New(errors.ValidationError, "models generated by the server cannot be profiled, profile one user's instead", nil)
+	}
+	opts, err := shareClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.ArtOnly, opts.noPreview, opts.View, opts.StatsOut = false, true, nil, nil
+
+	format := opts.Format
+	if format == "" {
+		format = stl.FormatSTL
+	}
+	slots := make(chan struct{}, concurrency)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		var page bytes.Buffer
+		if err := servePageTemplate.Execute(&page, servePageData{Year: time.Now().Year(), Format: string(format), Formats: stl.Formats()}); err != nil {
+			http.Error(w, "failed to render the page", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = page.WriteTo(w)
+	})
+	mux.HandleFunc("GET /model", func(w http.ResponseWriter, r *http.Request) {
+		requestOpts, err := modelRequest(opts, format, r)
+		if err != nil {
+			writeServeError(w, err)
+			return
+		}
+
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		case <-r.Context().Done():
+			return
+		}
+
+		dir, err := os.MkdirTemp("", "gh-skyline-serve-")
+		if err != nil {
+			writeServeError(w, errors.New(errors.IOError, "failed to create a temporary directory", err))
+			return
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+
+		name := utils.GenerateOutputFilenameWithExt(requestOpts.User, requestOpts.StartYear, requestOpts.EndYear, "", requestOpts.Format.Extension())
+		requestOpts.Output = filepath.Join(dir, name)
+		if err := GenerateSkyline(r.Context(), requestOpts); err != nil {
+			writeServeError(w, err)
+			return
+		}
+		model, err := os.ReadFile(requestOpts.Output)
+		if err != nil {
+			writeServeError(w, errors.New(errors.IOError, "failed to read the generated model", err))
+			return
+		}
+
+		contentType := mime.TypeByExtension(requestOpts.Format.Extension())
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+		w.Header().Set("Content-Length", strconv.Itoa(len(model)))
+		_, _ = w.Write(model)
+	})
+	return mux, nil
+}
+
+// modelRequest returns opts for the model a request asks for, from its user,
+// year and format query parameters. The year defaults to the current year and
+// the format to defaultFormat.
+func modelRequest(opts Options, defaultFormat stl.Format, r *http.Request) (Options, error) {
+	query := r.URL.Query()
+	opts.User = query.Get("user")
+	if !loginPattern.MatchString(opts.User) {
+		return opts, errors.New(errors.ValidationError, fmt.Sprintf("%q is not a GitHub username", opts.User), nil)
+	}
+
+	opts.StartYear, opts.EndYear = time.Now().Year(), time.Now().Year()
+	if year := query.Get("year"); year != "" {
+		var err error
+		if opts.StartYear, opts.EndYear, err = utils.ParseYearRange(year); err != nil {
+			return opts, errors.New(errors.ValidationError, "invalid year range", err)
+		}
+	}
+
+	opts.Format = defaultFormat
+	if name := query.Get("format"); name != "" {
+		var err error
+		if opts.Format, err = stl.ParseFormat(name); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// writeServeError replies to a request whose model failed with the status
+// matching the kind of error, logging failures that are not the request's.
+func writeServeError(w http.ResponseWriter, err error) {
+	var skylineErr *errors.SkylineError
+	status := http.StatusInternalServerError
+	if stderrors.As(err, &skylineErr) {
+		switch skylineErr.Type {
+		case errors.ValidationError:
+			status = http.StatusBadRequest
+		case errors.NotFoundError:
+			status = http.StatusNotFound
+		}
+	}
+	if status == http.StatusInternalServerError {
+		_ = logger.GetLogger().Error("Failed to serve a model: %v", err)
+		http.Error(w, "failed to generate the model", status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// Serve serves the handler NewServeHandler returns for opts and concurrency at
+// addr until ctx is done.
+func Serve(ctx context.Context, opts Options, addr string, concurrency int) error {
+	log := logger.GetLogger()
+	handler, err := NewServeHandler(opts, concurrency)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to listen on %s", addr), err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: serveTimeout}
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(listener) }()
+	if err := log.Info("Serving models at http://%s/, press Ctrl+C to stop", listener.Addr()); err != nil {
+		_ = server.Close()
+		return err
+	}
+
+	select {
+	case err := <-served:
+		return errors.New(errors.NetworkError, "the server stopped", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return errors.New(errors.NetworkError, "failed to stop the server", err)
+	}
+	return log.Info("Stopped serving models")
+}
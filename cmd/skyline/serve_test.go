@@ -0,0 +1,86 @@
+package skyline
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/profile"
+)
+
+func TestNewServeHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        Options
+		concurrency int
+		wantError   string
+	}{
+		{"valid", Options{}, 1, ""},
+		{"no concurrency", Options{}, 0, "concurrency"},
+		{"user", Options{User: "mona"}, 1, "each request names"},
+		{"output", Options{Output: "skyline.stl"}, 1, "each request names"},
+		{"split", Options{SplitYears: true}, 1, "split"},
+		{"profiled", Options{Profile: profile.ModeCPU}, 1, "profiled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Client = github.NewClient(compareAPI)
+			_, err := NewServeHandler(tt.opts, tt.concurrency)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("NewServeHandler() error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("NewServeHandler() error = %v, want one mentioning %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestServeHandler(t *testing.T) {
+	handler, err := NewServeHandler(Options{Client: github.NewClient(compareAPI)}, 1)
+	if err != nil {
+		t.Fatalf("NewServeHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		target          string
+		wantStatus      int
+		wantType        string
+		wantDisposition string
+		wantBody        string
+	}{
+		{"page", "/", http.StatusOK, "text/html; charset=utf-8", "", `<option selected>stl</option>`},
+		{"model", "/model?user=mona&year=2024", http.StatusOK, "", `attachment; filename=mona-2024-github-skyline.stl`, ""},
+		{"format", "/model?user=mona&year=2023-2024&format=svg", http.StatusOK, "image/svg+xml", `attachment; filename=mona-2023-24-github-skyline.svg`, "<svg"},
+		{"missing user", "/model?year=2024", http.StatusBadRequest, "", "", "not a GitHub username"},
+		{"path in user", "/model?user=../mona&year=2024", http.StatusBadRequest, "", "", "not a GitHub username"},
+		{"invalid year", "/model?user=mona&year=1999", http.StatusBadRequest, "", "", "invalid year range"},
+		{"unknown format", "/model?user=mona&year=2024&format=obj", http.StatusBadRequest, "", "", "unsupported output format"},
+		{"unknown user", "/model?user=nobody&year=2024", http.StatusNotFound, "", "", "nobody"},
+		{"unknown path", "/missing", http.StatusNotFound, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d: %s", tt.target, rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantType != "" && rec.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("GET %s Content-Type = %q, want %q", tt.target, rec.Header().Get("Content-Type"), tt.wantType)
+			}
+			if got := rec.Header().Get("Content-Disposition"); got != tt.wantDisposition {
+				t.Errorf("GET %s Content-Disposition = %q, want %q", tt.target, got, tt.wantDisposition)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("GET %s body does not contain %q", tt.target, tt.wantBody)
+			}
+		})
+	}
+}
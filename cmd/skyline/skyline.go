@@ -3,100 +3,1066 @@
 package skyline
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
+	"image"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/dataset"
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/github"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/profile"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/stats"
 	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/transform"
 	"github.com/github/gh-skyline/internal/types"
 	"github.com/github/gh-skyline/internal/utils"
 )
 
 // GitHubClientInterface defines the methods for interacting with GitHub API
 type GitHubClientInterface interface {
-	GetAuthenticatedUser() (string, error)
-	GetUserJoinYear(username string) (int, error)
-	FetchContributions(username string, year int) (*types.ContributionsResponse, error)
+	GetAuthenticatedUser(ctx context.Context) (string, error)
+	GetUserJoinYear(ctx context.Context, username string) (int, error)
+	FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error)
 }
 
-// GenerateSkyline creates a 3D model with ASCII art preview of GitHub contributions for the specified year range, or "full lifetime" of the user
-func GenerateSkyline(startYear, endYear int, targetUser string, full bool, output string, artOnly bool) error {
+// ViewFunc shows a model built in memory, returning once it is no longer shown.
+type ViewFunc func(ctx context.Context, model *types.Model) error
+
+// Options configures a skyline generation run.
+type Options struct {
+	StartYear      int                       // First year to include
+	EndYear        int                       // Last year to include
+	From           time.Time                 // First day to include, within StartYear, zero to start with the year
+	To             time.Time                 // Last day to include, within EndYear, zero to end with the year
+	TimeZone       *time.Location            // Time zone contributions are counted on the days of, nil for UTC as GitHub does by default
+	User           string                    // Target GitHub user, defaults to the authenticated user
+	Full           bool                      // Generate from the user's join year to the current year
+	SkipEmptyYears bool                      // Leave years without contributions out of the preview and model
+	Output         string                    // Output file path, generated from user and years when empty
+	ArtOnly        bool                      // Only print the ASCII preview
+	Smooth         int                       // Moving average window in days applied to the counts, 0 to disable
+	CapPercentile  float64                   // Percentile of the active days' counts that higher counts are clamped to, 0 to disable
+	WeekStart      time.Weekday              // First day of each week's column, Sunday like GitHub's calendar by default
+	Format         stl.Format                // Output file format
+	Fit            stl.Bed                   // Print bed to scale the model to, zero to keep its size
+	Unit           types.Unit                // Unit of the exported model, defaults to millimeters
+	Split          bool                      // Write each part of the model to its own file
+	SplitYears     bool                      // Write each year to its own file, next to an index of the files
+	Mirror         bool                      // Mirror the model left to right, for use as a stamp or mold master
+	Repair         bool                      // Repair the model's meshes before writing
+	Decimate       float64                   // Fraction of the model's triangles to simplify it down to, zero to keep them all
+	Profile        profile.Mode              // Runtime profile recorded while the model is generated, next to the model file
+	QR             bool                      // Emboss a QR code linking to the user's profile, unless Geometry.QRCode is set
+	Metadata       []types.Metadata          // How the model was generated, such as the tool version and flag settings, written into the file
+	Cache          *cache.Cache              // Fetched contributions are reused from and added to, nil to always fetch them
+	Client         *github.Client            // Client to fetch with, created by github.InitializeGitHubClient when nil
+	Offline        bool                      // Generate from the cache alone, without calling the GitHub API
+	ExportData     string                    // JSON or CSV file the per-day counts are written to, empty to not write them
+	Input          string                    // JSON or CSV file of per-day counts to generate from in place of the GitHub API, empty to fetch them
+	Org            string                    // Organization whose repositories' commits are counted in place of a user's contributions
+	Team           []string                  // Users whose contributions are summed into one model in place of User's, named together on it
+	MergeUsers     []string                  // Accounts of one user, oldest first, whose contributions are summed into one history named after the last
+	FilterOrg      string                    // Organization to count only the contributions made in, empty for all
+	Types          []github.ContributionType // Kinds of contributions to count, empty for the contribution calendar's totals
+	PublicOnly     bool                      // Count only contributions to public repositories, listed by type
+	IncludePrivate bool                      // Count private contributions as the profile does, reporting those the token cannot see
+	StatsOut       io.Writer                 // Where to print statistics of the contributions in place of the preview and model, nil to generate them
+	View           ViewFunc                  // Shows the model built in memory in place of writing it, nil to write it
+	ResultOut      io.Writer                 // Where to write a JSON Result describing the model in place of the preview, nil to print the preview
+	PathsOut       io.Writer                 // Where to print only the paths of the files written, one per line, in place of the preview, nil to print the preview
+	ModelOut       io.Writer                 // Where to stream the model as binary STL in place of writing it to Output, nil to write the file
+	PreviewOut     io.Writer                 // Where to print the ASCII preview, nil for standard output
+	PreviewStyle   ascii.Style               // Colors of the ASCII preview, plain when zero
+	Open           func(path string) error   // Opens the model file once it is written, such as in the default application for its format, nil to leave it
+
+	Geometry geometry.Config // Model measurements
+	Render   render.Options  // Settings for raster image formats
+
+	collect   func(name string, years [][][]types.ContributionDay) // Receives the fetched contributions in place of the preview and model, for a comparison
+	noPreview bool                                                 // Leave out the ASCII preview, for models generated alongside others
+	result    *Result                                              // Receives the description of the model generated, for ResultOut or PathsOut
+	started   time.Time                                            // When generation started, timing the result
+}
+
+// Limits on fetching a range of years.
+const (
+	maxConcurrentFetches = 4 // Requests in flight at once, few enough to stay clear of GitHub's secondary rate limit on concurrent requests
+	maxYearsPerFetch     = 5 // Years asked for in one request, few enough that a busy account's request finishes within GitHub's query timeout
+)
+
+// avatarSize is the size in pixels of the avatar fetched for the lithophane
+// panel, a little finer than the panel can print.
+const avatarSize = 256
+
+// ProfileURL returns the address of a user's profile on the GitHub host the
+// CLI is authenticated with.
+func ProfileURL(username string) string {
+	hostname, _ := auth.DefaultHost()
+	return fmt.Sprintf("https://%s/%s", hostname, username)
+}
+
+// GenerateSkyline creates a 3D model with ASCII art preview of GitHub contributions for the specified year range, or "full lifetime" of the user.
+// Canceling ctx stops in-flight API requests and model file writes.
+func GenerateSkyline(ctx context.Context, opts Options) error {
+	switch {
+	case opts.ResultOut != nil:
+		return generateResult(ctx, opts)
+	case opts.PathsOut != nil:
+		return generatePaths(ctx, opts)
+	case opts.ModelOut != nil:
+		return streamModel(ctx, opts)
+	}
+	return generateSkyline(ctx, opts)
+}
+
+// generateSkyline generates the skyline GenerateSkyline describes.
+func generateSkyline(ctx context.Context, opts Options) error {
 	log := logger.GetLogger()
+	startYear, endYear := opts.StartYear, opts.EndYear
+	targetUser, artOnly := opts.User, opts.ArtOnly
 
-	client, err := github.InitializeGitHubClient()
-	if err != nil {
-		return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+	if opts.Org != "" && (opts.Input != "" || opts.Offline) {
+		return errors.New(errors.ValidationError, "an organization's commits can only be fetched from the GitHub API", nil)
+	}
+	if opts.FilterOrg != "" && (opts.Org != "" || opts.Input != "" || opts.Offline) {
+		return errors.New(errors.ValidationError, "contributions can only be filtered by organization when fetching a user's from the GitHub API", nil)
+	}
+	if len(opts.Types) > 0 && (opts.Org != "" || opts.Input != "" || opts.Offline) {
+		return errors.New(errors.ValidationError, "contributions can only be counted by type when fetching a user's from the GitHub API", nil)
+	}
+	if err := validatePrivacy(opts); err != nil {
+		return err
+	}
+	if err := validateWindow(opts); err != nil {
+		return err
+	}
+	if opts.TimeZone != nil && (opts.Input != "" || opts.Offline) {
+		return errors.New(errors.ValidationError, "contributions can only be counted in another time zone when fetching them from the GitHub API", nil)
+	}
+	if err := validateTeam(opts); err != nil {
+		return err
+	}
+	if err := validateMerge(opts); err != nil {
+		return err
+	}
+	if err := validateOpen(opts); err != nil {
+		return err
+	}
+	if opts.Input != "" {
+		return generateFromInput(ctx, opts)
+	}
+	if opts.Offline {
+		return generateOffline(ctx, opts)
+	}
+
+	var err error
+	client := opts.Client
+	if client == nil {
+		if client, err = github.InitializeGitHubClient(); err != nil {
+			return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		}
+	}
+	// Cached contributions were counted on the days of UTC
+	if opts.TimeZone != nil {
+		client.SetTimeZone(opts.TimeZone)
+		opts.Cache = nil
+	}
+	if opts.Org != "" {
+		return generateOrganization(ctx, opts, client)
+	}
+
+	filter := github.ContributionFilter{PublicOnly: opts.PublicOnly}
+	if opts.FilterOrg != "" {
+		if filter.OrganizationID, err = client.GetOrganizationID(ctx, opts.FilterOrg); err != nil {
+			return errors.New(errors.NetworkError, "failed to look up the organization to filter by", err)
+		}
+		if err := log.Debug("Counting only contributions made in %s", opts.FilterOrg); err != nil {
+			return err
+		}
+	}
+	if usernames, name := summedUsers(opts); len(usernames) > 0 {
+		return generateSummed(ctx, opts, client, filter, usernames, name)
 	}
 
 	if targetUser == "" {
 		if err := log.Debug("No target user specified, using authenticated user"); err != nil {
 			return err
 		}
-		username, err := client.GetAuthenticatedUser()
+		username, err := client.GetAuthenticatedUser(ctx)
 		if err != nil {
 			return errors.New(errors.NetworkError, "failed to get authenticated user", err)
 		}
 		targetUser = username
 	}
 
-	if full {
-		joinYear, err := client.GetUserJoinYear(targetUser)
+	if opts.QR && opts.Geometry.QRCode == "" {
+		opts.Geometry.QRCode = ProfileURL(targetUser)
+	}
+
+	var avatar image.Image
+	if opts.Geometry.Avatar && !artOnly {
+		avatar, err = client.FetchAvatar(ctx, targetUser, avatarSize)
 		if err != nil {
-			return errors.New(errors.NetworkError, "failed to get user join year", err)
+			return accountError(ctx, client, targetUser, errors.New(errors.NetworkError, "failed to fetch avatar", err))
+		}
+	}
+
+	if opts.Full {
+		joinYear, err := client.GetUserJoinYear(ctx, targetUser)
+		if err != nil {
+			return accountError(ctx, client, targetUser, errors.New(errors.NetworkError, "failed to get user join year", err))
 		}
 		startYear = joinYear
 		endYear = time.Now().Year()
 	}
 
-	var allContributions [][][]types.ContributionDay
-	for year := startYear; year <= endYear; year++ {
-		contributions, err := fetchContributionData(client, targetUser, year)
+	years, err := fetchUserYears(ctx, client, opts, targetUser, startYear, endYear, filter)
+	if err != nil {
+		return accountError(ctx, client, targetUser, err)
+	}
+	return generateFromYears(ctx, opts, targetUser, startYear, endYear, years, avatar)
+}
+
+// accountError explains a failure to fetch a user's contributions that GitHub
+// found no user for: the login either belongs to an organization, which has no
+// contribution calendar but whose repositories' commits can be counted, or to
+// no account at all. Any other failure is returned as it is.
+func accountError(ctx context.Context, client *github.Client, username string, err error) error {
+	if !stderrors.Is(err, &errors.SkylineError{Type: errors.NotFoundError}) {
+		return err
+	}
+	kind, lookupErr := client.GetAccountKind(ctx, username)
+	if lookupErr != nil {
+		return err
+	}
+	switch kind {
+	case github.AccountOrganization:
+		return errors.New(errors.ValidationError, fmt.Sprintf("%s is an organization, not a user, build its skyline with --org %s", username, username), nil)
+	case github.AccountNone:
+		return errors.New(errors.NotFoundError, fmt.Sprintf("no GitHub user is named %s, check the spelling of the login", username), nil)
+	}
+	return err
+}
+
+// validateOpen checks that the model opened once it is written is a single
+// file: not split into several, nor streamed or left out.
+func validateOpen(opts Options) error {
+	if opts.Open == nil {
+		return nil
+	}
+	switch {
+	case opts.ArtOnly:
+		return errors.New(errors.ValidationError, "only the ASCII preview writes no model file to open", nil)
+	case opts.ModelOut != nil:
+		return errors.New(errors.ValidationError, "a streamed model is written to no file to open", nil)
+	case opts.Split || opts.SplitYears:
+		return errors.New(errors.ValidationError, "a model split into several files cannot be opened, open the files needed instead", nil)
+	}
+	return nil
+}
+
+// validateTeam checks that a team's skyline can be generated with the other
+// options: its contributions come from GitHub or the cache, and it has no
+// single profile to show the avatar of or link to.
+func validateTeam(opts Options) error {
+	if len(opts.Team) == 0 {
+		return nil
+	}
+	if err := validateUsernames(opts.Team, "team"); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.User != "":
+		return errors.New(errors.ValidationError, "a team's skyline cannot also be of a single user", nil)
+	case opts.Org != "":
+		return errors.New(errors.ValidationError, "a team's skyline cannot also be of an organization", nil)
+	case opts.Input != "":
+		return errors.New(errors.ValidationError, "a data file holds the contributions of a single user, not a team", nil)
+	case opts.Geometry.Avatar && !opts.ArtOnly:
+		return errors.New(errors.ValidationError, "a team's skyline cannot have an avatar panel", nil)
+	case opts.QR && opts.Geometry.QRCode == "":
+		return errors.New(errors.ValidationError, "a team has no single profile for the QR code to link to, give its URL", nil)
+	}
+	return nil
+}
+
+// validateMerge checks that the accounts of a user can be merged into one
+// history with the other options. The history is the last account's, so it
+// can show that account's avatar and link to its profile.
+func validateMerge(opts Options) error {
+	if len(opts.MergeUsers) == 0 {
+		return nil
+	}
+	if len(opts.MergeUsers) < 2 {
+		return errors.New(errors.ValidationError, "merging accounts needs at least two of them", nil)
+	}
+	if err := validateUsernames(opts.MergeUsers, "accounts to merge"); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.User != "" || len(opts.Team) > 0:
+		return errors.New(errors.ValidationError, "merged accounts cannot also be given as users", nil)
+	case opts.Org != "":
+		return errors.New(errors.ValidationError, "merged accounts cannot also be of an organization", nil)
+	case opts.Input != "":
+		return errors.New(errors.ValidationError, "a data file holds the contributions of a single account, not merged ones", nil)
+	}
+	return nil
+}
+
+// validateUsernames checks that none of a list of usernames is empty or
+// listed twice, ignoring case as GitHub does.
+func validateUsernames(usernames []string, list string) error {
+	seen := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		if username == "" {
+			return errors.New(errors.ValidationError, fmt.Sprintf("usernames in the %s cannot be empty", list), nil)
+		}
+		if seen[strings.ToLower(username)] {
+			return errors.New(errors.ValidationError, fmt.Sprintf("%s is listed twice in the %s", username, list), nil)
+		}
+		seen[strings.ToLower(username)] = true
+	}
+	return nil
+}
+
+// summedUsers returns the users whose contributions are summed into one
+// skyline, a team's or the merged accounts of one user, and the name the
+// skyline is rendered and saved under, or no users for any other skyline.
+func summedUsers(opts Options) ([]string, string) {
+	if len(opts.MergeUsers) > 0 {
+		return opts.MergeUsers, opts.MergeUsers[len(opts.MergeUsers)-1]
+	}
+	if len(opts.Team) > 0 {
+		return opts.Team, teamName(opts.Team)
+	}
+	return nil, ""
+}
+
+// teamName returns the name a team's skyline is rendered and saved under.
+func teamName(team []string) string {
+	return strings.Join(team, "+")
+}
+
+// sumTeamYears adds up the contributions of each member of a team, or each
+// account of a user, each a grid per year, year by year.
+func sumTeamYears(members [][][][]types.ContributionDay) [][][]types.ContributionDay {
+	years := make([][][]types.ContributionDay, len(members[0]))
+	for i := range years {
+		grids := make([][][]types.ContributionDay, len(members))
+		for m, member := range members {
+			grids[m] = member[i]
+		}
+		years[i] = transform.Sum(grids...)
+	}
+	return years
+}
+
+// generateSummed generates the skyline of the summed contributions of several
+// users under name, and the full range from the year the first of them
+// joined. The avatar and QR code, which validateTeam only lets a team have
+// when its URL is given, are those of name.
+func generateSummed(ctx context.Context, opts Options, client *github.Client, filter github.ContributionFilter, usernames []string, name string) error {
+	log := logger.GetLogger()
+	startYear, endYear := opts.StartYear, opts.EndYear
+	if opts.Full {
+		startYear = time.Now().Year()
+		for _, username := range usernames {
+			joinYear, err := client.GetUserJoinYear(ctx, username)
+			if err != nil {
+				return accountError(ctx, client, username, errors.New(errors.NetworkError, fmt.Sprintf("failed to get join year of %s", username), err))
+			}
+			startYear = min(startYear, joinYear)
+		}
+		endYear = time.Now().Year()
+	}
+
+	if opts.QR && opts.Geometry.QRCode == "" {
+		opts.Geometry.QRCode = ProfileURL(name)
+	}
+	var avatar image.Image
+	if opts.Geometry.Avatar && !opts.ArtOnly {
+		var err error
+		if avatar, err = client.FetchAvatar(ctx, name, avatarSize); err != nil {
+			return accountError(ctx, client, name, errors.New(errors.NetworkError, "failed to fetch avatar", err))
+		}
+	}
+
+	members := make([][][][]types.ContributionDay, len(usernames))
+	for i, username := range usernames {
+		if err := log.Debug("Fetching contributions of %s (%d of %d)", username, i+1, len(usernames)); err != nil {
+			return err
+		}
+		years, err := fetchUserYears(ctx, client, opts, username, startYear, endYear, filter)
+		if err != nil {
+			return accountError(ctx, client, username, err)
+		}
+		members[i] = years
+	}
+	return generateFromYears(ctx, opts, name, startYear, endYear, sumTeamYears(members), avatar)
+}
+
+// generateOrganization generates the skyline of the commits made to an
+// organization's repositories, and the full range from the year the
+// organization was created. Organizations' commits are not cached.
+func generateOrganization(ctx context.Context, opts Options, client *github.Client) error {
+	switch {
+	case opts.User != "":
+		return errors.New(errors.ValidationError, "an organization's skyline cannot also be of a user", nil)
+	case opts.Geometry.Avatar && !opts.ArtOnly:
+		return errors.New(errors.ValidationError, "an organization's skyline cannot have an avatar panel", nil)
+	}
+
+	startYear, endYear := opts.StartYear, opts.EndYear
+	if opts.Full {
+		createdYear, err := client.GetOrganizationCreatedYear(ctx, opts.Org)
 		if err != nil {
+			return errors.New(errors.NetworkError, "failed to get organization creation year", err)
+		}
+		startYear = createdYear
+		endYear = time.Now().Year()
+	}
+
+	bar := progress.FromContext(ctx)
+	bar.Start(fmt.Sprintf("Fetching the commits of %s", opts.Org), "", 0)
+	counts, err := client.FetchOrganizationContributions(ctx, opts.Org, startYear, endYear)
+	bar.Finish()
+	if err != nil {
+		return fmt.Errorf("failed to fetch organization commits: %w", err)
+	}
+	var years [][][]types.ContributionDay
+	for year := startYear; year <= endYear; year++ {
+		years = append(years, transform.Calendar(year, counts))
+	}
+	if opts.QR && opts.Geometry.QRCode == "" {
+		opts.Geometry.QRCode = ProfileURL(opts.Org)
+	}
+	return generateFromYears(ctx, opts, opts.Org, startYear, endYear, years, nil)
+}
+
+// generateOffline generates the skyline from the contributions in the cache,
+// however old, failing when any year is missing rather than fetching it.
+func generateOffline(ctx context.Context, opts Options) error {
+	switch {
+	case opts.Cache == nil:
+		return errors.New(errors.ValidationError, "offline mode needs the contribution cache", nil)
+	case opts.User == "" && len(opts.Team) == 0 && len(opts.MergeUsers) == 0:
+		return errors.New(errors.ValidationError, "offline mode needs a user, as the authenticated user cannot be looked up", nil)
+	case opts.Full:
+		return errors.New(errors.ValidationError, "offline mode cannot look up the year the user joined, give the years instead of the full range", nil)
+	case opts.Geometry.Avatar && !opts.ArtOnly:
+		return errors.New(errors.ValidationError, "offline mode cannot download the user's avatar", nil)
+	}
+
+	if usernames, name := summedUsers(opts); len(usernames) > 0 {
+		members := make([][][][]types.ContributionDay, len(usernames))
+		for i, username := range usernames {
+			years, err := loadCachedYears(opts.Cache, username, opts.StartYear, opts.EndYear)
+			if err != nil {
+				return err
+			}
+			members[i] = years
+		}
+		if opts.QR && opts.Geometry.QRCode == "" {
+			opts.Geometry.QRCode = ProfileURL(name)
+		}
+		return generateFromYears(ctx, opts, name, opts.StartYear, opts.EndYear, sumTeamYears(members), nil)
+	}
+
+	years, err := loadCachedYears(opts.Cache, opts.User, opts.StartYear, opts.EndYear)
+	if err != nil {
+		return err
+	}
+	if opts.QR && opts.Geometry.QRCode == "" {
+		opts.Geometry.QRCode = ProfileURL(opts.User)
+	}
+	return generateFromYears(ctx, opts, opts.User, opts.StartYear, opts.EndYear, years, nil)
+}
+
+// generateFromInput generates the skyline from the counts in a data file,
+// without calling the GitHub API. The user defaults to the one the file names,
+// and the full range to the years the file covers.
+func generateFromInput(ctx context.Context, opts Options) error {
+	data, err := dataset.Read(opts.Input)
+	if err != nil {
+		return err
+	}
+
+	username := opts.User
+	if username == "" {
+		username = data.Username
+	}
+	switch {
+	case username == "":
+		return errors.New(errors.ValidationError, fmt.Sprintf("data file %s does not name the user, give one with --user", opts.Input), nil)
+	case opts.Geometry.Avatar && !opts.ArtOnly:
+		return errors.New(errors.ValidationError, "the user's avatar cannot be downloaded when generating from a data file", nil)
+	}
+
+	startYear, endYear := opts.StartYear, opts.EndYear
+	if opts.Full {
+		startYear, endYear = data.YearSpan()
+	}
+	years, err := data.Years(startYear, endYear)
+	if err != nil {
+		return err
+	}
+	if opts.QR && opts.Geometry.QRCode == "" {
+		opts.Geometry.QRCode = ProfileURL(username)
+	}
+	return generateFromYears(ctx, opts, username, startYear, endYear, years, nil)
+}
+
+// generateFromYears previews the contributions of each year and generates the
+// model file from them, cropped to the days from opts.From to opts.To when
+// set. Days listed in the wrong year are moved to their own first, so the
+// years meet without overlapping.
+func generateFromYears(ctx context.Context, opts Options, targetUser string, startYear, endYear int, years [][][]types.ContributionDay, avatar image.Image) error {
+	log := logger.GetLogger()
+	fetched := time.Now()
+	artOnly := opts.ArtOnly
+
+	years = transform.Stitch(years, startYear)
+
+	// A window of months labels the model and names its file in place of the years
+	var period string
+	if !opts.From.IsZero() {
+		for i := range years {
+			years[i] = transform.Window(years[i], opts.From, opts.To)
+		}
+		period = utils.FormatMonthRange(opts.From, opts.To)
+	}
+
+	if opts.ExportData != "" {
+		if err := dataset.Write(opts.ExportData, targetUser, years); err != nil {
 			return err
 		}
+		if err := log.Info("Contribution data written to: %s", opts.ExportData); err != nil {
+			return err
+		}
+	}
+
+	// The preview names the kinds of contributions counted, when not all are
+	previewName := targetUser
+	if len(opts.Types) > 0 {
+		previewName = fmt.Sprintf("%s (%s)", targetUser, typesLabel(opts.Types))
+	}
+
+	if opts.StatsOut != nil {
+		if period == "" {
+			period = utils.FormatYearRange(startYear, endYear)
+		}
+		return writeStats(opts.StatsOut, previewName, period, stats.Compute(years))
+	}
+	if opts.collect != nil {
+		opts.collect(targetUser, years)
+		return nil
+	}
+
+	// Truncated years end with the current week, in the preview and on the model
+	truncate := opts.Geometry.FutureDays == geometry.FutureTruncate
+	if truncate {
+		now := time.Now()
+		for i := range years {
+			years[i] = transform.Until(years[i], now)
+		}
+	}
+
+	// Years without contributions would only add bare base to print
+	yearNumbers := make([]int, len(years))
+	for i := range years {
+		yearNumbers[i] = startYear + i
+	}
+	if opts.SkipEmptyYears {
+		skipped := len(years)
+		years, yearNumbers = skipEmptyYears(years, yearNumbers)
+		if len(years) == 0 {
+			return errors.New(errors.ValidationError, fmt.Sprintf("%s has no contributions in %d-%d", targetUser, startYear, endYear), nil)
+		}
+		if skipped -= len(years); skipped > 0 {
+			if err := log.Info("Leaving out %d years without contributions", skipped); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Outlying days are clamped before the towers are scaled to the busiest day
+	limit := 0
+	if opts.CapPercentile > 0 {
+		limit = transform.Percentile(years, opts.CapPercentile)
+		if err := log.Info("Capping daily contributions at %d, the %gth percentile of active days", limit, opts.CapPercentile); err != nil {
+			return err
+		}
+	}
+
+	var allContributions, rawContributions [][][]types.ContributionDay
+	for i, contributions := range years {
+		year := yearNumbers[i]
+		if opts.WeekStart != transform.DefaultWeekStart {
+			contributions = transform.MergeEndWeeks(transform.WeekStart(contributions, opts.WeekStart), geometry.GridSize)
+			if len(contributions) > geometry.GridSize && !artOnly {
+				return errors.New(errors.ValidationError, fmt.Sprintf("starting weeks on %s spreads %d over %d weeks, more than the model's %d", opts.WeekStart, year, len(contributions), geometry.GridSize), nil)
+			}
+		}
+		rawContributions = append(rawContributions, contributions)
+		if opts.Geometry.Weekdays {
+			contributions = transform.Weekdays(contributions)
+		}
+		if limit > 0 {
+			contributions = transform.Cap(contributions, limit)
+		}
+		if opts.Smooth > 1 {
+			contributions = transform.Smooth(contributions, opts.Smooth)
+		}
 		allContributions = append(allContributions, contributions)
 
 		// Generate ASCII art for each year
-		asciiArt, err := ascii.GenerateASCII(contributions, targetUser, year, (year == startYear) && !artOnly, !artOnly)
+		asciiArt, err := ascii.GenerateASCIIStyled(contributions, previewName, yearPeriod(opts, year), (i == 0) && !artOnly, !artOnly, opts.PreviewStyle)
 		if err != nil {
 			if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
 				return warnErr
 			}
-		} else {
-			fmt.Println(asciiArt)
+		} else if !opts.noPreview && opts.result == nil {
+			previewOut := opts.PreviewOut
+			if previewOut == nil {
+				previewOut = os.Stdout
+			}
+			fmt.Fprintln(previewOut, asciiArt)
 		}
 	}
 
 	if !artOnly {
+		// The base ends with the longest truncated year, when all of them are cut short
+		if truncate {
+			weeks := 0
+			for _, contributions := range allContributions {
+				weeks = max(weeks, len(contributions))
+			}
+			if weeks < geometry.GridSize {
+				opts.Geometry.Weeks = max(weeks, 1)
+			}
+		}
+
+		format := opts.Format
+		if format == "" {
+			format = stl.FormatSTL
+		}
+
 		// Generate filename
-		outputPath := utils.GenerateOutputFilename(targetUser, startYear, endYear, output)
+		outputPath := utils.GenerateOutputFilenameWithExt(targetUser, startYear, endYear, opts.Output, format.Extension())
+		if period != "" {
+			outputPath = utils.GenerateOutputFilenameForPeriod(targetUser, period, opts.Output, format.Extension())
+		}
+
+		// Statistics describe the actual contributions, before any smoothing
+		summary := stats.Compute(rawContributions)
+		var yearStats []stats.Summary
+		if opts.SplitYears {
+			for _, year := range rawContributions {
+				yearStats = append(yearStats, stats.Compute([][][]types.ContributionDay{year}))
+			}
+		}
+
+		modelOpts := stl.Options{
+			OutputPath: outputPath,
+			Format:     format,
+			Username:   targetUser,
+			StartYear:  startYear,
+			EndYear:    endYear,
+			Years:      yearNumbers,
+			Period:     period,
+			Fit:        opts.Fit,
+			Unit:       opts.Unit,
+			SplitParts: opts.Split,
+			SplitYears: opts.SplitYears,
+			Mirror:     opts.Mirror,
+			Repair:     opts.Repair,
+			Decimate:   opts.Decimate,
+			Stats:      &summary,
+			YearStats:  yearStats,
+			Avatar:     avatar,
+			Metadata:   opts.Metadata,
+			Geometry:   opts.Geometry,
+			Render:     opts.Render,
+		}
+		if opts.View != nil {
+			model, err := stl.BuildModel(ctx, allContributions, modelOpts)
+			if err != nil {
+				return err
+			}
+			return opts.View(ctx, model)
+		}
+		if opts.ModelOut != nil {
+			model, err := stl.BuildModel(ctx, allContributions, modelOpts)
+			if err != nil {
+				return err
+			}
+			if err := stl.WriteSTLTo(ctx, opts.ModelOut, model); err != nil {
+				return err
+			}
+			return log.Info("Model streamed successfully as binary STL")
+		}
+
+		profilePath := opts.Profile.Path(outputPath)
+		stopProfile, err := profile.Start(opts.Profile, profilePath)
+		if err != nil {
+			return err
+		}
+
+		// Generate the model file
+		generating := time.Now()
+		generated, genErr := stl.GenerateModelResult(ctx, allContributions, modelOpts)
+		if err := stopProfile(); err != nil {
+			if genErr != nil {
+				return genErr
+			}
+			return err
+		}
+		if opts.Profile != profile.ModeNone {
+			if err := log.Info("Wrote %s profile to %s", opts.Profile, profilePath); err != nil {
+				return err
+			}
+		}
+		if genErr == nil && opts.Open != nil {
+			genErr = opts.Open(generated.Files[0])
+		}
+		if genErr != nil || opts.result == nil {
+			return genErr
+		}
 
-		// Generate the STL file
-		if len(allContributions) == 1 {
-			return stl.GenerateSTL(allContributions[0], outputPath, targetUser, startYear)
+		if period == "" {
+			period = utils.FormatYearRange(startYear, endYear)
+		}
+		files, err := resultFiles(generated.Files)
+		if err != nil {
+			return err
 		}
-		return stl.GenerateSTLRange(allContributions, outputPath, targetUser, startYear, endYear)
+		*opts.result = Result{
+			User:      targetUser,
+			Period:    period,
+			Format:    format,
+			Files:     files,
+			Triangles: generated.Triangles,
+			Stats:     newResultStats(summary),
+			Timing: ResultTiming{
+				Fetch:    fetched.Sub(opts.started).Milliseconds(),
+				Generate: time.Since(generating).Milliseconds(),
+			},
+		}
+		return nil
 	}
 
 	return nil
 }
 
-// fetchContributionData retrieves and formats the contribution data for the specified year.
-func fetchContributionData(client *github.Client, username string, year int) ([][]types.ContributionDay, error) {
-	response, err := client.FetchContributions(username, year)
+// skipEmptyYears leaves out the years without contributions, returning the
+// remaining grids along with their years.
+func skipEmptyYears(years [][][]types.ContributionDay, yearNumbers []int) ([][][]types.ContributionDay, []int) {
+	var kept [][][]types.ContributionDay
+	var keptNumbers []int
+	for i, year := range years {
+		if stats.Compute([][][]types.ContributionDay{year}).Total > 0 {
+			kept = append(kept, year)
+			keptNumbers = append(keptNumbers, yearNumbers[i])
+		}
+	}
+	return kept, keptNumbers
+}
+
+// validateWindow checks that the days a skyline is cropped to, if any, lie
+// within its years. The full range runs to whichever year a user joined, so it
+// cannot be cropped.
+func validateWindow(opts Options) error {
+	if opts.From.IsZero() && opts.To.IsZero() {
+		return nil
+	}
+	switch {
+	case opts.Full:
+		return errors.New(errors.ValidationError, "the full range cannot be limited to a range of months", nil)
+	case opts.From.IsZero() || opts.To.IsZero() || opts.To.Before(opts.From):
+		return errors.New(errors.ValidationError, "a range of months needs a first day on or before its last", nil)
+	case opts.From.Year() != opts.StartYear || opts.To.Year() != opts.EndYear:
+		return errors.New(errors.ValidationError, fmt.Sprintf("months %s must run from %d to %d", utils.FormatMonthRange(opts.From, opts.To), opts.StartYear, opts.EndYear), nil)
+	}
+	return nil
+}
+
+// yearPeriod returns the label of the part of year within the window from
+// opts.From to opts.To, or the year itself when there is no window.
+func yearPeriod(opts Options, year int) string {
+	if opts.From.IsZero() {
+		return fmt.Sprintf("%d", year)
+	}
+	from, to := opts.From, opts.To
+	if from.Year() < year {
+		from = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.Year() > year {
+		to = time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+	return utils.FormatMonthRange(from, to)
+}
+
+// validatePrivacy checks that the private contributions settings can be
+// applied. Only contributions listed by type tell which repository they were
+// made in, and only the contribution calendar counts those the token cannot
+// see.
+func validatePrivacy(opts Options) error {
+	switch {
+	case opts.PublicOnly && opts.IncludePrivate:
+		return errors.New(errors.ValidationError, "private contributions cannot be both included and left out", nil)
+	case (opts.PublicOnly || opts.IncludePrivate) && (opts.Org != "" || opts.Input != "" || opts.Offline):
+		return errors.New(errors.ValidationError, "private contributions can only be told apart when fetching a user's from the GitHub API", nil)
+	case opts.IncludePrivate && len(opts.Types) > 0:
+		return errors.New(errors.ValidationError, "private contributions the token cannot see have no type, so they cannot be included when counting by type", nil)
+	}
+	return nil
+}
+
+// fetchUserYears retrieves the contributions of a user from startYear to
+// endYear, counting the kinds in opts.Types when given, or all kinds when only
+// public contributions are counted, and otherwise the contribution calendar's
+// totals.
+func fetchUserYears(ctx context.Context, client *github.Client, opts Options, username string, startYear, endYear int, filter github.ContributionFilter) ([][][]types.ContributionDay, error) {
+	kinds := opts.Types
+	if len(kinds) == 0 && opts.PublicOnly {
+		var err error
+		if kinds, err = github.ParseContributionTypes(github.ContributionTypes()); err != nil {
+			return nil, err
+		}
+	}
+	if len(kinds) == 0 && !opts.IncludePrivate {
+		return fetchYears(ctx, client, opts.Cache, username, startYear, endYear, filter)
+	}
+	if len(kinds) == 0 {
+		responses, err := fetchResponses(ctx, client, opts.Cache, username, startYear, endYear, filter)
+		if err != nil {
+			return nil, err
+		}
+		if err := reportRestricted(username, responses); err != nil {
+			return nil, err
+		}
+		return responseGrids(responses), nil
+	}
+
+	log := logger.GetLogger()
+	bar := progress.FromContext(ctx)
+	bar.Start(fmt.Sprintf("Counting %s contributions of %s", typesLabel(kinds), username), "years", endYear-startYear+1)
+	defer bar.Finish()
+	var years [][][]types.ContributionDay
+	for year := startYear; year <= endYear; year++ {
+		if err := log.Debug("Counting %s contributions of %s for %d", typesLabel(kinds), username, year); err != nil {
+			return nil, err
+		}
+		counts, err := client.FetchTypedContributions(ctx, username, year, kinds, filter)
+		if err != nil {
+			return nil, err
+		}
+		years = append(years, transform.Calendar(year, counts))
+		bar.Add(1)
+	}
+	return years, nil
+}
+
+// reportRestricted tells how many of a user's contributions counted in the
+// calendar were made in repositories the token cannot see, as GitHub only
+// counts those when the user shares their private contributions.
+func reportRestricted(username string, responses []*types.ContributionsResponse) error {
+	restricted := 0
+	for _, response := range responses {
+		restricted += response.User.ContributionsCollection.RestrictedContributionsCount
+	}
+	log := logger.GetLogger()
+	if restricted > 0 {
+		return log.Info("Including %d private contributions of %s to repositories the token cannot see, counted on their days without details", restricted, username)
+	}
+	return log.Info("No private contributions of %s hidden from the token are included: those are only counted when %s shares private contributions on their profile", username, username)
+}
+
+// typesLabel names the kinds of contributions counted, for previews and logs.
+func typesLabel(kinds []github.ContributionType) string {
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, ", ")
+}
+
+// fetchYears retrieves the contributions of every year from startYear to
+// endYear that filter lets through as fetchResponses does, converted to a
+// [week][day] grid per year.
+func fetchYears(ctx context.Context, client *github.Client, store *cache.Cache, username string, startYear, endYear int, filter github.ContributionFilter) ([][][]types.ContributionDay, error) {
+	responses, err := fetchResponses(ctx, client, store, username, startYear, endYear, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch contributions: %w", err)
+		return nil, err
+	}
+	return responseGrids(responses), nil
+}
+
+// fetchResponses retrieves the contributions of every year from startYear to
+// endYear that filter lets through and returns them in year order. Years found
+// in store are not fetched again, and those fetched are added to it, unless
+// they are filtered. The rest are fetched in batches of up to maxYearsPerFetch
+// consecutive years per request, up to maxConcurrentFetches requests at a
+// time. The first fetch to fail cancels the rest and its error is returned.
+func fetchResponses(ctx context.Context, client *github.Client, store *cache.Cache, username string, startYear, endYear int, filter github.ContributionFilter) ([]*types.ContributionsResponse, error) {
+	log := logger.GetLogger()
+	if filter != (github.ContributionFilter{}) {
+		store = nil
+	}
+	host, _ := auth.DefaultHost()
+	yearCount := max(endYear-startYear+1, 0)
+	responses := make([]*types.ContributionsResponse, yearCount)
+
+	// Batches of consecutive years missing from the cache
+	var batches [][2]int
+	for i := range responses {
+		if store != nil {
+			response, found, err := store.Get(host, username, startYear+i)
+			if err != nil {
+				if warnErr := log.Warning("Ignoring cached contributions: %v", err); warnErr != nil {
+					return nil, warnErr
+				}
+			}
+			if found {
+				if err := log.Debug("Using cached contributions for %d", startYear+i); err != nil {
+					return nil, err
+				}
+				responses[i] = response
+				continue
+			}
+		}
+		if n := len(batches); n > 0 && batches[n-1][1] == i-1 && i-batches[n-1][0] < maxYearsPerFetch {
+			batches[n-1][1] = i
+		} else {
+			batches = append(batches, [2]int{i, i})
+		}
+	}
+
+	// Years found in the cache count as fetched already
+	bar := progress.FromContext(ctx)
+	if len(batches) > 0 {
+		fetching := 0
+		for _, batch := range batches {
+			fetching += batch[1] - batch[0] + 1
+		}
+		bar.Start(fmt.Sprintf("Fetching contributions of %s", username), "years", yearCount)
+		bar.Add(yearCount - fetching)
+		defer bar.Finish()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for w := 0; w < min(maxConcurrentFetches, len(batches)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := int(next.Add(1) - 1); b < len(batches) && ctx.Err() == nil; b = int(next.Add(1) - 1) {
+				first, last := batches[b][0], batches[b][1]
+				fetched, err := client.FetchContributionsRange(ctx, username, startYear+first, startYear+last, filter)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to fetch contributions: %w", err)
+						cancel()
+					}
+					mu.Unlock()
+					return
+				}
+				copy(responses[first:], fetched)
+				bar.Add(last - first + 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// Canceled by the caller between fetches
+	if err := ctx.Err(); err != nil {
+		return nil, errors.New(errors.NetworkError, "fetching contributions canceled", err)
 	}
 
-	// Convert weeks data to 2D array for STL generation
+	if store != nil {
+		for _, batch := range batches {
+			for i := batch[0]; i <= batch[1]; i++ {
+				if err := store.Put(host, username, startYear+i, responses[i]); err != nil {
+					if warnErr := log.Warning("Failed to cache contributions: %v", err); warnErr != nil {
+						return nil, warnErr
+					}
+				}
+			}
+		}
+	}
+
+	return responses, nil
+}
+
+// loadCachedYears returns the contributions of every year from startYear to
+// endYear from store, however long ago they were fetched, or an error naming
+// the years it does not hold.
+func loadCachedYears(store *cache.Cache, username string, startYear, endYear int) ([][][]types.ContributionDay, error) {
+	host, _ := auth.DefaultHost()
+	var years [][][]types.ContributionDay
+	var missing []string
+	for year := startYear; year <= endYear; year++ {
+		response, found, err := store.GetStale(host, username, year)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			missing = append(missing, strconv.Itoa(year))
+			continue
+		}
+		years = append(years, contributionGrid(response))
+	}
+	if len(missing) > 0 {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("no cached contributions of %s for %s, run once without --offline to fetch them", username, strings.Join(missing, ", ")), nil)
+	}
+	return years, nil
+}
+
+// responseGrids converts the responses of a range of years to a [week][day]
+// grid per year.
+func responseGrids(responses []*types.ContributionsResponse) [][][]types.ContributionDay {
+	years := make([][][]types.ContributionDay, len(responses))
+	for i, response := range responses {
+		years[i] = contributionGrid(response)
+	}
+	return years
+}
+
+// contributionGrid converts the weeks of a contributions response to the
+// [week][day] grid the model is generated from.
+func contributionGrid(response *types.ContributionsResponse) [][]types.ContributionDay {
 	weeks := response.User.ContributionsCollection.ContributionCalendar.Weeks
-	contributionGrid := make([][]types.ContributionDay, len(weeks))
+	grid := make([][]types.ContributionDay, len(weeks))
 	for i, week := range weeks {
-		contributionGrid[i] = week.ContributionDays
+		grid[i] = week.ContributionDays
 	}
-
-	return contributionGrid, nil
+	return grid
 }
@@ -1,11 +1,28 @@
 package skyline
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/dataset"
 	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/testutil/fixtures"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/transform"
+	"github.com/github/gh-skyline/internal/types"
 )
 
 func TestGenerateSkyline(t *testing.T) {
@@ -72,10 +89,963 @@ func TestGenerateSkyline(t *testing.T) {
 				return github.NewClient(tt.mockClient), nil
 			}
 
-			err := GenerateSkyline(tt.startYear, tt.endYear, tt.targetUser, tt.full, "", false)
+			err := GenerateSkyline(context.Background(), Options{
+				StartYear: tt.startYear,
+				EndYear:   tt.endYear,
+				User:      tt.targetUser,
+				Full:      tt.full,
+				Output:    filepath.Join(t.TempDir(), "skyline.stl"),
+			})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GenerateSkyline() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestGenerateSkylineWeekdays(t *testing.T) {
+	sizes := make(map[bool]int64)
+	for _, weekdays := range []bool{false, true} {
+		opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: filepath.Join(t.TempDir(), "skyline.stl"), Geometry: geometry.DefaultConfig()}
+		opts.Geometry.Weekdays = weekdays
+		opts.Client = github.NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+		if err := GenerateSkyline(context.Background(), opts); err != nil {
+			t.Fatalf("GenerateSkyline() weekdays only = %t error = %v", weekdays, err)
+		}
+		info, err := os.Stat(opts.Output)
+		if err != nil {
+			t.Fatalf("model was not written: %v", err)
+		}
+		sizes[weekdays] = info.Size()
+	}
+	if sizes[true] >= sizes[false] {
+		t.Errorf("model without weekends is %d bytes, want fewer than the %d with their towers", sizes[true], sizes[false])
+	}
+}
+
+func TestGenerateSkylineWeekStart(t *testing.T) {
+	// 2012 is a leap year starting on a Sunday, spread over 54 weeks starting on Monday
+	dir := t.TempDir()
+	input := filepath.Join(dir, "data.json")
+	if err := dataset.Write(input, "testuser", [][][]types.ContributionDay{transform.Calendar(2012, map[string]int{"2012-01-01": 1, "2012-12-31": 2})}); err != nil {
+		t.Fatalf("dataset.Write() error = %v", err)
+	}
+
+	opts := Options{Input: input, StartYear: 2012, EndYear: 2012, Output: filepath.Join(dir, "skyline.stl"), Geometry: geometry.DefaultConfig(), WeekStart: time.Monday}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() with weeks starting on Monday in 2012 error = %v", err)
+	}
+	if _, err := os.Stat(opts.Output); err != nil {
+		t.Errorf("model was not written: %v", err)
+	}
+}
+
+func TestGenerateSkylineFutureDays(t *testing.T) {
+	now := time.Now()
+	if now.Month() == time.December && now.Day() > 24 {
+		t.Skip("the current year has no whole week left to come")
+	}
+
+	sizes := make(map[geometry.FutureDays]int64)
+	for _, future := range []geometry.FutureDays{geometry.FutureBlank, geometry.FutureTruncate, geometry.FutureDots} {
+		opts := Options{StartYear: now.Year(), EndYear: now.Year(), User: "testuser", Output: filepath.Join(t.TempDir(), "skyline.stl"), Geometry: geometry.DefaultConfig()}
+		opts.Geometry.FutureDays = future
+		opts.Client = github.NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+		if err := GenerateSkyline(context.Background(), opts); err != nil {
+			t.Fatalf("GenerateSkyline() future days = %s error = %v", future, err)
+		}
+		info, err := os.Stat(opts.Output)
+		if err != nil {
+			t.Fatalf("model was not written: %v", err)
+		}
+		sizes[future] = info.Size()
+	}
+	// The generated calendar has contributions on future days, which truncating leaves out
+	if sizes[geometry.FutureTruncate] >= sizes[geometry.FutureBlank] {
+		t.Errorf("truncated model is %d bytes, want fewer than the %d of the whole year", sizes[geometry.FutureTruncate], sizes[geometry.FutureBlank])
+	}
+	if sizes[geometry.FutureDots] <= sizes[geometry.FutureBlank] {
+		t.Errorf("model with future dots is %d bytes, want more than the %d without", sizes[geometry.FutureDots], sizes[geometry.FutureBlank])
+	}
+}
+
+func TestGenerateSkylineCapPercentile(t *testing.T) {
+	models := make(map[float64][]byte)
+	for _, percentile := range []float64{0, 50, 100} {
+		opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: filepath.Join(t.TempDir(), "skyline.stl"), CapPercentile: percentile}
+		opts.Client = github.NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+		if err := GenerateSkyline(context.Background(), opts); err != nil {
+			t.Fatalf("GenerateSkyline() cap percentile = %v error = %v", percentile, err)
+		}
+		model, err := os.ReadFile(opts.Output)
+		if err != nil {
+			t.Fatalf("model was not written: %v", err)
+		}
+		models[percentile] = model
+	}
+	// Capping at the busiest day changes nothing, and capping at the median flattens the busier half
+	if !bytes.Equal(models[100], models[0]) {
+		t.Error("capping at the 100th percentile changed the model")
+	}
+	if bytes.Equal(models[50], models[0]) {
+		t.Error("capping at the 50th percentile left the model unchanged")
+	}
+}
+
+func TestGenerateSkylineClient(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return nil, fmt.Errorf("the given client should be used")
+	}
+
+	err := GenerateSkyline(context.Background(), Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    filepath.Join(t.TempDir(), "skyline.stl"),
+		Client:    github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}),
+	})
+	if err != nil {
+		t.Errorf("GenerateSkyline() error = %v", err)
+	}
+}
+
+func TestGenerateSkylineView(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "skyline.stl")
+	var viewed *types.Model
+	err := GenerateSkyline(context.Background(), Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    output,
+		Client:    github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}),
+		View: func(_ context.Context, model *types.Model) error {
+			viewed = model
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if viewed == nil || viewed.TriangleCount() == 0 {
+		t.Fatal("GenerateSkyline() did not show the model")
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("GenerateSkyline() wrote the shown model to %s", output)
+	}
+}
+
+func TestGenerateSkylineExportData(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"data.json", "data.csv"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			err := GenerateSkyline(context.Background(), Options{
+				StartYear:  2023,
+				EndYear:    2024,
+				User:       "testuser",
+				ArtOnly:    true,
+				ExportData: path,
+				Client:     github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}),
+			})
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("data file was not written: %v", err)
+			}
+			if !strings.Contains(string(data), "2023-01-01") || !strings.Contains(string(data), "2024-01-01") {
+				t.Errorf("data file is missing days of the years fetched")
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineInput(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return nil, fmt.Errorf("generating from a data file should not create a client")
+	}
+
+	dir := t.TempDir()
+	var years [][][]types.ContributionDay
+	for year := 2021; year <= 2022; year++ {
+		years = append(years, contributionGrid(fixtures.GenerateContributionsResponse("testuser", year)))
+	}
+	jsonPath, csvPath := filepath.Join(dir, "data.json"), filepath.Join(dir, "data.csv")
+	for _, path := range []string{jsonPath, csvPath} {
+		if err := dataset.Write(path, "testuser", years); err != nil {
+			t.Fatalf("dataset.Write() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantError string
+	}{
+		{"json names the user", Options{Input: jsonPath, StartYear: 2021, EndYear: 2022}, ""},
+		{"csv with a user", Options{Input: csvPath, User: "testuser", StartYear: 2022, EndYear: 2022}, ""},
+		{"full range of the file", Options{Input: jsonPath, Full: true}, ""},
+		{"csv without a user", Options{Input: csvPath, StartYear: 2021, EndYear: 2022}, "--user"},
+		{"year missing from the file", Options{Input: jsonPath, StartYear: 2020, EndYear: 2022}, "2020"},
+		{"missing file", Options{Input: filepath.Join(dir, "missing.json"), StartYear: 2021, EndYear: 2022}, "read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Output = filepath.Join(t.TempDir(), "skyline.stl")
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("GenerateSkyline() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineOffline(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return nil, fmt.Errorf("offline mode should not create a client")
+	}
+
+	store, err := cache.New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+	host, _ := auth.DefaultHost()
+	for year := 2020; year <= 2022; year++ {
+		if err := store.Put(host, "testuser", year, fixtures.GenerateContributionsResponse("testuser", year)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantError string
+	}{
+		{"cached years", Options{StartYear: 2020, EndYear: 2022, User: "testuser", Cache: store}, ""},
+		{"missing years", Options{StartYear: 2019, EndYear: 2023, User: "testuser", Cache: store}, "2019, 2023"},
+		{"no cache", Options{StartYear: 2020, EndYear: 2022, User: "testuser"}, "cache"},
+		{"no user", Options{StartYear: 2020, EndYear: 2022, Cache: store}, "user"},
+		{"full range", Options{User: "testuser", Full: true, Cache: store}, "joined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Offline = true
+			tt.opts.Output = filepath.Join(t.TempDir(), "skyline.stl")
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("GenerateSkyline() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineTeam(t *testing.T) {
+	store, err := cache.New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+	host, _ := auth.DefaultHost()
+	for _, username := range []string{"alice", "bob"} {
+		if err := store.Put(host, username, 2022, fixtures.GenerateContributionsResponse(username, 2022)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantDay   string
+		wantError string
+	}{
+		{"fetched", Options{StartYear: 2024, EndYear: 2024, Team: []string{"alice", "bob"}}, "2024-01-02,2", ""},
+		{"three users", Options{StartYear: 2024, EndYear: 2024, Team: []string{"alice", "bob", "carol"}}, "2024-01-02,3", ""},
+		{"offline", Options{StartYear: 2022, EndYear: 2022, Team: []string{"alice", "bob"}, Offline: true, Cache: store}, "2022-01-02,2", ""},
+		{"offline member missing", Options{StartYear: 2022, EndYear: 2022, Team: []string{"alice", "carol"}, Offline: true, Cache: store}, "", "carol"},
+		{"listed twice", Options{StartYear: 2024, EndYear: 2024, Team: []string{"alice", "Alice"}}, "", "twice"},
+		{"empty username", Options{StartYear: 2024, EndYear: 2024, Team: []string{"alice", ""}}, "", "empty"},
+		{"with a data file", Options{StartYear: 2024, EndYear: 2024, Team: []string{"alice", "bob"}, Input: "data.csv"}, "", "data file"},
+		{"with a QR code", Options{StartYear: 2024, EndYear: 2024, Team: []string{"alice", "bob"}, QR: true}, "", "QR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.ArtOnly = true
+			tt.opts.ExportData = filepath.Join(t.TempDir(), "data.csv")
+			tt.opts.Client = github.NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := os.ReadFile(tt.opts.ExportData)
+			if err != nil {
+				t.Fatalf("data file was not written: %v", err)
+			}
+			if !strings.Contains(string(data), tt.wantDay) {
+				t.Errorf("data file does not sum the team's contributions to %s", tt.wantDay)
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineMergeUsers(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		wantError string
+	}{
+		{"two accounts", Options{MergeUsers: []string{"olduser", "newuser"}}, ""},
+		{"with a QR code", Options{MergeUsers: []string{"olduser", "newuser"}, QR: true}, ""},
+		{"one account", Options{MergeUsers: []string{"newuser"}}, "two"},
+		{"listed twice", Options{MergeUsers: []string{"newuser", "NewUser"}}, "twice"},
+		{"with a team", Options{MergeUsers: []string{"olduser", "newuser"}, Team: []string{"alice", "bob"}}, "users"},
+		{"with a data file", Options{MergeUsers: []string{"olduser", "newuser"}, Input: "data.json"}, "data file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.StartYear, tt.opts.EndYear = 2024, 2024
+			tt.opts.ArtOnly = true
+			tt.opts.ExportData = filepath.Join(t.TempDir(), "data.json")
+			tt.opts.Client = github.NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := dataset.Read(tt.opts.ExportData)
+			if err != nil {
+				t.Fatalf("dataset.Read() error = %v", err)
+			}
+			if data.Username != "newuser" {
+				t.Errorf("merged history is named %q, want the last account's", data.Username)
+			}
+			for _, day := range data.Days {
+				if day.Date == "2024-01-02" && day.ContributionCount != 2 {
+					t.Errorf("merged history counts %d on %s, want both accounts' 2", day.ContributionCount, day.Date)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineWindow(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		opts      Options
+		wantFirst string
+		wantLast  string
+		wantError string
+	}{
+		{"within a year", Options{StartYear: 2024, EndYear: 2024, From: date(2024, time.February, 1), To: date(2024, time.March, 31)}, "2024-02-01", "2024-03-31", ""},
+		{"across years", Options{StartYear: 2023, EndYear: 2024, From: date(2023, time.November, 1), To: date(2024, time.February, 29)}, "2023-11-01", "2024-02-29", ""},
+		{"outside the years", Options{StartYear: 2024, EndYear: 2024, From: date(2023, time.November, 1), To: date(2024, time.February, 29)}, "", "", "must run from"},
+		{"reversed", Options{StartYear: 2024, EndYear: 2024, From: date(2024, time.March, 1), To: date(2024, time.February, 29)}, "", "", "on or before"},
+		{"full range", Options{StartYear: 2024, EndYear: 2024, From: date(2024, time.February, 1), To: date(2024, time.March, 31), Full: true}, "", "", "full range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.ArtOnly = true
+			tt.opts.ExportData = filepath.Join(t.TempDir(), "data.json")
+			tt.opts.Client = github.NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := dataset.Read(tt.opts.ExportData)
+			if err != nil {
+				t.Fatalf("dataset.Read() error = %v", err)
+			}
+			if len(data.Days) == 0 {
+				t.Fatal("no days were exported")
+			}
+			first, last := data.Days[0].Date, data.Days[0].Date
+			for _, day := range data.Days {
+				first, last = min(first, day.Date), max(last, day.Date)
+			}
+			if first != tt.wantFirst || last != tt.wantLast {
+				t.Errorf("exported days run %s to %s, want %s to %s", first, last, tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineYearBoundary(t *testing.T) {
+	// Both years list the days of the week that straddles the new year
+	api := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"user": {"login": "testuser",
+			"y2023": {"contributionCalendar": {"totalContributions": 5, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2023-12-31"}, {"contributionCount": 3, "date": "2024-01-01"}]}]}},
+			"y2024": {"contributionCalendar": {"totalContributions": 6, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2023-12-31"}, {"contributionCount": 3, "date": "2024-01-01"}, {"contributionCount": 1, "date": "2024-01-02"}]}]}}}}`, nil
+	})
+	opts := Options{StartYear: 2023, EndYear: 2024, User: "testuser", ArtOnly: true, ExportData: filepath.Join(t.TempDir(), "data.csv"), Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	data, err := os.ReadFile(opts.ExportData)
+	if err != nil {
+		t.Fatalf("data file was not written: %v", err)
+	}
+	for _, row := range []string{"2023-12-31,2", "2024-01-01,3", "2024-01-02,1"} {
+		if n := strings.Count(string(data), row); n != 1 {
+			t.Errorf("data file lists %s %d times, want once:\n%s", row, n, data)
+		}
+	}
+}
+
+func TestGenerateSkylineSkipEmptyYears(t *testing.T) {
+	// 2021 has no contributions
+	api := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"user": {"login": "testuser",
+			"y2020": {"contributionCalendar": {"totalContributions": 2, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2020-03-02"}]}]}},
+			"y2021": {"contributionCalendar": {"totalContributions": 0, "weeks": [{"contributionDays": [{"contributionCount": 0, "date": "2021-03-01"}]}]}},
+			"y2022": {"contributionCalendar": {"totalContributions": 1, "weeks": [{"contributionDays": [{"contributionCount": 1, "date": "2022-03-07"}]}]}}}}`, nil
+	})
+
+	for _, skip := range []bool{false, true} {
+		dir := t.TempDir()
+		opts := Options{StartYear: 2020, EndYear: 2022, User: "testuser", SkipEmptyYears: skip, SplitYears: true, Output: filepath.Join(dir, "skyline.stl"), Client: github.NewClient(api)}
+		opts.Geometry.OmitText, opts.Geometry.OmitLogo = true, true
+		if err := GenerateSkyline(context.Background(), opts); err != nil {
+			t.Fatalf("GenerateSkyline() skip empty years = %t error = %v", skip, err)
+		}
+		for _, year := range []int{2020, 2021, 2022} {
+			_, err := os.Stat(filepath.Join(dir, fmt.Sprintf("skyline-%d.stl", year)))
+			if want := !skip || year != 2021; (err == nil) != want {
+				t.Errorf("skip empty years = %t: model of %d written = %t, want %t", skip, year, err == nil, want)
+			}
+		}
+	}
+
+	// A range without any contributions leaves nothing to generate
+	opts := Options{StartYear: 2021, EndYear: 2021, User: "testuser", SkipEmptyYears: true, ArtOnly: true, Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "no contributions") {
+		t.Errorf("GenerateSkyline() of only empty years error = %v, want no contributions", err)
+	}
+}
+
+func TestGenerateSkylineAccountErrors(t *testing.T) {
+	// GitHub has a user named mona, but none named octo-org, an organization, or nobody
+	api := mocks.GraphQLFunc(func(query string, variables map[string]interface{}) (string, error) {
+		if strings.Contains(query, "AccountKind") {
+			if variables["login"] == "octo-org" {
+				return `{"repositoryOwner": {"__typename": "Organization"}}`, nil
+			}
+			return `{"repositoryOwner": null}`, nil
+		}
+		if variables["username"] == "mona" {
+			return `{"user": {"login": "mona", "y2024": {"contributionCalendar": {"totalContributions": 1, "weeks": [{"contributionDays": [{"contributionCount": 1, "date": "2024-01-01"}]}]}}}}`, nil
+		}
+		return `{"user": null}`, nil
+	})
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantError string
+	}{
+		{"organization", Options{User: "octo-org"}, "--org octo-org"},
+		{"organization full range", Options{User: "octo-org", Full: true}, "--org octo-org"},
+		{"organization in a team", Options{Team: []string{"mona", "octo-org"}}, "--org octo-org"},
+		{"no account", Options{User: "nobody"}, "no GitHub user is named nobody"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.StartYear, tt.opts.EndYear, tt.opts.ArtOnly = 2024, 2024, true
+			tt.opts.Client = github.NewClient(api)
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("GenerateSkyline() error = %v, want it to mention %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestTeamName(t *testing.T) {
+	if got := teamName([]string{"alice", "bob", "carol"}); got != "alice+bob+carol" {
+		t.Errorf("teamName() = %q, want %q", got, "alice+bob+carol")
+	}
+}
+
+func TestGenerateSkylineFilterOrg(t *testing.T) {
+	api := mocks.GraphQLFunc(func(query string, variables map[string]interface{}) (string, error) {
+		switch {
+		case strings.Contains(query, "OrganizationID"):
+			if variables["org"] != "octo-org" {
+				return `{"organization": null}`, nil
+			}
+			return `{"organization": {"id": "O_kgDOAbc123"}}`, nil
+		case strings.Contains(query, "ContributionGraphs"):
+			if variables["organizationID"] != "O_kgDOAbc123" {
+				return "", fmt.Errorf("contributions fetched without the organization filter")
+			}
+			return `{"user": {"login": "testuser", "y2024": {"contributionCalendar": {"totalContributions": 1, "weeks": [{"contributionDays": [{"contributionCount": 1, "date": "2024-01-01"}]}]}}}}`, nil
+		}
+		return "", fmt.Errorf("unexpected query %q", query)
+	})
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantError string
+	}{
+		{"filtered", Options{FilterOrg: "octo-org"}, ""},
+		{"unknown organization", Options{FilterOrg: "no-such-org"}, "organization"},
+		{"offline", Options{FilterOrg: "octo-org", Offline: true}, "filtered"},
+		{"organization skyline", Options{FilterOrg: "octo-org", Org: "octo-org"}, "filtered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.StartYear, tt.opts.EndYear = 2024, 2024
+			if tt.opts.Org == "" {
+				tt.opts.User = "testuser"
+			}
+			tt.opts.ArtOnly = true
+			tt.opts.ExportData = filepath.Join(t.TempDir(), "data.csv")
+			tt.opts.Client = github.NewClient(api)
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := os.ReadFile(tt.opts.ExportData)
+			if err != nil {
+				t.Fatalf("data file was not written: %v", err)
+			}
+			if !strings.Contains(string(data), "2024-01-01,1") {
+				t.Errorf("data file does not hold the filtered contributions:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineTimeZone(t *testing.T) {
+	zone := time.FixedZone("PST", -8*60*60)
+	api := mocks.GraphQLFunc(func(query string, variables map[string]interface{}) (string, error) {
+		if variables["from2024"] != "2024-01-01T00:00:00-08:00" {
+			return "", fmt.Errorf("contributions fetched from %v, not midnight in the time zone", variables["from2024"])
+		}
+		return `{"user": {"login": "testuser", "y2024": {"contributionCalendar": {"totalContributions": 1, "weeks": [{"contributionDays": [{"contributionCount": 1, "date": "2024-01-01"}]}]}}}}`, nil
+	})
+	store, err := cache.New(t.TempDir(), cache.DefaultTTL)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", ArtOnly: true, TimeZone: zone, Cache: store, Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	// Days counted in another time zone are not cached with those counted in UTC
+	host, _ := auth.DefaultHost()
+	if _, found, _ := store.GetStale(host, "testuser", 2024); found {
+		t.Error("contributions counted in another time zone were cached")
+	}
+
+	opts.Offline = true
+	if err := GenerateSkyline(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "time zone") {
+		t.Errorf("GenerateSkyline() offline error = %v, want one mentioning the time zone", err)
+	}
+}
+
+func TestGenerateSkylineTypes(t *testing.T) {
+	api := mocks.GraphQLFunc(func(query string, _ map[string]interface{}) (string, error) {
+		if strings.Contains(query, "pullRequestReviewContributions") {
+			return `{"user": {"contributionsCollection": {"contributions": {"pageInfo": {"hasNextPage": false}, "nodes": [{"occurredAt": "2024-03-01T12:00:00Z"}, {"occurredAt": "2024-03-01T13:00:00Z"}]}}}}`, nil
+		}
+		return "", fmt.Errorf("unexpected query %q", query)
+	})
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantError string
+	}{
+		{"reviews", Options{}, ""},
+		{"offline", Options{Offline: true}, "type"},
+		{"organization skyline", Options{Org: "octo-org"}, "type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.StartYear, tt.opts.EndYear = 2024, 2024
+			if tt.opts.Org == "" {
+				tt.opts.User = "testuser"
+			}
+			tt.opts.Types = []github.ContributionType{github.TypeReviews}
+			tt.opts.ArtOnly = true
+			tt.opts.ExportData = filepath.Join(t.TempDir(), "data.csv")
+			tt.opts.Client = github.NewClient(api)
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := os.ReadFile(tt.opts.ExportData)
+			if err != nil {
+				t.Fatalf("data file was not written: %v", err)
+			}
+			if !strings.Contains(string(data), "2024-03-01,2") {
+				t.Errorf("data file does not count the reviews:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestGenerateSkylinePrivacy(t *testing.T) {
+	api := mocks.GraphQLFunc(func(query string, _ map[string]interface{}) (string, error) {
+		switch {
+		case strings.Contains(query, "ContributionGraphs"):
+			return `{"user": {"login": "testuser", "y2024": {"restrictedContributionsCount": 3, "contributionCalendar": {"totalContributions": 4, "weeks": [{"contributionDays": [{"contributionCount": 4, "date": "2024-01-01"}]}]}}}}`, nil
+		case strings.Contains(query, "CommitContributions"):
+			return `{"user": {"contributionsCollection": {"commitContributionsByRepository": [
+				{"repository": {"nameWithOwner": "testuser/public"}, "contributions": {"pageInfo": {"hasNextPage": false}, "nodes": [{"occurredAt": "2024-01-01T08:00:00Z", "commitCount": 1}]}},
+				{"repository": {"nameWithOwner": "testuser/private", "isPrivate": true}, "contributions": {"pageInfo": {"hasNextPage": false}, "nodes": [{"occurredAt": "2024-01-01T08:00:00Z", "commitCount": 3}]}}
+			]}}}`, nil
+		case strings.Contains(query, "TypedContributions"):
+			return `{"user": {"contributionsCollection": {"contributions": {"pageInfo": {"hasNextPage": false}, "nodes": []}}}}`, nil
+		}
+		return "", fmt.Errorf("unexpected query %q", query)
+	})
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantDay   string
+		wantError string
+	}{
+		{"public only", Options{PublicOnly: true}, "2024-01-01,1", ""},
+		{"include private", Options{IncludePrivate: true}, "2024-01-01,4", ""},
+		{"both", Options{PublicOnly: true, IncludePrivate: true}, "", "both"},
+		{"include private by type", Options{IncludePrivate: true, Types: []github.ContributionType{github.TypeCommits}}, "", "type"},
+		{"public only offline", Options{PublicOnly: true, Offline: true}, "", "private"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.StartYear, tt.opts.EndYear = 2024, 2024
+			tt.opts.User = "testuser"
+			tt.opts.ArtOnly = true
+			tt.opts.ExportData = filepath.Join(t.TempDir(), "data.csv")
+			tt.opts.Client = github.NewClient(api)
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := os.ReadFile(tt.opts.ExportData)
+			if err != nil {
+				t.Fatalf("data file was not written: %v", err)
+			}
+			if !strings.Contains(string(data), tt.wantDay) {
+				t.Errorf("data file does not count %s:\n%s", tt.wantDay, data)
+			}
+		})
+	}
+}
+
+func TestTypesLabel(t *testing.T) {
+	if got := typesLabel([]github.ContributionType{github.TypeCommits, github.TypeReviews}); got != "commits, reviews" {
+		t.Errorf("typesLabel() = %q, want %q", got, "commits, reviews")
+	}
+}
+
+func TestGenerateSkylineOrganization(t *testing.T) {
+	api := mocks.GraphQLFunc(func(query string, variables map[string]interface{}) (string, error) {
+		switch {
+		case strings.Contains(query, "OrganizationCreatedDate"):
+			return `{"organization": {"createdAt": "2023-06-01T00:00:00Z"}}`, nil
+		case strings.Contains(query, "OrganizationRepositories"):
+			return `{"organization": {"repositories": {"pageInfo": {"hasNextPage": false}, "nodes": [{"name": "api"}]}}}`, nil
+		case strings.Contains(query, "RepositoryCommits"):
+			return `{"repository": {"defaultBranchRef": {"target": {"history": {"pageInfo": {"hasNextPage": false}, "nodes": [{"authoredDate": "2024-05-01T10:00:00Z"}]}}}}}`, nil
+		}
+		return "", fmt.Errorf("unexpected query %q", query)
+	})
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantError string
+	}{
+		{"years", Options{StartYear: 2024, EndYear: 2024}, ""},
+		{"full range", Options{Full: true}, ""},
+		{"with a user", Options{StartYear: 2024, EndYear: 2024, User: "testuser"}, "user"},
+		{"offline", Options{StartYear: 2024, EndYear: 2024, Offline: true}, "GitHub API"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Org = "octo-org"
+			tt.opts.ArtOnly = true
+			tt.opts.ExportData = filepath.Join(t.TempDir(), "data.csv")
+			tt.opts.Client = github.NewClient(api)
+			err := GenerateSkyline(context.Background(), tt.opts)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSkyline() error = %v", err)
+			}
+			data, err := os.ReadFile(tt.opts.ExportData)
+			if err != nil {
+				t.Fatalf("data file was not written: %v", err)
+			}
+			if !strings.Contains(string(data), "2024-05-01,1") {
+				t.Errorf("data file does not count the organization's commit:\n%s", data)
+			}
+		})
+	}
+}
+
+// concurrencyAPIClient answers batched contribution queries for the requested
+// years after a short delay, recording the requests made and the most it had
+// in flight at once.
+type concurrencyAPIClient struct {
+	failYear    int
+	mu          sync.Mutex
+	requests    int
+	inFlight    int
+	maxInFlight int
+}
+
+// DoWithContext implements github.APIClient
+func (c *concurrencyAPIClient) DoWithContext(ctx context.Context, _ string, variables map[string]interface{}, response interface{}) error {
+	c.mu.Lock()
+	c.requests++
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(10 * time.Millisecond):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	username := variables["username"].(string)
+	login, _ := json.Marshal(username)
+	user := map[string]json.RawMessage{"login": login}
+	for name := range variables {
+		year, err := strconv.Atoi(strings.TrimPrefix(name, "from"))
+		if err != nil || !strings.HasPrefix(name, "from") {
+			continue
+		}
+		if year == c.failYear {
+			return fmt.Errorf("fetching %d failed", year)
+		}
+		user[fmt.Sprintf("y%d", year)], _ = json.Marshal(fixtures.GenerateContributionsResponse(username, year).User.ContributionsCollection)
+	}
+	response.(*struct {
+		User map[string]json.RawMessage `json:"user"`
+	}).User = user
+	return nil
+}
+
+func TestFetchYears(t *testing.T) {
+	apiClient := &concurrencyAPIClient{}
+	years, err := fetchYears(context.Background(), github.NewClient(apiClient), nil, "testuser", 2008, 2024, github.ContributionFilter{})
+	if err != nil {
+		t.Fatalf("fetchYears() error = %v", err)
+	}
+	if len(years) != 17 {
+		t.Fatalf("fetchYears() returned %d years, want 17", len(years))
+	}
+	for i, year := range years {
+		if want := fmt.Sprint(2008 + i); year[0][0].Date[:4] != want {
+			t.Errorf("fetchYears() year %d starts on %s, want a day in %s", i, year[0][0].Date, want)
+		}
+	}
+	if want := 4; apiClient.requests != want {
+		t.Errorf("fetchYears() made %d requests for 17 years, want %d", apiClient.requests, want)
+	}
+	if apiClient.maxInFlight < 2 || apiClient.maxInFlight > maxConcurrentFetches {
+		t.Errorf("fetchYears() made %d requests at once, want between 2 and %d", apiClient.maxInFlight, maxConcurrentFetches)
+	}
+
+	if _, err := fetchYears(context.Background(), github.NewClient(&concurrencyAPIClient{failYear: 2015}), nil, "testuser", 2008, 2024, github.ContributionFilter{}); err == nil || !strings.Contains(err.Error(), "fetching 2015 failed") {
+		t.Errorf("fetchYears() error = %v, want the failed year's error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fetchYears(ctx, github.NewClient(&concurrencyAPIClient{}), nil, "testuser", 2008, 2024, github.ContributionFilter{}); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("fetchYears() error = %v, want it canceled", err)
+	}
+}
+
+func TestFetchYearsCache(t *testing.T) {
+	store, err := cache.New(t.TempDir(), cache.DefaultTTL)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+
+	// 2010-2014 are cached, leaving 2008-2009 and 2015-2020 in batches of at most five years
+	apiClient := &concurrencyAPIClient{}
+	if _, err := fetchYears(context.Background(), github.NewClient(apiClient), store, "testuser", 2010, 2014, github.ContributionFilter{}); err != nil {
+		t.Fatalf("fetchYears() error = %v", err)
+	}
+	apiClient = &concurrencyAPIClient{}
+	years, err := fetchYears(context.Background(), github.NewClient(apiClient), store, "testuser", 2008, 2020, github.ContributionFilter{})
+	if err != nil {
+		t.Fatalf("fetchYears() error = %v", err)
+	}
+	if want := 3; apiClient.requests != want {
+		t.Errorf("fetchYears() made %d requests around the cached years, want %d", apiClient.requests, want)
+	}
+	for i, year := range years {
+		if want := fmt.Sprint(2008 + i); year[0][0].Date[:4] != want {
+			t.Errorf("fetchYears() year %d starts on %s, want a day in %s", i, year[0][0].Date, want)
+		}
+	}
+
+	apiClient = &concurrencyAPIClient{}
+	if _, err := fetchYears(context.Background(), github.NewClient(apiClient), store, "testuser", 2008, 2020, github.ContributionFilter{}); err != nil {
+		t.Fatalf("fetchYears() error = %v", err)
+	}
+	if apiClient.requests != 0 {
+		t.Errorf("fetchYears() made %d requests for cached years, want none", apiClient.requests)
+	}
+
+	// Filtered contributions are neither read from nor added to the cache
+	apiClient = &concurrencyAPIClient{}
+	filter := github.ContributionFilter{OrganizationID: "O_kgDOAbc123"}
+	if _, err := fetchYears(context.Background(), github.NewClient(apiClient), store, "testuser", 2010, 2014, filter); err != nil {
+		t.Fatalf("fetchYears() error = %v", err)
+	}
+	if apiClient.requests != 1 {
+		t.Errorf("fetchYears() made %d requests for filtered years, want 1", apiClient.requests)
+	}
+	empty, err := cache.New(t.TempDir(), cache.DefaultTTL)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+	if _, err := fetchYears(context.Background(), github.NewClient(&concurrencyAPIClient{}), empty, "testuser", 2010, 2014, filter); err != nil {
+		t.Fatalf("fetchYears() error = %v", err)
+	}
+	host, _ := auth.DefaultHost()
+	if _, found, _ := empty.GetStale(host, "testuser", 2010); found {
+		t.Error("fetchYears() cached filtered contributions")
+	}
+}
+
+func TestGenerateSkylineOpen(t *testing.T) {
+	api := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"user": {"login": "mona", "y2024": {"contributionCalendar": {"totalContributions": 5, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2024-01-01"}, {"contributionCount": 3, "date": "2024-01-02"}]}]}}}}`, nil
+	})
+
+	var opened []string
+	open := func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+	output := filepath.Join(t.TempDir(), "model.stl")
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "mona", Output: output, Open: open, Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if len(opened) != 1 || opened[0] != output {
+		t.Errorf("GenerateSkyline() opened %v, want only %s", opened, output)
+	}
+
+	failing := opts
+	failing.Open = func(string) error { return stderrors.New("no launcher") }
+	if err := GenerateSkyline(context.Background(), failing); err == nil || !strings.Contains(err.Error(), "no launcher") {
+		t.Errorf("GenerateSkyline() error = %v, want the failure to open", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Options)
+		want   string
+	}{
+		{"art only", func(o *Options) { o.ArtOnly = true }, "ASCII preview"},
+		{"streamed", func(o *Options) { o.ModelOut = &bytes.Buffer{} }, "streamed"},
+		{"split parts", func(o *Options) { o.Split = true }, "split"},
+		{"split years", func(o *Options) { o.SplitYears = true }, "split"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejected := opts
+			tt.modify(&rejected)
+			opened = nil
+			if err := GenerateSkyline(context.Background(), rejected); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.want)
+			}
+			if len(opened) > 0 {
+				t.Errorf("GenerateSkyline() opened %v, want nothing opened", opened)
+			}
+		})
+	}
+}
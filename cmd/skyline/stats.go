@@ -0,0 +1,44 @@
+package skyline
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/stats"
+)
+
+// statsBarWidth is the length in characters of the bar of the day of the week
+// with the most contributions.
+const statsBarWidth = 30
+
+// writeStats prints a summary of a contribution history to w: the totals, the
+// busiest day and week, the longest streak and a bar for each day of the week.
+func writeStats(w io.Writer, name, period string, s stats.Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s, %s\n\n", name, period)
+	fmt.Fprintf(tw, "Total contributions\t%d\n", s.Total)
+	fmt.Fprintf(tw, "Active days\t%d\n", s.ActiveDays)
+	if s.Total > 0 {
+		fmt.Fprintf(tw, "Busiest day\t%s (%d)\n", s.BusiestDay.Date, s.BusiestDay.ContributionCount)
+		fmt.Fprintf(tw, "Busiest week\t%s (%d)\n", s.BusiestWeek, s.BusiestWeekTotal)
+		fmt.Fprintf(tw, "Longest streak\t%d days (%s to %s)\n", s.LongestStreak, s.StreakStart, s.StreakEnd)
+	}
+	fmt.Fprintln(tw)
+
+	busiest := 0
+	for _, count := range s.Weekdays {
+		busiest = max(busiest, count)
+	}
+	for day, count := range s.Weekdays {
+		bar := 0
+		if busiest > 0 {
+			bar = (count*statsBarWidth + busiest - 1) / busiest
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", time.Weekday(day), strings.Repeat(string(ascii.FoundationHigh), bar), count)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,96 @@
+package skyline
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stats"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWriteStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary stats.Summary
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "contributions",
+			summary: stats.Summary{
+				Total:            17,
+				ActiveDays:       6,
+				BusiestDay:       types.ContributionDay{Date: "2024-01-10", ContributionCount: 5},
+				LongestStreak:    3,
+				StreakStart:      "2024-01-10",
+				StreakEnd:        "2024-01-12",
+				BusiestWeek:      "2024-01-07",
+				BusiestWeekTotal: 14,
+				Weekdays:         [7]int{4, 2, 0, 5, 5, 1, 0},
+			},
+			want: []string{
+				"mona, 2024",
+				"Total contributions  17",
+				"Active days          6",
+				"Busiest day          2024-01-10 (5)",
+				"Busiest week         2024-01-07 (14)",
+				"Longest streak       3 days (2024-01-10 to 2024-01-12)",
+				"Wednesday  " + strings.Repeat("▓", statsBarWidth) + "  5",
+				"Tuesday    " + strings.Repeat(" ", statsBarWidth) + "  0",
+			},
+		},
+		{
+			name:    "no contributions",
+			want:    []string{"Total contributions  0", "Sunday"},
+			notWant: []string{"Busiest day", "Longest streak", "▓"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeStats(&buf, "mona", "2024", tt.summary); err != nil {
+				t.Fatalf("writeStats() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("writeStats() output lacks %q:\n%s", want, buf.String())
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(buf.String(), notWant) {
+					t.Errorf("writeStats() output has %q:\n%s", notWant, buf.String())
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateSkylineStats verifies statistics are printed in place of the
+// model, fetched like the model's contributions are
+func TestGenerateSkylineStats(t *testing.T) {
+	api := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"user": {"login": "mona", "y2024": {"contributionCalendar": {"totalContributions": 5, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2024-01-01"}, {"contributionCount": 3, "date": "2024-01-02"}]}]}}}}`, nil
+	})
+
+	var buf bytes.Buffer
+	output := filepath.Join(t.TempDir(), "model.stl")
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "mona", Output: output, StatsOut: &buf, Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	for _, want := range []string{"mona, 2024", "Total contributions  5", "Longest streak       2 days (2024-01-01 to 2024-01-02)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("GenerateSkyline() statistics lack %q:\n%s", want, buf.String())
+		}
+	}
+	if _, err := os.Stat(output); err == nil {
+		t.Errorf("GenerateSkyline() wrote a model alongside the statistics")
+	}
+}
@@ -0,0 +1,27 @@
+package skyline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/profile"
+	"github.com/github/gh-skyline/internal/stl"
+)
+
+// streamModel generates the skyline as GenerateSkyline does, streaming the
+// model to opts.ModelOut as binary STL in place of writing a file, such as to
+// pipe it into a slicer. Only a single STL model can be streamed.
+func streamModel(ctx context.Context, opts Options) error {
+	switch {
+	case opts.ArtOnly:
+		return errors.New(errors.ValidationError, "only the ASCII preview writes no model to stream", nil)
+	case opts.Format != "" && opts.Format != stl.FormatSTL:
+		return errors.New(errors.ValidationError, fmt.Sprintf("only STL models can be streamed, write %s models to a file", opts.Format), nil)
+	case opts.Split || opts.SplitYears:
+		return errors.New(errors.ValidationError, "a streamed model cannot be split into several files", nil)
+	case opts.Profile != profile.ModeNone:
+		return errors.New(errors.ValidationError, "profiles are written next to the model file, so a streamed model cannot be profiled", nil)
+	}
+	return generateSkyline(ctx, opts)
+}
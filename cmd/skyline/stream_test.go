@@ -0,0 +1,69 @@
+package skyline
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/profile"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestGenerateSkylineStream(t *testing.T) {
+	api := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"user": {"login": "mona", "y2024": {"contributionCalendar": {"totalContributions": 5, "weeks": [{"contributionDays": [{"contributionCount": 2, "date": "2024-01-01"}, {"contributionCount": 3, "date": "2024-01-02"}]}]}}}}`, nil
+	})
+
+	dir := t.TempDir()
+	var model, preview bytes.Buffer
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "mona", ModelOut: &model, PreviewOut: &preview, Client: github.NewClient(api)}
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	triangles, err := stl.DecodeSTL(&model)
+	if err != nil {
+		t.Fatalf("GenerateSkyline() streamed no STL model: %v", err)
+	}
+	if len(triangles) == 0 {
+		t.Error("GenerateSkyline() streamed a model without triangles")
+	}
+	if !strings.Contains(preview.String(), "mona") {
+		t.Errorf("GenerateSkyline() printed the preview %q, want it on PreviewOut", preview.String())
+	}
+
+	opts.Output, opts.Format = filepath.Join(dir, "model.stl"), stl.FormatSTL
+	model.Reset()
+	if err := GenerateSkyline(context.Background(), opts); err != nil {
+		t.Fatalf("GenerateSkyline() with an output error = %v", err)
+	}
+	if _, err := os.Stat(opts.Output); !os.IsNotExist(err) {
+		t.Errorf("GenerateSkyline() wrote %s, want the model streamed alone: %v", opts.Output, err)
+	}
+}
+
+func TestGenerateSkylineStreamRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"art only", Options{ArtOnly: true}, "ASCII preview"},
+		{"png", Options{Format: stl.FormatPNG}, "only STL"},
+		{"split parts", Options{Split: true}, "split"},
+		{"split years", Options{SplitYears: true}, "split"},
+		{"profile", Options{Profile: profile.ModeCPU}, "profiled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.StartYear, tt.opts.EndYear, tt.opts.User, tt.opts.ModelOut = 2024, 2024, "mona", &bytes.Buffer{}
+			if err := GenerateSkyline(context.Background(), tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GenerateSkyline() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd prints statistics of a contribution history in place of a model.
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Print statistics of a user's GitHub contribution history",
+	Long: `Print statistics of the contributions of a user, team or organization over
+a year range: the total, the active days, the busiest day and week, the longest
+streak and how contributions fall across the days of the week.
+
+Contributions are fetched, cached and counted as they are for a model, but no
+preview or model file is generated.`,
+	Args: cobra.NoArgs,
+	RunE: handleStatsCommand,
+}
+
+// init registers the stats command and its flags.
+func init() {
+	addSourceFlags(statsCmd.Flags())
+	rootCmd.AddCommand(statsCmd)
+}
+
+// handleStatsCommand is the main execution function for the stats command.
+func handleStatsCommand(cmd *cobra.Command, _ []string) error {
+	src, err := loadSource()
+	if err != nil {
+		return err
+	}
+
+	startYear, endYear, from, to, err := parsePeriod(yearRange)
+	if err != nil {
+		return err
+	}
+
+	contributionCache, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	return skyline.GenerateSkyline(progressContext(cmd, os.Stdout), skyline.Options{
+		StartYear:      startYear,
+		EndYear:        endYear,
+		From:           from,
+		To:             to,
+		TimeZone:       src.zone,
+		User:           src.user,
+		Full:           full,
+		Cache:          contributionCache,
+		Client:         src.client,
+		Offline:        offline,
+		Input:          input,
+		Org:            org,
+		Team:           src.team,
+		MergeUsers:     src.merged,
+		FilterOrg:      filterOrg,
+		Types:          src.kinds,
+		PublicOnly:     publicOnly,
+		IncludePrivate: includePrivate,
+		StatsOut:       cmd.OutOrStdout(),
+	})
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/dataset"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestStatsCmd(t *testing.T) {
+	if cmd, _, err := rootCmd.Find([]string{"stats"}); err != nil || cmd != statsCmd {
+		t.Fatalf("expected stats to be a subcommand of the root command, got %v, %v", cmd, err)
+	}
+
+	flags := statsCmd.Flags()
+	for _, flag := range []string{"year", "user", "merge-users", "org", "filter-org", "types", "include-private", "public-only", "full", "timezone", "token", "retries", "retry-backoff", "retry-jitter", "cache-ttl", "no-cache", "offline", "input", "debug"} {
+		if flags.Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+	for _, flag := range []string{"output", "format", "art-only"} {
+		if flags.Lookup(flag) != nil {
+			t.Errorf("expected model flag %s not to be initialized", flag)
+		}
+	}
+}
+
+func TestHandleStatsCommand(t *testing.T) {
+	defer func(path, years string, disabled bool) { input, yearRange, noCache = path, years, disabled }(input, yearRange, noCache)
+
+	path := filepath.Join(t.TempDir(), "mona.json")
+	days := []types.ContributionDay{
+		{Date: "2024-01-01", ContributionCount: 2},
+		{Date: "2024-01-02", ContributionCount: 3},
+		{Date: "2024-01-03", ContributionCount: 0},
+	}
+	if err := dataset.Write(path, "mona", [][][]types.ContributionDay{{days}}); err != nil {
+		t.Fatalf("dataset.Write() error = %v", err)
+	}
+
+	input, yearRange, noCache = path, "2024", true
+	var buf bytes.Buffer
+	statsCmd.SetOut(&buf)
+	defer statsCmd.SetOut(nil)
+	if err := handleStatsCommand(statsCmd, nil); err != nil {
+		t.Fatalf("handleStatsCommand() error = %v", err)
+	}
+	for _, want := range []string{"mona, 2024", "Total contributions  5", "Busiest day          2024-01-02 (3)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("handleStatsCommand() output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/github/gh-skyline/internal/buildinfo"
+	"github.com/spf13/cobra"
+)
+
+// versionCmd prints the version and build of the CLI.
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version, commit, build date and Go version of gh-skyline",
+	Args:  cobra.NoArgs,
+	RunE:  handleVersionCommand,
+	// The version is printed even when the config file is broken
+	Annotations: map[string]string{noConfigAnnotation: ""},
+}
+
+// init registers the version command, and the --version flag printing the
+// same.
+func init() {
+	build := buildinfo.Get()
+	rootCmd.Version = build.Version
+	rootCmd.SetVersionTemplate(build.String())
+	rootCmd.AddCommand(versionCmd)
+}
+
+// handleVersionCommand is the main execution function for the version command.
+func handleVersionCommand(cmd *cobra.Command, _ []string) error {
+	_, err := fmt.Fprint(cmd.OutOrStdout(), buildinfo.Get())
+	return err
+}
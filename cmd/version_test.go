@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/buildinfo"
+)
+
+func TestVersionCmd(t *testing.T) {
+	if cmd, _, err := rootCmd.Find([]string{"version"}); err != nil || cmd != versionCmd {
+		t.Fatalf("expected version to be a subcommand of the root command, got %v, %v", cmd, err)
+	}
+	if rootCmd.Version == "" {
+		t.Error("expected the root command to have a version for its --version flag")
+	}
+
+	want := buildinfo.Get().String()
+	for _, args := range [][]string{{"version"}, {"--version"}} {
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(args)
+		err := rootCmd.Execute()
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		if err != nil {
+			t.Fatalf("skyline %s error = %v", strings.Join(args, " "), err)
+		}
+		if buf.String() != want {
+			t.Errorf("skyline %s printed %q, want %q", strings.Join(args, " "), buf.String(), want)
+		}
+	}
+}
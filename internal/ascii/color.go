@@ -0,0 +1,136 @@
+package ascii
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/github/gh-skyline/internal/theme"
+)
+
+// ColorMode identifies the ANSI colors a terminal shows.
+type ColorMode int
+
+// Supported color modes, from none to the most colors.
+const (
+	NoColor   ColorMode = iota // Plain text, without ANSI sequences
+	Color16                    // The 16 colors of the basic ANSI palette
+	Color256                   // The 256 colors of xterm
+	TrueColor                  // Any 24-bit color
+)
+
+// Style sets how a preview is drawn.
+type Style struct {
+	Color   ColorMode     // Colors the terminal shows, NoColor for plain text
+	Palette theme.Palette // Colors of the contribution levels, theme.GitHubDark when zero
+	Width   int           // Columns the preview may span, such as the terminal's width, 0 for no limit
+}
+
+// resetSequence ends a colored run of text.
+const resetSequence = "\x1b[0m"
+
+// level returns the contribution level, from 0 to 3, of a day whose count is
+// normalized against the busiest day, split into quarters as GitHub's
+// calendar does.
+func level(normalized float64) int {
+	return min(max(int(normalized*4-1e-9), 0), 3)
+}
+
+// sequence returns the ANSI sequence coloring the text of a day of level in
+// the style, or an empty string when the style has no colors.
+func (s Style) sequence(level int) string {
+	palette := s.Palette
+	if palette.IsZero() {
+		palette = theme.GitHubDark
+	}
+	c := palette[level]
+	switch s.Color {
+	case TrueColor:
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+	case Color256:
+		return fmt.Sprintf("\x1b[38;5;%dm", ansi256(c))
+	case Color16:
+		return fmt.Sprintf("\x1b[%dm", ansi16(c))
+	default:
+		return ""
+	}
+}
+
+// cubeLevels are the intensities of each channel in the 6x6x6 color cube of
+// the 256 color palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// ansi256 returns the color of the 256 color palette's cube nearest to c.
+func ansi256(c color.RGBA) int {
+	nearest := func(v uint8) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if abs(int(v)-l) < abs(int(v)-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	return 16 + 36*nearest(c.R) + 6*nearest(c.G) + nearest(c.B)
+}
+
+// basicColors are the colors of the basic ANSI palette as xterm shows them,
+// by their SGR code. Black is left out, as the towers would vanish into the
+// background of dark terminals.
+var basicColors = map[int]color.RGBA{
+	31: {R: 205}, 32: {G: 205}, 33: {R: 205, G: 205}, 34: {B: 238},
+	35: {R: 205, B: 205}, 36: {G: 205, B: 205}, 37: {R: 229, G: 229, B: 229},
+	90: {R: 127, G: 127, B: 127}, 91: {R: 255}, 92: {G: 255}, 93: {R: 255, G: 255},
+	94: {R: 92, G: 92, B: 255}, 95: {R: 255, B: 255}, 96: {G: 255, B: 255}, 97: {R: 255, G: 255, B: 255},
+}
+
+// ansi16 returns the SGR code of the basic ANSI color nearest to c.
+func ansi16(c color.RGBA) int {
+	best, bestDistance := 0, -1
+	for code, basic := range basicColors {
+		dr, dg, db := int(c.R)-int(basic.R), int(c.G)-int(basic.G), int(c.B)-int(basic.B)
+		distance := dr*dr + dg*dg + db*db
+		// Ties go to the lower code, so the choice does not depend on map order
+		if bestDistance < 0 || distance < bestDistance || (distance == bestDistance && code < best) {
+			best, bestDistance = code, distance
+		}
+	}
+	return best
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// visibleWidth returns the number of characters of s shown on a terminal,
+// leaving out the ANSI sequences coloring it.
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(stripColors(s))
+}
+
+// stripColors removes the ANSI sequences coloring s.
+func stripColors(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "\x1b[")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], 'm')
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:start])
+		s = s[start+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
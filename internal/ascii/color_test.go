@@ -0,0 +1,114 @@
+package ascii
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/theme"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		normalized float64
+		want       int
+	}{
+		{0.01, 0},
+		{0.25, 0},
+		{0.26, 1},
+		{0.5, 1},
+		{0.75, 2},
+		{0.9, 3},
+		{1, 3},
+	}
+	for _, tt := range tests {
+		if got := level(tt.normalized); got != tt.want {
+			t.Errorf("level(%g) = %d, want %d", tt.normalized, got, tt.want)
+		}
+	}
+}
+
+func TestStyleSequence(t *testing.T) {
+	custom := theme.Palette{{R: 255, A: 255}, {G: 255, A: 255}, {B: 255, A: 255}, {R: 255, G: 136, A: 255}}
+	tests := []struct {
+		name  string
+		style Style
+		level int
+		want  string
+	}{
+		{"no color", Style{}, 3, ""},
+		{"true color", Style{Color: TrueColor}, 3, "\x1b[38;2;57;211;83m"},
+		{"256 colors", Style{Color: Color256}, 3, "\x1b[38;5;77m"},
+		{"16 colors", Style{Color: Color16}, 3, "\x1b[32m"},
+		{"16 colors of the faintest level", Style{Color: Color16}, 0, "\x1b[32m"},
+		{"custom palette", Style{Color: TrueColor, Palette: custom}, 3, "\x1b[38;2;255;136;0m"},
+		{"custom palette in 256 colors", Style{Color: Color256, Palette: custom}, 0, "\x1b[38;5;196m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.sequence(tt.level); got != tt.want {
+				t.Errorf("sequence(%d) = %q, want %q", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestANSI16(t *testing.T) {
+	tests := []struct {
+		c    color.RGBA
+		want int
+	}{
+		{color.RGBA{R: 250, G: 10, B: 10}, 91},
+		{color.RGBA{R: 200, G: 200, B: 10}, 33},
+		{color.RGBA{R: 250, G: 250, B: 250}, 97},
+		{color.RGBA{R: 10, G: 10, B: 10}, 31},
+	}
+	for _, tt := range tests {
+		if got := ansi16(tt.c); got != tt.want {
+			t.Errorf("ansi16(%v) = %d, want %d", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateASCIIStyled(t *testing.T) {
+	grid := makeTestGrid(3, 7)
+	plain, err := GenerateASCIIPeriod(grid, "testuser", "2024", false, true)
+	if err != nil {
+		t.Fatalf("GenerateASCIIPeriod() error = %v", err)
+	}
+
+	for _, mode := range []ColorMode{NoColor, Color16, Color256, TrueColor} {
+		colored, err := GenerateASCIIStyled(grid, "testuser", "2024", false, true, Style{Color: mode})
+		if err != nil {
+			t.Fatalf("GenerateASCIIStyled() error = %v", err)
+		}
+		if stripColors(colored) != plain {
+			t.Errorf("GenerateASCIIStyled() in mode %d = %q, want the plain preview colored", mode, colored)
+		}
+		if hasColors := strings.Contains(colored, "\x1b["); hasColors != (mode != NoColor) {
+			t.Errorf("GenerateASCIIStyled() in mode %d colored the preview: %v", mode, hasColors)
+		}
+		for _, line := range strings.Split(colored, "\n") {
+			if strings.Contains(line, "\x1b[") && !strings.HasSuffix(line, resetSequence) {
+				t.Errorf("GenerateASCIIStyled() line %q leaves its color on", line)
+			}
+		}
+	}
+}
+
+func TestStripColors(t *testing.T) {
+	tests := map[string]string{
+		"plain":                            "plain",
+		"\x1b[38;2;1;2;3m▓▓\x1b[0m ░":      "▓▓ ░",
+		"\x1b[32m░\x1b[0m\x1b[92m▓\x1b[0m": "░▓",
+		"unterminated \x1b[38;5":           "unterminated \x1b[38;5",
+	}
+	for s, want := range tests {
+		if got := stripColors(s); got != want {
+			t.Errorf("stripColors(%q) = %q, want %q", s, got, want)
+		}
+		if got := visibleWidth(s); got != len([]rune(want)) {
+			t.Errorf("visibleWidth(%q) = %d, want %d", s, got, len([]rune(want)))
+		}
+	}
+}
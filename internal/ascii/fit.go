@@ -0,0 +1,58 @@
+package ascii
+
+import (
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// weeksPerColumn returns how many weeks each column of a preview of weeks
+// weeks shows, for it to be at most width columns wide: 1 when it fits, or
+// when width is 0 for no limit.
+func weeksPerColumn(weeks, width int) int {
+	if width <= 0 || weeks <= width {
+		return 1
+	}
+	return (weeks + width - 1) / width
+}
+
+// mergeWeeks compresses a contribution grid to fewer columns by summing each
+// run of n weeks into one, day by day. A merged day is in the future only when
+// all of the days it sums are.
+func mergeWeeks(grid [][]types.ContributionDay, n int, now time.Time) [][]types.ContributionDay {
+	if n <= 1 {
+		return grid
+	}
+	merged := make([][]types.ContributionDay, 0, (len(grid)+n-1)/n)
+	for start := 0; start < len(grid); start += n {
+		var column []types.ContributionDay
+		for _, week := range grid[start:min(start+n, len(grid))] {
+			for i, day := range week {
+				if i == len(column) {
+					column = append(column, day)
+					continue
+				}
+				if day.IsAfter(now) {
+					continue
+				}
+				if column[i].IsAfter(now) {
+					column[i] = day
+					continue
+				}
+				column[i].ContributionCount += day.ContributionCount
+			}
+		}
+		merged = append(merged, column)
+	}
+	return merged
+}
+
+// headerWidth is the width of the widest line of HeaderTemplate.
+func headerWidth() int {
+	width := 0
+	for _, line := range strings.Split(HeaderTemplate, "\n") {
+		width = max(width, visibleWidth(line))
+	}
+	return width
+}
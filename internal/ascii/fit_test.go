@@ -0,0 +1,66 @@
+package ascii
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWeeksPerColumn(t *testing.T) {
+	tests := []struct {
+		weeks, width int
+		want         int
+	}{
+		{53, 0, 1},
+		{53, 80, 1},
+		{53, 53, 1},
+		{53, 52, 2},
+		{53, 27, 2},
+		{53, 26, 3},
+		{53, 1, 53},
+	}
+	for _, tt := range tests {
+		if got := weeksPerColumn(tt.weeks, tt.width); got != tt.want {
+			t.Errorf("weeksPerColumn(%d, %d) = %d, want %d", tt.weeks, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestMergeWeeks(t *testing.T) {
+	now := time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)
+	day := func(date string, count int) types.ContributionDay {
+		return types.ContributionDay{Date: date, ContributionCount: count}
+	}
+	grid := [][]types.ContributionDay{
+		{day("2024-01-01", 1), day("2024-01-02", 2)},
+		{day("2024-01-08", 3), day("2024-01-09", 4)},
+		{day("2024-01-15", 5), day("2024-01-22", 7)},
+		{day("2024-01-29", 9), day("2024-01-30", 9)},
+		{day("2024-02-05", 9)},
+	}
+
+	got := mergeWeeks(grid, 2, now)
+	want := [][]types.ContributionDay{
+		{day("2024-01-01", 4), day("2024-01-02", 6)},
+		{day("2024-01-15", 5), day("2024-01-22", 7)}, // Future days are only kept when no past day replaces them
+		{day("2024-02-05", 9)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("mergeWeeks() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("mergeWeeks() column %d = %v, want %v", i, got[i], want[i])
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Errorf("mergeWeeks() column %d day %d = %v, want %v", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+
+	if got := mergeWeeks(grid, 1, now); len(got) != len(grid) {
+		t.Errorf("mergeWeeks() of one week per column = %d columns, want %d", len(got), len(grid))
+	}
+}
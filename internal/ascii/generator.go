@@ -17,14 +17,39 @@ var ErrInvalidGrid = errors.New("invalid contribution grid")
 // It returns the generated ASCII art as a string and an error if the operation fails.
 // When includeHeader is true, the output includes the header template.
 func GenerateASCII(contributionGrid [][]types.ContributionDay, username string, year int, includeHeader bool, includeUserInfo bool) (string, error) {
+	return GenerateASCIIPeriod(contributionGrid, username, fmt.Sprintf("%d", year), includeHeader, includeUserInfo)
+}
+
+// GenerateASCIIPeriod is GenerateASCII for a grid covering a period other than
+// a whole year, such as a few months, named by period below the user.
+func GenerateASCIIPeriod(contributionGrid [][]types.ContributionDay, username, period string, includeHeader bool, includeUserInfo bool) (string, error) {
+	return GenerateASCIIStyled(contributionGrid, username, period, includeHeader, includeUserInfo, Style{})
+}
+
+// GenerateASCIIStyled is GenerateASCIIPeriod coloring the blocks of each day
+// with ANSI sequences in the style, by the day's contribution level. When the
+// weeks do not fit in the style's width, each column sums as many weeks as it
+// takes for them to, and the header is left out if it does not fit either.
+func GenerateASCIIStyled(contributionGrid [][]types.ContributionDay, username, period string, includeHeader bool, includeUserInfo bool, style Style) (string, error) {
 	if len(contributionGrid) == 0 {
 		return "", ErrInvalidGrid
 	}
 
+	// Get current time for future date comparison
+	now := time.Now()
+
+	// Compress the weeks into fewer columns when they do not fit
+	weeks := weeksPerColumn(len(contributionGrid), style.Width)
+	contributionGrid = mergeWeeks(contributionGrid, weeks, now)
+	textWidth := GridWidth
+	if style.Width > 0 {
+		textWidth = min(textWidth, style.Width)
+	}
+
 	var buffer bytes.Buffer
 
-	// Only include header if requested
-	if includeHeader {
+	// Only include header if requested, and if it fits
+	if includeHeader && headerWidth() <= textWidth {
 		for _, line := range strings.Split(HeaderTemplate, "\n") {
 			buffer.WriteString(line + "\n")
 		}
@@ -41,15 +66,19 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 		}
 	}
 
-	// Initialize the ASCII grid (7 rows x 53 columns)
-	asciiGrid := make([][]rune, 7)
+	// Initialize the ASCII grid, a row per day of the longest week (7 rows x 53
+	// columns for a year, fewer rows when weekends are left out)
+	rows := 0
+	for _, week := range contributionGrid {
+		rows = max(rows, min(len(week), 7))
+	}
+	asciiGrid := make([][]rune, rows)
+	levels := make([][]int, rows) // Contribution level of each block, -1 for those left uncolored
 	for i := range asciiGrid {
 		asciiGrid[i] = make([]rune, len(contributionGrid))
+		levels[i] = make([]int, len(contributionGrid))
 	}
 
-	// Get current time for future date comparison
-	now := time.Now()
-
 	// Process each week
 	for weekIdx, week := range contributionGrid {
 		// Update to receive nonZeroCount
@@ -63,6 +92,7 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 		}
 		for dayIdx := 0; dayIdx < maxDayIdx; dayIdx++ {
 			day := sortedDays[dayIdx]
+			levels[dayIdx][weekIdx] = -1 // #nosec G602 -- bounds checked by maxDayIdx calculation above
 			if day.ContributionCount == -1 {
 				asciiGrid[dayIdx][weekIdx] = FutureBlock // #nosec G602 -- bounds checked by maxDayIdx calculation above
 			} else {
@@ -71,28 +101,55 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 					normalized = float64(day.ContributionCount) / float64(maxContributions)
 				}
 				asciiGrid[dayIdx][weekIdx] = getBlock(normalized, dayIdx, nonZeroCount) // #nosec G602 -- bounds checked by maxDayIdx calculation above
+				if normalized > 0 {
+					levels[dayIdx][weekIdx] = level(normalized) // #nosec G602 -- bounds checked by maxDayIdx calculation above
+				}
 			}
 		}
 	}
 
 	// Write the contribution grid
 	for i := len(asciiGrid) - 1; i >= 0; i-- {
-		for _, ch := range asciiGrid[i] {
-			buffer.WriteRune(ch)
-		}
-		buffer.WriteRune('\n')
+		writeRow(&buffer, asciiGrid[i], levels[i], style)
 	}
 
 	if includeUserInfo {
 		// Add centered user info below
 		buffer.WriteString("\n")
-		buffer.WriteString(centerText(username))
-		buffer.WriteString(centerText(fmt.Sprintf("%d", year)))
+		buffer.WriteString(centerText(username, textWidth))
+		buffer.WriteString(centerText(period, textWidth))
+		if weeks > 1 {
+			buffer.WriteString(centerText(fmt.Sprintf("%d weeks per column", weeks), textWidth))
+		}
 	}
 
 	return buffer.String(), nil
 }
 
+// writeRow writes a row of blocks and a line break, coloring the runs of
+// blocks of the same level in the style.
+func writeRow(buffer *bytes.Buffer, row []rune, levels []int, style Style) {
+	current := ""
+	for i, ch := range row {
+		sequence := ""
+		if ch != 0 && levels[i] >= 0 {
+			sequence = style.sequence(levels[i])
+		}
+		if sequence != current {
+			if current != "" {
+				buffer.WriteString(resetSequence)
+			}
+			buffer.WriteString(sequence)
+			current = sequence
+		}
+		buffer.WriteRune(ch)
+	}
+	if current != "" {
+		buffer.WriteString(resetSequence)
+	}
+	buffer.WriteRune('\n')
+}
+
 // sortContributionDays sorts the contribution days within a week.
 // It places non-zero contributions first, followed by zero contributions, and future dates last.
 func sortContributionDays(week []types.ContributionDay, now time.Time) ([]types.ContributionDay, int) {
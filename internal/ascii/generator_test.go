@@ -79,6 +79,61 @@ func TestGenerateASCII(t *testing.T) {
 	}
 }
 
+func TestGenerateASCIIPeriod(t *testing.T) {
+	result, err := GenerateASCIIPeriod(makeTestGrid(3, 7), "testuser", "2024.01-06", false, true)
+	if err != nil {
+		t.Fatalf("GenerateASCIIPeriod() error = %v", err)
+	}
+	if !strings.Contains(result, "2024.01-06") {
+		t.Error("Generated ASCII should contain the period")
+	}
+}
+
+func TestGenerateASCIIWeekdays(t *testing.T) {
+	result, err := GenerateASCII(makeTestGrid(3, 5), "testuser", 2023, false, false)
+	if err != nil {
+		t.Fatalf("GenerateASCII() error = %v", err)
+	}
+	if rows := strings.Count(result, "\n"); rows != 5 {
+		t.Errorf("GenerateASCII() of five day weeks drew %d rows, want 5", rows)
+	}
+}
+
+func TestGenerateASCIIWidth(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int
+		wantHeader bool
+		wantNote   string
+	}{
+		{"no limit", 0, true, ""},
+		{"wide terminal", 120, true, ""},
+		{"two weeks per column", 30, false, "2 weeks per column"},
+		{"three weeks per column", 20, false, "3 weeks per column"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GenerateASCIIStyled(makeTestGrid(53, 7), "testuser", "2024", true, true, Style{Width: tt.width})
+			if err != nil {
+				t.Fatalf("GenerateASCIIStyled() error = %v", err)
+			}
+			if got := strings.Contains(result, HeaderTemplate); got != tt.wantHeader {
+				t.Errorf("GenerateASCIIStyled() includes header = %v, want %v", got, tt.wantHeader)
+			}
+			if tt.wantNote != "" && !strings.Contains(result, tt.wantNote) {
+				t.Errorf("GenerateASCIIStyled() does not note %q", tt.wantNote)
+			}
+			if tt.width > 0 {
+				for _, line := range strings.Split(result, "\n") {
+					if visibleWidth(line) > tt.width {
+						t.Errorf("GenerateASCIIStyled() line %q is wider than %d columns", line, tt.width)
+					}
+				}
+			}
+		})
+	}
+}
+
 // Helper function to create test grid
 func makeTestGrid(weeks, days int) [][]types.ContributionDay {
 	grid := make([][]types.ContributionDay, weeks)
@@ -25,17 +25,17 @@ const HeaderTemplate = `
                     |___/
 `
 
-// centerText centers the given text within the GridWidth.
+// centerText centers the given text within width columns.
 // It accounts for wide Unicode characters and ensures the text fits within
-// the specified width. If the text is longer than GridWidth, it will be truncated.
-func centerText(text string) string {
+// the specified width. If the text is longer than width, it will be truncated.
+func centerText(text string, width int) string {
 	visualWidth := len(text)
 
-	if visualWidth >= GridWidth {
-		return text[:GridWidth] + "\n"
+	if visualWidth >= width {
+		return text[:width] + "\n"
 	}
 
-	totalPadding := GridWidth - visualWidth
+	totalPadding := width - visualWidth
 
 	if totalPadding <= 1 {
 		return text + "\n"
@@ -46,3 +46,28 @@ func centerText(text string) string {
 
 	return strings.Repeat(" ", leftPadding) + text + strings.Repeat(" ", rightPadding) + "\n"
 }
+
+// SideBySide sets two blocks of text, such as the previews of two skylines,
+// next to each other, with gap spaces between the widest line of left and the
+// lines of right. Colors take no room. The shorter block is padded with blank
+// lines at the top, so the two end on the same line.
+func SideBySide(left, right string, gap int) string {
+	leftLines := strings.Split(strings.TrimRight(left, "\n"), "\n")
+	rightLines := strings.Split(strings.TrimRight(right, "\n"), "\n")
+
+	width := 0
+	for _, line := range leftLines {
+		width = max(width, visibleWidth(line))
+	}
+	rows := max(len(leftLines), len(rightLines))
+	leftLines = append(make([]string, rows-len(leftLines)), leftLines...)
+	rightLines = append(make([]string, rows-len(rightLines)), rightLines...)
+
+	var b strings.Builder
+	for i := range rows {
+		padding := width - visibleWidth(leftLines[i]) + gap
+		b.WriteString(strings.TrimRight(leftLines[i]+strings.Repeat(" ", padding)+rightLines[i], " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -35,9 +35,51 @@ func TestCenterText(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := centerText(tt.input)
+			result := centerText(tt.input, GridWidth)
 			if result != tt.expected {
-				t.Errorf("centerText(%q) = %q, want %q", tt.input, result, tt.expected)
+				t.Errorf("centerText(%q, GridWidth) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     string
+		right    string
+		expected string
+	}{
+		{
+			name:     "same height",
+			left:     "░░\n▓▓▓\n",
+			right:    "a\nb\n",
+			expected: "░░    a\n▓▓▓   b\n",
+		},
+		{
+			name:     "shorter left",
+			left:     "x\n",
+			right:    "a\nb\nc\n",
+			expected: "    a\n    b\nx   c\n",
+		},
+		{
+			name:     "colored left",
+			left:     "\x1b[32m░░\x1b[0m\n▓▓▓\n",
+			right:    "a\nb\n",
+			expected: "\x1b[32m░░\x1b[0m    a\n▓▓▓   b\n",
+		},
+		{
+			name:     "shorter right",
+			left:     "x\ny\n",
+			right:    "a\n",
+			expected: "x\ny   a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := SideBySide(tt.left, tt.right, 3); result != tt.expected {
+				t.Errorf("SideBySide() = %q, want %q", result, tt.expected)
 			}
 		})
 	}
@@ -0,0 +1,104 @@
+// Package buildinfo describes the build of the CLI: its version, the commit
+// it was built from, when, and with which Go toolchain.
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Values stamped at build time, overriding those Go records in the binary:
+//
+//	go build -ldflags "-X github.com/github/gh-skyline/internal/buildinfo.Version=v1.2.3 \
+//	  -X github.com/github/gh-skyline/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/github/gh-skyline/internal/buildinfo.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version string // Semantic version, such as v1.2.3
+	Commit  string // SHA of the commit built
+	Date    string // When the binary was built, in RFC 3339
+)
+
+// DevVersion is the version of builds from a working copy without a tag.
+const DevVersion = "dev"
+
+// shortCommit is the number of characters of a commit SHA shown.
+const shortCommit = 12
+
+// Info is what is known of a build.
+type Info struct {
+	Version   string // Semantic version, DevVersion when unknown
+	Commit    string // SHA of the commit built, empty when unknown
+	Date      string // When the build or its commit was made, empty when unknown
+	Modified  bool   // Whether the working copy had uncommitted changes
+	GoVersion string // Go toolchain the binary was built with
+	Platform  string // Operating system and architecture, such as linux/amd64
+}
+
+// Get returns the build's information: the values stamped at build time, or
+// else what Go recorded from the module and version control. Go records the
+// version of a tagged commit and of go install, and the commit and its time of
+// builds from a git checkout.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = DevVersion
+	}
+	return info
+}
+
+// ShortCommit returns the first characters of the commit SHA, marked dirty
+// when the working copy was modified, or empty when the commit is unknown.
+func (i Info) ShortCommit() string {
+	if i.Commit == "" {
+		return ""
+	}
+	commit := i.Commit
+	if len(commit) > shortCommit {
+		commit = commit[:shortCommit]
+	}
+	if i.Modified {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// String describes the build over several lines, leaving out what is unknown.
+func (i Info) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gh-skyline %s\n", i.Version)
+	if commit := i.ShortCommit(); commit != "" {
+		fmt.Fprintf(&b, "commit: %s\n", commit)
+	}
+	if i.Date != "" {
+		fmt.Fprintf(&b, "built: %s\n", i.Date)
+	}
+	fmt.Fprintf(&b, "go: %s %s\n", i.GoVersion, i.Platform)
+	return b.String()
+}
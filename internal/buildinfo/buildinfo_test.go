@@ -0,0 +1,59 @@
+package buildinfo
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	defer func(version, commit, date string) { Version, Commit, Date = version, commit, date }(Version, Commit, Date)
+
+	Version, Commit, Date = "", "", ""
+	info := Get()
+	if info.Version == "" {
+		t.Error("Get() returned no version, want at least the development version")
+	}
+	if info.GoVersion != runtime.Version() || info.Platform != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("Get() toolchain = %s %s, want %s %s/%s", info.GoVersion, info.Platform, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	}
+
+	Version, Commit, Date = "v1.2.3", "0123456789abcdef0123", "2026-01-02T03:04:05Z"
+	info = Get()
+	if info.Version != "v1.2.3" || info.Commit != Commit || info.Date != Date {
+		t.Errorf("Get() = %+v, want the stamped values", info)
+	}
+}
+
+func TestInfoShortCommit(t *testing.T) {
+	tests := []struct {
+		name string
+		info Info
+		want string
+	}{
+		{"unknown", Info{}, ""},
+		{"full", Info{Commit: "0123456789abcdef0123"}, "0123456789ab"},
+		{"short", Info{Commit: "abc123"}, "abc123"},
+		{"modified", Info{Commit: "0123456789abcdef0123", Modified: true}, "0123456789ab-dirty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.ShortCommit(); got != tt.want {
+				t.Errorf("ShortCommit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	full := Info{Version: "v1.2.3", Commit: "0123456789abcdef", Date: "2026-01-02T03:04:05Z", GoVersion: "go1.25.0", Platform: "linux/amd64"}
+	want := "gh-skyline v1.2.3\ncommit: 0123456789ab\nbuilt: 2026-01-02T03:04:05Z\ngo: go1.25.0 linux/amd64\n"
+	if got := full.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	dev := Info{Version: DevVersion, GoVersion: "go1.25.0", Platform: "linux/amd64"}
+	if got := dev.String(); strings.Contains(got, "commit") || strings.Contains(got, "built") {
+		t.Errorf("String() of an unknown commit = %q, want it left out", got)
+	}
+}
@@ -0,0 +1,147 @@
+// Package cache keeps contribution data fetched from GitHub on disk, so
+// generating a model of the same years again does not need the API.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// DefaultTTL is how long the contributions of a year still in progress are
+// reused before they are fetched again.
+const DefaultTTL = time.Hour
+
+// yearSettled is how long after a year ends in UTC its contributions can no
+// longer change, allowing for users whose time zone is a day behind UTC.
+const yearSettled = 24 * time.Hour
+
+// Cache stores the contributions of each user and year, keyed by the GitHub
+// host they were fetched from. Contributions fetched after their year was over
+// never go stale; those of a year still in progress are reused for the TTL.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// entry is a cached response along with when it was fetched.
+type entry struct {
+	FetchedAt time.Time                    `json:"fetchedAt"`
+	Response  *types.ContributionsResponse `json:"response"`
+}
+
+// Dir returns the directory the cache is kept in by default: gh-skyline in the
+// user's cache directory, which is $XDG_CACHE_HOME or ~/.cache on Linux.
+func Dir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.New(errors.IOError, "failed to find the user cache directory", err)
+	}
+	return filepath.Join(dir, "gh-skyline"), nil
+}
+
+// New returns a cache kept in dir that reuses the contributions of a year
+// still in progress for ttl, or never when ttl is zero.
+func New(dir string, ttl time.Duration) (*Cache, error) {
+	if dir == "" {
+		return nil, errors.New(errors.ValidationError, "cache directory cannot be empty", nil)
+	}
+	if ttl < 0 {
+		return nil, errors.New(errors.ValidationError, "cache TTL cannot be negative", nil)
+	}
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+// path returns the file the contributions of a user and year are kept in.
+// Hosts and usernames are case-insensitive, so both are lowercased.
+func (c *Cache) path(host, username string, year int) string {
+	return filepath.Join(c.dir, "contributions",
+		url.PathEscape(strings.ToLower(host)),
+		url.PathEscape(strings.ToLower(username)),
+		fmt.Sprintf("%d.json", year))
+}
+
+// Get returns the cached contributions of a user and year, and whether they
+// were found and are still fresh.
+func (c *Cache) Get(host, username string, year int) (*types.ContributionsResponse, bool, error) {
+	e, err := c.read(host, username, year)
+	if e == nil || err != nil {
+		return nil, false, err
+	}
+
+	settled := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Add(yearSettled)
+	if !e.FetchedAt.Before(settled) || c.now().Sub(e.FetchedAt) < c.ttl {
+		return e.Response, true, nil
+	}
+	return nil, false, nil
+}
+
+// GetStale returns the cached contributions of a user and year however long
+// ago they were fetched, and whether they were found, for when they cannot be
+// fetched again.
+func (c *Cache) GetStale(host, username string, year int) (*types.ContributionsResponse, bool, error) {
+	e, err := c.read(host, username, year)
+	if e == nil || err != nil {
+		return nil, false, err
+	}
+	return e.Response, true, nil
+}
+
+// read returns the cache entry of a user and year, or nil when there is none.
+func (c *Cache) read(host, username string, year int) (*entry, error) {
+	data, err := os.ReadFile(c.path(host, username, year))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read cached contributions", err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("cached contributions for %d are corrupt", year), err)
+	}
+	if e.Response == nil {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// Put stores the contributions of a user and year, fetched now. The file is
+// replaced in one step, so a concurrent run never reads it half written.
+func (c *Cache) Put(host, username string, year int, response *types.ContributionsResponse) error {
+	path := c.path(host, username, year)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.New(errors.IOError, "failed to create cache directory", err)
+	}
+
+	data, err := json.Marshal(entry{FetchedAt: c.now().UTC(), Response: response})
+	if err != nil {
+		return errors.New(errors.IOError, "failed to encode contributions", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create cache file", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.New(errors.IOError, "failed to write cache file", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to close cache file", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.New(errors.IOError, "failed to write cache file", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{"default", t.TempDir(), DefaultTTL, false},
+		{"no ttl", t.TempDir(), 0, false},
+		{"empty dir", "", DefaultTTL, true},
+		{"negative ttl", t.TempDir(), -time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.dir, tt.ttl); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+	t.Setenv("HOME", "/tmp/home")
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	if filepath.Base(dir) != "gh-skyline" {
+		t.Errorf("Dir() = %s, want a gh-skyline directory", dir)
+	}
+}
+
+func TestCacheGetPut(t *testing.T) {
+	midYear := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	newYear := time.Date(2025, time.January, 1, 6, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		year      int
+		fetchedAt time.Time
+		ttl       time.Duration
+		elapsed   time.Duration
+		wantFound bool
+	}{
+		{"current year within ttl", 2024, midYear, time.Hour, 30 * time.Minute, true},
+		{"current year past ttl", 2024, midYear, time.Hour, 2 * time.Hour, false},
+		{"current year without ttl", 2024, midYear, 0, 0, false},
+		{"past year", 2023, midYear, time.Hour, 365 * 24 * time.Hour, true},
+		{"past year without ttl", 2023, midYear, 0, 0, true},
+		{"year ended in UTC but not everywhere", 2024, newYear, time.Hour, 2 * time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(t.TempDir(), tt.ttl)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			c.now = func() time.Time { return tt.fetchedAt }
+
+			response := fixtures.GenerateContributionsResponse("mona", tt.year)
+			if err := c.Put("github.com", "mona", tt.year, response); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+
+			c.now = func() time.Time { return tt.fetchedAt.Add(tt.elapsed) }
+			got, found, err := c.Get("github.com", "mona", tt.year)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("Get() found = %v, want %v", found, tt.wantFound)
+			}
+			if found && !reflect.DeepEqual(got, response) {
+				t.Errorf("Get() = %+v, want the stored response", got)
+			}
+
+			// However old, the entry is there for when it cannot be fetched again
+			stale, found, err := c.GetStale("github.com", "mona", tt.year)
+			if err != nil || !found || !reflect.DeepEqual(stale, response) {
+				t.Errorf("GetStale() = %+v, %v, %v, want the stored response", stale, found, err)
+			}
+		})
+	}
+}
+
+func TestCacheKeys(t *testing.T) {
+	c, err := New(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := c.Put("github.com", "Mona", 2023, fixtures.GenerateContributionsResponse("Mona", 2023)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		host      string
+		username  string
+		year      int
+		wantFound bool
+	}{
+		{"same key", "github.com", "Mona", 2023, true},
+		{"username case", "github.com", "mona", 2023, true},
+		{"other year", "github.com", "Mona", 2022, false},
+		{"other user", "github.com", "octocat", 2023, false},
+		{"other host", "ghe.example.com", "Mona", 2023, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, found, err := c.Get(tt.host, tt.username, tt.year)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if found != tt.wantFound {
+				t.Errorf("Get() found = %v, want %v", found, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestCacheCorrupt(t *testing.T) {
+	c, err := New(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	path := c.path("github.com", "mona", 2023)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found, err := c.Get("github.com", "mona", 2023); err == nil || found {
+		t.Errorf("Get() found = %v, error = %v, want an error", found, err)
+	}
+}
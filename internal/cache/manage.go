@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Item describes the cached contributions of one user and year.
+type Item struct {
+	Host      string    // GitHub host the contributions were fetched from
+	Username  string    // User the contributions are of, lowercased
+	Year      int       // Year the contributions are of
+	Size      int64     // Size of the cache file in bytes
+	FetchedAt time.Time // When the contributions were fetched, zero when the file is corrupt
+}
+
+// Dir returns the directory the cache is kept in.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// List returns the cached contributions of username and year, of every user
+// when username is empty and every year when year is zero, ordered by host,
+// user and year.
+func (c *Cache) List(username string, year int) ([]Item, error) {
+	var items []Item
+	root := filepath.Join(c.dir, "contributions")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && path == root {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		item, ok := c.item(root, path)
+		if !ok || (username != "" && item.Username != strings.ToLower(username)) || (year != 0 && item.Year != year) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		item.Size = info.Size()
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to list cached contributions", err)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Username != b.Username {
+			return a.Username < b.Username
+		}
+		return a.Year < b.Year
+	})
+	return items, nil
+}
+
+// item returns the item kept in the file at path under root, and false when
+// the file is not a cache entry, such as one still being written by Put.
+func (c *Cache) item(root, path string) (Item, bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return Item{}, false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 3 || !strings.HasSuffix(parts[2], ".json") {
+		return Item{}, false
+	}
+	host, hostErr := url.PathUnescape(parts[0])
+	username, userErr := url.PathUnescape(parts[1])
+	year, yearErr := strconv.Atoi(strings.TrimSuffix(parts[2], ".json"))
+	if hostErr != nil || userErr != nil || yearErr != nil {
+		return Item{}, false
+	}
+
+	item := Item{Host: host, Username: username, Year: year}
+	if data, err := os.ReadFile(path); err == nil {
+		var e entry
+		if json.Unmarshal(data, &e) == nil {
+			item.FetchedAt = e.FetchedAt
+		}
+	}
+	return item, true
+}
+
+// Remove deletes the cached contributions of username and year, of every user
+// when username is empty and every year when year is zero, returning what was
+// removed. Directories left empty are removed too.
+func (c *Cache) Remove(username string, year int) ([]Item, error) {
+	items, err := c.List(username, year)
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range items {
+		path := c.path(item.Host, item.Username, item.Year)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return items[:i], errors.New(errors.IOError, "failed to remove cached contributions", err)
+		}
+		// Removing a directory fails while it still holds other entries
+		userDir := filepath.Dir(path)
+		if os.Remove(userDir) == nil {
+			_ = os.Remove(filepath.Dir(userDir))
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+)
+
+// newTestCache returns a cache holding mona's 2023 and 2024 and hubot's 2024
+// contributions from github.com, and mona's 2024 from a GitHub Enterprise host.
+func newTestCache(t *testing.T) *Cache {
+	t.Helper()
+	c, err := New(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	entries := []struct {
+		host     string
+		username string
+		year     int
+	}{
+		{"github.com", "Mona", 2023},
+		{"github.com", "mona", 2024},
+		{"github.com", "hubot", 2024},
+		{"ghe.example.com", "mona", 2024},
+	}
+	for _, e := range entries {
+		if err := c.Put(e.host, e.username, e.year, fixtures.GenerateContributionsResponse(e.username, e.year)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	return c
+}
+
+// itemKeys returns each item as host/user/year, in order.
+func itemKeys(items []Item) []string {
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, fmt.Sprintf("%s/%s/%d", item.Host, item.Username, item.Year))
+	}
+	return keys
+}
+
+func TestCacheList(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		year     int
+		want     []string
+	}{
+		{"everything", "", 0, []string{"ghe.example.com/mona/2024", "github.com/hubot/2024", "github.com/mona/2023", "github.com/mona/2024"}},
+		{"user", "MONA", 0, []string{"ghe.example.com/mona/2024", "github.com/mona/2023", "github.com/mona/2024"}},
+		{"year", "", 2023, []string{"github.com/mona/2023"}},
+		{"user and year", "hubot", 2024, []string{"github.com/hubot/2024"}},
+		{"nothing", "octocat", 0, nil},
+	}
+
+	c := newTestCache(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := c.List(tt.username, tt.year)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := itemKeys(items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+			for _, item := range items {
+				if item.Size == 0 || item.FetchedAt.IsZero() {
+					t.Errorf("List() item %+v lacks its size or fetch time", item)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheListEmpty(t *testing.T) {
+	c, err := New(filepath.Join(t.TempDir(), "missing"), time.Hour)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if items, err := c.List("", 0); err != nil || len(items) != 0 {
+		t.Errorf("List() of a cache never written = %v, %v, want nothing", items, err)
+	}
+}
+
+func TestCacheRemove(t *testing.T) {
+	c := newTestCache(t)
+	removed, err := c.Remove("mona", 2024)
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Remove() removed %v, want mona's 2024 from both hosts", itemKeys(removed))
+	}
+	if _, found, _ := c.GetStale("github.com", "mona", 2023); !found {
+		t.Error("Remove() removed another year of the user")
+	}
+	if _, err := os.Stat(filepath.Join(c.Dir(), "contributions", "ghe.example.com")); !os.IsNotExist(err) {
+		t.Errorf("Remove() left the emptied host directory behind: %v", err)
+	}
+
+	if _, err := c.Remove("", 0); err != nil {
+		t.Fatalf("Remove() of everything error = %v", err)
+	}
+	if items, err := c.List("", 0); err != nil || len(items) != 0 {
+		t.Errorf("List() after removing everything = %v, %v, want nothing", items, err)
+	}
+}
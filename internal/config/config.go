@@ -0,0 +1,124 @@
+// Package config reads the settings file holding a user's defaults for the
+// CLI's flags, so frequently used flags need not be typed every time.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv is the environment variable naming a settings file to read in place
+// of the default one.
+const FileEnv = "GH_SKYLINE_CONFIG"
+
+// envPrefix starts the environment variable holding each setting.
+const envPrefix = "GH_SKYLINE_"
+
+// Settings are the values of a settings file, by key. Values are formatted as
+// they would be given on the command line, with lists comma separated.
+type Settings map[string]string
+
+// Path returns the settings file read: the one named by FileEnv, or else
+// config.yml in gh-skyline under $XDG_CONFIG_HOME, or ~/.config when that is
+// not set, as the gh CLI keeps its own on every platform.
+func Path() (string, error) {
+	if path := os.Getenv(FileEnv); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errors.New(errors.IOError, "failed to find the home directory", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gh-skyline", "config.yml"), nil
+}
+
+// Load reads the settings file at path, a YAML mapping of keys to strings,
+// numbers, booleans or lists of them. A missing file holds no settings.
+func Load(path string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Settings{}, nil
+	}
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read the config file", err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("config file %s is not a YAML mapping", path), err)
+	}
+	settings := make(Settings, len(raw))
+	for key, value := range raw {
+		formatted, err := formatValue(value)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid value of %s in config file %s", key, path), err)
+		}
+		settings[key] = formatted
+	}
+	return settings, nil
+}
+
+// formatValue formats a YAML value as it would be given on the command line.
+func formatValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			formatted, err := formatValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = formatted
+		}
+		return strings.Join(items, ","), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("%v is not a string, number, boolean or list", value)
+	}
+}
+
+// Keys returns the keys of the settings, sorted.
+func (s Settings) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvName returns the environment variable overriding the setting key, such
+// as GH_SKYLINE_BASE_WIDTH for base-width.
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// Lookup returns the value of the setting key, from its environment variable
+// when set, or else from the settings, and whether it has one.
+func (s Settings) Lookup(key string) (string, bool) {
+	if value, ok := os.LookupEnv(EnvName(key)); ok {
+		return value, true
+	}
+	value, ok := s[key]
+	return value, ok
+}
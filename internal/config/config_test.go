@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	t.Setenv(FileEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	if got, err := Path(); err != nil || got != filepath.Join("/tmp/xdg", "gh-skyline", "config.yml") {
+		t.Errorf("Path() = %q, %v, want config.yml under XDG_CONFIG_HOME", got, err)
+	}
+
+	t.Setenv(FileEnv, "/tmp/skyline.yml")
+	if got, err := Path(); err != nil || got != "/tmp/skyline.yml" {
+		t.Errorf("Path() = %q, %v, want the file named by %s", got, err, FileEnv)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Settings
+		wantErr bool
+	}{
+		{"empty", "", Settings{}, false},
+		{"strings", "output-dir: ~/models\nhostname: github.example.com\n", Settings{"output-dir": "~/models", "hostname": "github.example.com"}, false},
+		{"numbers", "base-width: 120\nbase-depth: 30.5\n", Settings{"base-width": "120", "base-depth": "30.5"}, false},
+		{"booleans", "no-logo: true\n", Settings{"no-logo": "true"}, false},
+		{"lists", "types: [commits, pulls]\n", Settings{"types": "commits,pulls"}, false},
+		{"not a mapping", "- base-width\n", nil, true},
+		{"nested mapping", "base:\n  width: 120\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := Load(filepath.Join(t.TempDir(), "missing.yml")); err != nil || len(got) != 0 {
+		t.Errorf("Load() of a missing file = %v, %v, want no settings", got, err)
+	}
+}
+
+func TestSettingsKeys(t *testing.T) {
+	settings := Settings{"units": "in", "base-width": "5", "output-dir": "models"}
+	if got, want := settings.Keys(), []string{"base-width", "output-dir", "units"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("base-width"); got != "GH_SKYLINE_BASE_WIDTH" {
+		t.Errorf("EnvName() = %q, want GH_SKYLINE_BASE_WIDTH", got)
+	}
+}
+
+func TestSettingsLookup(t *testing.T) {
+	settings := Settings{"base-width": "120"}
+
+	t.Setenv(EnvName("base-width"), "")
+	if err := os.Unsetenv(EnvName("base-width")); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := settings.Lookup("base-width"); !ok || got != "120" {
+		t.Errorf("Lookup() = %q, %v, want the file's value", got, ok)
+	}
+	if _, ok := settings.Lookup("base-depth"); ok {
+		t.Error("Lookup() of an unset key found a value")
+	}
+
+	t.Setenv(EnvName("base-width"), "150")
+	if got, ok := settings.Lookup("base-width"); !ok || got != "150" {
+		t.Errorf("Lookup() = %q, %v, want the environment variable's value", got, ok)
+	}
+}
@@ -0,0 +1,125 @@
+// Package dataset writes the per-day contribution counts a skyline is built
+// from to JSON and CSV data files, so they can be archived, inspected or
+// processed with other tools, and reads them back to build a skyline from
+// without the GitHub API.
+//
+// A JSON data file holds the username and each day's count:
+//
+//	{
+//	  "username": "mona",
+//	  "days": [
+//	    {"contributionCount": 3, "date": "2024-01-01"},
+//	    ...
+//	  ]
+//	}
+//
+// A CSV data file has a header row and one row per day:
+//
+//	date,contributionCount
+//	2024-01-01,3
+//
+// Days are listed in date order, as GitHub's contribution calendar lists them.
+package dataset
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Format identifies a data file format.
+type Format string
+
+// Supported data file formats.
+const (
+	FormatJSON Format = "json" // Username and days as a JSON object
+	FormatCSV  Format = "csv"  // One row of date and count per day
+)
+
+// formats lists the supported formats in the order they are presented to users.
+var formats = []Format{FormatJSON, FormatCSV}
+
+// Formats returns the names of all supported data file formats.
+func Formats() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// FormatOf returns the format of a data file from its extension.
+func FormatOf(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	for _, f := range formats {
+		if strings.EqualFold(ext, string(f)) {
+			return f, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("data file %q must end in one of .%s", path, strings.Join(Formats(), ", .")), nil)
+}
+
+// csvHeader is the header row of a CSV data file.
+var csvHeader = []string{"date", "contributionCount"}
+
+// file is the JSON schema of a data file.
+type file struct {
+	Username string                  `json:"username"`
+	Days     []types.ContributionDay `json:"days"`
+}
+
+// Write writes the contributions of a user's years, each a [week][day] grid,
+// to a data file in the format its extension names.
+func Write(path, username string, years [][][]types.ContributionDay) error {
+	format, err := FormatOf(path)
+	if err != nil {
+		return err
+	}
+
+	data := file{Username: username, Days: []types.ContributionDay{}}
+	for _, weeks := range years {
+		for _, week := range weeks {
+			data.Days = append(data.Days, week...)
+		}
+	}
+
+	var encoded []byte
+	switch format {
+	case FormatCSV:
+		encoded, err = encodeCSV(data.Days)
+	default:
+		encoded, err = json.MarshalIndent(data, "", "  ")
+		encoded = append(encoded, '\n')
+	}
+	if err != nil {
+		return errors.New(errors.IOError, "failed to encode contribution data", err)
+	}
+	if err := os.WriteFile(path, encoded, 0o644); err != nil {
+		return errors.New(errors.IOError, "failed to write contribution data", err)
+	}
+	return nil
+}
+
+// encodeCSV encodes days as CSV rows under csvHeader.
+func encodeCSV(days []types.ContributionDay) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		if err := w.Write([]string{day.Date, strconv.Itoa(day.ContributionCount)}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
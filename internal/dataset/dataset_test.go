@@ -0,0 +1,96 @@
+package dataset
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// testYears returns two years of a few days each, as [year][week][day] grids.
+func testYears() [][][]types.ContributionDay {
+	return [][][]types.ContributionDay{
+		{{{ContributionCount: 1, Date: "2023-12-30"}, {ContributionCount: 0, Date: "2023-12-31"}}},
+		{{{ContributionCount: 5, Date: "2024-01-01"}}, {{ContributionCount: 2, Date: "2024-01-07"}}},
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Format
+		wantErr bool
+	}{
+		{"data.json", FormatJSON, false},
+		{"data.csv", FormatCSV, false},
+		{"DATA.CSV", FormatCSV, false},
+		{"dir.json/data", "", true},
+		{"data.txt", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := FormatOf(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FormatOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := Write(path, "mona", testYears()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got file
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("data file is not valid JSON: %v", err)
+	}
+	want := file{Username: "mona", Days: []types.ContributionDay{
+		{ContributionCount: 1, Date: "2023-12-30"},
+		{ContributionCount: 0, Date: "2023-12-31"},
+		{ContributionCount: 5, Date: "2024-01-01"},
+		{ContributionCount: 2, Date: "2024-01-07"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("data file = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := Write(path, "mona", testYears()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date,contributionCount\n2023-12-30,1\n2023-12-31,0\n2024-01-01,5\n2024-01-07,2\n"
+	if string(data) != want {
+		t.Errorf("data file = %q, want %q", data, want)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := Write(filepath.Join(dir, "data.txt"), "mona", testYears()); err == nil {
+		t.Error("Write() of an unknown format error = nil, want an error")
+	}
+	if err := Write(filepath.Join(dir, "missing", "data.json"), "mona", testYears()); err == nil {
+		t.Error("Write() into a missing directory error = nil, want an error")
+	}
+}
@@ -0,0 +1,131 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/transform"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// dateLayout is the layout of the dates in data files.
+const dateLayout = "2006-01-02"
+
+// Data is the contents of a data file.
+type Data struct {
+	Username string                  // User the contributions are of, empty when the file does not say
+	Days     []types.ContributionDay // Each day's count, in date order
+}
+
+// Read reads a data file in the format its extension names. Days may be
+// listed in any order, but each only once.
+func Read(path string) (*Data, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read data file", err)
+	}
+
+	var data Data
+	switch format {
+	case FormatCSV:
+		data.Days, err = decodeCSV(raw)
+	default:
+		var f file
+		if err = json.Unmarshal(raw, &f); err == nil {
+			data = Data{Username: f.Username, Days: f.Days}
+		}
+	}
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid data file %s", path), err)
+	}
+
+	if len(data.Days) == 0 {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("data file %s has no days", path), nil)
+	}
+	for _, day := range data.Days {
+		if err := day.Validate(); err != nil {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid day %q in data file %s", day.Date, path), err)
+		}
+	}
+	slices.SortStableFunc(data.Days, func(a, b types.ContributionDay) int { return strings.Compare(a.Date, b.Date) })
+	for i := 1; i < len(data.Days); i++ {
+		if data.Days[i].Date == data.Days[i-1].Date {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("day %s is listed twice in data file %s", data.Days[i].Date, path), nil)
+		}
+	}
+	return &data, nil
+}
+
+// decodeCSV decodes the rows of a CSV data file. The columns are found by
+// their names in the header row, so other columns are ignored.
+func decodeCSV(raw []byte) ([]types.ContributionDay, error) {
+	r := csv.NewReader(bytes.NewReader(raw))
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row: %w", err)
+	}
+	dateCol, countCol := slices.Index(header, csvHeader[0]), slices.Index(header, csvHeader[1])
+	if dateCol < 0 || countCol < 0 {
+		return nil, fmt.Errorf("header row must name the %s columns", strings.Join(csvHeader, " and "))
+	}
+
+	var days []types.ContributionDay
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return days, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		count, err := strconv.Atoi(strings.TrimSpace(row[countCol]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid contribution count %q", line, row[countCol])
+		}
+		days = append(days, types.ContributionDay{Date: strings.TrimSpace(row[dateCol]), ContributionCount: count})
+	}
+}
+
+// YearSpan returns the first and last year with days in the data.
+func (d *Data) YearSpan() (int, int) {
+	first, _ := time.Parse(dateLayout, d.Days[0].Date)
+	last, _ := time.Parse(dateLayout, d.Days[len(d.Days)-1].Date)
+	return first.Year(), last.Year()
+}
+
+// Years arranges the days from startYear to endYear into a [week][day] grid
+// per year, shaped like GitHub's contribution calendar. Days missing from the
+// data count as no contributions, but a year without any days in the data is
+// an error, as it was most likely left out by mistake.
+func (d *Data) Years(startYear, endYear int) ([][][]types.ContributionDay, error) {
+	counts := make(map[string]int, len(d.Days))
+	daysInYear := make(map[int]bool)
+	for _, day := range d.Days {
+		counts[day.Date] = day.ContributionCount
+		year, _ := strconv.Atoi(day.Date[:4])
+		daysInYear[year] = true
+	}
+
+	var years [][][]types.ContributionDay
+	for year := startYear; year <= endYear; year++ {
+		if !daysInYear[year] {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("data file has no days in %d", year), nil)
+		}
+		years = append(years, transform.Calendar(year, counts))
+	}
+	return years, nil
+}
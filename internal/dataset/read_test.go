@@ -0,0 +1,153 @@
+package dataset
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// writeFile writes a data file named name with the given contents.
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadRoundTrip(t *testing.T) {
+	for _, name := range []string{"data.json", "data.csv"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := Write(path, "mona", testYears()); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			got, err := Read(path)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+
+			want := &Data{Username: "mona", Days: []types.ContributionDay{
+				{ContributionCount: 1, Date: "2023-12-30"},
+				{ContributionCount: 0, Date: "2023-12-31"},
+				{ContributionCount: 5, Date: "2024-01-01"},
+				{ContributionCount: 2, Date: "2024-01-07"},
+			}}
+			if name == "data.csv" {
+				want.Username = "" // CSV files do not say whose contributions they are
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Read() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		want     []types.ContributionDay
+	}{
+		{"csv columns in any order", "data.csv", "contributionCount,note,date\n3,busy,2024-01-02\n", []types.ContributionDay{{ContributionCount: 3, Date: "2024-01-02"}}},
+		{"days out of order", "data.csv", "date,contributionCount\n2024-01-02,2\n2024-01-01,1\n", []types.ContributionDay{{ContributionCount: 1, Date: "2024-01-01"}, {ContributionCount: 2, Date: "2024-01-02"}}},
+		{"json without a username", "data.json", `{"days": [{"date": "2024-01-01", "contributionCount": 4}]}`, []types.ContributionDay{{ContributionCount: 4, Date: "2024-01-01"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(writeFile(t, tt.file, tt.contents))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Days, tt.want) {
+				t.Errorf("Read() days = %+v, want %+v", got.Days, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{"unknown format", "data.txt", "date,contributionCount\n2024-01-01,1\n"},
+		{"no days", "data.csv", "date,contributionCount\n"},
+		{"empty csv", "data.csv", ""},
+		{"missing column", "data.csv", "date,count\n2024-01-01,1\n"},
+		{"invalid count", "data.csv", "date,contributionCount\n2024-01-01,many\n"},
+		{"negative count", "data.csv", "date,contributionCount\n2024-01-01,-1\n"},
+		{"invalid date", "data.csv", "date,contributionCount\n01/01/2024,1\n"},
+		{"duplicate day", "data.csv", "date,contributionCount\n2024-01-01,1\n2024-01-01,2\n"},
+		{"invalid json", "data.json", `{"days": [`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Read(writeFile(t, tt.file, tt.contents)); err == nil {
+				t.Errorf("Read() = %+v, want an error", got)
+			}
+		})
+	}
+
+	if _, err := Read(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Read() of a missing file error = nil, want an error")
+	}
+}
+
+func TestDataYears(t *testing.T) {
+	data := &Data{Days: []types.ContributionDay{
+		{ContributionCount: 3, Date: "2023-06-15"},
+		{ContributionCount: 7, Date: "2024-01-06"},
+	}}
+
+	if first, last := data.YearSpan(); first != 2023 || last != 2024 {
+		t.Errorf("YearSpan() = %d, %d, want 2023, 2024", first, last)
+	}
+
+	years, err := data.Years(2023, 2024)
+	if err != nil {
+		t.Fatalf("Years() error = %v", err)
+	}
+	if len(years) != 2 {
+		t.Fatalf("Years() returned %d years, want 2", len(years))
+	}
+
+	// 2024 starts on a Monday, so its first week is cut short at six days
+	if got := len(years[1][0]); got != 6 {
+		t.Errorf("first week of 2024 has %d days, want 6", got)
+	}
+	if got := years[1][0][5]; got != (types.ContributionDay{ContributionCount: 7, Date: "2024-01-06"}) {
+		t.Errorf("2024-01-06 = %+v, want 7 contributions", got)
+	}
+
+	total, days := 0, 0
+	for _, week := range years[0] {
+		if len(week) > 7 {
+			t.Errorf("week starting %s has %d days", week[0].Date, len(week))
+		}
+		for _, day := range week {
+			date, _ := time.Parse(dateLayout, day.Date)
+			if day != week[0] && date.Weekday() == time.Sunday {
+				t.Errorf("week starting %s runs past Saturday", week[0].Date)
+			}
+			total += day.ContributionCount
+			days++
+		}
+	}
+	if days != 365 || total != 3 {
+		t.Errorf("2023 has %d days and %d contributions, want 365 and 3", days, total)
+	}
+
+	if _, err := data.Years(2022, 2024); err == nil {
+		t.Error("Years() of a year without days error = nil, want an error")
+	}
+}
@@ -0,0 +1,203 @@
+// Package doctor checks what generating a skyline depends on, such as the
+// GitHub credentials and API and a writable output directory, describing how
+// to fix each problem found.
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+// Check outcomes, from best to worst.
+const (
+	StatusOK      Status = "ok"      // Nothing to fix
+	StatusWarning Status = "warning" // Models can be generated, but some features will not work
+	StatusFailed  Status = "failed"  // Models cannot be generated until it is fixed
+)
+
+// symbols mark each status in a report.
+var symbols = map[Status]string{StatusOK: "✓", StatusWarning: "!", StatusFailed: "✗"}
+
+// Result is what a check found.
+type Result struct {
+	Name   string // What was checked
+	Status Status // Outcome of the check
+	Detail string // What was found
+	Fix    string // How to fix it, empty when there is nothing to fix
+}
+
+// Requester sends requests to GitHub's REST API, as api.RESTClient does.
+type Requester interface {
+	RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error)
+}
+
+// recommendedScopes are the OAuth scopes a classic token needs for every
+// feature: repo to see the private repositories counted by --org, --types and
+// --public-only, and read:org for the organizations of --filter-org.
+var recommendedScopes = []string{"repo", "read:org"}
+
+// CheckAuth reports whether there is a token to authenticate to host with,
+// found in source.
+func CheckAuth(host, token, source string) Result {
+	result := Result{Name: "Authentication"}
+	if token == "" {
+		result.Status = StatusFailed
+		result.Detail = fmt.Sprintf("no token for %s", host)
+		result.Fix = fmt.Sprintf("Run `gh auth login --hostname %s`, or pass a token with --token or GH_SKYLINE_TOKEN", host)
+		return result
+	}
+	result.Status = StatusOK
+	result.Detail = fmt.Sprintf("token for %s from %s", host, source)
+	return result
+}
+
+// CheckAPI reports whether the GitHub API at host can be reached with the
+// client's token, which scopes the token has and how many requests are left
+// before the rate limit.
+func CheckAPI(ctx context.Context, client Requester, host string) []Result {
+	reach := Result{Name: "GitHub API"}
+	resp, err := client.RequestWithContext(ctx, http.MethodGet, "user", nil)
+	if err != nil {
+		reach.Status = StatusFailed
+		reach.Detail = fmt.Sprintf("cannot reach %s: %v", host, err)
+		reach.Fix = apiFix(host, err)
+		return []Result{reach}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil || user.Login == "" {
+		reach.Status = StatusFailed
+		reach.Detail = fmt.Sprintf("%s did not return the authenticated user", host)
+		reach.Fix = fmt.Sprintf("Check that %s is a GitHub host, such as a GitHub Enterprise Server", host)
+		return []Result{reach}
+	}
+	reach.Status = StatusOK
+	reach.Detail = fmt.Sprintf("signed in to %s as %s", host, user.Login)
+	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+		reach.Detail += fmt.Sprintf(", %s of %s requests left this hour", remaining, resp.Header.Get("X-RateLimit-Limit"))
+		if remaining == "0" {
+			reach.Status = StatusWarning
+			reach.Fix = "Wait for the rate limit to reset, or generate from the cache with --offline"
+		}
+	}
+
+	return []Result{reach, checkScopes(host, resp.Header)}
+}
+
+// checkScopes reports whether a token has the recommended scopes, from the
+// X-OAuth-Scopes header of a response to a request it authenticated.
+func checkScopes(host string, header http.Header) Result {
+	result := Result{Name: "Token scopes", Status: StatusOK}
+	if _, ok := header["X-Oauth-Scopes"]; !ok {
+		result.Detail = "not listed for fine-grained and app tokens, which see what they were granted"
+		return result
+	}
+
+	granted := make(map[string]bool)
+	for _, scope := range strings.Split(header.Get("X-OAuth-Scopes"), ",") {
+		granted[strings.TrimSpace(scope)] = true
+	}
+	var missing []string
+	for _, scope := range recommendedScopes {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) == 0 {
+		result.Detail = strings.Join(recommendedScopes, ", ")
+		return result
+	}
+	result.Status = StatusWarning
+	result.Detail = fmt.Sprintf("missing %s, so private repositories and organizations are left out of --org, --types, --public-only and --filter-org", strings.Join(missing, ", "))
+	result.Fix = fmt.Sprintf("Run `gh auth refresh --hostname %s --scopes %s`", host, strings.Join(missing, ","))
+	return result
+}
+
+// apiFix describes how to fix a request to host failing with err.
+func apiFix(host string, err error) string {
+	var httpErr *api.HTTPError
+	if stderrors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusUnauthorized:
+			return fmt.Sprintf("The token is invalid or expired: run `gh auth refresh --hostname %s`, or pass a new one with --token", host)
+		case http.StatusForbidden:
+			return "The token is rate limited or blocked by an organization's policy: wait an hour, or authorize the token for single sign-on"
+		}
+	}
+	if auth.IsEnterprise(host) {
+		return fmt.Sprintf("Check that %s is reachable from this network, such as over a VPN, and that GH_HOST names it", host)
+	}
+	return "Check the network connection and any proxy set by HTTPS_PROXY"
+}
+
+// CheckFonts reports whether the embedded fonts load, as no text can be drawn
+// without them.
+func CheckFonts() Result {
+	result := Result{Name: "Fonts"}
+	if err := geometry.CheckFonts(); err != nil {
+		result.Status = StatusFailed
+		result.Detail = err.Error()
+		result.Fix = "Reinstall the extension with `gh extension upgrade skyline --force`, as its embedded fonts are damaged"
+		return result
+	}
+	result.Status = StatusOK
+	result.Detail = fmt.Sprintf("%s and %s load", geometry.PrimaryFont, geometry.FallbackFont)
+	return result
+}
+
+// CheckOutputDir reports whether files can be written to dir.
+func CheckOutputDir(dir string) Result {
+	result := Result{Name: "Output directory"}
+	file, err := os.CreateTemp(dir, ".gh-skyline-doctor-*")
+	if err != nil {
+		result.Status = StatusFailed
+		result.Detail = fmt.Sprintf("cannot write to %s: %v", dir, err)
+		result.Fix = "Run from a writable directory, or name a file in one with --output"
+		return result
+	}
+	_ = file.Close()
+	_ = os.Remove(file.Name())
+	result.Status = StatusOK
+	result.Detail = fmt.Sprintf("%s is writable", dir)
+	return result
+}
+
+// Write writes a report of results to w, one line per check followed by the
+// fix of each problem found, and returns an error when any check failed.
+func Write(w io.Writer, results []Result) error {
+	failed := 0
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w, "%s %s: %s\n", symbols[r.Status], r.Name, r.Detail); err != nil {
+			return errors.New(errors.IOError, "failed to write the report", err)
+		}
+		if r.Fix != "" {
+			if _, err := fmt.Fprintf(w, "  Fix: %s\n", r.Fix); err != nil {
+				return errors.New(errors.IOError, "failed to write the report", err)
+			}
+		}
+		if r.Status == StatusFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.New(errors.ValidationError, fmt.Sprintf("%d of %d checks failed", failed, len(results)), nil)
+	}
+	return nil
+}
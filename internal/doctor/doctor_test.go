@@ -0,0 +1,166 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// requesterFunc adapts a function to the Requester interface.
+type requesterFunc func(path string) (*http.Response, error)
+
+// RequestWithContext implements Requester.
+func (f requesterFunc) RequestWithContext(_ context.Context, _ string, path string, _ io.Reader) (*http.Response, error) {
+	return f(path)
+}
+
+// userResponse returns a response to GET user with body and headers.
+func userResponse(body string, header map[string]string) requesterFunc {
+	return func(string) (*http.Response, error) {
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+		for key, value := range header {
+			resp.Header.Set(key, value)
+		}
+		return resp, nil
+	}
+}
+
+func TestCheckAuth(t *testing.T) {
+	if got := CheckAuth("github.com", "", ""); got.Status != StatusFailed || !strings.Contains(got.Fix, "gh auth login --hostname github.com") {
+		t.Errorf("CheckAuth() without a token = %+v, want a failure suggesting gh auth login", got)
+	}
+	if got := CheckAuth("github.com", "gho_abc", "GH_TOKEN"); got.Status != StatusOK || !strings.Contains(got.Detail, "GH_TOKEN") {
+		t.Errorf("CheckAuth() with a token = %+v, want it found in GH_TOKEN", got)
+	}
+}
+
+func TestCheckAPI(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		client     requesterFunc
+		want       []Status
+		wantDetail string
+		wantFix    string
+	}{
+		{
+			name:       "classic token with every scope",
+			client:     userResponse(`{"login": "mona"}`, map[string]string{"X-OAuth-Scopes": "gist, read:org, repo", "X-RateLimit-Remaining": "4999", "X-RateLimit-Limit": "5000"}),
+			want:       []Status{StatusOK, StatusOK},
+			wantDetail: "4999 of 5000 requests left",
+		},
+		{
+			name:    "classic token missing a scope",
+			client:  userResponse(`{"login": "mona"}`, map[string]string{"X-OAuth-Scopes": "gist, repo"}),
+			want:    []Status{StatusOK, StatusWarning},
+			wantFix: "--scopes read:org",
+		},
+		{
+			name:       "fine-grained token",
+			client:     userResponse(`{"login": "mona"}`, nil),
+			want:       []Status{StatusOK, StatusOK},
+			wantDetail: "fine-grained",
+		},
+		{
+			name:    "rate limited",
+			client:  userResponse(`{"login": "mona"}`, map[string]string{"X-OAuth-Scopes": "repo, read:org", "X-RateLimit-Remaining": "0", "X-RateLimit-Limit": "5000"}),
+			want:    []Status{StatusWarning, StatusOK},
+			wantFix: "--offline",
+		},
+		{
+			name: "expired token",
+			client: func(string) (*http.Response, error) {
+				return nil, &api.HTTPError{StatusCode: http.StatusUnauthorized, Message: "Bad credentials"}
+			},
+			want:    []Status{StatusFailed},
+			wantFix: "gh auth refresh",
+		},
+		{
+			name: "unreachable enterprise server",
+			host: "github.example.com",
+			client: func(string) (*http.Response, error) {
+				return nil, fmt.Errorf("dial tcp: no such host")
+			},
+			want:    []Status{StatusFailed},
+			wantFix: "VPN",
+		},
+		{
+			name:    "not a GitHub host",
+			client:  userResponse(`<html></html>`, nil),
+			want:    []Status{StatusFailed},
+			wantFix: "GitHub host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.host == "" {
+				tt.host = "github.com"
+			}
+			results := CheckAPI(context.Background(), tt.client, tt.host)
+			var got []Status
+			var details, fixes string
+			for _, r := range results {
+				got = append(got, r.Status)
+				details += r.Detail + "\n"
+				fixes += r.Fix + "\n"
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("CheckAPI() statuses = %v, want %v: %+v", got, tt.want, results)
+			}
+			if !strings.Contains(details, tt.wantDetail) {
+				t.Errorf("CheckAPI() details lack %q:\n%s", tt.wantDetail, details)
+			}
+			if !strings.Contains(fixes, tt.wantFix) {
+				t.Errorf("CheckAPI() fixes lack %q:\n%s", tt.wantFix, fixes)
+			}
+		})
+	}
+}
+
+func TestCheckFonts(t *testing.T) {
+	if got := CheckFonts(); got.Status != StatusOK {
+		t.Errorf("CheckFonts() = %+v, want the embedded fonts to load", got)
+	}
+}
+
+func TestCheckOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	if got := CheckOutputDir(dir); got.Status != StatusOK {
+		t.Errorf("CheckOutputDir() = %+v, want a temporary directory to be writable", got)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("CheckOutputDir() left %v behind", entries)
+	}
+	if got := CheckOutputDir(filepath.Join(dir, "missing")); got.Status != StatusFailed || got.Fix == "" {
+		t.Errorf("CheckOutputDir() of a missing directory = %+v, want a failure with a fix", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	results := []Result{
+		{Name: "Fonts", Status: StatusOK, Detail: "load"},
+		{Name: "Token scopes", Status: StatusWarning, Detail: "missing repo", Fix: "Run gh auth refresh"},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, results); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "✓ Fonts: load\n! Token scopes: missing repo\n  Fix: Run gh auth refresh\n"
+	if buf.String() != want {
+		t.Errorf("Write() = %q, want %q", buf.String(), want)
+	}
+
+	results = append(results, Result{Name: "Authentication", Status: StatusFailed, Detail: "no token"})
+	if err := Write(io.Discard, results); err == nil || !strings.Contains(err.Error(), "1 of 3 checks failed") {
+		t.Errorf("Write() error = %v, want one counting the failed check", err)
+	}
+}
@@ -16,6 +16,7 @@ const (
 	IOError         ErrorType = "IO"         // File/network I/O errors
 	NetworkError    ErrorType = "NETWORK"    // Network communication errors
 	GraphQLError    ErrorType = "GRAPHQL"    // GitHub GraphQL API errors
+	NotFoundError   ErrorType = "NOT_FOUND"  // Accounts or other objects GitHub has none of by the given name
 	STLError        ErrorType = "STL"        // STL file generation errors
 	GeneralError    ErrorType = "GENERAL"    // General errors not fitting other categories
 )
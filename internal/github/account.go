@@ -0,0 +1,79 @@
+package github
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// AccountKind identifies what kind of GitHub account a login belongs to.
+type AccountKind string
+
+// Kinds of accounts a login can belong to.
+const (
+	AccountNone         AccountKind = ""             // No account has the login
+	AccountUser         AccountKind = "User"         // A personal account, with a contribution calendar
+	AccountOrganization AccountKind = "Organization" // An organization, whose repositories' commits can be counted instead
+)
+
+// GetAccountKind looks up what kind of account a login belongs to, or
+// AccountNone when no account has it.
+func (c *Client) GetAccountKind(ctx context.Context, login string) (AccountKind, error) {
+	if login == "" {
+		return AccountNone, errors.New(errors.ValidationError, "login cannot be empty", nil)
+	}
+
+	// GraphQL query to fetch the type of the account owning the login.
+	query := `
+    query AccountKind($login: String!) {
+        repositoryOwner(login: $login) {
+            __typename
+        }
+    }`
+
+	variables := map[string]interface{}{
+		"login": login,
+	}
+
+	var response struct {
+		RepositoryOwner *struct {
+			Typename string `json:"__typename"`
+		} `json:"repositoryOwner"`
+	}
+
+	// Execute the GraphQL query.
+	if err := c.api.DoWithContext(ctx, query, variables, &response); err != nil {
+		if isNotFound(err) {
+			return AccountNone, nil
+		}
+		return AccountNone, errors.New(errors.NetworkError, fmt.Sprintf("failed to look up the account %s", login), err)
+	}
+	if response.RepositoryOwner == nil {
+		return AccountNone, nil
+	}
+	return AccountKind(response.RepositoryOwner.Typename), nil
+}
+
+// userNotFound reports that GitHub has no user with the given login, wrapping
+// the API's error when it gave one.
+func userNotFound(username string, err error) error {
+	return errors.New(errors.NotFoundError, fmt.Sprintf("user %s not found", username), err)
+}
+
+// isNotFound reports whether err is the GitHub GraphQL API failing to resolve
+// an object, such as a user, by the name it was asked for.
+func isNotFound(err error) bool {
+	var graphQLErr *api.GraphQLError
+	if !stderrors.As(err, &graphQLErr) {
+		return false
+	}
+	for _, item := range graphQLErr.Errors {
+		if item.Type == "NOT_FOUND" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+// notFoundError is the GitHub GraphQL API failing to resolve a user by login.
+var notFoundError = &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND", Message: "Could not resolve to a User with the login of 'nobody'."}}}
+
+func TestGetAccountKind(t *testing.T) {
+	tests := []struct {
+		name      string
+		login     string
+		data      string
+		apiErr    error
+		want      AccountKind
+		wantError bool
+	}{
+		{"user", "mona", `{"repositoryOwner": {"__typename": "User"}}`, nil, AccountUser, false},
+		{"organization", "octo-org", `{"repositoryOwner": {"__typename": "Organization"}}`, nil, AccountOrganization, false},
+		{"no account", "nobody", `{"repositoryOwner": null}`, nil, AccountNone, false},
+		{"not found", "nobody", "", notFoundError, AccountNone, false},
+		{"empty login", "", "", nil, AccountNone, true},
+		{"network error", "mona", "", fmt.Errorf("connection reset"), AccountNone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(mocks.GraphQLFunc(func(_ string, variables map[string]interface{}) (string, error) {
+				if variables["login"] != tt.login {
+					return "", fmt.Errorf("looked up %v, want %s", variables["login"], tt.login)
+				}
+				return tt.data, tt.apiErr
+			}))
+
+			kind, err := client.GetAccountKind(context.Background(), tt.login)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("GetAccountKind() error = %v, want error %v", err, tt.wantError)
+			}
+			if kind != tt.want {
+				t.Errorf("GetAccountKind() = %q, want %q", kind, tt.want)
+			}
+		})
+	}
+}
+
+// TestUserNotFound verifies a login GitHub has no user for is reported as not
+// found, whether the API answers with an error or a missing user
+func TestUserNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		apiErr error
+	}{
+		{"graphql error", "", notFoundError},
+		{"missing user", `{"user": null}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+				return tt.data, tt.apiErr
+			}))
+
+			_, err := client.FetchContributionsRange(context.Background(), "nobody", 2023, 2024, ContributionFilter{})
+			if !stderrors.Is(err, &errors.SkylineError{Type: errors.NotFoundError}) {
+				t.Errorf("FetchContributionsRange() error = %v, want user not found", err)
+			}
+			_, err = client.FetchTypedContributions(context.Background(), "nobody", 2024, []ContributionType{TypeIssues}, ContributionFilter{})
+			if !stderrors.Is(err, &errors.SkylineError{Type: errors.NotFoundError}) {
+				t.Errorf("FetchTypedContributions() error = %v, want user not found", err)
+			}
+		})
+	}
+}
@@ -3,7 +3,15 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoding for avatars
+	_ "image/jpeg" // Register JPEG decoding for avatars
+	_ "image/png"  // Register PNG decoding for avatars
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/github/gh-skyline/internal/errors"
@@ -12,21 +20,70 @@ import (
 
 // APIClient interface defines the methods we need from the client
 type APIClient interface {
-	Do(query string, variables map[string]interface{}, response interface{}) error
+	DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error
 }
 
+// HTTPClient interface defines the methods we need to download files, such as avatars
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// downloadTimeout limits how long downloading a file may take.
+const downloadTimeout = 30 * time.Second
+
 // Client holds the API client
 type Client struct {
-	api APIClient
+	api     APIClient
+	http    HTTPClient
+	retries *retryingClient // Retries transient failures of api, nil when they are not retried
+	zone    *time.Location  // Time zone days are counted in, nil for UTC
 }
 
-// NewClient creates a new GitHub client
+// NewClient creates a new GitHub client. Requests turned away by the GitHub
+// API's rate limits are retried once the limits reset, and requests that fail
+// for transient reasons under DefaultRetryPolicy.
 func NewClient(apiClient APIClient) *Client {
-	return &Client{api: apiClient}
+	retries := newRetryingClient(apiClient)
+	return &Client{api: newRateLimitedClient(retries), http: &http.Client{Timeout: downloadTimeout}, retries: retries}
+}
+
+// SetTimeZone changes the time zone days are counted in, which sets where the
+// years fetched begin and end, and the day contributions listed one by one
+// are counted on. GitHub counts the contribution calendar's days from the
+// beginning of the year in that zone. Nil counts days in UTC, as GitHub does
+// by default.
+func (c *Client) SetTimeZone(zone *time.Location) {
+	c.zone = zone
+}
+
+// yearBounds returns the first and last second of a year in the client's time
+// zone.
+func (c *Client) yearBounds(year int) (start, end time.Time) {
+	zone := c.zone
+	if zone == nil {
+		zone = time.UTC
+	}
+	start = time.Date(year, time.January, 1, 0, 0, 0, 0, zone)
+	return start, start.AddDate(1, 0, 0).Add(-time.Second)
+}
+
+// yearRange returns the first and last second of a year in the client's time
+// zone as the DateTime values of a contributions collection's range.
+func (c *Client) yearRange(year int) (from, to string) {
+	start, end := c.yearBounds(year)
+	return start.Format(time.RFC3339), end.Format(time.RFC3339)
+}
+
+// day returns the YYYY-MM-DD date t falls on in the client's time zone.
+func (c *Client) day(t time.Time) string {
+	if c.zone == nil {
+		return t.UTC().Format("2006-01-02")
+	}
+	return t.In(c.zone).Format("2006-01-02")
 }
 
 // GetAuthenticatedUser fetches the authenticated user's login name from GitHub.
-func (c *Client) GetAuthenticatedUser() (string, error) {
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (string, error) {
 	// GraphQL query to fetch the authenticated user's login.
 	query := `
     query {
@@ -42,7 +99,7 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 	}
 
 	// Execute the GraphQL query.
-	err := c.api.Do(query, nil, &response)
+	err := c.api.DoWithContext(ctx, query, nil, &response)
 	if err != nil {
 		return "", errors.New(errors.NetworkError, "failed to fetch authenticated user", err)
 	}
@@ -55,7 +112,7 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 }
 
 // FetchContributions retrieves the contribution data for a given username and year from GitHub.
-func (c *Client) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+func (c *Client) FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
 	if username == "" {
 		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
 	}
@@ -64,8 +121,7 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
 		return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
 	}
 
-	startDate := fmt.Sprintf("%d-01-01T00:00:00Z", year)
-	endDate := fmt.Sprintf("%d-12-31T23:59:59Z", year)
+	startDate, endDate := c.yearRange(year)
 
 	// GraphQL query to fetch the user's contributions within the specified date range.
 	query := `
@@ -73,6 +129,7 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
         user(login: $username) {
             login
             contributionsCollection(from: $from, to: $to) {
+                restrictedContributionsCount
                 contributionCalendar {
                     totalContributions
                     weeks {
@@ -95,20 +152,121 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
 	var response types.ContributionsResponse
 
 	// Execute the GraphQL query.
-	err := c.api.Do(query, variables, &response)
+	err := c.api.DoWithContext(ctx, query, variables, &response)
 	if err != nil {
+		if isNotFound(err) {
+			return nil, userNotFound(username, err)
+		}
 		return nil, errors.New(errors.NetworkError, "failed to fetch contributions", err)
 	}
 
 	if response.User.Login == "" {
-		return nil, errors.New(errors.ValidationError, "received empty username from GitHub API", nil)
+		return nil, userNotFound(username, nil)
 	}
 
 	return &response, nil
 }
 
+// ContributionFilter narrows the contributions fetched down to some of them.
+// The zero value fetches them all.
+type ContributionFilter struct {
+	OrganizationID string // Node ID of the organization to count only the contributions made in, empty for all
+	PublicOnly     bool   // Leave out contributions to private repositories, which only typed contributions can tell apart
+}
+
+// FetchContributionsRange retrieves the contribution data for each year from
+// startYear to endYear in a single request, asking for every year's
+// contributions collection under its own alias. The responses are returned
+// in year order, each shaped as FetchContributions returns it.
+func (c *Client) FetchContributionsRange(ctx context.Context, username string, startYear, endYear int, filter ContributionFilter) ([]*types.ContributionsResponse, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	if startYear < 2008 {
+		return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
+	}
+	if endYear < startYear {
+		return nil, errors.New(errors.ValidationError, "end year cannot be before start year", nil)
+	}
+	if filter.PublicOnly {
+		return nil, errors.New(errors.ValidationError, "the contribution calendar cannot leave out private contributions, count typed contributions instead", nil)
+	}
+
+	// One aliased contributionsCollection per year, each with its own range.
+	var params, collections strings.Builder
+	variables := map[string]interface{}{"username": username, "organizationID": nil}
+	if filter.OrganizationID != "" {
+		variables["organizationID"] = filter.OrganizationID
+	}
+	for year := startYear; year <= endYear; year++ {
+		fmt.Fprintf(&params, ", $from%[1]d: DateTime!, $to%[1]d: DateTime!", year)
+		fmt.Fprintf(&collections, `
+            y%[1]d: contributionsCollection(from: $from%[1]d, to: $to%[1]d, organizationID: $organizationID) {
+                ...calendar
+            }`, year)
+		variables[fmt.Sprintf("from%d", year)], variables[fmt.Sprintf("to%d", year)] = c.yearRange(year)
+	}
+	query := fmt.Sprintf(`
+    query ContributionGraphs($username: String!, $organizationID: ID%s) {
+        user(login: $username) {
+            login%s
+        }
+    }
+
+    fragment calendar on ContributionsCollection {
+        restrictedContributionsCount
+        contributionCalendar {
+            totalContributions
+            weeks {
+                contributionDays {
+                    contributionCount
+                    date
+                }
+            }
+        }
+    }`, params.String(), collections.String())
+
+	var response struct {
+		User map[string]json.RawMessage `json:"user"`
+	}
+
+	// Execute the GraphQL query.
+	err := c.api.DoWithContext(ctx, query, variables, &response)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, userNotFound(username, err)
+		}
+		return nil, errors.New(errors.NetworkError, "failed to fetch contributions", err)
+	}
+
+	var login string
+	if raw, ok := response.User["login"]; ok {
+		if err := json.Unmarshal(raw, &login); err != nil {
+			return nil, errors.New(errors.GraphQLError, "failed to decode username", err)
+		}
+	}
+	if login == "" {
+		return nil, userNotFound(username, nil)
+	}
+
+	responses := make([]*types.ContributionsResponse, 0, endYear-startYear+1)
+	for year := startYear; year <= endYear; year++ {
+		raw, ok := response.User[fmt.Sprintf("y%d", year)]
+		if !ok {
+			return nil, errors.New(errors.GraphQLError, fmt.Sprintf("response is missing the contributions for %d", year), nil)
+		}
+		yearResponse := &types.ContributionsResponse{}
+		yearResponse.User.Login = login
+		if err := json.Unmarshal(raw, &yearResponse.User.ContributionsCollection); err != nil {
+			return nil, errors.New(errors.GraphQLError, fmt.Sprintf("failed to decode the contributions for %d", year), err)
+		}
+		responses = append(responses, yearResponse)
+	}
+	return responses, nil
+}
+
 // GetUserJoinYear fetches the year a user joined GitHub using the GitHub API.
-func (c *Client) GetUserJoinYear(username string) (int, error) {
+func (c *Client) GetUserJoinYear(ctx context.Context, username string) (int, error) {
 	if username == "" {
 		return 0, errors.New(errors.ValidationError, "username cannot be empty", nil)
 	}
@@ -126,17 +284,24 @@ func (c *Client) GetUserJoinYear(username string) (int, error) {
 	}
 
 	var response struct {
-		User struct {
+		User *struct {
 			CreatedAt time.Time `json:"createdAt"`
 		} `json:"user"`
 	}
 
 	// Execute the GraphQL query.
-	err := c.api.Do(query, variables, &response)
+	err := c.api.DoWithContext(ctx, query, variables, &response)
 	if err != nil {
+		if isNotFound(err) {
+			return 0, userNotFound(username, err)
+		}
 		return 0, errors.New(errors.NetworkError, "failed to fetch user's join date", err)
 	}
 
+	if response.User == nil {
+		return 0, userNotFound(username, nil)
+	}
+
 	// Validate that the API returned a real creation date
 	if response.User.CreatedAt.IsZero() {
 		return 0, errors.New(errors.ValidationError, "invalid join date received from GitHub API", nil)
@@ -147,3 +312,73 @@ func (c *Client) GetUserJoinYear(username string) (int, error) {
 
 	return joinYear, nil
 }
+
+// GetAvatarURL fetches the address of a user's avatar, scaled to size pixels square.
+func (c *Client) GetAvatarURL(ctx context.Context, username string, size int) (string, error) {
+	if username == "" {
+		return "", errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+
+	// GraphQL query to fetch the user's avatar address.
+	query := `
+    query UserAvatar($username: String!, $size: Int!) {
+        user(login: $username) {
+            avatarUrl(size: $size)
+        }
+    }`
+
+	variables := map[string]interface{}{
+		"username": username,
+		"size":     size,
+	}
+
+	var response struct {
+		User struct {
+			AvatarURL string `json:"avatarUrl"`
+		} `json:"user"`
+	}
+
+	// Execute the GraphQL query.
+	err := c.api.DoWithContext(ctx, query, variables, &response)
+	if err != nil {
+		if isNotFound(err) {
+			return "", userNotFound(username, err)
+		}
+		return "", errors.New(errors.NetworkError, "failed to fetch avatar URL", err)
+	}
+
+	if response.User.AvatarURL == "" {
+		return "", errors.New(errors.ValidationError, "received empty avatar URL from GitHub API", nil)
+	}
+
+	return response.User.AvatarURL, nil
+}
+
+// FetchAvatar downloads and decodes a user's avatar, scaled to size pixels square.
+func (c *Client) FetchAvatar(ctx context.Context, username string, size int) (image.Image, error) {
+	url, err := c.GetAvatarURL(ctx, username, size)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "invalid avatar URL", err)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to download avatar", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(errors.NetworkError, fmt.Sprintf("failed to download avatar: %s", resp.Status), nil)
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, "failed to decode avatar", err)
+	}
+
+	return img, nil
+}
@@ -1,7 +1,16 @@
 package github
 
 import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
@@ -41,7 +50,7 @@ func TestGetAuthenticatedUser(t *testing.T) {
 				Err:      tt.mockError,
 			})
 
-			user, err := client.GetAuthenticatedUser()
+			user, err := client.GetAuthenticatedUser(context.Background())
 			if (err != nil) != tt.expectedError {
 				t.Errorf("expected error: %v, got: %v", tt.expectedError, err)
 			}
@@ -92,7 +101,7 @@ func TestGetUserJoinYear(t *testing.T) {
 				Err:      tt.mockError,
 			})
 
-			year, err := client.GetUserJoinYear(tt.username)
+			year, err := client.GetUserJoinYear(context.Background(), tt.username)
 			if (err != nil) != tt.expectedError {
 				t.Errorf("expected error: %v, got: %v", tt.expectedError, err)
 			}
@@ -108,7 +117,8 @@ func TestFetchContributions(t *testing.T) {
 		User: struct {
 			Login                   string `json:"login"`
 			ContributionsCollection struct {
-				ContributionCalendar struct {
+				RestrictedContributionsCount int `json:"restrictedContributionsCount"`
+				ContributionCalendar         struct {
 					TotalContributions int `json:"totalContributions"`
 					Weeks              []struct {
 						ContributionDays []types.ContributionDay `json:"contributionDays"`
@@ -118,7 +128,8 @@ func TestFetchContributions(t *testing.T) {
 		}{
 			Login: "chrisreddington",
 			ContributionsCollection: struct {
-				ContributionCalendar struct {
+				RestrictedContributionsCount int `json:"restrictedContributionsCount"`
+				ContributionCalendar         struct {
 					TotalContributions int `json:"totalContributions"`
 					Weeks              []struct {
 						ContributionDays []types.ContributionDay `json:"contributionDays"`
@@ -193,7 +204,7 @@ func TestFetchContributions(t *testing.T) {
 				Err:      tt.mockError,
 			})
 
-			resp, err := client.FetchContributions(tt.username, tt.year)
+			resp, err := client.FetchContributions(context.Background(), tt.username, tt.year)
 			if (err != nil) != tt.expectedError {
 				t.Errorf("expected error: %v, got: %v", tt.expectedError, err)
 			}
@@ -207,3 +218,230 @@ func TestFetchContributions(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchContributionsRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		username      string
+		startYear     int
+		endYear       int
+		mockError     error
+		expectedError bool
+	}{
+		{"single year", "testuser", 2023, 2023, nil, false},
+		{"year range", "testuser", 2019, 2023, nil, false},
+		{"empty username", "", 2019, 2023, nil, true},
+		{"invalid year", "testuser", 2007, 2023, nil, true},
+		{"reversed range", "testuser", 2023, 2019, nil, true},
+		{"network error", "testuser", 2019, 2023, errors.New(errors.NetworkError, "network error", nil), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&mocks.MockGitHubClient{Username: tt.username, Err: tt.mockError})
+
+			responses, err := client.FetchContributionsRange(context.Background(), tt.username, tt.startYear, tt.endYear, ContributionFilter{})
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("expected error: %v, got: %v", tt.expectedError, err)
+			}
+			if tt.expectedError {
+				return
+			}
+			if len(responses) != tt.endYear-tt.startYear+1 {
+				t.Fatalf("expected %d responses, got %d", tt.endYear-tt.startYear+1, len(responses))
+			}
+			for i, resp := range responses {
+				if resp.User.Login != tt.username {
+					t.Errorf("expected user %s, got %s", tt.username, resp.User.Login)
+				}
+				weeks := resp.User.ContributionsCollection.ContributionCalendar.Weeks
+				if want := fmt.Sprint(tt.startYear + i); len(weeks) == 0 || weeks[0].ContributionDays[0].Date[:4] != want {
+					t.Errorf("response %d is not the contributions of %s", i, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchContributionsRangeFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ContributionFilter
+		want   interface{}
+	}{
+		{"all contributions", ContributionFilter{}, nil},
+		{"organization", ContributionFilter{OrganizationID: "O_kgDOAbc123"}, "O_kgDOAbc123"},
+	}
+
+	// The calendar's daily totals cannot tell public contributions apart
+	client := NewClient(mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		t.Error("calendar fetched for public contributions only")
+		return "", nil
+	}))
+	if _, err := client.FetchContributionsRange(context.Background(), "testuser", 2024, 2024, ContributionFilter{PublicOnly: true}); err == nil {
+		t.Error("FetchContributionsRange() of public contributions only error = nil, want an error")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got interface{} = "not sent"
+			client := NewClient(mocks.GraphQLFunc(func(query string, variables map[string]interface{}) (string, error) {
+				if !strings.Contains(query, "organizationID: $organizationID") {
+					t.Errorf("query does not filter by organization:\n%s", query)
+				}
+				got = variables["organizationID"]
+				return `{"user": {"login": "testuser", "y2024": {"contributionCalendar": {"totalContributions": 0, "weeks": []}}}}`, nil
+			}))
+
+			if _, err := client.FetchContributionsRange(context.Background(), "testuser", 2024, 2024, tt.filter); err != nil {
+				t.Fatalf("FetchContributionsRange() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("organizationID = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchContributionsRangeTimeZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		zone     *time.Location
+		wantFrom string
+		wantTo   string
+	}{
+		{"UTC", nil, "2024-01-01T00:00:00Z", "2024-12-31T23:59:59Z"},
+		{"behind UTC", time.FixedZone("PST", -8*60*60), "2024-01-01T00:00:00-08:00", "2024-12-31T23:59:59-08:00"},
+		{"ahead of UTC", time.FixedZone("JST", 9*60*60), "2024-01-01T00:00:00+09:00", "2024-12-31T23:59:59+09:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var from, to interface{}
+			client := NewClient(mocks.GraphQLFunc(func(query string, variables map[string]interface{}) (string, error) {
+				from, to = variables["from2024"], variables["to2024"]
+				return `{"user": {"login": "testuser", "y2024": {"contributionCalendar": {"totalContributions": 0, "weeks": []}}}}`, nil
+			}))
+			client.SetTimeZone(tt.zone)
+
+			if _, err := client.FetchContributionsRange(context.Background(), "testuser", 2024, 2024, ContributionFilter{}); err != nil {
+				t.Fatalf("FetchContributionsRange() error = %v", err)
+			}
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("range = %v to %v, want %s to %s", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestGetAvatarURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		username      string
+		avatarURL     string
+		mockError     error
+		expectedError bool
+	}{
+		{
+			name:      "successful response",
+			username:  "testuser",
+			avatarURL: "https://avatars.githubusercontent.com/u/1?s=256",
+		},
+		{
+			name:          "empty username",
+			username:      "",
+			avatarURL:     "https://avatars.githubusercontent.com/u/1?s=256",
+			expectedError: true,
+		},
+		{
+			name:          "empty avatar URL",
+			username:      "testuser",
+			expectedError: true,
+		},
+		{
+			name:          "network error",
+			username:      "testuser",
+			mockError:     errors.New(errors.NetworkError, "network error", nil),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&mocks.MockGitHubClient{
+				AvatarURL: tt.avatarURL,
+				Err:       tt.mockError,
+			})
+
+			url, err := client.GetAvatarURL(context.Background(), tt.username, 256)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("expected error: %v, got: %v", tt.expectedError, err)
+			}
+			if !tt.expectedError && url != tt.avatarURL {
+				t.Errorf("expected URL %q, got %q", tt.avatarURL, url)
+			}
+		})
+	}
+}
+
+func TestFetchAvatar(t *testing.T) {
+	avatar := image.NewGray(image.Rect(0, 0, 4, 3))
+	avatar.SetGray(1, 1, color.Gray{Y: 0x80})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/avatar.png":
+			w.Header().Set("Content-Type", "image/png")
+			if err := png.Encode(w, avatar); err != nil {
+				t.Errorf("failed to encode avatar: %v", err)
+			}
+		case "/corrupt.png":
+			_, _ = w.Write([]byte("not an image"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		avatarURL     string
+		expectedError bool
+	}{
+		{
+			name:      "successful download",
+			avatarURL: server.URL + "/avatar.png",
+		},
+		{
+			name:          "not found",
+			avatarURL:     server.URL + "/missing.png",
+			expectedError: true,
+		},
+		{
+			name:          "not an image",
+			avatarURL:     server.URL + "/corrupt.png",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&mocks.MockGitHubClient{AvatarURL: tt.avatarURL})
+			client.http = server.Client()
+
+			img, err := client.FetchAvatar(context.Background(), "testuser", 256)
+			if (err != nil) != tt.expectedError {
+				t.Fatalf("expected error: %v, got: %v", tt.expectedError, err)
+			}
+			if tt.expectedError {
+				return
+			}
+			if img.Bounds() != avatar.Bounds() {
+				t.Errorf("expected bounds %v, got %v", avatar.Bounds(), img.Bounds())
+			}
+			if r, _, _, _ := img.At(1, 1).RGBA(); r>>8 != 0x80 {
+				t.Errorf("expected pixel 0x80, got %#x", r>>8)
+			}
+		})
+	}
+}
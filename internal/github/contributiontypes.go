@@ -0,0 +1,319 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// ContributionType identifies a kind of contribution that can be counted on
+// its own.
+type ContributionType string
+
+// Supported contribution types.
+const (
+	TypeCommits      ContributionType = "commits" // Commits authored to repositories' default branches
+	TypePullRequests ContributionType = "prs"     // Pull requests opened
+	TypeIssues       ContributionType = "issues"  // Issues opened
+	TypeReviews      ContributionType = "reviews" // Pull request reviews submitted
+)
+
+// contributionTypes lists the supported contribution types in the order they
+// are presented to users.
+var contributionTypes = []ContributionType{TypeCommits, TypePullRequests, TypeIssues, TypeReviews}
+
+// ContributionTypes returns the names of all supported contribution types.
+func ContributionTypes() []string {
+	names := make([]string, len(contributionTypes))
+	for i, t := range contributionTypes {
+		names[i] = string(t)
+	}
+	return names
+}
+
+// ParseContributionType converts a user supplied contribution type name into
+// a ContributionType. Matching is case-insensitive.
+func ParseContributionType(name string) (ContributionType, error) {
+	for _, t := range contributionTypes {
+		if strings.EqualFold(strings.TrimSpace(name), string(t)) {
+			return t, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported contribution type %q (supported: %s)", name, strings.Join(ContributionTypes(), ", ")), nil)
+}
+
+// ParseContributionTypes converts user supplied contribution type names into
+// ContributionTypes, leaving out repeated ones. No names selects none, which
+// counts the contributions of every kind the contribution calendar shows.
+func ParseContributionTypes(names []string) ([]ContributionType, error) {
+	var selected []ContributionType
+	for _, name := range names {
+		t, err := ParseContributionType(name)
+		if err != nil {
+			return nil, err
+		}
+		if !containsType(selected, t) {
+			selected = append(selected, t)
+		}
+	}
+	return selected, nil
+}
+
+// containsType reports whether t is one of types.
+func containsType(types []ContributionType, t ContributionType) bool {
+	for _, selected := range types {
+		if selected == t {
+			return true
+		}
+	}
+	return false
+}
+
+// typeConnection is the contributions collection connection listing the
+// contributions of a type, one per node, along with the field of each node
+// leading to the repository it was made in.
+type typeConnection struct {
+	connection string
+	subject    string
+}
+
+// typeConnections maps the contribution types counted one contribution per
+// node to the connection listing them.
+var typeConnections = map[ContributionType]typeConnection{
+	TypePullRequests: {"pullRequestContributions", "pullRequest"},
+	TypeIssues:       {"issueContributions", "issue"},
+	TypeReviews:      {"pullRequestReviewContributions", "pullRequestReview"},
+}
+
+// maxCommitRepositories is the most repositories GitHub lists the commit
+// contributions of for a contributions collection.
+const maxCommitRepositories = 100
+
+// FetchTypedContributions counts a user's contributions of the given types
+// made each day of a year that filter lets through, keyed by YYYY-MM-DD date
+// in the client's time zone. Unlike the contribution calendar, which only has daily totals, each
+// type's contributions are listed a page at a time, so this takes a request
+// per hundred contributions. Commits are counted in the first hundred
+// repositories the user committed to that year. With filter.PublicOnly,
+// contributions to private repositories and to those the token cannot see
+// are left out.
+func (c *Client) FetchTypedContributions(ctx context.Context, username string, year int, types []ContributionType, filter ContributionFilter) (map[string]int, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	if year < 2008 {
+		return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
+	}
+	if len(types) == 0 {
+		return nil, errors.New(errors.ValidationError, "no contribution types to count", nil)
+	}
+
+	from, to := c.yearRange(year)
+	variables := map[string]interface{}{
+		"username":       username,
+		"from":           from,
+		"to":             to,
+		"organizationID": nil,
+		"after":          nil,
+	}
+	if filter.OrganizationID != "" {
+		variables["organizationID"] = filter.OrganizationID
+	}
+
+	counts := make(map[string]int)
+	for _, t := range types {
+		var err error
+		if t == TypeCommits {
+			err = c.countCommitContributions(ctx, variables, filter.PublicOnly, counts)
+		} else {
+			err = c.countConnection(ctx, typeConnections[t], variables, filter.PublicOnly, counts)
+		}
+		if isNotFound(err) {
+			err = userNotFound(username, err)
+		}
+		if err != nil {
+			return nil, errors.New(errors.NetworkError, fmt.Sprintf("failed to fetch %s contributions of %s for %d", t, username, year), err)
+		}
+	}
+	return counts, nil
+}
+
+// countConnection adds one contribution per node of a contributions collection
+// connection to counts, on the day it occurred, a page at a time, leaving out
+// those not made in public repositories when publicOnly is set.
+func (c *Client) countConnection(ctx context.Context, tc typeConnection, variables map[string]interface{}, publicOnly bool, counts map[string]int) error {
+	// GraphQL query to fetch a page of the connection's contributions.
+	query := fmt.Sprintf(`
+    query TypedContributions($username: String!, $from: DateTime!, $to: DateTime!, $organizationID: ID, $after: String) {
+        user(login: $username) {
+            contributionsCollection(from: $from, to: $to, organizationID: $organizationID) {
+                contributions: %s(first: 100, after: $after) {
+                    pageInfo {
+                        hasNextPage
+                        endCursor
+                    }
+                    nodes {
+                        occurredAt
+                        isRestricted
+                        subject: %s {
+                            repository {
+                                isPrivate
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    }`, tc.connection, tc.subject)
+
+	variables["after"] = nil
+	for {
+		var response struct {
+			User *struct {
+				ContributionsCollection struct {
+					Contributions struct {
+						PageInfo pageInfo `json:"pageInfo"`
+						Nodes    []struct {
+							OccurredAt   time.Time `json:"occurredAt"`
+							IsRestricted bool      `json:"isRestricted"`
+							Subject      *struct {
+								Repository struct {
+									IsPrivate bool `json:"isPrivate"`
+								} `json:"repository"`
+							} `json:"subject"`
+						} `json:"nodes"`
+					} `json:"contributions"`
+				} `json:"contributionsCollection"`
+			} `json:"user"`
+		}
+
+		// Execute the GraphQL query.
+		if err := c.api.DoWithContext(ctx, query, variables, &response); err != nil {
+			return err
+		}
+		if response.User == nil {
+			return userNotFound(fmt.Sprint(variables["username"]), nil)
+		}
+
+		contributions := response.User.ContributionsCollection.Contributions
+		for _, node := range contributions.Nodes {
+			if publicOnly && (node.IsRestricted || node.Subject == nil || node.Subject.Repository.IsPrivate) {
+				continue
+			}
+			counts[c.day(node.OccurredAt)]++
+		}
+		if !contributions.PageInfo.HasNextPage {
+			return nil
+		}
+		variables["after"] = contributions.PageInfo.EndCursor
+	}
+}
+
+// countCommitContributions adds the commits of each day to counts. GitHub
+// groups commit contributions by repository, and by day within each, so the
+// first request fetches every repository's first page and any repository with
+// more is paged through on its own. Private repositories are left out when
+// publicOnly is set.
+func (c *Client) countCommitContributions(ctx context.Context, variables map[string]interface{}, publicOnly bool, counts map[string]int) error {
+	// GraphQL query to fetch a page of each repository's commit contributions.
+	query := fmt.Sprintf(`
+    query CommitContributions($username: String!, $from: DateTime!, $to: DateTime!, $organizationID: ID, $after: String) {
+        user(login: $username) {
+            contributionsCollection(from: $from, to: $to, organizationID: $organizationID) {
+                commitContributionsByRepository(maxRepositories: %d) {
+                    repository {
+                        nameWithOwner
+                        isPrivate
+                    }
+                    contributions(first: 100, after: $after) {
+                        pageInfo {
+                            hasNextPage
+                            endCursor
+                        }
+                        nodes {
+                            occurredAt
+                            isRestricted
+                            commitCount
+                        }
+                    }
+                }
+            }
+        }
+    }`, maxCommitRepositories)
+
+	type repositoryPage struct {
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+			IsPrivate     bool   `json:"isPrivate"`
+		} `json:"repository"`
+		Contributions struct {
+			PageInfo pageInfo `json:"pageInfo"`
+			Nodes    []struct {
+				OccurredAt   time.Time `json:"occurredAt"`
+				IsRestricted bool      `json:"isRestricted"`
+				CommitCount  int       `json:"commitCount"`
+			} `json:"nodes"`
+		} `json:"contributions"`
+	}
+	fetch := func(after interface{}) ([]repositoryPage, error) {
+		variables["after"] = after
+		var response struct {
+			User *struct {
+				ContributionsCollection struct {
+					CommitContributionsByRepository []repositoryPage `json:"commitContributionsByRepository"`
+				} `json:"contributionsCollection"`
+			} `json:"user"`
+		}
+
+		// Execute the GraphQL query.
+		if err := c.api.DoWithContext(ctx, query, variables, &response); err != nil {
+			return nil, err
+		}
+		if response.User == nil {
+			return nil, userNotFound(fmt.Sprint(variables["username"]), nil)
+		}
+		return response.User.ContributionsCollection.CommitContributionsByRepository, nil
+	}
+	add := func(page repositoryPage) {
+		for _, node := range page.Contributions.Nodes {
+			if publicOnly && node.IsRestricted {
+				continue
+			}
+			counts[c.day(node.OccurredAt)] += node.CommitCount
+		}
+	}
+
+	repositories, err := fetch(nil)
+	if err != nil {
+		return err
+	}
+	for _, repository := range repositories {
+		if publicOnly && repository.Repository.IsPrivate {
+			continue
+		}
+		add(repository)
+		// Later pages ask every repository for the page after the cursor, and
+		// only this repository's answer is kept
+		for page := repository; page.Contributions.PageInfo.HasNextPage; {
+			more, err := fetch(page.Contributions.PageInfo.EndCursor)
+			if err != nil {
+				return err
+			}
+			found := false
+			for _, next := range more {
+				if next.Repository.NameWithOwner == repository.Repository.NameWithOwner {
+					page, found = next, true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+			add(page)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestParseContributionTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []ContributionType
+		wantErr bool
+	}{
+		{"none", nil, nil, false},
+		{"one", []string{"reviews"}, []ContributionType{TypeReviews}, false},
+		{"several", []string{"commits", "PRs", " issues"}, []ContributionType{TypeCommits, TypePullRequests, TypeIssues}, false},
+		{"repeated", []string{"commits", "commits"}, []ContributionType{TypeCommits}, false},
+		{"unknown", []string{"commits", "stars"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContributionTypes(tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseContributionTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseContributionTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeTypedContributions answers the typed contribution queries of a user
+// with commits to two public repositories, the second over two pages, and a
+// private one, and reviews over two pages, two of them of private or
+// restricted pull requests.
+func fakeTypedContributions(query string, variables map[string]interface{}) (string, error) {
+	switch {
+	case strings.Contains(query, "CommitContributions"):
+		if variables["after"] == nil {
+			return `{"user": {"contributionsCollection": {"commitContributionsByRepository": [
+				{"repository": {"nameWithOwner": "mona/api"}, "contributions": {"pageInfo": {"hasNextPage": false}, "nodes": [{"occurredAt": "2024-03-01T08:00:00Z", "commitCount": 3}]}},
+				{"repository": {"nameWithOwner": "mona/web"}, "contributions": {"pageInfo": {"hasNextPage": true, "endCursor": "c2"}, "nodes": [{"occurredAt": "2024-03-01T08:00:00Z", "commitCount": 1}]}},
+				{"repository": {"nameWithOwner": "mona/secret", "isPrivate": true}, "contributions": {"pageInfo": {"hasNextPage": false}, "nodes": [{"occurredAt": "2024-03-01T08:00:00Z", "commitCount": 7}]}}
+			]}}}`, nil
+		}
+		return `{"user": {"contributionsCollection": {"commitContributionsByRepository": [
+			{"repository": {"nameWithOwner": "mona/api"}, "contributions": {"pageInfo": {"hasNextPage": false}, "nodes": []}},
+			{"repository": {"nameWithOwner": "mona/web"}, "contributions": {"pageInfo": {"hasNextPage": false}, "nodes": [{"occurredAt": "2024-03-02T08:00:00Z", "commitCount": 5}]}}
+		]}}}`, nil
+	case strings.Contains(query, "pullRequestReviewContributions"):
+		if variables["after"] == nil {
+			return `{"user": {"contributionsCollection": {"contributions": {"pageInfo": {"hasNextPage": true, "endCursor": "r2"}, "nodes": [
+				{"occurredAt": "2024-03-01T12:00:00Z", "subject": {"repository": {"isPrivate": false}}},
+				{"occurredAt": "2024-03-01T13:00:00Z", "subject": {"repository": {"isPrivate": false}}},
+				{"occurredAt": "2024-03-04T13:00:00Z", "subject": {"repository": {"isPrivate": true}}},
+				{"occurredAt": "2024-03-04T14:00:00Z", "isRestricted": true, "subject": null}
+			]}}}}`, nil
+		}
+		return `{"user": {"contributionsCollection": {"contributions": {"pageInfo": {"hasNextPage": false}, "nodes": [{"occurredAt": "2024-03-03T12:00:00Z", "subject": {"repository": {"isPrivate": false}}}]}}}}`, nil
+	case strings.Contains(query, "issueContributions"):
+		return `{"user": null}`, nil
+	}
+	return "", fmt.Errorf("unexpected query %q", query)
+}
+
+func TestFetchTypedContributions(t *testing.T) {
+	tests := []struct {
+		name       string
+		types      []ContributionType
+		publicOnly bool
+		want       map[string]int
+		wantErr    bool
+	}{
+		{"commits", []ContributionType{TypeCommits}, false, map[string]int{"2024-03-01": 11, "2024-03-02": 5}, false},
+		{"reviews", []ContributionType{TypeReviews}, false, map[string]int{"2024-03-01": 2, "2024-03-03": 1, "2024-03-04": 2}, false},
+		{"commits and reviews", []ContributionType{TypeCommits, TypeReviews}, false, map[string]int{"2024-03-01": 13, "2024-03-02": 5, "2024-03-03": 1, "2024-03-04": 2}, false},
+		{"public commits", []ContributionType{TypeCommits}, true, map[string]int{"2024-03-01": 4, "2024-03-02": 5}, false},
+		{"public reviews", []ContributionType{TypeReviews}, true, map[string]int{"2024-03-01": 2, "2024-03-03": 1}, false},
+		{"user not found", []ContributionType{TypeIssues}, false, nil, true},
+		{"no types", nil, false, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(mocks.GraphQLFunc(fakeTypedContributions))
+			got, err := client.FetchTypedContributions(t.Context(), "mona", 2024, tt.types, ContributionFilter{PublicOnly: tt.publicOnly})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchTypedContributions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchTypedContributions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchTypedContributionsTimeZone(t *testing.T) {
+	client := NewClient(mocks.GraphQLFunc(fakeTypedContributions))
+	client.SetTimeZone(time.FixedZone("NZST", 12*60*60))
+
+	got, err := client.FetchTypedContributions(t.Context(), "mona", 2024, []ContributionType{TypeReviews}, ContributionFilter{})
+	if err != nil {
+		t.Fatalf("FetchTypedContributions() error = %v", err)
+	}
+	// Every review was made from 12:00 UTC on, the day after in the time zone
+	if want := map[string]int{"2024-03-02": 2, "2024-03-04": 1, "2024-03-05": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchTypedContributions() = %v, want %v", got, want)
+	}
+}
+
+func TestFetchTypedContributionsFilter(t *testing.T) {
+	var got interface{}
+	client := NewClient(mocks.GraphQLFunc(func(_ string, variables map[string]interface{}) (string, error) {
+		got = variables["organizationID"]
+		return `{"user": {"contributionsCollection": {"contributions": {"pageInfo": {"hasNextPage": false}, "nodes": []}}}}`, nil
+	}))
+	filter := ContributionFilter{OrganizationID: "O_kgDOAbc123"}
+	if _, err := client.FetchTypedContributions(t.Context(), "mona", 2024, []ContributionType{TypeIssues}, filter); err != nil {
+		t.Fatalf("FetchTypedContributions() error = %v", err)
+	}
+	if got != filter.OrganizationID {
+		t.Errorf("organizationID = %v, want %s", got, filter.OrganizationID)
+	}
+}
@@ -7,12 +7,27 @@ import (
 	"github.com/cli/go-gh/v2/pkg/api"
 )
 
+// TokenEnv is the environment variable holding an auth token to use in place
+// of the gh CLI's stored credentials.
+const TokenEnv = "GH_SKYLINE_TOKEN"
+
+// authToken is the token clients authenticate with, or empty to resolve the
+// gh CLI's credentials.
+var authToken string
+
+// SetAuthToken makes clients created by InitializeGitHubClient authenticate
+// with token, bypassing the gh CLI's credential resolution so the gh CLI need
+// not be logged in. An empty token restores the gh CLI's credentials.
+func SetAuthToken(token string) {
+	authToken = token
+}
+
 // ClientInitializer is a function type for initializing GitHub clients
 type ClientInitializer func() (*Client, error)
 
 // InitializeGitHubClient is the default client initializer
 var InitializeGitHubClient ClientInitializer = func() (*Client, error) {
-	apiClient, err := api.DefaultGraphQLClient()
+	apiClient, err := api.NewGraphQLClient(api.ClientOptions{AuthToken: authToken})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GraphQL client: %w", err)
 	}
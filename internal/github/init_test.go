@@ -0,0 +1,26 @@
+package github
+
+import "testing"
+
+func TestInitializeGitHubClientWithToken(t *testing.T) {
+	// Without a token the gh CLI's credentials are needed, which are not
+	// available where tests run, but a token stands in for them
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	SetAuthToken("test-token")
+	defer SetAuthToken("")
+
+	client, err := InitializeGitHubClient()
+	if err != nil {
+		t.Fatalf("InitializeGitHubClient() with a token error = %v", err)
+	}
+	if client == nil {
+		t.Fatal("InitializeGitHubClient() returned a nil client")
+	}
+
+	SetAuthToken("")
+	if _, err := InitializeGitHubClient(); err == nil {
+		t.Error("InitializeGitHubClient() without a token or credentials error = nil, want an error")
+	}
+}
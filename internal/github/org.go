@@ -0,0 +1,240 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+)
+
+// pageInfo is the pagination state of a GraphQL connection. Paginated queries
+// ask for 100 items a page, the most the GitHub GraphQL API allows.
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// GetOrganizationCreatedYear fetches the year an organization was created.
+func (c *Client) GetOrganizationCreatedYear(ctx context.Context, org string) (int, error) {
+	if org == "" {
+		return 0, errors.New(errors.ValidationError, "organization cannot be empty", nil)
+	}
+
+	// GraphQL query to fetch the organization's creation date.
+	query := `
+    query OrganizationCreatedDate($org: String!) {
+        organization(login: $org) {
+            createdAt
+        }
+    }`
+
+	var response struct {
+		Organization *struct {
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"organization"`
+	}
+
+	// Execute the GraphQL query.
+	err := c.api.DoWithContext(ctx, query, map[string]interface{}{"org": org}, &response)
+	if err != nil {
+		return 0, errors.New(errors.NetworkError, "failed to fetch organization creation date", err)
+	}
+	if response.Organization == nil {
+		return 0, errors.New(errors.ValidationError, fmt.Sprintf("organization %s not found", org), nil)
+	}
+
+	return response.Organization.CreatedAt.Year(), nil
+}
+
+// GetOrganizationID fetches the node ID of an organization, which filtering
+// contributions by organization asks for.
+func (c *Client) GetOrganizationID(ctx context.Context, org string) (string, error) {
+	if org == "" {
+		return "", errors.New(errors.ValidationError, "organization cannot be empty", nil)
+	}
+
+	// GraphQL query to fetch the organization's node ID.
+	query := `
+    query OrganizationID($org: String!) {
+        organization(login: $org) {
+            id
+        }
+    }`
+
+	var response struct {
+		Organization *struct {
+			ID string `json:"id"`
+		} `json:"organization"`
+	}
+
+	// Execute the GraphQL query.
+	err := c.api.DoWithContext(ctx, query, map[string]interface{}{"org": org}, &response)
+	if err != nil {
+		return "", errors.New(errors.NetworkError, "failed to fetch organization ID", err)
+	}
+	if response.Organization == nil || response.Organization.ID == "" {
+		return "", errors.New(errors.ValidationError, fmt.Sprintf("organization %s not found", org), nil)
+	}
+
+	return response.Organization.ID, nil
+}
+
+// FetchOrganizationContributions counts the commits made each day from
+// startYear to endYear on the default branches of an organization's
+// repositories, keyed by YYYY-MM-DD date in the client's time zone. Commits are counted on the
+// day they were authored, as they are in a user's contribution calendar.
+func (c *Client) FetchOrganizationContributions(ctx context.Context, org string, startYear, endYear int) (map[string]int, error) {
+	if org == "" {
+		return nil, errors.New(errors.ValidationError, "organization cannot be empty", nil)
+	}
+	if startYear < 2008 {
+		return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
+	}
+	if endYear < startYear {
+		return nil, errors.New(errors.ValidationError, "end year cannot be before start year", nil)
+	}
+
+	repos, err := c.organizationRepositories(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	log := logger.GetLogger()
+	since, _ := c.yearBounds(startYear)
+	_, until := c.yearBounds(endYear)
+	counts := make(map[string]int)
+	for i, repo := range repos {
+		if err := log.Debug("Counting commits in %s/%s (%d of %d)", org, repo, i+1, len(repos)); err != nil {
+			return nil, err
+		}
+		if err := c.countRepositoryCommits(ctx, org, repo, since, until, counts); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// organizationRepositories lists the names of an organization's repositories,
+// a page at a time.
+func (c *Client) organizationRepositories(ctx context.Context, org string) ([]string, error) {
+	// GraphQL query to fetch a page of the organization's repositories.
+	query := `
+    query OrganizationRepositories($org: String!, $after: String) {
+        organization(login: $org) {
+            repositories(first: 100, after: $after) {
+                pageInfo {
+                    hasNextPage
+                    endCursor
+                }
+                nodes {
+                    name
+                }
+            }
+        }
+    }`
+
+	var names []string
+	variables := map[string]interface{}{"org": org, "after": nil}
+	for {
+		var response struct {
+			Organization *struct {
+				Repositories struct {
+					PageInfo pageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						Name string `json:"name"`
+					} `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"organization"`
+		}
+
+		// Execute the GraphQL query.
+		if err := c.api.DoWithContext(ctx, query, variables, &response); err != nil {
+			return nil, errors.New(errors.NetworkError, "failed to fetch organization repositories", err)
+		}
+		if response.Organization == nil {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("organization %s not found", org), nil)
+		}
+
+		repos := response.Organization.Repositories
+		for _, node := range repos.Nodes {
+			names = append(names, node.Name)
+		}
+		if !repos.PageInfo.HasNextPage {
+			return names, nil
+		}
+		variables["after"] = repos.PageInfo.EndCursor
+	}
+}
+
+// countRepositoryCommits adds the commits authored between since and until on
+// a repository's default branch to counts, a page of history at a time.
+// Repositories without a default branch, such as empty ones, have none.
+func (c *Client) countRepositoryCommits(ctx context.Context, owner, name string, since, until time.Time, counts map[string]int) error {
+	// GraphQL query to fetch a page of the default branch's history.
+	query := `
+    query RepositoryCommits($owner: String!, $name: String!, $since: GitTimestamp!, $until: GitTimestamp!, $after: String) {
+        repository(owner: $owner, name: $name) {
+            defaultBranchRef {
+                target {
+                    ... on Commit {
+                        history(first: 100, since: $since, until: $until, after: $after) {
+                            pageInfo {
+                                hasNextPage
+                                endCursor
+                            }
+                            nodes {
+                                authoredDate
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    }`
+
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  name,
+		"since": since.Format(time.RFC3339),
+		"until": until.Format(time.RFC3339),
+		"after": nil,
+	}
+	for {
+		var response struct {
+			Repository struct {
+				DefaultBranchRef *struct {
+					Target struct {
+						History struct {
+							PageInfo pageInfo `json:"pageInfo"`
+							Nodes    []struct {
+								AuthoredDate time.Time `json:"authoredDate"`
+							} `json:"nodes"`
+						} `json:"history"`
+					} `json:"target"`
+				} `json:"defaultBranchRef"`
+			} `json:"repository"`
+		}
+
+		// Execute the GraphQL query.
+		if err := c.api.DoWithContext(ctx, query, variables, &response); err != nil {
+			return errors.New(errors.NetworkError, fmt.Sprintf("failed to fetch commits of %s/%s", owner, name), err)
+		}
+		if response.Repository.DefaultBranchRef == nil {
+			return nil
+		}
+
+		history := response.Repository.DefaultBranchRef.Target.History
+		for _, node := range history.Nodes {
+			// The history is filtered by committed date, which can differ from the authored one
+			if authored := node.AuthoredDate; !authored.Before(since) && !authored.After(until) {
+				counts[c.day(authored)]++
+			}
+		}
+		if !history.PageInfo.HasNextPage {
+			return nil
+		}
+		variables["after"] = history.PageInfo.EndCursor
+	}
+}
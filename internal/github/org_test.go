@@ -0,0 +1,115 @@
+package github
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+// fakeOrganization answers the organization queries for an organization
+// with three repositories over two pages: one whose history takes two pages,
+// one empty and one with a commit authored long before it was committed.
+func fakeOrganization(query string, variables map[string]interface{}) (string, error) {
+	switch {
+	case strings.Contains(query, "OrganizationCreatedDate"):
+		return `{"organization": {"createdAt": "2015-03-04T05:06:07Z"}}`, nil
+	case strings.Contains(query, "OrganizationID"):
+		return `{"organization": {"id": "O_kgDOAbc123"}}`, nil
+	case strings.Contains(query, "OrganizationRepositories"):
+		if variables["after"] == nil {
+			return `{"organization": {"repositories": {"pageInfo": {"hasNextPage": true, "endCursor": "page2"}, "nodes": [{"name": "api"}, {"name": "empty"}]}}}`, nil
+		}
+		return `{"organization": {"repositories": {"pageInfo": {"hasNextPage": false}, "nodes": [{"name": "web"}]}}}`, nil
+	case strings.Contains(query, "RepositoryCommits"):
+		switch variables["name"] {
+		case "api":
+			if variables["after"] == nil {
+				return `{"repository": {"defaultBranchRef": {"target": {"history": {"pageInfo": {"hasNextPage": true, "endCursor": "c2"}, "nodes": [{"authoredDate": "2024-05-01T10:00:00Z"}, {"authoredDate": "2024-05-01T23:30:00-02:00"}]}}}}}`, nil
+			}
+			return `{"repository": {"defaultBranchRef": {"target": {"history": {"pageInfo": {"hasNextPage": false}, "nodes": [{"authoredDate": "2024-05-01T08:00:00Z"}]}}}}}`, nil
+		case "empty":
+			return `{"repository": {"defaultBranchRef": null}}`, nil
+		case "web":
+			return `{"repository": {"defaultBranchRef": {"target": {"history": {"pageInfo": {"hasNextPage": false}, "nodes": [{"authoredDate": "2024-12-31T12:00:00Z"}, {"authoredDate": "2019-01-01T00:00:00Z"}]}}}}}`, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected query %q with %v", query, variables)
+}
+
+func TestFetchOrganizationContributions(t *testing.T) {
+	client := NewClient(mocks.GraphQLFunc(fakeOrganization))
+	got, err := client.FetchOrganizationContributions(t.Context(), "octo-org", 2024, 2024)
+	if err != nil {
+		t.Fatalf("FetchOrganizationContributions() error = %v", err)
+	}
+	want := map[string]int{"2024-05-01": 2, "2024-05-02": 1, "2024-12-31": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchOrganizationContributions() = %v, want %v", got, want)
+	}
+}
+
+func TestFetchOrganizationContributionsErrors(t *testing.T) {
+	notFound := mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"organization": null}`, nil
+	})
+	tests := []struct {
+		name      string
+		api       APIClient
+		org       string
+		startYear int
+		endYear   int
+	}{
+		{"empty organization", mocks.GraphQLFunc(fakeOrganization), "", 2024, 2024},
+		{"before GitHub", mocks.GraphQLFunc(fakeOrganization), "octo-org", 2007, 2024},
+		{"reversed range", mocks.GraphQLFunc(fakeOrganization), "octo-org", 2024, 2023},
+		{"not found", notFound, "octo-org", 2024, 2024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(tt.api)
+			if _, err := client.FetchOrganizationContributions(t.Context(), tt.org, tt.startYear, tt.endYear); err == nil {
+				t.Error("FetchOrganizationContributions() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestGetOrganizationCreatedYear(t *testing.T) {
+	client := NewClient(mocks.GraphQLFunc(fakeOrganization))
+	year, err := client.GetOrganizationCreatedYear(t.Context(), "octo-org")
+	if err != nil || year != 2015 {
+		t.Errorf("GetOrganizationCreatedYear() = %d, %v, want 2015", year, err)
+	}
+
+	notFound := NewClient(mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"organization": null}`, nil
+	}))
+	if _, err := notFound.GetOrganizationCreatedYear(t.Context(), "octo-org"); err == nil {
+		t.Error("GetOrganizationCreatedYear() of a missing organization error = nil, want an error")
+	}
+	if _, err := client.GetOrganizationCreatedYear(t.Context(), ""); err == nil {
+		t.Error("GetOrganizationCreatedYear() of an empty organization error = nil, want an error")
+	}
+}
+
+func TestGetOrganizationID(t *testing.T) {
+	client := NewClient(mocks.GraphQLFunc(fakeOrganization))
+	id, err := client.GetOrganizationID(t.Context(), "octo-org")
+	if err != nil || id != "O_kgDOAbc123" {
+		t.Errorf("GetOrganizationID() = %q, %v, want O_kgDOAbc123", id, err)
+	}
+
+	notFound := NewClient(mocks.GraphQLFunc(func(string, map[string]interface{}) (string, error) {
+		return `{"organization": null}`, nil
+	}))
+	if _, err := notFound.GetOrganizationID(t.Context(), "octo-org"); err == nil {
+		t.Error("GetOrganizationID() of a missing organization error = nil, want an error")
+	}
+	if _, err := client.GetOrganizationID(t.Context(), ""); err == nil {
+		t.Error("GetOrganizationID() of an empty organization error = nil, want an error")
+	}
+}
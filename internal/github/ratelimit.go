@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+)
+
+// Limits on waiting out rate limits.
+const (
+	maxRateLimitRetries = 3                // Times a rate limited request is retried before giving up
+	maxRateLimitWait    = time.Hour        // Longest wait for a rate limit to reset, the length of GitHub's primary limit window
+	secondaryLimitWait  = time.Minute      // Wait after a rate limit that gives no reset time, as GitHub advises
+	rateLimitMargin     = 1 * time.Second  // Added to reset times, so the retry lands after the reset despite clock skew
+	rateLimitPollPeriod = 30 * time.Second // How often the remaining wait is reported while waiting
+)
+
+// rateLimitedClient retries requests the GitHub API turns away for exceeding
+// a rate limit, once the limit has reset, so a long multi-year fetch is not
+// cut short partway through.
+type rateLimitedClient struct {
+	api   APIClient
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+// newRateLimitedClient wraps an API client to wait out rate limits.
+func newRateLimitedClient(apiClient APIClient) *rateLimitedClient {
+	return &rateLimitedClient{api: apiClient, now: time.Now, sleep: sleepContext}
+}
+
+// sleepContext waits for the duration, or until ctx is done, when it returns
+// the context's error.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DoWithContext executes a GraphQL query, waiting and retrying when it is
+// rate limited. Waiting stops when ctx is done.
+func (c *rateLimitedClient) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	log := logger.GetLogger()
+	for attempt := 1; ; attempt++ {
+		err := c.api.DoWithContext(ctx, query, variables, response)
+		wait, limited := rateLimitWait(err, c.now())
+		if !limited {
+			return err
+		}
+		if attempt > maxRateLimitRetries {
+			return errors.New(errors.NetworkError, "GitHub API rate limit still exceeded after waiting for it to reset", err)
+		}
+		if wait > maxRateLimitWait {
+			return errors.New(errors.NetworkError, "GitHub API rate limit exceeded until "+c.now().Add(wait).Format(time.Kitchen), err)
+		}
+
+		if err := log.Warning("GitHub API rate limit exceeded, waiting %s for it to reset (retry %d of %d)", wait.Round(time.Second), attempt, maxRateLimitRetries); err != nil {
+			return err
+		}
+		for wait > 0 {
+			step := min(wait, rateLimitPollPeriod)
+			if err := c.sleep(ctx, step); err != nil {
+				return err
+			}
+			wait -= step
+			if wait > 0 {
+				if err := log.Info("Waiting %s more for the GitHub API rate limit to reset", wait.Round(time.Second)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// rateLimitWait reports whether err is the GitHub API turning a request away
+// for exceeding a primary or secondary rate limit, and how long to wait before
+// retrying it: the Retry-After header when given, otherwise until the reset
+// time of an exhausted primary limit, otherwise a minute.
+func rateLimitWait(err error, now time.Time) (time.Duration, bool) {
+	var graphQLErr *api.GraphQLError
+	if stderrors.As(err, &graphQLErr) {
+		for _, item := range graphQLErr.Errors {
+			if item.Type == "RATE_LIMITED" {
+				return secondaryLimitWait, true
+			}
+		}
+		return 0, false
+	}
+
+	var httpErr *api.HTTPError
+	if !stderrors.As(err, &httpErr) {
+		return 0, false
+	}
+	if httpErr.StatusCode != http.StatusForbidden && httpErr.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(httpErr.Headers.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if httpErr.Headers.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(httpErr.Headers.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(now), 0) + rateLimitMargin, true
+		}
+		return secondaryLimitWait, true
+	}
+	// Secondary limits are only told apart from other refusals by their message
+	if httpErr.StatusCode == http.StatusTooManyRequests || strings.Contains(strings.ToLower(httpErr.Message), "rate limit") {
+		return secondaryLimitWait, true
+	}
+	return 0, false
+}
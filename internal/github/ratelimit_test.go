@@ -0,0 +1,127 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// sequenceAPIClient returns each of its errors in turn from DoWithContext, then nil.
+type sequenceAPIClient struct {
+	errs  []error
+	calls int
+}
+
+// DoWithContext implements APIClient
+func (s *sequenceAPIClient) DoWithContext(_ context.Context, _ string, _ map[string]interface{}, _ interface{}) error {
+	s.calls++
+	if s.calls > len(s.errs) {
+		return nil
+	}
+	return s.errs[s.calls-1]
+}
+
+// rateLimitError returns an HTTP error with the given status and headers.
+func rateLimitError(status int, message string, headers map[string]string) error {
+	h := http.Header{}
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+	return &api.HTTPError{StatusCode: status, Message: message, Headers: h}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+
+	tests := []struct {
+		name        string
+		err         error
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{"no error", nil, 0, false},
+		{"other error", errors.New(errors.NetworkError, "connection reset", nil), 0, false},
+		{"graphql rate limited", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "RATE_LIMITED"}}}, time.Minute, true},
+		{"graphql not found", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND"}}}, 0, false},
+		{"retry after", rateLimitError(http.StatusForbidden, "", map[string]string{"Retry-After": "30"}), 30 * time.Second, true},
+		{"primary limit reset", rateLimitError(http.StatusForbidden, "API rate limit exceeded", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset(2 * time.Minute)}), 2*time.Minute + rateLimitMargin, true},
+		{"primary limit already reset", rateLimitError(http.StatusForbidden, "", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset(-time.Minute)}), rateLimitMargin, true},
+		{"primary limit without reset", rateLimitError(http.StatusForbidden, "", map[string]string{"X-RateLimit-Remaining": "0"}), time.Minute, true},
+		{"secondary limit", rateLimitError(http.StatusForbidden, "You have exceeded a secondary rate limit", nil), time.Minute, true},
+		{"too many requests", rateLimitError(http.StatusTooManyRequests, "", nil), time.Minute, true},
+		{"forbidden", rateLimitError(http.StatusForbidden, "Resource not accessible", map[string]string{"X-RateLimit-Remaining": "4999"}), 0, false},
+		{"server error", rateLimitError(http.StatusBadGateway, "", map[string]string{"Retry-After": "30"}), 0, false},
+		{"wrapped", errors.New(errors.NetworkError, "failed", rateLimitError(http.StatusTooManyRequests, "", nil)), time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, limited := rateLimitWait(tt.err, now)
+			if wait != tt.wantWait || limited != tt.wantLimited {
+				t.Errorf("rateLimitWait() = %v, %v, want %v, %v", wait, limited, tt.wantWait, tt.wantLimited)
+			}
+		})
+	}
+}
+
+func TestRateLimitedClientDo(t *testing.T) {
+	limited := rateLimitError(http.StatusForbidden, "", map[string]string{"Retry-After": "75"})
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   bool
+		wantCalls int
+		wantSlept time.Duration
+	}{
+		{"no limit", nil, false, 1, 0},
+		{"limited once", []error{limited}, false, 2, 75 * time.Second},
+		{"limited until retries run out", []error{limited, limited, limited, limited}, true, 4, 3 * 75 * time.Second},
+		{"other error", []error{errors.New(errors.NetworkError, "connection reset", nil)}, true, 1, 0},
+		{"reset too far away", []error{rateLimitError(http.StatusForbidden, "", map[string]string{"Retry-After": "7200"})}, true, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiClient := &sequenceAPIClient{errs: tt.errs}
+			var slept time.Duration
+			client := &rateLimitedClient{
+				api:   apiClient,
+				now:   time.Now,
+				sleep: func(_ context.Context, d time.Duration) error { slept += d; return nil },
+			}
+
+			err := client.DoWithContext(context.Background(), "query", nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DoWithContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if apiClient.calls != tt.wantCalls {
+				t.Errorf("DoWithContext() made %d requests, want %d", apiClient.calls, tt.wantCalls)
+			}
+			if slept != tt.wantSlept {
+				t.Errorf("DoWithContext() waited %v, want %v", slept, tt.wantSlept)
+			}
+		})
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("sleepContext() error = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := sleepContext(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("sleepContext() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleepContext() waited %v after the context was canceled", elapsed)
+	}
+}
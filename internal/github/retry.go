@@ -0,0 +1,117 @@
+package github
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"math/rand/v2"
+	"net/url"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+)
+
+// maxRetryBackoff caps the wait between retries, however many there are.
+const maxRetryBackoff = time.Minute
+
+// RetryPolicy controls how requests that fail for transient reasons, such as
+// dropped connections and server errors, are retried.
+type RetryPolicy struct {
+	Attempts int           // Times a failed request is retried, 0 to fail on the first error
+	Backoff  time.Duration // Wait before the first retry, doubled for each one after it
+	Jitter   float64       // Fraction of each wait that is randomized, from 0 to 1, so clients retrying together spread out
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Attempts: 3, Backoff: time.Second, Jitter: 0.2}
+}
+
+// Validate checks that the policy's values are in range.
+func (p RetryPolicy) Validate() error {
+	if p.Attempts < 0 {
+		return errors.New(errors.ValidationError, "retry count cannot be negative", nil)
+	}
+	if p.Backoff < 0 {
+		return errors.New(errors.ValidationError, "retry backoff cannot be negative", nil)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return errors.New(errors.ValidationError, "retry jitter must be between 0 and 1", nil)
+	}
+	return nil
+}
+
+// wait returns how long to wait before the given retry, counted from 1, with
+// random a value in [0, 1) that picks the jitter.
+func (p RetryPolicy) wait(retry int, random float64) time.Duration {
+	backoff := min(p.Backoff<<min(retry-1, 30), maxRetryBackoff)
+	if backoff < 0 {
+		backoff = maxRetryBackoff // Shifted past the range of a duration
+	}
+	return time.Duration(float64(backoff) * (1 - p.Jitter*random))
+}
+
+// retryingClient retries requests that fail for transient reasons according
+// to its policy. Rate limited requests are left to rateLimitedClient, which
+// knows how long to wait for them.
+type retryingClient struct {
+	api    APIClient
+	policy RetryPolicy
+	random func() float64
+	sleep  func(context.Context, time.Duration) error
+}
+
+// newRetryingClient wraps an API client to retry transient failures under the
+// default policy.
+func newRetryingClient(apiClient APIClient) *retryingClient {
+	return &retryingClient{api: apiClient, policy: DefaultRetryPolicy(), random: rand.Float64, sleep: sleepContext}
+}
+
+// DoWithContext executes a GraphQL query, retrying it when it fails for a
+// transient reason. Retrying stops when ctx is done.
+func (c *retryingClient) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	log := logger.GetLogger()
+	for retry := 1; ; retry++ {
+		err := c.api.DoWithContext(ctx, query, variables, response)
+		if err == nil || !isTransient(err) || retry > c.policy.Attempts {
+			return err
+		}
+
+		wait := c.policy.wait(retry, c.random())
+		if err := log.Warning("Request to GitHub failed (%v), retrying in %s (retry %d of %d)", err, wait.Round(time.Millisecond), retry, c.policy.Attempts); err != nil {
+			return err
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// isTransient reports whether err is a failure that may not happen again: a
+// server error from the GitHub API, or a request that failed to get a
+// response at all, short of being canceled.
+func isTransient(err error) bool {
+	var httpErr *api.HTTPError
+	if stderrors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+	if stderrors.Is(err, context.Canceled) {
+		return false
+	}
+	var urlErr *url.Error
+	return stderrors.As(err, &urlErr) || stderrors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// SetRetryPolicy changes how requests that fail for transient reasons are retried.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	if c.retries == nil {
+		return errors.New(errors.GeneralError, "client was not created to retry requests", nil)
+	}
+	c.retries.policy = policy
+	return nil
+}
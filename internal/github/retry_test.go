@@ -0,0 +1,147 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr bool
+	}{
+		{"default", DefaultRetryPolicy(), false},
+		{"no retries", RetryPolicy{}, false},
+		{"full jitter", RetryPolicy{Attempts: 5, Backoff: time.Second, Jitter: 1}, false},
+		{"negative attempts", RetryPolicy{Attempts: -1}, true},
+		{"negative backoff", RetryPolicy{Backoff: -time.Second}, true},
+		{"jitter above one", RetryPolicy{Jitter: 1.5}, true},
+		{"negative jitter", RetryPolicy{Jitter: -0.1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	policy := RetryPolicy{Attempts: 10, Backoff: time.Second, Jitter: 0.5}
+	tests := []struct {
+		name   string
+		retry  int
+		random float64
+		want   time.Duration
+	}{
+		{"first retry", 1, 0, time.Second},
+		{"doubled", 2, 0, 2 * time.Second},
+		{"doubled again", 3, 0, 4 * time.Second},
+		{"jittered", 3, 0.5, 3 * time.Second},
+		{"capped", 8, 0, maxRetryBackoff},
+		{"capped far beyond the range of a shift", 100, 0, maxRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.wait(tt.retry, tt.random); got != tt.want {
+				t.Errorf("wait(%d, %v) = %v, want %v", tt.retry, tt.random, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server error", &api.HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{"client error", &api.HTTPError{StatusCode: http.StatusNotFound}, false},
+		{"rate limited", &api.HTTPError{StatusCode: http.StatusTooManyRequests}, false},
+		{"connection failed", &url.Error{Op: "Post", URL: "https://api.github.com/graphql", Err: io.EOF}, true},
+		{"response cut short", io.ErrUnexpectedEOF, true},
+		{"canceled", &url.Error{Op: "Post", URL: "https://api.github.com/graphql", Err: context.Canceled}, false},
+		{"graphql error", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND"}}}, false},
+		{"wrapped server error", errors.New(errors.NetworkError, "failed", &api.HTTPError{StatusCode: http.StatusServiceUnavailable}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryingClientDo(t *testing.T) {
+	serverError := &api.HTTPError{StatusCode: http.StatusInternalServerError}
+	tests := []struct {
+		name      string
+		errs      []error
+		attempts  int
+		wantErr   bool
+		wantCalls int
+		wantSlept time.Duration
+	}{
+		{"success", nil, 3, false, 1, 0},
+		{"recovers", []error{serverError, serverError}, 3, false, 3, 3 * time.Second},
+		{"retries run out", []error{serverError, serverError, serverError}, 2, true, 3, 3 * time.Second},
+		{"retries disabled", []error{serverError}, 0, true, 1, 0},
+		{"not transient", []error{&api.HTTPError{StatusCode: http.StatusUnauthorized}}, 3, true, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiClient := &sequenceAPIClient{errs: tt.errs}
+			var slept time.Duration
+			client := &retryingClient{
+				api:    apiClient,
+				policy: RetryPolicy{Attempts: tt.attempts, Backoff: time.Second},
+				random: func() float64 { return 0 },
+				sleep:  func(_ context.Context, d time.Duration) error { slept += d; return nil },
+			}
+
+			err := client.DoWithContext(context.Background(), "query", nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DoWithContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if apiClient.calls != tt.wantCalls {
+				t.Errorf("DoWithContext() made %d requests, want %d", apiClient.calls, tt.wantCalls)
+			}
+			if slept != tt.wantSlept {
+				t.Errorf("DoWithContext() waited %v, want %v", slept, tt.wantSlept)
+			}
+		})
+	}
+}
+
+func TestClientSetRetryPolicy(t *testing.T) {
+	client := NewClient(&mocks.MockGitHubClient{})
+	policy := RetryPolicy{Attempts: 7, Backoff: time.Millisecond, Jitter: 0.1}
+	if err := client.SetRetryPolicy(policy); err != nil {
+		t.Fatalf("SetRetryPolicy() error = %v", err)
+	}
+	if client.retries.policy != policy {
+		t.Errorf("client retry policy = %+v, want %+v", client.retries.policy, policy)
+	}
+	if err := client.SetRetryPolicy(RetryPolicy{Attempts: -1}); err == nil {
+		t.Error("SetRetryPolicy() with negative attempts error = nil, want an error")
+	}
+	if err := (&Client{}).SetRetryPolicy(policy); err == nil {
+		t.Error("SetRetryPolicy() on a client without retries error = nil, want an error")
+	}
+}
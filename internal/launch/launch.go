@@ -0,0 +1,71 @@
+// Package launch opens files in the application the operating system
+// associates with their type, such as a slicer or 3D viewer for a model, as
+// the browser package of the gh CLI opens URLs in the browser.
+package launch
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Launcher opens files in their default application.
+type Launcher struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// New returns a Launcher whose launching commands write to stdout and stderr.
+func New(stdout, stderr io.Writer) *Launcher {
+	return &Launcher{stdout: stdout, stderr: stderr}
+}
+
+// Open opens the file at path in the application associated with its type,
+// returning once the operating system has been asked to.
+func (l *Launcher) Open(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to resolve the file to open", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return errors.New(errors.IOError, fmt.Sprintf("failed to open %s", path), err)
+	}
+	name, args, err := command(runtime.GOOS, path, exec.LookPath)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout, cmd.Stderr = l.stdout, l.stderr
+	if err := cmd.Run(); err != nil {
+		return errors.New(errors.IOError, fmt.Sprintf("failed to open %s with %s", path, name), err)
+	}
+	return nil
+}
+
+// linuxLaunchers are tried in turn on Linux and the BSDs: the desktop's
+// opener, then WSL's, which opens files in the Windows application.
+var linuxLaunchers = []string{"xdg-open", "wslview"}
+
+// command returns the command opening path in its default application on
+// goos, looking the launchers that may not be installed up with lookPath.
+func command(goos, path string, lookPath func(string) (string, error)) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "open", []string{path}, nil
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", path}, nil
+	}
+	for _, launcher := range linuxLaunchers {
+		if _, err := lookPath(launcher); err == nil {
+			return launcher, []string{path}, nil
+		}
+	}
+	return "", nil, errors.New(errors.IOError, fmt.Sprintf("no application launcher found to open %s (tried %s)", path, strings.Join(linuxLaunchers, ", ")), nil)
+}
@@ -0,0 +1,59 @@
+package launch
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", exec.ErrNotFound
+		}
+	}
+
+	tests := []struct {
+		name     string
+		goos     string
+		lookPath func(string) (string, error)
+		wantName string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"macos", "darwin", installed(), "open", []string{"/m.stl"}, false},
+		{"windows", "windows", installed(), "rundll32", []string{"url.dll,FileProtocolHandler", "/m.stl"}, false},
+		{"linux desktop", "linux", installed("xdg-open", "wslview"), "xdg-open", []string{"/m.stl"}, false},
+		{"wsl", "linux", installed("wslview"), "wslview", []string{"/m.stl"}, false},
+		{"freebsd", "freebsd", installed("xdg-open"), "xdg-open", []string{"/m.stl"}, false},
+		{"no launcher", "linux", installed(), "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, err := command(tt.goos, "/m.stl", tt.lookPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("command() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("command() = %s %v, want %s %v", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestOpenMissingFile(t *testing.T) {
+	var out bytes.Buffer
+	if err := New(&out, &out).Open(filepath.Join(t.TempDir(), "missing.stl")); err == nil {
+		t.Error("Open() of a missing file error = nil, want an error")
+	}
+	if out.Len() > 0 {
+		t.Errorf("Open() of a missing file ran a launcher, which printed %q", out.String())
+	}
+}
@@ -3,8 +3,10 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 	"sync"
 )
 
@@ -32,6 +34,10 @@ type Logger struct {
 	error   *log.Logger
 	level   LogLevel
 	mu      sync.Mutex
+
+	collecting bool     // Whether warnings are recorded
+	warnings   []string // Warnings recorded since collecting started
+	annotating bool     // Whether warnings and errors are GitHub Actions annotations
 }
 
 var (
@@ -46,8 +52,8 @@ func GetLogger() *Logger {
 		instance = &Logger{
 			debug:   log.New(os.Stdout, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile),
 			info:    log.New(os.Stdout, "", 0),
-			warning: log.New(os.Stdout, "WARNING: ", log.Ldate|log.Ltime),
-			error:   log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime),
+			warning: log.New(os.Stdout, warningPrefix, levelFlags),
+			error:   log.New(os.Stderr, errorPrefix, levelFlags),
 			level:   INFO,
 		}
 	})
@@ -62,13 +68,78 @@ func (l *Logger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
+// SetOutput sends the debug, info and warning messages to w, such as to keep
+// them apart from a command's own output on stdout. Errors go to stderr.
+// Thread-safe through mutex locking
+func (l *Logger) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debug.SetOutput(w)
+	l.info.SetOutput(w)
+	l.warning.SetOutput(w)
+}
+
+// Prefixes and flags of warnings and errors, as logged by default.
+const (
+	warningPrefix = "WARNING: "
+	errorPrefix   = "ERROR: "
+	levelFlags    = log.Ldate | log.Ltime
+)
+
+// annotationEscaper escapes the characters GitHub Actions workflow commands
+// cannot hold, so messages spanning lines stay one annotation.
+var annotationEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// SetAnnotations logs warnings and errors as GitHub Actions workflow
+// commands, which the runner shows as annotations of the run, in place of
+// prefixing them with their level and time.
+// Thread-safe through mutex locking
+func (l *Logger) SetAnnotations(on bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.annotating = on
+	if on {
+		l.warning.SetPrefix("::warning::")
+		l.error.SetPrefix("::error::")
+		l.warning.SetFlags(0)
+		l.error.SetFlags(0)
+		return
+	}
+	l.warning.SetPrefix(warningPrefix)
+	l.error.SetPrefix(errorPrefix)
+	l.warning.SetFlags(levelFlags)
+	l.error.SetFlags(levelFlags)
+}
+
+// CollectWarnings records the warning messages logged from now on, whatever
+// the level, until the returned function is called, which returns them in the
+// order they were logged.
+func (l *Logger) CollectWarnings() func() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.collecting, l.warnings = true, nil
+	return func() []string {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		warnings := l.warnings
+		l.collecting, l.warnings = false, nil
+		return warnings
+	}
+}
+
 // logf is an internal helper that handles mutex locking and level checking
 func (l *Logger) logf(level LogLevel, format string, v ...interface{}) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if level == WARNING && l.collecting {
+		l.warnings = append(l.warnings, fmt.Sprintf(format, v...))
+	}
 	if l.level <= level {
 		msg := fmt.Sprintf(format, v...)
+		if l.annotating && level >= WARNING {
+			msg = annotationEscaper.Replace(msg)
+		}
 		var err error
 
 		switch level {
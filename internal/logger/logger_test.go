@@ -185,6 +185,86 @@ func TestWarning(t *testing.T) {
 	}
 }
 
+func TestSetOutput(t *testing.T) {
+	logger, capture := setupTestLogger(t)
+	defer logger.SetOutput(capture.stdout)
+	logger.SetLevel(INFO)
+
+	var redirected bytes.Buffer
+	logger.SetOutput(&redirected)
+	if err := logger.Info("%s", "moved message"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if err := logger.Error("%s", "error message"); err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+	if !strings.Contains(redirected.String(), "moved message") || capture.stdout.Len() > 0 {
+		t.Errorf("Info() wrote %q to the new output and %q to the old, want it moved", redirected.String(), capture.stdout.String())
+	}
+	if !strings.Contains(capture.stderr.String(), "error message") {
+		t.Errorf("Error() output = %q, want errors kept on stderr", capture.stderr.String())
+	}
+}
+
+func TestCollectWarnings(t *testing.T) {
+	logger, _ := setupTestLogger(t)
+	logger.SetLevel(ERROR)
+
+	if err := logger.Warning("%s", "before"); err != nil {
+		t.Fatalf("Warning() error = %v", err)
+	}
+	stop := logger.CollectWarnings()
+	for _, message := range []string{"first", "second"} {
+		if err := logger.Warning("%s", message); err != nil {
+			t.Fatalf("Warning() error = %v", err)
+		}
+	}
+	if err := logger.Info("%s", "not a warning"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if got := stop(); strings.Join(got, ",") != "first,second" {
+		t.Errorf("CollectWarnings() = %v, want the warnings logged while collecting, hidden by the level or not", got)
+	}
+
+	if err := logger.Warning("%s", "after"); err != nil {
+		t.Fatalf("Warning() error = %v", err)
+	}
+	if got := logger.CollectWarnings()(); len(got) != 0 {
+		t.Errorf("CollectWarnings() = %v, want none once stopped", got)
+	}
+}
+
+func TestSetAnnotations(t *testing.T) {
+	logger, capture := setupTestLogger(t)
+	logger.SetLevel(INFO)
+	logger.SetAnnotations(true)
+
+	if err := logger.Warning("%s", "100% of\nthe towers"); err != nil {
+		t.Fatalf("Warning() error = %v", err)
+	}
+	if err := logger.Error("%s", "failed"); err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+	if err := logger.Info("%s", "plain\nmessage"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if want := "::warning::100%25 of%0Athe towers\nplain\nmessage\n"; capture.stdout.String() != want {
+		t.Errorf("annotated output = %q, want %q", capture.stdout.String(), want)
+	}
+	if want := "::error::failed\n"; capture.stderr.String() != want {
+		t.Errorf("annotated error = %q, want %q", capture.stderr.String(), want)
+	}
+
+	logger.SetAnnotations(false)
+	capture.stdout.Reset()
+	if err := logger.Warning("%s", "100%"); err != nil {
+		t.Fatalf("Warning() error = %v", err)
+	}
+	if got := capture.stdout.String(); !strings.HasPrefix(got, "WARNING: ") || !strings.HasSuffix(got, " 100%\n") {
+		t.Errorf("Warning() output = %q, want the level and time prefixed once annotations are off", got)
+	}
+}
+
 func TestLogLevelString(t *testing.T) {
 	tests := []struct {
 		name     string
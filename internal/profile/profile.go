@@ -0,0 +1,116 @@
+// Package profile records runtime profiles of model generation, for
+// diagnosing slow generations without rebuilding the extension.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Mode identifies the kind of profile recorded.
+type Mode string
+
+// Supported profile modes.
+const (
+	ModeNone   Mode = ""      // No profiling
+	ModeCPU    Mode = "cpu"   // pprof CPU profile
+	ModeMemory Mode = "mem"   // pprof heap profile, taken when generation finishes
+	ModeTrace  Mode = "trace" // Execution trace, for go tool trace
+)
+
+// modes lists the supported profile modes in the order they are presented to users.
+var modes = []Mode{ModeCPU, ModeMemory, ModeTrace}
+
+// Modes returns the names of all supported profile modes.
+func Modes() []string {
+	names := make([]string, len(modes))
+	for i, m := range modes {
+		names[i] = string(m)
+	}
+	return names
+}
+
+// ParseMode converts a user supplied profile name into a Mode.
+// Matching is case-insensitive and an empty string selects ModeNone.
+func ParseMode(name string) (Mode, error) {
+	if name == "" {
+		return ModeNone, nil
+	}
+	for _, m := range modes {
+		if strings.EqualFold(name, string(m)) {
+			return m, nil
+		}
+	}
+	return ModeNone, errors.New(errors.ValidationError, fmt.Sprintf("unsupported profile %q (supported: %s)", name, strings.Join(Modes(), ", ")), nil)
+}
+
+// Path returns where the profile of generating the model at outputPath is
+// written: next to the model, with the extension replaced by the mode's,
+// such as skyline.cpu.pprof for skyline.stl.
+func (m Mode) Path(outputPath string) string {
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	if m == ModeTrace {
+		return base + ".trace"
+	}
+	return base + "." + string(m) + ".pprof"
+}
+
+// Start begins recording a profile in mode m, written to path. The returned
+// function stops recording and finishes the file; it must be called once the
+// profiled work is done. With ModeNone nothing is recorded.
+func Start(m Mode, path string) (func() error, error) {
+	if m == ModeNone {
+		return func() error { return nil }, nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to create profile file", err)
+	}
+	closeFile := func() error {
+		if err := f.Close(); err != nil {
+			return errors.New(errors.IOError, "failed to close profile file", err)
+		}
+		return nil
+	}
+
+	switch m {
+	case ModeCPU:
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, errors.New(errors.IOError, "failed to start CPU profile", err)
+		}
+		return func() error {
+			pprof.StopCPUProfile()
+			return closeFile()
+		}, nil
+	case ModeMemory:
+		return func() error {
+			// Collect garbage first, so the profile shows what is still in use
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				_ = f.Close()
+				return errors.New(errors.IOError, "failed to write memory profile", err)
+			}
+			return closeFile()
+		}, nil
+	case ModeTrace:
+		if err := trace.Start(f); err != nil {
+			_ = f.Close()
+			return nil, errors.New(errors.IOError, "failed to start execution trace", err)
+		}
+		return func() error {
+			trace.Stop()
+			return closeFile()
+		}, nil
+	}
+	_ = f.Close()
+	return nil, errors.New(errors.ValidationError, fmt.Sprintf("unsupported profile %q", m), nil)
+}
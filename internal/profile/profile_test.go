@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Mode
+		wantErr bool
+	}{
+		{"", ModeNone, false},
+		{"cpu", ModeCPU, false},
+		{"MEM", ModeMemory, false},
+		{"trace", ModeTrace, false},
+		{"block", ModeNone, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMode(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMode(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMode(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := strings.Join(Modes(), ","); got != "cpu,mem,trace" {
+		t.Errorf("Modes() = %q, want cpu,mem,trace", got)
+	}
+}
+
+func TestModePath(t *testing.T) {
+	tests := []struct {
+		mode Mode
+		path string
+		want string
+	}{
+		{ModeCPU, "octocat-2024-github-skyline.stl", "octocat-2024-github-skyline.cpu.pprof"},
+		{ModeMemory, filepath.Join("out", "skyline.3mf"), filepath.Join("out", "skyline.mem.pprof")},
+		{ModeTrace, "skyline", "skyline.trace"},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.Path(tt.path); got != tt.want {
+			t.Errorf("%s.Path(%q) = %q, want %q", tt.mode, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestStart(t *testing.T) {
+	for _, mode := range modes {
+		t.Run(string(mode), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profile")
+			stop, err := Start(mode, path)
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			// Give the profile something to record
+			var buf []byte
+			for i := 0; i < 1000; i++ {
+				buf = append(buf, make([]byte, 1024)...)
+			}
+			_ = buf
+
+			if err := stop(); err != nil {
+				t.Fatalf("stop() error = %v", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("profile was not written: %v", err)
+			}
+			if info.Size() == 0 {
+				t.Error("profile is empty")
+			}
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "profile")
+		stop, err := Start(ModeNone, path)
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if err := stop(); err != nil {
+			t.Errorf("stop() error = %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("Start() with no profile wrote a file")
+		}
+	})
+
+	t.Run("unwritable path", func(t *testing.T) {
+		if _, err := Start(ModeCPU, filepath.Join(t.TempDir(), "missing", "profile")); err == nil {
+			t.Error("Start() error = nil, want an error for a missing directory")
+		}
+	})
+}
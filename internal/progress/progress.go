@@ -0,0 +1,192 @@
+// Package progress shows how far a long running task has got on a terminal:
+// a spinner with a count of the steps done, and a bar once the number of
+// steps is known, redrawn in place on a single line.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Drawing settings.
+const (
+	refreshInterval = 100 * time.Millisecond // How often the spinner turns
+	barWidth        = 24                     // Cells of the bar
+)
+
+// spinnerFrames are drawn in turn while a task runs.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Bar draws the progress of one task at a time on a line of a terminal. The
+// methods of a nil Bar do nothing, so tasks can report to a Bar that is not
+// shown, such as when the output is not a terminal.
+type Bar struct {
+	w io.Writer
+
+	mu    sync.Mutex
+	label string        // What the task is doing
+	unit  string        // What its steps are, such as years
+	done  int           // Steps done
+	total int           // Steps in all, 0 when not known
+	frame int           // Spinner frame drawn next
+	stop  chan struct{} // Closed to stop redrawing, nil when no task runs
+	ended chan struct{} // Closed once redrawing stopped
+}
+
+// New returns a Bar drawing on w, which must be a terminal understanding the
+// ANSI sequence clearing a line.
+func New(w io.Writer) *Bar {
+	return &Bar{w: w}
+}
+
+// Start shows a task labeled label of total steps of unit, or of an unknown
+// number when total is 0, finishing the task shown before.
+func (b *Bar) Start(label, unit string, total int) {
+	if b == nil {
+		return
+	}
+	b.Finish()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.label, b.unit, b.done, b.total, b.frame = label, unit, 0, total, 0
+	b.stop, b.ended = make(chan struct{}), make(chan struct{})
+	b.draw()
+	go b.spin(b.stop, b.ended)
+}
+
+// Add counts n more steps of the task as done.
+func (b *Bar) Add(n int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done += n
+	if b.stop != nil {
+		b.draw()
+	}
+}
+
+// Finish clears the task from the terminal, so what is printed next starts on
+// an empty line. Finishing when no task is shown does nothing.
+func (b *Bar) Finish() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	stop, ended := b.stop, b.ended
+	b.stop, b.ended = nil, nil
+	b.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-ended
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, _ = io.WriteString(b.w, "\r\x1b[K")
+}
+
+// Writer returns a writer writing to w, a writer to the same terminal as b,
+// that clears the task shown first and redraws it below what was written, so
+// messages logged while a task runs do not break up its line.
+func (b *Bar) Writer(w io.Writer) io.Writer {
+	if b == nil {
+		return w
+	}
+	return &barWriter{bar: b, w: w}
+}
+
+// barWriter keeps the task of a Bar below the lines written to w.
+type barWriter struct {
+	bar *Bar
+	w   io.Writer
+}
+
+// Write writes p to the underlying writer between clearing the task shown, if
+// any, and drawing it again.
+func (bw *barWriter) Write(p []byte) (int, error) {
+	bw.bar.mu.Lock()
+	defer bw.bar.mu.Unlock()
+	if bw.bar.stop == nil {
+		return bw.w.Write(p)
+	}
+	_, _ = io.WriteString(bw.bar.w, "\r\x1b[K")
+	n, err := bw.w.Write(p)
+	bw.bar.draw()
+	return n, err
+}
+
+// spin turns the spinner until stop is closed, then closes ended.
+func (b *Bar) spin(stop <-chan struct{}, ended chan<- struct{}) {
+	defer close(ended)
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			if b.stop == stop {
+				b.frame = (b.frame + 1) % len(spinnerFrames)
+				b.draw()
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// draw redraws the line of the task. The caller holds b.mu.
+func (b *Bar) draw() {
+	_, _ = io.WriteString(b.w, "\r\x1b[K"+b.line())
+}
+
+// line describes the task: the spinner, its label and the steps done, with a
+// bar of their share of the total when it is known.
+func (b *Bar) line() string {
+	line := spinnerFrames[b.frame] + " " + b.label
+	if b.total <= 0 {
+		if b.done > 0 {
+			line += fmt.Sprintf(" %s %s", formatCount(b.done), b.unit)
+		}
+		return line
+	}
+	done := min(b.done, b.total)
+	filled := done * barWidth / b.total
+	return fmt.Sprintf("%s [%s%s] %s/%s %s", line, strings.Repeat("█", filled), strings.Repeat("░", barWidth-filled), formatCount(done), formatCount(b.total), b.unit)
+}
+
+// formatCount formats n with commas between groups of thousands.
+func formatCount(n int) string {
+	digits := fmt.Sprint(n)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// contextKey is the key of the Bar a context carries.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying b, for the tasks run with it to
+// report their progress to.
+func NewContext(ctx context.Context, b *Bar) context.Context {
+	return context.WithValue(ctx, contextKey{}, b)
+}
+
+// FromContext returns the Bar ctx carries, or nil when it carries none.
+func FromContext(ctx context.Context) *Bar {
+	b, _ := ctx.Value(contextKey{}).(*Bar)
+	return b
+}
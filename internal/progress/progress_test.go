@@ -0,0 +1,129 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is a buffer safe to write while the spinner turns.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		name  string
+		unit  string
+		total int
+		done  int
+		want  string
+	}{
+		{"known total", "years", 10, 5, "Fetching [████████████░░░░░░░░░░░░] 5/10 years"},
+		{"done past the total", "years", 4, 6, "Fetching [████████████████████████] 4/4 years"},
+		{"unknown total", "triangles", 0, 12345, "Fetching 12,345 triangles"},
+		{"nothing done", "triangles", 0, 0, "Fetching"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf syncBuffer
+			bar := New(&buf)
+			bar.Start("Fetching", tt.unit, tt.total)
+			bar.Add(tt.done)
+			bar.Finish()
+
+			out := buf.String()
+			lines := strings.Split(out, "\r\x1b[K")
+			if last := lines[len(lines)-2]; !strings.HasSuffix(last, tt.want) {
+				t.Errorf("Bar drew %q last, want it to end with %q", last, tt.want)
+			}
+			if !strings.HasSuffix(out, "\r\x1b[K") {
+				t.Errorf("Finish() left %q, want the line cleared", out)
+			}
+		})
+	}
+}
+
+func TestBarStartFinishes(t *testing.T) {
+	var buf syncBuffer
+	bar := New(&buf)
+	bar.Start("First", "years", 2)
+	bar.Start("Second", "years", 2)
+	bar.Finish()
+	bar.Finish()
+
+	out := buf.String()
+	if !strings.Contains(out, "First") || !strings.Contains(out, "Second") || strings.LastIndex(out, "First") > strings.Index(out, "Second") {
+		t.Errorf("Bar drew %q, want the first task then the second", out)
+	}
+}
+
+func TestBarWriter(t *testing.T) {
+	var buf syncBuffer
+	bar := New(&buf)
+	w := bar.Writer(&buf)
+
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatal(err)
+	}
+	bar.Start("Writing", "triangles", 0)
+	if _, err := w.Write([]byte("during\n")); err != nil {
+		t.Fatal(err)
+	}
+	bar.Finish()
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "before\n") {
+		t.Errorf("Writer() wrote %q first, want the message alone when no task is shown", out)
+	}
+	during := strings.Index(out, "\r\x1b[Kduring\n")
+	if during < 0 || !strings.Contains(out[during:], "Writing") {
+		t.Errorf("Writer() wrote %q, want the task cleared before the message and drawn after it", out)
+	}
+}
+
+func TestNilBar(t *testing.T) {
+	var bar *Bar
+	bar.Start("Nothing", "years", 1)
+	bar.Add(1)
+	bar.Finish()
+
+	var buf bytes.Buffer
+	if w := bar.Writer(&buf); w != &buf {
+		t.Errorf("Writer() of a nil Bar = %v, want the writer itself", w)
+	}
+	if got := FromContext(context.Background()); got != nil {
+		t.Errorf("FromContext() = %v, want nil without a Bar", got)
+	}
+}
+
+func TestContext(t *testing.T) {
+	bar := New(&bytes.Buffer{})
+	if got := FromContext(NewContext(context.Background(), bar)); got != bar {
+		t.Errorf("FromContext() = %v, want the Bar the context carries", got)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 36864: "36,864", 1234567: "1,234,567"}
+	for n, want := range tests {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Package qr encodes text as QR code symbols (ISO/IEC 18004).
+//
+// Text is always encoded in byte mode with error correction level M, which
+// recovers about 15% of damaged modules and suits codes embossed on prints.
+package qr
+
+import (
+	"fmt"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+
+	byteMode       = 0x4 // Mode indicator for 8-bit byte data
+	levelMFormat   = 0x0 // Format bits identifying error correction level M
+	formatMask     = 0x5412
+	formatPoly     = 0x537
+	versionPoly    = 0x1F25
+	fieldPoly      = 0x11D // Reducing polynomial of GF(2^8)
+	padCodewordOne = 0xEC
+	padCodewordTwo = 0x11
+)
+
+// eccCodewordsPerBlock is the number of error correction codewords in each
+// block at level M, indexed by version.
+var eccCodewordsPerBlock = [maxVersion + 1]int{
+	0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26,
+	26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28,
+}
+
+// eccBlocks is the number of error correction blocks at level M, indexed by version.
+var eccBlocks = [maxVersion + 1]int{
+	0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16,
+	17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49,
+}
+
+// Code is a QR code symbol, a square grid of dark and light modules. The grid
+// does not include the quiet zone that should surround the symbol.
+type Code struct {
+	Version int // Symbol version, from 1 (21x21 modules) to 40 (177x177 modules)
+	Size    int // Number of modules along each side
+
+	modules    [][]bool // Dark modules, indexed by row then column
+	isFunction [][]bool // Modules of the finder, timing, alignment, format and version patterns
+}
+
+// Dark reports whether the module in column x and row y, counted from the
+// top left corner, is dark. Modules outside the symbol are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y][x]
+}
+
+// Encode encodes text as the smallest QR code symbol that holds it.
+func Encode(text string) (*Code, error) {
+	if text == "" {
+		return nil, errors.New(errors.ValidationError, "QR code text cannot be empty", nil)
+	}
+	data := []byte(text)
+
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+charCountBits(version)+8*len(data) <= 8*dataCodewords(version) {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("text of %d bytes is too long for a QR code", len(data)), nil)
+	}
+
+	// Mode, length and data, then a terminator and padding to fill the symbol
+	var bits bitBuffer
+	bits.append(byteMode, 4)
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := 8 * dataCodewords(version)
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	codewords := bits.bytes()
+	for pad := padCodewordOne; len(codewords) < dataCodewords(version); pad ^= padCodewordOne ^ padCodewordTwo {
+		codewords = append(codewords, byte(pad))
+	}
+
+	c := newCode(version)
+	c.drawCodewords(addErrorCorrection(codewords, version))
+
+	// Keep the mask that leaves the fewest patterns confusing to scanners
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		c.applyMask(mask) // Masks are their own inverse
+	}
+	c.applyMask(bestMask)
+	c.drawFormatBits(bestMask)
+
+	return c, nil
+}
+
+// charCountBits returns the width of the byte mode length field for a version.
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// rawDataModules returns the number of modules available for data and error
+// correction in a symbol of the given version, after the function patterns.
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		alignments := version/7 + 2
+		result -= (25*alignments-10)*alignments - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// dataCodewords returns the number of data codewords a symbol of the given version holds.
+func dataCodewords(version int) int {
+	return rawDataModules(version)/8 - eccCodewordsPerBlock[version]*eccBlocks[version]
+}
+
+// addErrorCorrection splits the data codewords into blocks, appends each
+// block's error correction codewords and interleaves the blocks.
+func addErrorCorrection(data []byte, version int) []byte {
+	blockCount := eccBlocks[version]
+	eccLen := eccCodewordsPerBlock[version]
+	rawCodewords := rawDataModules(version) / 8
+	shortBlocks := blockCount - rawCodewords%blockCount
+	shortDataLen := rawCodewords/blockCount - eccLen
+
+	divisor := reedSolomonGenerator(eccLen)
+	dataBlocks := make([][]byte, blockCount)
+	eccCodewords := make([][]byte, blockCount)
+	for i, k := 0, 0; i < blockCount; i++ {
+		n := shortDataLen
+		if i >= shortBlocks {
+			n++
+		}
+		dataBlocks[i] = data[k : k+n]
+		eccCodewords[i] = reedSolomonRemainder(dataBlocks[i], divisor)
+		k += n
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortDataLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for _, block := range eccCodewords {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// reedSolomonGenerator returns the coefficients of the Reed-Solomon generator
+// polynomial of the given degree, highest power first, without the leading 1.
+func reedSolomonGenerator(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 2)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords for data.
+func reedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies two elements of GF(2^8).
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * fieldPoly)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// bitBuffer accumulates a big-endian bit stream.
+type bitBuffer []bool
+
+// append adds the low n bits of value, most significant first.
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 != 0)
+	}
+}
+
+// len returns the number of bits in the buffer.
+func (b bitBuffer) len() int {
+	return len(b)
+}
+
+// bytes packs the buffer, whose length is a multiple of eight, into bytes.
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
@@ -0,0 +1,277 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// Version 1-M encoding of "HELLO WORLD" from the worked example in ISO/IEC 18004
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	got := reedSolomonRemainder(data, reedSolomonGenerator(len(want)))
+	if !bytes.Equal(got, want) {
+		t.Errorf("reedSolomonRemainder() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	// Level M format information for each mask
+	want := []string{
+		"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+		"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+	}
+	for mask, w := range want {
+		got := formatBits(mask)
+		var s strings.Builder
+		for i := 14; i >= 0; i-- {
+			s.WriteByte('0' + byte((got>>i)&1))
+		}
+		if s.String() != w {
+			t.Errorf("formatBits(%d) = %s, want %s", mask, s.String(), w)
+		}
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	tests := []struct {
+		version int
+		want    int
+	}{
+		{7, 0x07C94},
+		{8, 0x085BC},
+		{21, 0x15683},
+		{40, 0x28C69},
+	}
+	for _, tt := range tests {
+		if got := versionBits(tt.version); got != tt.want {
+			t.Errorf("versionBits(%d) = %#05x, want %#05x", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	tests := []struct {
+		version int
+		want    []int
+	}{
+		{1, nil},
+		{2, []int{6, 18}},
+		{7, []int{6, 22, 38}},
+		{22, []int{6, 26, 50, 74, 98}},
+		{32, []int{6, 34, 60, 86, 112, 138}},
+		{40, []int{6, 30, 58, 86, 114, 142, 170}},
+	}
+	for _, tt := range tests {
+		got := alignmentPositions(tt.version)
+		if len(got) != len(tt.want) {
+			t.Errorf("alignmentPositions(%d) = %v, want %v", tt.version, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("alignmentPositions(%d) = %v, want %v", tt.version, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDataCodewords(t *testing.T) {
+	tests := []struct {
+		version int
+		want    int
+	}{
+		{1, 16},
+		{2, 28},
+		{4, 64},
+		{7, 124},
+		{10, 216},
+		{27, 1128},
+		{40, 2334},
+	}
+	for _, tt := range tests {
+		if got := dataCodewords(tt.version); got != tt.want {
+			t.Errorf("dataCodewords(%d) = %d, want %d", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantVersion int
+	}{
+		{"short", "skyline", 1},
+		{"version 1 capacity", strings.Repeat("a", 14), 1},
+		{"version 2", strings.Repeat("a", 15), 2},
+		{"profile URL", "https://github.com/octocat", 2},
+		{"long username", "https://github.com/" + strings.Repeat("a", 39), 4},
+		{"long length field", strings.Repeat("b", 200), 10},
+		{"multiple block sizes", strings.Repeat("c", 500), 17},
+		{"version 40 capacity", strings.Repeat("d", 2331), 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Encode(tt.text)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if c.Version != tt.wantVersion {
+				t.Errorf("Encode() version = %d, want %d", c.Version, tt.wantVersion)
+			}
+			if c.Size != 4*tt.wantVersion+17 {
+				t.Errorf("Encode() size = %d, want %d", c.Size, 4*tt.wantVersion+17)
+			}
+			if got := decode(t, c); got != tt.text {
+				t.Errorf("decoded text = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	if _, err := Encode(""); err == nil {
+		t.Error("Encode() expected error for empty text")
+	}
+	if _, err := Encode(strings.Repeat("x", 2332)); err == nil {
+		t.Error("Encode() expected error for text beyond version 40")
+	}
+}
+
+func TestCodeFinderPatterns(t *testing.T) {
+	c, err := Encode("https://github.com/octocat")
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				if want := ring != 2; c.Dark(corner[0]+dx, corner[1]+dy) != want {
+					t.Fatalf("finder at %v module (%d, %d) dark = %v, want %v", corner, dx, dy, !want, want)
+				}
+			}
+		}
+	}
+	if c.Dark(-1, 0) || c.Dark(c.Size, 0) {
+		t.Error("modules outside the symbol should be light")
+	}
+}
+
+// decode reads a symbol back into text, checking its format information and
+// error correction along the way.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+
+	// Format information next to the top left finder identifies the mask
+	format := 0
+	for i := 0; i <= 5; i++ {
+		format |= boolBit(c.modules[i][8]) << i
+	}
+	format |= boolBit(c.modules[7][8]) << 6
+	format |= boolBit(c.modules[8][8]) << 7
+	format |= boolBit(c.modules[8][7]) << 8
+	for i := 9; i < 15; i++ {
+		format |= boolBit(c.modules[8][14-i]) << i
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %015b is not a level M format", format)
+	}
+
+	// Read the masked data modules in placement order
+	probe := newCode(c.Version)
+	var codewords []byte
+	var current byte
+	count := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if probe.isFunction[y][x] {
+					continue
+				}
+				dark := c.modules[y][x] != maskFunctions[mask](x, y)
+				current = current<<1 | byte(boolBit(dark))
+				if count++; count%8 == 0 {
+					codewords = append(codewords, current)
+					current = 0
+				}
+			}
+		}
+	}
+	codewords = codewords[:rawDataModules(c.Version)/8]
+
+	// De-interleave the blocks and check each one's error correction
+	blockCount := eccBlocks[c.Version]
+	eccLen := eccCodewordsPerBlock[c.Version]
+	shortBlocks := blockCount - len(codewords)%blockCount
+	shortDataLen := len(codewords)/blockCount - eccLen
+	blocks := make([][]byte, blockCount)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for b := range blocks {
+			if i < shortDataLen || b >= shortBlocks {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	divisor := reedSolomonGenerator(eccLen)
+	for b := range blocks {
+		ecc := make([]byte, eccLen)
+		for i := range ecc {
+			ecc[i] = codewords[k+i*blockCount+b]
+		}
+		if got := reedSolomonRemainder(blocks[b], divisor); !bytes.Equal(got, ecc) {
+			t.Fatalf("block %d error correction = %v, want %v", b, ecc, got)
+		}
+		data = append(data, blocks[b]...)
+	}
+
+	// Byte mode segment
+	bit := func(i int) int { return int(data[i/8]>>(7-i%8)) & 1 }
+	read := func(pos, n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | bit(pos+i)
+		}
+		return v
+	}
+	if mode := read(0, 4); mode != byteMode {
+		t.Fatalf("mode = %#x, want byte mode", mode)
+	}
+	length := read(4, charCountBits(c.Version))
+	text := make([]byte, length)
+	for i := range text {
+		text[i] = byte(read(4+charCountBits(c.Version)+8*i, 8))
+	}
+	return string(text)
+}
+
+// boolBit converts a module to a bit.
+func boolBit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,187 @@
+// Package render draws generated 3D models as 2D images, such as isometric
+// SVG drawings and shaded PNG renders of the skyline.
+package render
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/theme"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Isometric view vectors. The camera looks at the model from the front-right and
+// above, so the front face (text and logo), the right side and the top of every
+// tower are visible.
+var (
+	viewDir  = normalize(types.Point3D{X: 1, Y: -1, Z: 1})     // Towards the viewer
+	screenX  = normalize(types.Point3D{X: 1, Y: 1, Z: 0})      // Screen right
+	screenY  = normalize(types.Point3D{X: -1, Y: 1, Z: 2})     // Screen up
+	lightDir = normalize(types.Point3D{X: 0.4, Y: -0.6, Z: 1}) // Key light
+)
+
+// Shading constants for the simple Lambertian lighting model.
+const (
+	ambientLight = 0.45
+	diffuseLight = 0.55
+)
+
+// Colors used for each kind of model object.
+var kindColors = map[types.ObjectKind]color.RGBA{
+	types.ObjectBase:    {R: 0x30, G: 0x36, B: 0x3d, A: 0xff},
+	types.ObjectTower:   {R: 0x39, G: 0xd3, B: 0x53, A: 0xff},
+	types.ObjectSurface: {R: 0x39, G: 0xd3, B: 0x53, A: 0xff},
+	types.ObjectFuture:  {R: 0x6e, G: 0x76, B: 0x81, A: 0xff},
+	types.ObjectText:    {R: 0xe6, G: 0xed, B: 0xf3, A: 0xff},
+	types.ObjectLogo:    {R: 0xe6, G: 0xed, B: 0xf3, A: 0xff},
+	types.ObjectQR:      {R: 0xe6, G: 0xed, B: 0xf3, A: 0xff},
+	types.ObjectStats:   {R: 0xe6, G: 0xed, B: 0xf3, A: 0xff},
+	types.ObjectMonths:  {R: 0xe6, G: 0xed, B: 0xf3, A: 0xff},
+	types.ObjectYears:   {R: 0xe6, G: 0xed, B: 0xf3, A: 0xff},
+	types.ObjectAvatar:  {R: 0xf6, G: 0xf8, B: 0xfa, A: 0xff},
+	types.ObjectMold:    {R: 0x30, G: 0x36, B: 0x3d, A: 0xff},
+}
+
+// defaultColor is used for objects without a kind.
+var defaultColor = color.RGBA{R: 0x8b, G: 0x94, B: 0x9e, A: 0xff}
+
+// objectColor returns the color an object of kind and material is drawn in,
+// before shading: the color of its contribution level in palette, when palette
+// is set and the material is of a level, or else the color of its kind.
+func objectColor(kind types.ObjectKind, material types.Material, palette theme.Palette) color.RGBA {
+	if level, ok := material.Level(); ok && !palette.IsZero() {
+		return palette[level]
+	}
+	return ObjectColor(kind)
+}
+
+// ObjectColor returns the color objects of a kind are drawn in, before shading.
+func ObjectColor(kind types.ObjectKind) color.RGBA {
+	if c, ok := kindColors[kind]; ok {
+		return c
+	}
+	return defaultColor
+}
+
+// point2D is a projected point in model units, with Y increasing downwards.
+type point2D struct {
+	X, Y float64
+}
+
+// face is a projected, shaded triangle ready to be drawn.
+type face struct {
+	points [3]point2D
+	depth  float64 // Distance along the view direction, larger is closer to the viewer
+	layer  int     // Faces in lower layers are always drawn first
+	fill   color.RGBA
+}
+
+// bounds is an axis-aligned rectangle in projected coordinates.
+type bounds struct {
+	minX, minY, maxX, maxY float64
+}
+
+// width returns the horizontal extent of the bounds.
+func (b bounds) width() float64 { return b.maxX - b.minX }
+
+// height returns the vertical extent of the bounds.
+func (b bounds) height() float64 { return b.maxY - b.minY }
+
+// projectModel projects every visible triangle of the model into 2D and returns the
+// faces in painter's order (back to front) along with their bounding box.
+//
+// Triangles facing away from the viewer are culled. The base is drawn before all
+// other geometry because everything else sits on or in front of it; within a layer
+// faces are ordered by the depth of their centroid. Towers are ordered as a whole by
+// the depth of their footprint so tall towers are not drawn over shorter ones in front.
+// Objects of a contribution level are drawn in its color in palette, when it is set.
+func projectModel(model *types.Model, palette theme.Palette) ([]face, bounds) {
+	var faces []face
+	b := bounds{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+
+	for _, obj := range model.Objects {
+		base := objectColor(obj.Kind, obj.Material, palette)
+		layer := 1
+		if obj.Kind == types.ObjectBase {
+			layer = 0
+		}
+
+		towerDepth, isTower := 0.0, obj.Kind == types.ObjectTower && obj.Mesh.Len() > 0
+		if isTower {
+			towerDepth = footprintDepth(obj.Mesh.Triangles())
+		}
+
+		for _, t := range obj.Mesh.Triangles() {
+			if dot(t.Normal, viewDir) <= 0 {
+				continue
+			}
+
+			f := face{layer: layer, fill: shade(base, t.Normal)}
+			for i, v := range []types.Point3D{t.V1, t.V2, t.V3} {
+				p := project(v)
+				f.points[i] = p
+				f.depth += dot(v, viewDir) / 3
+				b.minX = math.Min(b.minX, p.X)
+				b.minY = math.Min(b.minY, p.Y)
+				b.maxX = math.Max(b.maxX, p.X)
+				b.maxY = math.Max(b.maxY, p.Y)
+			}
+			if isTower {
+				f.depth = towerDepth
+			}
+			faces = append(faces, f)
+		}
+	}
+
+	sort.SliceStable(faces, func(i, j int) bool {
+		if faces[i].layer != faces[j].layer {
+			return faces[i].layer < faces[j].layer
+		}
+		return faces[i].depth < faces[j].depth
+	})
+
+	if len(faces) == 0 {
+		b = bounds{}
+	}
+	return faces, b
+}
+
+// footprintDepth returns the depth of the center of an object's footprint,
+// ignoring its height.
+func footprintDepth(triangles []types.Triangle) float64 {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, t := range triangles {
+		for _, v := range []types.Point3D{t.V1, t.V2, t.V3} {
+			minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+			minY, maxY = math.Min(minY, v.Y), math.Max(maxY, v.Y)
+		}
+	}
+	return dot(types.Point3D{X: (minX + maxX) / 2, Y: (minY + maxY) / 2}, viewDir)
+}
+
+// project maps a model-space point onto the isometric image plane.
+func project(p types.Point3D) point2D {
+	return point2D{X: dot(p, screenX), Y: -dot(p, screenY)}
+}
+
+// shade applies Lambertian lighting for a surface normal to a base color.
+func shade(c color.RGBA, normal types.Point3D) color.RGBA {
+	intensity := ambientLight + diffuseLight*math.Max(0, dot(normal, lightDir))
+	scale := func(v uint8) uint8 {
+		return uint8(math.Min(255, math.Round(float64(v)*intensity)))
+	}
+	return color.RGBA{R: scale(c.R), G: scale(c.G), B: scale(c.B), A: c.A}
+}
+
+// dot returns the dot product of two vectors.
+func dot(a, b types.Point3D) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+// normalize scales a vector to unit length.
+func normalize(v types.Point3D) types.Point3D {
+	length := math.Sqrt(dot(v, v))
+	return types.Point3D{X: v.X / length, Y: v.Y / length, Z: v.Z / length}
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/theme"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// createTestModel returns a model with a base and a single tower standing on it.
+func createTestModel(t *testing.T) *types.Model {
+	t.Helper()
+	base, err := geometry.CreateCuboidBase(20, 10)
+	if err != nil {
+		t.Fatalf("CreateCuboidBase() error = %v", err)
+	}
+	tower, err := geometry.CreateColumn(5, 5, 10, 2.5)
+	if err != nil {
+		t.Fatalf("CreateColumn() error = %v", err)
+	}
+	return &types.Model{Objects: []types.ModelObject{
+		{Name: "tower", Kind: types.ObjectTower, Mesh: types.NewMesh(tower)},
+		{Name: "base", Kind: types.ObjectBase, Mesh: types.NewMesh(base)},
+	}}
+}
+
+func TestProjectModel(t *testing.T) {
+	faces, b := projectModel(createTestModel(t), theme.Palette{})
+
+	// Three faces of each box are visible: front, right and top (2 triangles each)
+	if len(faces) != 12 {
+		t.Fatalf("projectModel() returned %d faces, want 12 after back-face culling", len(faces))
+	}
+	for i := 0; i < 6; i++ {
+		if faces[i].layer != 0 {
+			t.Fatalf("face %d is in layer %d, want base faces drawn first", i, faces[i].layer)
+		}
+	}
+	for i := 7; i < len(faces); i++ {
+		if faces[i].layer == faces[i-1].layer && faces[i].depth < faces[i-1].depth {
+			t.Errorf("faces %d and %d are not ordered back to front", i-1, i)
+		}
+	}
+	if b.width() <= 0 || b.height() <= 0 {
+		t.Errorf("projectModel() bounds = %+v, want positive extent", b)
+	}
+}
+
+func TestProjectModelEmpty(t *testing.T) {
+	faces, b := projectModel(&types.Model{}, theme.Palette{})
+	if len(faces) != 0 {
+		t.Errorf("projectModel() returned %d faces for empty model", len(faces))
+	}
+	if b != (bounds{}) {
+		t.Errorf("projectModel() bounds = %+v, want zero bounds", b)
+	}
+}
+
+func TestProject(t *testing.T) {
+	up := project(types.Point3D{Z: 1})
+	if up.Y >= 0 || math.Abs(up.X) > 1e-9 {
+		t.Errorf("project(+Z) = %+v, want straight up on screen", up)
+	}
+	right := project(types.Point3D{X: 1})
+	if right.X <= 0 {
+		t.Errorf("project(+X) = %+v, want to the right on screen", right)
+	}
+}
+
+func TestShade(t *testing.T) {
+	c := color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	lit := shade(c, lightDir)
+	unlit := shade(c, types.Point3D{X: -lightDir.X, Y: -lightDir.Y, Z: -lightDir.Z})
+	if lit.R <= unlit.R {
+		t.Errorf("shade() lit = %v, unlit = %v, want lit surface brighter", lit, unlit)
+	}
+	if unlit.R != uint8(math.Round(200*ambientLight)) {
+		t.Errorf("shade() unlit = %v, want ambient only", unlit)
+	}
+	if lit.A != 255 {
+		t.Errorf("shade() changed alpha to %d", lit.A)
+	}
+}
+
+func TestObjectColor(t *testing.T) {
+	if got := ObjectColor(types.ObjectTower); got != kindColors[types.ObjectTower] {
+		t.Errorf("ObjectColor(tower) = %v, want %v", got, kindColors[types.ObjectTower])
+	}
+	if got := ObjectColor(""); got != defaultColor {
+		t.Errorf("ObjectColor(no kind) = %v, want the default color %v", got, defaultColor)
+	}
+}
+
+func TestProjectModelPalette(t *testing.T) {
+	model := createTestModel(t)
+	for i := range model.Objects {
+		if model.Objects[i].Kind == types.ObjectTower {
+			model.Objects[i].Material = types.MaterialLevel2
+		}
+	}
+	red := color.RGBA{R: 0xff, A: 0xff}
+
+	tests := []struct {
+		name    string
+		palette theme.Palette
+		want    color.RGBA
+	}{
+		{"default colors", theme.Palette{}, kindColors[types.ObjectTower]},
+		{"palette", theme.Palette{1: red}, red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := objectColor(types.ObjectTower, types.MaterialLevel2, tt.palette); got != tt.want {
+				t.Errorf("objectColor() = %v, want %v", got, tt.want)
+			}
+			// The base, of no level, keeps its own color either way
+			if got := objectColor(types.ObjectBase, types.MaterialBase, tt.palette); got != kindColors[types.ObjectBase] {
+				t.Errorf("objectColor(base) = %v, want %v", got, kindColors[types.ObjectBase])
+			}
+
+			faces, _ := projectModel(model, tt.palette)
+			lit := 0
+			for _, f := range faces {
+				if f.layer == 1 && f.fill.G < 0x10 && f.fill.B < 0x10 {
+					lit++
+				}
+			}
+			if tt.palette.IsZero() == (lit > 0) {
+				t.Errorf("projectModel() drew %d tower faces in red", lit)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image/color"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/theme"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Defaults for raster renders.
+const (
+	DefaultResolution = 1600 // Image width in pixels
+	MaxResolution     = 8192 // Largest supported image width in pixels
+	pngMarginPercent  = 0.04 // Blank space around the model, as a fraction of the width
+)
+
+// DefaultBackground is the background color used when none is specified.
+var DefaultBackground = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+// Options controls how raster images are rendered.
+type Options struct {
+	Resolution int           // Image width in pixels; the height follows the model's aspect ratio
+	Background color.NRGBA   // Background color, may be transparent
+	Palette    theme.Palette // Colors of the towers by contribution level, zero to draw them all in one green
+}
+
+// DefaultOptions returns the default raster render options.
+func DefaultOptions() Options {
+	return Options{Resolution: DefaultResolution, Background: DefaultBackground}
+}
+
+// Validate checks that the options describe a renderable image.
+func (o Options) Validate() error {
+	if o.Resolution <= 0 || o.Resolution > MaxResolution {
+		return errors.New(errors.ValidationError, fmt.Sprintf("resolution must be between 1 and %d pixels", MaxResolution), nil)
+	}
+	return nil
+}
+
+// WritePNG renders a shaded isometric view of the model and saves it as a PNG file.
+func WritePNG(filename string, model *types.Model, opts Options) error {
+	if filename == "" {
+		return errors.New(errors.ValidationError, "PNG filename cannot be empty", nil)
+	}
+	if model == nil {
+		return errors.New(errors.ValidationError, "model cannot be nil", nil)
+	}
+
+	dc, err := renderPNG(model, opts)
+	if err != nil {
+		return err
+	}
+	var encoded bytes.Buffer
+	if err := dc.EncodePNG(&encoded); err != nil {
+		return errors.New(errors.IOError, "failed to encode PNG image", err)
+	}
+	if err := os.WriteFile(filename, addPNGText(encoded.Bytes(), model.Metadata), 0o644); err != nil {
+		return errors.New(errors.IOError, "failed to write PNG file", err)
+	}
+	return nil
+}
+
+// addPNGText returns the encoded PNG with a tEXt chunk for each metadata
+// entry, placed after the 33 bytes of the signature and the IHDR chunk that
+// must come first. PNG keywords are limited to 79 bytes of Latin-1, so keys
+// are cut to length and text outside Latin-1 is replaced.
+func addPNGText(png []byte, metadata []types.Metadata) []byte {
+	const headerEnd = 8 + 4 + 4 + 13 + 4
+	if len(metadata) == 0 || len(png) < headerEnd {
+		return png
+	}
+	latin1 := func(s string) []byte {
+		b := make([]byte, 0, len(s))
+		for _, r := range s {
+			if r > 0xff || r == 0 {
+				r = '?'
+			}
+			b = append(b, byte(r))
+		}
+		return b
+	}
+
+	result := append([]byte(nil), png[:headerEnd]...)
+	for _, m := range metadata {
+		keyword := latin1(m.Key)
+		data := append(append(keyword[:min(len(keyword), 79)], 0), latin1(m.Value)...)
+		chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+		chunk = append(append(chunk, "tEXt"...), data...)
+		chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+		result = append(result, chunk...)
+	}
+	return append(result, png[headerEnd:]...)
+}
+
+// renderPNG rasterizes the projected faces of the model into a new drawing context.
+func renderPNG(model *types.Model, opts Options) (*gg.Context, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	faces, b := projectModel(model, opts.Palette)
+
+	width := float64(opts.Resolution)
+	margin := width * pngMarginPercent
+	scale := 1.0
+	if b.width() > 0 {
+		scale = (width - 2*margin) / b.width()
+	}
+	height := math.Max(1, math.Ceil(b.height()*scale+2*margin))
+
+	dc := gg.NewContext(opts.Resolution, int(height))
+	dc.SetColor(opts.Background)
+	dc.Clear()
+	dc.SetLineWidth(1)
+	dc.SetLineJoinRound()
+
+	toPixel := func(p point2D) (float64, float64) {
+		return (p.X-b.minX)*scale + margin, (p.Y-b.minY)*scale + margin
+	}
+
+	for _, f := range faces {
+		x, y := toPixel(f.points[0])
+		dc.MoveTo(x, y)
+		for _, p := range f.points[1:] {
+			x, y = toPixel(p)
+			dc.LineTo(x, y)
+		}
+		dc.ClosePath()
+		dc.SetColor(f.fill)
+		// Stroke the outline as well to hide anti-aliasing seams between adjacent triangles
+		dc.FillPreserve()
+		dc.Stroke()
+	}
+
+	return dc, nil
+}
+
+// ParseColor parses a CSS-style hex color (#rgb, #rrggbb or #rrggbbaa) or the
+// keyword "transparent".
+func ParseColor(value string) (color.NRGBA, error) {
+	if strings.EqualFold(value, "transparent") {
+		return color.NRGBA{}, nil
+	}
+
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.NRGBA{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid color %q, expected #rgb, #rrggbb or #rrggbbaa", value), nil)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid color %q", value), err)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWritePNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skyline.png")
+	opts := Options{Resolution: 200, Background: color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xff}}
+	if err := WritePNG(path, createTestModel(t), opts); err != nil {
+		t.Fatalf("WritePNG() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Cannot open generated PNG file: %v", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			t.Fatalf("Failed to close PNG file: %v", err)
+		}
+	}()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("Generated file is not a valid PNG: %v", err)
+	}
+	if img.Bounds().Dx() != 200 {
+		t.Errorf("PNG width = %d, want 200", img.Bounds().Dx())
+	}
+
+	r, g, b, _ := img.At(0, 0).RGBA()
+	if r>>8 != 0x12 || g>>8 != 0x34 || b>>8 != 0x56 {
+		t.Errorf("corner pixel = %02x%02x%02x, want background 123456", r>>8, g>>8, b>>8)
+	}
+
+	center := img.At(img.Bounds().Dx()/2, img.Bounds().Dy()/2)
+	if center == img.At(0, 0) {
+		t.Error("center pixel matches background, want model drawn")
+	}
+}
+
+func TestAddPNGText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skyline.png")
+	model := createTestModel(t)
+	model.Metadata = []types.Metadata{{Key: "username", Value: "mona"}, {Key: "flags", Value: "--stack"}}
+	if err := WritePNG(path, model, Options{Resolution: 50}); err != nil {
+		t.Fatalf("WritePNG() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read generated PNG file: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("PNG with text chunks is not valid: %v", err)
+	}
+
+	// Walk the chunks, checking each one's CRC
+	var texts []string
+	for offset := 8; offset+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		chunk := data[offset+4 : offset+8+length]
+		if crc := binary.BigEndian.Uint32(data[offset+8+length:]); crc != crc32.ChecksumIEEE(chunk) {
+			t.Fatalf("%s chunk has CRC %08x, want %08x", chunk[:4], crc, crc32.ChecksumIEEE(chunk))
+		}
+		if string(chunk[:4]) == "tEXt" {
+			texts = append(texts, strings.Replace(string(chunk[4:]), "\x00", "=", 1))
+		}
+		offset += 12 + length
+	}
+	if want := []string{"username=mona", "flags=--stack"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("PNG text chunks = %q, want %q", texts, want)
+	}
+}
+
+func TestWritePNGErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := WritePNG("", &types.Model{}, DefaultOptions()); err == nil {
+		t.Error("WritePNG() expected error for empty filename")
+	}
+	if err := WritePNG(filepath.Join(dir, "nil.png"), nil, DefaultOptions()); err == nil {
+		t.Error("WritePNG() expected error for nil model")
+	}
+	if err := WritePNG(filepath.Join(dir, "zero.png"), &types.Model{}, Options{}); err == nil {
+		t.Error("WritePNG() expected error for zero resolution")
+	}
+	if err := WritePNG(filepath.Join(dir, "huge.png"), &types.Model{}, Options{Resolution: MaxResolution + 1}); err == nil {
+		t.Error("WritePNG() expected error for oversized resolution")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    color.NRGBA
+		wantErr bool
+	}{
+		{"#ffffff", color.NRGBA{R: 255, G: 255, B: 255, A: 255}, false},
+		{"0d1117", color.NRGBA{R: 0x0d, G: 0x11, B: 0x17, A: 255}, false},
+		{"#abc", color.NRGBA{R: 0xaa, G: 0xbb, B: 0xcc, A: 255}, false},
+		{"#ff000080", color.NRGBA{R: 255, A: 0x80}, false},
+		{"transparent", color.NRGBA{}, false},
+		{"#12345", color.NRGBA{}, true},
+		{"#gggggg", color.NRGBA{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseColor(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// svgMargin is the blank space, in millimeters, left around the drawing.
+const svgMargin = 5.0
+
+// svgStrokeWidth is the width, in millimeters, of the seam-hiding outline on each face.
+const svgStrokeWidth = 0.05
+
+// WriteSVG writes an isometric vector drawing of the model to an SVG file.
+//
+// The document is sized in the model's unit so that one model unit maps to one
+// millimeter (or inch) on the page, which keeps the drawing to scale for laser
+// cutters and documentation. Only the palette of opts applies to drawings.
+func WriteSVG(filename string, model *types.Model, opts Options) (err error) {
+	if filename == "" {
+		return errors.New(errors.ValidationError, "SVG filename cannot be empty", nil)
+	}
+	if model == nil {
+		return errors.New(errors.ValidationError, "model cannot be nil", nil)
+	}
+
+	faces, b := projectModel(model, opts.Palette)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create SVG file", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close SVG file", cerr)
+		}
+	}()
+
+	writer := bufio.NewWriter(file)
+	if err := encodeSVG(writer, faces, b, model.Unit, model.Metadata); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush SVG writer", err)
+	}
+	return nil
+}
+
+// encodeSVG writes the SVG document for the projected faces, sized in unit,
+// with the metadata as lines of its description.
+func encodeSVG(writer *bufio.Writer, faces []face, b bounds, unit types.Unit, metadata []types.Metadata) error {
+	if unit == "" {
+		unit = types.UnitMillimeter
+	}
+	margin := svgMargin / unit.Millimeters()
+	width := b.width() + 2*margin
+	height := b.height() + 2*margin
+
+	var desc strings.Builder
+	if len(metadata) > 0 {
+		lines := make([]string, len(metadata))
+		for i, m := range metadata {
+			lines[i] = m.Key + ": " + m.Value
+		}
+		desc.WriteString("<desc>")
+		_ = xml.EscapeText(&desc, []byte(strings.Join(lines, "\n"))) // Writing to a builder cannot fail
+		desc.WriteString("</desc>\n")
+	}
+
+	if _, err := fmt.Fprintf(writer,
+		"<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+			"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s%s\" height=\"%s%s\" viewBox=\"%s %s %s %s\">\n"+
+			"<title>GitHub Contributions Skyline</title>\n"+
+			"%s"+
+			"<g stroke-width=\"%s\" stroke-linejoin=\"round\">\n",
+		formatCoord(width), unit, formatCoord(height), unit,
+		formatCoord(b.minX-margin), formatCoord(b.minY-margin), formatCoord(width), formatCoord(height),
+		desc.String(),
+		formatCoord(svgStrokeWidth/unit.Millimeters()),
+	); err != nil {
+		return errors.New(errors.IOError, "failed to write SVG header", err)
+	}
+
+	for _, f := range faces {
+		// Stroke with the fill color to hide hairline seams between adjacent triangles
+		hex := hexColor(f.fill)
+		if _, err := fmt.Fprintf(writer, "<polygon points=\"%s,%s %s,%s %s,%s\" fill=\"%s\" stroke=\"%s\"/>\n",
+			formatCoord(f.points[0].X), formatCoord(f.points[0].Y),
+			formatCoord(f.points[1].X), formatCoord(f.points[1].Y),
+			formatCoord(f.points[2].X), formatCoord(f.points[2].Y),
+			hex, hex,
+		); err != nil {
+			return errors.New(errors.IOError, "failed to write SVG polygon", err)
+		}
+	}
+
+	if _, err := writer.WriteString("</g>\n</svg>\n"); err != nil {
+		return errors.New(errors.IOError, "failed to write SVG footer", err)
+	}
+	return nil
+}
+
+// formatCoord formats a coordinate with fixed precision, trimming redundant zeros.
+func formatCoord(v float64) string {
+	s := strings.TrimRight(strconv.FormatFloat(v, 'f', 3, 64), "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// hexColor formats a color as an SVG hex color string.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,132 @@
+package render
+
+import (
+	"encoding/xml"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWriteSVG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skyline.svg")
+	if err := WriteSVG(path, createTestModel(t), Options{}); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read generated SVG file: %v", err)
+	}
+
+	var doc struct {
+		XMLName  xml.Name `xml:"svg"`
+		Width    string   `xml:"width,attr"`
+		Polygons []struct {
+			Points string `xml:"points,attr"`
+			Fill   string `xml:"fill,attr"`
+		} `xml:"g>polygon"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Generated SVG is not valid XML: %v", err)
+	}
+	if !strings.HasSuffix(doc.Width, "mm") {
+		t.Errorf("SVG width = %q, want millimeter units", doc.Width)
+	}
+	if len(doc.Polygons) != 12 {
+		t.Errorf("SVG contains %d polygons, want 12", len(doc.Polygons))
+	}
+	for _, p := range doc.Polygons {
+		if len(strings.Fields(p.Points)) != 3 || !strings.HasPrefix(p.Fill, "#") {
+			t.Errorf("invalid polygon points=%q fill=%q", p.Points, p.Fill)
+		}
+	}
+}
+
+func TestWriteSVGMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skyline.svg")
+	model := createTestModel(t)
+	model.Metadata = []types.Metadata{{Key: "username", Value: "mona"}, {Key: "flags", Value: "--logo=<a&b>.svg"}}
+	if err := WriteSVG(path, model, Options{}); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read generated SVG file: %v", err)
+	}
+
+	var doc struct {
+		Desc string `xml:"desc"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Generated SVG is not valid XML: %v", err)
+	}
+	if want := "username: mona\nflags: --logo=<a&b>.svg"; doc.Desc != want {
+		t.Errorf("SVG description = %q, want %q", doc.Desc, want)
+	}
+}
+
+func TestWriteSVGInches(t *testing.T) {
+	model := createTestModel(t)
+	model.Unit = types.UnitInch
+	path := filepath.Join(t.TempDir(), "skyline.svg")
+	if err := WriteSVG(path, model, Options{}); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read generated SVG file: %v", err)
+	}
+	var doc struct {
+		Width  string `xml:"width,attr"`
+		Height string `xml:"height,attr"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Generated SVG is not valid XML: %v", err)
+	}
+	if !strings.HasSuffix(doc.Width, "in") || !strings.HasSuffix(doc.Height, "in") {
+		t.Errorf("SVG size = %q x %q, want inch units", doc.Width, doc.Height)
+	}
+}
+
+func TestWriteSVGErrors(t *testing.T) {
+	if err := WriteSVG("", &types.Model{}, Options{}); err == nil {
+		t.Error("WriteSVG() expected error for empty filename")
+	}
+	if err := WriteSVG(filepath.Join(t.TempDir(), "nil.svg"), nil, Options{}); err == nil {
+		t.Error("WriteSVG() expected error for nil model")
+	}
+	if err := WriteSVG("/nonexistent/path/file.svg", &types.Model{}, Options{}); err == nil {
+		t.Error("WriteSVG() expected error for invalid path")
+	}
+}
+
+func TestFormatCoord(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0"},
+		{1.5, "1.5"},
+		{-2.25, "-2.25"},
+		{10, "10"},
+		{0.0001, "0"},
+		{-0.0001, "0"},
+		{3.14159, "3.142"},
+	}
+	for _, tt := range tests {
+		if got := formatCoord(tt.value); got != tt.want {
+			t.Errorf("formatCoord(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	if got := hexColor(color.RGBA{R: 0x39, G: 0xd3, B: 0x53, A: 0xff}); got != "#39d353" {
+		t.Errorf("hexColor() = %q, want #39d353", got)
+	}
+}
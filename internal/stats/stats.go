@@ -0,0 +1,92 @@
+// Package stats summarizes contribution grids, such as the total number of
+// contributions, the busiest day and week, the longest streak and how
+// contributions fall across the days of the week.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// dateLayout is the format of contribution dates.
+const dateLayout = "2006-01-02"
+
+// Summary holds statistics of a contribution history.
+type Summary struct {
+	Total            int                   // Contributions over all days
+	ActiveDays       int                   // Days with at least one contribution
+	BusiestDay       types.ContributionDay // Day with the most contributions, the earliest on ties
+	LongestStreak    int                   // Most consecutive days with contributions
+	StreakStart      string                // First day of the longest streak, empty without contributions
+	StreakEnd        string                // Last day of the longest streak, empty without contributions
+	BusiestWeek      string                // Sunday starting the week with the most contributions, the earliest on ties
+	BusiestWeekTotal int                   // Contributions in the busiest week
+	Weekdays         [7]int                // Contributions on each day of the week, indexed by time.Weekday
+}
+
+// Compute summarizes contributions laid out as [year][week][day]. Days are
+// taken in calendar order, and a day listed more than once, such as in
+// neighboring years, is counted once. Days with invalid dates are ignored.
+func Compute(contributions [][][]types.ContributionDay) Summary {
+	byDate := make(map[string]types.ContributionDay)
+	for _, year := range contributions {
+		for _, week := range year {
+			for _, day := range week {
+				if _, err := time.Parse(dateLayout, day.Date); err == nil {
+					byDate[day.Date] = day
+				}
+			}
+		}
+	}
+	days := make([]types.ContributionDay, 0, len(byDate))
+	for _, day := range byDate {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+
+	var s Summary
+	var streak int
+	var streakStart string
+	var previous time.Time
+	weeks := make(map[string]int)
+	var weekStarts []string
+	for _, day := range days {
+		date, _ := time.Parse(dateLayout, day.Date)
+		if day.ContributionCount <= 0 {
+			streak = 0
+			continue
+		}
+
+		s.Total += day.ContributionCount
+		s.ActiveDays++
+		s.Weekdays[date.Weekday()] += day.ContributionCount
+		week := date.AddDate(0, 0, -int(date.Weekday())).Format(dateLayout)
+		if _, ok := weeks[week]; !ok {
+			weekStarts = append(weekStarts, week)
+		}
+		weeks[week] += day.ContributionCount
+		if day.ContributionCount > s.BusiestDay.ContributionCount {
+			s.BusiestDay = day
+		}
+
+		if streak > 0 && date.Sub(previous) == 24*time.Hour {
+			streak++
+		} else {
+			streak, streakStart = 1, day.Date
+		}
+		previous = date
+		if streak > s.LongestStreak {
+			s.LongestStreak, s.StreakStart, s.StreakEnd = streak, streakStart, day.Date
+		}
+	}
+
+	// Weeks are met in calendar order, so the first of equally busy ones is kept
+	for _, week := range weekStarts {
+		if weeks[week] > s.BusiestWeekTotal {
+			s.BusiestWeek, s.BusiestWeekTotal = week, weeks[week]
+		}
+	}
+	return s
+}
@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// days builds a week of contribution days starting on the given date.
+func days(start string, counts ...int) []types.ContributionDay {
+	date, err := time.Parse(dateLayout, start)
+	if err != nil {
+		panic(err)
+	}
+	week := make([]types.ContributionDay, len(counts))
+	for i, count := range counts {
+		week[i] = types.ContributionDay{Date: date.AddDate(0, 0, i).Format(dateLayout), ContributionCount: count}
+	}
+	return week
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name          string
+		contributions [][][]types.ContributionDay
+		want          Summary
+	}{
+		{
+			name: "empty",
+			want: Summary{},
+		},
+		{
+			name:          "no contributions",
+			contributions: [][][]types.ContributionDay{{days("2024-01-07", 0, 0, 0, 0, 0, 0, 0)}},
+			want:          Summary{},
+		},
+		{
+			name: "single year",
+			contributions: [][][]types.ContributionDay{{
+				days("2024-01-07", 1, 2, 0, 5, 5, 1, 0),
+				days("2024-01-14", 3, 0, 0, 0, 0, 0, 0),
+			}},
+			want: Summary{
+				Total:            17,
+				ActiveDays:       6,
+				BusiestDay:       types.ContributionDay{Date: "2024-01-10", ContributionCount: 5},
+				LongestStreak:    3,
+				StreakStart:      "2024-01-10",
+				StreakEnd:        "2024-01-12",
+				BusiestWeek:      "2024-01-07",
+				BusiestWeekTotal: 14,
+				Weekdays:         [7]int{4, 2, 0, 5, 5, 1, 0},
+			},
+		},
+		{
+			name: "streak across years",
+			contributions: [][][]types.ContributionDay{
+				{days("2023-12-29", 0, 1, 1)},
+				{days("2024-01-01", 2, 1, 0)},
+			},
+			want: Summary{
+				Total:            5,
+				ActiveDays:       4,
+				BusiestDay:       types.ContributionDay{Date: "2024-01-01", ContributionCount: 2},
+				LongestStreak:    4,
+				StreakStart:      "2023-12-30",
+				StreakEnd:        "2024-01-02",
+				BusiestWeek:      "2023-12-31",
+				BusiestWeekTotal: 4,
+				Weekdays:         [7]int{1, 2, 1, 0, 0, 0, 1},
+			},
+		},
+		{
+			name: "gap in dates breaks streak",
+			contributions: [][][]types.ContributionDay{{
+				days("2024-03-01", 1, 1),
+				days("2024-03-05", 1),
+			}},
+			want: Summary{
+				Total:            3,
+				ActiveDays:       3,
+				BusiestDay:       types.ContributionDay{Date: "2024-03-01", ContributionCount: 1},
+				LongestStreak:    2,
+				StreakStart:      "2024-03-01",
+				StreakEnd:        "2024-03-02",
+				BusiestWeek:      "2024-02-25",
+				BusiestWeekTotal: 2,
+				Weekdays:         [7]int{0, 0, 1, 0, 0, 1, 1},
+			},
+		},
+		{
+			name: "duplicate and invalid days",
+			contributions: [][][]types.ContributionDay{
+				{days("2024-05-01", 4, 4)},
+				{days("2024-05-02", 4), {{Date: "not a date", ContributionCount: 100}}},
+			},
+			want: Summary{
+				Total:            8,
+				ActiveDays:       2,
+				BusiestDay:       types.ContributionDay{Date: "2024-05-01", ContributionCount: 4},
+				LongestStreak:    2,
+				StreakStart:      "2024-05-01",
+				StreakEnd:        "2024-05-02",
+				BusiestWeek:      "2024-04-28",
+				BusiestWeekTotal: 8,
+				Weekdays:         [7]int{0, 0, 0, 4, 4, 0, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compute(tt.contributions); got != tt.want {
+				t.Errorf("Compute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
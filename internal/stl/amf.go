@@ -0,0 +1,139 @@
+package stl
+
+import (
+	"bufio"
+	"encoding/xml"
+	"strconv"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// amfDocument is the root element of an Additive Manufacturing File (ISO/ASTM 52915).
+type amfDocument struct {
+	XMLName  xml.Name      `xml:"amf"`
+	Unit     string        `xml:"unit,attr"`
+	Version  string        `xml:"version,attr"`
+	Metadata []amfMetadata `xml:"metadata"`
+	Objects  []amfObject   `xml:"object"`
+}
+
+// amfMetadata is a typed key/value annotation on the document or an object.
+type amfMetadata struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// amfObject is a single named mesh in the document.
+type amfObject struct {
+	ID       int           `xml:"id,attr"`
+	Metadata []amfMetadata `xml:"metadata"`
+	Mesh     amfMesh       `xml:"mesh"`
+}
+
+// amfMesh holds the shared vertex list and the triangle volume referencing it.
+type amfMesh struct {
+	Vertices []amfVertex `xml:"vertices>vertex"`
+	Volume   amfVolume   `xml:"volume"`
+}
+
+// amfVertex is a vertex position in the mesh.
+type amfVertex struct {
+	X string `xml:"coordinates>x"`
+	Y string `xml:"coordinates>y"`
+	Z string `xml:"coordinates>z"`
+}
+
+// amfVolume is a closed set of triangles.
+type amfVolume struct {
+	Triangles []amfTriangle `xml:"triangle"`
+}
+
+// amfTriangle references three vertices by index, counter-clockwise when viewed from outside.
+type amfTriangle struct {
+	V1 uint32 `xml:"v1"`
+	V2 uint32 `xml:"v2"`
+	V3 uint32 `xml:"v3"`
+}
+
+// toAMFMetadata converts model metadata into AMF metadata elements.
+func toAMFMetadata(metadata []types.Metadata) []amfMetadata {
+	result := make([]amfMetadata, 0, len(metadata))
+	for _, m := range metadata {
+		result = append(result, amfMetadata{Type: m.Key, Value: m.Value})
+	}
+	return result
+}
+
+// buildAMFObject converts a model object into an AMF object with an indexed mesh.
+func buildAMFObject(id int, obj types.ModelObject) (amfObject, error) {
+	mesh, err := buildIndexedMesh(obj.Mesh)
+	if err != nil {
+		return amfObject{}, err
+	}
+
+	metadata := []amfMetadata{{Type: "name", Value: obj.Name}}
+	if obj.Kind != "" {
+		metadata = append(metadata, amfMetadata{Type: "kind", Value: string(obj.Kind)})
+	}
+	metadata = append(metadata, toAMFMetadata(obj.Metadata)...)
+
+	object := amfObject{ID: id, Metadata: metadata}
+	object.Mesh.Vertices = make([]amfVertex, len(mesh.vertices))
+	for i, v := range mesh.vertices {
+		object.Mesh.Vertices[i] = amfVertex{X: formatFloat(v.X), Y: formatFloat(v.Y), Z: formatFloat(v.Z)}
+	}
+	object.Mesh.Volume.Triangles = make([]amfTriangle, len(mesh.faces))
+	for i, f := range mesh.faces {
+		object.Mesh.Volume.Triangles[i] = amfTriangle{V1: f[0], V2: f[1], V3: f[2]}
+	}
+	return object, nil
+}
+
+// WriteAMF writes a model to an uncompressed AMF file.
+//
+// Each model object (base, individual towers, text and logo) becomes its own AMF
+// object carrying the object's metadata, such as the date and contribution count
+// of a tower, so downstream tools can attribute regions of the model.
+// Objects without triangles are omitted because AMF requires at least one triangle per volume.
+func WriteAMF(filename string, model *types.Model) error {
+	if model == nil {
+		return errors.New(errors.ValidationError, "model cannot be nil", nil)
+	}
+
+	unit := "millimeter"
+	if model.Unit == types.UnitInch {
+		unit = "inch"
+	}
+
+	doc := amfDocument{
+		Unit:     unit,
+		Version:  "1.1",
+		Metadata: append([]amfMetadata{{Type: "producer", Value: "GitHub Contributions Skyline Generator"}}, toAMFMetadata(model.Metadata)...),
+	}
+	for _, obj := range model.Objects {
+		if obj.Mesh.Len() == 0 {
+			continue
+		}
+		object, err := buildAMFObject(len(doc.Objects), obj)
+		if err != nil {
+			return errors.Wrap(err, "failed to build AMF object "+strconv.Quote(obj.Name))
+		}
+		doc.Objects = append(doc.Objects, object)
+	}
+
+	return writeFile(filename, func(writer *bufio.Writer) error {
+		if _, err := writer.WriteString(xml.Header); err != nil {
+			return errors.New(errors.IOError, "failed to write AMF header", err)
+		}
+		encoder := xml.NewEncoder(writer)
+		encoder.Indent("", " ")
+		if err := encoder.Encode(doc); err != nil {
+			return errors.New(errors.IOError, "failed to encode AMF document", err)
+		}
+		if _, err := writer.WriteString("\n"); err != nil {
+			return errors.New(errors.IOError, "failed to write AMF document", err)
+		}
+		return nil
+	})
+}
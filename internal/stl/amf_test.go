@@ -0,0 +1,106 @@
+package stl
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWriteAMF(t *testing.T) {
+	model := &types.Model{
+		Metadata: []types.Metadata{{Key: "username", Value: "testuser"}},
+		Objects: []types.ModelObject{
+			{Name: "base", Kind: types.ObjectBase, Mesh: types.NewMesh(createTestQuad())},
+			{Name: "text", Kind: types.ObjectText},
+			{
+				Name:     "tower-0-0",
+				Kind:     types.ObjectTower,
+				Mesh:     types.NewMesh(createTestQuad()),
+				Metadata: []types.Metadata{{Key: "contributions", Value: "5"}},
+			},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "test.amf")
+	if err := WriteAMF(path, model); err != nil {
+		t.Fatalf("WriteAMF() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read generated AMF file: %v", err)
+	}
+
+	var doc amfDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Generated AMF is not valid XML: %v", err)
+	}
+
+	if doc.Unit != "millimeter" {
+		t.Errorf("AMF unit = %q, want millimeter", doc.Unit)
+	}
+	if !hasAMFMetadata(doc.Metadata, "username", "testuser") {
+		t.Errorf("AMF document metadata missing username, got %v", doc.Metadata)
+	}
+	if len(doc.Objects) != 2 {
+		t.Fatalf("AMF contains %d objects, want 2 (empty objects omitted)", len(doc.Objects))
+	}
+
+	tower := doc.Objects[1]
+	if tower.ID != 1 {
+		t.Errorf("tower object id = %d, want 1", tower.ID)
+	}
+	for _, want := range [][2]string{{"name", "tower-0-0"}, {"kind", "tower"}, {"contributions", "5"}} {
+		if !hasAMFMetadata(tower.Metadata, want[0], want[1]) {
+			t.Errorf("tower metadata missing %s=%s, got %v", want[0], want[1], tower.Metadata)
+		}
+	}
+	if len(tower.Mesh.Vertices) != 4 || len(tower.Mesh.Volume.Triangles) != 2 {
+		t.Errorf("tower mesh has %d vertices and %d triangles, want 4 and 2",
+			len(tower.Mesh.Vertices), len(tower.Mesh.Volume.Triangles))
+	}
+}
+
+func TestWriteAMFInches(t *testing.T) {
+	model := &types.Model{
+		Unit:    types.UnitInch,
+		Objects: []types.ModelObject{{Name: "base", Kind: types.ObjectBase, Mesh: types.NewMesh(createTestQuad())}},
+	}
+
+	path := filepath.Join(t.TempDir(), "test.amf")
+	if err := WriteAMF(path, model); err != nil {
+		t.Fatalf("WriteAMF() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read generated AMF file: %v", err)
+	}
+	var doc amfDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Generated AMF is not valid XML: %v", err)
+	}
+	if doc.Unit != "inch" {
+		t.Errorf("AMF unit = %q, want inch", doc.Unit)
+	}
+}
+
+func TestWriteAMFErrors(t *testing.T) {
+	if err := WriteAMF(filepath.Join(t.TempDir(), "nil.amf"), nil); err == nil {
+		t.Error("WriteAMF() expected error for nil model")
+	}
+	if err := WriteAMF("/nonexistent/path/file.amf", &types.Model{}); err == nil {
+		t.Error("WriteAMF() expected error for invalid file path")
+	}
+}
+
+func hasAMFMetadata(metadata []amfMetadata, key, value string) bool {
+	for _, m := range metadata {
+		if m.Type == key && m.Value == value {
+			return true
+		}
+	}
+	return false
+}
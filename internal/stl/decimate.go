@@ -0,0 +1,390 @@
+package stl
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// decimateMinAgreement is the least a face's normal may agree with its
+// normal before an edge collapse, as the cosine of the angle it turns by.
+// Collapses turning faces further, or over, are skipped.
+const decimateMinAgreement = 0.2
+
+// quadric measures the summed squared distance of a point from a set of
+// planes, stored as the upper triangle of a symmetric 4x4 matrix:
+// aa ab ac ad bb bc bd cc cd dd.
+type quadric [10]float64
+
+// planeQuadric returns the quadric of the plane through p with unit normal n,
+// weighted by w.
+func planeQuadric(n, p types.Point3D, w float64) quadric {
+	a, b, c := n.X, n.Y, n.Z
+	d := -(a*p.X + b*p.Y + c*p.Z)
+	return quadric{w * a * a, w * a * b, w * a * c, w * a * d, w * b * b, w * b * c, w * b * d, w * c * c, w * c * d, w * d * d}
+}
+
+// add returns the sum of two quadrics.
+func (q quadric) add(o quadric) quadric {
+	for i := range q {
+		q[i] += o[i]
+	}
+	return q
+}
+
+// error returns the weighted squared distance of p from the quadric's planes.
+func (q quadric) error(p types.Point3D) float64 {
+	x, y, z := p.X, p.Y, p.Z
+	return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+		q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z +
+		q[9]
+}
+
+// edgeCollapse is a candidate merge of vertex drop into vertex keep, which
+// moves to target. The versions of both vertices when the candidate was made
+// tell whether it is still current.
+type edgeCollapse struct {
+	cost                     float64
+	keep, drop               uint32
+	target                   types.Point3D
+	keepVersion, dropVersion int
+}
+
+// collapseQueue orders candidate collapses cheapest first, and by vertex for
+// equal costs, so the same mesh always decimates the same way.
+type collapseQueue []edgeCollapse
+
+func (q collapseQueue) Len() int { return len(q) }
+func (q collapseQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	if q[i].keep != q[j].keep {
+		return q[i].keep < q[j].keep
+	}
+	return q[i].drop < q[j].drop
+}
+func (q collapseQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+// Push adds a candidate collapse, for container/heap.
+func (q *collapseQueue) Push(x any) { *q = append(*q, x.(edgeCollapse)) }
+
+// Pop removes the last candidate collapse, for container/heap.
+func (q *collapseQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// decimator simplifies the meshes of a model together, so the collapses
+// changing the shape least are made first wherever they are.
+type decimator struct {
+	vertices []types.Point3D
+	quadrics []quadric
+	versions []int   // Bumped whenever a vertex moves or is merged away
+	around   [][]int // Faces using each vertex, including faces since removed
+	faces    [][3]uint32
+	objects  []int     // Object each face belongs to
+	parts    []int     // Closed part of the mesh each face belongs to
+	volumes  []float64 // Signed volume enclosed by each part, times six
+	removed  []bool
+	alive    int
+	queue    collapseQueue
+}
+
+// decimateModel simplifies the model's meshes in place by collapsing edges,
+// cheapest first by the quadric error of the planes around them, until at
+// most ratio of the model's triangles are left or no collapse keeps the
+// meshes closed and their faces facing the same way. It returns the number
+// of triangles before and after.
+func decimateModel(model *types.Model, ratio float64) (int, int) {
+	before := model.TriangleCount()
+	if ratio >= 1 || before == 0 {
+		return before, before
+	}
+
+	d := &decimator{}
+	for i, obj := range model.Objects {
+		offset := uint32(len(d.vertices))
+		d.vertices = append(d.vertices, obj.Mesh.Vertices...)
+		for _, f := range obj.Mesh.Faces {
+			d.faces = append(d.faces, [3]uint32{f.V[0] + offset, f.V[1] + offset, f.V[2] + offset})
+			d.objects = append(d.objects, i)
+		}
+	}
+	d.quadrics = make([]quadric, len(d.vertices))
+	d.versions = make([]int, len(d.vertices))
+	d.around = make([][]int, len(d.vertices))
+	d.removed = make([]bool, len(d.faces))
+	d.alive = len(d.faces)
+	d.findParts()
+	for i, f := range d.faces {
+		normal, area := faceNormal(d.vertices, f)
+		q := planeQuadric(normal, d.vertices[f[0]], area)
+		for _, v := range f {
+			d.quadrics[v] = d.quadrics[v].add(q)
+			d.around[v] = append(d.around[v], i)
+		}
+	}
+	for _, f := range d.faces {
+		for k := 0; k < 3; k++ {
+			// Each edge of a closed mesh is listed twice, once each way
+			if a, b := f[k], f[(k+1)%3]; a < b {
+				d.push(a, b)
+			}
+		}
+	}
+
+	target := int(math.Ceil(ratio * float64(before)))
+	for d.alive > target && d.queue.Len() > 0 {
+		c := heap.Pop(&d.queue).(edgeCollapse)
+		if c.keepVersion != d.versions[c.keep] || c.dropVersion != d.versions[c.drop] {
+			continue
+		}
+		d.collapse(c)
+	}
+
+	// Rebuild each object's mesh from its remaining faces
+	meshes := make([]types.Mesh, len(model.Objects))
+	remap := make(map[uint32]uint32)
+	last := -1
+	for i, f := range d.faces {
+		if d.removed[i] {
+			continue
+		}
+		obj := d.objects[i]
+		if obj != last {
+			remap, last = make(map[uint32]uint32), obj
+		}
+		mesh := &meshes[obj]
+		var face types.Face
+		for k, v := range f {
+			idx, ok := remap[v]
+			if !ok {
+				idx = uint32(len(mesh.Vertices))
+				remap[v] = idx
+				mesh.Vertices = append(mesh.Vertices, d.vertices[v])
+			}
+			face.V[k] = idx
+		}
+		face.Normal, _ = faceNormal(mesh.Vertices, face.V)
+		mesh.Faces = append(mesh.Faces, face)
+	}
+	for i := range model.Objects {
+		model.Objects[i].Mesh = meshes[i]
+	}
+	return before, d.alive
+}
+
+// findParts numbers the parts of the meshes, made of faces joined along
+// edges between exactly two faces, as orientFaces joins them, and sums the
+// volume each encloses. Solids touching along an edge or at a corner are
+// separate parts.
+func (d *decimator) findParts() {
+	parent := make([]int, len(d.faces))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	type edge struct{ a, b uint32 }
+	owners := make(map[edge][]int, len(d.faces)*3/2)
+	for i, f := range d.faces {
+		for k := 0; k < 3; k++ {
+			a, b := f[k], f[(k+1)%3]
+			if a > b {
+				a, b = b, a
+			}
+			owners[edge{a, b}] = append(owners[edge{a, b}], i)
+		}
+	}
+	for _, faces := range owners {
+		if len(faces) == 2 {
+			parent[find(faces[1])] = find(faces[0])
+		}
+	}
+
+	ids := make(map[int]int)
+	d.parts = make([]int, len(d.faces))
+	for i := range d.faces {
+		root := find(i)
+		id, ok := ids[root]
+		if !ok {
+			id = len(ids)
+			ids[root] = id
+		}
+		d.parts[i] = id
+	}
+	d.volumes = make([]float64, len(ids))
+	for i, f := range d.faces {
+		d.volumes[d.parts[i]] += tripleProduct(d.vertices[f[0]], d.vertices[f[1]], d.vertices[f[2]])
+	}
+}
+
+// tripleProduct returns six times the signed volume of the tetrahedron
+// between the origin and a face, which summed over a closed mesh gives six
+// times the volume it encloses.
+func tripleProduct(a, b, c types.Point3D) float64 {
+	return a.X*(b.Y*c.Z-b.Z*c.Y) - a.Y*(b.X*c.Z-b.Z*c.X) + a.Z*(b.X*c.Y-b.Y*c.X)
+}
+
+// push queues the collapse of the edge between a and b, keeping the lower
+// numbered vertex at whichever of the two ends or their midpoint adds the
+// least error.
+func (d *decimator) push(a, b uint32) {
+	if a > b {
+		a, b = b, a
+	}
+	q := d.quadrics[a].add(d.quadrics[b])
+	pa, pb := d.vertices[a], d.vertices[b]
+	best := edgeCollapse{cost: math.Inf(1), keep: a, drop: b, keepVersion: d.versions[a], dropVersion: d.versions[b]}
+	for _, p := range []types.Point3D{pa, pb, {X: (pa.X + pb.X) / 2, Y: (pa.Y + pb.Y) / 2, Z: (pa.Z + pb.Z) / 2}} {
+		// Rounding can take the error of a point on every plane just below zero
+		if cost := math.Max(0, q.error(p)); cost < best.cost {
+			best.cost, best.target = cost, p
+		}
+	}
+	heap.Push(&d.queue, best)
+}
+
+// collapse merges c.drop into c.keep at c.target, unless that would tear the
+// mesh, turn faces over or turn the part it is in inside out.
+func (d *decimator) collapse(c edgeCollapse) {
+	// The edge must lie between exactly two faces, whose third corners are
+	// the only vertices both ends have as neighbors
+	var shared []int
+	dropNeighbors := make(map[uint32]bool)
+	for _, i := range d.around[c.drop] {
+		if d.removed[i] {
+			continue
+		}
+		f := d.faces[i]
+		if f[0] == c.keep || f[1] == c.keep || f[2] == c.keep {
+			shared = append(shared, i)
+		}
+		for _, v := range f {
+			if v != c.drop {
+				dropNeighbors[v] = true
+			}
+		}
+	}
+	if len(shared) != 2 {
+		return
+	}
+	opposite := make(map[uint32]bool, 2)
+	for _, i := range shared {
+		for _, v := range d.faces[i] {
+			if v != c.keep && v != c.drop {
+				opposite[v] = true
+			}
+		}
+	}
+	common := make(map[uint32]bool)
+	for _, i := range d.around[c.keep] {
+		if d.removed[i] {
+			continue
+		}
+		for _, v := range d.faces[i] {
+			if v != c.keep && dropNeighbors[v] {
+				common[v] = true
+			}
+		}
+	}
+	if len(common) != len(opposite) {
+		return
+	}
+	for v := range common {
+		if !opposite[v] {
+			return
+		}
+	}
+
+	// Every other face around either end must keep facing about the same way
+	moved := func(v uint32) types.Point3D {
+		if v == c.keep || v == c.drop {
+			return c.target
+		}
+		return d.vertices[v]
+	}
+	volumeChange := make(map[int]float64)
+	for _, i := range shared {
+		f := d.faces[i]
+		volumeChange[d.parts[i]] -= tripleProduct(d.vertices[f[0]], d.vertices[f[1]], d.vertices[f[2]])
+	}
+	for _, end := range []uint32{c.keep, c.drop} {
+		for _, i := range d.around[end] {
+			if d.removed[i] || i == shared[0] || i == shared[1] {
+				continue
+			}
+			f := d.faces[i]
+			before, _ := faceNormal(d.vertices, f)
+			after, area := faceNormal([]types.Point3D{moved(f[0]), moved(f[1]), moved(f[2])}, [3]uint32{0, 1, 2})
+			if area == 0 || before.X*after.X+before.Y*after.Y+before.Z*after.Z < decimateMinAgreement {
+				return
+			}
+			volumeChange[d.parts[i]] += tripleProduct(moved(f[0]), moved(f[1]), moved(f[2])) - tripleProduct(d.vertices[f[0]], d.vertices[f[1]], d.vertices[f[2]])
+		}
+	}
+
+	// Thin parts can turn inside out with no face turning far, so each part
+	// must still enclose a volume of the same sign, positive for a solid and
+	// negative for a cavity
+	for part, change := range volumeChange {
+		if (d.volumes[part]+change)*d.volumes[part] <= 0 {
+			return
+		}
+	}
+	for part, change := range volumeChange {
+		d.volumes[part] += change
+	}
+
+	for _, i := range shared {
+		d.removed[i] = true
+		d.alive--
+	}
+	for _, i := range d.around[c.drop] {
+		if d.removed[i] {
+			continue
+		}
+		for k, v := range d.faces[i] {
+			if v == c.drop {
+				d.faces[i][k] = c.keep
+			}
+		}
+		d.around[c.keep] = append(d.around[c.keep], i)
+	}
+	d.around[c.drop] = nil
+	d.vertices[c.keep] = c.target
+	d.quadrics[c.keep] = d.quadrics[c.keep].add(d.quadrics[c.drop])
+	d.versions[c.keep]++
+	d.versions[c.drop]++
+
+	// Drop removed faces from the kept vertex, and requeue its edges with their new costs
+	faces := d.around[c.keep][:0]
+	neighbors := make(map[uint32]bool)
+	var order []uint32
+	for _, i := range d.around[c.keep] {
+		if d.removed[i] {
+			continue
+		}
+		faces = append(faces, i)
+		for _, v := range d.faces[i] {
+			if v != c.keep && !neighbors[v] {
+				neighbors[v] = true
+				order = append(order, v)
+			}
+		}
+	}
+	d.around[c.keep] = faces
+	for _, v := range order {
+		d.push(c.keep, v)
+	}
+}
@@ -0,0 +1,149 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// createSubdividedCube returns a closed unit cube whose faces are each split
+// into n by n squares of two triangles, sharing vertices along every seam.
+func createSubdividedCube(n int) []types.Triangle {
+	var triangles []types.Triangle
+	// Each face is spanned from its corner origin along u and v, with u x v facing out
+	faces := []struct{ origin, u, v types.Point3D }{
+		{types.Point3D{}, types.Point3D{Y: 1}, types.Point3D{X: 1}},     // Bottom
+		{types.Point3D{Z: 1}, types.Point3D{X: 1}, types.Point3D{Y: 1}}, // Top
+		{types.Point3D{}, types.Point3D{X: 1}, types.Point3D{Z: 1}},     // Front
+		{types.Point3D{Y: 1}, types.Point3D{Z: 1}, types.Point3D{X: 1}}, // Back
+		{types.Point3D{}, types.Point3D{Z: 1}, types.Point3D{Y: 1}},     // Left
+		{types.Point3D{X: 1}, types.Point3D{Y: 1}, types.Point3D{Z: 1}}, // Right
+	}
+	for _, f := range faces {
+		at := func(i, j int) types.Point3D {
+			s, t := float64(i)/float64(n), float64(j)/float64(n)
+			return types.Point3D{
+				X: f.origin.X + s*f.u.X + t*f.v.X,
+				Y: f.origin.Y + s*f.u.Y + t*f.v.Y,
+				Z: f.origin.Z + s*f.u.Z + t*f.v.Z,
+			}
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				triangles = append(triangles,
+					types.Triangle{V1: at(i, j), V2: at(i+1, j), V3: at(i+1, j+1)},
+					types.Triangle{V1: at(i, j), V2: at(i+1, j+1), V3: at(i, j+1)},
+				)
+			}
+		}
+	}
+	return triangles
+}
+
+func TestDecimateModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		ratio     float64
+		wantAfter int
+	}{
+		{"keep everything", 1, 192},
+		{"half", 0.5, 96},
+		{"down to a box", 12.0 / 192, 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &types.Model{Objects: []types.ModelObject{{Name: "cube", Mesh: types.NewMesh(createSubdividedCube(4))}}}
+			before, after := decimateModel(model, tt.ratio)
+			if before != 192 || after != tt.wantAfter {
+				t.Errorf("decimateModel() = %d, %d, want 192, %d", before, after, tt.wantAfter)
+			}
+			mesh := model.Objects[0].Mesh
+			if mesh.Len() != after {
+				t.Errorf("mesh has %d faces, want %d", mesh.Len(), after)
+			}
+			if report := checkMesh(mesh); !report.ok() {
+				t.Errorf("decimated mesh has %s", report)
+			}
+
+			// Only collapses within the cube's flat faces and along its edges are
+			// needed, so the cube keeps its shape
+			triangles := mesh.Triangles()
+			if volume := signedVolume(mesh); math.Abs(volume-1) > 1e-9 {
+				t.Errorf("decimated cube volume = %v, want 1", volume)
+			}
+			// Rebuilt meshes get their normals from their winding
+			if after == before {
+				return
+			}
+			for i, tri := range triangles {
+				if normal, _ := faceNormal(mesh.Vertices, mesh.Faces[i].V); !vectorAgrees(normal, tri.Normal) {
+					t.Errorf("face %d normal = %v, want %v from its winding", i, tri.Normal, normal)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestDecimateModelObjects(t *testing.T) {
+	// The fine cube's flat faces are simplified before the plain box is touched
+	box := createTestCube(t, 2)
+	model := &types.Model{Objects: []types.ModelObject{
+		{Name: "fine", Mesh: types.NewMesh(createSubdividedCube(4))},
+		{Name: "box", Mesh: types.NewMesh(box)},
+	}}
+	before, after := decimateModel(model, 24.0/204)
+	if before != 204 || after != 24 {
+		t.Errorf("decimateModel() = %d, %d, want 204, 24", before, after)
+	}
+	if got := model.Objects[1].Mesh.Len(); got != 12 {
+		t.Errorf("box has %d faces, want its 12 untouched", got)
+	}
+	if model.Objects[0].Name != "fine" || model.Objects[1].Name != "box" {
+		t.Error("decimateModel() reordered the objects")
+	}
+}
+
+func TestBuildModelDecimate(t *testing.T) {
+	contributions := fixtureContributions(2024, 2024)
+	cfg := geometry.DefaultConfig()
+	dims, err := calculateDimensions(cfg, len(contributions))
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	maxContrib := findMaxContributionsAcrossYears(contributions)
+
+	full, err := buildModel(contributions, dims, maxContrib, Options{Username: "testuser", StartYear: 2024, EndYear: 2024, Geometry: cfg}, nil)
+	if err != nil {
+		t.Fatalf("buildModel() error = %v", err)
+	}
+	decimated, err := buildModel(contributions, dims, maxContrib, Options{Username: "testuser", StartYear: 2024, EndYear: 2024, Geometry: cfg, Decimate: 0.5}, nil)
+	if err != nil {
+		t.Fatalf("buildModel() with decimation error = %v", err)
+	}
+
+	if got, limit := decimated.TriangleCount(), (full.TriangleCount()+1)/2; got > limit {
+		t.Errorf("decimated model has %d triangles, want at most %d", got, limit)
+	}
+	if err := validateModel(decimated); err != nil {
+		t.Errorf("decimated model is not watertight: %v", err)
+	}
+}
+
+func TestGenerateModelDecimateRatio(t *testing.T) {
+	for _, ratio := range []float64{-0.5, 1.5, math.NaN()} {
+		err := GenerateModel([][][]types.ContributionDay{createTestContributions()}, Options{
+			OutputPath: "skyline.stl",
+			Username:   "testuser",
+			StartYear:  2024,
+			EndYear:    2024,
+			Decimate:   ratio,
+		})
+		if err == nil {
+			t.Errorf("GenerateModel() with decimate ratio %v error = nil, want a validation error", ratio)
+		}
+	}
+}
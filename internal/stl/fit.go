@@ -0,0 +1,65 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Bed is the printable area, in millimeters, that a model is scaled to fit.
+// The zero Bed disables fitting.
+type Bed struct {
+	Width float64 // Extent along X
+	Depth float64 // Extent along Y
+}
+
+// Format formats the bed as WIDTHxDEPTH in unit, such as 220x220mm.
+func (b Bed) Format(unit types.Unit) string {
+	return fmt.Sprintf("%.4gx%s", b.Width/unit.Millimeters(), unit.Format(b.Depth))
+}
+
+// IsZero reports whether no bed was configured.
+func (b Bed) IsZero() bool {
+	return b.Width == 0 && b.Depth == 0
+}
+
+// ParseBed parses a bed size written as WIDTHxDEPTH, for example "220x220".
+// An empty string returns the zero Bed.
+func ParseBed(value string) (Bed, error) {
+	if value == "" {
+		return Bed{}, nil
+	}
+
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(value)), "x")
+	if len(parts) != 2 {
+		return Bed{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid bed size %q, expected WIDTHxDEPTH (e.g., 220x220)", value), nil)
+	}
+
+	var dims [2]float64
+	for i, part := range parts {
+		dim, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(dim) || math.IsInf(dim, 0) || dim <= 0 {
+			return Bed{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid bed size %q, dimensions must be positive numbers of millimeters", value), err)
+		}
+		dims[i] = dim
+	}
+	return Bed{Width: dims[0], Depth: dims[1]}, nil
+}
+
+// fitToBed uniformly scales model so that its footprint fills bed without
+// exceeding it, and returns the applied scale factor.
+func fitToBed(model *types.Model, bed Bed) (float64, error) {
+	minPoint, maxPoint := model.Bounds()
+	width, depth := maxPoint.X-minPoint.X, maxPoint.Y-minPoint.Y
+	if width <= 0 || depth <= 0 {
+		return 0, errors.New(errors.ValidationError, "cannot fit an empty model to the print bed", nil)
+	}
+
+	factor := math.Min(bed.Width/width, bed.Depth/depth)
+	model.Scale(factor)
+	return factor, nil
+}
@@ -0,0 +1,82 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseBed(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Bed
+		wantErr bool
+	}{
+		{"empty disables fitting", "", Bed{}, false},
+		{"square bed", "220x220", Bed{Width: 220, Depth: 220}, false},
+		{"decimal and uppercase", "250.5X210", Bed{Width: 250.5, Depth: 210}, false},
+		{"missing depth", "220", Bed{}, true},
+		{"too many parts", "220x220x250", Bed{}, true},
+		{"not a number", "widex220", Bed{}, true},
+		{"zero width", "0x220", Bed{}, true},
+		{"negative depth", "220x-10", Bed{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBed(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBed(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBed(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitToBed(t *testing.T) {
+	tests := []struct {
+		name       string
+		bed        Bed
+		wantFactor float64
+	}{
+		{"shrink to width", Bed{Width: 100, Depth: 100}, 0.5},
+		{"shrink to depth", Bed{Width: 400, Depth: 25}, 0.5},
+		{"enlarge to fill", Bed{Width: 400, Depth: 400}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &types.Model{Objects: []types.ModelObject{{
+				Mesh: types.NewMesh([]types.Triangle{{
+					V1: types.Point3D{X: 0, Y: 0, Z: -10},
+					V2: types.Point3D{X: 200, Y: 0, Z: 0},
+					V3: types.Point3D{X: 200, Y: 50, Z: 30},
+				}}),
+			}}}
+
+			factor, err := fitToBed(model, tt.bed)
+			if err != nil {
+				t.Fatalf("fitToBed() error = %v", err)
+			}
+			if math.Abs(factor-tt.wantFactor) > 1e-9 {
+				t.Errorf("fitToBed() factor = %v, want %v", factor, tt.wantFactor)
+			}
+
+			minPoint, maxPoint := model.Bounds()
+			if maxPoint.X-minPoint.X > tt.bed.Width+1e-9 || maxPoint.Y-minPoint.Y > tt.bed.Depth+1e-9 {
+				t.Errorf("scaled model %vx%v exceeds bed %+v", maxPoint.X-minPoint.X, maxPoint.Y-minPoint.Y, tt.bed)
+			}
+			if math.Abs(maxPoint.Z-30*tt.wantFactor) > 1e-9 {
+				t.Errorf("height was not scaled uniformly: max Z = %v", maxPoint.Z)
+			}
+		})
+	}
+
+	if _, err := fitToBed(&types.Model{}, Bed{Width: 100, Depth: 100}); err == nil {
+		t.Error("fitToBed() expected error for an empty model")
+	}
+}
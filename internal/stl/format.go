@@ -0,0 +1,148 @@
+package stl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Format identifies an output file format the model can be written in.
+type Format string
+
+// Supported output formats.
+const (
+	FormatSTL      Format = "stl"       // Binary STL
+	FormatPLY      Format = "ply"       // Binary little-endian PLY
+	FormatPLYASCII Format = "ply-ascii" // ASCII PLY
+	FormatAMF      Format = "amf"       // Additive Manufacturing File with per-object metadata
+	Format3MF      Format = "3mf"       // 3D Manufacturing Format with towers colored by contribution level
+	FormatSVG      Format = "svg"       // Isometric vector drawing of the model
+	FormatPNG      Format = "png"       // Shaded isometric raster render of the model
+)
+
+// formats lists the supported formats in the order they are presented to users.
+var formats = []Format{FormatSTL, FormatPLY, FormatPLYASCII, FormatAMF, Format3MF, FormatSVG, FormatPNG}
+
+// Formats returns the names of all supported output formats.
+func Formats() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// ParseFormat converts a user supplied format name into a Format.
+// Matching is case-insensitive and an empty string selects STL.
+func ParseFormat(name string) (Format, error) {
+	if name == "" {
+		return FormatSTL, nil
+	}
+	for _, f := range formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported output format %q (supported: %s)", name, strings.Join(Formats(), ", ")), nil)
+}
+
+// Extension returns the file extension, including the leading dot, used for the format.
+func (f Format) Extension() string {
+	switch f {
+	case FormatPLY, FormatPLYASCII:
+		return ".ply"
+	case FormatAMF:
+		return ".amf"
+	case Format3MF:
+		return ".3mf"
+	case FormatSVG:
+		return ".svg"
+	case FormatPNG:
+		return ".png"
+	default:
+		return ".stl"
+	}
+}
+
+// WriteModel writes a model to filename using the writer registered for format.
+// Formats without object support receive the model's flattened triangle list.
+// renderOpts only applies to image formats, but for its palette, which colors
+// the contribution levels of every format holding colors.
+func WriteModel(filename string, format Format, model *types.Model, renderOpts render.Options) error {
+	return WriteModelContext(context.Background(), filename, format, model, renderOpts)
+}
+
+// WriteModelContext is WriteModel that gives up when ctx is done: before
+// starting the file, or partway through an STL file, which is then removed.
+func WriteModelContext(ctx context.Context, filename string, format Format, model *types.Model, renderOpts render.Options) error {
+	if model == nil {
+		return errors.New(errors.ValidationError, "model cannot be nil", nil)
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.New(errors.IOError, "writing model file canceled", err)
+	}
+
+	// Only STL files are written a triangle at a time, the others at once
+	bar := progress.FromContext(ctx)
+	if format == FormatSTL || format == "" {
+		bar.Start("Writing "+filepath.Base(filename), "triangles", model.TriangleCount())
+	} else {
+		bar.Start("Writing "+filepath.Base(filename), "", 0)
+	}
+	defer bar.Finish()
+
+	switch format {
+	case FormatSTL, "":
+		return writeSTLBinary(ctx, filename, model.Triangles(), model.Metadata)
+	case FormatPLY:
+		return writePLYBinary(filename, model.Mesh(), model.Metadata)
+	case FormatPLYASCII:
+		return writePLYASCII(filename, model.Mesh(), model.Metadata)
+	case FormatAMF:
+		return WriteAMF(filename, model)
+	case Format3MF:
+		return write3MF(filename, model, renderOpts.Palette)
+	case FormatSVG:
+		return render.WriteSVG(filename, model, renderOpts)
+	case FormatPNG:
+		return render.WritePNG(filename, model, renderOpts)
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("unsupported output format %q", format), nil)
+	}
+}
+
+// writeFile creates filename and passes a buffered writer for it to write.
+// The writer is flushed and the file closed before returning, and any error
+// from either step is reported if write itself succeeded.
+func writeFile(filename string, write func(*bufio.Writer) error) (err error) {
+	if filename == "" {
+		return errors.New(errors.ValidationError, "output filename cannot be empty", nil)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create output file", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close output file", cerr)
+		}
+	}()
+
+	writer := bufio.NewWriterSize(file, bufferSize)
+	if err := write(writer); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush writer", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package stl
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"default", "", FormatSTL, false},
+		{"stl", "stl", FormatSTL, false},
+		{"uppercase", "PLY", FormatPLY, false},
+		{"ply ascii", "ply-ascii", FormatPLYASCII, false},
+		{"amf", "amf", FormatAMF, false},
+		{"3mf", "3MF", Format3MF, false},
+		{"svg", "svg", FormatSVG, false},
+		{"png", "png", FormatPNG, false},
+		{"unknown", "obj", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatExtension(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatSTL, ".stl"},
+		{FormatPLY, ".ply"},
+		{FormatPLYASCII, ".ply"},
+		{FormatAMF, ".amf"},
+		{Format3MF, ".3mf"},
+		{FormatSVG, ".svg"},
+		{FormatPNG, ".png"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Extension(); got != tt.want {
+			t.Errorf("%s.Extension() = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestWriteModel(t *testing.T) {
+	dir := t.TempDir()
+	model := &types.Model{Objects: []types.ModelObject{{Name: "quad", Mesh: types.NewMesh(createTestQuad())}}}
+	for _, name := range Formats() {
+		format := Format(name)
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "model-"+name+format.Extension())
+			if err := WriteModel(path, format, model, render.DefaultOptions()); err != nil {
+				t.Fatalf("WriteModel() error = %v", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("model file was not created: %v", err)
+			}
+			if info.Size() == 0 {
+				t.Error("model file is empty")
+			}
+		})
+	}
+
+	if err := WriteModel(filepath.Join(dir, "model.obj"), Format("obj"), model, render.DefaultOptions()); err == nil {
+		t.Error("WriteModel() expected error for unsupported format")
+	}
+	if err := WriteModel(filepath.Join(dir, "nil.stl"), FormatSTL, nil, render.DefaultOptions()); err == nil {
+		t.Error("WriteModel() expected error for nil model")
+	}
+}
+
+func TestWriteModelContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	model := &types.Model{Objects: []types.ModelObject{{Name: "quad", Mesh: types.NewMesh(createTestQuad())}}}
+	for _, name := range Formats() {
+		format := Format(name)
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "model"+format.Extension())
+			if err := WriteModelContext(ctx, path, format, model, render.DefaultOptions()); !stderrors.Is(err, context.Canceled) {
+				t.Errorf("WriteModelContext() error = %v, want it canceled", err)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("WriteModelContext() created the file: %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteModelMetadata(t *testing.T) {
+	dir := t.TempDir()
+	model := &types.Model{
+		Objects:  []types.ModelObject{{Name: "quad", Mesh: types.NewMesh(createTestQuad())}},
+		Metadata: []types.Metadata{{Key: "version", Value: "v1.2.3"}, {Key: "flags", Value: "--stack"}},
+	}
+	for _, name := range Formats() {
+		format := Format(name)
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "model-"+name+format.Extension())
+			if err := WriteModel(path, format, model, render.DefaultOptions()); err != nil {
+				t.Fatalf("WriteModel() error = %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read model file: %v", err)
+			}
+			// The 3MF model document is compressed inside its package
+			if format == Format3MF {
+				doc := readThreeMFModel(t, path)
+				data = nil
+				for _, m := range doc.Metadata {
+					data = append(data, m.Name+"="+m.Value+"\n"...)
+				}
+			}
+			for _, want := range []string{"version", "v1.2.3", "--stack"} {
+				if !bytes.Contains(data, []byte(want)) {
+					t.Errorf("%s file does not contain %q", name, want)
+				}
+			}
+		})
+	}
+}
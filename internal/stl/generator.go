@@ -1,10 +1,18 @@
 package stl
 
 import (
+	"context"
 	"fmt"
+	"image"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/stats"
 	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/types"
 )
@@ -17,8 +25,35 @@ func GenerateSTL(contributions [][]types.ContributionDay, outputPath, username s
 	return GenerateSTLRange(contributionsRange, outputPath, username, year, year)
 }
 
-// GenerateSTLRange creates a 3D model from multiple years of GitHub contribution data.
-// It handles the complete process from data validation through geometry generation to file output.
+// Options describes the model to generate and where to write it.
+type Options struct {
+	OutputPath string           // Destination path for the model file
+	Format     Format           // Output file format, defaults to binary STL
+	Username   string           // GitHub username rendered on the model
+	StartYear  int              // First year in the range
+	EndYear    int              // Last year in the range
+	Years      []int            // Year of each grid of contributions, oldest first, consecutive from StartYear when empty
+	Period     string           // Label of the period covered, such as 2024.01-06, in place of the years when set
+	Fit        Bed              // Print bed to scale the finished model to, zero to keep its size
+	Unit       types.Unit       // Unit of the exported coordinates, defaults to millimeters
+	SplitParts bool             // Write the base, towers, text and logo to separate files
+	SplitYears bool             // Write each year to its own file, next to an index of the files
+	Mirror     bool             // Mirror the model left to right, for use as a stamp or mold master
+	Repair     bool             // Weld vertices, drop degenerate faces and fix inverted faces before writing
+	Decimate   float64          // Fraction of the model's triangles to simplify it down to, zero to keep them all
+	Stats      *stats.Summary   // Statistics embossed when Geometry.Stats is set, computed from the contributions when nil
+	YearStats  []stats.Summary  // Statistics of each year when the years are split, computed from the contributions when missing
+	Avatar     image.Image      // User's avatar, required when Geometry.Avatar is set
+	Metadata   []types.Metadata // How the model was generated, such as the tool version and flag settings, written into the file
+
+	Geometry geometry.Config // Model measurements, zero values select the defaults
+	Render   render.Options  // Settings for image formats, and the palette of all formats holding colors
+
+	progress *progress.Bar // Counts the triangles generated, from the context generating the model
+}
+
+// GenerateSTLRange creates a 3D model from multiple years of GitHub contribution data
+// and writes it as a binary STL file.
 // Parameters:
 //   - contributions: 3D slice of contribution data ([year][week][day])
 //   - outputPath: destination path for the STL file
@@ -26,22 +61,101 @@ func GenerateSTL(contributions [][]types.ContributionDay, outputPath, username s
 //   - startYear: first year in the range
 //   - endYear: last year in the range
 func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int) error {
+	return GenerateModel(contributions, Options{
+		OutputPath: outputPath,
+		Format:     FormatSTL,
+		Username:   username,
+		StartYear:  startYear,
+		EndYear:    endYear,
+		Geometry:   geometry.DefaultConfig(),
+		Render:     render.DefaultOptions(),
+	})
+}
+
+// GenerateModel creates a 3D model from multiple years of GitHub contribution data.
+// It handles the complete process from data validation through geometry generation to
+// writing the file in the requested format. The same contributions and options
+// always give byte-identical files, however many workers generate the geometry.
+func GenerateModel(contributions [][][]types.ContributionDay, opts Options) error {
+	return GenerateModelContext(context.Background(), contributions, opts)
+}
+
+// GenerateModelContext is GenerateModel that gives up when ctx is done,
+// between generating the geometry and writing each file, and partway through
+// writing an STL file, which is then removed.
+func GenerateModelContext(ctx context.Context, contributions [][][]types.ContributionDay, opts Options) error {
+	_, err := GenerateModelResult(ctx, contributions, opts)
+	return err
+}
+
+// Result describes the files a model was written to.
+type Result struct {
+	Files     []string // Files written, in the order written, the index of split years last
+	Triangles int      // Triangles of the models written, over all of the files
+}
+
+// GenerateModelResult is GenerateModelContext that also returns the files
+// written and the size of the model, such as to report them to scripts.
+func GenerateModelResult(ctx context.Context, contributions [][][]types.ContributionDay, opts Options) (Result, error) {
 	log := logger.GetLogger()
-	if err := log.Debug("Starting STL generation for user %s, years %d-%d", username, startYear, endYear); err != nil {
-		return errors.Wrap(err, "failed to log debug message")
+	if err := log.Debug("Starting %s generation for user %s, years %d-%d", opts.Format, opts.Username, opts.StartYear, opts.EndYear); err != nil {
+		return Result{}, errors.Wrap(err, "failed to log debug message")
 	}
 
-	if len(contributions) == 0 {
-		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	if err := validateContributions(contributions, opts); err != nil {
+		return Result{}, err
+	}
+	if opts.SplitParts && opts.Format.isImage() {
+		return Result{}, errors.New(errors.ValidationError, fmt.Sprintf("%s images cannot be split into parts", opts.Format), nil)
+	}
+	if err := validateInput(contributions[0], opts.OutputPath, opts.Username); err != nil {
+		return Result{}, errors.Wrap(err, "input validation failed")
+	}
+
+	// Image previews are small, so their text is drawn coarser unless configured
+	if opts.Format.isImage() && opts.Geometry.FaceResolution == 0 {
+		opts.Geometry.FaceResolution = geometry.PreviewFaceResolution
+	}
+
+	if opts.SplitYears {
+		return generateYears(ctx, contributions, opts)
+	}
+
+	model, err := assembleModel(ctx, contributions, opts)
+	if err != nil {
+		return Result{}, err
+	}
+	written, err := writeModelFiles(ctx, model, opts)
+	if err != nil {
+		return Result{}, err
 	}
+	return Result{Files: written, Triangles: model.TriangleCount()}, nil
+}
 
-	if err := validateInput(contributions[0], outputPath, username); err != nil {
-		return errors.Wrap(err, "input validation failed")
+// BuildModel generates the model GenerateModelContext would write for
+// contributions in memory, such as to show it in a viewer before writing it.
+// opts.OutputPath, opts.SplitParts and opts.SplitYears are not used.
+func BuildModel(ctx context.Context, contributions [][][]types.ContributionDay, opts Options) (*types.Model, error) {
+	if err := validateContributions(contributions, opts); err != nil {
+		return nil, err
+	}
+	if opts.Username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
 	}
+	return assembleModel(ctx, contributions, opts)
+}
 
-	// Apply the same size bounds to every remaining year.
-	// outputPath and username are shared across all years and have already been validated above.
-	for i := 1; i < len(contributions); i++ {
+// validateContributions checks that every year of contributions fits the
+// model's grid and that the model's settings can be applied to them.
+func validateContributions(contributions [][][]types.ContributionDay, opts Options) error {
+	if len(contributions) == 0 {
+		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if math.IsNaN(opts.Decimate) || opts.Decimate < 0 || opts.Decimate > 1 {
+		return errors.New(errors.ValidationError, "decimate ratio must be between 0 and 1", nil)
+	}
+	// Apply the same size bounds to every year
+	for i := range contributions {
 		if len(contributions[i]) == 0 {
 			return errors.New(errors.ValidationError, fmt.Sprintf("contributions data for year index %d cannot be empty", i), nil)
 		}
@@ -49,43 +163,249 @@ func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, use
 			return errors.New(errors.ValidationError, fmt.Sprintf("contributions data for year index %d exceeds maximum grid size", i), nil)
 		}
 	}
+	if weeks := opts.Geometry.Weeks; weeks > 0 {
+		for i, year := range contributions {
+			if len(year) > weeks {
+				return errors.New(errors.ValidationError, fmt.Sprintf("contributions data for year index %d spans %d weeks, more than the grid's %d", i, len(year), weeks), nil)
+			}
+		}
+	}
+	return nil
+}
 
-	dimensions, err := calculateDimensions(len(contributions))
+// assembleModel generates the model of all years of contributions, scaled to
+// fit the print bed and converted to the configured unit.
+func assembleModel(ctx context.Context, contributions [][][]types.ContributionDay, opts Options) (*types.Model, error) {
+	dimensions, err := calculateDimensions(opts.Geometry, len(contributions))
 	if err != nil {
-		return errors.Wrap(err, "failed to calculate dimensions")
+		return nil, errors.Wrap(err, "failed to calculate dimensions")
 	}
 
 	// Find global max contribution across all years
 	maxContribution := findMaxContributionsAcrossYears(contributions)
 
-	modelTriangles, err := generateModelGeometry(contributions, dimensions, maxContribution, username, startYear, endYear)
+	summary := opts.Stats
+	if summary == nil && dimensions.layout.Stats {
+		computed := stats.Compute(contributions)
+		summary = &computed
+	}
+	opts.progress = progress.FromContext(ctx)
+	opts.progress.Start("Generating geometry", "triangles", 0)
+	model, err := buildModel(contributions, dimensions, maxContribution, opts, summary)
+	opts.progress.Finish()
 	if err != nil {
-		return errors.Wrap(err, "failed to generate geometry")
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.New(errors.STLError, "model generation canceled", err)
 	}
 
-	if err := log.Info("Model generation complete: %d total triangles", len(modelTriangles)); err != nil {
-		return errors.Wrap(err, "failed to log info message")
+	if !opts.Fit.IsZero() {
+		factor, err := fitToBed(model, opts.Fit)
+		if err != nil {
+			return nil, err
+		}
+		if err := logger.GetLogger().Info("Scaled model by %.4g to fit a %s print bed", factor, opts.Fit.Format(opts.Unit)); err != nil {
+			return nil, errors.Wrap(err, "failed to log info message")
+		}
 	}
-	if err := log.Debug("Writing STL file to: %s", outputPath); err != nil {
-		return errors.Wrap(err, "failed to log debug message")
+	convertUnits(model, opts.Unit)
+	return model, nil
+}
+
+// generateYears writes each year of contributions as a model of its own, next
+// to an index of the files written, and returns the files. Every year's towers
+// are scaled to the busiest day of the whole range, and every model is scaled
+// by the same factor to fit the print bed, so the pieces match when printed
+// side by side.
+func generateYears(ctx context.Context, contributions [][][]types.ContributionDay, opts Options) (Result, error) {
+	log := logger.GetLogger()
+	dimensions, err := calculateDimensions(opts.Geometry, 1)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to calculate dimensions")
 	}
+	maxContribution := findMaxContributionsAcrossYears(contributions)
 
-	if err := WriteSTLBinary(outputPath, modelTriangles); err != nil {
-		return errors.Wrap(err, "failed to write STL file")
+	index := manifest{
+		Username:         opts.Username,
+		Years:            periodLabel(opts),
+		Format:           opts.Format,
+		Unit:             opts.Unit,
+		MaxContributions: maxContribution,
+	}
+	if index.Unit == "" {
+		index.Unit = types.UnitMillimeter
 	}
 
-	if err := log.Info("STL file written successfully to: %s", outputPath); err != nil {
-		return errors.Wrap(err, "failed to log info message")
+	years := modelYears(opts, len(contributions))
+	factor := 0.0
+	var result Result
+	for i, yearContributions := range contributions {
+		year := years[i]
+		if err := ctx.Err(); err != nil {
+			return Result{}, errors.New(errors.STLError, fmt.Sprintf("model generation canceled before %d", year), err)
+		}
+		yearOpts := opts
+		yearOpts.StartYear, yearOpts.EndYear, yearOpts.Years, yearOpts.Period = year, year, nil, ""
+		yearOpts.OutputPath = partFilename(opts.OutputPath, fmt.Sprintf("%d", year))
+
+		var summary stats.Summary
+		if i < len(opts.YearStats) {
+			summary = opts.YearStats[i]
+		} else {
+			summary = stats.Compute([][][]types.ContributionDay{yearContributions})
+		}
+		yearOpts.progress = progress.FromContext(ctx)
+		yearOpts.progress.Start(fmt.Sprintf("Generating the geometry of %d", year), "triangles", 0)
+		model, err := buildModel([][][]types.ContributionDay{yearContributions}, dimensions, maxContribution, yearOpts, &summary)
+		yearOpts.progress.Finish()
+		if err != nil {
+			return Result{}, errors.Wrap(err, fmt.Sprintf("failed to generate model for %d", year))
+		}
+
+		if !opts.Fit.IsZero() {
+			// The first year sets the scale for all, as every year shares the same base
+			if factor == 0 {
+				if factor, err = fitToBed(model, opts.Fit); err != nil {
+					return Result{}, err
+				}
+				if err := log.Info("Scaled models by %.4g to fit a %s print bed", factor, opts.Fit.Format(opts.Unit)); err != nil {
+					return Result{}, errors.Wrap(err, "failed to log info message")
+				}
+			} else {
+				model.Scale(factor)
+			}
+		}
+		convertUnits(model, opts.Unit)
+
+		written, err := writeModelFiles(ctx, model, yearOpts)
+		if err != nil {
+			return Result{}, err
+		}
+		index.add(year, summary.Total, written)
+		result.Files = append(result.Files, written...)
+		result.Triangles += model.TriangleCount()
 	}
-	return nil
+
+	path := manifestFilename(opts.OutputPath)
+	if err := writeManifest(path, index); err != nil {
+		return Result{}, err
+	}
+	if err := log.Info("Index of the split years written successfully to: %s", path); err != nil {
+		return Result{}, errors.Wrap(err, "failed to log info message")
+	}
+	result.Files = append(result.Files, path)
+	return result, nil
+}
+
+// buildModel generates the model for the given years of contributions,
+// including the statistics and avatar panel when the layout asks for them.
+func buildModel(contributions [][][]types.ContributionDay, dimensions modelDimensions, maxContribution int, opts Options, summary *stats.Summary) (*types.Model, error) {
+	model, err := generateModel(contributions, dimensions, maxContribution, opts.Username, modelYears(opts, len(contributions)), opts.Period)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate geometry")
+	}
+	model.Metadata = append(model.Metadata, opts.Metadata...)
+	opts.progress.Add(model.TriangleCount())
+	if dimensions.layout.Stats {
+		statsTriangles, err := dimensions.layout.CreateStats(statsLines(*summary))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate statistics geometry")
+		}
+		model.Objects = append(model.Objects, types.ModelObject{Name: "stats", Kind: types.ObjectStats, Material: types.MaterialEmboss, Mesh: types.NewMesh(statsTriangles)})
+		opts.progress.Add(len(statsTriangles))
+	}
+	if dimensions.layout.Avatar {
+		if opts.Avatar == nil {
+			return nil, errors.New(errors.ValidationError, "an avatar image is required for the avatar panel", nil)
+		}
+		avatarTriangles, err := dimensions.layout.CreateAvatar(opts.Avatar)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate avatar geometry")
+		}
+		model.Objects = append(model.Objects, types.ModelObject{Name: "avatar", Kind: types.ObjectAvatar, Material: types.MaterialPanel, Mesh: types.NewMesh(avatarTriangles)})
+		opts.progress.Add(len(avatarTriangles))
+	}
+	if dimensions.layout.Mold {
+		var towers [][]types.Triangle
+		for _, obj := range model.Objects {
+			if obj.Kind == types.ObjectTower {
+				towers = append(towers, obj.Mesh.Triangles())
+			}
+		}
+		moldTriangles, err := dimensions.layout.CreateMold(towers)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate mold geometry")
+		}
+		model.Objects = []types.ModelObject{{Name: "mold", Kind: types.ObjectMold, Material: types.MaterialBase, Mesh: types.NewMesh(moldTriangles)}}
+	}
+	if opts.Mirror {
+		// The text and logo read backwards on the model and correctly in its impression
+		model.Mirror()
+	}
+	if opts.Repair {
+		report := repairModel(model)
+		if err := logger.GetLogger().Info("Mesh repair: %s", report); err != nil {
+			return nil, errors.Wrap(err, "failed to log info message")
+		}
+	}
+	if opts.Decimate > 0 {
+		before, after := decimateModel(model, opts.Decimate)
+		if err := logger.GetLogger().Info("Mesh decimation: %d to %d triangles", before, after); err != nil {
+			return nil, errors.Wrap(err, "failed to log info message")
+		}
+	}
+
+	if err := logger.GetLogger().Info("Model generation complete: %d total triangles", model.TriangleCount()); err != nil {
+		return nil, errors.Wrap(err, "failed to log info message")
+	}
+	return model, nil
+}
+
+// writeModelFiles writes a finished model to opts.OutputPath, or to one file
+// per part when the parts are split, and returns the files written.
+func writeModelFiles(ctx context.Context, model *types.Model, opts Options) ([]string, error) {
+	log := logger.GetLogger()
+	if err := log.Debug("Writing %s file to: %s", opts.Format, opts.OutputPath); err != nil {
+		return nil, errors.Wrap(err, "failed to log debug message")
+	}
+
+	if !opts.Format.isImage() {
+		if err := validateModel(model); err != nil {
+			return nil, errors.Wrap(err, "model validation failed")
+		}
+	}
+
+	if opts.SplitParts {
+		written, err := writeParts(ctx, opts.OutputPath, opts.Format, model, opts.Render)
+		if err != nil {
+			return written, errors.Wrap(err, "failed to write model part")
+		}
+		for _, path := range written {
+			if err := log.Info("Model part written successfully to: %s", path); err != nil {
+				return written, errors.Wrap(err, "failed to log info message")
+			}
+		}
+		return written, nil
+	}
+
+	if err := WriteModelContext(ctx, opts.OutputPath, opts.Format, model, opts.Render); err != nil {
+		return nil, errors.Wrap(err, "failed to write model file")
+	}
+
+	if err := log.Info("Model file written successfully to: %s", opts.OutputPath); err != nil {
+		return nil, errors.Wrap(err, "failed to log info message")
+	}
+	return []string{opts.OutputPath}, nil
 }
 
 // modelDimensions represents the core measurements of the 3D model.
 // All measurements are in millimeters.
 type modelDimensions struct {
-	innerWidth float64 // Width of the contribution grid
-	innerDepth float64 // Depth of the contribution grid
-	imagePath  string  // Path to the logo image
+	innerWidth float64         // Width of the contribution grid
+	innerDepth float64         // Depth of the contribution grid
+	imagePath  string          // Path to the logo image
+	layout     geometry.Layout // Resolved placement of the base and towers
 }
 
 func validateInput(contributions [][]types.ContributionDay, outputPath, username string) error {
@@ -104,24 +424,46 @@ func validateInput(contributions [][]types.ContributionDay, outputPath, username
 	return nil
 }
 
-func calculateDimensions(yearCount int) (modelDimensions, error) {
+func calculateDimensions(cfg geometry.Config, yearCount int) (modelDimensions, error) {
 	if yearCount <= 0 {
 		return modelDimensions{}, errors.New(errors.ValidationError, "year count must be positive", nil)
 	}
 
-	var width, depth float64
-	width, depth = geometry.CalculateMultiYearDimensions(yearCount)
+	layout, err := geometry.NewLayout(cfg, yearCount)
+	if err != nil {
+		return modelDimensions{}, err
+	}
 
 	dims := modelDimensions{
-		innerWidth: width,
-		innerDepth: depth,
+		innerWidth: layout.Width,
+		innerDepth: layout.Depth,
 		imagePath:  "assets/invertocat.png",
+		layout:     layout,
 	}
 
 	if dims.innerWidth <= 0 || dims.innerDepth <= 0 {
 		return modelDimensions{}, errors.New(errors.ValidationError, "invalid model dimensions", nil)
 	}
 
+	// A round base carries its text and logo in its center rather than on its front face
+	if layout.IsRound() {
+		return dims, nil
+	}
+	// Corners rounded into the embossed text and logo are reduced to clear them
+	if maxRadius := geometry.MaxCornerRadius(layout.Width, layout.Emboss); layout.CornerRadius > maxRadius {
+		if err := logger.GetLogger().Warning("Reduced the corner radius of %s to %s, as a larger one would cut into the embossed text and logo", layout.Unit.Format(layout.CornerRadius), layout.Unit.Format(maxRadius)); err != nil {
+			return modelDimensions{}, err
+		}
+		dims.layout.CornerRadius = maxRadius
+	}
+	// So is a chamfer cutting into the embossed logo
+	if maxChamfer := geometry.MaxChamfer(layout.Height, layout.Emboss); layout.Chamfer > maxChamfer {
+		if err := logger.GetLogger().Warning("Reduced the chamfer of %s to %s, as a larger one would cut into the embossed logo on a %s thick base", layout.Unit.Format(layout.Chamfer), layout.Unit.Format(maxChamfer), layout.Unit.Format(layout.Height)); err != nil {
+			return modelDimensions{}, err
+		}
+		dims.layout.Chamfer = maxChamfer
+	}
+
 	return dims, nil
 }
 
@@ -150,25 +492,56 @@ func findMaxContributionsAcrossYears(contributionsPerYear [][][]types.Contributi
 }
 
 // geometryResult holds the output of geometry generation operations.
-// It includes both the generated triangles and any errors that occurred.
+// It includes the generated objects, their triangles flattened into a single
+// slice, and any errors that occurred.
 type geometryResult struct {
 	triangles []types.Triangle
+	objects   []types.ModelObject
 	err       error
 }
 
-// generateModelGeometry orchestrates the concurrent generation of all model components.
-// It manages four parallel processes for generating the base, columns, text, and logo.
+// newGeometryResult creates a result from a set of objects, flattening their triangles.
+func newGeometryResult(objects ...types.ModelObject) geometryResult {
+	triangles := []types.Triangle{}
+	for _, obj := range objects {
+		triangles = append(triangles, obj.Mesh.Triangles()...)
+	}
+	return geometryResult{triangles: triangles, objects: objects}
+}
+
+// generateModelGeometry orchestrates the generation of all model components and
+// returns their triangles as a single list.
+func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int) ([]types.Triangle, error) {
+	model, err := generateModel(contributionsPerYear, dims, maxContrib, username, consecutiveYears(startYear, len(contributionsPerYear)), "")
+	if err != nil {
+		return nil, err
+	}
+	return model.Triangles(), nil
+}
+
+// generateModel orchestrates the concurrent generation of all model components.
+// It manages parallel processes for generating the base, columns, text, logo, QR code,
+// month labels and year labels,
+// and groups the results into objects annotated with metadata. Years lists
+// the year of each grid, oldest first, and a non-empty period labels the model
+// in place of them.
 // Channels are buffered so every goroutine can send and exit even if an error causes
 // an early return, preventing goroutine leaks.
-func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int) ([]types.Triangle, error) {
+func generateModel(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, years []int, period string) (*types.Model, error) {
 	if len(contributionsPerYear) == 0 {
 		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
 	}
+	if len(years) != len(contributionsPerYear) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("%d years given for %d years of contributions", len(years), len(contributionsPerYear)), nil)
+	}
+	if period == "" {
+		period = formatYears(years[0], years[len(years)-1])
+	}
 
 	// componentChannel pairs a name with its buffered result channel.
 	// Using a slice (not a map) preserves a stable iteration order so that
-	// triangles are always appended base → columns → text → image, giving
-	// reproducible STL output across runs.
+	// objects are always appended base → columns → text → image → QR code → month
+	// labels → year labels, giving reproducible output across runs.
 	type componentChannel struct {
 		name string
 		ch   chan geometryResult
@@ -181,29 +554,81 @@ func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dim
 		{"columns", make(chan geometryResult, 1)},
 		{"text", make(chan geometryResult, 1)},
 		{"image", make(chan geometryResult, 1)},
+		{"QR code", make(chan geometryResult, 1)},
+		{"month labels", make(chan geometryResult, 1)},
+		{"year labels", make(chan geometryResult, 1)},
 	}
 
 	// Launch goroutines for each component
 	go generateBase(dims, components[0].ch)
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, components[1].ch)
-	go generateText(username, startYear, endYear, dims, components[2].ch)
+	go generateColumnsForYearRange(contributionsPerYear, dims, maxContrib, components[1].ch)
+	go generateText(username, period, dims, components[2].ch)
 	go generateLogo(dims, components[3].ch)
+	go generateQRCode(dims, components[4].ch)
+	go generateMonthLabels(contributionsPerYear[len(contributionsPerYear)-1], dims, components[5].ch)
+	go generateYearLabels(years, dims, components[6].ch)
+
+	model := &types.Model{
+		Metadata: []types.Metadata{
+			{Key: "username", Value: username},
+			{Key: "years", Value: period},
+		},
+	}
 
-	// Collect results in declaration order for a reproducible triangle sequence.
-	modelTriangles := make([]types.Triangle, 0, estimateTriangleCount(contributionsPerYear[0])*len(contributionsPerYear))
+	// Collect results in declaration order for a reproducible object sequence.
 	for _, component := range components {
 		result := <-component.ch
 		if result.err != nil {
 			return nil, errors.Wrap(result.err, fmt.Sprintf("failed to generate %s geometry", component.name))
 		}
-		modelTriangles = append(modelTriangles, result.triangles...)
+		for _, obj := range result.objects {
+			if obj.Kind == types.ObjectTower || obj.Kind == types.ObjectSurface {
+				obj.Metadata = append([]types.Metadata{{Key: "username", Value: username}}, obj.Metadata...)
+			}
+			model.Objects = append(model.Objects, obj)
+		}
+	}
+
+	return model, nil
+}
+
+// formatYears returns the year label embossed on the model, 'YYYY' or 'YYYY-YY' for ranges.
+func formatYears(startYear, endYear int) string {
+	if startYear == endYear {
+		return fmt.Sprintf("%d", endYear)
+	}
+	return fmt.Sprintf("%04d-%02d", startYear, endYear%100)
+}
+
+// consecutiveYears returns count years counting up from startYear.
+func consecutiveYears(startYear, count int) []int {
+	years := make([]int, count)
+	for i := range years {
+		years[i] = startYear + i
 	}
+	return years
+}
 
-	return modelTriangles, nil
+// modelYears returns the year of each of count grids of contributions: the
+// model's Years when set, otherwise consecutive years from its StartYear.
+func modelYears(opts Options, count int) []int {
+	if len(opts.Years) > 0 {
+		return opts.Years
+	}
+	return consecutiveYears(opts.StartYear, count)
+}
+
+// periodLabel returns the label of the period a model covers: its Period when
+// set, otherwise its years.
+func periodLabel(opts Options) string {
+	if opts.Period != "" {
+		return opts.Period
+	}
+	return formatYears(opts.StartYear, opts.EndYear)
 }
 
 func generateBase(dims modelDimensions, ch chan<- geometryResult) {
-	baseTriangles, err := geometry.CreateCuboidBase(dims.innerWidth, dims.innerDepth)
+	baseTriangles, err := dims.layout.CreateBase()
 
 	if err != nil {
 		if logErr := logger.GetLogger().Warning("Failed to generate base geometry: %v. Continuing without base.", err); logErr != nil {
@@ -214,20 +639,18 @@ func generateBase(dims modelDimensions, ch chan<- geometryResult) {
 		return
 	}
 
-	ch <- geometryResult{triangles: baseTriangles}
+	ch <- newGeometryResult(types.ModelObject{Name: "base", Kind: types.ObjectBase, Material: types.MaterialBase, Mesh: types.NewMesh(baseTriangles)})
 }
 
-// generateText creates 3D text geometry for the model
-func generateText(username string, startYear int, endYear int, dims modelDimensions, ch chan<- geometryResult) {
-	embossedYear := fmt.Sprintf("%d", endYear)
-
-	// If start year and end year are the same, only show one year
-	if startYear != endYear {
-		// Make the year 'YYYY-YY'
-		embossedYear = fmt.Sprintf("%04d-%02d", startYear, endYear%100)
+// generateText creates 3D text geometry for the model, embossing the username
+// and the label of the period covered
+func generateText(username, period string, dims modelDimensions, ch chan<- geometryResult) {
+	if !dims.layout.Emboss.Text {
+		ch <- newGeometryResult()
+		return
 	}
 
-	textTriangles, err := geometry.Create3DText(username, embossedYear, dims.innerWidth, geometry.BaseHeight)
+	textTriangles, err := dims.layout.CreateText(username, period)
 	if err != nil {
 		if logErr := logger.GetLogger().Warning("Failed to generate text geometry: %v. Continuing without text.", err); logErr != nil {
 			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
@@ -236,12 +659,22 @@ func generateText(username string, startYear int, endYear int, dims modelDimensi
 		ch <- geometryResult{triangles: []types.Triangle{}}
 		return
 	}
-	ch <- geometryResult{triangles: textTriangles}
+	ch <- newGeometryResult(types.ModelObject{Name: "text", Kind: types.ObjectText, Material: types.MaterialEmboss, Mesh: types.NewMesh(textTriangles)})
 }
 
 // generateLogo handles the generation of the GitHub logo geometry
 func generateLogo(dims modelDimensions, ch chan<- geometryResult) {
-	logoTriangles, err := geometry.GenerateImageGeometry(dims.innerWidth, geometry.BaseHeight)
+	if !dims.layout.Emboss.Logo {
+		ch <- newGeometryResult()
+		return
+	}
+
+	logoTriangles, err := dims.layout.CreateLogo()
+	if err != nil && dims.layout.LogoFile != "" {
+		// A logo the user asked for is not silently dropped
+		ch <- geometryResult{triangles: []types.Triangle{}, err: err}
+		return
+	}
 	if err != nil {
 		// Log warning and continue without logo instead of failing
 		if logErr := logger.GetLogger().Warning("Failed to generate logo geometry: %v. Continuing without logo.", err); logErr != nil {
@@ -251,7 +684,90 @@ func generateLogo(dims modelDimensions, ch chan<- geometryResult) {
 		ch <- geometryResult{triangles: []types.Triangle{}}
 		return
 	}
-	ch <- geometryResult{triangles: logoTriangles}
+	ch <- newGeometryResult(types.ModelObject{Name: "logo", Kind: types.ObjectLogo, Material: types.MaterialEmboss, Mesh: types.NewMesh(logoTriangles)})
+}
+
+// generateQRCode handles the generation of the QR code on the back of the base
+func generateQRCode(dims modelDimensions, ch chan<- geometryResult) {
+	if dims.layout.QRCode == "" {
+		ch <- newGeometryResult()
+		return
+	}
+
+	qrTriangles, err := dims.layout.CreateQRCode()
+	if err != nil {
+		// A QR code the user asked for is not silently dropped
+		ch <- geometryResult{triangles: []types.Triangle{}, err: err}
+		return
+	}
+	ch <- newGeometryResult(types.ModelObject{Name: "qr-code", Kind: types.ObjectQR, Material: types.MaterialEmboss, Mesh: types.NewMesh(qrTriangles)})
+}
+
+// generateMonthLabels handles the generation of the month labels for the
+// most recent year, which is at the front of the model
+func generateMonthLabels(contributions [][]types.ContributionDay, dims modelDimensions, ch chan<- geometryResult) {
+	if dims.layout.MonthLabels == geometry.MonthLabelsNone {
+		ch <- newGeometryResult()
+		return
+	}
+
+	monthTriangles, err := dims.layout.CreateMonthLabels(contributions)
+	if err != nil {
+		// Labels the user asked for are not silently dropped
+		ch <- geometryResult{triangles: []types.Triangle{}, err: err}
+		return
+	}
+	ch <- newGeometryResult(types.ModelObject{Name: "months", Kind: types.ObjectMonths, Material: types.MaterialEmboss, Mesh: types.NewMesh(monthTriangles)})
+}
+
+// generateYearLabels handles the generation of the labels beside each of the
+// years, given oldest first, with the most recent year at the front
+func generateYearLabels(years []int, dims modelDimensions, ch chan<- geometryResult) {
+	if !dims.layout.YearLabels {
+		ch <- newGeometryResult()
+		return
+	}
+
+	yearTriangles, err := dims.layout.CreateYearLabels(years)
+	if err != nil {
+		// Labels the user asked for are not silently dropped
+		ch <- geometryResult{triangles: []types.Triangle{}, err: err}
+		return
+	}
+	ch <- newGeometryResult(types.ModelObject{Name: "years", Kind: types.ObjectYears, Material: types.MaterialEmboss, Mesh: types.NewMesh(yearTriangles)})
+}
+
+// statsLines formats the statistics embossed on the back of the model.
+func statsLines(s stats.Summary) []string {
+	lines := []string{fmt.Sprintf("%s contributions", formatThousands(s.Total))}
+	if day := s.BusiestDay; day.ContributionCount > 0 {
+		if date, err := time.Parse("2006-01-02", day.Date); err == nil {
+			lines = append(lines, fmt.Sprintf("Busiest day: %s (%s)", date.Format("Jan 2, 2006"), formatThousands(day.ContributionCount)))
+		}
+	}
+	switch {
+	case s.LongestStreak == 1:
+		lines = append(lines, "Longest streak: 1 day")
+	case s.LongestStreak > 1:
+		lines = append(lines, fmt.Sprintf("Longest streak: %s days", formatThousands(s.LongestStreak)))
+	}
+	return lines
+}
+
+// formatThousands formats a count with commas between groups of thousands.
+func formatThousands(n int) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	digits := fmt.Sprintf("%d", n)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
 }
 
 func estimateTriangleCount(contributions [][]types.ContributionDay) int {
@@ -270,14 +786,22 @@ func estimateTriangleCount(contributions [][]types.ContributionDay) int {
 	return baseTrianglesCount + columnsTrianglesCount + textTrianglesEstimate
 }
 
-// generateColumnsForYearRange generates contribution columns for multiple years
-func generateColumnsForYearRange(contributionsPerYear [][][]types.ContributionDay, maxContrib int, ch chan<- geometryResult) {
-	var yearTriangles []types.Triangle
+// generateColumnsForYearRange generates contribution columns for multiple years,
+// scaled against maxContrib, or against each year's own busiest day when the
+// layout normalizes per year, along with the dots marking each year's future
+// days when the layout asks for them
+func generateColumnsForYearRange(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, ch chan<- geometryResult) {
+	var towers []types.ModelObject
+	now := time.Now()
 
 	// Process years in reverse order so most recent year is at the front
 	for i := len(contributionsPerYear) - 1; i >= 0; i-- {
 		yearOffset := len(contributionsPerYear) - 1 - i
-		triangles, err := geometry.CreateContributionGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+		yearMax := maxContrib
+		if dims.layout.Normalize == geometry.NormalizePerYear {
+			yearMax = findMaxContributions(contributionsPerYear[i])
+		}
+		yearTowers, err := dims.layout.CreateContributionObjects(contributionsPerYear[i], yearOffset, yearMax)
 		if err != nil {
 			if logErr := logger.GetLogger().Warning("Failed to generate column geometry for year %d: %v. Skipping year.", i, err); logErr != nil {
 				// logErr is secondary; report the original geometry error to the caller.
@@ -286,8 +810,17 @@ func generateColumnsForYearRange(contributionsPerYear [][][]types.ContributionDa
 			}
 			continue
 		}
-		yearTriangles = append(yearTriangles, triangles...)
+		towers = append(towers, yearTowers...)
+
+		dots, err := dims.layout.CreateFutureDots(contributionsPerYear[i], yearOffset, now)
+		if err != nil {
+			ch <- geometryResult{triangles: []types.Triangle{}, err: err}
+			return
+		}
+		if len(dots) > 0 {
+			towers = append(towers, types.ModelObject{Name: fmt.Sprintf("future-%d", yearOffset), Kind: types.ObjectFuture, Material: types.MaterialBase, Mesh: types.NewMesh(dots)})
+		}
 	}
 
-	ch <- geometryResult{triangles: yearTriangles}
+	ch <- newGeometryResult(towers...)
 }
@@ -1,11 +1,24 @@
 package stl
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/stats"
 	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/types"
 )
@@ -242,15 +255,15 @@ func TestCalculateDimensionsMultiYear(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dims, err := calculateDimensions(tt.yearCount)
+			dims, err := calculateDimensions(geometry.DefaultConfig(), tt.yearCount)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("calculateDimensions() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("calculateDimensions(geometry.DefaultConfig(), ) error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
 			if !tt.wantErr {
 				if dims.innerWidth <= 0 || dims.innerDepth <= 0 {
-					t.Errorf("calculateDimensions() returned invalid dimensions: width=%v, depth=%v",
+					t.Errorf("calculateDimensions(geometry.DefaultConfig(), ) returned invalid dimensions: width=%v, depth=%v",
 						dims.innerWidth, dims.innerDepth)
 				}
 			}
@@ -334,9 +347,9 @@ func TestFindMaxContributionsAcrossYears(t *testing.T) {
 }
 
 func TestGenerateBase(t *testing.T) {
-	dims, err := calculateDimensions(1)
+	dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
 	if err != nil {
-		t.Fatalf("calculateDimensions() error = %v", err)
+		t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 	}
 	ch := make(chan geometryResult, 1)
 
@@ -352,13 +365,13 @@ func TestGenerateBase(t *testing.T) {
 }
 
 func TestGenerateText(t *testing.T) {
-	dims, err := calculateDimensions(1)
+	dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
 	if err != nil {
-		t.Fatalf("calculateDimensions() error = %v", err)
+		t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 	}
 	ch := make(chan geometryResult, 1)
 
-	go generateText("testuser", 2023, 2023, dims, ch)
+	go generateText("testuser", "2023", dims, ch)
 
 	result := <-ch
 	if result.err != nil {
@@ -383,12 +396,16 @@ func TestGenerateColumnsForYearRange(t *testing.T) {
 		contributionsPerYear[i] = createTestContributions()
 	}
 
+	dims, err := calculateDimensions(geometry.DefaultConfig(), len(contributionsPerYear))
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
 	ch := make(chan geometryResult, 1)
 
 	maxContrib := 10 // Set a known max contribution value
 
 	// Test the goroutine
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, ch)
+	go generateColumnsForYearRange(contributionsPerYear, dims, maxContrib, ch)
 
 	// Collect the result
 	result := <-ch
@@ -397,6 +414,58 @@ func TestGenerateColumnsForYearRange(t *testing.T) {
 	}
 }
 
+// TestGenerateColumnsNormalize verifies each normalization mode scales a quiet
+// year against the right busiest day
+func TestGenerateColumnsNormalize(t *testing.T) {
+	quiet, busy := createTestContributions(), createTestContributions()
+	for i := range busy {
+		for j := range busy[i] {
+			busy[i][j].ContributionCount *= 4
+		}
+	}
+	contributionsPerYear := [][][]types.ContributionDay{quiet, busy}
+
+	// tallest returns the height of the tallest tower of each year, front year first
+	tallest := func(normalize geometry.Normalization) [2]float64 {
+		cfg := geometry.DefaultConfig()
+		cfg.Normalize = normalize
+		dims, err := calculateDimensions(cfg, len(contributionsPerYear))
+		if err != nil {
+			t.Fatalf("calculateDimensions() error = %v", err)
+		}
+		ch := make(chan geometryResult, 1)
+		go generateColumnsForYearRange(contributionsPerYear, dims, findMaxContributionsAcrossYears(contributionsPerYear), ch)
+		result := <-ch
+		if result.err != nil {
+			t.Fatalf("generateColumnsForYearRange() error = %v", result.err)
+		}
+		// The busy year is the front one, and every tower of the quiet year starts
+		// at least a year spacing back
+		back := dims.layout.OffsetY + dims.layout.YearSpacing
+		var heights [2]float64
+		for _, obj := range result.objects {
+			year, top := 1, 0.0
+			for _, tri := range obj.Mesh.Triangles() {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if v.Y < back {
+						year = 0
+					}
+					top = math.Max(top, v.Z)
+				}
+			}
+			heights[year] = math.Max(heights[year], top)
+		}
+		return heights
+	}
+
+	if global := tallest(geometry.NormalizeGlobal); global[1] >= global[0] {
+		t.Errorf("global normalization: quiet year tallest %v, busy year %v, want the quiet year lower", global[1], global[0])
+	}
+	if perYear := tallest(geometry.NormalizePerYear); math.Abs(perYear[0]-perYear[1]) > 1e-9 {
+		t.Errorf("per-year normalization: tallest towers %v and %v, want equal", perYear[0], perYear[1])
+	}
+}
+
 func TestCreateContributionGeometry(t *testing.T) {
 	contributions := createTestContributions()
 	yearIndex := 0
@@ -435,9 +504,9 @@ func TestGenerateModelGeometry(t *testing.T) {
 		contributionsPerYear[i] = createTestContributions()
 	}
 
-	dims, err := calculateDimensions(len(contributionsPerYear))
+	dims, err := calculateDimensions(geometry.DefaultConfig(), len(contributionsPerYear))
 	if err != nil {
-		t.Fatalf("calculateDimensions() error = %v", err)
+		t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 	}
 	maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
 	username := "testuser"
@@ -466,9 +535,9 @@ func TestGenerateModelGeometry(t *testing.T) {
 }
 
 func TestGenerateLogo(t *testing.T) {
-	dims, err := calculateDimensions(1)
+	dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
 	if err != nil {
-		t.Fatalf("calculateDimensions() error = %v", err)
+		t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 	}
 	ch := make(chan geometryResult, 1)
 
@@ -481,6 +550,94 @@ func TestGenerateLogo(t *testing.T) {
 	}
 }
 
+func TestGenerateQRCode(t *testing.T) {
+	dims, err := calculateDimensions(geometry.Config{BaseHeight: 20, QRCode: "https://github.com/octocat"}, 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	ch := make(chan geometryResult, 1)
+
+	go generateQRCode(dims, ch)
+
+	result := <-ch
+	if result.err != nil {
+		t.Fatalf("generateQRCode() error = %v", result.err)
+	}
+	if len(result.objects) != 1 || result.objects[0].Kind != types.ObjectQR || len(result.triangles) == 0 {
+		t.Errorf("generateQRCode() returned %d objects and %d triangles, want one QR code object", len(result.objects), len(result.triangles))
+	}
+
+	// Without a QR code nothing is generated
+	dims, err = calculateDimensions(geometry.DefaultConfig(), 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	go generateQRCode(dims, ch)
+	if result := <-ch; len(result.objects) != 0 {
+		t.Errorf("generateQRCode() returned %d objects without a QR code, want none", len(result.objects))
+	}
+}
+
+func TestGenerateMonthLabels(t *testing.T) {
+	dims, err := calculateDimensions(geometry.Config{MonthLabels: geometry.MonthLabelsTop}, 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	contributions := createTestContributions()
+	for i, week := range contributions {
+		week[0].Date = time.Date(2024, 1, 7*i+1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	}
+	ch := make(chan geometryResult, 1)
+
+	go generateMonthLabels(contributions, dims, ch)
+
+	result := <-ch
+	if result.err != nil {
+		t.Fatalf("generateMonthLabels() error = %v", result.err)
+	}
+	if len(result.objects) != 1 || result.objects[0].Kind != types.ObjectMonths || len(result.triangles) == 0 {
+		t.Errorf("generateMonthLabels() returned %d objects and %d triangles, want one month labels object", len(result.objects), len(result.triangles))
+	}
+
+	// Without month labels nothing is generated
+	dims, err = calculateDimensions(geometry.DefaultConfig(), 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	go generateMonthLabels(contributions, dims, ch)
+	if result := <-ch; len(result.objects) != 0 {
+		t.Errorf("generateMonthLabels() returned %d objects without month labels, want none", len(result.objects))
+	}
+}
+
+func TestGenerateYearLabels(t *testing.T) {
+	dims, err := calculateDimensions(geometry.Config{YearLabels: true}, 3)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	ch := make(chan geometryResult, 1)
+
+	go generateYearLabels([]int{2012, 2015, 2024}, dims, ch)
+
+	result := <-ch
+	if result.err != nil {
+		t.Fatalf("generateYearLabels() error = %v", result.err)
+	}
+	if len(result.objects) != 1 || result.objects[0].Kind != types.ObjectYears || len(result.triangles) == 0 {
+		t.Errorf("generateYearLabels() returned %d objects and %d triangles, want one year labels object", len(result.objects), len(result.triangles))
+	}
+
+	// Without year labels nothing is generated
+	dims, err = calculateDimensions(geometry.DefaultConfig(), 3)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	go generateYearLabels([]int{2022, 2023, 2024}, dims, ch)
+	if result := <-ch; len(result.objects) != 0 {
+		t.Errorf("generateYearLabels() returned %d objects without year labels, want none", len(result.objects))
+	}
+}
+
 func TestCalculateDimensions(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -495,14 +652,14 @@ func TestCalculateDimensions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dims, err := calculateDimensions(tt.yearCount)
+			dims, err := calculateDimensions(geometry.DefaultConfig(), tt.yearCount)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("calculateDimensions(%d) error = %v, wantErr %v", tt.yearCount, err, tt.wantErr)
+				t.Errorf("calculateDimensions(geometry.DefaultConfig(), %d) error = %v, wantErr %v", tt.yearCount, err, tt.wantErr)
 				return
 			}
 
 			if !tt.wantErr && (dims.innerWidth <= 0 || dims.innerDepth <= 0) {
-				t.Errorf("calculateDimensions(%d) returned invalid dimensions: width=%v, depth=%v",
+				t.Errorf("calculateDimensions(geometry.DefaultConfig(), %d) returned invalid dimensions: width=%v, depth=%v",
 					tt.yearCount, dims.innerWidth, dims.innerDepth)
 			}
 		})
@@ -510,9 +667,9 @@ func TestCalculateDimensions(t *testing.T) {
 }
 
 func TestGenerateText_WithYearRange(t *testing.T) {
-	dims, err := calculateDimensions(1)
+	dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
 	if err != nil {
-		t.Fatalf("calculateDimensions() error = %v", err)
+		t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 	}
 	tests := []struct {
 		name      string
@@ -531,7 +688,7 @@ func TestGenerateText_WithYearRange(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ch := make(chan geometryResult, 1)
 
-			go generateText(tt.username, tt.startYear, tt.endYear, dims, ch)
+			go generateText(tt.username, formatYears(tt.startYear, tt.endYear), dims, ch)
 
 			result := <-ch
 			// Even if font generation fails, result should not be nil
@@ -564,9 +721,13 @@ func TestGenerateColumnsForYearRange_Extended(t *testing.T) {
 				contributionsPerYear[i] = createTestContributions()
 			}
 
+			dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
+			if err != nil {
+				t.Fatalf("calculateDimensions() error = %v", err)
+			}
 			ch := make(chan geometryResult, 1)
 
-			go generateColumnsForYearRange(contributionsPerYear, tt.maxContrib, ch)
+			go generateColumnsForYearRange(contributionsPerYear, dims, tt.maxContrib, ch)
 
 			result := <-ch
 			if tt.expectTriangles && len(result.triangles) == 0 {
@@ -581,14 +742,14 @@ func TestGenerateColumnsForYearRange_Extended(t *testing.T) {
 func TestResourceHandling(t *testing.T) {
 	// Test handling of missing font files
 	t.Run("missing font handling", func(t *testing.T) {
-		dims, err := calculateDimensions(1)
+		dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
 		if err != nil {
-			t.Fatalf("calculateDimensions() error = %v", err)
+			t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 		}
 		ch := make(chan geometryResult, 1)
 
 		// This should log a warning but continue
-		go generateText("testuser", 2023, 2023, dims, ch)
+		go generateText("testuser", "2023", dims, ch)
 
 		result := <-ch
 		// Even with missing fonts, we should get a valid (possibly empty) result
@@ -599,9 +760,9 @@ func TestResourceHandling(t *testing.T) {
 
 	// Test handling of missing image file
 	t.Run("missing image handling", func(t *testing.T) {
-		dims, err := calculateDimensions(1)
+		dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
 		if err != nil {
-			t.Fatalf("calculateDimensions() error = %v", err)
+			t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 		}
 		ch := make(chan geometryResult, 1)
 
@@ -622,9 +783,9 @@ func TestResourceHandling(t *testing.T) {
 			contributionsPerYear[i] = createTestContributions()
 		}
 
-		dims, err := calculateDimensions(len(contributionsPerYear))
+		dims, err := calculateDimensions(geometry.DefaultConfig(), len(contributionsPerYear))
 		if err != nil {
-			t.Fatalf("calculateDimensions() error = %v", err)
+			t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
 		}
 		maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
 
@@ -654,9 +815,9 @@ func TestCalculateDimensionsEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dims, err := calculateDimensions(tt.yearCount)
+			dims, err := calculateDimensions(geometry.DefaultConfig(), tt.yearCount)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("calculateDimensions() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("calculateDimensions(geometry.DefaultConfig(), ) error = %v, wantErr %v", err, tt.wantErr)
 			}
 
 			if !tt.wantErr {
@@ -668,3 +829,667 @@ func TestCalculateDimensionsEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateDimensionsBaseThickness(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     geometry.Config
+		wantErr bool
+	}{
+		{"default thickness", geometry.DefaultConfig(), false},
+		{"thin base fits emboss", geometry.Config{BaseHeight: 9}, false},
+		{"thin base shrinks emboss", geometry.Config{BaseHeight: 3}, false},
+		{"wide base shrinks emboss", geometry.Config{BaseWidth: 300}, false},
+		{"gap widens base", geometry.Config{Gap: 0.5}, false},
+		{"larger footprint widens base", geometry.Config{CellSize: 3}, false},
+		{"wide base with thick slab", geometry.Config{BaseWidth: 300, BaseHeight: 20}, false},
+		{"small corner radius", geometry.Config{CornerRadius: 3}, false},
+		{"corner radius under emboss", geometry.Config{CornerRadius: 4.5}, false},
+		{"small chamfer", geometry.Config{Chamfer: 0.5}, false},
+		{"chamfer shrinking text", geometry.Config{Chamfer: 1}, false},
+		{"chamfer under emboss", geometry.Config{Chamfer: 2}, false},
+		{"thin base without emboss", geometry.Config{BaseHeight: 3, OmitText: true, OmitLogo: true}, false},
+		{"large chamfer without emboss", geometry.Config{Chamfer: 2, OmitText: true, OmitLogo: true}, false},
+		{"large chamfer without logo", geometry.Config{Chamfer: 2, OmitLogo: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calculateDimensions(tt.cfg, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("calculateDimensions(%+v, 1) error = %v, wantErr %v", tt.cfg, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculateDimensionsClampsEdges(t *testing.T) {
+	dims, err := calculateDimensions(geometry.Config{CornerRadius: 10}, 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	if want := geometry.MaxCornerRadius(dims.layout.Width, dims.layout.Emboss); dims.layout.CornerRadius != want {
+		t.Errorf("calculateDimensions() corner radius = %v, want it reduced to %v", dims.layout.CornerRadius, want)
+	}
+
+	dims, err = calculateDimensions(geometry.Config{Chamfer: 4}, 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	if want := geometry.MaxChamfer(dims.layout.Height, dims.layout.Emboss); dims.layout.Chamfer != want {
+		t.Errorf("calculateDimensions() chamfer = %v, want it reduced to %v", dims.layout.Chamfer, want)
+	}
+}
+
+func TestGenerateModelOmitEmboss(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(geometry.Config{OmitText: true, OmitLogo: true}, 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+
+	model, err := generateModel(contributionsPerYear, dims, findMaxContributionsAcrossYears(contributionsPerYear), "testuser", []int{2023}, "")
+	if err != nil {
+		t.Fatalf("generateModel() error = %v", err)
+	}
+	for _, obj := range model.Objects {
+		if obj.Kind == types.ObjectText || obj.Kind == types.ObjectLogo {
+			t.Errorf("generateModel() emitted %s object %q although it was omitted", obj.Kind, obj.Name)
+		}
+	}
+}
+
+func TestGenerateModelObjects(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(geometry.DefaultConfig(), len(contributionsPerYear))
+	if err != nil {
+		t.Fatalf("calculateDimensions(geometry.DefaultConfig(), ) error = %v", err)
+	}
+	maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
+
+	model, err := generateModel(contributionsPerYear, dims, maxContrib, "testuser", []int{2023}, "")
+	if err != nil {
+		t.Fatalf("generateModel() error = %v", err)
+	}
+
+	if len(model.Objects) == 0 || model.Objects[0].Kind != types.ObjectBase {
+		t.Fatal("generateModel() should emit the base as the first object")
+	}
+
+	towers := 0
+	for _, obj := range model.Objects {
+		if obj.Kind != types.ObjectTower {
+			continue
+		}
+		towers++
+		if len(obj.Metadata) == 0 || obj.Metadata[0] != (types.Metadata{Key: "username", Value: "testuser"}) {
+			t.Errorf("tower %s metadata = %v, want username first", obj.Name, obj.Metadata)
+		}
+	}
+
+	activeDays := 0
+	for _, week := range contributionsPerYear[0] {
+		for _, day := range week {
+			if day.ContributionCount > 0 {
+				activeDays++
+			}
+		}
+	}
+	if towers != activeDays {
+		t.Errorf("generateModel() emitted %d towers, want one per active day (%d)", towers, activeDays)
+	}
+
+	if len(model.Metadata) != 2 || model.Metadata[1] != (types.Metadata{Key: "years", Value: "2023"}) {
+		t.Errorf("model metadata = %v, want username and years", model.Metadata)
+	}
+}
+
+func TestBuildModel(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	dir := t.TempDir()
+	opts := Options{OutputPath: filepath.Join(dir, "skyline.stl"), Username: "testuser", StartYear: 2024, EndYear: 2024, Unit: types.UnitInch}
+
+	model, err := BuildModel(context.Background(), contributions, opts)
+	if err != nil {
+		t.Fatalf("BuildModel() error = %v", err)
+	}
+	if model.Unit != types.UnitInch || model.TriangleCount() == 0 {
+		t.Errorf("BuildModel() = %d triangles in %q, want a model in inches", model.TriangleCount(), model.Unit)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Errorf("BuildModel() wrote %d files, want none", len(entries))
+	}
+
+	opts.Username = ""
+	if _, err := BuildModel(context.Background(), contributions, opts); err == nil {
+		t.Error("BuildModel() without a username should fail")
+	}
+	if _, err := BuildModel(context.Background(), nil, Options{Username: "testuser"}); err == nil {
+		t.Error("BuildModel() without contributions should fail")
+	}
+}
+
+func TestGenerateModelPeriod(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(geometry.DefaultConfig(), len(contributionsPerYear))
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+
+	model, err := generateModel(contributionsPerYear, dims, findMaxContributionsAcrossYears(contributionsPerYear), "testuser", []int{2024}, "2024.01-06")
+	if err != nil {
+		t.Fatalf("generateModel() error = %v", err)
+	}
+	if len(model.Metadata) != 2 || model.Metadata[1] != (types.Metadata{Key: "years", Value: "2024.01-06"}) {
+		t.Errorf("model metadata = %v, want the period in place of the years", model.Metadata)
+	}
+	if got := periodLabel(Options{StartYear: 2023, EndYear: 2024}); got != "2023-24" {
+		t.Errorf("periodLabel() = %q, want the years", got)
+	}
+}
+
+func TestGenerateModelStats(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	tempDir := t.TempDir()
+
+	sizes := make(map[bool]int64)
+	for _, withStats := range []bool{false, true} {
+		path := filepath.Join(tempDir, fmt.Sprintf("stats-%t.stl", withStats))
+		err := GenerateModel(contributionsPerYear, Options{
+			OutputPath: path,
+			Format:     FormatSTL,
+			Username:   "testuser",
+			StartYear:  2023,
+			EndYear:    2023,
+			Geometry:   geometry.Config{OmitText: true, OmitLogo: true, Stats: withStats},
+		})
+		if err != nil {
+			t.Fatalf("GenerateModel() with stats=%t error = %v", withStats, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("model file was not created: %v", err)
+		}
+		sizes[withStats] = info.Size()
+	}
+	if sizes[true] <= sizes[false] {
+		t.Errorf("model with statistics is %d bytes, want more than the %d bytes without", sizes[true], sizes[false])
+	}
+}
+
+func TestGenerateModelAvatar(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	tempDir := t.TempDir()
+	avatar := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := 0; i < 16; i++ {
+		avatar.SetGray(i, i, color.Gray{Y: 0xff})
+	}
+
+	tests := []struct {
+		name    string
+		avatar  image.Image
+		wantErr bool
+	}{
+		{"with avatar", avatar, false},
+		{"missing avatar", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := GenerateModel(contributionsPerYear, Options{
+				OutputPath: filepath.Join(tempDir, "avatar.stl"),
+				Format:     FormatSTL,
+				Username:   "testuser",
+				StartYear:  2023,
+				EndYear:    2023,
+				Avatar:     tt.avatar,
+				Geometry:   geometry.Config{OmitText: true, OmitLogo: true, Avatar: true},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateModelSplitYears(t *testing.T) {
+	busy, quiet := createTestContributions(), createTestContributions()
+	for _, week := range quiet {
+		for j := range week {
+			week[j].ContributionCount /= 2
+		}
+	}
+	contributionsPerYear := [][][]types.ContributionDay{quiet, busy}
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "model.stl")
+
+	err := GenerateModel(contributionsPerYear, Options{
+		OutputPath: path,
+		Format:     FormatSTL,
+		Username:   "testuser",
+		StartYear:  2023,
+		EndYear:    2024,
+		SplitYears: true,
+		Geometry:   geometry.Config{OmitText: true, OmitLogo: true},
+	})
+	if err != nil {
+		t.Fatalf("GenerateModel() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("GenerateModel() wrote the combined model %s", path)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "model-index.json"))
+	if err != nil {
+		t.Fatalf("index was not written: %v", err)
+	}
+	var index manifest
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("index is not valid JSON: %v", err)
+	}
+	if index.Years != "2023-24" || index.MaxContributions != 4 || len(index.Pieces) != 2 {
+		t.Fatalf("index = %+v, want two years scaled to 4 contributions", index)
+	}
+
+	// Both years are scaled to the busiest day of the range, so the quiet year stays lower
+	var heights []float64
+	for i, piece := range index.Pieces {
+		if piece.Year != 2023+i || len(piece.Files) != 1 || piece.Files[0] != fmt.Sprintf("model-%d.stl", piece.Year) {
+			t.Fatalf("index piece %d = %+v, want the file for %d", i, piece, 2023+i)
+		}
+		heights = append(heights, maxSTLHeight(t, filepath.Join(tempDir, piece.Files[0])))
+	}
+	if heights[0] >= heights[1] {
+		t.Errorf("quiet year rises to %v, want lower than the busy year's %v", heights[0], heights[1])
+	}
+}
+
+// TestGenerateModelResult verifies the files written and the triangles of the
+// models are reported, for a single model and for split years
+func TestGenerateModelResult(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	tests := []struct {
+		name       string
+		splitYears bool
+		want       []string
+	}{
+		{"single model", false, []string{"model.stl"}},
+		{"split years", true, []string{"model-2023.stl", "model-2024.stl", "model-index.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			result, err := GenerateModelResult(context.Background(), contributionsPerYear, Options{
+				OutputPath: filepath.Join(tempDir, "model.stl"),
+				Format:     FormatSTL,
+				Username:   "testuser",
+				StartYear:  2023,
+				EndYear:    2024,
+				SplitYears: tt.splitYears,
+				Geometry:   geometry.Config{OmitText: true, OmitLogo: true},
+			})
+			if err != nil {
+				t.Fatalf("GenerateModelResult() error = %v", err)
+			}
+			if len(result.Files) != len(tt.want) {
+				t.Fatalf("GenerateModelResult() files = %v, want %v", result.Files, tt.want)
+			}
+			triangles := 0
+			for i, file := range result.Files {
+				if filepath.Base(file) != tt.want[i] {
+					t.Errorf("GenerateModelResult() file %d = %s, want %s", i, file, tt.want[i])
+				}
+				if strings.HasSuffix(file, ".stl") {
+					read, err := ReadSTL(file)
+					if err != nil {
+						t.Fatalf("ReadSTL() error = %v", err)
+					}
+					triangles += len(read)
+				}
+			}
+			if result.Triangles != triangles {
+				t.Errorf("GenerateModelResult() triangles = %d, want the %d written", result.Triangles, triangles)
+			}
+		})
+	}
+}
+
+// TestGenerateModelSplitYearsGaps verifies years left out of a range are not
+// written, and the remaining ones are named after their own years
+func TestGenerateModelSplitYearsGaps(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "model.stl")
+
+	err := GenerateModel(contributionsPerYear, Options{
+		OutputPath: path,
+		Format:     FormatSTL,
+		Username:   "testuser",
+		StartYear:  2012,
+		EndYear:    2024,
+		Years:      []int{2012, 2024},
+		SplitYears: true,
+		Geometry:   geometry.Config{OmitText: true, OmitLogo: true},
+	})
+	if err != nil {
+		t.Fatalf("GenerateModel() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "model-index.json"))
+	if err != nil {
+		t.Fatalf("index was not written: %v", err)
+	}
+	var index manifest
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("index is not valid JSON: %v", err)
+	}
+	if index.Years != "2012-24" || len(index.Pieces) != 2 {
+		t.Fatalf("index = %+v, want the two years of 2012-24", index)
+	}
+	for i, year := range []int{2012, 2024} {
+		if piece := index.Pieces[i]; piece.Year != year || len(piece.Files) != 1 || piece.Files[0] != fmt.Sprintf("model-%d.stl", year) {
+			t.Errorf("index piece %d = %+v, want the file for %d", i, piece, year)
+		}
+	}
+}
+
+func TestGenerateModelContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+
+	for _, splitYears := range []bool{false, true} {
+		t.Run(fmt.Sprintf("split years %v", splitYears), func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := GenerateModelContext(ctx, contributionsPerYear, Options{
+				OutputPath: filepath.Join(tempDir, "model.stl"),
+				Format:     FormatSTL,
+				Username:   "testuser",
+				StartYear:  2023,
+				EndYear:    2024,
+				SplitYears: splitYears,
+			})
+			if !stderrors.Is(err, context.Canceled) {
+				t.Fatalf("GenerateModelContext() error = %v, want it canceled", err)
+			}
+			if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+				t.Errorf("GenerateModelContext() wrote %d files, want none", len(entries))
+			}
+		})
+	}
+}
+
+func TestBuildModelMirror(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	opts := Options{Username: "testuser", StartYear: 2023, EndYear: 2023}
+	plain, err := buildModel(contributionsPerYear, dims, 4, opts, nil)
+	if err != nil {
+		t.Fatalf("buildModel() error = %v", err)
+	}
+	opts.Mirror = true
+	mirrored, err := buildModel(contributionsPerYear, dims, 4, opts, nil)
+	if err != nil {
+		t.Fatalf("buildModel() with mirror error = %v", err)
+	}
+
+	plainMin, plainMax := plain.Bounds()
+	mirroredMin, mirroredMax := mirrored.Bounds()
+	if plainMin != mirroredMin || plainMax != mirroredMax {
+		t.Errorf("mirrored bounds = %v, %v, want the model kept in place at %v, %v", mirroredMin, mirroredMax, plainMin, plainMax)
+	}
+
+	// The text's leftmost point moves to the right, where the year was
+	text := func(m *types.Model) (left, right float64) {
+		left, right = math.Inf(1), math.Inf(-1)
+		for _, obj := range m.Objects {
+			if obj.Kind != types.ObjectText {
+				continue
+			}
+			for _, tri := range obj.Mesh.Triangles() {
+				left, right = math.Min(left, tri.V1.X), math.Max(right, tri.V1.X)
+			}
+		}
+		return left, right
+	}
+	plainLeft, plainRight := text(plain)
+	mirroredLeft, mirroredRight := text(mirrored)
+	center := plainMin.X + plainMax.X
+	if math.Abs(mirroredLeft-(center-plainRight)) > 1e-9 || math.Abs(mirroredRight-(center-plainLeft)) > 1e-9 {
+		t.Errorf("mirrored text spans %v to %v, want %v to %v", mirroredLeft, mirroredRight, center-plainRight, center-plainLeft)
+	}
+}
+
+// TestBuildModelRepair verifies repairing keeps a sound model intact
+func TestBuildModelRepair(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(geometry.DefaultConfig(), 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	opts := Options{Username: "testuser", StartYear: 2023, EndYear: 2023, Repair: true}
+	model, err := buildModel(contributionsPerYear, dims, 4, opts, nil)
+	if err != nil {
+		t.Fatalf("buildModel() error = %v", err)
+	}
+	if err := validateModel(model); err != nil {
+		t.Errorf("repaired model is invalid: %v", err)
+	}
+	if len(model.Objects) == 0 || model.TriangleCount() == 0 {
+		t.Error("repaired model is empty")
+	}
+}
+
+func TestBuildModelMold(t *testing.T) {
+	dims, err := calculateDimensions(geometry.Config{Mold: true}, 1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	model, err := buildModel([][][]types.ContributionDay{createTestContributions()}, dims, 4, Options{Username: "testuser", StartYear: 2023, EndYear: 2023}, nil)
+	if err != nil {
+		t.Fatalf("buildModel() error = %v", err)
+	}
+	if len(model.Objects) != 1 || model.Objects[0].Kind != types.ObjectMold || model.Objects[0].Mesh.Len() == 0 {
+		t.Fatalf("buildModel() returned %d objects, want only the mold", len(model.Objects))
+	}
+	minPoint, _ := model.Bounds()
+	if minPoint.Z != 0 {
+		t.Errorf("mold stands at %v, want on its top at 0", minPoint.Z)
+	}
+	if err := validateModel(model); err != nil {
+		t.Errorf("validateModel() error = %v", err)
+	}
+}
+
+// maxSTLHeight returns the highest Z coordinate in a binary STL file.
+func maxSTLHeight(t *testing.T, path string) float64 {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("model file was not created: %v", err)
+	}
+	top := math.Inf(-1)
+	for offset := 84; offset+50 <= len(data); offset += 50 {
+		for v := 0; v < 3; v++ {
+			z := math.Float32frombits(binary.LittleEndian.Uint32(data[offset+12+12*v+8:]))
+			top = math.Max(top, float64(z))
+		}
+	}
+	return top
+}
+
+func TestGenerateModelRadial(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	path := filepath.Join(t.TempDir(), "radial.stl")
+
+	for _, arrangement := range []geometry.Arrangement{geometry.ArrangementRadial, geometry.ArrangementSpiral} {
+		err := GenerateModel(contributionsPerYear, Options{
+			OutputPath: path,
+			Format:     FormatSTL,
+			Username:   "testuser",
+			StartYear:  2023,
+			EndYear:    2024,
+			Geometry:   geometry.Config{Arrangement: arrangement},
+		})
+		if err != nil {
+			t.Fatalf("GenerateModel() with %s layout error = %v", arrangement, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("model file was not created: %v", err)
+		}
+	}
+
+	// Features on the flat faces of the grid's base are rejected up front
+	err := GenerateModel(contributionsPerYear, Options{
+		OutputPath: path,
+		Format:     FormatSTL,
+		Username:   "testuser",
+		StartYear:  2023,
+		EndYear:    2024,
+		Geometry:   geometry.Config{Arrangement: geometry.ArrangementRadial, Stats: true},
+	})
+	if err == nil {
+		t.Error("GenerateModel() expected error for statistics on a round base")
+	}
+}
+
+func TestGenerateModelReproducible(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	tempDir := t.TempDir()
+
+	// Each format is written with one worker and with several, and must come
+	// out byte for byte the same both times
+	for _, format := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			var outputs [][]byte
+			for _, workers := range []int{1, 4} {
+				path := filepath.Join(tempDir, fmt.Sprintf("model-%d%s", workers, format.Extension()))
+				previous := runtime.GOMAXPROCS(workers)
+				err := GenerateModel(contributionsPerYear, Options{
+					OutputPath: path,
+					Format:     format,
+					Username:   "testuser",
+					StartYear:  2023,
+					EndYear:    2023,
+					Geometry:   geometry.Config{Stats: true, QRCode: "https://github.com/testuser", BaseHeight: 20},
+					Render:     render.Options{Resolution: 400, Background: render.DefaultBackground},
+				})
+				runtime.GOMAXPROCS(previous)
+				if err != nil {
+					t.Fatalf("GenerateModel() with %d workers error = %v", workers, err)
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("failed to read model file: %v", err)
+				}
+				outputs = append(outputs, data)
+			}
+			if !bytes.Equal(outputs[0], outputs[1]) {
+				t.Errorf("%s output differs between runs", format)
+			}
+		})
+	}
+}
+
+func TestStatsLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary stats.Summary
+		want    []string
+	}{
+		{"no contributions", stats.Summary{}, []string{"0 contributions"}},
+		{
+			"single day",
+			stats.Summary{Total: 3, BusiestDay: types.ContributionDay{Date: "2024-03-05", ContributionCount: 3}, LongestStreak: 1},
+			[]string{"3 contributions", "Busiest day: Mar 5, 2024 (3)", "Longest streak: 1 day"},
+		},
+		{
+			"busy year",
+			stats.Summary{Total: 12345, BusiestDay: types.ContributionDay{Date: "2023-11-20", ContributionCount: 1024}, LongestStreak: 42},
+			[]string{"12,345 contributions", "Busiest day: Nov 20, 2023 (1,024)", "Longest streak: 42 days"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statsLines(tt.summary)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("statsLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatThousands(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{123456, "123,456"},
+		{1234567, "1,234,567"},
+		{-4321, "-4,321"},
+	}
+	for _, tt := range tests {
+		if got := formatThousands(tt.n); got != tt.want {
+			t.Errorf("formatThousands(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkBuildModel measures assembling complete models from fixture
+// contributions, without writing them.
+func BenchmarkBuildModel(b *testing.B) {
+	for _, bench := range []struct {
+		name      string
+		startYear int
+		cfg       geometry.Config
+	}{
+		{"one-year", 2024, geometry.DefaultConfig()},
+		{"five-years", 2020, geometry.DefaultConfig()},
+		{"one-year-mold", 2024, geometry.Config{Mold: true, Gap: 0.5}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			contributions := fixtureContributions(bench.startYear, 2024)
+			dims, err := calculateDimensions(bench.cfg, len(contributions))
+			if err != nil {
+				b.Fatal(err)
+			}
+			opts := Options{Username: "testuser", StartYear: bench.startYear, EndYear: 2024, Geometry: bench.cfg}
+			maxContrib := findMaxContributionsAcrossYears(contributions)
+			var model *types.Model
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if model, err = buildModel(contributions, dims, maxContrib, opts, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(model.TriangleCount()), "triangles")
+		})
+	}
+}
+
+// BenchmarkGenerateModel measures the whole pipeline for a year, from
+// contributions to a binary STL file.
+func BenchmarkGenerateModel(b *testing.B) {
+	contributions := fixtureContributions(2024, 2024)
+	path := filepath.Join(b.TempDir(), "skyline.stl")
+	for i := 0; i < b.N; i++ {
+		err := GenerateModel(contributions, Options{
+			OutputPath: path,
+			Format:     FormatSTL,
+			Username:   "testuser",
+			StartYear:  2024,
+			EndYear:    2024,
+			Geometry:   geometry.DefaultConfig(),
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
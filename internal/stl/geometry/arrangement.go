@@ -0,0 +1,161 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Arrangement identifies how the towers are laid out on the base.
+type Arrangement string
+
+// Supported arrangements.
+const (
+	ArrangementGrid   Arrangement = "grid"   // Weeks in columns and days in rows on a rectangular base, one row of seven days per year
+	ArrangementRadial Arrangement = "radial" // Weeks around a round base like a clock, with days radiating outward
+	ArrangementSpiral Arrangement = "spiral" // Days winding outward from the center of a round base
+)
+
+// DefaultArrangement is the arrangement used when none is configured.
+const DefaultArrangement = ArrangementGrid
+
+// arrangements lists the supported arrangements in the order they are presented to users.
+var arrangements = []Arrangement{ArrangementGrid, ArrangementRadial, ArrangementSpiral}
+
+// Arrangements returns the names of all supported arrangements.
+func Arrangements() []string {
+	names := make([]string, len(arrangements))
+	for i, a := range arrangements {
+		names[i] = string(a)
+	}
+	return names
+}
+
+// ParseArrangement converts a user supplied arrangement name into an Arrangement.
+// Matching is case-insensitive and an empty string selects DefaultArrangement.
+func ParseArrangement(name string) (Arrangement, error) {
+	if name == "" {
+		return DefaultArrangement, nil
+	}
+	for _, a := range arrangements {
+		if strings.EqualFold(name, string(a)) {
+			return a, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported layout %q (supported: %s)", name, strings.Join(Arrangements(), ", ")), nil)
+}
+
+// roundArrangement places towers around the center of a round base. Its
+// measurements are in tower pitches, the cell size plus the gap, so a layout
+// scales with the cell size.
+type roundArrangement interface {
+	// hub returns the radius of the free center of the base.
+	hub() float64
+	// reach returns the radius of the outer edge of the towers, plus one gap.
+	reach(yearCount int) float64
+	// footprint returns the outline of a tower, counter-clockwise from above.
+	footprint(l Layout, yearIndex, weekIdx, dayIdx int) [4]point2D
+}
+
+// round returns the placement of towers for arrangements on a round base,
+// or nil for the grid.
+func (a Arrangement) round() roundArrangement {
+	switch a {
+	case ArrangementRadial:
+		return radialArrangement{}
+	case ArrangementSpiral:
+		return spiralArrangement{}
+	default:
+		return nil
+	}
+}
+
+// IsRound reports whether the towers stand on a round base, which has no flat
+// faces, so the username, year and logo are embossed in its center instead.
+func (l Layout) IsRound() bool {
+	return l.Arrangement.round() != nil
+}
+
+// validateRoundConfig rejects features that need the flat faces of the grid's
+// rectangular base.
+func (c Config) validateRoundConfig(a Arrangement) error {
+	for _, feature := range []struct {
+		used bool
+		name string
+	}{
+		{c.CornerRadius > 0, "rounded corners"},
+		{c.LogoFile != "", "a custom logo"},
+		{c.TextStyle == TextVector, "vector text"},
+		{c.QRCode != "", "a QR code"},
+		{c.Stats, "statistics on the back"},
+		{c.Avatar, "an avatar panel"},
+		{c.Stack, "stacked years"},
+		{c.MonthLabels != "" && c.MonthLabels != MonthLabelsNone, "month labels"},
+		{c.YearLabels, "year labels"},
+		{c.YearDividers, "year dividers"},
+		{c.Weekdays, "weekdays only"},
+		{c.Weeks > 0 && c.Weeks != GridSize, "a grid cut short"},
+	} {
+		if feature.used {
+			return errors.New(errors.ValidationError, fmt.Sprintf("%s cannot be combined with the round base of the %s layout", feature.name, a), nil)
+		}
+	}
+	if c.BaseWidth > 0 && c.BaseDepth > 0 && c.BaseWidth != c.BaseDepth {
+		return errors.New(errors.ValidationError, fmt.Sprintf("the round base of the %s layout needs equal base width and depth", a), nil)
+	}
+	return nil
+}
+
+// roundSpan returns the radius of the outer edge of the towers of a round
+// arrangement.
+func roundSpan(round roundArrangement, yearCount int, cell, gap float64) float64 {
+	return round.reach(yearCount)*(cell+gap) - gap
+}
+
+// roundCellSize returns the cell size for a round arrangement, taken from cfg
+// or solved so that the towers and their padding fit the given base diameter.
+func roundCellSize(round roundArrangement, cfg Config, yearCount int) (float64, error) {
+	diameter := math.Max(cfg.BaseWidth, cfg.BaseDepth)
+	if cfg.CellSize > 0 {
+		need := 2 * (roundSpan(round, yearCount, cfg.CellSize, cfg.Gap) + gridPadding*cfg.CellSize)
+		if diameter > 0 && diameter < need {
+			return 0, errors.New(errors.ValidationError, fmt.Sprintf("base diameter of %s is too small for %s towers (at least %s needed)", cfg.Unit.Format(diameter), cfg.Unit.Format(cfg.CellSize), cfg.Unit.Format(need)), nil)
+		}
+		return cfg.CellSize, nil
+	}
+	if diameter == 0 {
+		return CellSize, nil
+	}
+	// Solve diameter/2 = reach*(cell+gap) - gap + padding*cell for cell
+	reach := round.reach(yearCount)
+	cell := (diameter/2 + cfg.Gap - reach*cfg.Gap) / (reach + gridPadding)
+	if cell <= 0 {
+		return 0, errors.New(errors.ValidationError, fmt.Sprintf("gap of %s leaves no room for towers on the base", cfg.Unit.Format(cfg.Gap)), nil)
+	}
+	return cell, nil
+}
+
+// roundClearsTowers reports whether the top face of a round base, with its
+// outline approximated by straight segments, fully supports the outermost towers.
+func (l Layout) roundClearsTowers() bool {
+	radius := (l.Width/2 - l.topInset()) * math.Cos(math.Pi/(4*cornerSegments))
+	return l.Width/2-l.OffsetX <= radius
+}
+
+// towerRing returns the outline of a tower at height z as a ring for createLoft.
+func towerRing(outline []point2D, z float64) outlineRing {
+	ring := outlineRing{z: z, points: make([]types.Point3D, len(outline))}
+	for i, p := range outline {
+		ring.points[i] = types.Point3D{X: p.X, Y: p.Y, Z: z}
+	}
+	return ring
+}
+
+// createPrism generates a column of the given height standing on the base
+// with the given convex outline.
+func createPrism(outline []point2D, height float64) ([]types.Triangle, error) {
+	return createLoft([]outlineRing{towerRing(outline, 0), towerRing(outline, height)})
+}
@@ -0,0 +1,160 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// TestParseArrangement verifies arrangement name parsing
+func TestParseArrangement(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Arrangement
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultArrangement, false},
+		{"grid", "grid", ArrangementGrid, false},
+		{"radial", "Radial", ArrangementRadial, false},
+		{"spiral", "SPIRAL", ArrangementSpiral, false},
+		{"unknown", "hexagonal", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArrangement(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseArrangement(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseArrangement(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateRoundConfig verifies features needing flat faces are rejected on round bases
+func TestValidateRoundConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"radial", Config{Arrangement: ArrangementRadial}, false},
+		{"unknown arrangement", Config{Arrangement: "hexagonal"}, true},
+		{"spiral", Config{Arrangement: ArrangementSpiral}, false},
+		{"spiral with stats", Config{Arrangement: ArrangementSpiral, Stats: true}, true},
+		{"diameter from width", Config{Arrangement: ArrangementRadial, BaseWidth: 120}, false},
+		{"equal width and depth", Config{Arrangement: ArrangementRadial, BaseWidth: 120, BaseDepth: 120}, false},
+		{"unequal width and depth", Config{Arrangement: ArrangementRadial, BaseWidth: 120, BaseDepth: 100}, true},
+		{"corner radius", Config{Arrangement: ArrangementRadial, CornerRadius: 3}, true},
+		{"custom logo", Config{Arrangement: ArrangementRadial, LogoFile: "logo.svg"}, true},
+		{"vector text", Config{Arrangement: ArrangementRadial, TextStyle: TextVector}, true},
+		{"qr code", Config{Arrangement: ArrangementRadial, QRCode: "https://github.com/octocat"}, true},
+		{"stats", Config{Arrangement: ArrangementRadial, Stats: true}, true},
+		{"avatar", Config{Arrangement: ArrangementRadial, Avatar: true}, true},
+		{"grid with qr code", Config{Arrangement: ArrangementGrid, QRCode: "https://github.com/octocat"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewLayoutRound verifies round bases are sized around their towers
+func TestNewLayoutRound(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		years     int
+		wantCell  float64
+		wantWidth float64
+	}{
+		{"default", Config{Arrangement: ArrangementRadial}, 1, CellSize, 2 * (radialArrangement{}.reach(1)*CellSize + gridPadding*CellSize)},
+		{"three years", Config{Arrangement: ArrangementRadial}, 3, CellSize, 2 * (radialArrangement{}.reach(3)*CellSize + gridPadding*CellSize)},
+		{"cell size", Config{Arrangement: ArrangementRadial, CellSize: 2}, 1, 2, 2 * (radialArrangement{}.reach(1)*2 + gridPadding*2)},
+		{"spiral", Config{Arrangement: ArrangementSpiral}, 2, CellSize, 2 * (spiralArrangement{}.reach(2)*CellSize + gridPadding*CellSize)},
+		{"diameter", Config{Arrangement: ArrangementRadial, BaseDepth: 100}, 1, 50 / (radialArrangement{}.reach(1) + gridPadding), 100},
+		{"diameter with gap", Config{Arrangement: ArrangementRadial, BaseWidth: 100, Gap: 0.5}, 1, (50 + 0.5 - radialArrangement{}.reach(1)*0.5) / (radialArrangement{}.reach(1) + gridPadding), 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.cfg, tt.years)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			if !layout.IsRound() {
+				t.Fatal("IsRound() = false, want true")
+			}
+			if math.Abs(layout.CellSize-tt.wantCell) > epsilon {
+				t.Errorf("cell size = %v, want %v", layout.CellSize, tt.wantCell)
+			}
+			if math.Abs(layout.Width-tt.wantWidth) > epsilon || layout.Depth != layout.Width {
+				t.Errorf("base = %vx%v, want %vx%v", layout.Width, layout.Depth, tt.wantWidth, tt.wantWidth)
+			}
+			if layout.CornerRadius != layout.Width/2 {
+				t.Errorf("corner radius = %v, want %v", layout.CornerRadius, layout.Width/2)
+			}
+			// The padding around the outermost towers matches the grid's
+			if want := gridPadding * layout.CellSize; math.Abs(layout.OffsetX-want) > epsilon && tt.cfg.BaseWidth == 0 && tt.cfg.BaseDepth == 0 {
+				t.Errorf("padding = %v, want %v", layout.OffsetX, want)
+			}
+			if layout.YearCount != tt.years {
+				t.Errorf("year count = %d, want %d", layout.YearCount, tt.years)
+			}
+		})
+	}
+}
+
+// TestNewLayoutRoundErrors verifies round bases too small for their towers are rejected
+func TestNewLayoutRoundErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"diameter too small for cell size", Config{Arrangement: ArrangementRadial, CellSize: 2.5, BaseWidth: 50}},
+		{"gap fills the base", Config{Arrangement: ArrangementRadial, BaseWidth: 40, Gap: 5}},
+		{"chamfer reaches the towers", Config{Arrangement: ArrangementRadial, Chamfer: 6, BaseHeight: 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLayout(tt.cfg, 1); err == nil {
+				t.Error("NewLayout() expected error")
+			}
+		})
+	}
+}
+
+// TestLayoutCreateRoundBase verifies a round base is a closed disc around its towers
+func TestLayoutCreateRoundBase(t *testing.T) {
+	for _, cfg := range []Config{
+		{Arrangement: ArrangementRadial},
+		{Arrangement: ArrangementSpiral},
+		{Arrangement: ArrangementRadial, BaseStyle: BaseSloped, Chamfer: 1},
+		{Arrangement: ArrangementRadial, Hollow: 2, DrainHole: 3},
+	} {
+		layout, err := NewLayout(cfg, 1)
+		if err != nil {
+			t.Fatalf("NewLayout(%+v) error = %v", cfg, err)
+		}
+		triangles, err := layout.CreateBase()
+		if err != nil {
+			t.Fatalf("CreateBase() error = %v", err)
+		}
+		if !isClosedMesh(triangles) {
+			t.Errorf("CreateBase(%+v) mesh is not closed", cfg)
+		}
+		radius := layout.Width / 2
+		for _, tri := range triangles {
+			if d := math.Hypot(tri.V1.X-radius, tri.V1.Y-radius); d > radius+epsilon {
+				t.Fatalf("vertex %v is %v from the center, outside the %vmm radius", tri.V1, d, radius)
+			}
+		}
+	}
+}
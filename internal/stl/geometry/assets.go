@@ -1,73 +1,114 @@
 package geometry
 
 import (
+	"bytes"
 	"embed"
-	"fmt"
-	"os"
+	"image"
+	"image/png"
+	"math"
+	"sync"
 
+	"github.com/fogleman/gg"
 	"github.com/github/gh-skyline/internal/errors"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
 )
 
 //go:embed assets/*
 var embeddedAssets embed.FS
 
-// writeTempFont writes the embedded font to a temporary file and returns its path.
-// The caller is responsible for cleaning up the temporary file.
-func writeTempFont(fontName string) (string, func(), error) {
-	fontBytes, err := embeddedAssets.ReadFile("assets/" + fontName)
-	if err != nil {
-		return "", nil, errors.New(errors.IOError, "failed to read embedded font", err)
-	}
-
-	// Create temp file with .ttf extension to ensure proper font loading
-	tmpFile, err := os.CreateTemp("", "skyline-font-*.ttf")
-	if err != nil {
-		return "", nil, errors.New(errors.IOError, "failed to create temp font file", err)
-	}
+// rasterFont is the embedded font parsed for drawing, loaded once on first use
+// and shared by every drawing, as fonts are read-only once parsed.
+var rasterFont struct {
+	once sync.Once
+	font *truetype.Font
+	err  error
+}
 
-	if _, err := tmpFile.Write(fontBytes); err != nil {
-		closeErr := tmpFile.Close()
-		removeErr := os.Remove(tmpFile.Name())
-		return "", nil, errors.New(errors.IOError, "failed to write font to temp file", fmt.Errorf("%w; close error: %v; remove error: %v", err, closeErr, removeErr))
-	}
-	if err := tmpFile.Close(); err != nil {
-		removeErr := os.Remove(tmpFile.Name())
-		return "", nil, errors.New(errors.IOError, "failed to close temp font file", fmt.Errorf("%w; remove error: %v", err, removeErr))
-	}
+// loadRasterFont parses the embedded font for drawing, falling back to the
+// secondary font.
+func loadRasterFont() (*truetype.Font, error) {
+	rasterFont.once.Do(func() {
+		var lastErr error
+		for _, name := range []string{PrimaryFont, FallbackFont} {
+			f, err := parseRasterFont(name)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			rasterFont.font = f
+			return
+		}
+		rasterFont.err = errors.New(errors.IOError, "failed to load any fonts", lastErr)
+	})
+	return rasterFont.font, rasterFont.err
+}
 
-	cleanup := func() {
-		_ = os.Remove(tmpFile.Name()) // Ignore cleanup errors in defer
+// CheckFonts reports whether the embedded fonts text is drawn and outlined
+// with can be loaded, so models with text can be generated.
+func CheckFonts() error {
+	if _, err := loadRasterFont(); err != nil {
+		return err
 	}
-
-	return tmpFile.Name(), cleanup, nil
+	_, err := loadOutlineFont()
+	return err
 }
 
-// getEmbeddedImage returns a temporary file path for the embedded image.
-// The caller is responsible for cleaning up the temporary file.
-func getEmbeddedImage() (string, func(), error) {
-	imgBytes, err := embeddedAssets.ReadFile("assets/invertocat.png")
+// parseRasterFont parses an embedded font by file name.
+func parseRasterFont(name string) (*truetype.Font, error) {
+	fontBytes, err := embeddedAssets.ReadFile("assets/" + name)
 	if err != nil {
-		return "", nil, errors.New(errors.IOError, "failed to read embedded image", err)
+		return nil, errors.New(errors.IOError, "failed to read embedded font", err)
 	}
-
-	tmpFile, err := os.CreateTemp("", "skyline-img-*.png")
+	f, err := truetype.Parse(fontBytes)
 	if err != nil {
-		return "", nil, errors.New(errors.IOError, "failed to create temp image file", err)
+		return nil, errors.New(errors.IOError, "failed to parse embedded font", err)
 	}
+	return f, nil
+}
 
-	if _, err := tmpFile.Write(imgBytes); err != nil {
-		closeErr := tmpFile.Close()
-		removeErr := os.Remove(tmpFile.Name())
-		return "", nil, errors.New(errors.IOError, "failed to write image to temp file", fmt.Errorf("%w; close error: %v; remove error: %v", err, closeErr, removeErr))
-	}
-	if err := tmpFile.Close(); err != nil {
-		removeErr := os.Remove(tmpFile.Name())
-		return "", nil, errors.New(errors.IOError, "failed to close temp image file", fmt.Errorf("%w; remove error: %v", err, removeErr))
-	}
+// fontFace is a font face whose line height is that gg gives fonts loaded from
+// a file, three quarters of the size in points, which vertically anchored text
+// is centered with.
+type fontFace struct {
+	font.Face
+	height fixed.Int26_6
+}
 
-	cleanup := func() {
-		_ = os.Remove(tmpFile.Name()) // Ignore cleanup errors in defer
-	}
+// Metrics returns the metrics of the face with the adjusted line height.
+func (f fontFace) Metrics() font.Metrics {
+	m := f.Face.Metrics()
+	m.Height = f.height
+	return m
+}
+
+// setFontFace makes dc draw text in f at the size in points.
+func setFontFace(dc *gg.Context, f *truetype.Font, points float64) {
+	// Each face draws a few short strings, so a small glyph cache is plenty; the
+	// default one allocates masks for 512 glyphs, which dominates large text
+	face := truetype.NewFace(f, &truetype.Options{Size: points, GlyphCacheEntries: 16})
+	dc.SetFontFace(fontFace{Face: face, height: fixed.Int26_6(math.Round(points * 72 / 96 * 64))})
+}
+
+// logo is the embedded logo image, decoded once on first use.
+var logo struct {
+	once  sync.Once
+	image image.Image
+	err   error
+}
 
-	return tmpFile.Name(), cleanup, nil
+// loadLogo decodes the embedded logo image.
+func loadLogo() (image.Image, error) {
+	logo.once.Do(func() {
+		imgBytes, err := embeddedAssets.ReadFile("assets/invertocat.png")
+		if err != nil {
+			logo.err = errors.New(errors.IOError, "failed to read embedded image", err)
+			return
+		}
+		if logo.image, err = png.Decode(bytes.NewReader(imgBytes)); err != nil {
+			logo.err = errors.New(errors.IOError, "failed to decode PNG", err)
+		}
+	})
+	return logo.image, logo.err
 }
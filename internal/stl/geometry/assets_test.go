@@ -1,86 +1,94 @@
 package geometry
 
 import (
+	"bytes"
+	"image"
 	"os"
 	"path/filepath"
 	"testing"
-)
 
-// TestWriteTempFont verifies temporary font file creation and cleanup
-func TestWriteTempFont(t *testing.T) {
-	t.Run("verify valid font extraction", func(t *testing.T) {
-		fontPath, cleanup, err := writeTempFont("monasans-medium.ttf")
-		if err != nil {
-			t.Fatalf("writeTempFont failed: %v", err)
-		}
-		defer cleanup()
+	"github.com/fogleman/gg"
+)
 
-		// Verify file exists and has content
-		content, err := os.ReadFile(fontPath)
+// TestLoadRasterFont verifies the embedded fonts are parsed in memory
+func TestLoadRasterFont(t *testing.T) {
+	t.Run("verify embedded font loading", func(t *testing.T) {
+		f, err := loadRasterFont()
 		if err != nil {
-			t.Errorf("Failed to read temp font file: %v", err)
+			t.Fatalf("loadRasterFont failed: %v", err)
 		}
-		if len(content) == 0 {
-			t.Error("Temp font file is empty")
+		if f == nil {
+			t.Fatal("loadRasterFont returned no font")
 		}
 
-		// Verify file extension
-		if filepath.Ext(fontPath) != ".ttf" {
-			t.Errorf("Expected .ttf extension, got %s", filepath.Ext(fontPath))
-		}
-
-		// Verify cleanup works
-		cleanup()
-		if _, err := os.Stat(fontPath); !os.IsNotExist(err) {
-			t.Error("Temp font file not cleaned up properly")
+		// The font is parsed once and shared
+		again, err := loadRasterFont()
+		if err != nil || again != f {
+			t.Errorf("second loadRasterFont = %p, %v, want the same font", again, err)
 		}
 	})
 
 	t.Run("verify nonexistent font handling", func(t *testing.T) {
-		_, cleanup, err := writeTempFont("nonexistent.ttf")
-		if err == nil {
-			defer cleanup()
+		if _, err := parseRasterFont("nonexistent.ttf"); err == nil {
 			t.Error("Expected error for nonexistent font")
 		}
 	})
 }
 
-// TestGetEmbeddedImage verifies temporary image file creation and cleanup
-func TestGetEmbeddedImage(t *testing.T) {
-	t.Run("verify valid image extraction", func(t *testing.T) {
-		imagePath, cleanup, err := getEmbeddedImage()
-		if err != nil {
-			t.Fatalf("getEmbeddedImage failed: %v", err)
+// TestCheckFonts verifies the embedded fonts are reported as loadable
+func TestCheckFonts(t *testing.T) {
+	if err := CheckFonts(); err != nil {
+		t.Errorf("CheckFonts() error = %v", err)
+	}
+}
+
+// TestSetFontFace verifies faces keep the line height of fonts loaded from files
+func TestSetFontFace(t *testing.T) {
+	f, err := loadRasterFont()
+	if err != nil {
+		t.Fatalf("loadRasterFont failed: %v", err)
+	}
+	fontPath := filepath.Join(t.TempDir(), PrimaryFont)
+	fontBytes, err := embeddedAssets.ReadFile("assets/" + PrimaryFont)
+	if err != nil {
+		t.Fatalf("failed to read embedded font: %v", err)
+	}
+	if err := os.WriteFile(fontPath, fontBytes, 0644); err != nil {
+		t.Fatalf("failed to write font file: %v", err)
+	}
+
+	for _, size := range []float64{8, 40, 123.5} {
+		fromFile, inMemory := gg.NewContext(400, 200), gg.NewContext(400, 200)
+		if err := fromFile.LoadFontFace(fontPath, size); err != nil {
+			t.Fatalf("LoadFontFace failed: %v", err)
 		}
-		defer cleanup()
+		setFontFace(inMemory, f, size)
 
-		// Verify file exists and has content
-		content, err := os.ReadFile(imagePath)
-		if err != nil {
-			t.Errorf("Failed to read temp image file: %v", err)
+		if fromFile.FontHeight() != inMemory.FontHeight() {
+			t.Errorf("size %g: FontHeight() = %g, want %g", size, inMemory.FontHeight(), fromFile.FontHeight())
 		}
-		if len(content) == 0 {
-			t.Error("Temp image file is empty")
+		for _, dc := range []*gg.Context{fromFile, inMemory} {
+			dc.SetRGB(1, 1, 1)
+			dc.DrawStringAnchored("skyline 2024", 200, 100, 0.5, 0.5)
 		}
-
-		// Verify file extension
-		if filepath.Ext(imagePath) != ".png" {
-			t.Errorf("Expected .png extension, got %s", filepath.Ext(imagePath))
+		if !bytes.Equal(fromFile.Image().(*image.RGBA).Pix, inMemory.Image().(*image.RGBA).Pix) {
+			t.Errorf("size %g: text drawn in memory differs from text drawn with a font file", size)
 		}
+	}
+}
 
-		// Verify cleanup works
-		cleanup()
-		if _, err := os.Stat(imagePath); !os.IsNotExist(err) {
-			t.Error("Temp image file not cleaned up properly")
-		}
-	})
+// TestLoadLogo verifies the embedded logo is decoded in memory
+func TestLoadLogo(t *testing.T) {
+	img, err := loadLogo()
+	if err != nil {
+		t.Fatalf("loadLogo failed: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() == 0 || bounds.Dy() == 0 {
+		t.Errorf("logo is %dx%d pixels, want an image", bounds.Dx(), bounds.Dy())
+	}
 
-	// Test embedded filesystem access
-	t.Run("verify embedded filesystem access", func(t *testing.T) {
-		// Try to read the embedded image directly
-		_, err := embeddedAssets.ReadFile("assets/invertocat.png")
-		if err != nil {
-			t.Errorf("Failed to access embedded image: %v", err)
-		}
-	})
+	// The logo is decoded once and shared
+	if again, err := loadLogo(); err != nil || again != img {
+		t.Errorf("second loadLogo = %v, %v, want the same image", again, err)
+	}
 }
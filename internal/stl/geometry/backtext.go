@@ -0,0 +1,142 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/qr"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	backTextMargin        = 0.03  // Share of the base width kept clear at each end of the back face
+	backTextMaxFontSize   = 80.0  // Largest back face font, in face voxels
+	backTextMinFontSize   = 28.0  // Smallest back face font, in face voxels, that still prints legibly
+	backTextLineSpacing   = 1.25  // Distance between baselines as a multiple of the font size
+	backTextFill          = 0.85  // Share of the flat face height the text may take
+	backTextSeparator     = " · " // Joins the lines when they fit better on a single line
+	backTextReferenceSize = 100.0 // Font size the lines are measured at before scaling to fit
+)
+
+// backClearance returns how far from each end the back face is flat, clear of
+// the rounded corners and the sloped sides.
+func (l Layout) backClearance() float64 {
+	return math.Max(l.Width*backTextMargin, math.Max(l.CornerRadius, l.topInset()))
+}
+
+// backTextRegion returns the range of X, in millimeters, that the back face
+// leaves for text, next to the QR code when there is one.
+func (l Layout) backTextRegion() (float64, float64, error) {
+	x0, x1 := l.backClearance(), l.Width-l.backClearance()
+	if l.QRCode != "" {
+		code, err := qr.Encode(l.QRCode)
+		if err != nil {
+			return 0, 0, err
+		}
+		qrX0, _, moduleSize := l.qrPlacement(code)
+		x0 = qrX0 + float64(code.Size+qrQuietZone)*moduleSize
+	}
+	return x0, x1, nil
+}
+
+// CreateStats generates lines of text, such as contribution statistics, on
+// the back face of the base, as voxels like the front face text. The text reads
+// correctly from behind, starting at the left as seen from there, and is sized
+// to fill the face either on one line or with one line each.
+func (l Layout) CreateStats(lines []string) ([]types.Triangle, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	x0, x1, err := l.backTextRegion()
+	if err != nil {
+		return nil, err
+	}
+
+	faceWidthRes := baseWidthVoxelResolution
+	faceHeightRes := int(float64(faceWidthRes) * l.Height / l.Width)
+	toMillimeters := l.Width / float64(faceWidthRes)
+	regionWidth := (x1 - x0) / toMillimeters
+	flatHeight := (l.Height - 2*l.Chamfer) / toMillimeters
+
+	// Sizes are worked out in face voxels, and drawn at the layout's resolution
+	drawScale := float64(l.FaceResolution) / float64(faceWidthRes)
+	dc := gg.NewContext(l.FaceResolution, int(float64(l.FaceResolution)*l.Height/l.Width))
+	f, err := loadRasterFont()
+	if err != nil {
+		return nil, err
+	}
+
+	// Measure both arrangements and keep the one allowing the larger font
+	setFontFace(dc, f, backTextReferenceSize)
+	var best []string
+	var fontSize, needHeight float64
+	needHeight = math.Inf(1)
+	for _, arrangement := range [][]string{{strings.Join(lines, backTextSeparator)}, lines} {
+		var widest float64
+		for _, line := range arrangement {
+			w, _ := dc.MeasureString(line)
+			widest = math.Max(widest, w)
+		}
+		rows := float64(len(arrangement)) * backTextLineSpacing
+		fitWidth := backTextReferenceSize * regionWidth / widest
+		size := math.Min(backTextMaxFontSize, math.Min(fitWidth, flatHeight*backTextFill/rows))
+		if size > fontSize {
+			best, fontSize = arrangement, size
+		}
+		if fitWidth >= backTextMinFontSize {
+			needHeight = math.Min(needHeight, backTextMinFontSize*rows/backTextFill*toMillimeters+2*l.Chamfer)
+		}
+	}
+	if fontSize < backTextMinFontSize {
+		if math.IsInf(needHeight, 1) {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("text on the back face does not fit legibly on a %s wide base", l.Unit.Format(l.Width)), nil)
+		}
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("text on the back face does not fit legibly on a %s thick base (at least %s needed)", l.Unit.Format(l.Height), l.Unit.Format(needHeight)), nil)
+	}
+
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetRGB(1, 1, 1)
+	setFontFace(dc, f, fontSize*drawScale)
+	// Seen from behind, the face's left edge is at the largest X
+	left := (l.Width - x1) / toMillimeters
+	for i, line := range best {
+		offset := (float64(i) - float64(len(best)-1)/2) * fontSize * backTextLineSpacing
+		dc.DrawStringAnchored(line, left*drawScale, (float64(faceHeightRes)*0.5+offset)*drawScale, 0, 0.5)
+	}
+
+	var triangles []types.Triangle
+	// Voxels on a sloped face step in row by row, so only merge along rows
+	for _, r := range mergeDrawing(dc, l.FrontSlope() == 0) {
+		voxel, err := createVoxelOnBack(float64(r.x)/drawScale, float64(r.y)/drawScale, float64(r.w)/drawScale, float64(r.h)/drawScale, voxelDepth, l.Width, l.Height, l.Depth, l.FrontSlope())
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create cube", err)
+		}
+		triangles = append(triangles, voxel...)
+	}
+	return triangles, nil
+}
+
+// createVoxelOnBack creates a voxel on the back face of a base, at the face
+// voxel x, y counted from the top left corner as seen from behind, spanning
+// width by tall face voxels and coming out of the face by height. The back face
+// leans in by slope like the front face.
+func createVoxelOnBack(x float64, y float64, width float64, tall float64, height float64, baseWidth float64, baseHeight float64, baseDepth float64, slope float64) ([]types.Triangle, error) {
+	voxelSize := baseWidth / baseWidthVoxelResolution
+
+	// Set the voxel against the face at its top edge, where a sloped face leans furthest in
+	top := y * voxelSize
+	faceY := baseDepth - slope*(baseHeight-top)
+
+	return CreateCube(
+		baseWidth-(x+width)*voxelSize, // Mirrored, as X runs right to left when seen from behind
+		faceY,
+		-top-tall*voxelSize,
+		width*voxelSize,
+		height,
+		tall*voxelSize,
+	)
+}
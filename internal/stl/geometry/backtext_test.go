@@ -0,0 +1,119 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+var testStatsLines = []string{"1,234 contributions", "Busiest day: Mar 5, 2024 (87)", "Longest streak: 42 days"}
+
+// TestLayoutCreateStats verifies statistics are embossed within the back face
+func TestLayoutCreateStats(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"default base", Config{Stats: true}},
+		{"thick base", Config{Stats: true, BaseHeight: 25}},
+		{"sloped rounded base", Config{Stats: true, BaseStyle: BaseSloped, CornerRadius: 4}},
+		{"beside a QR code", Config{Stats: true, BaseHeight: 20, QRCode: "https://github.com/octocat"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.cfg, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			triangles, err := layout.CreateStats(testStatsLines)
+			if err != nil {
+				t.Fatalf("CreateStats() error = %v", err)
+			}
+			if len(triangles) == 0 {
+				t.Fatal("CreateStats() returned no triangles")
+			}
+
+			x0, x1, err := layout.backTextRegion()
+			if err != nil {
+				t.Fatalf("backTextRegion() error = %v", err)
+			}
+			voxel := layout.Width / baseWidthVoxelResolution
+			slope := layout.FrontSlope()
+			for _, tri := range triangles {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if v.X < x0-voxel || v.X > x1+voxel {
+						t.Fatalf("vertex %v is outside the text region x=%g..%g", v, x0, x1)
+					}
+					if v.Z < -layout.Height+layout.Chamfer-epsilon || v.Z > -layout.Chamfer+epsilon {
+						t.Fatalf("vertex %v is off the flat part of the back face", v)
+					}
+					face := layout.Depth - slope*(layout.Height+v.Z)
+					if v.Y < face-slope*voxel-epsilon || v.Y > face+voxelDepth+epsilon {
+						t.Fatalf("vertex %v is detached from the back face at y=%g", v, face)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestLayoutCreateStatsErrors verifies text that would be too small is rejected
+func TestLayoutCreateStatsErrors(t *testing.T) {
+	layout, err := NewLayout(Config{Stats: true, BaseHeight: 2, OmitText: true, OmitLogo: true}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if _, err := layout.CreateStats(testStatsLines); err == nil {
+		t.Error("CreateStats() expected error on a 2mm thick base")
+	}
+
+	if triangles, err := layout.CreateStats(nil); err != nil || triangles != nil {
+		t.Errorf("CreateStats(nil) = %d triangles, %v; want none", len(triangles), err)
+	}
+}
+
+// TestBackTextRegionBesideQRCode verifies text and QR code share the back face without overlapping
+func TestBackTextRegionBesideQRCode(t *testing.T) {
+	layout, err := NewLayout(Config{Stats: true, BaseHeight: 20, QRCode: "https://github.com/octocat"}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	x0, _, err := layout.backTextRegion()
+	if err != nil {
+		t.Fatalf("backTextRegion() error = %v", err)
+	}
+	qrTriangles, err := layout.CreateQRCode()
+	if err != nil {
+		t.Fatalf("CreateQRCode() error = %v", err)
+	}
+	qrMaxX := math.Inf(-1)
+	for _, tri := range qrTriangles {
+		qrMaxX = math.Max(qrMaxX, math.Max(tri.V1.X, math.Max(tri.V2.X, tri.V3.X)))
+	}
+	if qrMaxX >= x0 {
+		t.Errorf("QR code reaches x=%g, into the text region from x=%g", qrMaxX, x0)
+	}
+	if qrMaxX > layout.Width/2 {
+		t.Errorf("QR code reaches x=%g, want it kept to the right as seen from behind", qrMaxX)
+	}
+}
+
+// TestCreateVoxelOnBack verifies back face voxels are mirrored and set against the face
+func TestCreateVoxelOnBack(t *testing.T) {
+	triangles, err := createVoxelOnBack(0, 0, 1, 1, 1, 100, 10, 40, 0)
+	if err != nil {
+		t.Fatalf("createVoxelOnBack() error = %v", err)
+	}
+	voxel := 100.0 / baseWidthVoxelResolution
+	minX, minY, maxZ := math.Inf(1), math.Inf(1), math.Inf(-1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			minX, minY, maxZ = math.Min(minX, v.X), math.Min(minY, v.Y), math.Max(maxZ, v.Z)
+		}
+	}
+	if math.Abs(minX-(100-voxel)) > epsilon || math.Abs(minY-40) > epsilon || math.Abs(maxZ) > epsilon {
+		t.Errorf("first voxel starts at (%g, %g) with top %g, want (%g, 40) with top 0", minX, minY, maxZ, 100-voxel)
+	}
+}
@@ -0,0 +1,155 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// BaseStyle identifies the shape of the base the towers stand on.
+type BaseStyle string
+
+// Supported base styles.
+const (
+	BaseFlat   BaseStyle = "flat"   // Rectangular slab with vertical walls
+	BaseSloped BaseStyle = "sloped" // Trapezoidal slab with walls leaning inward, like skyline.github.com
+)
+
+// DefaultBaseStyle is the base style used when none is configured.
+const DefaultBaseStyle = BaseFlat
+
+// slopedInsetRatio is the share of the grid padding taken up by the slope of a
+// sloped base, leaving the rest as a flat margin around the towers.
+const slopedInsetRatio = 0.6
+
+// baseStyles lists the supported base styles in the order they are presented to users.
+var baseStyles = []BaseStyle{BaseFlat, BaseSloped}
+
+// BaseStyles returns the names of all supported base styles.
+func BaseStyles() []string {
+	names := make([]string, len(baseStyles))
+	for i, s := range baseStyles {
+		names[i] = string(s)
+	}
+	return names
+}
+
+// ParseBaseStyle converts a user supplied base style name into a BaseStyle.
+// Matching is case-insensitive and an empty string selects DefaultBaseStyle.
+func ParseBaseStyle(name string) (BaseStyle, error) {
+	if name == "" {
+		return DefaultBaseStyle, nil
+	}
+	for _, s := range baseStyles {
+		if strings.EqualFold(name, string(s)) {
+			return s, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported base style %q (supported: %s)", name, strings.Join(BaseStyles(), ", ")), nil)
+}
+
+// baseInset returns how far the top edges of a base in the given style are
+// inset from its bottom edges. The slope never reaches the contribution grid.
+func baseInset(style BaseStyle, cell, offsetX, offsetY float64) float64 {
+	if style != BaseSloped {
+		return 0
+	}
+	return math.Min(slopedInsetRatio*gridPadding*cell, math.Min(offsetX, offsetY))
+}
+
+// CreateBase generates triangles for the base described by the layout,
+// including the tiers of stacked years and the dividers between years.
+func (l Layout) CreateBase() ([]types.Triangle, error) {
+	slab, err := l.createSlab()
+	if err != nil {
+		return nil, err
+	}
+	tiers, err := l.createTiers()
+	if err != nil {
+		return nil, err
+	}
+	dividers, err := l.createYearDividers()
+	if err != nil {
+		return nil, err
+	}
+	return append(append(slab, tiers...), dividers...), nil
+}
+
+// createSlab generates triangles for the slab of the base.
+func (l Layout) createSlab() ([]types.Triangle, error) {
+	// The base starts at Z = -Height and extends to Z = 0
+	if l.Hollow > 0 {
+		return l.createHollowBase()
+	}
+	if l.CornerRadius > 0 || l.Chamfer > 0 {
+		return createLoft(l.baseRings())
+	}
+	if l.BaseInset > 0 {
+		return createFrustum(0, 0, -l.Height, l.Width, l.Depth, l.Height, l.BaseInset)
+	}
+	return createBox(0, 0, -l.Height, l.Width, l.Depth, l.Height)
+}
+
+// baseRings returns the outlines of the base from bottom to top. The wall runs
+// from the bottom outline to the top outline inset by BaseInset, and a chamfer
+// cuts an extra ring in at both ends of the wall.
+func (l Layout) baseRings() []outlineRing {
+	ring := func(z, inset float64) outlineRing {
+		return roundedRectRing(inset, inset, l.Width-inset, l.Depth-inset, l.CornerRadius-inset, z)
+	}
+	// wallInset is how far the uncut wall has leaned in at height z
+	wallInset := func(z float64) float64 {
+		return l.FrontSlope() * (z + l.Height)
+	}
+
+	if l.Chamfer == 0 {
+		return []outlineRing{ring(-l.Height, 0), ring(0, l.BaseInset)}
+	}
+	bottom, top := -l.Height, 0.0
+	return []outlineRing{
+		ring(bottom, l.Chamfer),
+		ring(bottom+l.Chamfer, wallInset(bottom+l.Chamfer)),
+		ring(top-l.Chamfer, wallInset(top-l.Chamfer)),
+		ring(top, l.BaseInset+l.Chamfer),
+	}
+}
+
+// FrontSlope returns how far the front face of the base leans back per unit of
+// height, which is zero for a flat base.
+func (l Layout) FrontSlope() float64 {
+	if l.Height <= 0 {
+		return 0
+	}
+	return l.BaseInset / l.Height
+}
+
+// topInset returns how far the edges of the top face are inset from the bottom
+// outline of the base, which is where the towers must stay clear of.
+func (l Layout) topInset() float64 {
+	return l.BaseInset + l.Chamfer
+}
+
+// cornersClearGrid reports whether the rounded corners and edges of the top
+// face leave the outermost towers fully supported.
+func (l Layout) cornersClearGrid() bool {
+	if l.IsRound() {
+		return l.roundClearsTowers()
+	}
+	if l.CornerRadius > math.Min(l.Width, l.Depth)/2 {
+		return false
+	}
+	// Distance from the top face's corner to the grid's corner along each axis
+	inset := l.topInset()
+	dx, dy := l.OffsetX-inset, l.OffsetY-inset
+	if dx < 0 || dy < 0 {
+		return false
+	}
+	radius := math.Max(0, l.CornerRadius-inset)
+	if dx >= radius || dy >= radius {
+		return true
+	}
+	return math.Hypot(radius-dx, radius-dy) <= radius
+}
@@ -0,0 +1,220 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestParseBaseStyle verifies base style name parsing
+func TestParseBaseStyle(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    BaseStyle
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultBaseStyle, false},
+		{"flat", "flat", BaseFlat, false},
+		{"sloped", "Sloped", BaseSloped, false},
+		{"unknown", "round", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBaseStyle(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBaseStyle(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBaseStyle(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLayoutCreateBase verifies the base spans the layout dimensions
+func TestLayoutCreateBase(t *testing.T) {
+	layout, err := NewLayout(Config{BaseWidth: 100, BaseDepth: 30, BaseHeight: 6}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	triangles, err := layout.CreateBase()
+	if err != nil {
+		t.Fatalf("CreateBase() error = %v", err)
+	}
+	if len(triangles) != 12 {
+		t.Fatalf("CreateBase() returned %d triangles, want 12", len(triangles))
+	}
+
+	minZ, maxX, maxY := math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, tri := range triangles {
+		for _, v := range []struct{ X, Y, Z float64 }{tri.V1, tri.V2, tri.V3} {
+			minZ = math.Min(minZ, v.Z)
+			maxX = math.Max(maxX, v.X)
+			maxY = math.Max(maxY, v.Y)
+		}
+	}
+	if minZ != -6 || maxX != 100 || maxY != 30 {
+		t.Errorf("base extents = (%v, %v, %v), want (100, 30, -6)", maxX, maxY, minZ)
+	}
+}
+
+// TestLayoutCreateSlopedBase verifies a sloped base keeps its footprint and insets its top
+func TestLayoutCreateSlopedBase(t *testing.T) {
+	layout, err := NewLayout(Config{BaseStyle: BaseSloped}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if layout.BaseInset <= 0 || layout.BaseInset > layout.OffsetX || layout.BaseInset > layout.OffsetY {
+		t.Fatalf("base inset = %v, want positive and clear of the grid", layout.BaseInset)
+	}
+
+	triangles, err := layout.CreateBase()
+	if err != nil {
+		t.Fatalf("CreateBase() error = %v", err)
+	}
+	if len(triangles) != 12 {
+		t.Fatalf("CreateBase() returned %d triangles, want 12", len(triangles))
+	}
+
+	center := types.Point3D{X: layout.Width / 2, Y: layout.Depth / 2, Z: -layout.Height / 2}
+	for i, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Z == 0 && (v.X < layout.BaseInset-epsilon || v.Y < layout.BaseInset-epsilon) {
+				t.Errorf("top vertex %v is not inset by %v", v, layout.BaseInset)
+			}
+		}
+		// Every face of a convex solid points away from its center
+		centroid := types.Point3D{
+			X: (tri.V1.X + tri.V2.X + tri.V3.X) / 3,
+			Y: (tri.V1.Y + tri.V2.Y + tri.V3.Y) / 3,
+			Z: (tri.V1.Z + tri.V2.Z + tri.V3.Z) / 3,
+		}
+		out := vectorSubtract(centroid, center)
+		if out.X*tri.Normal.X+out.Y*tri.Normal.Y+out.Z*tri.Normal.Z <= 0 {
+			t.Errorf("triangle %d normal %v points inward", i, tri.Normal)
+		}
+	}
+
+	if want := layout.BaseInset / layout.Height; math.Abs(layout.FrontSlope()-want) > epsilon {
+		t.Errorf("FrontSlope() = %v, want %v", layout.FrontSlope(), want)
+	}
+}
+
+// TestLayoutCreateTextSloped verifies embossed text follows a sloped front face
+func TestLayoutCreateTextSloped(t *testing.T) {
+	layout, err := NewLayout(Config{BaseStyle: BaseSloped}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	triangles, err := layout.CreateText("mona", "2024")
+	if err != nil {
+		t.Fatalf("CreateText() error = %v", err)
+	}
+	if len(triangles) == 0 {
+		t.Fatal("CreateText() returned no triangles")
+	}
+
+	// The text leans back with the face: its highest voxels sit further back than its lowest
+	lowest, highest := triangles[0].V1, triangles[0].V1
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Z < lowest.Z || (v.Z == lowest.Z && v.Y < lowest.Y) {
+				lowest = v
+			}
+			if v.Z > highest.Z || (v.Z == highest.Z && v.Y < highest.Y) {
+				highest = v
+			}
+		}
+	}
+	wantShift := layout.FrontSlope() * (highest.Z - lowest.Z)
+	if shift := highest.Y - lowest.Y; shift < wantShift/2 {
+		t.Errorf("text front moved back %v between its lowest and highest points, want about %v", shift, wantShift)
+	}
+
+	flat, err := NewLayout(DefaultConfig(), 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if flat.FrontSlope() != 0 || flat.BaseInset != 0 {
+		t.Errorf("flat base has slope %v and inset %v, want 0", flat.FrontSlope(), flat.BaseInset)
+	}
+}
+
+// TestLayoutCreateRoundedBase verifies rounded corners on flat and sloped bases
+func TestLayoutCreateRoundedBase(t *testing.T) {
+	for _, style := range []BaseStyle{BaseFlat, BaseSloped} {
+		t.Run(string(style), func(t *testing.T) {
+			layout, err := NewLayout(Config{BaseStyle: style, CornerRadius: 4}, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			triangles, err := layout.CreateBase()
+			if err != nil {
+				t.Fatalf("CreateBase() error = %v", err)
+			}
+			if !isClosedMesh(triangles) {
+				t.Error("CreateBase() mesh is not closed")
+			}
+			square := layout.Width * layout.Depth * layout.Height
+			if v := meshVolume(triangles); v <= 0 || v >= square {
+				t.Errorf("CreateBase() volume = %v, want positive and below the square base's %v", v, square)
+			}
+		})
+	}
+}
+
+// TestCornerRadiusClearsGrid verifies radii that would undercut the towers are rejected
+func TestCornerRadiusClearsGrid(t *testing.T) {
+	if _, err := NewLayout(Config{CornerRadius: 2 * gridPadding * CellSize}, 1); err != nil {
+		t.Errorf("NewLayout() error = %v for a radius that clears the grid", err)
+	}
+	if _, err := NewLayout(Config{CornerRadius: 10 * gridPadding * CellSize}, 1); err == nil {
+		t.Error("NewLayout() expected error for a radius that cuts into the grid")
+	}
+}
+
+// TestLayoutCreateChamferedBase verifies chamfered bases are closed and bevel both edges
+func TestLayoutCreateChamferedBase(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"flat", Config{Chamfer: 0.6}},
+		{"rounded", Config{Chamfer: 0.6, CornerRadius: 3}},
+		{"sloped", Config{Chamfer: 0.6, BaseStyle: BaseSloped}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.cfg, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			triangles, err := layout.CreateBase()
+			if err != nil {
+				t.Fatalf("CreateBase() error = %v", err)
+			}
+			if !isClosedMesh(triangles) {
+				t.Error("CreateBase() mesh is not closed")
+			}
+
+			// The outermost points of the top and bottom faces are pulled in by the chamfer
+			for _, tri := range triangles {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					onEdge := v.Z == 0 || v.Z == -layout.Height
+					if onEdge && (v.X < layout.Chamfer-epsilon || v.Y < layout.Chamfer-epsilon) {
+						t.Fatalf("vertex %v on a chamfered edge is not inset by %v", v, layout.Chamfer)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := NewLayout(Config{Chamfer: BaseHeight / 2}, 1); err == nil {
+		t.Error("NewLayout() expected error for a chamfer that consumes the base")
+	}
+}
@@ -0,0 +1,488 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/qr"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// gridPadding is the number of cells of padding on each side of the contribution grid.
+const gridPadding = 2
+
+// Config holds the user adjustable measurements of the model. All measurements
+// are in millimeters. Zero values select the defaults, so the zero Config
+// describes the standard model.
+type Config struct {
+	BaseWidth      float64       // Width of the base (X), derived from the contribution grid when zero
+	BaseDepth      float64       // Depth of the base (Y), derived from the contribution grid when zero
+	BaseHeight     float64       // Height of the base slab (Z), BaseHeight or as thick as the QR code needs when zero
+	BaseStyle      BaseStyle     // Shape of the base, DefaultBaseStyle when empty
+	Arrangement    Arrangement   // Layout of the towers on the base, DefaultArrangement when empty
+	CornerRadius   float64       // Radius of the base's vertical corners, zero for square corners
+	Chamfer        float64       // Size of the bevel along the top and bottom edges of the base, zero for none
+	Hollow         float64       // Wall thickness of a hollow base, zero for a solid base
+	DrainHole      float64       // Diameter of the drain holes under a hollow base's cavity, zero for none
+	TextStyle      TextStyle     // Construction of the username and year, DefaultTextStyle when empty
+	FaceResolution int           // Voxels across the face that voxel text and statistics are drawn with, DefaultFaceResolution when zero
+	OmitText       bool          // Leave the username and year off the front face
+	OmitLogo       bool          // Leave the GitHub logo off the front face
+	LogoFile       string        // SVG file embossed in place of the GitHub logo, empty for the GitHub logo
+	QRCode         string        // Text, usually a URL, of a QR code embossed on the back face, empty for none
+	Stats          bool          // Emboss contribution statistics on the back face
+	Avatar         bool          // Stand a lithophane of the user's avatar along the back edge
+	MonthLabels    MonthLabels   // Placement of the month labels along the front, DefaultMonthLabels when empty
+	Stack          bool          // Raise each year behind the front-most on a tier above the one in front
+	YearLabels     bool          // Emboss each year's number on the top face to the right of its towers
+	YearDividers   bool          // Stand a low ridge between neighboring years
+	Mold           bool          // Generate a mold for casting the base and towers instead of the skyline
+	SmoothSurface  bool          // Build each year as a continuous surface over its contributions instead of towers
+	Weekdays       bool          // Give each year rows for the five weekdays alone, for contributions without weekends
+	Weeks          int           // Columns of weeks across the grid, GridSize when zero
+	Unit           types.Unit    // Unit the dimensions in messages are given in, millimeters when empty
+	FutureDays     FutureDays    // How the days still to come are shown, DefaultFutureDays when empty
+	TowerShape     TowerShape    // Cross-section of the towers, DefaultTowerShape when empty
+	TowerSegments  int           // Sides of a cylinder tower, DefaultTowerSegments when zero
+	TowerTop       TowerTop      // Shape of the top of each tower, DefaultTowerTop when empty
+	CellSize       float64       // Footprint of a single day's tower, derived from the base when zero
+	Gap            float64       // Spacing between neighboring towers, zero for a fused grid
+	MinHeight      float64       // Height of a tower with a single contribution, derived from the cell size when zero
+	MaxHeight      float64       // Height of the tallest tower, derived from the cell size when zero
+	Scale          Scale         // Mapping of contribution counts to tower heights, DefaultScale when empty
+	Normalize      Normalization // Busiest day each year's towers are scaled against, DefaultNormalization when empty
+}
+
+// DefaultConfig returns the configuration of the standard model.
+func DefaultConfig() Config {
+	return Config{BaseHeight: BaseHeight, BaseStyle: DefaultBaseStyle, TextStyle: DefaultTextStyle, Scale: DefaultScale}
+}
+
+// Validate checks that the configured measurements are usable.
+func (c Config) Validate() error {
+	for _, dim := range []struct {
+		name  string
+		value float64
+	}{
+		{"base width", c.BaseWidth},
+		{"base depth", c.BaseDepth},
+		{"base thickness", c.BaseHeight},
+		{"cell size", c.CellSize},
+		{"gap", c.Gap},
+		{"corner radius", c.CornerRadius},
+		{"chamfer", c.Chamfer},
+		{"wall thickness", c.Hollow},
+		{"drain hole", c.DrainHole},
+		{"min height", c.MinHeight},
+		{"max height", c.MaxHeight},
+	} {
+		if math.IsNaN(dim.value) || math.IsInf(dim.value, 0) || dim.value < 0 {
+			return errors.New(errors.ValidationError, fmt.Sprintf("%s cannot be negative", dim.name), nil)
+		}
+	}
+	if c.LogoFile != "" {
+		if c.OmitLogo {
+			return errors.New(errors.ValidationError, "a custom logo cannot be combined with omitting the logo", nil)
+		}
+		if !strings.EqualFold(filepath.Ext(c.LogoFile), ".svg") {
+			return errors.New(errors.ValidationError, fmt.Sprintf("logo %q must be an SVG file", c.LogoFile), nil)
+		}
+	}
+	if c.QRCode != "" {
+		if _, err := qr.Encode(c.QRCode); err != nil {
+			return err
+		}
+	}
+	if c.MinHeight > 0 && c.MaxHeight > 0 && c.MinHeight > c.MaxHeight {
+		return errors.New(errors.ValidationError, "min height cannot be greater than max height", nil)
+	}
+	if c.BaseStyle != "" {
+		if _, err := ParseBaseStyle(string(c.BaseStyle)); err != nil {
+			return err
+		}
+	}
+	if c.TextStyle != "" {
+		if _, err := ParseTextStyle(string(c.TextStyle)); err != nil {
+			return err
+		}
+	}
+	if c.Scale != "" {
+		if _, err := ParseScale(string(c.Scale)); err != nil {
+			return err
+		}
+	}
+	if c.Normalize != "" {
+		if _, err := ParseNormalization(string(c.Normalize)); err != nil {
+			return err
+		}
+	}
+	if c.FutureDays != "" {
+		if _, err := ParseFutureDays(string(c.FutureDays)); err != nil {
+			return err
+		}
+	}
+	if c.Weeks < 0 || c.Weeks > GridSize {
+		return errors.New(errors.ValidationError, fmt.Sprintf("weeks cannot be more than %d", GridSize), nil)
+	}
+	if c.Stack && c.Avatar {
+		return errors.New(errors.ValidationError, "stacked years cannot be combined with an avatar panel, which stands where the top tier is", nil)
+	}
+	if c.Stack && c.YearDividers {
+		return errors.New(errors.ValidationError, "year dividers cannot be combined with stacked years, whose tiers already divide the years", nil)
+	}
+	if c.MonthLabels != "" {
+		if _, err := ParseMonthLabels(string(c.MonthLabels)); err != nil {
+			return err
+		}
+	}
+	if c.TowerShape != "" {
+		if _, err := ParseTowerShape(string(c.TowerShape)); err != nil {
+			return err
+		}
+	}
+	if c.TowerTop != "" {
+		if _, err := ParseTowerTop(string(c.TowerTop)); err != nil {
+			return err
+		}
+	}
+	if err := validateFaceResolution(c.FaceResolution); err != nil {
+		return err
+	}
+	if err := validateTowerSegments(c.TowerSegments); err != nil {
+		return err
+	}
+	if c.SmoothSurface {
+		if err := c.validateSmoothSurfaceConfig(); err != nil {
+			return err
+		}
+	}
+	if c.Mold {
+		if err := c.validateMoldConfig(); err != nil {
+			return err
+		}
+	}
+	if c.Arrangement != "" {
+		arrangement, err := ParseArrangement(string(c.Arrangement))
+		if err != nil {
+			return err
+		}
+		if arrangement.round() != nil {
+			return c.validateRoundConfig(arrangement)
+		}
+	}
+	return nil
+}
+
+// Layout holds the resolved measurements used to place geometry on the base.
+// Towers are placed on a grid of square cells centered on the base, with one
+// row per day of the week for each year, most recent year at the front, or
+// around the center of a round base as described by the Arrangement.
+type Layout struct {
+	Width  float64 // Width of the base
+	Depth  float64 // Depth of the base
+	Height float64 // Height of the base slab
+
+	BaseStyle BaseStyle // Shape of the base
+	BaseInset float64   // Inset of the top edges of the base from its bottom edges
+
+	Arrangement Arrangement // Layout of the towers on the base
+	YearCount   int         // Number of years of contributions
+	DaysPerWeek int         // Rows of days of each year, five when weekends are left out, seven when zero
+	Weeks       int         // Columns of weeks across the grid, GridSize when zero
+	Unit        types.Unit  // Unit the dimensions in messages are given in, millimeters when empty
+	HubRadius   float64     // Radius of the free center of a round base, zero for the grid
+
+	CornerRadius   float64     // Radius of the base's vertical corners at its bottom
+	Chamfer        float64     // Size of the bevel along the top and bottom edges of the base
+	Hollow         float64     // Wall thickness of a hollow base, zero for a solid base
+	DrainHole      float64     // Diameter of the drain holes under the cavity, zero for none
+	Emboss         Emboss      // Features embossed on the front face of the base
+	TextStyle      TextStyle   // Construction of the embossed username and year
+	FaceResolution int         // Voxels across the face that voxel text and statistics are drawn with
+	LogoFile       string      // SVG file embossed in place of the GitHub logo, empty for the GitHub logo
+	QRCode         string      // Text of the QR code embossed on the back face, empty for none
+	Stats          bool        // Contribution statistics embossed on the back face
+	Avatar         bool        // Lithophane of the user's avatar standing along the back edge
+	MonthLabels    MonthLabels // Placement of the month labels along the front
+	YearLabels     bool        // Year numbers embossed to the right of each year's towers
+	YearDividers   bool        // Ridges standing between neighboring years
+	Mold           bool        // Mold for casting the base and towers in place of the skyline
+	SmoothSurface  bool        // Continuous surface over each year's contributions in place of its towers
+
+	TowerShape    TowerShape // Cross-section of the towers
+	TowerSegments int        // Sides of a cylinder tower
+	TowerTop      TowerTop   // Shape of the top of each tower
+
+	CellSize    float64 // Footprint of a single day's tower
+	Gap         float64 // Spacing between neighboring towers
+	OffsetX     float64 // X position of the first week, or the padding around the towers on a round base
+	OffsetY     float64 // Y position of the first day of the front-most year, or the padding on a round base
+	YearSpacing float64 // Depth taken by each year
+	TierHeight  float64 // Rise of each tier of stacked years, zero when the years are not stacked
+
+	MinHeight float64       // Height of a tower with the fewest contributions
+	MaxHeight float64       // Height of a tower with the most contributions
+	Scale     Scale         // Mapping of contribution counts to tower heights
+	Normalize Normalization // Busiest day each year's towers are scaled against
+
+	FutureDays FutureDays // How the days still to come are shown
+}
+
+// NewLayout resolves cfg into a layout for a model covering yearCount years.
+//
+// The cell size is taken from cfg when given, and the base is sized to fit the
+// contribution grid and its padding unless its width or depth are also given.
+// Otherwise, when the base width or depth is given, the cell size is chosen so
+// that the grid fits inside the base. Tower heights are scaled with the cell
+// size to keep the model's proportions. An explicit
+// maximum height overrides the scaled one, with the minimum height following
+// it proportionally unless it is also given. Round arrangements size a round
+// base in the same way, from the larger of the base width and depth.
+func NewLayout(cfg Config, yearCount int) (Layout, error) {
+	if yearCount <= 0 {
+		return Layout{}, errors.New(errors.ValidationError, "year count must be positive", nil)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Layout{}, err
+	}
+
+	arrangement := cfg.Arrangement
+	if arrangement == "" {
+		arrangement = DefaultArrangement
+	}
+	round := arrangement.round()
+
+	daysPerWeek := 7
+	if cfg.Weekdays {
+		daysPerWeek = 5
+	}
+	weeks := GridSize
+	if cfg.Weeks > 0 {
+		weeks = cfg.Weeks
+	}
+	gridCellsX := float64(weeks)
+	gridCellsY := float64(daysPerWeek * yearCount)
+	gap := cfg.Gap
+
+	// Dividers stand in an extra gap between neighboring years
+	dividers := 0.0
+	if cfg.YearDividers {
+		dividers = yearDividerWidth + gap
+	}
+	gridDepth := func(cell float64) float64 {
+		return gridSpan(gridCellsY, cell, gap) + float64(yearCount-1)*dividers
+	}
+
+	cell := CellSize
+	switch {
+	case round != nil:
+		var err error
+		if cell, err = roundCellSize(round, cfg, yearCount); err != nil {
+			return Layout{}, err
+		}
+	case cfg.CellSize > 0:
+		cell = cfg.CellSize
+		if need := gridSpan(gridCellsX, cell, gap); cfg.BaseWidth > 0 && cfg.BaseWidth < need {
+			return Layout{}, errors.New(errors.ValidationError, fmt.Sprintf("base width of %s is too narrow for %s towers (at least %s needed)", cfg.Unit.Format(cfg.BaseWidth), cfg.Unit.Format(cell), cfg.Unit.Format(need)), nil)
+		}
+		if need := gridDepth(cell); cfg.BaseDepth > 0 && cfg.BaseDepth < need {
+			return Layout{}, errors.New(errors.ValidationError, fmt.Sprintf("base depth of %s is too shallow for %s towers (at least %s needed)", cfg.Unit.Format(cfg.BaseDepth), cfg.Unit.Format(cell), cfg.Unit.Format(need)), nil)
+		}
+	case cfg.BaseWidth > 0 || cfg.BaseDepth > 0:
+		// Solve base = cells*cell + (cells-1)*gap + 2*padding*cell for cell
+		cell = math.Inf(1)
+		if cfg.BaseWidth > 0 {
+			cell = math.Min(cell, (cfg.BaseWidth-(gridCellsX-1)*gap)/(gridCellsX+2*gridPadding))
+		}
+		if cfg.BaseDepth > 0 {
+			cell = math.Min(cell, (cfg.BaseDepth-(gridCellsY-1)*gap-float64(yearCount-1)*dividers)/(gridCellsY+2*gridPadding))
+		}
+		if cell <= 0 {
+			return Layout{}, errors.New(errors.ValidationError, fmt.Sprintf("gap of %s leaves no room for towers on the base", cfg.Unit.Format(gap)), nil)
+		}
+	}
+
+	layout := Layout{
+		Width:          cfg.BaseWidth,
+		Depth:          cfg.BaseDepth,
+		Height:         cfg.BaseHeight,
+		BaseStyle:      cfg.BaseStyle,
+		Arrangement:    arrangement,
+		YearCount:      yearCount,
+		DaysPerWeek:    daysPerWeek,
+		Weeks:          weeks,
+		Unit:           cfg.Unit,
+		CornerRadius:   cfg.CornerRadius,
+		Chamfer:        cfg.Chamfer,
+		Hollow:         cfg.Hollow,
+		DrainHole:      cfg.DrainHole,
+		Emboss:         Emboss{Text: !cfg.OmitText, Logo: !cfg.OmitLogo},
+		TextStyle:      cfg.TextStyle,
+		FaceResolution: cfg.FaceResolution,
+		LogoFile:       cfg.LogoFile,
+		QRCode:         cfg.QRCode,
+		Stats:          cfg.Stats,
+		Avatar:         cfg.Avatar,
+		MonthLabels:    cfg.MonthLabels,
+		YearLabels:     cfg.YearLabels,
+		YearDividers:   cfg.YearDividers,
+		Mold:           cfg.Mold,
+		SmoothSurface:  cfg.SmoothSurface,
+		TowerShape:     cfg.TowerShape,
+		TowerSegments:  cfg.TowerSegments,
+		TowerTop:       cfg.TowerTop,
+		CellSize:       cell,
+		Gap:            gap,
+		YearSpacing:    float64(daysPerWeek)*(cell+gap) + dividers,
+		MinHeight:      MinHeight * cell / CellSize,
+		MaxHeight:      MaxHeight * cell / CellSize,
+		Scale:          cfg.Scale,
+		Normalize:      cfg.Normalize,
+		FutureDays:     cfg.FutureDays,
+	}
+	if round != nil {
+		// A round base is as wide as it is deep, with corners rounded into a circle
+		diameter := math.Max(layout.Width, layout.Depth)
+		if diameter == 0 {
+			diameter = 2 * (roundSpan(round, yearCount, cell, gap) + gridPadding*cell)
+		}
+		layout.Width, layout.Depth, layout.CornerRadius = diameter, diameter, diameter/2
+		layout.HubRadius = round.hub() * (cell + gap)
+	}
+	if layout.Width == 0 {
+		layout.Width = gridSpan(gridCellsX, cell, gap) + 2*gridPadding*cell
+	}
+	if layout.Depth == 0 {
+		layout.Depth = gridDepth(cell) + 2*gridPadding*cell
+	}
+	if layout.Height == 0 {
+		layout.Height = BaseHeight
+		if cfg.QRCode != "" {
+			// Only a base left at its default thickness is thickened for the QR code
+			need, err := qrThickness(cfg.QRCode, cfg.Chamfer)
+			if err != nil {
+				return Layout{}, err
+			}
+			layout.Height = math.Max(layout.Height, need)
+		}
+	}
+	if cfg.MaxHeight > 0 {
+		// Keep the ratio between the shortest and tallest towers when clamping
+		layout.MinHeight *= cfg.MaxHeight / layout.MaxHeight
+		layout.MaxHeight = cfg.MaxHeight
+	}
+	if cfg.MinHeight > 0 {
+		if cfg.MinHeight > layout.MaxHeight {
+			return Layout{}, errors.New(errors.ValidationError, fmt.Sprintf("min height %s cannot be greater than the max height of %s", cfg.Unit.Format(cfg.MinHeight), cfg.Unit.Format(layout.MaxHeight)), nil)
+		}
+		layout.MinHeight = cfg.MinHeight
+	}
+	if layout.Scale == "" {
+		layout.Scale = DefaultScale
+	}
+	if layout.Normalize == "" {
+		layout.Normalize = DefaultNormalization
+	}
+	if layout.FutureDays == "" {
+		layout.FutureDays = DefaultFutureDays
+	}
+	if layout.BaseStyle == "" {
+		layout.BaseStyle = DefaultBaseStyle
+	}
+	if layout.TextStyle == "" {
+		layout.TextStyle = DefaultTextStyle
+	}
+	if layout.FaceResolution == 0 {
+		layout.FaceResolution = DefaultFaceResolution
+	}
+	if layout.MonthLabels == "" {
+		layout.MonthLabels = DefaultMonthLabels
+	}
+	if layout.TowerShape == "" {
+		layout.TowerShape = DefaultTowerShape
+	}
+	if layout.TowerTop == "" {
+		layout.TowerTop = DefaultTowerTop
+	}
+	if layout.TowerSegments == 0 {
+		layout.TowerSegments = DefaultTowerSegments
+	}
+
+	// Center the grid on the base
+	layout.OffsetX = (layout.Width - gridSpan(gridCellsX, cell, gap)) / 2
+	layout.OffsetY = (layout.Depth - gridDepth(cell)) / 2
+	if round != nil {
+		layout.OffsetX = layout.Width/2 - roundSpan(round, yearCount, cell, gap)
+		layout.OffsetY = layout.OffsetX
+	}
+	if cfg.Mold {
+		// Embossing on the faces of the base would lock the cast in the mold
+		layout.Emboss = Emboss{}
+	}
+	if cfg.Stack {
+		layout.TierHeight = stackTierShare * layout.Height
+	}
+	layout.BaseInset = baseInset(layout.BaseStyle, cell, layout.OffsetX, layout.OffsetY)
+	if 2*layout.Chamfer >= layout.Height {
+		return Layout{}, errors.New(errors.ValidationError, fmt.Sprintf("chamfer of %s does not fit on a %s thick base", cfg.Unit.Format(layout.Chamfer), cfg.Unit.Format(layout.Height)), nil)
+	}
+	if !layout.cornersClearGrid() {
+		return Layout{}, errors.New(errors.ValidationError, "base corners and edges would cut into the contribution grid", nil)
+	}
+	if err := layout.validateHollow(); err != nil {
+		return Layout{}, err
+	}
+	if err := layout.validateQRCode(); err != nil {
+		return Layout{}, err
+	}
+	if err := layout.validateAvatar(); err != nil {
+		return Layout{}, err
+	}
+	if err := layout.validateMonthLabels(); err != nil {
+		return Layout{}, err
+	}
+	if err := layout.validateYearLabels(); err != nil {
+		return Layout{}, err
+	}
+
+	return layout, nil
+}
+
+// daysPerWeek returns the rows of days of each year.
+func (l Layout) daysPerWeek() int {
+	if l.DaysPerWeek == 0 {
+		return 7
+	}
+	return l.DaysPerWeek
+}
+
+// weeks returns the columns of weeks across the grid.
+func (l Layout) weeks() int {
+	if l.Weeks == 0 {
+		return GridSize
+	}
+	return l.Weeks
+}
+
+// gridSpan returns the length of a row of cells separated by gaps.
+func gridSpan(cells, cell, gap float64) float64 {
+	return cells*cell + (cells-1)*gap
+}
+
+// TowerHeight converts a contribution count to the height of its tower.
+// Returns 0 for no contributions, or a value between MinHeight and MaxHeight for active contributions.
+func (l Layout) TowerHeight(count, maxCount int) float64 {
+	return normalizeContribution(count, maxCount, l.MinHeight, l.MaxHeight, l.Scale)
+}
+
+// TowerPosition returns the front left corner of the tower for a given week and
+// day in the grid arrangement.
+func (l Layout) TowerPosition(yearIndex, weekIdx, dayIdx int) (x, y float64) {
+	pitch := l.CellSize + l.Gap
+	x = l.OffsetX + float64(weekIdx)*pitch
+	y = l.OffsetY + float64(yearIndex)*l.YearSpacing + float64(dayIdx)*pitch
+	return x, y
+}
@@ -0,0 +1,291 @@
+package geometry
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestConfigValidate verifies rejection of unusable measurements
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"zero config", Config{}, false},
+		{"default config", DefaultConfig(), false},
+		{"explicit dimensions", Config{BaseWidth: 200, BaseDepth: 40, BaseHeight: 5}, false},
+		{"negative width", Config{BaseWidth: -1}, true},
+		{"negative height", Config{BaseHeight: -5}, true},
+		{"NaN depth", Config{BaseDepth: math.NaN()}, true},
+		{"infinite width", Config{BaseWidth: math.Inf(1)}, true},
+		{"log scale", Config{Scale: ScaleLog}, false},
+		{"negative max height", Config{MaxHeight: -1}, true},
+		{"negative cell size", Config{CellSize: -2}, true},
+		{"negative gap", Config{Gap: -0.5}, true},
+		{"negative corner radius", Config{CornerRadius: -1}, true},
+		{"negative chamfer", Config{Chamfer: -1}, true},
+		{"negative min height", Config{MinHeight: -1}, true},
+		{"min above max", Config{MinHeight: 12, MaxHeight: 10}, true},
+		{"min equal to max", Config{MinHeight: 10, MaxHeight: 10}, false},
+		{"unknown scale", Config{Scale: "cubic"}, true},
+		{"negative wall thickness", Config{Hollow: -1}, true},
+		{"negative drain hole", Config{Hollow: 2, DrainHole: -1}, true},
+		{"unknown text style", Config{TextStyle: "bitmap"}, true},
+		{"svg logo", Config{LogoFile: "logo.SVG"}, false},
+		{"raster logo", Config{LogoFile: "logo.png"}, true},
+		{"omitted custom logo", Config{LogoFile: "logo.svg", OmitLogo: true}, true},
+		{"qr code", Config{QRCode: "https://github.com/octocat"}, false},
+		{"preview face resolution", Config{FaceResolution: PreviewFaceResolution}, false},
+		{"face resolution too low", Config{FaceResolution: 100}, true},
+		{"face resolution too high", Config{FaceResolution: 20000}, true},
+		{"qr code too long", Config{QRCode: strings.Repeat("x", 3000)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewLayoutDefaults verifies the default layout matches the standard model
+func TestNewLayoutDefaults(t *testing.T) {
+	layout, err := NewLayout(DefaultConfig(), 2)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	wantWidth, wantDepth := CalculateMultiYearDimensions(2)
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"width", layout.Width, wantWidth},
+		{"depth", layout.Depth, wantDepth},
+		{"height", layout.Height, BaseHeight},
+		{"cell size", layout.CellSize, CellSize},
+		{"offset x", layout.OffsetX, 2 * CellSize},
+		{"offset y", layout.OffsetY, 2 * CellSize},
+		{"year spacing", layout.YearSpacing, YearOffset},
+		{"min height", layout.MinHeight, MinHeight},
+		{"max height", layout.MaxHeight, MaxHeight},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > epsilon {
+			t.Errorf("layout %s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+// TestNewLayoutExplicitBase verifies the grid is scaled and centered on a custom base
+func TestNewLayoutExplicitBase(t *testing.T) {
+	// A 114.5mm wide base fits 57 cells of 2mm, while the 60mm depth has room to spare
+	layout, err := NewLayout(Config{BaseWidth: 114, BaseDepth: 60, BaseHeight: 4}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	if math.Abs(layout.CellSize-2) > epsilon {
+		t.Errorf("cell size = %v, want 2 (limited by width)", layout.CellSize)
+	}
+	if layout.Width != 114 || layout.Depth != 60 || layout.Height != 4 {
+		t.Errorf("base = %vx%vx%v, want 114x60x4", layout.Width, layout.Depth, layout.Height)
+	}
+	if math.Abs(layout.OffsetY-(60-14)/2) > epsilon {
+		t.Errorf("offset y = %v, want grid centered in depth", layout.OffsetY)
+	}
+	if math.Abs(layout.MaxHeight-MaxHeight*0.8) > epsilon {
+		t.Errorf("max height = %v, want scaled with cell size", layout.MaxHeight)
+	}
+
+	x, y := layout.TowerPosition(0, GridSize-1, 6)
+	if x+layout.CellSize > layout.Width-layout.OffsetX+epsilon || y+layout.CellSize > layout.Depth {
+		t.Errorf("last tower at (%v, %v) does not fit on the base", x, y)
+	}
+}
+
+// TestNewLayoutMaxHeight verifies an explicit maximum height clamps the towers proportionally
+func TestNewLayoutMaxHeight(t *testing.T) {
+	layout, err := NewLayout(Config{BaseWidth: 114, MaxHeight: 10}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	if layout.MaxHeight != 10 {
+		t.Errorf("max height = %v, want 10", layout.MaxHeight)
+	}
+	if want := MinHeight / MaxHeight * 10; math.Abs(layout.MinHeight-want) > epsilon {
+		t.Errorf("min height = %v, want %v", layout.MinHeight, want)
+	}
+	if got := layout.TowerHeight(1000, 100); math.Abs(got-10) > epsilon {
+		t.Errorf("TowerHeight() for an outlier = %v, want 10", got)
+	}
+}
+
+// TestNewLayoutMinHeight verifies days with contributions reach the minimum height
+func TestNewLayoutMinHeight(t *testing.T) {
+	layout, err := NewLayout(Config{MinHeight: 6}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	if got := layout.TowerHeight(1, 1000); got < 6 {
+		t.Errorf("TowerHeight() for a single contribution = %v, want at least 6", got)
+	}
+	if got := layout.TowerHeight(0, 1000); got != 0 {
+		t.Errorf("TowerHeight() for no contributions = %v, want 0", got)
+	}
+	if got := layout.TowerHeight(1000, 1000); math.Abs(got-MaxHeight) > epsilon {
+		t.Errorf("TowerHeight() for the busiest day = %v, want %v", got, MaxHeight)
+	}
+
+	if _, err := NewLayout(Config{MinHeight: MaxHeight + 1}, 1); err == nil {
+		t.Error("NewLayout() expected error for min height above the default max height")
+	}
+}
+
+// TestNewLayoutCellSize verifies an explicit footprint sizes the base to fit the grid
+func TestNewLayoutCellSize(t *testing.T) {
+	layout, err := NewLayout(Config{CellSize: 2}, 2)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if want := float64(GridSize+2*gridPadding) * 2; math.Abs(layout.Width-want) > epsilon {
+		t.Errorf("width = %v, want %v", layout.Width, want)
+	}
+	if want := float64(14+2*gridPadding) * 2; math.Abs(layout.Depth-want) > epsilon {
+		t.Errorf("depth = %v, want %v", layout.Depth, want)
+	}
+	if want := MaxHeight * 2 / CellSize; math.Abs(layout.MaxHeight-want) > epsilon {
+		t.Errorf("max height = %v, want %v", layout.MaxHeight, want)
+	}
+
+	// A base wider than the grid keeps the footprint and centers the towers
+	layout, err = NewLayout(Config{CellSize: 2, BaseWidth: 200}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if layout.CellSize != 2 || math.Abs(layout.OffsetX-(200-float64(GridSize)*2)/2) > epsilon {
+		t.Errorf("cell size = %v, offset x = %v, want footprint kept and grid centered", layout.CellSize, layout.OffsetX)
+	}
+
+	if _, err := NewLayout(Config{CellSize: 3, BaseWidth: 100}, 1); err == nil {
+		t.Error("NewLayout() expected error when the grid does not fit the base")
+	}
+}
+
+// TestNewLayoutGap verifies gaps separate neighboring towers
+func TestNewLayoutGap(t *testing.T) {
+	layout, err := NewLayout(Config{CellSize: 2, Gap: 0.5}, 2)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	x0, y0 := layout.TowerPosition(0, 0, 0)
+	x1, y1 := layout.TowerPosition(0, 1, 1)
+	if math.Abs(x1-x0-2.5) > epsilon || math.Abs(y1-y0-2.5) > epsilon {
+		t.Errorf("neighboring towers at (%v, %v) and (%v, %v), want 2.5mm apart", x0, y0, x1, y1)
+	}
+	_, yNext := layout.TowerPosition(1, 0, 0)
+	_, yLast := layout.TowerPosition(0, 0, 6)
+	if math.Abs(yNext-yLast-2.5) > epsilon {
+		t.Errorf("first day of the next year is %vmm behind the last day, want 2.5", yNext-yLast)
+	}
+	xLast, _ := layout.TowerPosition(1, GridSize-1, 6)
+	if math.Abs(layout.Width-(xLast+layout.CellSize)-layout.OffsetX) > epsilon {
+		t.Errorf("grid is not centered: offset %v, right margin %v", layout.OffsetX, layout.Width-xLast-layout.CellSize)
+	}
+
+	// With a fixed base the footprint shrinks to make room for the gaps
+	fused, err := NewLayout(Config{BaseWidth: 150}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	spaced, err := NewLayout(Config{BaseWidth: 150, Gap: 0.5}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if spaced.CellSize >= fused.CellSize || spaced.Width != 150 {
+		t.Errorf("cell size with gap = %v, without = %v, want smaller on a 150mm base", spaced.CellSize, fused.CellSize)
+	}
+
+	if _, err := NewLayout(Config{BaseWidth: 20, Gap: 1}, 1); err == nil {
+		t.Error("NewLayout() expected error when gaps leave no room for towers")
+	}
+}
+
+// TestNewLayoutWeekdays verifies each year takes five rows when weekends are left out
+func TestNewLayoutWeekdays(t *testing.T) {
+	week, err := NewLayout(Config{CellSize: 2, Gap: 0.5}, 2)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	weekdays, err := NewLayout(Config{CellSize: 2, Gap: 0.5, Weekdays: true}, 2)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	if weekdays.DaysPerWeek != 5 || math.Abs(weekdays.YearSpacing-5*2.5) > epsilon {
+		t.Errorf("weekday layout has %d days per week %vmm apart, want 5 days 12.5mm apart", weekdays.DaysPerWeek, weekdays.YearSpacing)
+	}
+	if math.Abs(week.Depth-weekdays.Depth-2*2*2.5) > epsilon {
+		t.Errorf("weekday base is %vmm deep, want two rows a year shallower than %vmm", weekdays.Depth, week.Depth)
+	}
+	_, yNext := weekdays.TowerPosition(1, 0, 0)
+	_, yLast := weekdays.TowerPosition(0, 0, 4)
+	if math.Abs(yNext-yLast-2.5) > epsilon {
+		t.Errorf("first weekday of the next year is %vmm behind the last, want 2.5", yNext-yLast)
+	}
+
+	if err := (Config{Arrangement: ArrangementRadial, Weekdays: true}).Validate(); err == nil {
+		t.Error("Validate() expected error for weekdays only on a round base")
+	}
+}
+
+func TestNewLayoutWeeks(t *testing.T) {
+	year, err := NewLayout(Config{CellSize: 2, Gap: 0.5}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	short, err := NewLayout(Config{CellSize: 2, Gap: 0.5, Weeks: 40}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+
+	if short.Weeks != 40 || math.Abs(year.Width-short.Width-13*2.5) > epsilon {
+		t.Errorf("40 week base is %vmm wide, want 13 weeks narrower than %vmm", short.Width, year.Width)
+	}
+	if short.Depth != year.Depth {
+		t.Errorf("40 week base is %vmm deep, want the %vmm of a whole year", short.Depth, year.Depth)
+	}
+
+	for _, weeks := range []int{-1, GridSize + 1} {
+		if err := (Config{Weeks: weeks}).Validate(); err == nil {
+			t.Errorf("Validate() expected error for %d weeks", weeks)
+		}
+	}
+	if err := (Config{Arrangement: ArrangementRadial, Weeks: 40}).Validate(); err == nil {
+		t.Error("Validate() expected error for a grid cut short on a round base")
+	}
+}
+
+// TestNewLayoutErrors verifies invalid inputs are rejected
+func TestNewLayoutErrors(t *testing.T) {
+	if _, err := NewLayout(DefaultConfig(), 0); err == nil {
+		t.Error("NewLayout() expected error for zero years")
+	}
+	if _, err := NewLayout(Config{BaseWidth: -10}, 1); err == nil {
+		t.Error("NewLayout() expected error for negative width")
+	}
+	// Dimensions are given in the unit the user works in
+	want := "base width of 2in is too narrow for 0.1in towers (at least 5.3in needed)"
+	if _, err := NewLayout(Config{BaseWidth: 50.8, CellSize: 2.54, Unit: types.UnitInch}, 1); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("NewLayout() error = %v, want it to contain %q", err, want)
+	}
+}
@@ -0,0 +1,453 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// csgEpsilon is the distance within which a point counts as lying on a plane.
+const csgEpsilon = 1e-5
+
+// csgWeld is the distance within which sealCuts welds the corners of the
+// sealed mesh, far below what a printer resolves. Cuts place the points where
+// they split faces only to within csgEpsilon of the cutting plane, which puts
+// them up to a fraction of a millimeter off along a face nearly parallel to it.
+const csgWeld = 1e-3
+
+// Sides of a plane a point or polygon can lie on. A polygon with points on
+// both sides spans the plane.
+const (
+	csgCoplanar = 0
+	csgFront    = 1
+	csgBack     = 2
+	csgSpanning = csgFront | csgBack
+)
+
+// csgPlane is an oriented plane, holding the points p with normal·p = w.
+type csgPlane struct {
+	normal types.Point3D
+	w      float64
+}
+
+// flip turns the plane to face the other way.
+func (p csgPlane) flip() csgPlane {
+	return csgPlane{normal: types.Point3D{X: -p.normal.X, Y: -p.normal.Y, Z: -p.normal.Z}, w: -p.w}
+}
+
+// distance returns how far v lies in front of the plane.
+func (p csgPlane) distance(v types.Point3D) float64 {
+	return vectorDot(p.normal, v) - p.w
+}
+
+// csgPolygon is a convex, planar polygon, counter-clockwise seen from the
+// front of its plane.
+type csgPolygon struct {
+	vertices []types.Point3D
+	plane    csgPlane
+}
+
+// flip reverses the polygon to face the other way.
+func (p csgPolygon) flip() csgPolygon {
+	vertices := make([]types.Point3D, len(p.vertices))
+	for i, v := range p.vertices {
+		vertices[len(vertices)-1-i] = v
+	}
+	return csgPolygon{vertices: vertices, plane: p.plane.flip()}
+}
+
+// splitPolygon sorts polygon by which side of the plane it lies on, cutting
+// it in two when it spans the plane. Polygons in the plane go to coplanarFront
+// or coplanarBack by the way they face.
+func (p csgPlane) splitPolygon(polygon csgPolygon, coplanarFront, coplanarBack, front, back *[]csgPolygon) {
+	sides := make([]int, len(polygon.vertices))
+	polygonSide := csgCoplanar
+	for i, v := range polygon.vertices {
+		t := p.distance(v)
+		switch {
+		case t < -csgEpsilon:
+			sides[i] = csgBack
+		case t > csgEpsilon:
+			sides[i] = csgFront
+		}
+		polygonSide |= sides[i]
+	}
+
+	switch polygonSide {
+	case csgCoplanar:
+		if vectorDot(p.normal, polygon.plane.normal) > 0 {
+			*coplanarFront = append(*coplanarFront, polygon)
+		} else {
+			*coplanarBack = append(*coplanarBack, polygon)
+		}
+	case csgFront:
+		*front = append(*front, polygon)
+	case csgBack:
+		*back = append(*back, polygon)
+	default:
+		var f, b []types.Point3D
+		n := len(polygon.vertices)
+		for i := 0; i < n; i++ {
+			j := (i + 1) % n
+			si, sj := sides[i], sides[j]
+			vi, vj := polygon.vertices[i], polygon.vertices[j]
+			if si != csgBack {
+				f = append(f, vi)
+			}
+			if si != csgFront {
+				b = append(b, vi)
+			}
+			if si|sj == csgSpanning {
+				edge := vectorSubtract(vj, vi)
+				t := (p.w - vectorDot(p.normal, vi)) / vectorDot(p.normal, edge)
+				v := types.Point3D{X: vi.X + edge.X*t, Y: vi.Y + edge.Y*t, Z: vi.Z + edge.Z*t}
+				f = append(f, v)
+				b = append(b, v)
+			}
+		}
+		if len(f) >= 3 {
+			*front = append(*front, csgPolygon{vertices: f, plane: polygon.plane})
+		}
+		if len(b) >= 3 {
+			*back = append(*back, csgPolygon{vertices: b, plane: polygon.plane})
+		}
+	}
+}
+
+// csgNode is a node of a BSP tree, splitting space by the plane of its
+// polygons into the part in front and the part behind. A tree built from the
+// faces of a closed solid tells the inside of the solid from the outside.
+type csgNode struct {
+	plane       *csgPlane
+	front, back *csgNode
+	polygons    []csgPolygon
+}
+
+// newCSGNode builds a BSP tree from polygons.
+func newCSGNode(polygons []csgPolygon) *csgNode {
+	node := &csgNode{}
+	node.build(polygons)
+	return node
+}
+
+// invert turns the solid the tree describes inside out.
+func (n *csgNode) invert() {
+	for i := range n.polygons {
+		n.polygons[i] = n.polygons[i].flip()
+	}
+	if n.plane != nil {
+		flipped := n.plane.flip()
+		n.plane = &flipped
+	}
+	if n.front != nil {
+		n.front.invert()
+	}
+	if n.back != nil {
+		n.back.invert()
+	}
+	n.front, n.back = n.back, n.front
+}
+
+// clipPolygons returns the parts of polygons outside the solid the tree describes.
+func (n *csgNode) clipPolygons(polygons []csgPolygon) []csgPolygon {
+	if n.plane == nil {
+		return append([]csgPolygon(nil), polygons...)
+	}
+	var front, back []csgPolygon
+	for _, p := range polygons {
+		n.plane.splitPolygon(p, &front, &back, &front, &back)
+	}
+	if n.front != nil {
+		front = n.front.clipPolygons(front)
+	}
+	if n.back != nil {
+		back = n.back.clipPolygons(back)
+	} else {
+		back = nil
+	}
+	return append(front, back...)
+}
+
+// clipTo removes the parts of the tree's polygons inside the solid of other.
+func (n *csgNode) clipTo(other *csgNode) {
+	n.polygons = other.clipPolygons(n.polygons)
+	if n.front != nil {
+		n.front.clipTo(other)
+	}
+	if n.back != nil {
+		n.back.clipTo(other)
+	}
+}
+
+// allPolygons returns the polygons of the whole tree.
+func (n *csgNode) allPolygons() []csgPolygon {
+	polygons := append([]csgPolygon(nil), n.polygons...)
+	if n.front != nil {
+		polygons = append(polygons, n.front.allPolygons()...)
+	}
+	if n.back != nil {
+		polygons = append(polygons, n.back.allPolygons()...)
+	}
+	return polygons
+}
+
+// build adds polygons to the tree, splitting them by the planes on their way down.
+func (n *csgNode) build(polygons []csgPolygon) {
+	if len(polygons) == 0 {
+		return
+	}
+	if n.plane == nil {
+		plane := polygons[0].plane
+		n.plane = &plane
+	}
+	var front, back []csgPolygon
+	for _, p := range polygons {
+		n.plane.splitPolygon(p, &n.polygons, &n.polygons, &front, &back)
+	}
+	if len(front) > 0 {
+		if n.front == nil {
+			n.front = &csgNode{}
+		}
+		n.front.build(front)
+	}
+	if len(back) > 0 {
+		if n.back == nil {
+			n.back = &csgNode{}
+		}
+		n.back.build(back)
+	}
+}
+
+// subtractSolid returns the solid a with the solid b cut away. Both must be
+// closed meshes with outward facing triangles. Only the triangles of a near b
+// take part in the cut, so cutting many small solids out of a large one stays
+// fast. The result is closed, but cut faces may meet their neighbors partway
+// along an edge.
+func subtractSolid(a, b []types.Triangle) []types.Triangle {
+	bMin, bMax := triangleBounds(b)
+	var near, far []types.Triangle
+	for _, t := range a {
+		tMin, tMax := triangleBounds([]types.Triangle{t})
+		if tMax.X < bMin.X-csgEpsilon || tMin.X > bMax.X+csgEpsilon ||
+			tMax.Y < bMin.Y-csgEpsilon || tMin.Y > bMax.Y+csgEpsilon ||
+			tMax.Z < bMin.Z-csgEpsilon || tMin.Z > bMax.Z+csgEpsilon {
+			far = append(far, t)
+		} else {
+			near = append(near, t)
+		}
+	}
+	if len(near) == 0 {
+		// b is wholly inside or outside a, leaving a cavity or nothing to cut
+		if len(b) == 0 || !insideSolid(b[0].V1, a) {
+			return a
+		}
+		return append(append([]types.Triangle(nil), a...), csgTriangles(flipPolygons(csgPolygons(b)))...)
+	}
+
+	// a - b is the inverse of the union of the inverse of a with b
+	nodeA, nodeB := newCSGNode(csgPolygons(near)), newCSGNode(csgPolygons(b))
+	nodeA.invert()
+	nodeA.clipTo(nodeB)
+	nodeB.clipTo(nodeA)
+	nodeB.invert()
+	nodeB.clipTo(nodeA)
+	nodeB.invert()
+	nodeA.build(nodeB.allPolygons())
+	nodeA.invert()
+
+	return append(far, csgTriangles(nodeA.allPolygons())...)
+}
+
+// flipPolygons turns every polygon to face the other way.
+func flipPolygons(polygons []csgPolygon) []csgPolygon {
+	for i := range polygons {
+		polygons[i] = polygons[i].flip()
+	}
+	return polygons
+}
+
+// insideSolid reports whether p lies inside the closed mesh of triangles.
+func insideSolid(p types.Point3D, triangles []types.Triangle) bool {
+	mesh := types.NewMesh(triangles)
+	return mesh.Contains(p)
+}
+
+// csgPolygons converts triangles to polygons, leaving out those without area.
+func csgPolygons(triangles []types.Triangle) []csgPolygon {
+	polygons := make([]csgPolygon, 0, len(triangles))
+	for _, t := range triangles {
+		normal, err := calculateNormal(t.V1, t.V2, t.V3)
+		if err != nil {
+			continue
+		}
+		polygons = append(polygons, csgPolygon{
+			vertices: []types.Point3D{t.V1, t.V2, t.V3},
+			plane:    csgPlane{normal: normal, w: vectorDot(normal, t.V1)},
+		})
+	}
+	return polygons
+}
+
+// csgTriangles fans convex polygons into triangles facing along their planes,
+// leaving out slivers without area.
+func csgTriangles(polygons []csgPolygon) []types.Triangle {
+	var triangles []types.Triangle
+	for _, p := range polygons {
+		for i := 1; i+1 < len(p.vertices); i++ {
+			a, b, c := p.vertices[0], p.vertices[i], p.vertices[i+1]
+			if isZeroVector(vectorCross(vectorSubtract(b, a), vectorSubtract(c, a))) {
+				continue
+			}
+			triangles = append(triangles, types.Triangle{Normal: p.plane.normal, V1: a, V2: b, V3: c})
+		}
+	}
+	return triangles
+}
+
+// triangleBounds returns the corners of the box enclosing triangles.
+func triangleBounds(triangles []types.Triangle) (minPoint, maxPoint types.Point3D) {
+	minPoint = types.Point3D{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxPoint = types.Point3D{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, t := range triangles {
+		for _, v := range [3]types.Point3D{t.V1, t.V2, t.V3} {
+			minPoint = types.Point3D{X: math.Min(minPoint.X, v.X), Y: math.Min(minPoint.Y, v.Y), Z: math.Min(minPoint.Z, v.Z)}
+			maxPoint = types.Point3D{X: math.Max(maxPoint.X, v.X), Y: math.Max(maxPoint.Y, v.Y), Z: math.Max(maxPoint.Z, v.Z)}
+		}
+	}
+	return minPoint, maxPoint
+}
+
+// weldCorners moves each corner of triangles closer than csgWeld to one found
+// before it onto that one, leaving out the triangles two of whose corners
+// meet. Edges run as often one way as the other still are afterwards.
+func weldCorners(triangles []types.Triangle) []types.Triangle {
+	// Corners are kept in cells of a grid csgWeld apart, so those within
+	// csgWeld of a point are in its cell or the cells around it
+	type cell struct{ x, y, z int64 }
+	corners := make(map[cell][]types.Point3D)
+	weld := func(p types.Point3D) types.Point3D {
+		c := cell{int64(math.Floor(p.X / csgWeld)), int64(math.Floor(p.Y / csgWeld)), int64(math.Floor(p.Z / csgWeld))}
+		for dx := int64(-1); dx <= 1; dx++ {
+			for dy := int64(-1); dy <= 1; dy++ {
+				for dz := int64(-1); dz <= 1; dz++ {
+					for _, q := range corners[cell{c.x + dx, c.y + dy, c.z + dz}] {
+						if d := vectorSubtract(p, q); vectorDot(d, d) < csgWeld*csgWeld {
+							return q
+						}
+					}
+				}
+			}
+		}
+		corners[c] = append(corners[c], p)
+		return p
+	}
+
+	welded := make([]types.Triangle, 0, len(triangles))
+	for _, t := range triangles {
+		t.V1, t.V2, t.V3 = weld(t.V1), weld(t.V2), weld(t.V3)
+		if t.V1 == t.V2 || t.V2 == t.V3 || t.V3 == t.V1 {
+			continue
+		}
+		welded = append(welded, t)
+	}
+	return welded
+}
+
+// sealCuts joins up the faces left by cutting solids, so the mesh is closed
+// edge to edge. Corners closer than csgEpsilon/10 are welded, which leaves
+// some slivers without area to drop. Faces whose edges pass through the
+// corners of their neighbors, where a face meets two smaller ones along one
+// edge, are then split. The gaps such T-junctions leave are invisible, but
+// make the mesh look open to slicers. Each split face is fanned from its
+// center over its corners and the corners found along its edges. Edges run
+// as often one way as the other need no split, even where four faces meet
+// along them, as they do where two cuts touch only along an edge. Last, the
+// corners of the sealed mesh are welded by weldCorners, closing up the
+// slivers cuts leave along faces nearly parallel to them.
+func sealCuts(triangles []types.Triangle) []types.Triangle {
+	weld := func(p types.Point3D) types.Point3D {
+		const grid = csgEpsilon / 10
+		return types.Point3D{X: math.Round(p.X/grid) * grid, Y: math.Round(p.Y/grid) * grid, Z: math.Round(p.Z/grid) * grid}
+	}
+	welded := make([]types.Triangle, 0, len(triangles))
+	for _, t := range triangles {
+		t.V1, t.V2, t.V3 = weld(t.V1), weld(t.V2), weld(t.V3)
+		if isZeroVector(vectorCross(vectorSubtract(t.V2, t.V1), vectorSubtract(t.V3, t.V1))) {
+			continue
+		}
+		welded = append(welded, t)
+	}
+
+	mesh := types.NewMesh(welded)
+	type edge struct{ a, b uint32 }
+	edges := make(map[edge]int, len(mesh.Faces)*3)
+	for _, f := range mesh.Faces {
+		edges[edge{f.V[0], f.V[1]}]++
+		edges[edge{f.V[1], f.V[2]}]++
+		edges[edge{f.V[2], f.V[0]}]++
+	}
+
+	// between returns the vertices lying inside the edge from a to b, in order from a
+	between := func(a, b uint32) []types.Point3D {
+		pa, pb := mesh.Vertices[a], mesh.Vertices[b]
+		dir := vectorSubtract(pb, pa)
+		length := math.Sqrt(vectorDot(dir, dir))
+		if length < csgEpsilon {
+			return nil
+		}
+		dir = types.Point3D{X: dir.X / length, Y: dir.Y / length, Z: dir.Z / length}
+		var along []float64
+		var points []types.Point3D
+		for i, p := range mesh.Vertices {
+			if uint32(i) == a || uint32(i) == b {
+				continue
+			}
+			offset := vectorSubtract(p, pa)
+			t := vectorDot(offset, dir)
+			if t < csgEpsilon || t > length-csgEpsilon {
+				continue
+			}
+			if off := vectorSubtract(offset, types.Point3D{X: dir.X * t, Y: dir.Y * t, Z: dir.Z * t}); vectorDot(off, off) > csgEpsilon*csgEpsilon {
+				continue
+			}
+			// Insert in order along the edge
+			j := sort.SearchFloat64s(along, t)
+			along = append(along[:j], append([]float64{t}, along[j:]...)...)
+			points = append(points[:j], append([]types.Point3D{p}, points[j:]...)...)
+		}
+		return points
+	}
+
+	result := make([]types.Triangle, 0, len(mesh.Faces))
+	for i, f := range mesh.Faces {
+		var outline []types.Point3D
+		split := false
+		for k := 0; k < 3; k++ {
+			a, b := f.V[k], f.V[(k+1)%3]
+			outline = append(outline, mesh.Vertices[a])
+			if edges[edge{b, a}] == edges[edge{a, b}] {
+				continue
+			}
+			if points := between(a, b); len(points) > 0 {
+				outline = append(outline, points...)
+				split = true
+			}
+		}
+		if !split {
+			result = append(result, mesh.Triangle(i))
+			continue
+		}
+
+		t := mesh.Triangle(i)
+		center := types.Point3D{
+			X: (t.V1.X + t.V2.X + t.V3.X) / 3,
+			Y: (t.V1.Y + t.V2.Y + t.V3.Y) / 3,
+			Z: (t.V1.Z + t.V2.Z + t.V3.Z) / 3,
+		}
+		for k := range outline {
+			result = append(result, types.Triangle{Normal: f.Normal, V1: center, V2: outline[k], V3: outline[(k+1)%len(outline)]})
+		}
+	}
+	return weldCorners(result)
+}
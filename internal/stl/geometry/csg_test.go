@@ -0,0 +1,103 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestSubtractSolid verifies cuts remove the overlapping volume, including cuts flush with faces
+func TestSubtractSolid(t *testing.T) {
+	box := func(x, y, z, w, d, h float64) []types.Triangle {
+		triangles, err := createBox(x, y, z, w, d, h)
+		if err != nil {
+			t.Fatalf("createBox() error = %v", err)
+		}
+		return triangles
+	}
+
+	tests := []struct {
+		name       string
+		a          []types.Triangle
+		cuts       [][]types.Triangle
+		wantVolume float64
+	}{
+		{"pocket", box(0, 0, 0, 10, 10, 10), [][]types.Triangle{box(2, 2, 5, 2, 2, 10)}, 1000 - 2*2*5},
+		{"hole through", box(0, 0, 0, 10, 10, 10), [][]types.Triangle{box(4, 4, -1, 2, 2, 12)}, 1000 - 2*2*10},
+		{"cavity", box(0, 0, 0, 10, 10, 10), [][]types.Triangle{box(2, 2, 2, 2, 2, 2)}, 1000 - 8},
+		{"miss", box(0, 0, 0, 10, 10, 10), [][]types.Triangle{box(20, 20, 20, 2, 2, 2)}, 1000},
+		{"flush side", box(0, 0, 0, 10, 10, 10), [][]types.Triangle{box(0, 2, 5, 2, 2, 10)}, 1000 - 2*2*5},
+		{"neighboring pockets", box(0, 0, 0, 10, 10, 10), [][]types.Triangle{box(2, 2, 5, 2, 2, 10), box(4, 2, 5, 2, 2, 10), box(2, 4, 4, 2, 2, 10)}, 1000 - 3*2*2*5 - 2*2},
+		{"everything", box(0, 0, 0, 10, 10, 10), [][]types.Triangle{box(-1, -1, -1, 12, 12, 12)}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.a
+			for _, cut := range tt.cuts {
+				result = subtractSolid(result, cut)
+			}
+			if got := meshVolume(result); math.Abs(got-tt.wantVolume) > 1e-6 {
+				t.Errorf("volume = %v, want %v", got, tt.wantVolume)
+			}
+			for _, tri := range result {
+				if normal, err := calculateNormal(tri.V1, tri.V2, tri.V3); err != nil || vectorDot(normal, tri.Normal) < 1-epsilon {
+					t.Fatalf("triangle %+v is not wound along its normal", tri)
+				}
+			}
+		})
+	}
+}
+
+// TestSubtractSolidKeepsFarTriangles verifies triangles away from the cut pass through untouched
+func TestSubtractSolidKeepsFarTriangles(t *testing.T) {
+	a, err := createBox(0, 0, 0, 100, 10, 10)
+	if err != nil {
+		t.Fatalf("createBox() error = %v", err)
+	}
+	b, err := createBox(2, 2, 5, 2, 2, 10)
+	if err != nil {
+		t.Fatalf("createBox() error = %v", err)
+	}
+	result := subtractSolid(a, b)
+	minPoint, maxPoint := triangleBounds(result)
+	if minPoint != (types.Point3D{}) || maxPoint != (types.Point3D{X: 100, Y: 10, Z: 10}) {
+		t.Errorf("bounds = %v, %v, want the block unchanged outside the cut", minPoint, maxPoint)
+	}
+}
+
+// TestSealCuts verifies faces meeting neighbors partway along an edge are split to close the mesh
+func TestSealCuts(t *testing.T) {
+	box, err := createBox(0, 0, 0, 10, 10, 10)
+	if err != nil {
+		t.Fatalf("createBox() error = %v", err)
+	}
+	// Replace the two triangles of the top face with three, whose corner at
+	// (5, 0, 10) lies along the edges of the front face, and a sliver
+	var open []types.Triangle
+	for _, tri := range box {
+		if tri.V1.Z != 10 || tri.V2.Z != 10 || tri.V3.Z != 10 {
+			open = append(open, tri)
+		}
+	}
+	up := types.Point3D{Z: 1}
+	mid := types.Point3D{X: 5, Z: 10}
+	open = append(open,
+		types.Triangle{Normal: up, V1: types.Point3D{Z: 10}, V2: mid, V3: types.Point3D{Y: 10, Z: 10}},
+		types.Triangle{Normal: up, V1: mid, V2: types.Point3D{X: 10, Z: 10}, V3: types.Point3D{X: 10, Y: 10, Z: 10}},
+		types.Triangle{Normal: up, V1: mid, V2: types.Point3D{X: 10, Y: 10, Z: 10}, V3: types.Point3D{Y: 10, Z: 10}},
+		types.Triangle{Normal: up, V1: mid, V2: types.Point3D{X: 5 + 1e-7, Z: 10}, V3: types.Point3D{X: 10, Z: 10}},
+	)
+	if isClosedMesh(open) {
+		t.Fatal("test mesh is already closed")
+	}
+
+	sealed := sealCuts(open)
+	if !isClosedMesh(sealed) {
+		t.Error("sealCuts() left the mesh open")
+	}
+	if got := meshVolume(sealed); math.Abs(got-1000) > 1e-6 {
+		t.Errorf("volume = %v, want 1000", got)
+	}
+}
@@ -0,0 +1,98 @@
+package geometry
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// FutureDays identifies how the days of a year still to come are shown.
+type FutureDays string
+
+// Supported ways of showing future days.
+const (
+	FutureBlank    FutureDays = "blank"    // Bare base, like days without contributions
+	FutureTruncate FutureDays = "truncate" // Left out, with the grid and base ending at the current week
+	FutureDots     FutureDays = "dots"     // A low dot on each day, like the preview's '.' marker
+)
+
+// DefaultFutureDays is the way future days are shown when none is configured.
+const DefaultFutureDays = FutureBlank
+
+// futureDays lists the supported ways of showing future days in the order they are presented to users.
+var futureDays = []FutureDays{FutureBlank, FutureTruncate, FutureDots}
+
+// FutureDaysModes returns the names of all supported ways of showing future days.
+func FutureDaysModes() []string {
+	names := make([]string, len(futureDays))
+	for i, f := range futureDays {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// ParseFutureDays converts a user supplied name into a FutureDays. Matching is
+// case-insensitive and an empty string selects DefaultFutureDays.
+func ParseFutureDays(name string) (FutureDays, error) {
+	if name == "" {
+		return DefaultFutureDays, nil
+	}
+	for _, f := range futureDays {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported future days mode %q (supported: %s)", name, strings.Join(FutureDaysModes(), ", ")), nil)
+}
+
+// Size of the dots marking future days.
+const (
+	futureDotShare  = 0.4 // Width of a dot as a share of a tower's
+	futureDotHeight = 0.2 // Height of a dot as a share of the cell size
+)
+
+// CreateFutureDots generates a low dot in the middle of each day of a year
+// that is after now, when the layout marks future days with dots. The dots
+// follow the shape of the towers and stand on their year's tier.
+func (l Layout) CreateFutureDots(contributions [][]types.ContributionDay, yearIndex int, now time.Time) ([]types.Triangle, error) {
+	if l.FutureDays != FutureDots {
+		return nil, nil
+	}
+
+	var triangles []types.Triangle
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			if !day.IsAfter(now) {
+				continue
+			}
+
+			// Shrink the tower's outline about its middle
+			outline := l.towerOutline(yearIndex, weekIdx, dayIdx)
+			var center point2D
+			for _, p := range outline {
+				center.X += p.X / float64(len(outline))
+				center.Y += p.Y / float64(len(outline))
+			}
+			for i, p := range outline {
+				outline[i] = point2D{X: center.X + (p.X-center.X)*futureDotShare, Y: center.Y + (p.Y-center.Y)*futureDotShare}
+			}
+
+			dot, err := createPrism(outline, l.CellSize*futureDotHeight)
+			if err != nil {
+				return nil, err
+			}
+			if elevation := l.TierElevation(yearIndex); elevation > 0 {
+				for i := range dot {
+					dot[i].V1.Z += elevation
+					dot[i].V2.Z += elevation
+					dot[i].V3.Z += elevation
+				}
+			}
+			triangles = append(triangles, dot...)
+		}
+	}
+	return triangles, nil
+}
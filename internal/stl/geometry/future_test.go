@@ -0,0 +1,84 @@
+package geometry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseFutureDays(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FutureDays
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultFutureDays, false},
+		{"blank", "blank", FutureBlank, false},
+		{"truncate", "truncate", FutureTruncate, false},
+		{"dots", "dots", FutureDots, false},
+		{"case insensitive", "Dots", FutureDots, false},
+		{"unknown", "hide", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFutureDays(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFutureDays(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFutureDays(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCreateFutureDots verifies a low dot stands inside the cell of each day after now, on its tier when stacked
+func TestCreateFutureDots(t *testing.T) {
+	week := []types.ContributionDay{{Date: "2024-03-03"}, {Date: "2024-03-04", ContributionCount: 2}, {Date: "2024-03-05"}, {Date: "2024-03-06"}}
+	now := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	for _, stack := range []bool{false, true} {
+		layout, err := NewLayout(Config{FutureDays: FutureDots, Stack: stack}, 2)
+		if err != nil {
+			t.Fatalf("NewLayout() error = %v", err)
+		}
+		dots, err := layout.CreateFutureDots([][]types.ContributionDay{week}, 1, now)
+		if err != nil {
+			t.Fatalf("CreateFutureDots() error = %v", err)
+		}
+		if len(dots) == 0 {
+			t.Fatal("CreateFutureDots() returned no triangles")
+		}
+
+		days := make(map[int]bool)
+		bottom := layout.TierElevation(1)
+		for _, tri := range dots {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				if v.Z < bottom-epsilon || v.Z > bottom+layout.CellSize*futureDotHeight+epsilon {
+					t.Fatalf("stack=%v: dot vertex %v is not low on its tier at %v", stack, v, bottom)
+				}
+				x, y := layout.TowerPosition(1, 0, 0)
+				day := int((v.Y - y) / (layout.CellSize + layout.Gap))
+				if v.X <= x || v.X >= x+layout.CellSize {
+					t.Fatalf("stack=%v: dot vertex %v leaves its week", stack, v)
+				}
+				days[day] = true
+			}
+		}
+		if len(days) != 2 || !days[2] || !days[3] {
+			t.Errorf("stack=%v: dots stand on days %v, want the two after now", stack, days)
+		}
+	}
+
+	// Without dots nothing is generated
+	layout, err := NewLayout(DefaultConfig(), 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if dots, err := layout.CreateFutureDots([][]types.ContributionDay{week}, 0, now); err != nil || len(dots) != 0 {
+		t.Errorf("CreateFutureDots() without dots = %d triangles, %v, want none", len(dots), err)
+	}
+}
@@ -1,7 +1,10 @@
 package geometry
 
 import (
+	"fmt"
 	"math"
+	"strconv"
+	"time"
 
 	"github.com/github/gh-skyline/internal/types"
 )
@@ -41,48 +44,120 @@ type ModelDimensions struct {
 // NormalizeContribution converts a contribution count to a normalized height value.
 // Returns 0 for no contributions, or a value between MinHeight and MaxHeight for active contributions.
 func NormalizeContribution(count, maxCount int) float64 {
+	return normalizeContribution(count, maxCount, MinHeight, MaxHeight, DefaultScale)
+}
+
+// normalizeContribution maps a contribution count onto the height range [minHeight, maxHeight]
+// using the given scaling mode.
+func normalizeContribution(count, maxCount int, minHeight, maxHeight float64, scale Scale) float64 {
 	if count == 0 {
 		return 0 // No contribution means no column
 	}
 	if maxCount <= 0 {
-		return MinHeight // Avoid division by zero, return minimum height
+		return minHeight // Avoid division by zero, return minimum height
 	}
 
 	// Calculate the available height range for columns
-	heightRange := MaxHeight - MinHeight
+	heightRange := maxHeight - minHeight
 
-	// Use square root to create more visual variation in height
-	// This creates a more pronounced difference between low and high contribution counts
-	normalizedValue := math.Sqrt(float64(count)) / math.Sqrt(float64(maxCount))
+	// Non-linear scales create a more pronounced difference between low and
+	// high contribution counts, so a few busy days do not flatten the rest
+	normalizedValue := math.Min(scale.fraction(count, maxCount), 1)
 
-	// Scale to fit between MinHeight and MaxHeight
-	return MinHeight + (normalizedValue * heightRange)
+	// Scale to fit between minHeight and maxHeight
+	return minHeight + (normalizedValue * heightRange)
 }
 
 // CreateContributionGeometry generates geometry for a single year's contributions
 func CreateContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
+	towers, err := CreateContributionObjects(contributions, yearIndex, maxContrib)
+	if err != nil {
+		return nil, err
+	}
+
 	var triangles []types.Triangle
+	for _, tower := range towers {
+		triangles = append(triangles, tower.Mesh.Triangles()...)
+	}
+	return triangles, nil
+}
 
-	// Base Y offset includes padding and positions each year accordingly
-	baseYOffset := 2*CellSize + float64(yearIndex)*YearOffset
+// CreateContributionObjects generates one tower object per day with contributions for a single
+// year using the default model layout.
+func CreateContributionObjects(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.ModelObject, error) {
+	layout, err := NewLayout(DefaultConfig(), yearIndex+1)
+	if err != nil {
+		return nil, err
+	}
+	return layout.CreateContributionObjects(contributions, yearIndex, maxContrib)
+}
+
+// CreateContributionObjects generates one tower object per day with contributions for a single year.
+// Each tower carries its date and contribution count as metadata so exporters can attribute it.
+// Weeks are generated in parallel and their towers gathered in week order. With a smooth surface,
+// the year is generated as a single surface object instead.
+func (l Layout) CreateContributionObjects(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.ModelObject, error) {
+	if l.SmoothSurface {
+		if len(contributions) == 0 {
+			return nil, nil
+		}
+		surface, err := l.createSurfaceObject(contributions, yearIndex, maxContrib)
+		if err != nil {
+			return nil, err
+		}
+		return []types.ModelObject{surface}, nil
+	}
 
-	for weekIdx, week := range contributions {
-		for dayIdx, day := range week {
+	weeks, err := parallelMap(len(contributions), func(weekIdx int) ([]types.ModelObject, error) {
+		var towers []types.ModelObject
+		for dayIdx, day := range contributions[weekIdx] {
 			if day.ContributionCount > 0 {
-				height := NormalizeContribution(day.ContributionCount, maxContrib)
-				x := 2*CellSize + float64(weekIdx)*CellSize
-				y := baseYOffset + float64(dayIdx)*CellSize
+				height := l.TowerHeight(day.ContributionCount, maxContrib)
 
-				columnTriangles, err := CreateColumn(x, y, height, CellSize)
+				columnTriangles, err := l.createTower(yearIndex, weekIdx, dayIdx, height)
 				if err != nil {
 					return nil, err
 				}
-				triangles = append(triangles, columnTriangles...)
+				towers = append(towers, types.ModelObject{
+					Name:     fmt.Sprintf("tower-%d-%d", weekIdx, dayIdx),
+					Kind:     types.ObjectTower,
+					Material: towerMaterial(day.ContributionCount, maxContrib),
+					Mesh:     types.NewMesh(columnTriangles),
+					Metadata: towerMetadata(day),
+				})
 			}
 		}
+		return towers, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return triangles, nil
+	var towers []types.ModelObject
+	for _, week := range weeks {
+		towers = append(towers, week...)
+	}
+	return towers, nil
+}
+
+// towerMetadata describes the contribution day a tower represents.
+func towerMetadata(day types.ContributionDay) []types.Metadata {
+	metadata := []types.Metadata{{Key: "date", Value: day.Date}}
+	if date, err := time.Parse("2006-01-02", day.Date); err == nil {
+		metadata = append(metadata, types.Metadata{Key: "year", Value: strconv.Itoa(date.Year())})
+	}
+	return append(metadata, types.Metadata{Key: "contributions", Value: strconv.Itoa(day.ContributionCount)})
+}
+
+// towerMaterial buckets a contribution count into one of GitHub's four
+// contribution levels, each covering a quarter of the range up to maxCount.
+func towerMaterial(count, maxCount int) types.Material {
+	levels := []types.Material{types.MaterialLevel1, types.MaterialLevel2, types.MaterialLevel3, types.MaterialLevel4}
+	if maxCount <= 0 {
+		return levels[len(levels)-1]
+	}
+	level := (4*count - 1) / maxCount
+	return levels[max(0, min(level, len(levels)-1))]
 }
 
 // CalculateMultiYearDimensions calculates dimensions for multiple years
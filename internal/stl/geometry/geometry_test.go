@@ -107,3 +107,83 @@ func TestCalculateMultiYearDimensions(t *testing.T) {
 		})
 	}
 }
+
+// TestCreateContributionObjects verifies towers are emitted per active day with metadata
+func TestCreateContributionObjects(t *testing.T) {
+	contributions := [][]types.ContributionDay{
+		{
+			{ContributionCount: 3, Date: "2024-01-01"},
+			{ContributionCount: 0, Date: "2024-01-02"},
+		},
+		{
+			{ContributionCount: 7, Date: "2024-01-08"},
+		},
+	}
+
+	towers, err := CreateContributionObjects(contributions, 0, 7)
+	if err != nil {
+		t.Fatalf("CreateContributionObjects() error = %v", err)
+	}
+	if len(towers) != 2 {
+		t.Fatalf("CreateContributionObjects() returned %d towers, want 2", len(towers))
+	}
+
+	tower := towers[1]
+	if tower.Kind != types.ObjectTower {
+		t.Errorf("tower kind = %q, want %q", tower.Kind, types.ObjectTower)
+	}
+	if tower.Mesh.Len() != 12 {
+		t.Errorf("tower has %d triangles, want 12", tower.Mesh.Len())
+	}
+	want := []types.Metadata{
+		{Key: "date", Value: "2024-01-08"},
+		{Key: "year", Value: "2024"},
+		{Key: "contributions", Value: "7"},
+	}
+	if len(tower.Metadata) != len(want) {
+		t.Fatalf("tower metadata = %v, want %v", tower.Metadata, want)
+	}
+	for i := range want {
+		if tower.Metadata[i] != want[i] {
+			t.Errorf("tower metadata[%d] = %v, want %v", i, tower.Metadata[i], want[i])
+		}
+	}
+}
+
+func TestTowerMaterial(t *testing.T) {
+	tests := []struct {
+		count, maxCount int
+		want            types.Material
+	}{
+		{1, 100, types.MaterialLevel1},
+		{25, 100, types.MaterialLevel1},
+		{26, 100, types.MaterialLevel2},
+		{50, 100, types.MaterialLevel2},
+		{75, 100, types.MaterialLevel3},
+		{76, 100, types.MaterialLevel4},
+		{100, 100, types.MaterialLevel4},
+		{1, 1, types.MaterialLevel4},
+		{1, 0, types.MaterialLevel4},
+	}
+
+	for _, tt := range tests {
+		if got := towerMaterial(tt.count, tt.maxCount); got != tt.want {
+			t.Errorf("towerMaterial(%d, %d) = %q, want %q", tt.count, tt.maxCount, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkCreateContributionObjects measures building the towers of a year of contributions.
+func BenchmarkCreateContributionObjects(b *testing.B) {
+	contributions := make([][]types.ContributionDay, GridSize)
+	for week := range contributions {
+		for day := 0; day < 7; day++ {
+			contributions[week] = append(contributions[week], types.ContributionDay{ContributionCount: (week + day) % 10, Date: "2024-01-01"})
+		}
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := CreateContributionObjects(contributions, 0, 9); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package geometry
+
+import (
+	"image"
+
+	"github.com/fogleman/gg"
+)
+
+// pixelRect is a rectangle of pixels with its top left pixel at column x and
+// row y, w pixels wide and h pixels tall.
+type pixelRect struct {
+	x, y, w, h int
+}
+
+// mergePixels covers the active pixels of a grid cols wide and rows tall with
+// rectangles, so each rectangle becomes a single voxel instead of one voxel
+// per pixel. The grid is swept row by row, growing every rectangle as wide as
+// it goes and then as tall as the full width allows. When tall is false, every
+// rectangle stays a single row, for faces whose voxels step with the row.
+func mergePixels(cols, rows int, active func(x, y int) bool, tall bool) []pixelRect {
+	used := make([]bool, cols*rows)
+	free := func(x, y int) bool {
+		return !used[y*cols+x] && active(x, y)
+	}
+
+	var rects []pixelRect
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if !free(x, y) {
+				continue
+			}
+			w := 1
+			for x+w < cols && free(x+w, y) {
+				w++
+			}
+			h := 1
+		grow:
+			for tall && y+h < rows {
+				for i := x; i < x+w; i++ {
+					if !free(i, y+h) {
+						break grow
+					}
+				}
+				h++
+			}
+			for j := y; j < y+h; j++ {
+				for i := x; i < x+w; i++ {
+					used[j*cols+i] = true
+				}
+			}
+			rects = append(rects, pixelRect{x: x, y: y, w: w, h: h})
+		}
+	}
+	return rects
+}
+
+// mergeDrawing covers the white pixels of a drawing with rectangles, as
+// mergePixels does.
+func mergeDrawing(dc *gg.Context, tall bool) []pixelRect {
+	// Reading the canvas directly skips converting the color of every pixel,
+	// which otherwise dominates voxelizing large drawings
+	if img, ok := dc.Image().(*image.RGBA); ok {
+		return mergePixels(dc.Width(), dc.Height(), func(x, y int) bool {
+			return img.Pix[img.PixOffset(x, y)] > 0x7f // Red above half, as isPixelActive
+		}, tall)
+	}
+	return mergePixels(dc.Width(), dc.Height(), func(x, y int) bool {
+		return isPixelActive(dc, x, y)
+	}, tall)
+}
@@ -0,0 +1,78 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+// TestMergePixels verifies the rectangles cover every active pixel exactly once
+func TestMergePixels(t *testing.T) {
+	tests := []struct {
+		name      string
+		grid      []string
+		tall      bool
+		wantRects int
+	}{
+		{name: "empty", grid: []string{"...", "..."}, tall: true, wantRects: 0},
+		{name: "full", grid: []string{"####", "####", "####"}, tall: true, wantRects: 1},
+		{name: "full rows only", grid: []string{"####", "####", "####"}, tall: false, wantRects: 3},
+		{name: "L shape", grid: []string{"#..", "#..", "###"}, tall: true, wantRects: 2},
+		{name: "checkerboard", grid: []string{"#.#", ".#.", "#.#"}, tall: true, wantRects: 5},
+		{name: "ring", grid: []string{"####", "#..#", "####"}, tall: true, wantRects: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, cols := len(tt.grid), len(tt.grid[0])
+			active := func(x, y int) bool { return tt.grid[y][x] == '#' }
+
+			rects := mergePixels(cols, rows, active, tt.tall)
+			if len(rects) != tt.wantRects {
+				t.Errorf("mergePixels() returned %d rectangles, want %d", len(rects), tt.wantRects)
+			}
+
+			covered := make([]int, cols*rows)
+			for _, r := range rects {
+				if !tt.tall && r.h != 1 {
+					t.Errorf("rectangle %+v spans rows, want single rows", r)
+				}
+				for y := r.y; y < r.y+r.h; y++ {
+					for x := r.x; x < r.x+r.w; x++ {
+						covered[y*cols+x]++
+					}
+				}
+			}
+			for y := 0; y < rows; y++ {
+				for x := 0; x < cols; x++ {
+					want := 0
+					if active(x, y) {
+						want = 1
+					}
+					if covered[y*cols+x] != want {
+						t.Errorf("pixel (%d, %d) covered %d times, want %d", x, y, covered[y*cols+x], want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestEmbossOnTopMerged verifies a filled drawing becomes a single voxel of the same volume
+func TestEmbossOnTopMerged(t *testing.T) {
+	triangles, err := embossOnTop(0, 0, 10, 5, func(dc *gg.Context) error {
+		dc.DrawRectangle(0, 0, float64(dc.Width()), float64(dc.Height()))
+		dc.Fill()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("embossOnTop() error = %v", err)
+	}
+	if len(triangles) != 12 {
+		t.Errorf("embossOnTop() returned %d triangles, want a single box of 12", len(triangles))
+	}
+	if volume, want := meshVolume(triangles), 10*5*voxelDepth; math.Abs(volume-want) > epsilon {
+		t.Errorf("embossOnTop() volume = %g, want %g", volume, want)
+	}
+}
@@ -0,0 +1,106 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// drainHoleSegments is the number of straight segments approximating each drain hole.
+const drainHoleSegments = 4 * cornerSegments
+
+// cavityBounds returns the footprint and height range of the cavity inside a
+// hollow base. The cavity keeps the wall thickness from the bottom and top
+// faces, and from the narrowest outline of the base at its top.
+func (l Layout) cavityBounds() (x0, y0, x1, y1, z0, z1 float64) {
+	inset := l.topInset() + l.Hollow
+	return inset, inset, l.Width - inset, l.Depth - inset, -l.Height + l.Hollow, -l.Hollow
+}
+
+// validateHollow checks that the cavity and its drain holes fit inside the base.
+func (l Layout) validateHollow() error {
+	if l.Hollow == 0 {
+		if l.DrainHole > 0 {
+			return errors.New(errors.ValidationError, "drain holes need a hollow base", nil)
+		}
+		return nil
+	}
+	x0, y0, x1, y1, z0, z1 := l.cavityBounds()
+	if x1 <= x0 || y1 <= y0 || z1 <= z0 {
+		return errors.New(errors.ValidationError, fmt.Sprintf("walls of %s leave no room for a cavity in the base", l.Unit.Format(l.Hollow)), nil)
+	}
+	if l.DrainHole > 0 && (2*l.DrainHole > y1-y0 || 4*l.DrainHole > x1-x0) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("drain holes of %s do not fit under the cavity", l.Unit.Format(l.DrainHole)), nil)
+	}
+	return nil
+}
+
+// drainHoles returns the clockwise outlines of the drain holes in the bottom
+// of a hollow base, one near each end of the cavity.
+func (l Layout) drainHoles() []contour {
+	if l.DrainHole == 0 {
+		return nil
+	}
+	x0, y0, x1, y1, _, _ := l.cavityBounds()
+	radius := l.DrainHole / 2
+	cy := (y0 + y1) / 2
+	var holes []contour
+	for _, cx := range []float64{x0 + l.DrainHole, x1 - l.DrainHole} {
+		hole := make(contour, drainHoleSegments)
+		for i := range hole {
+			angle := -2 * math.Pi * float64(i) / drainHoleSegments
+			hole[i] = point2D{X: cx + radius*math.Cos(angle), Y: cy + radius*math.Sin(angle)}
+		}
+		holes = append(holes, hole)
+	}
+	return holes
+}
+
+// createHollowBase generates the base as an outer shell around an inward
+// facing cavity, joined by a tube through the bottom wall for each drain hole.
+func (l Layout) createHollowBase() ([]types.Triangle, error) {
+	holes := l.drainHoles()
+	outer, err := createLoftWithHoles(l.baseRings(), holes)
+	if err != nil {
+		return nil, err
+	}
+
+	x0, y0, x1, y1, z0, z1 := l.cavityBounds()
+	radius := math.Max(0, l.CornerRadius-l.topInset()-l.Hollow)
+	cavity, err := createLoftWithHoles([]outlineRing{
+		roundedRectRing(x0, y0, x1, y1, radius, z0),
+		roundedRectRing(x0, y0, x1, y1, radius, z1),
+	}, holes)
+	if err != nil {
+		return nil, err
+	}
+	// The cavity's faces point into it, away from the surrounding material
+	for i, t := range cavity {
+		cavity[i] = types.Triangle{
+			Normal: types.Point3D{X: -t.Normal.X, Y: -t.Normal.Y, Z: -t.Normal.Z},
+			V1:     t.V1, V2: t.V3, V3: t.V2,
+		}
+	}
+
+	triangles := append(outer, cavity...)
+	bottom := -l.Height
+	for _, hole := range holes {
+		// Tube walls face the hole's axis, joining the bottom face to the cavity floor
+		for i, a := range hole {
+			b := hole[(i+1)%len(hole)]
+			lowA := types.Point3D{X: a.X, Y: a.Y, Z: bottom}
+			lowB := types.Point3D{X: b.X, Y: b.Y, Z: bottom}
+			highA := types.Point3D{X: a.X, Y: a.Y, Z: z0}
+			highB := types.Point3D{X: b.X, Y: b.Y, Z: z0}
+			quad, err := CreateQuad(lowA, lowB, highB, highA)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, quad...)
+		}
+	}
+
+	return triangles, nil
+}
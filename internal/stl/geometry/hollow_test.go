@@ -0,0 +1,78 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// TestLayoutCreateHollowBase verifies hollow bases are closed with the cavity and holes removed
+func TestLayoutCreateHollowBase(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"flat", Config{Hollow: 2}},
+		{"flat with drain holes", Config{Hollow: 2, DrainHole: 3}},
+		{"sloped", Config{Hollow: 2, BaseStyle: BaseSloped}},
+		{"rounded and chamfered with drain holes", Config{Hollow: 1.5, DrainHole: 2, CornerRadius: 3, Chamfer: 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.cfg, 2)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			hollow, err := layout.CreateBase()
+			if err != nil {
+				t.Fatalf("CreateBase() error = %v", err)
+			}
+			if !isClosedMesh(hollow) {
+				t.Error("hollow base is not a closed mesh")
+			}
+
+			solidLayout := layout
+			solidLayout.Hollow, solidLayout.DrainHole = 0, 0
+			solid, err := solidLayout.CreateBase()
+			if err != nil {
+				t.Fatalf("CreateBase() error = %v", err)
+			}
+
+			x0, y0, x1, y1, z0, z1 := layout.cavityBounds()
+			radius := math.Max(0, layout.CornerRadius-layout.topInset()-layout.Hollow)
+			cavity := ((x1-x0)*(y1-y0) - (4-math.Pi)*radius*radius) * (z1 - z0)
+			holes := 0.0
+			for _, h := range layout.drainHoles() {
+				holes -= h.signedArea() * layout.Hollow
+			}
+			want := meshVolume(solid) - cavity - holes
+			if got := meshVolume(hollow); math.Abs(got-want) > 0.01*want {
+				t.Errorf("hollow base volume = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestValidateHollow verifies cavities and drain holes must fit inside the base
+func TestValidateHollow(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"solid", Config{}, false},
+		{"hollow", Config{Hollow: 2}, false},
+		{"walls too thick", Config{Hollow: 5}, true},
+		{"drain holes without cavity", Config{DrainHole: 2}, true},
+		{"drain holes too large", Config{Hollow: 2, DrainHole: 20}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLayout(tt.cfg, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
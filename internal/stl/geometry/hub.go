@@ -0,0 +1,142 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	topVoxelSize      = 0.1   // Side of each voxel embossed on the top face, in millimeters
+	hubTextFill       = 0.8   // Share of its row the text's height may take
+	hubMinFontSize    = 1.5   // Smallest font, in millimeters, that still prints legibly on the top face
+	hubReferenceSize  = 100.0 // Font size the text is measured at before scaling to fit
+	hubLogoShare      = 0.4   // Share of the hub taken by the logo above the text
+	hubUsernameShare  = 0.55  // Share of the text taken by the username above the year
+	hubLogoOnlyMargin = 0.1   // Margin around a logo shown without text, as a share of the hub
+)
+
+// hubRow is a horizontal band of the square in the center of a round base,
+// from its top and as a share of its side.
+type hubRow struct {
+	top, height float64
+}
+
+// hubSquare returns the center and side of the square in the middle of a round
+// base that is embossed with the username, year and logo, keeping a cell clear
+// of the innermost towers.
+func (l Layout) hubSquare() (cx, cy, side float64) {
+	radius := math.Max(0, l.HubRadius-l.CellSize)
+	return l.Width / 2, l.Depth / 2, radius * math.Sqrt2
+}
+
+// hubRows returns the rows of the hub taken by the logo, the username and the
+// year. The logo sits above the text when both are embossed.
+func (l Layout) hubRows() (logo, username, year hubRow) {
+	textTop, textHeight := 0.0, 1.0
+	switch {
+	case l.Emboss.Logo && l.Emboss.Text:
+		logo = hubRow{0, hubLogoShare}
+		textTop, textHeight = hubLogoShare, 1-hubLogoShare
+	case l.Emboss.Logo:
+		logo = hubRow{hubLogoOnlyMargin, 1 - 2*hubLogoOnlyMargin}
+	}
+	username = hubRow{textTop, textHeight * hubUsernameShare}
+	year = hubRow{textTop + username.height, textHeight - username.height}
+	return logo, username, year
+}
+
+// createHubText generates the username and year in the center of a round base,
+// reading from the front.
+func (l Layout) createHubText(username string, year string) ([]types.Triangle, error) {
+	if username == "" {
+		username = "anonymous"
+	}
+	_, usernameRow, yearRow := l.hubRows()
+
+	f, err := loadRasterFont()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.embossOnHub(func(dc *gg.Context, res float64) error {
+		for _, line := range []struct {
+			text string
+			row  hubRow
+		}{{username, usernameRow}, {year, yearRow}} {
+			setFontFace(dc, f, hubReferenceSize)
+			width, _ := dc.MeasureString(line.text)
+			size := math.Min(line.row.height*res*hubTextFill, hubReferenceSize*res*hubTextFill/width)
+			if millimeters := size * topVoxelSize; millimeters < hubMinFontSize {
+				return errors.New(errors.ValidationError, fmt.Sprintf("%q does not fit legibly in the center of a %s round base", line.text, l.Unit.Format(l.Width)), nil)
+			}
+			setFontFace(dc, f, size)
+			dc.DrawStringAnchored(line.text, res/2, (line.row.top+line.row.height/2)*res, 0.5, 0.5)
+		}
+		return nil
+	})
+}
+
+// createHubLogo generates the embedded logo in the center of a round base.
+func (l Layout) createHubLogo() ([]types.Triangle, error) {
+	img, err := loadLogo()
+	if err != nil {
+		return nil, err
+	}
+	row, _, _ := l.hubRows()
+
+	return l.embossOnHub(func(dc *gg.Context, res float64) error {
+		bounds := img.Bounds()
+		scale := math.Min(row.height*res/float64(bounds.Dy()), res/float64(bounds.Dx()))
+		dc.Scale(scale, scale)
+		dc.DrawImageAnchored(img, int(res/2/scale), int((row.top+row.height/2)*res/scale), 0.5, 0.5)
+		return nil
+	})
+}
+
+// embossOnHub rasterizes a drawing of the square in the center of a round
+// base, res pixels across, and generates voxels on the top face covering the
+// white pixels.
+func (l Layout) embossOnHub(draw func(dc *gg.Context, res float64) error) ([]types.Triangle, error) {
+	cx, cy, side := l.hubSquare()
+	if int(side/topVoxelSize) == 0 {
+		return nil, errors.New(errors.ValidationError, "the center of the round base is too small to emboss", nil)
+	}
+	return embossOnTop(cx-side/2, cy-side/2, side, side, func(dc *gg.Context) error {
+		return draw(dc, float64(dc.Width()))
+	})
+}
+
+// embossOnTop rasterizes a drawing of a rectangle of the top face, with its
+// front left corner at x, y, and generates voxels on the top face covering the
+// white pixels. The top of the drawing is at the back of the rectangle, so it
+// reads from the front.
+func embossOnTop(x, y, width, depth float64, draw func(dc *gg.Context) error) ([]types.Triangle, error) {
+	cols, rows := int(width/topVoxelSize), int(depth/topVoxelSize)
+	if cols == 0 || rows == 0 {
+		return nil, errors.New(errors.ValidationError, "the area of the top face is too small to emboss", nil)
+	}
+	voxelX, voxelY := width/float64(cols), depth/float64(rows)
+
+	dc := gg.NewContext(cols, rows)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetRGB(1, 1, 1)
+	if err := draw(dc); err != nil {
+		return nil, err
+	}
+
+	back := y + depth
+	var triangles []types.Triangle
+	for _, r := range mergeDrawing(dc, true) {
+		cube, err := CreateCube(x+float64(r.x)*voxelX, back-float64(r.y+r.h)*voxelY, 0, float64(r.w)*voxelX, float64(r.h)*voxelY, voxelDepth)
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create cube", err)
+		}
+		triangles = append(triangles, cube...)
+	}
+	return triangles, nil
+}
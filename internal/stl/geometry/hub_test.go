@@ -0,0 +1,89 @@
+package geometry
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestLayoutHubRows verifies the logo and text share the center of a round base
+func TestLayoutHubRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		emboss Emboss
+	}{
+		{"text and logo", Emboss{Text: true, Logo: true}},
+		{"text only", Emboss{Text: true}},
+		{"logo only", Emboss{Logo: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logo, username, year := Layout{Emboss: tt.emboss}.hubRows()
+			for _, row := range []hubRow{logo, username, year} {
+				if row.top < 0 || row.top+row.height > 1+epsilon {
+					t.Errorf("row %+v is outside the hub", row)
+				}
+			}
+			if tt.emboss.Text && username.top+username.height > year.top+epsilon {
+				t.Errorf("username %+v overlaps the year %+v", username, year)
+			}
+			if tt.emboss.Logo && tt.emboss.Text && logo.top+logo.height > username.top+epsilon {
+				t.Errorf("logo %+v overlaps the username %+v", logo, username)
+			}
+			if tt.emboss.Logo && logo.height == 0 {
+				t.Error("logo has no room")
+			}
+		})
+	}
+}
+
+// TestLayoutCreateHubEmboss verifies text and logo are embossed on the top face inside the towers
+func TestLayoutCreateHubEmboss(t *testing.T) {
+	for _, arrangement := range []Arrangement{ArrangementRadial, ArrangementSpiral} {
+		t.Run(string(arrangement), func(t *testing.T) {
+			layout, err := NewLayout(Config{Arrangement: arrangement}, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+
+			text, err := layout.CreateText("mona", "2024")
+			if err != nil {
+				t.Fatalf("CreateText() error = %v", err)
+			}
+			logo, err := layout.CreateLogo()
+			if err != nil {
+				t.Fatalf("CreateLogo() error = %v", err)
+			}
+
+			for name, triangles := range map[string][]types.Triangle{"text": text, "logo": logo} {
+				if len(triangles) == 0 {
+					t.Fatalf("%s has no triangles", name)
+				}
+				for _, tri := range triangles {
+					for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+						if r := math.Hypot(v.X-layout.Width/2, v.Y-layout.Depth/2); r > layout.HubRadius-layout.CellSize+epsilon {
+							t.Fatalf("%s vertex %v is %vmm from the center, outside the hub", name, v, r)
+						}
+						if v.Z < -epsilon || v.Z > voxelDepth+epsilon {
+							t.Fatalf("%s vertex %v is not on the top face", name, v)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestLayoutCreateHubTextTooLong verifies text too small to print is rejected
+func TestLayoutCreateHubTextTooLong(t *testing.T) {
+	layout, err := NewLayout(Config{Arrangement: ArrangementRadial}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if _, err := layout.CreateText(strings.Repeat("w", 60), "2024"); err == nil {
+		t.Error("CreateText() expected error for a username too long to fit")
+	}
+}
@@ -0,0 +1,175 @@
+package geometry
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	avatarPanelSize    = 50.0 // Width and height of the avatar panel, shrunk to fit narrow bases
+	avatarFrameWidth   = 2.0  // Border of full thickness around the lithophane
+	avatarMinThickness = 0.8  // Thickness of white areas, which let the most light through
+	avatarMaxThickness = 3.0  // Thickness of black areas, limited by the room behind the towers
+	avatarMinRelief    = 1.0  // Smallest difference between white and black areas that shows an image
+	avatarPixelSize    = 0.4  // Spacing of the lithophane's height samples, about one nozzle width
+)
+
+// avatarThickness returns the thickness of the darkest areas of the avatar
+// panel, which stands in the margin between the back edge and the towers.
+func (l Layout) avatarThickness() float64 {
+	return math.Min(avatarMaxThickness, l.OffsetY-l.topInset())
+}
+
+// avatarWidth returns the width and height of the avatar panel, which keeps
+// clear of the rounded corners and sloped sides.
+func (l Layout) avatarWidth() float64 {
+	return math.Min(avatarPanelSize, l.Width-2*l.backClearance())
+}
+
+// validateAvatar checks that the avatar panel fits behind the towers.
+func (l Layout) validateAvatar() error {
+	if !l.Avatar {
+		return nil
+	}
+	if thickness := l.avatarThickness(); thickness < avatarMinThickness+avatarMinRelief {
+		return errors.New(errors.ValidationError, fmt.Sprintf("the %s behind the towers is too thin for an avatar panel (at least %s needed)", l.Unit.Format(math.Max(0, thickness)), l.Unit.Format(avatarMinThickness+avatarMinRelief)), nil)
+	}
+	if l.avatarWidth() <= 2*avatarFrameWidth+avatarPixelSize {
+		return errors.New(errors.ValidationError, "the base is too narrow for an avatar panel", nil)
+	}
+	return nil
+}
+
+// CreateAvatar generates a lithophane of img standing on the top face along
+// the back edge of the base, behind the towers. Darker areas are thicker, so
+// the image shows when the panel is lit from behind. The image reads correctly
+// from the front, and its relief faces the towers while the back stays flat.
+func (l Layout) CreateAvatar(img image.Image) ([]types.Triangle, error) {
+	if img == nil || img.Bounds().Empty() {
+		return nil, errors.New(errors.ValidationError, "avatar image is empty", nil)
+	}
+
+	size := l.avatarWidth()
+	cells := int(math.Round(size / avatarPixelSize))
+	step := size / float64(cells)
+	x0 := (l.Width - size) / 2
+	back := l.Depth - l.topInset()
+	darkest := l.avatarThickness()
+	luminance := newLuminanceGrid(img)
+
+	// Thickness at each vertex of the grid, with j counting rows up from the base
+	thickness := make([][]float64, cells+1)
+	for i := range thickness {
+		thickness[i] = make([]float64, cells+1)
+		for j := range thickness[i] {
+			x, z := float64(i)*step, float64(j)*step
+			inner := size - 2*avatarFrameWidth
+			u, v := (x-avatarFrameWidth)/inner, 1-(z-avatarFrameWidth)/inner
+			thickness[i][j] = darkest
+			if u > 0 && u < 1 && v > 0 && v < 1 {
+				thickness[i][j] = avatarMinThickness + (1-luminance.at(u, v))*(darkest-avatarMinThickness)
+			}
+		}
+	}
+	front := func(i, j int) types.Point3D {
+		return types.Point3D{X: x0 + float64(i)*step, Y: back - thickness[i][j], Z: float64(j) * step}
+	}
+	rear := func(i, j int) types.Point3D {
+		return types.Point3D{X: x0 + float64(i)*step, Y: back, Z: float64(j) * step}
+	}
+
+	var triangles []types.Triangle
+	var err error
+	add := func(a, b, c types.Point3D) {
+		if err != nil {
+			return
+		}
+		var normal types.Point3D
+		if normal, err = calculateNormal(a, b, c); err == nil {
+			triangles = append(triangles, types.Triangle{Normal: normal, V1: a, V2: b, V3: c})
+		}
+	}
+
+	// Relief facing the towers
+	for i := 0; i < cells; i++ {
+		for j := 0; j < cells; j++ {
+			a, b, c, d := front(i, j), front(i+1, j), front(i+1, j+1), front(i, j+1)
+			add(a, b, c)
+			add(a, c, d)
+		}
+	}
+
+	// Edges, from the relief back to the flat rear
+	for i := 0; i < cells; i++ {
+		add(front(i, 0), rear(i, 0), rear(i+1, 0))
+		add(front(i, 0), rear(i+1, 0), front(i+1, 0))
+		add(front(i, cells), front(i+1, cells), rear(i+1, cells))
+		add(front(i, cells), rear(i+1, cells), rear(i, cells))
+	}
+	for j := 0; j < cells; j++ {
+		add(front(0, j), front(0, j+1), rear(0, j+1))
+		add(front(0, j), rear(0, j+1), rear(0, j))
+		add(front(cells, j), rear(cells, j+1), front(cells, j+1))
+		add(front(cells, j), rear(cells, j), rear(cells, j+1))
+	}
+
+	// The flat rear only needs the vertices along its outline, so it is split
+	// into full-width rows, with fans reaching the subdivided bottom and top edges
+	for i := 0; i < cells; i++ {
+		add(rear(0, 1), rear(i+1, 0), rear(i, 0))
+		add(rear(0, cells-1), rear(i, cells), rear(i+1, cells))
+	}
+	add(rear(0, 1), rear(cells, 1), rear(cells, 0))
+	add(rear(0, cells-1), rear(cells, cells), rear(cells, cells-1))
+	for j := 1; j < cells-1; j++ {
+		add(rear(0, j), rear(cells, j+1), rear(cells, j))
+		add(rear(0, j), rear(0, j+1), rear(cells, j+1))
+	}
+
+	if err != nil {
+		return nil, errors.New(errors.STLError, "failed to create avatar panel", err)
+	}
+	return triangles, nil
+}
+
+// luminanceGrid holds the brightness of each pixel of an image, from 0 for
+// black to 1 for white, with transparent areas treated as white.
+type luminanceGrid struct {
+	width, height int
+	values        []float64
+}
+
+// newLuminanceGrid converts an image to a luminance grid.
+func newLuminanceGrid(img image.Image) luminanceGrid {
+	bounds := img.Bounds()
+	g := luminanceGrid{width: bounds.Dx(), height: bounds.Dy()}
+	g.values = make([]float64, g.width*g.height)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			// Colors are premultiplied, so compositing over white adds the missing alpha
+			r, gr, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			lum := (0.299*float64(r) + 0.587*float64(gr) + 0.114*float64(b) + float64(0xffff-a)) / 0xffff
+			g.values[y*g.width+x] = math.Min(1, lum)
+		}
+	}
+	return g
+}
+
+// at returns the luminance at u, v across and down the image, from 0 to 1,
+// interpolated between the centers of the neighboring pixels.
+func (g luminanceGrid) at(u, v float64) float64 {
+	fx := math.Max(0, math.Min(float64(g.width-1), u*float64(g.width)-0.5))
+	fy := math.Max(0, math.Min(float64(g.height-1), v*float64(g.height)-0.5))
+	x, y := int(fx), int(fy)
+	x1, y1 := min(x+1, g.width-1), min(y+1, g.height-1)
+	tx, ty := fx-float64(x), fy-float64(y)
+
+	pixel := func(x, y int) float64 { return g.values[y*g.width+x] }
+	top := pixel(x, y)*(1-tx) + pixel(x1, y)*tx
+	bottom := pixel(x, y1)*(1-tx) + pixel(x1, y1)*tx
+	return top*(1-ty) + bottom*ty
+}
@@ -0,0 +1,164 @@
+package geometry
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// uniformImage returns a square image of a single color.
+func uniformImage(c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// TestLayoutCreateAvatar verifies the panel is a closed solid standing behind the towers
+func TestLayoutCreateAvatar(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+	}{
+		{"white", uniformImage(color.White)},
+		{"black", uniformImage(color.Black)},
+		{"transparent", uniformImage(color.Transparent)},
+		{"gradient", image.NewGray16(image.Rect(0, 0, 3, 5))},
+	}
+
+	layout, err := NewLayout(Config{Avatar: true}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	back := layout.Depth - layout.topInset()
+	size := layout.avatarWidth()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := layout.CreateAvatar(tt.img)
+			if err != nil {
+				t.Fatalf("CreateAvatar() error = %v", err)
+			}
+			if !isClosedMesh(triangles) {
+				t.Fatal("CreateAvatar() mesh is not closed")
+			}
+			for _, tri := range triangles {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if v.Y < back-layout.avatarThickness()-epsilon || v.Y > back+epsilon {
+						t.Fatalf("vertex %v is outside the panel's thickness", v)
+					}
+					if v.Z < -epsilon || v.Z > size+epsilon {
+						t.Fatalf("vertex %v is outside the panel's height", v)
+					}
+					if v.X < (layout.Width-size)/2-epsilon || v.X > (layout.Width+size)/2+epsilon {
+						t.Fatalf("vertex %v is outside the panel's width", v)
+					}
+				}
+			}
+			if volume := meshVolume(triangles); volume <= 0 {
+				t.Errorf("meshVolume() = %g, want positive volume with outward normals", volume)
+			}
+		})
+	}
+}
+
+// TestLayoutCreateAvatarThickness verifies darker images make a thicker panel
+func TestLayoutCreateAvatarThickness(t *testing.T) {
+	layout, err := NewLayout(Config{Avatar: true}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	size := layout.avatarWidth()
+	inner := size - 2*avatarFrameWidth
+	frame := size*size - inner*inner
+
+	for _, tt := range []struct {
+		name      string
+		img       image.Image
+		thickness float64
+	}{
+		{"white", uniformImage(color.White), avatarMinThickness},
+		{"black", uniformImage(color.Black), layout.avatarThickness()},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := layout.CreateAvatar(tt.img)
+			if err != nil {
+				t.Fatalf("CreateAvatar() error = %v", err)
+			}
+			// The grid only approximates the frame's inner edge, so allow a row of cells
+			want := frame*layout.avatarThickness() + inner*inner*tt.thickness
+			tolerance := 4 * size * avatarPixelSize * layout.avatarThickness()
+			if volume := meshVolume(triangles); math.Abs(volume-want) > tolerance {
+				t.Errorf("meshVolume() = %g, want %g ± %g", volume, want, tolerance)
+			}
+		})
+	}
+}
+
+// TestLayoutCreateAvatarErrors verifies missing images are rejected
+func TestLayoutCreateAvatarErrors(t *testing.T) {
+	layout, err := NewLayout(Config{Avatar: true}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if _, err := layout.CreateAvatar(nil); err == nil {
+		t.Error("CreateAvatar(nil) expected error")
+	}
+	if _, err := layout.CreateAvatar(image.NewRGBA(image.Rectangle{})); err == nil {
+		t.Error("CreateAvatar() expected error for an empty image")
+	}
+}
+
+// TestValidateAvatar verifies the panel needs room behind the towers
+func TestValidateAvatar(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"no avatar", Config{Chamfer: 4.5}, false},
+		{"default base", Config{Avatar: true}, false},
+		{"deep base", Config{Avatar: true, BaseDepth: 60}, false},
+		{"large chamfer", Config{Avatar: true, Chamfer: 4.5}, true},
+		{"narrow base", Config{Avatar: true, BaseWidth: 20, BaseDepth: 60, CornerRadius: 8, OmitText: true, OmitLogo: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLayout(tt.cfg, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestLuminanceGrid verifies brightness is read and interpolated across pixels
+func TestLuminanceGrid(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(10, 10, 12, 11))
+	img.Set(10, 10, color.Black)
+	img.Set(11, 10, color.NRGBA{A: 0})
+	grid := newLuminanceGrid(img)
+
+	tests := []struct {
+		u, v float64
+		want float64
+	}{
+		{0, 0.5, 0},
+		{0.25, 0.5, 0},
+		{0.5, 0.5, 0.5},
+		{0.75, 0.5, 1},
+		{1, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := grid.at(tt.u, tt.v); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("at(%g, %g) = %g, want %g", tt.u, tt.v, got, tt.want)
+		}
+	}
+}
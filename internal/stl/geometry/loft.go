@@ -0,0 +1,171 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// cornerSegments is the number of straight segments used to approximate each rounded corner.
+const cornerSegments = 12
+
+// outlineRing is a closed horizontal outline at a fixed height. Points are
+// listed counter-clockwise when viewed from above.
+type outlineRing struct {
+	z      float64
+	points []types.Point3D
+}
+
+// roundedRectRing returns the outline of a rectangle with rounded corners at
+// height z. Every ring has the same number of points regardless of its radius,
+// so rings of different sizes can be joined by createLoft; a zero radius
+// collapses each corner's points onto the corner.
+func roundedRectRing(x0, y0, x1, y1, radius, z float64) outlineRing {
+	radius = math.Max(0, math.Min(radius, math.Min(x1-x0, y1-y0)/2))
+
+	// Corner arc centers, which coincide exactly when the radius fills a side
+	// so that neighboring arcs meet in the same point
+	left, right, front, back := x0+radius, x1-radius, y0+radius, y1-radius
+	if 2*radius >= x1-x0 {
+		left, right = (x0+x1)/2, (x0+x1)/2
+	}
+	if 2*radius >= y1-y0 {
+		front, back = (y0+y1)/2, (y0+y1)/2
+	}
+
+	// Corner arc centers and start angles, counter-clockwise from the front right
+	corners := [4]struct{ cx, cy, start float64 }{
+		{right, front, -math.Pi / 2},
+		{right, back, 0},
+		{left, back, math.Pi / 2},
+		{left, front, math.Pi},
+	}
+
+	ring := outlineRing{z: z, points: make([]types.Point3D, 0, 4*(cornerSegments+1))}
+	for _, c := range corners {
+		for i := 0; i <= cornerSegments; i++ {
+			angle := c.start + float64(i)*(math.Pi/2)/cornerSegments
+			cos, sin := math.Cos(angle), math.Sin(angle)
+			if i == 0 || i == cornerSegments {
+				// The ends of each arc lie exactly on the sides of the rectangle
+				cos, sin = math.Round(cos), math.Round(sin)
+			}
+			ring.points = append(ring.points, types.Point3D{
+				X: c.cx + radius*cos,
+				Y: c.cy + radius*sin,
+				Z: z,
+			})
+		}
+	}
+	return ring
+}
+
+// createLoft generates a closed solid by joining convex outline rings, listed
+// from bottom to top, with side walls and capping the first and last rings.
+// All rings must have the same number of points. Faces that collapse to zero
+// area, such as walls between coincident corner points, are skipped.
+func createLoft(rings []outlineRing) ([]types.Triangle, error) {
+	return createLoftWithHoles(rings, nil)
+}
+
+// createLoftWithHoles generates a loft like createLoft whose bottom cap has the
+// given clockwise holes cut out of it. The holes are left open for the caller
+// to join to other geometry.
+func createLoftWithHoles(rings []outlineRing, bottomHoles []contour) ([]types.Triangle, error) {
+	if len(rings) < 2 {
+		return nil, errors.New(errors.ValidationError, "a loft needs at least two rings", nil)
+	}
+	n := len(rings[0].points)
+	for _, ring := range rings {
+		if len(ring.points) != n || n < 3 {
+			return nil, errors.New(errors.ValidationError, "loft rings must have the same number of points", nil)
+		}
+	}
+
+	var triangles []types.Triangle
+	add := func(a, b, c types.Point3D) error {
+		if isZeroVector(vectorCross(vectorSubtract(b, a), vectorSubtract(c, a))) {
+			return nil
+		}
+		normal, err := calculateNormal(a, b, c)
+		if err != nil {
+			return err
+		}
+		triangles = append(triangles, types.Triangle{Normal: normal, V1: a, V2: b, V3: c})
+		return nil
+	}
+
+	// Side walls between each pair of neighboring rings
+	for r := 0; r+1 < len(rings); r++ {
+		lower, upper := rings[r].points, rings[r+1].points
+		for i := 0; i < n; i++ {
+			j := (i + 1) % n
+			if err := add(lower[i], lower[j], upper[j]); err != nil {
+				return nil, err
+			}
+			if err := add(lower[i], upper[j], upper[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Caps are fanned from their centroid, which lies inside a convex outline
+	bottom, top := rings[0], rings[len(rings)-1]
+	bottomCenter, topCenter := ringCentroid(bottom), ringCentroid(top)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		if len(bottomHoles) == 0 {
+			if err := add(bottomCenter, bottom.points[j], bottom.points[i]); err != nil {
+				return nil, err
+			}
+		}
+		if err := add(topCenter, top.points[i], top.points[j]); err != nil {
+			return nil, err
+		}
+	}
+	if len(bottomHoles) == 0 {
+		return triangles, nil
+	}
+
+	// A bottom cap with holes is triangulated instead, turned to face down
+	caps, err := polygon{outer: ringContour(bottom), holes: bottomHoles}.triangulate()
+	if err != nil {
+		return nil, err
+	}
+	at := func(p point2D) types.Point3D { return types.Point3D{X: p.X, Y: p.Y, Z: bottom.z} }
+	for _, t := range caps {
+		if err := add(at(t[0]), at(t[2]), at(t[1])); err != nil {
+			return nil, err
+		}
+	}
+
+	return triangles, nil
+}
+
+// ringCentroid returns the average of a ring's points.
+func ringCentroid(ring outlineRing) types.Point3D {
+	var c types.Point3D
+	for _, p := range ring.points {
+		c.X += p.X
+		c.Y += p.Y
+	}
+	count := float64(len(ring.points))
+	return types.Point3D{X: c.X / count, Y: c.Y / count, Z: ring.z}
+}
+
+// ringContour returns a ring's outline without its coincident points, which
+// carry no edge of their own.
+func ringContour(ring outlineRing) contour {
+	c := make(contour, 0, len(ring.points))
+	for _, p := range ring.points {
+		q := point2D{X: p.X, Y: p.Y}
+		if len(c) == 0 || q != c[len(c)-1] {
+			c = append(c, q)
+		}
+	}
+	if len(c) > 1 && c[0] == c[len(c)-1] {
+		c = c[:len(c)-1]
+	}
+	return c
+}
@@ -0,0 +1,101 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// meshVolume returns the signed volume enclosed by triangles, which is positive
+// for a closed mesh whose faces are wound to point outward.
+func meshVolume(triangles []types.Triangle) float64 {
+	volume := 0.0
+	for _, t := range triangles {
+		volume += (t.V1.X*(t.V2.Y*t.V3.Z-t.V3.Y*t.V2.Z) -
+			t.V2.X*(t.V1.Y*t.V3.Z-t.V3.Y*t.V1.Z) +
+			t.V3.X*(t.V1.Y*t.V2.Z-t.V2.Y*t.V1.Z)) / 6
+	}
+	return volume
+}
+
+// isClosedMesh reports whether every directed edge is matched by exactly one
+// edge running the opposite way, as in a watertight, consistently wound mesh.
+func isClosedMesh(triangles []types.Triangle) bool {
+	type edge struct{ a, b types.Point3D }
+	edges := make(map[edge]int)
+	for _, t := range triangles {
+		for _, e := range []edge{{t.V1, t.V2}, {t.V2, t.V3}, {t.V3, t.V1}} {
+			edges[e]++
+		}
+	}
+	for e, count := range edges {
+		if count != 1 || edges[edge{e.b, e.a}] != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestRoundedRectRing verifies rounded outlines keep a constant point count
+func TestRoundedRectRing(t *testing.T) {
+	for _, radius := range []float64{0, 2, 100} {
+		ring := roundedRectRing(0, 0, 20, 10, radius, 3)
+		if len(ring.points) != 4*(cornerSegments+1) {
+			t.Errorf("radius %v: ring has %d points, want %d", radius, len(ring.points), 4*(cornerSegments+1))
+		}
+		for _, p := range ring.points {
+			if p.X < -epsilon || p.X > 20+epsilon || p.Y < -epsilon || p.Y > 10+epsilon || p.Z != 3 {
+				t.Errorf("radius %v: point %v lies outside the rectangle", radius, p)
+			}
+		}
+	}
+
+	// Corner points lie on the arc around the corner's center
+	ring := roundedRectRing(0, 0, 20, 10, 2, 0)
+	for _, p := range ring.points[:cornerSegments+1] {
+		if d := math.Hypot(p.X-18, p.Y-2); math.Abs(d-2) > epsilon {
+			t.Errorf("corner point %v is %v from the arc center, want 2", p, d)
+		}
+	}
+}
+
+// TestCreateLoft verifies lofted solids are closed with the expected volume
+func TestCreateLoft(t *testing.T) {
+	tests := []struct {
+		name       string
+		radius     float64
+		wantVolume float64
+	}{
+		{"square corners", 0, 20 * 10 * 5},
+		{"rounded corners", 2, (20*10 - (4-math.Pi)*4) * 5},
+		{"rounded ends", 5, (20*10 - (4-math.Pi)*25) * 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := createLoft([]outlineRing{
+				roundedRectRing(0, 0, 20, 10, tt.radius, 0),
+				roundedRectRing(0, 0, 20, 10, tt.radius, 5),
+			})
+			if err != nil {
+				t.Fatalf("createLoft() error = %v", err)
+			}
+			if !isClosedMesh(triangles) {
+				t.Error("createLoft() mesh is not closed")
+			}
+			// Arcs are approximated by chords, so allow a small shortfall
+			if v := meshVolume(triangles); math.Abs(v-tt.wantVolume) > 0.01*tt.wantVolume {
+				t.Errorf("createLoft() volume = %v, want %v", v, tt.wantVolume)
+			}
+		})
+	}
+
+	if _, err := createLoft([]outlineRing{roundedRectRing(0, 0, 1, 1, 0, 0)}); err == nil {
+		t.Error("createLoft() expected error for a single ring")
+	}
+	mismatched := []outlineRing{roundedRectRing(0, 0, 1, 1, 0, 0), {z: 1, points: make([]types.Point3D, 3)}}
+	if _, err := createLoft(mismatched); err == nil {
+		t.Error("createLoft() expected error for rings of different sizes")
+	}
+}
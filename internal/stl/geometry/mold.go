@@ -0,0 +1,95 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	moldWall    = 5.0 // Thickness of the mold around the base and above the tallest tower
+	moldOverlap = 1.0 // Distance the cuts reach past the faces they meet, so no two cuts share a face
+)
+
+// validateMoldConfig rejects features a mold cannot cast, which only holds
+// the negative of the base and towers.
+func (c Config) validateMoldConfig() error {
+	for _, feature := range []struct {
+		used bool
+		name string
+	}{
+		{c.Hollow > 0, "a hollow base"},
+		{c.LogoFile != "", "a custom logo"},
+		{c.QRCode != "", "a QR code"},
+		{c.Stats, "statistics on the back"},
+		{c.Avatar, "an avatar panel"},
+		{c.Stack, "stacked years"},
+		{c.MonthLabels != "" && c.MonthLabels != MonthLabelsNone, "month labels"},
+		{c.YearLabels, "year labels"},
+		{c.YearDividers, "year dividers"},
+	} {
+		if feature.used {
+			return errors.New(errors.ValidationError, fmt.Sprintf("%s cannot be combined with a mold, which only casts the base and towers", feature.name), nil)
+		}
+	}
+	return nil
+}
+
+// CreateMold generates a block holding the negative of the base and the given
+// towers, for casting the skyline. The towers and base are cut out of the
+// block one at a time, with the bottom of the base cut through the block to
+// open the mold. The mold is turned over, so it stands on its flat top with
+// the opening facing up, ready to be filled.
+func (l Layout) CreateMold(towers [][]types.Triangle) ([]types.Triangle, error) {
+	top := 0.0
+	for _, tower := range towers {
+		_, maxPoint := triangleBounds(tower)
+		top = math.Max(top, maxPoint.Z)
+	}
+	ceiling := top + moldWall
+
+	mold, err := createBox(-moldWall, -moldWall, -l.Height, l.Width+2*moldWall, l.Depth+2*moldWall, ceiling+l.Height)
+	if err != nil {
+		return nil, errors.New(errors.STLError, "failed to create mold block", err)
+	}
+
+	// The base reaches below the block, so cutting it opens the bottom
+	rings := l.baseRings()
+	opening := outlineRing{z: rings[0].z - moldOverlap, points: make([]types.Point3D, len(rings[0].points))}
+	for i, p := range rings[0].points {
+		opening.points[i] = types.Point3D{X: p.X, Y: p.Y, Z: opening.z}
+	}
+	base, err := createLoft(append([]outlineRing{opening}, rings...))
+	if err != nil {
+		return nil, errors.New(errors.STLError, "failed to create mold cavity", err)
+	}
+	mold = subtractSolid(mold, base)
+
+	// Towers reach into the cavity of the base, so they do not share its ceiling
+	for _, tower := range towers {
+		sunk := make([]types.Triangle, len(tower))
+		for i, t := range tower {
+			for _, v := range []*types.Point3D{&t.V1, &t.V2, &t.V3} {
+				if v.Z < csgEpsilon {
+					v.Z = -moldOverlap
+				}
+			}
+			sunk[i] = t
+		}
+		mold = subtractSolid(mold, sunk)
+	}
+
+	mold = sealCuts(mold)
+
+	// Turn the mold over about the X axis, onto the top of the block
+	for i := range mold {
+		t := &mold[i]
+		for _, v := range []*types.Point3D{&t.V1, &t.V2, &t.V3} {
+			v.Y, v.Z = l.Depth-v.Y, ceiling-v.Z
+		}
+		t.Normal.Y, t.Normal.Z = -t.Normal.Y, -t.Normal.Z
+	}
+	return mold, nil
+}
@@ -0,0 +1,165 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// moldTower is a tower cut out of a mold, standing on a cell of the grid.
+type moldTower struct {
+	x, y   int
+	height float64
+}
+
+// TestCreateMold verifies the mold holds exactly the negative of the base and towers
+func TestCreateMold(t *testing.T) {
+	// A few towers of different heights, two of them neighbors
+	few := []moldTower{{3, 2, 5}, {4, 2, 10}, {20, 5, 15}}
+	// Towers touching only along an edge, the taller with a lower neighbor
+	diagonal := []moldTower{{4, 1, 15}, {4, 2, 30.0 / 7}, {5, 0, 90.0 / 7}}
+	// A year of contributions, leaving out days without any
+	var year []moldTower
+	for x := 0; x < 53; x++ {
+		for y := 0; y < 7; y++ {
+			if count := (x*7 + y) * 7919 % 13; count > 4 {
+				year = append(year, moldTower{x, y, float64(5+5*(count%7)) * 3 / 7})
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		cfg    Config
+		towers []moldTower
+	}{
+		{"separate towers", Config{Gap: 0.5}, few},
+		{"fused towers", Config{}, few},
+		{"diagonal neighbors", Config{}, diagonal},
+		{"fused year", Config{}, year},
+		{"rounded chamfered base", Config{CornerRadius: 4, Chamfer: 1}, few},
+		{"cylinder towers", Config{Gap: 0.5, TowerShape: TowerCylinder, TowerSegments: 8}, few},
+		{"cylinder year", Config{Gap: 0.5, TowerShape: TowerCylinder}, year},
+		{"round base", Config{Arrangement: ArrangementRadial}, few},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Mold = true
+			layout, err := NewLayout(tt.cfg, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+
+			var towers [][]types.Triangle
+			towerVolume, top := 0.0, 0.0
+			for _, mt := range tt.towers {
+				top = math.Max(top, mt.height)
+				tower, err := layout.createTower(0, mt.x, mt.y, mt.height)
+				if err != nil {
+					t.Fatalf("createTower() error = %v", err)
+				}
+				towers = append(towers, tower)
+				towerVolume += meshVolume(tower)
+			}
+			slab, err := layout.createSlab()
+			if err != nil {
+				t.Fatalf("createSlab() error = %v", err)
+			}
+
+			mold, err := layout.CreateMold(towers)
+			if err != nil {
+				t.Fatalf("CreateMold() error = %v", err)
+			}
+			if !isWatertight(mold) {
+				t.Error("mold is not watertight")
+			}
+			// Cuts leave no slivers, which slicers report as damaged
+			for _, tri := range mold {
+				for _, e := range [][2]types.Point3D{{tri.V1, tri.V2}, {tri.V2, tri.V3}, {tri.V3, tri.V1}} {
+					if d := vectorSubtract(e[0], e[1]); math.Sqrt(vectorDot(d, d)) < csgWeld {
+						t.Fatalf("mold has an edge from %v to %v shorter than %v", e[0], e[1], csgWeld)
+					}
+				}
+			}
+			block := (layout.Width + 2*moldWall) * (layout.Depth + 2*moldWall) * (layout.Height + top + moldWall)
+			want := block - meshVolume(slab) - towerVolume
+			if got := meshVolume(mold); math.Abs(got-want) > 1e-6*block {
+				t.Errorf("mold volume = %v, want %v", got, want)
+			}
+
+			// Turned over, the mold stands on its flat top with the opening facing up
+			minPoint, maxPoint := triangleBounds(mold)
+			if math.Abs(minPoint.Z) > epsilon || math.Abs(maxPoint.Z-(layout.Height+top+moldWall)) > epsilon {
+				t.Errorf("mold spans Z %v to %v, want 0 to %v", minPoint.Z, maxPoint.Z, layout.Height+top+moldWall)
+			}
+			// The top of the block is open where the bottom of the base was
+			area := func(triangles []types.Triangle, z float64) float64 {
+				total := 0.0
+				for _, tri := range triangles {
+					if tri.V1.Z == z && tri.V2.Z == z && tri.V3.Z == z {
+						total += math.Abs(vectorCross(vectorSubtract(tri.V2, tri.V1), vectorSubtract(tri.V3, tri.V1)).Z) / 2
+					}
+				}
+				return total
+			}
+			rim := (layout.Width+2*moldWall)*(layout.Depth+2*moldWall) - area(slab, -layout.Height)
+			if got := area(mold, maxPoint.Z); math.Abs(got-rim) > 1e-6*rim {
+				t.Errorf("top of the mold has area %v, want %v around the opening", got, rim)
+			}
+		})
+	}
+}
+
+// isWatertight reports whether every edge of the mesh is run along as often
+// one way as the other, as it is in a closed mesh. Unlike isClosedMesh, it
+// allows four faces along an edge, where two cavities of a mold touch only
+// along it.
+func isWatertight(triangles []types.Triangle) bool {
+	type edge struct{ a, b types.Point3D }
+	edges := make(map[edge]int)
+	for _, t := range triangles {
+		for _, e := range []edge{{t.V1, t.V2}, {t.V2, t.V3}, {t.V3, t.V1}} {
+			edges[e]++
+		}
+	}
+	for e, count := range edges {
+		if edges[edge{e.b, e.a}] != count {
+			return false
+		}
+	}
+	return true
+}
+
+// TestConfigValidateMold verifies features a mold cannot cast are rejected
+func TestConfigValidateMold(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"mold", Config{Mold: true}, false},
+		{"mold of a round base", Config{Mold: true, Arrangement: ArrangementSpiral}, false},
+		{"mold of a hollow base", Config{Mold: true, Hollow: 2}, true},
+		{"mold with a QR code", Config{Mold: true, QRCode: "https://github.com"}, true},
+		{"mold of stacked years", Config{Mold: true, Stack: true}, true},
+		{"mold with year labels", Config{Mold: true, YearLabels: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	layout, err := NewLayout(Config{Mold: true}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if layout.Emboss.Text || layout.Emboss.Logo {
+		t.Errorf("mold layout embosses %+v, want nothing on the faces of the base", layout.Emboss)
+	}
+}
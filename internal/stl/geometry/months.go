@@ -0,0 +1,190 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// MonthLabels identifies where month labels are embossed along the front of the base.
+type MonthLabels string
+
+// Supported month label placements.
+const (
+	MonthLabelsNone  MonthLabels = "none"  // No month labels
+	MonthLabelsTop   MonthLabels = "top"   // On the top face, between the front edge and the towers
+	MonthLabelsFront MonthLabels = "front" // Along the top of the front face, above the text and logo
+)
+
+// DefaultMonthLabels is the month label placement used when none is configured.
+const DefaultMonthLabels = MonthLabelsNone
+
+const (
+	monthLabelMaxSize = 2.5 // Largest month label font, in millimeters
+	monthLabelMinSize = 1.5 // Smallest month label font, in millimeters, that still prints legibly
+	monthLabelFill    = 0.8 // Share of the free strip the labels' height may take
+	monthLabelSpacing = 1.0 // Smallest distance between neighboring labels, in millimeters
+)
+
+// monthLabelPlacements lists the supported placements in the order they are presented to users.
+var monthLabelPlacements = []MonthLabels{MonthLabelsNone, MonthLabelsTop, MonthLabelsFront}
+
+// MonthLabelPlacements returns the names of all supported month label placements.
+func MonthLabelPlacements() []string {
+	names := make([]string, len(monthLabelPlacements))
+	for i, m := range monthLabelPlacements {
+		names[i] = string(m)
+	}
+	return names
+}
+
+// ParseMonthLabels converts a user supplied placement name into MonthLabels.
+// Matching is case-insensitive and an empty string selects DefaultMonthLabels.
+func ParseMonthLabels(name string) (MonthLabels, error) {
+	if name == "" {
+		return DefaultMonthLabels, nil
+	}
+	for _, m := range monthLabelPlacements {
+		if strings.EqualFold(name, string(m)) {
+			return m, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported month label placement %q (supported: %s)", name, strings.Join(MonthLabelPlacements(), ", ")), nil)
+}
+
+// monthLabel is a month's name and the week its label starts at.
+type monthLabel struct {
+	name string
+	week int
+}
+
+// monthColumns returns a label for every month in a year of contributions,
+// starting at the first week holding one of its days.
+func monthColumns(weeks [][]types.ContributionDay) []monthLabel {
+	var labels []monthLabel
+	var last time.Month
+	for weekIdx, week := range weeks {
+		for _, day := range week {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil || date.Month() == last {
+				continue
+			}
+			last = date.Month()
+			labels = append(labels, monthLabel{name: strings.ToUpper(date.Format("Jan")), week: weekIdx})
+			break
+		}
+	}
+	return labels
+}
+
+// monthStrip returns the distance from the edge of the base to the start of
+// the strip left free for month labels, and the strip's width, in millimeters.
+// The top strip runs from the front edge of the top face to the towers, and
+// the front strip from the top edge of the front face to the text and logo.
+func (l Layout) monthStrip() (start, width float64) {
+	if l.MonthLabels == MonthLabelsTop {
+		return l.topInset(), l.OffsetY - l.topInset()
+	}
+	free := l.Height - l.Chamfer
+	if l.Emboss.Text {
+		text := max(usernameFontSize, yearFontSize) * l.Width / baseWidthVoxelResolution * textFit(l.Width, l.Height, l.faceMargin())
+		free = math.Min(free, (l.Height-text)/2)
+	}
+	if l.Emboss.Logo {
+		free = math.Min(free, logoTopOffset*l.Height)
+	}
+	return l.Chamfer, free - l.Chamfer
+}
+
+// monthLabelSize returns the font size of the month labels in millimeters.
+func (l Layout) monthLabelSize() float64 {
+	_, width := l.monthStrip()
+	return math.Min(monthLabelMaxSize, width*monthLabelFill)
+}
+
+// validateMonthLabels checks that the month labels fit legibly in their strip.
+func (l Layout) validateMonthLabels() error {
+	if l.MonthLabels == MonthLabelsNone || l.MonthLabels == "" {
+		return nil
+	}
+	if l.monthLabelSize() >= monthLabelMinSize {
+		return nil
+	}
+	_, width := l.monthStrip()
+	need := monthLabelMinSize / monthLabelFill
+	if l.MonthLabels == MonthLabelsTop {
+		return errors.New(errors.ValidationError, fmt.Sprintf("the %s in front of the towers is too narrow for month labels (at least %s needed)", l.Unit.Format(math.Max(0, width)), l.Unit.Format(need)), nil)
+	}
+
+	// The text shrinks to leave room for the strip, while the strip above the
+	// logo grows with the thickness of the base
+	thickness := need + 2*l.Chamfer
+	if l.Emboss.Logo {
+		thickness = math.Max(thickness, (need+l.Chamfer)/logoTopOffset)
+	}
+	return errors.New(errors.ValidationError, fmt.Sprintf("month labels do not fit above the text and logo on the front face of a %s thick base (at least %s needed)", l.Unit.Format(l.Height), l.Unit.Format(thickness)), nil)
+}
+
+// CreateMonthLabels generates a label for every month of the front-most year
+// of contributions, above the week it starts in, along the front edge of the
+// top face or along the top of the front face. Labels that would run into
+// their neighbor or off the base are left out.
+func (l Layout) CreateMonthLabels(weeks [][]types.ContributionDay) ([]types.Triangle, error) {
+	if l.MonthLabels == MonthLabelsNone || l.MonthLabels == "" {
+		return nil, nil
+	}
+	labels := monthColumns(weeks)
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	f, err := loadRasterFont()
+	if err != nil {
+		return nil, err
+	}
+
+	// Labels are drawn in millimeters from the left edge of the base and the
+	// start of the strip, scaled to the drawing's pixels
+	start, width := l.monthStrip()
+	margin := math.Max(l.CornerRadius, l.topInset())
+	draw := func(dc *gg.Context, pixel float64) error {
+		setFontFace(dc, f, l.monthLabelSize()/pixel)
+		right := math.Inf(-1)
+		for _, label := range labels {
+			x, _ := l.TowerPosition(0, label.week, 0)
+			w, _ := dc.MeasureString(label.name)
+			if x < right+monthLabelSpacing || x+w*pixel > l.Width-margin {
+				continue
+			}
+			dc.DrawStringAnchored(label.name, x/pixel, width/2/pixel, 0, 0.5)
+			right = x + w*pixel
+		}
+		return nil
+	}
+
+	if l.MonthLabels == MonthLabelsTop {
+		return embossOnTop(0, start, l.Width, width, func(dc *gg.Context) error {
+			return draw(dc, l.Width/float64(dc.Width()))
+		})
+	}
+
+	// The front face is drawn at its full resolution, supersampled for
+	// faceVoxels, with the strip at its top
+	faceWidthRes := l.FaceResolution * faceSupersample
+	faceHeightRes := int(float64(faceWidthRes) * l.Height / l.Width)
+	pixel := l.Width / float64(faceWidthRes)
+	dc := gg.NewContext(faceWidthRes, faceHeightRes)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetRGB(1, 1, 1)
+	dc.Translate(0, start/pixel)
+	if err := draw(dc, pixel); err != nil {
+		return nil, err
+	}
+	return faceVoxels(dc, l.Width, l.Height, l.FrontSlope())
+}
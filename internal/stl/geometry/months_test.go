@@ -0,0 +1,147 @@
+package geometry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// calendarYear returns the days of a year as weeks starting on Sunday, each
+// day with a single contribution.
+func calendarYear(year int) [][]types.ContributionDay {
+	var weeks [][]types.ContributionDay
+	for date := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		if len(weeks) == 0 || date.Weekday() == time.Sunday {
+			weeks = append(weeks, nil)
+		}
+		weeks[len(weeks)-1] = append(weeks[len(weeks)-1], types.ContributionDay{Date: date.Format("2006-01-02"), ContributionCount: 1})
+	}
+	return weeks
+}
+
+// TestParseMonthLabels verifies month label placement name parsing
+func TestParseMonthLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    MonthLabels
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultMonthLabels, false},
+		{"none", "none", MonthLabelsNone, false},
+		{"top", "top", MonthLabelsTop, false},
+		{"front", "front", MonthLabelsFront, false},
+		{"case insensitive", "TOP", MonthLabelsTop, false},
+		{"unknown", "back", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMonthLabels(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMonthLabels(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMonthLabels(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMonthColumns verifies each month is labeled at the first week holding one of its days
+func TestMonthColumns(t *testing.T) {
+	labels := monthColumns(calendarYear(2024))
+	if len(labels) != 12 {
+		t.Fatalf("monthColumns() returned %d labels, want 12", len(labels))
+	}
+	// 2024 starts on a Monday, so February 1st, a Thursday, is in the fifth week
+	want := map[string]int{"JAN": 0, "FEB": 4, "DEC": 48}
+	for _, label := range labels {
+		if week, ok := want[label.name]; ok && label.week != week {
+			t.Errorf("%s starts at week %d, want %d", label.name, label.week, week)
+		}
+	}
+
+	// Days without dates, such as padding, are skipped
+	if labels := monthColumns([][]types.ContributionDay{{{}, {}}, {{Date: "2024-03-30"}}}); len(labels) != 1 || labels[0] != (monthLabel{"MAR", 1}) {
+		t.Errorf("monthColumns() = %v, want MAR at week 1", labels)
+	}
+}
+
+// TestLayoutCreateMonthLabels verifies labels stay in their strip on the top or front face
+func TestLayoutCreateMonthLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"top", Config{MonthLabels: MonthLabelsTop}},
+		{"top of a chamfered base", Config{MonthLabels: MonthLabelsTop, Chamfer: 1}},
+		{"front at the least thickness", Config{MonthLabels: MonthLabelsFront, BaseHeight: 12.5}},
+		{"front", Config{MonthLabels: MonthLabelsFront, BaseHeight: 13}},
+		{"front without emboss", Config{MonthLabels: MonthLabelsFront, OmitText: true, OmitLogo: true}},
+		{"front above shrunk text", Config{MonthLabels: MonthLabelsFront, OmitLogo: true}},
+		{"front above shrunk text of a chamfered base", Config{MonthLabels: MonthLabelsFront, OmitLogo: true, Chamfer: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.cfg, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			triangles, err := layout.CreateMonthLabels(calendarYear(2024))
+			if err != nil {
+				t.Fatalf("CreateMonthLabels() error = %v", err)
+			}
+			if len(triangles) == 0 {
+				t.Fatal("CreateMonthLabels() returned no triangles")
+			}
+
+			start, width := layout.monthStrip()
+			for _, tri := range triangles {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if v.X < 0 || v.X > layout.Width {
+						t.Fatalf("vertex %v is off the base", v)
+					}
+					if layout.MonthLabels == MonthLabelsTop {
+						if v.Y < start-epsilon || v.Y > start+width+epsilon || v.Z < -epsilon || v.Z > voxelDepth+epsilon {
+							t.Fatalf("vertex %v is outside the strip in front of the towers", v)
+						}
+					} else if -v.Z < start-epsilon || -v.Z > start+width+epsilon || v.Y > epsilon {
+						t.Fatalf("vertex %v is outside the strip at the top of the front face", v)
+					}
+				}
+			}
+		})
+	}
+
+	layout, err := NewLayout(Config{}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if triangles, err := layout.CreateMonthLabels(calendarYear(2024)); err != nil || triangles != nil {
+		t.Errorf("CreateMonthLabels() without labels = %d triangles, %v, want none", len(triangles), err)
+	}
+}
+
+// TestNewLayoutMonthLabelErrors verifies labels that cannot fit legibly are rejected
+func TestNewLayoutMonthLabelErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"front above text and logo on the standard base", Config{MonthLabels: MonthLabelsFront}},
+		{"top without room in front of the towers", Config{MonthLabels: MonthLabelsTop, CellSize: 0.5}},
+		{"round base", Config{MonthLabels: MonthLabelsTop, Arrangement: ArrangementRadial}},
+		{"unknown placement", Config{MonthLabels: "back"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLayout(tt.cfg, 1); err == nil {
+				t.Error("NewLayout() expected error")
+			}
+		})
+	}
+}
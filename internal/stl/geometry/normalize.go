@@ -0,0 +1,48 @@
+package geometry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Normalization identifies which busiest day the towers of each year of a
+// range are scaled against.
+type Normalization string
+
+// Supported normalization modes.
+const (
+	NormalizeGlobal  Normalization = "global"   // Every year against the busiest day of the whole range, so heights compare across years
+	NormalizePerYear Normalization = "per-year" // Each year against its own busiest day, so every year fills the height range
+)
+
+// DefaultNormalization is the normalization mode used when none is configured.
+const DefaultNormalization = NormalizeGlobal
+
+// normalizations lists the supported normalization modes in the order they are presented to users.
+var normalizations = []Normalization{NormalizeGlobal, NormalizePerYear}
+
+// Normalizations returns the names of all supported normalization modes.
+func Normalizations() []string {
+	names := make([]string, len(normalizations))
+	for i, n := range normalizations {
+		names[i] = string(n)
+	}
+	return names
+}
+
+// ParseNormalization converts a user supplied normalization mode name into a
+// Normalization. Matching is case-insensitive and an empty string selects
+// DefaultNormalization.
+func ParseNormalization(name string) (Normalization, error) {
+	if name == "" {
+		return DefaultNormalization, nil
+	}
+	for _, n := range normalizations {
+		if strings.EqualFold(name, string(n)) {
+			return n, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported normalization %q (supported: %s)", name, strings.Join(Normalizations(), ", ")), nil)
+}
@@ -0,0 +1,30 @@
+package geometry
+
+import "testing"
+
+func TestParseNormalization(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Normalization
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultNormalization, false},
+		{"global", "global", NormalizeGlobal, false},
+		{"per year", "per-year", NormalizePerYear, false},
+		{"case insensitive", "Per-Year", NormalizePerYear, false},
+		{"unknown", "per-month", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNormalization(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNormalization(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNormalization(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package geometry
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelMap calls fn for every index below n on a pool of one worker per
+// CPU, and returns the results in index order, so the output is the same
+// however the work was scheduled. When calls fail, the error of the lowest
+// failing index is returned.
+func parallelMap[T any](n int, fn func(i int) (T, error)) ([]T, error) {
+	results := make([]T, n)
+	errs := make([]error, n)
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				results[i], errs[i] = fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
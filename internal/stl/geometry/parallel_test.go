@@ -0,0 +1,69 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestParallelMap verifies results come back in index order with the first failure reported
+func TestParallelMap(t *testing.T) {
+	squares, err := parallelMap(100, func(i int) (int, error) {
+		return i * i, nil
+	})
+	if err != nil {
+		t.Fatalf("parallelMap() error = %v", err)
+	}
+	if len(squares) != 100 {
+		t.Fatalf("parallelMap() returned %d results, want 100", len(squares))
+	}
+	for i, got := range squares {
+		if got != i*i {
+			t.Errorf("result %d = %d, want %d", i, got, i*i)
+		}
+	}
+
+	_, err = parallelMap(10, func(i int) (int, error) {
+		if i%3 == 2 {
+			return 0, fmt.Errorf("index %d", i)
+		}
+		return i, nil
+	})
+	if err == nil || err.Error() != "index 2" {
+		t.Errorf("parallelMap() error = %v, want the error of index 2", err)
+	}
+
+	empty, err := parallelMap(0, func(int) (int, error) { return 0, errors.New("called") })
+	if err != nil || len(empty) != 0 {
+		t.Errorf("parallelMap(0) = %v, %v, want no results and no calls", empty, err)
+	}
+}
+
+// TestCreateContributionObjectsOrder verifies towers are gathered week by week, day by day
+func TestCreateContributionObjectsOrder(t *testing.T) {
+	contributions := make([][]types.ContributionDay, GridSize)
+	for week := range contributions {
+		contributions[week] = make([]types.ContributionDay, 7)
+		for day := range contributions[week] {
+			contributions[week][day].ContributionCount = 1 + (week+day)%3
+		}
+	}
+	layout, err := NewLayout(DefaultConfig(), 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	towers, err := layout.CreateContributionObjects(contributions, 0, 3)
+	if err != nil {
+		t.Fatalf("CreateContributionObjects() error = %v", err)
+	}
+	if len(towers) != GridSize*7 {
+		t.Fatalf("CreateContributionObjects() returned %d towers, want %d", len(towers), GridSize*7)
+	}
+	for i, tower := range towers {
+		if want := fmt.Sprintf("tower-%d-%d", i/7, i%7); tower.Name != want {
+			t.Fatalf("tower %d is %s, want %s", i, tower.Name, want)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+const (
+	polygonEpsilon = 1e-9 // Distance below which outline points are considered coincident
+	curveSegments  = 8    // Number of straight segments approximating each curve of an outline
+)
+
+// point2D is a point on a flat face, with X to the right and Y up.
+type point2D struct {
+	X, Y float64
+}
+
+// contour is a closed outline on a flat face. The last point connects back to
+// the first and is not repeated.
+type contour []point2D
+
+// signedArea returns the area enclosed by the contour, which is positive when
+// its points run counter-clockwise.
+func (c contour) signedArea() float64 {
+	area := 0.0
+	for i, p := range c {
+		q := c[(i+1)%len(c)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	return area / 2
+}
+
+// contains reports whether p lies inside the contour using the even-odd rule.
+func (c contour) contains(p point2D) bool {
+	inside := false
+	for i, a := range c {
+		b := c[(i+1)%len(c)]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// reversed returns a copy of the contour with its points in the opposite order.
+func (c contour) reversed() contour {
+	r := make(contour, len(c))
+	for i, p := range c {
+		r[len(c)-1-i] = p
+	}
+	return r
+}
+
+// cleaned returns a copy of the contour without repeated and collinear points,
+// which carry no area and would stall the triangulation.
+func (c contour) cleaned() contour {
+	points := append(contour(nil), c...)
+	for changed := true; changed && len(points) >= 3; {
+		changed = false
+		for i := 0; i < len(points) && len(points) >= 3; i++ {
+			prev := points[(i+len(points)-1)%len(points)]
+			next := points[(i+1)%len(points)]
+			if samePoint(points[i], prev) || math.Abs(cross2D(prev, points[i], next)) < polygonEpsilon {
+				points = append(points[:i], points[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	if len(points) < 3 {
+		return nil
+	}
+	return points
+}
+
+// polygon is an outer contour running counter-clockwise with the holes cut
+// out of it running clockwise.
+type polygon struct {
+	outer contour
+	holes []contour
+}
+
+// nestContours groups outlines into polygons. Outlines wound the same way as
+// the largest one are outer contours and the others are holes, which belong to
+// the smallest outer contour around them. This matches how font glyphs mark
+// their holes, and is unaffected by outer contours that overlap each other.
+func nestContours(contours []contour) []polygon {
+	var largest float64
+	for _, c := range contours {
+		if a := c.signedArea(); math.Abs(a) > math.Abs(largest) {
+			largest = a
+		}
+	}
+	return groupContours(contours, func(c contour) bool {
+		return (c.signedArea() > 0) == (largest > 0)
+	})
+}
+
+// nestContoursEvenOdd groups outlines into polygons, treating outlines that
+// lie inside an odd number of others as holes regardless of their winding,
+// like SVG's evenodd fill rule. This suits drawings whose holes are not wound
+// consistently, as long as their outlines do not cross.
+func nestContoursEvenOdd(contours []contour) []polygon {
+	return groupContours(contours, func(c contour) bool {
+		depth := 0
+		for _, other := range contours {
+			if len(other) > 0 && &other[0] != &c[0] && other.contains(c[0]) {
+				depth++
+			}
+		}
+		return depth%2 == 0
+	})
+}
+
+// groupContours winds outer contours counter-clockwise and holes clockwise,
+// and gives each hole to the smallest outer contour around it.
+func groupContours(contours []contour, isOuter func(contour) bool) []polygon {
+	var polygons []polygon
+	var holes []contour
+	for _, c := range contours {
+		if len(c) == 0 {
+			continue
+		}
+		outer := isOuter(c)
+		c = c.cleaned()
+		if c == nil {
+			continue
+		}
+		if (c.signedArea() > 0) != outer {
+			c = c.reversed()
+		}
+		if outer {
+			polygons = append(polygons, polygon{outer: c})
+		} else {
+			holes = append(holes, c)
+		}
+	}
+
+	for _, hole := range holes {
+		owner := -1
+		for i, p := range polygons {
+			if p.outer.contains(hole[0]) && (owner < 0 || p.outer.signedArea() < polygons[owner].outer.signedArea()) {
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			polygons[owner].holes = append(polygons[owner].holes, hole)
+		}
+	}
+	return polygons
+}
+
+// triangulate splits the polygon into counter-clockwise triangles by joining
+// each hole to the outer contour and clipping ears off the resulting outline.
+func (p polygon) triangulate() ([][3]point2D, error) {
+	outline := append(contour(nil), p.outer...)
+
+	// Bridge holes from right to left so that each bridge stays clear of the
+	// holes that have not been joined yet. Holes level with each other keep
+	// their order, so the same outline always gives the same triangles.
+	holes := append([]contour(nil), p.holes...)
+	sort.SliceStable(holes, func(i, j int) bool {
+		return holes[i][rightmost(holes[i])].X > holes[j][rightmost(holes[j])].X
+	})
+	for _, hole := range holes {
+		var err error
+		if outline, err = bridgeHole(outline, hole); err != nil {
+			return nil, err
+		}
+	}
+
+	return clipEars(outline), nil
+}
+
+// bridgeHole joins a clockwise hole to a counter-clockwise outline with a pair
+// of coincident edges, following the rightmost point of the hole to the
+// nearest point of the outline it can see.
+func bridgeHole(outline, hole contour) (contour, error) {
+	m := rightmost(hole)
+	origin := hole[m]
+
+	// Cast a ray to the right and find the nearest edge it hits
+	hitX, edge := math.Inf(1), -1
+	for i, a := range outline {
+		b := outline[(i+1)%len(outline)]
+		if (a.Y > origin.Y) == (b.Y > origin.Y) {
+			continue
+		}
+		x := a.X + (origin.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+		if x >= origin.X && x < hitX {
+			hitX, edge = x, i
+		}
+	}
+	if edge < 0 {
+		return nil, errors.New(errors.STLError, "hole lies outside of its outline", nil)
+	}
+
+	// The edge end furthest right is visible unless a reflex point of the
+	// outline lies between it and the ray, in which case the reflex point
+	// closest in angle to the ray is visible instead
+	hit := point2D{hitX, origin.Y}
+	target := edge
+	if b := (edge + 1) % len(outline); outline[b].X > outline[edge].X {
+		target = b
+	}
+	candidate := outline[target]
+	bestCos := math.Inf(-1)
+	for i, q := range outline {
+		if samePoint(hit, candidate) || samePoint(q, candidate) || !isReflex(outline, i) || !inTriangle(q, origin, hit, candidate) {
+			continue
+		}
+		dx, dy := q.X-origin.X, q.Y-origin.Y
+		if cos := dx / math.Hypot(dx, dy); cos > bestCos {
+			bestCos, target = cos, i
+		}
+	}
+
+	merged := make(contour, 0, len(outline)+len(hole)+2)
+	merged = append(merged, outline[:target+1]...)
+	merged = append(merged, hole[m:]...)
+	merged = append(merged, hole[:m+1]...)
+	merged = append(merged, outline[target:]...)
+	return merged, nil
+}
+
+// clipEars triangulates a counter-clockwise outline, which may touch itself
+// along hole bridges, by repeatedly cutting off a convex corner that holds no
+// other point of the outline.
+func clipEars(outline contour) [][3]point2D {
+	points := append(contour(nil), outline...)
+	triangles := make([][3]point2D, 0, len(points))
+
+	for len(points) > 3 {
+		ear := -1
+		for i := range points {
+			if isEar(points, i) {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			// Rounding can leave an outline without a clean ear; cut the most
+			// convex corner so the triangulation always finishes
+			best := math.Inf(-1)
+			for i := range points {
+				prev, next := points[(i+len(points)-1)%len(points)], points[(i+1)%len(points)]
+				if c := cross2D(prev, points[i], next); c > best {
+					best, ear = c, i
+				}
+			}
+		}
+
+		prev, next := points[(ear+len(points)-1)%len(points)], points[(ear+1)%len(points)]
+		if cross2D(prev, points[ear], next) > polygonEpsilon {
+			triangles = append(triangles, [3]point2D{prev, points[ear], next})
+		}
+		points = append(points[:ear], points[ear+1:]...)
+	}
+	if cross2D(points[0], points[1], points[2]) > polygonEpsilon {
+		triangles = append(triangles, [3]point2D{points[0], points[1], points[2]})
+	}
+	return triangles
+}
+
+// isEar reports whether the corner at i is convex and no other point of the
+// outline lies inside the triangle it forms with its neighbors.
+func isEar(points contour, i int) bool {
+	prev, cur, next := points[(i+len(points)-1)%len(points)], points[i], points[(i+1)%len(points)]
+	if cross2D(prev, cur, next) <= polygonEpsilon {
+		return false
+	}
+	for _, q := range points {
+		if samePoint(q, prev) || samePoint(q, cur) || samePoint(q, next) {
+			continue
+		}
+		if inTriangle(q, prev, cur, next) {
+			return false
+		}
+	}
+	return true
+}
+
+// isReflex reports whether the corner at i of a counter-clockwise outline turns clockwise.
+func isReflex(points contour, i int) bool {
+	prev, next := points[(i+len(points)-1)%len(points)], points[(i+1)%len(points)]
+	return cross2D(prev, points[i], next) < 0
+}
+
+// rightmost returns the index of the contour's point furthest right.
+func rightmost(c contour) int {
+	best := 0
+	for i, p := range c {
+		if p.X > c[best].X {
+			best = i
+		}
+	}
+	return best
+}
+
+// inTriangle reports whether p lies inside or on the edges of the triangle abc,
+// regardless of its winding.
+func inTriangle(p, a, b, c point2D) bool {
+	d1, d2, d3 := cross2D(a, b, p), cross2D(b, c, p), cross2D(c, a, p)
+	hasNeg := d1 < -polygonEpsilon || d2 < -polygonEpsilon || d3 < -polygonEpsilon
+	hasPos := d1 > polygonEpsilon || d2 > polygonEpsilon || d3 > polygonEpsilon
+	return !(hasNeg && hasPos)
+}
+
+// cross2D returns twice the signed area of the triangle abc, which is positive
+// when its points run counter-clockwise.
+func cross2D(a, b, c point2D) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// samePoint reports whether two points coincide.
+func samePoint(a, b point2D) bool {
+	return math.Abs(a.X-b.X) < polygonEpsilon && math.Abs(a.Y-b.Y) < polygonEpsilon
+}
+
+// appendQuadratic flattens a quadratic Bézier curve from p0 into curveSegments
+// straight segments, appending every point but p0 itself.
+func appendQuadratic(c contour, p0, p1, p2 point2D) contour {
+	for i := 1; i <= curveSegments; i++ {
+		t := float64(i) / curveSegments
+		u := 1 - t
+		c = append(c, point2D{
+			X: u*u*p0.X + 2*u*t*p1.X + t*t*p2.X,
+			Y: u*u*p0.Y + 2*u*t*p1.Y + t*t*p2.Y,
+		})
+	}
+	return c
+}
+
+// appendCubic flattens a cubic Bézier curve from p0 into curveSegments
+// straight segments, appending every point but p0 itself.
+func appendCubic(c contour, p0, p1, p2, p3 point2D) contour {
+	for i := 1; i <= curveSegments; i++ {
+		t := float64(i) / curveSegments
+		u := 1 - t
+		c = append(c, point2D{
+			X: u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+			Y: u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+		})
+	}
+	return c
+}
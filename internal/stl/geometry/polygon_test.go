@@ -0,0 +1,102 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// square returns a counter-clockwise square contour.
+func square(x, y, size float64) contour {
+	return contour{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}}
+}
+
+// triangleArea returns the total area of counter-clockwise triangles.
+func triangleArea(triangles [][3]point2D) float64 {
+	area := 0.0
+	for _, t := range triangles {
+		area += cross2D(t[0], t[1], t[2]) / 2
+	}
+	return area
+}
+
+// TestContourSignedArea verifies winding is reflected in the sign of the area
+func TestContourSignedArea(t *testing.T) {
+	c := square(0, 0, 2)
+	if got := c.signedArea(); got != 4 {
+		t.Errorf("signedArea() = %v, want 4", got)
+	}
+	if got := c.reversed().signedArea(); got != -4 {
+		t.Errorf("reversed signedArea() = %v, want -4", got)
+	}
+}
+
+// TestContourCleaned verifies repeated and collinear points are dropped
+func TestContourCleaned(t *testing.T) {
+	c := contour{{0, 0}, {1, 0}, {1, 0}, {2, 0}, {2, 2}, {0, 2}}
+	if got := c.cleaned(); len(got) != 4 {
+		t.Errorf("cleaned() = %v, want the 4 corners", got)
+	}
+	if got := (contour{{0, 0}, {1, 1}, {2, 2}}).cleaned(); got != nil {
+		t.Errorf("cleaned() = %v for a flat contour, want nil", got)
+	}
+}
+
+// TestNestContours verifies holes are told apart by winding and given to their outline
+func TestNestContours(t *testing.T) {
+	// Clockwise outlines like a TrueType glyph, with a counter-clockwise hole
+	outerA := square(0, 0, 10).reversed()
+	hole := square(2, 2, 4)
+	outerB := square(20, 0, 5).reversed()
+
+	polygons := nestContours([]contour{hole, outerA, outerB})
+	if len(polygons) != 2 {
+		t.Fatalf("nestContours() returned %d polygons, want 2", len(polygons))
+	}
+	for _, p := range polygons {
+		if p.outer.signedArea() <= 0 {
+			t.Errorf("outer contour %v is not counter-clockwise", p.outer)
+		}
+		for _, h := range p.holes {
+			if h.signedArea() >= 0 {
+				t.Errorf("hole %v is not clockwise", h)
+			}
+		}
+	}
+	if len(polygons[0].holes) != 1 || len(polygons[1].holes) != 0 {
+		t.Errorf("hole assigned to the wrong outline: %v", polygons)
+	}
+}
+
+// TestPolygonTriangulate verifies triangulations cover the polygon exactly
+func TestPolygonTriangulate(t *testing.T) {
+	tests := []struct {
+		name     string
+		poly     polygon
+		wantArea float64
+	}{
+		{"square", polygon{outer: square(0, 0, 3)}, 9},
+		{"concave", polygon{outer: contour{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 4}, {0, 4}}}, 7},
+		{"one hole", polygon{outer: square(0, 0, 10), holes: []contour{square(3, 3, 4).reversed()}}, 84},
+		{"two holes", polygon{outer: square(0, 0, 10), holes: []contour{
+			square(1, 1, 2).reversed(),
+			square(6, 5, 3).reversed(),
+		}}, 87},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := tt.poly.triangulate()
+			if err != nil {
+				t.Fatalf("triangulate() error = %v", err)
+			}
+			for _, tri := range triangles {
+				if cross2D(tri[0], tri[1], tri[2]) <= 0 {
+					t.Errorf("triangle %v is not counter-clockwise", tri)
+				}
+			}
+			if got := triangleArea(triangles); math.Abs(got-tt.wantArea) > epsilon {
+				t.Errorf("triangulated area = %v, want %v", got, tt.wantArea)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/qr"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	qrQuietZone     = 1   // Modules of flat face left clear around the QR code
+	qrMinModuleSize = 0.4 // Smallest module, in millimeters, that a typical 0.4mm nozzle prints cleanly
+)
+
+// qrFaceSize returns the side of the largest square on the flat part of the
+// back face, clear of the chamfers, rounded corners and sloped edges.
+func (l Layout) qrFaceSize() float64 {
+	faceHeight := l.Height - 2*l.Chamfer
+	faceWidth := l.Width - 2*math.Max(l.CornerRadius, l.topInset())
+	return math.Min(faceHeight, faceWidth)
+}
+
+// qrThickness returns the thinnest base, in millimeters, whose back face fits
+// a QR code of text with modules large enough to print, between chamfers of
+// the given size.
+func qrThickness(text string, chamfer float64) (float64, error) {
+	code, err := qr.Encode(text)
+	if err != nil {
+		return 0, err
+	}
+	return float64(code.Size+2*qrQuietZone)*qrMinModuleSize + 2*chamfer, nil
+}
+
+// validateQRCode checks that the modules of the layout's QR code come out
+// large enough to print.
+func (l Layout) validateQRCode() error {
+	if l.QRCode == "" {
+		return nil
+	}
+	code, err := qr.Encode(l.QRCode)
+	if err != nil {
+		return err
+	}
+	modules := float64(code.Size + 2*qrQuietZone)
+	if moduleSize := l.qrFaceSize() / modules; moduleSize < qrMinModuleSize {
+		need, err := qrThickness(l.QRCode, l.Chamfer)
+		if err != nil {
+			return err
+		}
+		return errors.New(errors.ValidationError, fmt.Sprintf("QR code modules of %s are too small to print on a %s thick base (at least %s needed)", l.Unit.Format(moduleSize), l.Unit.Format(l.Height), l.Unit.Format(need)), nil)
+	}
+	return nil
+}
+
+// qrPlacement returns the smallest X and the top of the QR code on the back
+// face, and the size of its modules, all in millimeters. The code is centered,
+// or kept to the right as seen from behind when text shares the back face.
+func (l Layout) qrPlacement(code *qr.Code) (x0, top, moduleSize float64) {
+	moduleSize = l.qrFaceSize() / float64(code.Size+2*qrQuietZone)
+	span := float64(code.Size) * moduleSize
+	x0 = (l.Width - span) / 2
+	if l.Stats {
+		x0 = l.backClearance() + qrQuietZone*moduleSize
+	}
+	return x0, -l.Height/2 + span/2, moduleSize
+}
+
+// CreateQRCode generates the layout's QR code on the back face of the base,
+// with a voxel coming out of the face for every dark module. The code is
+// mirrored along X so it reads correctly when viewed from behind.
+func (l Layout) CreateQRCode() ([]types.Triangle, error) {
+	if l.QRCode == "" {
+		return nil, nil
+	}
+	code, err := qr.Encode(l.QRCode)
+	if err != nil {
+		return nil, err
+	}
+
+	x0, top, moduleSize := l.qrPlacement(code)
+	left := x0 + float64(code.Size)*moduleSize // Seen from behind, the code's left edge is at the larger X
+	slope := l.FrontSlope()
+
+	// Modules on a sloped face step in row by row, so only merge along rows
+	rects := mergePixels(code.Size, code.Size, code.Dark, slope == 0)
+
+	var triangles []types.Triangle
+	for _, r := range rects {
+		// Set the voxel against the face at its top edge, where a sloped
+		// face leans furthest in
+		z := top - float64(r.y+r.h)*moduleSize
+		faceY := l.Depth - slope*(l.Height+top-float64(r.y)*moduleSize)
+		voxel, err := CreateCube(left-float64(r.x+r.w)*moduleSize, faceY, z, float64(r.w)*moduleSize, voxelDepth, float64(r.h)*moduleSize)
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create cube", err)
+		}
+		triangles = append(triangles, voxel...)
+	}
+	return triangles, nil
+}
@@ -0,0 +1,153 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/qr"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestLayoutCreateQRCode verifies the QR code is embossed on the back face and mirrored
+func TestLayoutCreateQRCode(t *testing.T) {
+	const url = "https://github.com/octocat"
+	layout, err := NewLayout(Config{BaseHeight: 20, QRCode: url}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	code, err := qr.Encode(url)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	triangles, err := layout.CreateQRCode()
+	if err != nil {
+		t.Fatalf("CreateQRCode() error = %v", err)
+	}
+	dark := 0
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Dark(x, y) {
+				dark++
+			}
+		}
+	}
+	moduleSize := layout.qrFaceSize() / float64(code.Size+2*qrQuietZone)
+	want := float64(dark) * moduleSize * moduleSize * voxelDepth
+	if volume := meshVolume(triangles); math.Abs(volume-want) > 1e-6 {
+		t.Errorf("CreateQRCode() volume = %g, want %g for %d dark modules", volume, want, dark)
+	}
+	if len(triangles) >= dark*12 {
+		t.Errorf("CreateQRCode() returned %d triangles, want fewer than one voxel per dark module", len(triangles))
+	}
+	minX, maxX, maxZ := math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Y < layout.Depth-epsilon || v.Y > layout.Depth+voxelDepth+epsilon {
+				t.Fatalf("vertex %v is not on the back face at y=%g", v, layout.Depth)
+			}
+			if v.Z < -layout.Height-epsilon || v.Z > epsilon {
+				t.Fatalf("vertex %v is outside the base's height", v)
+			}
+			minX, maxX, maxZ = math.Min(minX, v.X), math.Max(maxX, v.X), math.Max(maxZ, v.Z)
+		}
+	}
+
+	// The symbol is centered, with its quiet zone inside the face
+	span := float64(code.Size) * moduleSize
+	if math.Abs(minX-(layout.Width-span)/2) > epsilon || math.Abs(maxX-(layout.Width+span)/2) > epsilon {
+		t.Errorf("QR code spans x=%g..%g, want it centered on a %gmm wide base", minX, maxX, layout.Width)
+	}
+	if maxZ > -moduleSize+epsilon {
+		t.Errorf("QR code reaches z=%g, want a quiet zone of %gmm below the top", maxZ, moduleSize)
+	}
+
+	// Seen from behind, the top left finder is at the larger X
+	finderX, finderZ := (layout.Width+span)/2-moduleSize/2, -layout.Height/2+span/2-moduleSize/2
+	found := false
+	for _, tri := range triangles {
+		c := tri.V1
+		if math.Abs(c.X-finderX) <= moduleSize/2+epsilon && math.Abs(c.Z-finderZ) <= moduleSize/2+epsilon {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("expected the top left finder module at the right of the back face")
+	}
+}
+
+// TestLayoutCreateQRCodeSloped verifies the QR code follows a sloped back face
+func TestLayoutCreateQRCodeSloped(t *testing.T) {
+	layout, err := NewLayout(Config{BaseHeight: 20, BaseStyle: BaseSloped, QRCode: "https://github.com/octocat"}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	triangles, err := layout.CreateQRCode()
+	if err != nil {
+		t.Fatalf("CreateQRCode() error = %v", err)
+	}
+	slope := layout.FrontSlope()
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			// Every voxel reaches the face at its top edge and comes out past it
+			face := layout.Depth - slope*(layout.Height+v.Z)
+			if v.Y < face-slope*layout.Height-epsilon || v.Y > face+voxelDepth+epsilon {
+				t.Fatalf("vertex %v is detached from the sloped back face at y=%g", v, face)
+			}
+		}
+	}
+}
+
+// TestValidateQRCode verifies QR codes must have printable modules
+func TestValidateQRCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"no QR code", Config{}, false},
+		{"thick base", Config{BaseHeight: 20, QRCode: "https://github.com/octocat"}, false},
+		{"default base thickened", Config{QRCode: "https://github.com/octocat"}, false},
+		{"given base too thin", Config{BaseHeight: BaseHeight, QRCode: "https://github.com/octocat"}, true},
+		{"chamfer takes the room", Config{BaseHeight: 11, Chamfer: 1, QRCode: "https://github.com/octocat"}, true},
+		{"short text fits", Config{BaseHeight: 10, QRCode: "gh"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLayout(tt.cfg, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewLayoutQRCodeThickness verifies only a base left at its default
+// thickness is thickened to fit the QR code
+func TestNewLayoutQRCodeThickness(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want float64
+	}{
+		{"no QR code", Config{}, BaseHeight},
+		{"short text fits", Config{QRCode: "gh"}, BaseHeight},
+		{"profile link", Config{QRCode: "https://github.com/octocat"}, 27 * qrMinModuleSize},
+		{"profile link between chamfers", Config{QRCode: "https://github.com/octocat", Chamfer: 1}, 27*qrMinModuleSize + 2},
+		{"given thickness", Config{BaseHeight: 20, QRCode: "https://github.com/octocat"}, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.cfg, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			if math.Abs(layout.Height-tt.want) > 1e-9 {
+				t.Errorf("NewLayout() height = %v, want %v", layout.Height, tt.want)
+			}
+		})
+	}
+}
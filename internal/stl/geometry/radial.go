@@ -0,0 +1,40 @@
+package geometry
+
+import "math"
+
+// radialArrangement places the weeks around a round base like the hours of a
+// clock, starting at the back and running clockwise seen from above, with the
+// days of each week radiating outward. Each year adds seven rings of days, with
+// the most recent year on the outside.
+type radialArrangement struct{}
+
+// hub returns the radius of the free center, where each week's innermost tower
+// spans one pitch around the circle.
+func (radialArrangement) hub() float64 {
+	return float64(GridSize) / (2 * math.Pi)
+}
+
+// reach returns the radius of the outermost ring of towers, plus one gap.
+func (r radialArrangement) reach(yearCount int) float64 {
+	return r.hub() + float64(7*yearCount)
+}
+
+// footprint returns the wedge taken by a day's tower, with its curved sides
+// approximated by straight edges. Neighboring weeks are separated by the gap
+// measured across the middle of the wedge.
+func (radialArrangement) footprint(l Layout, yearIndex, weekIdx, dayIdx int) [4]point2D {
+	ring := (l.YearCount-1-yearIndex)*7 + dayIdx
+	inner := l.HubRadius + float64(ring)*(l.CellSize+l.Gap)
+	outer := inner + l.CellSize
+
+	step := 2 * math.Pi / float64(GridSize)
+	halfGap := l.Gap / (inner + outer)
+	start := math.Pi/2 - float64(weekIdx)*step - halfGap
+	end := math.Pi/2 - float64(weekIdx+1)*step + halfGap
+
+	cx, cy := l.Width/2, l.Depth/2
+	at := func(radius, angle float64) point2D {
+		return point2D{X: cx + radius*math.Cos(angle), Y: cy + radius*math.Sin(angle)}
+	}
+	return [4]point2D{at(inner, end), at(outer, end), at(outer, start), at(inner, start)}
+}
@@ -0,0 +1,116 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// polar returns the distance and angle of a point from the center of a round base.
+func polar(l Layout, p point2D) (radius, angle float64) {
+	dx, dy := p.X-l.Width/2, p.Y-l.Depth/2
+	return math.Hypot(dx, dy), math.Atan2(dy, dx)
+}
+
+// TestRadialFootprint verifies weeks run clockwise from the back and days radiate outward
+func TestRadialFootprint(t *testing.T) {
+	layout, err := NewLayout(Config{Arrangement: ArrangementRadial}, 2)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	radial := radialArrangement{}
+	step := 2 * math.Pi / float64(GridSize)
+
+	// The first week starts at the back of the base, 12 o'clock seen from the front
+	first := radial.footprint(layout, 0, 0, 0)
+	if _, angle := polar(layout, first[3]); math.Abs(angle-math.Pi/2) > epsilon {
+		t.Errorf("first week starts at %v rad, want %v", angle, math.Pi/2)
+	}
+	if _, angle := polar(layout, first[0]); math.Abs(angle-(math.Pi/2-step)) > epsilon {
+		t.Errorf("first week ends at %v rad, want %v", angle, math.Pi/2-step)
+	}
+
+	// Without a gap, neighboring weeks and days share their edges
+	next := radial.footprint(layout, 0, 1, 0)
+	if dist(first[0], next[3]) > epsilon || dist(first[1], next[2]) > epsilon {
+		t.Errorf("weeks 0 and 1 do not share an edge: %v, %v", first, next)
+	}
+	outward := radial.footprint(layout, 0, 0, 1)
+	if dist(first[1], outward[0]) > epsilon || dist(first[2], outward[3]) > epsilon {
+		t.Errorf("days 0 and 1 do not share an edge: %v, %v", first, outward)
+	}
+
+	// Older years are further in, with the first day of the older year at the hub
+	if inner, _ := polar(layout, radial.footprint(layout, 1, 0, 0)[0]); math.Abs(inner-layout.HubRadius) > epsilon {
+		t.Errorf("older year starts at radius %v, want the hub radius %v", inner, layout.HubRadius)
+	}
+	if inner, _ := polar(layout, first[0]); math.Abs(inner-layout.HubRadius-7*layout.CellSize) > epsilon {
+		t.Errorf("recent year starts at radius %v, want %v", inner, layout.HubRadius+7*layout.CellSize)
+	}
+	if outer, _ := polar(layout, radial.footprint(layout, 0, 0, 6)[1]); math.Abs(outer-(layout.Width/2-layout.OffsetX)) > epsilon {
+		t.Errorf("outermost towers reach radius %v, want %v", outer, layout.Width/2-layout.OffsetX)
+	}
+}
+
+// TestRadialFootprintGap verifies the gap separates neighboring towers
+func TestRadialFootprintGap(t *testing.T) {
+	layout, err := NewLayout(Config{Arrangement: ArrangementRadial, Gap: 0.5}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	radial := radialArrangement{}
+	a, b := radial.footprint(layout, 0, 3, 2), radial.footprint(layout, 0, 4, 2)
+	mid := func(p, q point2D) point2D { return point2D{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2} }
+	// Across the middle of the wedges, the towers are a gap apart
+	if got := dist(mid(a[0], a[1]), mid(b[3], b[2])); math.Abs(got-layout.Gap) > 0.01 {
+		t.Errorf("gap between weeks = %v, want %v", got, layout.Gap)
+	}
+	c := radial.footprint(layout, 0, 3, 3)
+	if inner, _ := polar(layout, c[0]); math.Abs(inner-layout.HubRadius-3*(layout.CellSize+layout.Gap)) > epsilon {
+		t.Errorf("day 3 starts at radius %v, want %v", inner, layout.HubRadius+3*(layout.CellSize+layout.Gap))
+	}
+}
+
+// TestLayoutCreateRadialTowers verifies radial towers are closed wedges standing on the base
+func TestLayoutCreateRadialTowers(t *testing.T) {
+	layout, err := NewLayout(Config{Arrangement: ArrangementRadial}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	week := []types.ContributionDay{{ContributionCount: 1}, {ContributionCount: 4}, {}, {ContributionCount: 2}, {}, {}, {ContributionCount: 3}}
+	contributions := make([][]types.ContributionDay, GridSize)
+	for i := range contributions {
+		contributions[i] = week
+	}
+	towers, err := layout.CreateContributionObjects(contributions, 0, 4)
+	if err != nil {
+		t.Fatalf("CreateContributionObjects() error = %v", err)
+	}
+	if len(towers) != 4*GridSize {
+		t.Fatalf("CreateContributionObjects() returned %d towers, want %d", len(towers), 4*GridSize)
+	}
+	for _, tower := range towers {
+		if !isClosedMesh(tower.Mesh.Triangles()) {
+			t.Fatalf("%s is not closed", tower.Name)
+		}
+		if volume := meshVolume(tower.Mesh.Triangles()); volume <= 0 {
+			t.Fatalf("%s has volume %v, want outward faces", tower.Name, volume)
+		}
+		for _, tri := range tower.Mesh.Triangles() {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				if r, _ := polar(layout, point2D{X: v.X, Y: v.Y}); r < layout.HubRadius-epsilon || r > layout.Width/2-layout.OffsetX+epsilon {
+					t.Fatalf("%s vertex %v is outside the ring of towers", tower.Name, v)
+				}
+				if v.Z < -epsilon {
+					t.Fatalf("%s vertex %v is below the top face", tower.Name, v)
+				}
+			}
+		}
+	}
+}
+
+// dist returns the distance between two points.
+func dist(a, b point2D) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
@@ -0,0 +1,62 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Scale identifies how contribution counts are mapped onto tower heights.
+type Scale string
+
+// Supported height scaling modes.
+const (
+	ScaleLinear Scale = "linear" // Height proportional to the contribution count
+	ScaleSqrt   Scale = "sqrt"   // Height proportional to the square root of the count
+	ScaleLog    Scale = "log"    // Height proportional to the logarithm of the count
+)
+
+// DefaultScale is the scaling mode used when none is configured.
+const DefaultScale = ScaleSqrt
+
+// scales lists the supported scaling modes in the order they are presented to users.
+var scales = []Scale{ScaleLinear, ScaleSqrt, ScaleLog}
+
+// Scales returns the names of all supported scaling modes.
+func Scales() []string {
+	names := make([]string, len(scales))
+	for i, s := range scales {
+		names[i] = string(s)
+	}
+	return names
+}
+
+// ParseScale converts a user supplied scaling mode name into a Scale.
+// Matching is case-insensitive and an empty string selects DefaultScale.
+func ParseScale(name string) (Scale, error) {
+	if name == "" {
+		return DefaultScale, nil
+	}
+	for _, s := range scales {
+		if strings.EqualFold(name, string(s)) {
+			return s, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported height scale %q (supported: %s)", name, strings.Join(Scales(), ", ")), nil)
+}
+
+// fraction maps count onto [0, 1] relative to maxCount.
+// Both count and maxCount must be positive.
+func (s Scale) fraction(count, maxCount int) float64 {
+	switch s {
+	case ScaleLinear:
+		return float64(count) / float64(maxCount)
+	case ScaleLog:
+		// log1p keeps a single contribution above zero and maxCount at exactly one
+		return math.Log1p(float64(count)) / math.Log1p(float64(maxCount))
+	default:
+		return math.Sqrt(float64(count)) / math.Sqrt(float64(maxCount))
+	}
+}
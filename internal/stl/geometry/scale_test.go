@@ -0,0 +1,78 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// TestParseScale verifies scaling mode name parsing
+func TestParseScale(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Scale
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultScale, false},
+		{"linear", "linear", ScaleLinear, false},
+		{"sqrt", "sqrt", ScaleSqrt, false},
+		{"log", "log", ScaleLog, false},
+		{"case insensitive", "LOG", ScaleLog, false},
+		{"unknown", "cubic", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScale(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScale(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseScale(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestScaleTowerHeight verifies each scaling mode maps counts onto the height range
+func TestScaleTowerHeight(t *testing.T) {
+	heightRange := MaxHeight - MinHeight
+	tests := []struct {
+		name     string
+		scale    Scale
+		count    int
+		maxCount int
+		want     float64
+	}{
+		{"linear zero", ScaleLinear, 0, 100, 0},
+		{"linear quarter", ScaleLinear, 25, 100, MinHeight + heightRange*0.25},
+		{"linear full", ScaleLinear, 100, 100, MaxHeight},
+		{"sqrt quarter", ScaleSqrt, 25, 100, MinHeight + heightRange*0.5},
+		{"log full", ScaleLog, 100, 100, MaxHeight},
+		{"log midpoint", ScaleLog, 9, 99, MinHeight + heightRange*0.5},
+		{"count above max clamps", ScaleLinear, 200, 100, MaxHeight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(Config{Scale: tt.scale}, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			got := layout.TowerHeight(tt.count, tt.maxCount)
+			if math.Abs(got-tt.want) > epsilon {
+				t.Errorf("TowerHeight(%d, %d) = %v, want %v", tt.count, tt.maxCount, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestScaleOrdering verifies log lifts low counts above sqrt, which lifts them above linear
+func TestScaleOrdering(t *testing.T) {
+	linear := ScaleLinear.fraction(5, 500)
+	sqrt := ScaleSqrt.fraction(5, 500)
+	log := ScaleLog.fraction(5, 500)
+	if !(linear < sqrt && sqrt < log) {
+		t.Errorf("expected linear < sqrt < log for low counts, got %v, %v, %v", linear, sqrt, log)
+	}
+}
@@ -64,12 +64,50 @@ func createBox(x, y, z, width, height, depth float64) ([]types.Triangle, error)
 		return nil, errors.New(errors.ValidationError, "negative dimensions not allowed", nil)
 	}
 
+	return createHexahedron([8]types.Point3D{
+		{X: x, Y: y, Z: z},
+		{X: x + width, Y: y, Z: z},
+		{X: x + width, Y: y + height, Z: z},
+		{X: x, Y: y + height, Z: z},
+		{X: x, Y: y, Z: z + depth},
+		{X: x + width, Y: y, Z: z + depth},
+		{X: x + width, Y: y + height, Z: z + depth},
+		{X: x, Y: y + height, Z: z + depth},
+	})
+}
+
+// createFrustum generates triangles for a box whose top face is inset by the
+// given amount on all four sides, giving it sloped walls. The parameters
+// follow createBox, with the bottom face spanning the full width and height.
+func createFrustum(x, y, z, width, height, depth, inset float64) ([]types.Triangle, error) {
+	if width < 0 || height < 0 || depth < 0 {
+		return nil, errors.New(errors.ValidationError, "negative dimensions not allowed", nil)
+	}
+	if inset < 0 || 2*inset >= width || 2*inset >= height {
+		return nil, errors.New(errors.ValidationError, "inset must leave a top face", nil)
+	}
+
+	return createHexahedron([8]types.Point3D{
+		{X: x, Y: y, Z: z},
+		{X: x + width, Y: y, Z: z},
+		{X: x + width, Y: y + height, Z: z},
+		{X: x, Y: y + height, Z: z},
+		{X: x + inset, Y: y + inset, Z: z + depth},
+		{X: x + width - inset, Y: y + inset, Z: z + depth},
+		{X: x + width - inset, Y: y + height - inset, Z: z + depth},
+		{X: x + inset, Y: y + height - inset, Z: z + depth},
+	})
+}
+
+// createHexahedron generates triangles for a six-sided solid from its eight corners.
+// Corners 0-3 are the bottom face and 4-7 the top face, each listed counter-clockwise
+// from the front left when viewed from above, so every face normal points outward.
+func createHexahedron(vertices [8]types.Point3D) ([]types.Triangle, error) {
 	// Pre-allocate with exact capacity needed
 	const facesCount = 6
 	const trianglesPerFace = 2
 	triangles := make([]types.Triangle, 0, facesCount*trianglesPerFace)
 
-	vertices := make([]types.Point3D, 8) // Pre-allocate vertices array
 	quads := [6][4]int{
 		{0, 3, 2, 1}, // front (viewed from front)
 		{5, 6, 7, 4}, // back (viewed from back)
@@ -79,16 +117,6 @@ func createBox(x, y, z, width, height, depth float64) ([]types.Triangle, error)
 		{4, 0, 1, 5}, // bottom (viewed from bottom)
 	}
 
-	// Fill vertices array
-	vertices[0] = types.Point3D{X: x, Y: y, Z: z}
-	vertices[1] = types.Point3D{X: x + width, Y: y, Z: z}
-	vertices[2] = types.Point3D{X: x + width, Y: y + height, Z: z}
-	vertices[3] = types.Point3D{X: x, Y: y + height, Z: z}
-	vertices[4] = types.Point3D{X: x, Y: y, Z: z + depth}
-	vertices[5] = types.Point3D{X: x + width, Y: y, Z: z + depth}
-	vertices[6] = types.Point3D{X: x + width, Y: y + height, Z: z + depth}
-	vertices[7] = types.Point3D{X: x, Y: y + height, Z: z + depth}
-
 	// Generate triangles
 	for _, quad := range quads {
 		quadTriangles, err := CreateQuad(
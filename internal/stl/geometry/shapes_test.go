@@ -191,3 +191,38 @@ func TestCreateBox(t *testing.T) {
 		}
 	})
 }
+
+// TestCreateFrustum verifies sloped box generation and inset validation
+func TestCreateFrustum(t *testing.T) {
+	tests := []struct {
+		name    string
+		inset   float64
+		wantErr bool
+	}{
+		{"no inset", 0, false},
+		{"valid inset", 2, false},
+		{"negative inset", -1, true},
+		{"inset consumes top", 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := createFrustum(0, 0, 0, 20, 10, 5, tt.inset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("createFrustum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(triangles) != 12 {
+				t.Errorf("createFrustum() returned %d triangles, want 12", len(triangles))
+			}
+		})
+	}
+}
+
+// BenchmarkCreateCube measures building a single box, the unit every voxel and tower is made of.
+func BenchmarkCreateCube(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := CreateCube(1, 2, 3, 4, 5, 6); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package geometry
+
+import "math"
+
+// spiralHub is the radius of the free center of a spiral, in tower pitches.
+const spiralHub = 5.0
+
+// spiralArrangement winds the days one after another along a spiral, starting
+// at the back of a round base and running clockwise seen from above, with the
+// oldest day next to the center. Neighboring turns of the spiral are a pitch
+// apart, which packs a year onto a smaller base than the other arrangements.
+type spiralArrangement struct{}
+
+// hub returns the radius of the free center.
+func (spiralArrangement) hub() float64 {
+	return spiralHub
+}
+
+// reach returns the radius of the outer edge of the last turn, plus one gap.
+func (s spiralArrangement) reach(yearCount int) float64 {
+	days := 7 * GridSize * yearCount
+	return s.hub() + s.angle(float64(days))/(2*math.Pi) + 1
+}
+
+// angle returns how far around the spiral, in radians, a day starts when
+// the days before it each take a pitch along the middle of the spiral.
+func (s spiralArrangement) angle(day float64) float64 {
+	// The middle of the spiral is at hub + 1/2 + angle/2π pitches from the
+	// center, so the distance along it is (hub + 1/2)*angle + angle²/4π
+	start := s.hub() + 0.5
+	return 2 * math.Pi * (math.Sqrt(start*start+day/math.Pi) - start)
+}
+
+// footprint returns the section of the spiral taken by a day's tower, with its
+// curved sides approximated by straight edges. Consecutive days are separated
+// by the gap measured along the middle of the spiral.
+func (s spiralArrangement) footprint(l Layout, yearIndex, weekIdx, dayIdx int) [4]point2D {
+	day := ((l.YearCount-1-yearIndex)*GridSize+weekIdx)*7 + dayIdx
+	pitch := l.CellSize + l.Gap
+
+	// inner returns the radius of the inner edge of the spiral at an angle
+	inner := func(angle float64) float64 {
+		return pitch * (s.hub() + angle/(2*math.Pi))
+	}
+	start, end := s.angle(float64(day)), s.angle(float64(day+1))
+	middle := inner((start+end)/2) + pitch/2
+	start += l.Gap / 2 / middle
+	end -= l.Gap / 2 / middle
+
+	cx, cy := l.Width/2, l.Depth/2
+	at := func(radius, angle float64) point2D {
+		// Angles run clockwise from the back of the base
+		return point2D{X: cx + radius*math.Cos(math.Pi/2-angle), Y: cy + radius*math.Sin(math.Pi/2-angle)}
+	}
+	return [4]point2D{
+		at(inner(end), end),
+		at(inner(end)+l.CellSize, end),
+		at(inner(start)+l.CellSize, start),
+		at(inner(start), start),
+	}
+}
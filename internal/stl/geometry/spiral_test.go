@@ -0,0 +1,99 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSpiralFootprint verifies days follow one another outward from the center
+func TestSpiralFootprint(t *testing.T) {
+	layout, err := NewLayout(Config{Arrangement: ArrangementSpiral}, 2)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	spiral := spiralArrangement{}
+
+	// The oldest day starts at the back of the base, next to the center
+	first := spiral.footprint(layout, 1, 0, 0)
+	if r, angle := polar(layout, first[3]); math.Abs(r-layout.HubRadius) > epsilon || math.Abs(angle-math.Pi/2) > epsilon {
+		t.Errorf("first day starts at radius %v and %v rad, want %v and %v", r, angle, layout.HubRadius, math.Pi/2)
+	}
+
+	// Without a gap, each day shares an edge with the next, across weeks and years
+	type day struct{ year, week, day int }
+	pairs := [][2]day{
+		{{1, 0, 0}, {1, 0, 1}},
+		{{1, 3, 6}, {1, 4, 0}},
+		{{1, GridSize - 1, 6}, {0, 0, 0}},
+	}
+	for _, pair := range pairs {
+		a := spiral.footprint(layout, pair[0].year, pair[0].week, pair[0].day)
+		b := spiral.footprint(layout, pair[1].year, pair[1].week, pair[1].day)
+		if dist(a[0], b[3]) > epsilon || dist(a[1], b[2]) > epsilon {
+			t.Errorf("%v and %v do not share an edge: %v, %v", pair[0], pair[1], a, b)
+		}
+	}
+
+	// Every day stays between the hub and the padding, taking about a pitch along the spiral
+	center := func(f [4]point2D) point2D {
+		return point2D{X: (f[0].X + f[1].X + f[2].X + f[3].X) / 4, Y: (f[0].Y + f[1].Y + f[2].Y + f[3].Y) / 4}
+	}
+	reach := layout.Width/2 - layout.OffsetX
+	var previous point2D
+	for year := 1; year >= 0; year-- {
+		for week := 0; week < GridSize; week++ {
+			for d := 0; d < 7; d++ {
+				f := spiral.footprint(layout, year, week, d)
+				for _, p := range f {
+					if r, _ := polar(layout, p); r < layout.HubRadius-epsilon || r > reach+epsilon {
+						t.Fatalf("day %d-%d-%d corner %v is at radius %v, outside %v..%v", year, week, d, p, r, layout.HubRadius, reach)
+					}
+				}
+				if c := center(f); year != 1 || week != 0 || d != 0 {
+					if step := dist(previous, c); math.Abs(step-layout.CellSize) > 0.05*layout.CellSize {
+						t.Fatalf("day %d-%d-%d is %v from the previous day, want about %v", year, week, d, step, layout.CellSize)
+					}
+				}
+				previous = center(f)
+			}
+		}
+	}
+
+	// The last day reaches the outer edge of the towers
+	last := spiral.footprint(layout, 0, GridSize-1, 6)
+	if r, _ := polar(layout, last[1]); math.Abs(r-reach) > epsilon {
+		t.Errorf("last day reaches radius %v, want %v", r, reach)
+	}
+}
+
+// TestSpiralTurnsGap verifies neighboring turns of the spiral are a gap apart
+func TestSpiralTurnsGap(t *testing.T) {
+	layout, err := NewLayout(Config{Arrangement: ArrangementSpiral, Gap: 0.4}, 1)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	spiral := spiralArrangement{}
+	pitch := layout.CellSize + layout.Gap
+	for _, angle := range []float64{0.5, 3, 10} {
+		inner := pitch * (spiral.hub() + (angle+2*math.Pi)/(2*math.Pi))
+		outer := pitch*(spiral.hub()+angle/(2*math.Pi)) + layout.CellSize
+		if math.Abs(inner-outer-layout.Gap) > epsilon {
+			t.Errorf("turns at %v rad are %v apart, want %v", angle, inner-outer, layout.Gap)
+		}
+	}
+}
+
+// TestSpiralIsCompact verifies a spiral fits a year on a smaller base than the other arrangements
+func TestSpiralIsCompact(t *testing.T) {
+	widths := make(map[Arrangement]float64)
+	for _, a := range []Arrangement{ArrangementGrid, ArrangementRadial, ArrangementSpiral} {
+		layout, err := NewLayout(Config{Arrangement: a}, 1)
+		if err != nil {
+			t.Fatalf("NewLayout(%s) error = %v", a, err)
+		}
+		widths[a] = math.Max(layout.Width, layout.Depth)
+	}
+	if widths[ArrangementSpiral] >= widths[ArrangementRadial] || widths[ArrangementSpiral] >= widths[ArrangementGrid] {
+		t.Errorf("spiral base is %vmm, want smaller than radial %vmm and grid %vmm", widths[ArrangementSpiral], widths[ArrangementRadial], widths[ArrangementGrid])
+	}
+}
@@ -0,0 +1,151 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// surfaceFloorShare is the thickness of a smooth surface where there are no
+// contributions, as a share of the cell size, so the surface never touches the
+// base it stands on.
+const surfaceFloorShare = 0.2
+
+// validateSmoothSurfaceConfig rejects features that shape the towers a smooth
+// surface replaces.
+func (c Config) validateSmoothSurfaceConfig() error {
+	arrangement, err := ParseArrangement(string(c.Arrangement))
+	if err != nil {
+		return err
+	}
+	for _, feature := range []struct {
+		used bool
+		name string
+	}{
+		{arrangement.round() != nil, fmt.Sprintf("the round base of the %s layout", arrangement)},
+		{c.TowerShape != "" && c.TowerShape != TowerSquare, "tower shapes"},
+		{c.TowerTop != "" && c.TowerTop != TowerFlat, "tower tops"},
+		{c.Mold, "a mold"},
+	} {
+		if feature.used {
+			return errors.New(errors.ValidationError, fmt.Sprintf("%s cannot be combined with a smooth surface, which replaces the towers", feature.name), nil)
+		}
+	}
+	return nil
+}
+
+// createSurfaceObject generates a year's contributions as a single solid
+// standing on the base: a heightfield with a point over the center of each
+// day's cell at the height of the day's tower, falling to a thin floor along
+// the edges of the year's grid, for a mountain range in place of the towers.
+func (l Layout) createSurfaceObject(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) (types.ModelObject, error) {
+	weeks := len(contributions)
+	floor := surfaceFloorShare * l.CellSize
+	base := l.TierElevation(yearIndex)
+
+	// Points run from the left and front edges of the grid, over the center of
+	// each day's cell, to its right and back edges
+	left, front := l.TowerPosition(yearIndex, 0, 0)
+	right, back := l.TowerPosition(yearIndex, weeks-1, l.daysPerWeek()-1)
+	xs := []float64{left}
+	for week := 0; week < weeks; week++ {
+		x, _ := l.TowerPosition(yearIndex, week, 0)
+		xs = append(xs, x+l.CellSize/2)
+	}
+	xs = append(xs, right+l.CellSize)
+	ys := []float64{front}
+	for day := 0; day < l.daysPerWeek(); day++ {
+		_, y := l.TowerPosition(yearIndex, 0, day)
+		ys = append(ys, y+l.CellSize/2)
+	}
+	ys = append(ys, back+l.CellSize)
+
+	last, rear := len(xs)-1, len(ys)-1
+	heights := make([][]float64, len(xs))
+	total := 0
+	for i := range heights {
+		heights[i] = make([]float64, len(ys))
+		for j := range heights[i] {
+			heights[i][j] = floor
+			if i == 0 || i == last || j == 0 || j == rear || j-1 >= len(contributions[i-1]) {
+				continue
+			}
+			count := contributions[i-1][j-1].ContributionCount
+			heights[i][j] += l.TowerHeight(count, maxContrib)
+			total += count
+		}
+	}
+	top := func(i, j int) types.Point3D {
+		return types.Point3D{X: xs[i], Y: ys[j], Z: base + heights[i][j]}
+	}
+	bottom := func(i, j int) types.Point3D {
+		return types.Point3D{X: xs[i], Y: ys[j], Z: base}
+	}
+
+	var triangles []types.Triangle
+	var err error
+	add := func(a, b, c types.Point3D) {
+		if err != nil {
+			return
+		}
+		var normal types.Point3D
+		if normal, err = calculateNormal(a, b, c); err == nil {
+			triangles = append(triangles, types.Triangle{Normal: normal, V1: a, V2: b, V3: c})
+		}
+	}
+
+	// Each square of the surface is split along the diagonal whose ends are
+	// closest in height, so ridges follow runs of busy days
+	for i := 0; i < last; i++ {
+		for j := 0; j < rear; j++ {
+			a, b, c, d := top(i, j), top(i+1, j), top(i+1, j+1), top(i, j+1)
+			if math.Abs(a.Z-c.Z) <= math.Abs(b.Z-d.Z) {
+				add(a, b, c)
+				add(a, c, d)
+			} else {
+				add(a, b, d)
+				add(b, c, d)
+			}
+			add(bottom(i, j), bottom(i+1, j+1), bottom(i+1, j))
+			add(bottom(i, j), bottom(i, j+1), bottom(i+1, j+1))
+		}
+	}
+
+	// Walls of floor thickness around the edges of the grid
+	for i := 0; i < last; i++ {
+		add(bottom(i, 0), bottom(i+1, 0), top(i+1, 0))
+		add(bottom(i, 0), top(i+1, 0), top(i, 0))
+		add(bottom(i, rear), top(i+1, rear), bottom(i+1, rear))
+		add(bottom(i, rear), top(i, rear), top(i+1, rear))
+	}
+	for j := 0; j < rear; j++ {
+		add(bottom(0, j), top(0, j+1), bottom(0, j+1))
+		add(bottom(0, j), top(0, j), top(0, j+1))
+		add(bottom(last, j), bottom(last, j+1), top(last, j+1))
+		add(bottom(last, j), top(last, j+1), top(last, j))
+	}
+	if err != nil {
+		return types.ModelObject{}, errors.New(errors.STLError, "failed to create smooth surface", err)
+	}
+
+	// The first week may start in the previous year, but always ends in this one
+	var metadata []types.Metadata
+	if first := contributions[0]; len(first) > 0 {
+		if date, err := time.Parse("2006-01-02", first[len(first)-1].Date); err == nil {
+			metadata = append(metadata, types.Metadata{Key: "year", Value: strconv.Itoa(date.Year())})
+		}
+	}
+	metadata = append(metadata, types.Metadata{Key: "contributions", Value: strconv.Itoa(total)})
+
+	return types.ModelObject{
+		Name:     fmt.Sprintf("surface-%d", yearIndex),
+		Kind:     types.ObjectSurface,
+		Material: types.MaterialLevel4, // One color for every day, that of the busiest
+		Mesh:     types.NewMesh(triangles),
+		Metadata: metadata,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestConfigValidateSmoothSurface verifies rejection of features a smooth surface cannot take
+func TestConfigValidateSmoothSurface(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"smooth surface", Config{SmoothSurface: true}, false},
+		{"stacked years", Config{SmoothSurface: true, Stack: true}, false},
+		{"square flat towers", Config{SmoothSurface: true, TowerShape: TowerSquare, TowerTop: TowerFlat}, false},
+		{"round layout", Config{SmoothSurface: true, Arrangement: ArrangementRadial}, true},
+		{"tower shape", Config{SmoothSurface: true, TowerShape: TowerHex}, true},
+		{"tower top", Config{SmoothSurface: true, TowerTop: TowerRounded}, true},
+		{"mold", Config{SmoothSurface: true, Mold: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestLayoutCreateSurfaceObject verifies a year becomes one closed surface
+// over the grid, raised at its busy days
+func TestLayoutCreateSurfaceObject(t *testing.T) {
+	tests := []struct {
+		name  string
+		peaks map[[2]int]int // Contributions by week and day
+	}{
+		{"no contributions", nil},
+		{"single peak", map[[2]int]int{{10, 3}: 5}},
+		{"two peaks", map[[2]int]int{{10, 3}: 5, {40, 1}: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(Config{SmoothSurface: true}, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			contributions := make([][]types.ContributionDay, GridSize)
+			for week := range contributions {
+				contributions[week] = make([]types.ContributionDay, 7)
+				for day := range contributions[week] {
+					contributions[week][day] = types.ContributionDay{Date: "2024-06-01", ContributionCount: tt.peaks[[2]int{week, day}]}
+				}
+			}
+
+			objects, err := layout.CreateContributionObjects(contributions, 0, 5)
+			if err != nil {
+				t.Fatalf("CreateContributionObjects() error = %v", err)
+			}
+			if len(objects) != 1 || objects[0].Kind != types.ObjectSurface {
+				t.Fatalf("CreateContributionObjects() returned %d objects, want one surface", len(objects))
+			}
+			triangles := objects[0].Mesh.Triangles()
+			if !isClosedMesh(triangles) {
+				t.Error("surface is not a closed mesh")
+			}
+
+			// The surface covers the grid, standing on the base
+			left, front := layout.TowerPosition(0, 0, 0)
+			right, back := layout.TowerPosition(0, GridSize-1, 6)
+			right, back = right+layout.CellSize, back+layout.CellSize
+			minPoint, maxPoint := triangleBounds(triangles)
+			floor := surfaceFloorShare * layout.CellSize
+			wantTop := floor
+			if len(tt.peaks) > 0 {
+				wantTop += layout.MaxHeight
+			}
+			for _, pair := range [][2]float64{{minPoint.X, left}, {minPoint.Y, front}, {minPoint.Z, 0}, {maxPoint.X, right}, {maxPoint.Y, back}, {maxPoint.Z, wantTop}} {
+				if math.Abs(pair[0]-pair[1]) > epsilon {
+					t.Errorf("surface spans %v to %v, want (%v, %v, 0) to (%v, %v, %v)", minPoint, maxPoint, left, front, right, back, wantTop)
+					break
+				}
+			}
+
+			// Each peak is a pyramid over the two cells' worth of triangles it is a corner of
+			want := floor * (right - left) * (back - front)
+			want += float64(len(tt.peaks)) * layout.MaxHeight * 2 * layout.CellSize * layout.CellSize / 3
+			if got := meshVolume(triangles); math.Abs(got-want) > epsilon {
+				t.Errorf("surface volume = %v, want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("stacked year", func(t *testing.T) {
+		layout, err := NewLayout(Config{SmoothSurface: true, Stack: true}, 2)
+		if err != nil {
+			t.Fatalf("NewLayout() error = %v", err)
+		}
+		contributions := [][]types.ContributionDay{{{Date: "2024-01-01", ContributionCount: 1}}}
+		objects, err := layout.CreateContributionObjects(contributions, 1, 1)
+		if err != nil {
+			t.Fatalf("CreateContributionObjects() error = %v", err)
+		}
+		minPoint, _ := triangleBounds(objects[0].Mesh.Triangles())
+		if want := layout.TierElevation(1); math.Abs(minPoint.Z-want) > epsilon {
+			t.Errorf("stacked surface stands at %v, want its tier at %v", minPoint.Z, want)
+		}
+		if got := objects[0].Metadata; len(got) != 2 || got[0].Value != "2024" || got[1].Value != "1" {
+			t.Errorf("surface metadata = %v, want year 2024 with 1 contribution", got)
+		}
+	})
+}
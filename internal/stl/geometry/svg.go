@@ -0,0 +1,655 @@
+package geometry
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// svgEllipseSegments is the number of straight segments used to approximate
+// circles and ellipses.
+const svgEllipseSegments = 4 * cornerSegments
+
+// svgDrawing is the filled outlines of an SVG document in its user space,
+// with X to the right and Y down.
+type svgDrawing struct {
+	viewBox  [4]float64 // Minimum X, minimum Y, width and height of the visible area
+	contours []contour
+}
+
+// affine is a 2D affine transform [a b c d e f] mapping (x, y) to
+// (a*x + c*y + e, b*x + d*y + f), as in SVG's matrix() transform.
+type affine [6]float64
+
+// identity is the affine transform that leaves points unchanged.
+var identity = affine{1, 0, 0, 1, 0, 0}
+
+// apply transforms a point.
+func (m affine) apply(p point2D) point2D {
+	return point2D{X: m[0]*p.X + m[2]*p.Y + m[4], Y: m[1]*p.X + m[3]*p.Y + m[5]}
+}
+
+// then returns the transform applying n in the coordinate system of m.
+func (m affine) then(n affine) affine {
+	return affine{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+// svgSkippedElements hold content that is not drawn directly.
+var svgSkippedElements = map[string]bool{
+	"defs": true, "clipPath": true, "mask": true, "marker": true, "pattern": true,
+	"symbol": true, "metadata": true, "title": true, "desc": true, "style": true,
+}
+
+// parseSVG reads the filled shapes of an SVG document. Paths, polygons,
+// rectangles, circles and ellipses are supported along with their transforms.
+// Shapes without a fill are skipped, as strokes have no area to extrude.
+func parseSVG(r io.Reader) (svgDrawing, error) {
+	var drawing svgDrawing
+	decoder := xml.NewDecoder(r)
+
+	transforms := []affine{identity}
+	fills := []bool{true}
+	skipDepth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return svgDrawing{}, errors.New(errors.ValidationError, "failed to parse SVG", err)
+		}
+
+		switch el := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 || svgSkippedElements[el.Name.Local] {
+				skipDepth++
+				continue
+			}
+			attrs := make(map[string]string, len(el.Attr))
+			for _, a := range el.Attr {
+				attrs[a.Name.Local] = a.Value
+			}
+
+			transform := transforms[len(transforms)-1]
+			if t, ok := attrs["transform"]; ok {
+				local, err := parseSVGTransform(t)
+				if err != nil {
+					return svgDrawing{}, err
+				}
+				transform = transform.then(local)
+			}
+			filled := fills[len(fills)-1]
+			if fill, ok := svgFill(attrs); ok {
+				filled = fill != "none" && fill != "transparent"
+			}
+			transforms = append(transforms, transform)
+			fills = append(fills, filled)
+
+			if el.Name.Local == "svg" && len(transforms) == 2 {
+				if err := drawing.setViewBox(attrs); err != nil {
+					return svgDrawing{}, err
+				}
+				continue
+			}
+
+			contours, err := svgShape(el.Name.Local, attrs)
+			if err != nil {
+				return svgDrawing{}, err
+			}
+			if !filled {
+				continue
+			}
+			for _, c := range contours {
+				for i, p := range c {
+					c[i] = transform.apply(p)
+				}
+				drawing.contours = append(drawing.contours, c)
+			}
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			transforms = transforms[:len(transforms)-1]
+			fills = fills[:len(fills)-1]
+		}
+	}
+
+	if len(drawing.contours) == 0 {
+		return svgDrawing{}, errors.New(errors.ValidationError, "SVG has no filled shapes", nil)
+	}
+	if drawing.viewBox[2] <= 0 || drawing.viewBox[3] <= 0 {
+		drawing.viewBox = drawing.bounds()
+	}
+	return drawing, nil
+}
+
+// setViewBox reads the visible area of the document from its root element,
+// falling back to its width and height.
+func (d *svgDrawing) setViewBox(attrs map[string]string) error {
+	if viewBox, ok := attrs["viewBox"]; ok {
+		values, err := parseSVGNumbers(viewBox)
+		if err != nil || len(values) != 4 {
+			return errors.New(errors.ValidationError, fmt.Sprintf("invalid SVG viewBox %q", viewBox), err)
+		}
+		copy(d.viewBox[:], values)
+		return nil
+	}
+	width, _ := parseSVGLength(attrs["width"])
+	height, _ := parseSVGLength(attrs["height"])
+	d.viewBox = [4]float64{0, 0, width, height}
+	return nil
+}
+
+// bounds returns the area covered by the drawing's outlines as a viewBox.
+func (d svgDrawing) bounds() [4]float64 {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range d.contours {
+		for _, p := range c {
+			minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+			minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+		}
+	}
+	return [4]float64{minX, minY, maxX - minX, maxY - minY}
+}
+
+// svgFill returns the fill of an element from its fill attribute or style.
+func svgFill(attrs map[string]string) (string, bool) {
+	for _, decl := range strings.Split(attrs["style"], ";") {
+		if name, value, ok := strings.Cut(decl, ":"); ok && strings.TrimSpace(name) == "fill" {
+			return strings.TrimSpace(value), true
+		}
+	}
+	fill, ok := attrs["fill"]
+	return strings.TrimSpace(fill), ok
+}
+
+// svgShape converts a drawing element into closed outlines.
+func svgShape(name string, attrs map[string]string) ([]contour, error) {
+	number := func(key string) float64 {
+		v, _ := parseSVGLength(attrs[key])
+		return v
+	}
+
+	switch name {
+	case "path":
+		return parseSVGPath(attrs["d"])
+	case "polygon", "polyline":
+		values, err := parseSVGNumbers(attrs["points"])
+		if err != nil {
+			return nil, err
+		}
+		var c contour
+		for i := 0; i+1 < len(values); i += 2 {
+			c = append(c, point2D{values[i], values[i+1]})
+		}
+		return []contour{c}, nil
+	case "rect":
+		x, y, w, h := number("x"), number("y"), number("width"), number("height")
+		if w <= 0 || h <= 0 {
+			return nil, nil
+		}
+		return []contour{{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}}, nil
+	case "circle":
+		return []contour{ellipseContour(number("cx"), number("cy"), number("r"), number("r"))}, nil
+	case "ellipse":
+		return []contour{ellipseContour(number("cx"), number("cy"), number("rx"), number("ry"))}, nil
+	}
+	return nil, nil
+}
+
+// ellipseContour approximates an axis aligned ellipse.
+func ellipseContour(cx, cy, rx, ry float64) contour {
+	if rx <= 0 || ry <= 0 {
+		return nil
+	}
+	c := make(contour, svgEllipseSegments)
+	for i := range c {
+		angle := 2 * math.Pi * float64(i) / svgEllipseSegments
+		c[i] = point2D{cx + rx*math.Cos(angle), cy + ry*math.Sin(angle)}
+	}
+	return c
+}
+
+// svgTransformPattern matches a single function of a transform list.
+var svgTransformPattern = regexp.MustCompile(`(\w+)\s*\(([^)]*)\)`)
+
+// parseSVGTransform converts a transform list into a single affine transform.
+func parseSVGTransform(s string) (affine, error) {
+	m := identity
+	for _, match := range svgTransformPattern.FindAllStringSubmatch(s, -1) {
+		args, err := parseSVGNumbers(match[2])
+		if err != nil {
+			return affine{}, err
+		}
+		arg := func(i int, fallback float64) float64 {
+			if i < len(args) {
+				return args[i]
+			}
+			return fallback
+		}
+
+		var local affine
+		switch match[1] {
+		case "matrix":
+			if len(args) != 6 {
+				return affine{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid SVG transform %q", match[0]), nil)
+			}
+			copy(local[:], args)
+		case "translate":
+			local = affine{1, 0, 0, 1, arg(0, 0), arg(1, 0)}
+		case "scale":
+			sx := arg(0, 1)
+			local = affine{sx, 0, 0, arg(1, sx), 0, 0}
+		case "rotate":
+			angle := arg(0, 0) * math.Pi / 180
+			cx, cy := arg(1, 0), arg(2, 0)
+			cos, sin := math.Cos(angle), math.Sin(angle)
+			local = affine{1, 0, 0, 1, cx, cy}.then(affine{cos, sin, -sin, cos, 0, 0}).then(affine{1, 0, 0, 1, -cx, -cy})
+		case "skewX":
+			local = affine{1, 0, math.Tan(arg(0, 0) * math.Pi / 180), 1, 0, 0}
+		case "skewY":
+			local = affine{1, math.Tan(arg(0, 0) * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			return affine{}, errors.New(errors.ValidationError, fmt.Sprintf("unsupported SVG transform %q", match[1]), nil)
+		}
+		m = m.then(local)
+	}
+	return m, nil
+}
+
+// parseSVGLength parses a length attribute, ignoring any unit suffix.
+func parseSVGLength(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	end := len(s)
+	for end > 0 && (s[end-1] < '0' || s[end-1] > '9') && s[end-1] != '.' {
+		end--
+	}
+	return strconv.ParseFloat(s[:end], 64)
+}
+
+// parseSVGNumbers parses a list of numbers separated by commas or whitespace.
+func parseSVGNumbers(s string) ([]float64, error) {
+	scanner := pathScanner{s: s}
+	var values []float64
+	for scanner.hasNumber() {
+		v, err := scanner.number()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// parseSVGPath converts path data into closed outlines, flattening curves and
+// arcs. Open subpaths are closed, as they are when an SVG path is filled.
+func parseSVGPath(d string) ([]contour, error) {
+	scanner := pathScanner{s: d}
+	var contours []contour
+	var current contour
+	var pos, start, ctrl point2D
+	var prevCmd byte
+	flush := func() {
+		if len(current) >= 3 {
+			contours = append(contours, current)
+		}
+		current = nil
+	}
+
+	var cmd byte
+	for {
+		if c, ok := scanner.command(); ok {
+			cmd = c
+		} else if !scanner.hasNumber() {
+			break
+		} else if cmd == 0 {
+			return nil, errors.New(errors.ValidationError, "SVG path data must start with a command", nil)
+		}
+
+		relative := cmd >= 'a'
+		upper := cmd &^ 0x20
+		if len(current) == 0 && upper != 'M' && upper != 'Z' {
+			// Drawing after a closed subpath continues from its start
+			current = append(current, pos)
+		}
+		offset := func(p point2D) point2D {
+			if relative {
+				return point2D{pos.X + p.X, pos.Y + p.Y}
+			}
+			return p
+		}
+
+		var err error
+		switch upper {
+		case 'M':
+			var p point2D
+			if p, err = scanner.point(); err != nil {
+				break
+			}
+			flush()
+			pos = offset(p)
+			start = pos
+			current = append(current, pos)
+			// Further coordinate pairs are implicit line commands
+			cmd = 'L' | cmd&0x20
+		case 'L':
+			var p point2D
+			if p, err = scanner.point(); err == nil {
+				pos = offset(p)
+				current = append(current, pos)
+			}
+		case 'H', 'V':
+			var v float64
+			if v, err = scanner.number(); err == nil {
+				switch {
+				case upper == 'H' && relative:
+					pos.X += v
+				case upper == 'H':
+					pos.X = v
+				case relative:
+					pos.Y += v
+				default:
+					pos.Y = v
+				}
+				current = append(current, pos)
+			}
+		case 'C', 'S':
+			var c1, c2, end point2D
+			if upper == 'S' {
+				// The first control point reflects the previous curve's last one
+				c1 = pos
+				if p := prevCmd &^ 0x20; p == 'C' || p == 'S' {
+					c1 = point2D{2*pos.X - ctrl.X, 2*pos.Y - ctrl.Y}
+				}
+			} else if c1, err = scanner.point(); err != nil {
+				break
+			} else {
+				c1 = offset(c1)
+			}
+			if c2, err = scanner.point(); err != nil {
+				break
+			}
+			if end, err = scanner.point(); err != nil {
+				break
+			}
+			c2, end = offset(c2), offset(end)
+			current = appendCubic(current, pos, c1, c2, end)
+			pos, ctrl = end, c2
+		case 'Q', 'T':
+			var c1, end point2D
+			if upper == 'T' {
+				c1 = pos
+				if p := prevCmd &^ 0x20; p == 'Q' || p == 'T' {
+					c1 = point2D{2*pos.X - ctrl.X, 2*pos.Y - ctrl.Y}
+				}
+			} else if c1, err = scanner.point(); err != nil {
+				break
+			} else {
+				c1 = offset(c1)
+			}
+			if end, err = scanner.point(); err != nil {
+				break
+			}
+			end = offset(end)
+			current = appendQuadratic(current, pos, c1, end)
+			pos, ctrl = end, c1
+		case 'A':
+			var rx, ry, rotation float64
+			var large, sweep bool
+			var end point2D
+			if rx, err = scanner.number(); err != nil {
+				break
+			}
+			if ry, err = scanner.number(); err != nil {
+				break
+			}
+			if rotation, err = scanner.number(); err != nil {
+				break
+			}
+			if large, err = scanner.flag(); err != nil {
+				break
+			}
+			if sweep, err = scanner.flag(); err != nil {
+				break
+			}
+			if end, err = scanner.point(); err != nil {
+				break
+			}
+			end = offset(end)
+			current = appendArc(current, pos, end, rx, ry, rotation, large, sweep)
+			pos = end
+		case 'Z':
+			flush()
+			pos = start
+		default:
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("unsupported SVG path command %q", cmd), nil)
+		}
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid SVG path data near %q", scanner.rest()), err)
+		}
+		prevCmd = cmd
+	}
+	flush()
+
+	// Closed subpaths often end back on their starting point
+	for i, c := range contours {
+		if len(c) > 1 && samePoint(c[0], c[len(c)-1]) {
+			contours[i] = c[:len(c)-1]
+		}
+	}
+	return contours, nil
+}
+
+// appendArc flattens an SVG elliptical arc from p0 to p1, skipping p0 itself,
+// following the endpoint to center conversion of the SVG specification.
+func appendArc(c contour, p0, p1 point2D, rx, ry, rotation float64, large, sweep bool) contour {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || samePoint(p0, p1) {
+		return append(c, p1)
+	}
+
+	phi := rotation * math.Pi / 180
+	cos, sin := math.Cos(phi), math.Sin(phi)
+	dx, dy := (p0.X-p1.X)/2, (p0.Y-p1.Y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+
+	// Scale up radii too small to reach the end point
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		coef = -coef
+	}
+	cx1, cy1 := coef*rx*y1/ry, -coef*ry*x1/rx
+	cx := cos*cx1 - sin*cy1 + (p0.X+p1.X)/2
+	cy := sin*cx1 + cos*cy1 + (p0.Y+p1.Y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	segments := int(math.Ceil(math.Abs(delta) / (math.Pi / 2) * cornerSegments))
+	for i := 1; i <= segments; i++ {
+		a := theta + delta*float64(i)/float64(segments)
+		x, y := rx*math.Cos(a), ry*math.Sin(a)
+		c = append(c, point2D{X: cos*x - sin*y + cx, Y: sin*x + cos*y + cy})
+	}
+	c[len(c)-1] = p1
+	return c
+}
+
+// pathScanner reads commands and numbers from SVG path data and attribute lists.
+type pathScanner struct {
+	s string
+	i int
+}
+
+// skipSeparators advances past whitespace and commas.
+func (p *pathScanner) skipSeparators() {
+	for p.i < len(p.s) && strings.ContainsRune(" \t\r\n,", rune(p.s[p.i])) {
+		p.i++
+	}
+}
+
+// command returns the next path command letter, if the next token is one.
+func (p *pathScanner) command() (byte, bool) {
+	p.skipSeparators()
+	if p.i < len(p.s) {
+		if c := p.s[p.i]; (c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') && c != 'e' && c != 'E' {
+			p.i++
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// hasNumber reports whether the next token is a number.
+func (p *pathScanner) hasNumber() bool {
+	p.skipSeparators()
+	return p.i < len(p.s) && strings.ContainsRune("0123456789+-.", rune(p.s[p.i]))
+}
+
+// number reads the next number, which may directly follow the previous one
+// when its sign or decimal point starts a new number, as in "1-2.5.5".
+func (p *pathScanner) number() (float64, error) {
+	p.skipSeparators()
+	start := p.i
+	if p.i < len(p.s) && (p.s[p.i] == '+' || p.s[p.i] == '-') {
+		p.i++
+	}
+	seenDot, seenDigit := false, false
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		switch {
+		case c >= '0' && c <= '9':
+			seenDigit = true
+		case c == '.' && !seenDot:
+			seenDot = true
+		case (c == 'e' || c == 'E') && seenDigit:
+			// Exponents only continue the number when followed by digits
+			j := p.i + 1
+			if j < len(p.s) && (p.s[j] == '+' || p.s[j] == '-') {
+				j++
+			}
+			if j >= len(p.s) || p.s[j] < '0' || p.s[j] > '9' {
+				return strconv.ParseFloat(p.s[start:p.i], 64)
+			}
+			p.i = j
+			for p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+				p.i++
+			}
+			return strconv.ParseFloat(p.s[start:p.i], 64)
+		default:
+			return strconv.ParseFloat(p.s[start:p.i], 64)
+		}
+		p.i++
+	}
+	return strconv.ParseFloat(p.s[start:p.i], 64)
+}
+
+// flag reads a single digit arc flag, which need not be separated from what follows.
+func (p *pathScanner) flag() (bool, error) {
+	p.skipSeparators()
+	if p.i >= len(p.s) || (p.s[p.i] != '0' && p.s[p.i] != '1') {
+		return false, errors.New(errors.ValidationError, "expected an arc flag", nil)
+	}
+	p.i++
+	return p.s[p.i-1] == '1', nil
+}
+
+// point reads a pair of numbers.
+func (p *pathScanner) point() (point2D, error) {
+	x, err := p.number()
+	if err != nil {
+		return point2D{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return point2D{}, err
+	}
+	return point2D{x, y}, nil
+}
+
+// rest returns a short excerpt of the unread input for error messages.
+func (p *pathScanner) rest() string {
+	rest := p.s[min(p.i, len(p.s)):]
+	if len(rest) > 20 {
+		rest = rest[:20] + "..."
+	}
+	return rest
+}
+
+// createSVGLogo generates 3D geometry for an SVG logo by extruding its filled
+// shapes. The drawing is fitted into the area of the embedded logo at scale
+// times its full size, keeping its proportions, on a front face leaning back
+// by slope.
+func createSVGLogo(path string, baseWidth float64, baseHeight float64, slope float64, scale float64) ([]types.Triangle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open logo", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	drawing, err := parseSVG(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fit the view box into the embedded logo's area, anchored at its top left
+	logoWidthRes, logoRes, err := logoSizeVoxels()
+	if err != nil {
+		return nil, err
+	}
+	toMillimeters := baseWidth / baseWidthVoxelResolution
+	faceHeightRes := int(float64(baseWidthVoxelResolution) * baseHeight / baseWidth)
+	vb := drawing.viewBox
+	fit := math.Min(logoWidthRes/vb[2], logoRes/vb[3]) * scale * toMillimeters
+	left := logoLeftOffset * baseWidthVoxelResolution * toMillimeters
+	top := -logoTopOffset * float64(faceHeightRes) * toMillimeters
+
+	for _, c := range drawing.contours {
+		for i, p := range c {
+			// SVG's Y axis points down, the face's up
+			c[i] = point2D{X: left + (p.X-vb[0])*fit, Y: top - (p.Y-vb[1])*fit}
+		}
+	}
+
+	var triangles []types.Triangle
+	for _, poly := range nestContoursEvenOdd(drawing.contours) {
+		solid, err := extrudeOnFace(poly, voxelDepth, baseHeight, slope)
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, solid...)
+	}
+	return triangles, nil
+}
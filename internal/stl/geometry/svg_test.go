@@ -0,0 +1,186 @@
+package geometry
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// contoursArea returns the even-odd area enclosed by outlines.
+func contoursArea(t *testing.T, contours []contour) float64 {
+	t.Helper()
+	area := 0.0
+	for _, p := range nestContoursEvenOdd(contours) {
+		triangles, err := p.triangulate()
+		if err != nil {
+			t.Fatalf("triangulate() error = %v", err)
+		}
+		area += triangleArea(triangles)
+	}
+	return area
+}
+
+// TestParseSVGPath verifies path commands are converted into outlines
+func TestParseSVGPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		d            string
+		wantContours int
+		wantArea     float64
+	}{
+		{"absolute lines", "M0 0 L10 0 L10 10 L0 10 Z", 1, 100},
+		{"relative lines", "m0 0 l10 0 0 10 -10 0z", 1, 100},
+		{"horizontal and vertical", "M0,0H10V10H0Z", 1, 100},
+		{"compact numbers", "M0-0L10-0L10,5.5.5,5.5z", 1, (10 + 9.5) / 2 * 5.5},
+		{"implicit close", "M0 0 L10 0 L10 10", 1, 50},
+		{"square with hole", "M0 0H10V10H0Z M2 2H8V8H2Z", 2, 64},
+		{"quadratic", "M0 0 Q5 10 10 0 Z", 1, 100.0 / 3},
+		{"smooth quadratic", "M0 0 Q2.5 5 5 0 T10 0 Z", 1, 0},
+		{"cubic", "M0 0 C0 10 10 10 10 0 Z", 1, 60},
+		{"circle from arcs", "M-5 0 A5 5 0 1 0 5 0 A5 5 0 1 0 -5 0 Z", 1, 25 * math.Pi},
+		{"compact arc flags", "M-5 0a5 5 0 1010 0a5 5 0 10-10 0z", 1, 25 * math.Pi},
+		{"exponent", "M0 0 L1e1 0 L1e1 1E1 L0 10 Z", 1, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contours, err := parseSVGPath(tt.d)
+			if err != nil {
+				t.Fatalf("parseSVGPath(%q) error = %v", tt.d, err)
+			}
+			if len(contours) != tt.wantContours {
+				t.Fatalf("parseSVGPath(%q) returned %d contours, want %d", tt.d, len(contours), tt.wantContours)
+			}
+			if tt.wantArea == 0 {
+				return
+			}
+			// Curves are flattened into straight segments, which cut off a little area
+			if got := contoursArea(t, contours); math.Abs(got-tt.wantArea) > 0.03*tt.wantArea {
+				t.Errorf("parseSVGPath(%q) area = %v, want %v", tt.d, got, tt.wantArea)
+			}
+		})
+	}
+}
+
+// TestParseSVGPathErrors verifies malformed path data is rejected
+func TestParseSVGPathErrors(t *testing.T) {
+	for _, d := range []string{"10 10", "M0 0 L10", "M0 0 A5 5 0 2 0 10 0", "M0 0 X10 10"} {
+		if _, err := parseSVGPath(d); err == nil {
+			t.Errorf("parseSVGPath(%q) expected an error", d)
+		}
+	}
+}
+
+// TestParseSVGTransform verifies transform lists are composed in order
+func TestParseSVGTransform(t *testing.T) {
+	tests := []struct {
+		transform string
+		in, want  point2D
+	}{
+		{"translate(10 5)", point2D{1, 1}, point2D{11, 6}},
+		{"scale(2)", point2D{1, 3}, point2D{2, 6}},
+		{"translate(10,0) scale(2,3)", point2D{1, 1}, point2D{12, 3}},
+		{"rotate(90)", point2D{1, 0}, point2D{0, 1}},
+		{"rotate(180 5 5)", point2D{0, 0}, point2D{10, 10}},
+		{"matrix(1 0 0 -1 0 10)", point2D{2, 3}, point2D{2, 7}},
+	}
+
+	for _, tt := range tests {
+		m, err := parseSVGTransform(tt.transform)
+		if err != nil {
+			t.Fatalf("parseSVGTransform(%q) error = %v", tt.transform, err)
+		}
+		if got := m.apply(tt.in); !samePointWithin(got, tt.want, 1e-9) {
+			t.Errorf("parseSVGTransform(%q) maps %v to %v, want %v", tt.transform, tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := parseSVGTransform("perspective(1)"); err == nil {
+		t.Error("parseSVGTransform() expected an error for an unsupported transform")
+	}
+}
+
+// samePointWithin reports whether two points are within tolerance of each other.
+func samePointWithin(a, b point2D, tolerance float64) bool {
+	return math.Abs(a.X-b.X) <= tolerance && math.Abs(a.Y-b.Y) <= tolerance
+}
+
+// TestParseSVG verifies shapes, fills, groups and the view box are read from documents
+func TestParseSVG(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 20 10" width="200" height="100">
+  <title>Logo</title>
+  <defs><rect id="unused" width="100" height="100"/></defs>
+  <rect x="0" y="0" width="4" height="4"/>
+  <g transform="translate(10 0)">
+    <circle cx="2" cy="2" r="2"/>
+    <polygon points="0,5 4,5 4,9"/>
+  </g>
+  <path d="M0 0H20V10H0Z" fill="none" stroke="black"/>
+  <g style="fill: none"><rect width="1" height="1"/></g>
+</svg>`
+
+	drawing, err := parseSVG(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("parseSVG() error = %v", err)
+	}
+	if drawing.viewBox != [4]float64{0, 0, 20, 10} {
+		t.Errorf("viewBox = %v, want [0 0 20 10]", drawing.viewBox)
+	}
+	if len(drawing.contours) != 3 {
+		t.Fatalf("parseSVG() returned %d contours, want 3 filled shapes", len(drawing.contours))
+	}
+	if b := drawing.bounds(); !samePointWithin(point2D{b[0] + b[2], b[1] + b[3]}, point2D{14, 9}, 1e-9) {
+		t.Errorf("bounds = %v, want shapes to reach (14, 9) after the group transform", b)
+	}
+
+	for _, bad := range []string{`<svg><path d="M0 0 L"/></svg>`, `<svg></svg>`, `<svg><rect`} {
+		if _, err := parseSVG(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseSVG(%q) expected an error", bad)
+		}
+	}
+}
+
+// TestCreateSVGLogo verifies SVG logos are closed solids in the logo's area
+func TestCreateSVGLogo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.svg")
+	doc := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100">
+  <path fill-rule="evenodd" d="M50 0A50 50 0 1 1 50 100A50 50 0 1 1 50 0ZM50 25A25 25 0 1 0 50 75A25 25 0 1 0 50 25Z"/>
+</svg>`
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	width, _ := CalculateMultiYearDimensions(1)
+	triangles, err := createSVGLogo(path, width, BaseHeight, 0, 1)
+	if err != nil {
+		t.Fatalf("createSVGLogo() error = %v", err)
+	}
+	if !isClosedMesh(triangles) {
+		t.Error("SVG logo is not a closed mesh")
+	}
+
+	logoWidth, logoHeight, err := logoSizeVoxels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	toMillimeters := width / baseWidthVoxelResolution
+	size := math.Min(logoWidth, logoHeight) * toMillimeters
+	wantVolume := math.Pi * (size*size/4 - size*size/16) * voxelDepth
+	if volume := meshVolume(triangles); math.Abs(volume-wantVolume) > 0.02*wantVolume {
+		t.Errorf("SVG logo volume = %v, want about %v", volume, wantVolume)
+	}
+
+	left := logoLeftOffset * width
+	for _, tri := range triangles {
+		if tri.V1.X < left-epsilon || tri.V1.X > left+size+epsilon || tri.V1.Z > 0 || tri.V1.Z < -BaseHeight {
+			t.Fatalf("vertex %v lies outside the logo area", tri.V1)
+		}
+	}
+
+	if _, err := createSVGLogo(filepath.Join(t.TempDir(), "missing.svg"), width, BaseHeight, 0, 1); err == nil {
+		t.Error("createSVGLogo() expected an error for a missing file")
+	}
+}
@@ -2,8 +2,9 @@ package geometry
 
 import (
 	"fmt"
-	"image/png"
-	"os"
+	"image"
+	"math"
+	"strings"
 
 	"github.com/fogleman/gg"
 	"github.com/github/gh-skyline/internal/errors"
@@ -11,8 +12,9 @@ import (
 )
 
 const (
-	baseWidthVoxelResolution = 2000 // Number of voxels across the skyline face
+	baseWidthVoxelResolution = 2000 // Number of face voxels across the skyline face that positions and font sizes are given in
 	voxelDepth               = 1.0  // Distance to come out of face
+	faceSupersample          = 2    // Pixels drawn across each face voxel, so voxels along the edges of text can be split
 
 	logoScale      = 0.4  // Percent
 	logoTopOffset  = 0.15 // Percent
@@ -27,8 +29,96 @@ const (
 	yearLeftOffset    = 0.97    // Percent
 )
 
-// Create3DText generates 3D text geometry for the username and year.
+// Voxels across the front and back faces that voxel text is drawn with.
+const (
+	DefaultFaceResolution = baseWidthVoxelResolution // Resolution when none is configured
+	PreviewFaceResolution = 500                      // Coarser resolution for image previews, quicker to generate and draw
+	minFaceResolution     = 250                      // Lowest resolution that still keeps the text legible
+	maxFaceResolution     = 8000                     // Highest resolution, beyond which text only adds triangles
+)
+
+// validateFaceResolution checks that a configured face resolution is usable,
+// with zero selecting DefaultFaceResolution.
+func validateFaceResolution(resolution int) error {
+	if resolution != 0 && (resolution < minFaceResolution || resolution > maxFaceResolution) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("face resolution must be between %d and %d voxels", minFaceResolution, maxFaceResolution), nil)
+	}
+	return nil
+}
+
+// TextStyle identifies how the username and year are built on the front face.
+type TextStyle string
+
+// Supported text styles.
+const (
+	TextVoxel  TextStyle = "voxel"  // Rasterized text built from voxels
+	TextVector TextStyle = "vector" // Extruded font outlines, smoother and with far fewer triangles
+)
+
+// DefaultTextStyle is the text style used when none is configured.
+const DefaultTextStyle = TextVoxel
+
+// textStyles lists the supported text styles in the order they are presented to users.
+var textStyles = []TextStyle{TextVoxel, TextVector}
+
+// TextStyles returns the names of all supported text styles.
+func TextStyles() []string {
+	names := make([]string, len(textStyles))
+	for i, s := range textStyles {
+		names[i] = string(s)
+	}
+	return names
+}
+
+// ParseTextStyle converts a user supplied text style name into a TextStyle.
+// Matching is case-insensitive and an empty string selects DefaultTextStyle.
+func ParseTextStyle(name string) (TextStyle, error) {
+	if name == "" {
+		return DefaultTextStyle, nil
+	}
+	for _, s := range textStyles {
+		if strings.EqualFold(name, string(s)) {
+			return s, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported text style %q (supported: %s)", name, strings.Join(TextStyles(), ", ")), nil)
+}
+
+// Create3DText generates 3D text geometry for the username and year, shrunk
+// to fit the front face when it is too thin for them.
 func Create3DText(username string, year string, baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
+	return create3DText(username, year, baseWidth, baseHeight, 0, DefaultFaceResolution, textFit(baseWidth, baseHeight, 0))
+}
+
+// CreateText generates 3D text geometry for the username and year on the
+// front face of the base described by the layout, in the layout's text style,
+// or in the center of a round base.
+func (l Layout) CreateText(username string, year string) ([]types.Triangle, error) {
+	if l.IsRound() {
+		return l.createHubText(username, year)
+	}
+	scale := textFit(l.Width, l.Height, l.faceMargin())
+	if l.TextStyle == TextVector {
+		return createVectorText(username, year, l.Width, l.Height, l.FrontSlope(), scale)
+	}
+	return create3DText(username, year, l.Width, l.Height, l.FrontSlope(), l.FaceResolution, scale)
+}
+
+// faceMargin returns the distance, in millimeters, the text keeps from the top
+// and bottom edges of the front face: clear of the chamfers, and of the month
+// labels along the top of the face.
+func (l Layout) faceMargin() float64 {
+	margin := l.Chamfer
+	if l.MonthLabels == MonthLabelsFront {
+		margin += monthLabelMinSize / monthLabelFill
+	}
+	return margin
+}
+
+// create3DText generates the username and year on a front face leaning back by
+// slope, drawn with resolution voxels across the face at scale times their
+// full size.
+func create3DText(username string, year string, baseWidth float64, baseHeight float64, slope float64, resolution int, scale float64) ([]types.Triangle, error) {
 	if username == "" {
 		username = "anonymous"
 	}
@@ -37,9 +127,11 @@ func Create3DText(username string, year string, baseWidth float64, baseHeight fl
 		username,
 		usernameJustification,
 		usernameLeftOffset,
-		usernameFontSize,
+		usernameFontSize*scale,
 		baseWidth,
 		baseHeight,
+		slope,
+		resolution,
 	)
 	if err != nil {
 		return nil, err
@@ -49,9 +141,11 @@ func Create3DText(username string, year string, baseWidth float64, baseHeight fl
 		year,
 		yearJustification,
 		yearLeftOffset,
-		yearFontSize,
+		yearFontSize*scale,
 		baseWidth,
 		baseHeight,
+		slope,
+		resolution,
 	)
 	if err != nil {
 		return nil, err
@@ -60,6 +154,80 @@ func Create3DText(username string, year string, baseWidth float64, baseHeight fl
 	return append(usernameTriangles, yearTriangles...), nil
 }
 
+// Emboss selects the features embossed on the front face of the base.
+type Emboss struct {
+	Text bool // Username and year
+	Logo bool // GitHub logo
+}
+
+// textFit returns the share of their full size the username and year are
+// drawn at on the front face of a base of the given width and thickness,
+// keeping margin millimeters clear along its top and bottom edges. The text
+// grows with the width of the base, so on wide or thin bases it is shrunk to
+// the face instead of running over its edges.
+func textFit(baseWidth, baseHeight, margin float64) float64 {
+	height := max(usernameFontSize, yearFontSize) * baseWidth / baseWidthVoxelResolution
+	return math.Max(0, math.Min(1, (baseHeight-2*margin)/height))
+}
+
+// logoFit returns the share of its full size the logo is drawn at on the front
+// face of a base of the given width and thickness, keeping margin millimeters
+// clear along its bottom edge. Like the text, the logo is shrunk to the face
+// below its offset from the top edge, less the face voxel its last row of
+// pixels may spill over.
+func logoFit(baseWidth, baseHeight, margin float64) (float64, error) {
+	logoRes, err := logoHeightVoxels()
+	if err != nil {
+		return 0, err
+	}
+	toMillimeters := baseWidth / baseWidthVoxelResolution
+	free := (1-logoTopOffset)*baseHeight - margin - toMillimeters
+	return math.Max(0, math.Min(1, free/(logoRes*toMillimeters))), nil
+}
+
+// MaxChamfer returns the largest chamfer, in millimeters, along the top and
+// bottom edges of a base of the given thickness that stays clear of the
+// embossed features. The text and logo shrink to the face left between the
+// chamfers, but the logo hangs from a fixed offset below the top edge, which
+// the chamfer must stay above. Without the logo the chamfer is only limited by
+// the thickness of the base.
+func MaxChamfer(baseHeight float64, emboss Emboss) float64 {
+	if emboss.Logo {
+		return logoTopOffset * baseHeight
+	}
+	return baseHeight / 2
+}
+
+// MaxCornerRadius returns the largest base corner radius, in millimeters, that
+// leaves the front face flat under the embossed features on a base of the given
+// width. Without any emboss the radius is not limited by the front face.
+func MaxCornerRadius(baseWidth float64, emboss Emboss) float64 {
+	limit := math.Inf(1)
+	if emboss.Text {
+		limit = math.Min(limit, math.Min(usernameLeftOffset, 1-yearLeftOffset)*baseWidth)
+	}
+	if emboss.Logo {
+		limit = math.Min(limit, logoLeftOffset*baseWidth)
+	}
+	return limit
+}
+
+// logoHeightVoxels returns the height of the embossed logo in face voxels.
+func logoHeightVoxels() (float64, error) {
+	_, height, err := logoSizeVoxels()
+	return height, err
+}
+
+// logoSizeVoxels returns the width and height of the embossed logo in face voxels.
+func logoSizeVoxels() (float64, float64, error) {
+	img, err := loadLogo()
+	if err != nil {
+		return 0, 0, err
+	}
+	bounds := img.Bounds()
+	return float64(bounds.Dx()) * logoScale, float64(bounds.Dy()) * logoScale, nil
+}
+
 // renderText places text on the face of a skyline, offset from the left and vertically-aligned.
 // The function takes the text to be displayed, offset from left, and font size.
 // It returns an array of types.Triangle.
@@ -68,14 +236,15 @@ func Create3DText(username string, year string, baseWidth float64, baseHeight fl
 //
 //	text (string): The text to be displayed on the skyline's front face.
 //	leftOffsetPercent (float64): The percentage distance from the left to start displaying the text.
-//	fontSize (float64): How large to make the text. Note: It scales with the baseWidthVoxelResolution.
+//	fontSize (float64): How large to make the text, in face voxels. Note: It scales with the baseWidthVoxelResolution.
+//	resolution (int): Number of voxels across the face the text is drawn with.
 //
 // Returns:
 //
 //	([]types.Triangle, error): A slice of triangles representing text.
-func renderText(text string, justification string, leftOffsetPercent float64, fontSize float64, baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
-	// Create a rendering context for the face of the skyline
-	faceWidthRes := baseWidthVoxelResolution
+func renderText(text string, justification string, leftOffsetPercent float64, fontSize float64, baseWidth float64, baseHeight float64, slope float64, resolution int) ([]types.Triangle, error) {
+	// Create a rendering context for the face of the skyline, supersampled for faceVoxels
+	faceWidthRes := resolution * faceSupersample
 	faceHeightRes := int(float64(faceWidthRes) * baseHeight / baseWidth)
 
 	// Create image representing the skyline face
@@ -85,20 +254,11 @@ func renderText(text string, justification string, leftOffsetPercent float64, fo
 	dc.SetRGB(1, 1, 1)
 
 	// Load font into context
-	fontPath, cleanup, err := writeTempFont(PrimaryFont)
+	f, err := loadRasterFont()
 	if err != nil {
-		// Try fallback font
-		fontPath, cleanup, err = writeTempFont(FallbackFont)
-		if err != nil {
-			return nil, errors.New(errors.IOError, "failed to load any fonts", err)
-		}
-	}
-	if err := dc.LoadFontFace(fontPath, fontSize); err != nil {
-		return nil, errors.New(errors.IOError, "failed to load font", err)
+		return nil, err
 	}
-
-	// Draw text on image at desired location
-	var triangles []types.Triangle
+	setFontFace(dc, f, fontSize*float64(faceWidthRes)/baseWidthVoxelResolution)
 
 	// Convert justification to a number
 	var justificationPercent float64
@@ -119,62 +279,79 @@ func renderText(text string, justification string, leftOffsetPercent float64, fo
 		0.5,                                     // Vertically aligned
 	)
 
-	// Convert context image pixels into voxels
-	for x := 0; x < faceWidthRes; x++ {
-		for y := 0; y < faceHeightRes; y++ {
-			if isPixelActive(dc, x, y) {
-				voxel, err := createVoxelOnFace(
-					float64(x),
-					float64(y),
-					voxelDepth,
-					baseWidth,
-					baseHeight,
-				)
-				if err != nil {
-					return nil, errors.New(errors.STLError, "failed to create cube", err)
-				}
-
-				triangles = append(triangles, voxel...)
-			}
-		}
-	}
+	return faceVoxels(dc, baseWidth, baseHeight, slope)
+}
 
-	defer cleanup()
+// faceVoxels converts the white pixels of a drawing of the front face into
+// voxels coming out of the face, merging neighboring pixels into single voxels.
+// The drawing is supersampled, faceSupersample pixels across each face voxel,
+// and a pixel is white where the antialiased glyphs cover at least half of it.
+// Inside the text the merged voxels are as large as before, while along its
+// edges voxels only partly covered keep just their covered parts, halving the
+// steps of curved and slanted outlines. The drawing spans the width of the
+// face at any resolution.
+func faceVoxels(dc *gg.Context, baseWidth float64, baseHeight float64, slope float64) ([]types.Triangle, error) {
+	// Convert pixels to the face voxels createVoxelOnFace is given
+	pixel := float64(baseWidthVoxelResolution) / float64(dc.Width())
+
+	// Voxels on a sloped face step back row by row, so only merge along rows
+	rects := mergeDrawing(dc, slope == 0)
 
+	var triangles []types.Triangle
+	for _, r := range rects {
+		voxel, err := createVoxelOnFace(
+			float64(r.x)*pixel,
+			float64(r.y)*pixel,
+			float64(r.w)*pixel,
+			float64(r.h)*pixel,
+			voxelDepth,
+			baseWidth,
+			baseHeight,
+			slope,
+		)
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create cube", err)
+		}
+
+		triangles = append(triangles, voxel...)
+	}
 	return triangles, nil
 }
 
 // createVoxelOnFace creates a voxel on the face of a skyline by generating a cube at the specified coordinates.
-// The function takes in the x, y coordinates and height.
+// The function takes in the x, y coordinates, the size in face voxels and height.
 // It returns a slice of types.Triangle representing the cube and an error if the cube creation fails.
 //
 // Parameters:
 //
 //	x (float64): The x-coordinate on the skyline face (left to right).
 //	y (float64): The y-coordinate on the skyline face (top to bottom).
+//	width (float64): Number of face voxels the voxel spans from left to right.
+//	tall (float64): Number of face voxels the voxel spans from top to bottom.
 //	height (float64): Distance coming out of the face.
 //
 // Returns:
 //
 //	([]types.Triangle, error): A slice of triangles representing the cube and an error if any.
-func createVoxelOnFace(x float64, y float64, height float64, baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
+func createVoxelOnFace(x float64, y float64, width float64, tall float64, height float64, baseWidth float64, baseHeight float64, slope float64) ([]types.Triangle, error) {
 	// Mapping resolution
 	xResolution := float64(baseWidthVoxelResolution)
 	yResolution := xResolution * baseHeight / baseWidth
 
-	// Pixel size
-	voxelSize := 1.0
-
 	// Scale coordinate to face resolution
 	x = (x / xResolution) * baseWidth
 	y = (y / yResolution) * baseHeight
-	voxelSizeX := (voxelSize / xResolution) * baseWidth
-	voxelSizeY := (voxelSize / yResolution) * baseHeight
+	voxelSizeX := (width / xResolution) * baseWidth
+	voxelSizeY := (tall / yResolution) * baseHeight
+
+	// A sloped face leans back with height, so set the voxel against the face at
+	// its top edge, where the face is furthest back
+	faceY := slope * (baseHeight - y)
 
 	cube, err := CreateCube(
 		// Location (from top left corner of skyline face)
 		x,             // x - Left to right
-		-height,       // y - Negative comes out of face. Positive goes into face.
+		faceY-height,  // y - Negative comes out of face. Positive goes into face.
 		-voxelSizeY-y, // z - Bottom to top
 
 		// Size
@@ -186,81 +363,93 @@ func createVoxelOnFace(x float64, y float64, height float64, baseWidth float64,
 	return cube, err
 }
 
-// GenerateImageGeometry creates 3D geometry from the embedded logo image.
+// GenerateImageGeometry creates 3D geometry from the embedded logo image,
+// shrunk to fit the front face when it is too thin for it.
 func GenerateImageGeometry(baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
-	// Get temporary image file
-	imgPath, cleanup, err := getEmbeddedImage()
+	scale, err := logoFit(baseWidth, baseHeight, 0)
 	if err != nil {
 		return nil, err
 	}
+	return generateImageGeometry(baseWidth, baseHeight, 0, scale)
+}
 
-	defer cleanup()
+// CreateLogo generates 3D geometry for the embedded logo, or the layout's SVG
+// logo, on the front face of the base described by the layout, or in the
+// center of a round base.
+func (l Layout) CreateLogo() ([]types.Triangle, error) {
+	if l.IsRound() {
+		return l.createHubLogo()
+	}
+	scale, err := logoFit(l.Width, l.Height, l.Chamfer)
+	if err != nil {
+		return nil, err
+	}
+	if l.LogoFile != "" {
+		return createSVGLogo(l.LogoFile, l.Width, l.Height, l.FrontSlope(), scale)
+	}
+	return generateImageGeometry(l.Width, l.Height, l.FrontSlope(), scale)
+}
+
+// generateImageGeometry creates the logo on a front face leaning back by slope,
+// at scale times its full size.
+func generateImageGeometry(baseWidth float64, baseHeight float64, slope float64, scale float64) ([]types.Triangle, error) {
+	img, err := loadLogo()
+	if err != nil {
+		return nil, err
+	}
 
 	return renderImage(
-		imgPath,
-		logoScale,
+		img,
+		logoScale*scale,
 		voxelDepth,
 		logoLeftOffset,
 		logoTopOffset,
 		baseWidth,
 		baseHeight,
+		slope,
 	)
 }
 
-// renderImage generates 3D geometry for the given image configuration.
-func renderImage(filePath string, scale float64, height float64, leftOffsetPercent float64, topOffsetPercent float64, baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
+// renderImage generates 3D geometry for the white pixels of an image.
+func renderImage(img image.Image, scale float64, height float64, leftOffsetPercent float64, topOffsetPercent float64, baseWidth float64, baseHeight float64, slope float64) ([]types.Triangle, error) {
 
 	// Get voxel resolution of base face
 	faceWidthRes := baseWidthVoxelResolution
 	faceHeightRes := int(float64(faceWidthRes) * baseHeight / baseWidth)
 
-	// Load image from file
-	reader, err := os.Open(filePath)
-	if err != nil {
-		return nil, errors.New(errors.IOError, "failed to open image", err)
-	}
-	defer func() {
-		if err := reader.Close(); err != nil {
-			closeErr := errors.New(errors.IOError, "failed to close reader", err)
-			// Log the error or handle it appropriately
-			fmt.Println(closeErr)
-		}
-	}()
-	img, err := png.Decode(reader)
-	if err != nil {
-		return nil, errors.New(errors.IOError, "failed to decode PNG", err)
-	}
-
 	// Get image size
 	bounds := img.Bounds()
-	logoWidth := bounds.Max.X
-	logoHeight := bounds.Max.Y
+	logoWidth := bounds.Dx()
+	logoHeight := bounds.Dy()
+
+	// Transfer image pixels onto face of skyline as voxels. Neighboring image
+	// pixels overlap on the face, so a run of n pixels spans (n-1)*scale+1 face voxels.
+	rects := mergePixels(logoWidth, logoHeight, func(x, y int) bool {
+		// Get pixel color and alpha
+		r, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+
+		// If pixel is active (white) and not fully transparent, create a voxel
+		return a > 32768 && r > 32768
+	}, slope == 0)
 
-	// Transfer image pixels onto face of skyline as voxels
 	var triangles []types.Triangle
-	for x := 0; x < logoWidth; x++ {
-		for y := logoHeight - 1; y >= 0; y-- {
-			// Get pixel color and alpha
-			r, _, _, a := img.At(x, y).RGBA()
-
-			// If pixel is active (white) and not fully transparent, create a voxel
-			if a > 32768 && r > 32768 {
-
-				voxel, err := createVoxelOnFace(
-					(leftOffsetPercent*float64(faceWidthRes))+float64(x)*scale,
-					(topOffsetPercent*float64(faceHeightRes))+float64(y)*scale,
-					height,
-					baseWidth,
-					baseHeight,
-				)
-
-				if err != nil {
-					return nil, errors.New(errors.STLError, "failed to create cube", err)
-				}
-
-				triangles = append(triangles, voxel...)
-			}
+	for _, r := range rects {
+		voxel, err := createVoxelOnFace(
+			(leftOffsetPercent*float64(faceWidthRes))+float64(r.x)*scale,
+			(topOffsetPercent*float64(faceHeightRes))+float64(r.y)*scale,
+			float64(r.w-1)*scale+1,
+			float64(r.h-1)*scale+1,
+			height,
+			baseWidth,
+			baseHeight,
+			slope,
+		)
+
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create cube", err)
 		}
+
+		triangles = append(triangles, voxel...)
 	}
 
 	return triangles, nil
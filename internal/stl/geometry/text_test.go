@@ -3,12 +3,11 @@ package geometry
 import (
 	"image"
 	"image/color"
-	"image/png"
 	"math"
-	"os"
 	"testing"
 
 	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/types"
 )
 
 // TestCreate3DText verifies text geometry generation functionality.
@@ -67,6 +66,8 @@ func TestRenderText(t *testing.T) {
 			10.0,   // fontSize
 			200.0,  // baseWidth
 			10.0,   // baseHeight
+			0,      // slope
+			DefaultFaceResolution,
 		)
 
 		if err != nil {
@@ -78,22 +79,125 @@ func TestRenderText(t *testing.T) {
 	})
 }
 
+// TestRenderTextResolution verifies text keeps its size at any face resolution,
+// with fewer triangles at lower resolutions
+func TestRenderTextResolution(t *testing.T) {
+	const width, height = 150.0, 10.0
+	var previous int
+	var wantMinX, wantMaxX, wantMinZ, wantMaxZ float64
+	for _, resolution := range []int{minFaceResolution, PreviewFaceResolution, DefaultFaceResolution, maxFaceResolution} {
+		triangles, err := renderText("mona", "left", usernameLeftOffset, usernameFontSize, width, height, 0, resolution)
+		if err != nil {
+			t.Fatalf("renderText at %d error = %v", resolution, err)
+		}
+		minX, minZ := math.Inf(1), math.Inf(1)
+		maxX, maxZ := math.Inf(-1), math.Inf(-1)
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+				minZ, maxZ = math.Min(minZ, v.Z), math.Max(maxZ, v.Z)
+			}
+		}
+		if resolution == minFaceResolution {
+			wantMinX, wantMaxX, wantMinZ, wantMaxZ = minX, maxX, minZ, maxZ
+		}
+		// The text may only move by a voxel of the coarsest resolution
+		tolerance := 2 * width / minFaceResolution
+		for _, pair := range [][2]float64{{minX, wantMinX}, {maxX, wantMaxX}, {minZ, wantMinZ}, {maxZ, wantMaxZ}} {
+			if math.Abs(pair[0]-pair[1]) > tolerance {
+				t.Errorf("text at %d spans x [%v, %v] z [%v, %v], want about x [%v, %v] z [%v, %v]",
+					resolution, minX, maxX, minZ, maxZ, wantMinX, wantMaxX, wantMinZ, wantMaxZ)
+				break
+			}
+		}
+		if len(triangles) <= previous {
+			t.Errorf("text at %d has %d triangles, want more than %d at the lower resolution", resolution, len(triangles), previous)
+		}
+		previous = len(triangles)
+	}
+}
+
+// TestFaceVoxels verifies fully covered face voxels are merged while partly
+// covered ones keep only their white pixels
+func TestFaceVoxels(t *testing.T) {
+	tests := []struct {
+		name       string
+		drawing    []string // Rows of the supersampled drawing, # for white pixels
+		wantVoxels int
+	}{
+		{"covered voxels merge", []string{"####", "####"}, 1},
+		{"partly covered voxel keeps its pixels", []string{"##..", "#..."}, 2},
+		{"covered and partly covered voxels", []string{"####..", "####..", "###...", "##...."}, 3},
+		{"odd sized drawing", []string{"###"}, 1},
+		{"empty drawing", []string{"....", "...."}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols, rows := len(tt.drawing[0]), len(tt.drawing)
+			dc := gg.NewContext(cols, rows)
+			dc.SetRGB(0, 0, 0)
+			dc.Clear()
+			dc.SetRGB(1, 1, 1)
+			white := 0
+			for y, row := range tt.drawing {
+				for x, c := range row {
+					if c == '#' {
+						dc.SetPixel(x, y)
+						white++
+					}
+				}
+			}
+
+			// One millimeter per pixel, so the volume counts the white pixels
+			triangles, err := faceVoxels(dc, float64(cols), float64(rows), 0)
+			if err != nil {
+				t.Fatalf("faceVoxels() error = %v", err)
+			}
+			if got := len(triangles) / 12; got != tt.wantVoxels {
+				t.Errorf("faceVoxels() made %d voxels, want %d", got, tt.wantVoxels)
+			}
+			if got := meshVolume(triangles) / voxelDepth; math.Abs(got-float64(white)) > epsilon {
+				t.Errorf("faceVoxels() covered %g pixels, want %d", got, white)
+			}
+		})
+	}
+}
+
 // TestRenderImage verifies internal image rendering functionality
 func TestRenderImage(t *testing.T) {
-	t.Run("verify invalid image", func(t *testing.T) {
-		_, err := renderImage(
-			"nonexistent.png", // filePath
-			0.5,               // scale
-			100.0,             // height
-			0.1,               // leftOffsetPercent
-			0.1,               // topOffsetPercent
-			200.0,             // baseWidth
-			10.0,              // baseHeight
-		)
-		if err == nil {
-			t.Error("Expected error for invalid image path")
-		}
-	})
+	tests := []struct {
+		name string
+		img  image.Image
+		want int // Face voxels covered by the image's white pixels
+	}{
+		{"white square", createTestImage(image.Rect(0, 0, 10, 10)), 25},
+		{"offset bounds", createTestImage(image.Rect(3, 7, 13, 17)), 25},
+		{"black image", image.NewRGBA(image.Rect(0, 0, 10, 10)), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := renderImage(
+				tt.img, // img
+				1.0,    // scale
+				1.0,    // height
+				0.1,    // leftOffsetPercent
+				0.1,    // topOffsetPercent
+				200.0,  // baseWidth
+				10.0,   // baseHeight
+				0,      // slope
+			)
+			if err != nil {
+				t.Fatalf("renderImage failed: %v", err)
+			}
+			// Each face voxel is a 1 x 1 x height box once scaled to millimeters
+			voxel := 200.0 / baseWidthVoxelResolution
+			if got := meshVolume(triangles) / (voxel * voxel); math.Abs(got-float64(tt.want)) > epsilon {
+				t.Errorf("renderImage covered %g face voxels, want %d", got, tt.want)
+			}
+		})
+	}
 }
 
 // TestIsPixelActive verifies pixel activity detection
@@ -119,39 +223,20 @@ func TestIsPixelActive(t *testing.T) {
 	})
 }
 
-// createTestPNG creates a temporary PNG file for testing
-func createTestPNG(t *testing.T) string {
-	tmpfile, err := os.CreateTemp("", "test-*.png")
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	// Create a 10x10 test image with some white pixels
-	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+// createTestImage creates an image with white pixels in the top left quarter
+func createTestImage(bounds image.Rectangle) image.Image {
+	img := image.NewRGBA(bounds)
 	white := color.RGBA{255, 255, 255, 255}
-	for y := 0; y < 5; y++ {
-		for x := 0; x < 5; x++ {
-			img.Set(x, y, white)
+	for y := 0; y < bounds.Dy()/2; y++ {
+		for x := 0; x < bounds.Dx()/2; x++ {
+			img.Set(bounds.Min.X+x, bounds.Min.Y+y, white)
 		}
 	}
-
-	if err := png.Encode(tmpfile, img); err != nil {
-		t.Fatal(err)
-	}
-
-	return tmpfile.Name()
+	return img
 }
 
 // TestGenerateImageGeometry verifies image geometry generation functionality
 func TestGenerateImageGeometry(t *testing.T) {
-	// Create a temporary test PNG file
-	testPNGPath := createTestPNG(t)
-	defer func() {
-		if err := os.Remove(testPNGPath); err != nil {
-			t.Fatalf("Failed to remove test PNG file: %v", err)
-		}
-	}()
-
 	t.Run("verify valid image geometry generation", func(t *testing.T) {
 		triangles, err := GenerateImageGeometry(100.0, 5.0)
 		if err != nil {
@@ -181,3 +266,162 @@ func TestGenerateImageGeometry(t *testing.T) {
 		}
 	})
 }
+
+// TestEmbossFit verifies the text and logo keep their full size on the default
+// base and shrink to fit thinner or wider ones
+func TestEmbossFit(t *testing.T) {
+	width, _ := CalculateMultiYearDimensions(1)
+
+	if got := textFit(width, BaseHeight, 0); got != 1 {
+		t.Errorf("textFit() = %v on the default base, want 1", got)
+	}
+	logo, err := logoFit(width, BaseHeight, 0)
+	if err != nil {
+		t.Fatalf("logoFit() error = %v", err)
+	}
+	if logo != 1 {
+		t.Errorf("logoFit() = %v on the default base, want 1", logo)
+	}
+
+	// The text fits a 200mm wide base when shrunk to the face's height
+	text := textFit(200, BaseHeight, 0)
+	if text <= 0 || text >= 1 {
+		t.Fatalf("textFit() = %v on a 200mm wide base, want between 0 and 1", text)
+	}
+	if height := max(usernameFontSize, yearFontSize) * 200 / baseWidthVoxelResolution * text; math.Abs(height-BaseHeight) > 1e-9 {
+		t.Errorf("text is %vmm tall on a %vmm thick base, want it to fill the face", height, BaseHeight)
+	}
+	if margin := textFit(200, BaseHeight, 1); margin >= text {
+		t.Errorf("textFit() = %v with a margin, want less than %v without", margin, text)
+	}
+
+	logo, err = logoFit(200, BaseHeight, 0)
+	if err != nil {
+		t.Fatalf("logoFit() error = %v", err)
+	}
+	if logo <= 0 || logo >= 1 {
+		t.Errorf("logoFit() = %v on a 200mm wide base, want between 0 and 1", logo)
+	}
+}
+
+// TestLayoutEmbossFitsFace verifies the text and logo stay on the front face,
+// clear of the chamfers, on bases too wide or thin for their full size
+func TestLayoutEmbossFitsFace(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"wide base", Config{BaseWidth: 300}},
+		{"thin base", Config{BaseHeight: 4}},
+		{"chamfer", Config{Chamfer: 1.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewLayout(tt.cfg, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			text, err := layout.CreateText("mona", "2024")
+			if err != nil {
+				t.Fatalf("CreateText() error = %v", err)
+			}
+			logo, err := layout.CreateLogo()
+			if err != nil {
+				t.Fatalf("CreateLogo() error = %v", err)
+			}
+
+			// The front face runs from z = 0 at its top down to the bottom of the base
+			top, bottom := -layout.Chamfer, -layout.Height+layout.Chamfer
+			for _, tri := range append(text, logo...) {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if v.Z > top+1e-9 || v.Z < bottom-1e-9 {
+						t.Fatalf("emboss reaches z = %v, want between %v and %v", v.Z, bottom, top)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestMaxChamfer verifies the chamfer limit grows with the base thickness and
+// is only set by the logo
+func TestMaxChamfer(t *testing.T) {
+	both := Emboss{Text: true, Logo: true}
+	standard := MaxChamfer(BaseHeight, both)
+	thick := MaxChamfer(2*BaseHeight, both)
+	if standard < 1 || thick <= standard {
+		t.Errorf("MaxChamfer() = %v on a standard base and %v on a thicker one, want at least 1mm and growing", standard, thick)
+	}
+	if chamfer := MaxChamfer(BaseHeight, Emboss{Text: true}); chamfer != BaseHeight/2 {
+		t.Errorf("MaxChamfer() with text alone = %v, want %v", chamfer, BaseHeight/2)
+	}
+}
+
+// TestEmbossLimits verifies omitted features no longer constrain the base
+func TestEmbossLimits(t *testing.T) {
+	width, _ := CalculateMultiYearDimensions(1)
+	both := Emboss{Text: true, Logo: true}
+
+	if chamfer := MaxChamfer(BaseHeight, Emboss{}); chamfer != BaseHeight/2 {
+		t.Errorf("MaxChamfer() without emboss = %v, want %v", chamfer, BaseHeight/2)
+	}
+	if radius := MaxCornerRadius(width, Emboss{}); !math.IsInf(radius, 1) {
+		t.Errorf("MaxCornerRadius() without emboss = %v, want unlimited", radius)
+	}
+	if radius := MaxCornerRadius(width, both); radius <= 0 || math.IsInf(radius, 1) {
+		t.Errorf("MaxCornerRadius() with emboss = %v, want a positive limit", radius)
+	}
+}
+
+// TestParseTextStyle verifies text style names are parsed case-insensitively
+func TestParseTextStyle(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    TextStyle
+		wantErr bool
+	}{
+		{"", DefaultTextStyle, false},
+		{"voxel", TextVoxel, false},
+		{"Vector", TextVector, false},
+		{"bitmap", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTextStyle(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTextStyle(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTextStyle(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkCreateText measures drawing the username and year on the front
+// face, in each text style and at the preview and default face resolutions.
+func BenchmarkCreateText(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		cfg  Config
+	}{
+		{"voxel", Config{}},
+		{"voxel-preview", Config{FaceResolution: PreviewFaceResolution}},
+		{"vector", Config{TextStyle: TextVector}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			layout, err := NewLayout(bench.cfg, 1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var triangles []types.Triangle
+			for i := 0; i < b.N; i++ {
+				if triangles, err = layout.CreateText("octocat", "2024"); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(triangles)), "triangles")
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// stackTierShare is the rise of each tier of stacked years as a share of the
+// base thickness.
+const stackTierShare = 0.5
+
+// TierElevation returns the height of the top of the tier a year's towers
+// stand on, counting years from the front. Without stacking every year
+// stands on the top face of the base.
+func (l Layout) TierElevation(yearIndex int) float64 {
+	return float64(yearIndex) * l.TierHeight
+}
+
+// tierClearance returns how far the tiers keep from the sides and back of the
+// top face, clear of its rounded corners and sloped or chamfered edges.
+func (l Layout) tierClearance() float64 {
+	return math.Max(l.CornerRadius, l.topInset())
+}
+
+// createTiers generates the tiers of stacked years, one for every year behind
+// the front-most, each rising TierHeight above the one in front. A tier spans
+// from the middle of the space in front of its year to the back of the top face, so the tiers
+// step up like a wedding cake and every year stays in view.
+func (l Layout) createTiers() ([]types.Triangle, error) {
+	if l.TierHeight == 0 {
+		return nil, nil
+	}
+	margin := l.tierClearance()
+	back := l.Depth - margin
+
+	var triangles []types.Triangle
+	for year := 1; year < l.YearCount; year++ {
+		_, front := l.TowerPosition(year, 0, 0)
+		front -= l.yearSeparation() / 2
+		tier, err := createBox(margin, front, l.TierElevation(year-1), l.Width-2*margin, back-front, l.TierHeight)
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create tier", err)
+		}
+		triangles = append(triangles, tier...)
+	}
+	return triangles, nil
+}
@@ -0,0 +1,107 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestLayoutTierElevation verifies each stacked year rises one tier above the year in front
+func TestLayoutTierElevation(t *testing.T) {
+	flat, err := NewLayout(Config{}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if flat.TierHeight != 0 || flat.TierElevation(2) != 0 {
+		t.Errorf("unstacked layout has tier height %v and elevation %v, want zero", flat.TierHeight, flat.TierElevation(2))
+	}
+
+	stacked, err := NewLayout(Config{Stack: true, BaseHeight: 8}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if stacked.TierHeight != 4 {
+		t.Errorf("TierHeight = %v, want half the base thickness", stacked.TierHeight)
+	}
+	for year, want := range []float64{0, 4, 8} {
+		if got := stacked.TierElevation(year); got != want {
+			t.Errorf("TierElevation(%d) = %v, want %v", year, got, want)
+		}
+	}
+}
+
+// TestLayoutCreateTiers verifies the tiers step up behind each year and carry its towers
+func TestLayoutCreateTiers(t *testing.T) {
+	layout, err := NewLayout(Config{Stack: true, Gap: 0.5, CornerRadius: 3}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	tiers, err := layout.createTiers()
+	if err != nil {
+		t.Fatalf("createTiers() error = %v", err)
+	}
+	if len(tiers) != 2*12 {
+		t.Fatalf("createTiers() returned %d triangles, want two boxes", len(tiers))
+	}
+
+	var top float64
+	for _, tri := range tiers {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			top = math.Max(top, v.Z)
+			if v.X < layout.CornerRadius-epsilon || v.X > layout.Width-layout.CornerRadius+epsilon || v.Y > layout.Depth-layout.CornerRadius+epsilon {
+				t.Fatalf("tier vertex %v cuts into the rounded corners", v)
+			}
+			_, front := layout.TowerPosition(1, 0, 0)
+			if v.Y < front-layout.Gap/2-epsilon {
+				t.Fatalf("tier vertex %v reaches under the front-most year", v)
+			}
+		}
+	}
+	if top != layout.TierElevation(2) {
+		t.Errorf("tiers rise to %v, want %v", top, layout.TierElevation(2))
+	}
+
+	// The back year's towers stand on the top tier
+	tower, err := layout.createTower(2, 0, 0, 5)
+	if err != nil {
+		t.Fatalf("createTower() error = %v", err)
+	}
+	bottom := math.Inf(1)
+	for _, tri := range tower {
+		bottom = math.Min(bottom, math.Min(tri.V1.Z, math.Min(tri.V2.Z, tri.V3.Z)))
+	}
+	if bottom != layout.TierElevation(2) {
+		t.Errorf("back tower stands at %v, want on the top tier at %v", bottom, layout.TierElevation(2))
+	}
+
+	// Without stacking the base has no tiers
+	layout, err = NewLayout(Config{}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if tiers, err := layout.createTiers(); err != nil || len(tiers) != 0 {
+		t.Errorf("createTiers() without stacking = %d triangles, %v, want none", len(tiers), err)
+	}
+}
+
+// TestConfigValidateStack verifies stacking is rejected with features that need the top tier's place
+func TestConfigValidateStack(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"stack", Config{Stack: true}, false},
+		{"stack with avatar", Config{Stack: true, Avatar: true}, true},
+		{"stack on a round base", Config{Stack: true, Arrangement: ArrangementRadial}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TowerShape identifies the cross-section of the contribution towers.
+type TowerShape string
+
+// Supported tower shapes.
+const (
+	TowerSquare   TowerShape = "square"   // Square columns filling their cell
+	TowerCylinder TowerShape = "cylinder" // Round columns, approximated by a configurable number of sides
+	TowerHex      TowerShape = "hex"      // Hexagonal prisms
+)
+
+// DefaultTowerShape is the tower shape used when none is configured.
+const DefaultTowerShape = TowerSquare
+
+// Sides of the polygon approximating a cylinder tower.
+const (
+	DefaultTowerSegments = 16  // Sides of a cylinder tower when none are configured
+	minTowerSegments     = 3   // Fewest sides that still form a column
+	maxTowerSegments     = 128 // Most sides, beyond which a tower only adds triangles
+)
+
+// towerShapes lists the supported tower shapes in the order they are presented to users.
+var towerShapes = []TowerShape{TowerSquare, TowerCylinder, TowerHex}
+
+// TowerShapes returns the names of all supported tower shapes.
+func TowerShapes() []string {
+	names := make([]string, len(towerShapes))
+	for i, s := range towerShapes {
+		names[i] = string(s)
+	}
+	return names
+}
+
+// ParseTowerShape converts a user supplied tower shape name into a TowerShape.
+// Matching is case-insensitive and an empty string selects DefaultTowerShape.
+func ParseTowerShape(name string) (TowerShape, error) {
+	if name == "" {
+		return DefaultTowerShape, nil
+	}
+	for _, s := range towerShapes {
+		if strings.EqualFold(name, string(s)) {
+			return s, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported tower shape %q (supported: %s)", name, strings.Join(TowerShapes(), ", ")), nil)
+}
+
+// validateTowerSegments checks that a configured number of cylinder sides is
+// usable, with zero selecting DefaultTowerSegments.
+func validateTowerSegments(segments int) error {
+	if segments != 0 && (segments < minTowerSegments || segments > maxTowerSegments) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("tower segments must be between %d and %d", minTowerSegments, maxTowerSegments), nil)
+	}
+	return nil
+}
+
+// sides returns the number of sides of the polygon outlining a tower of
+// shape s, given the configured number of sides for cylinders.
+func (s TowerShape) sides(segments int) int {
+	switch s {
+	case TowerCylinder:
+		if segments == 0 {
+			return DefaultTowerSegments
+		}
+		return segments
+	case TowerHex:
+		return 6
+	default:
+		return 4
+	}
+}
+
+// createTower generates the tower for a day of the given height, with the
+// layout's tower shape standing in the day's cell, topped by its tower top.
+// The tower stands on its year's tier when the years are stacked.
+func (l Layout) createTower(yearIndex, weekIdx, dayIdx int, height float64) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	var err error
+	if l.TowerShape == TowerSquare && l.TowerTop == TowerFlat && !l.IsRound() {
+		x, y := l.TowerPosition(yearIndex, weekIdx, dayIdx)
+		triangles, err = CreateColumn(x, y, height, l.CellSize)
+	} else {
+		triangles, err = createCappedPrism(l.towerOutline(yearIndex, weekIdx, dayIdx), height, l.TowerTop, l.CellSize*towerCapShare)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if elevation := l.TierElevation(yearIndex); elevation > 0 {
+		for i := range triangles {
+			triangles[i].V1.Z += elevation
+			triangles[i].V2.Z += elevation
+			triangles[i].V3.Z += elevation
+		}
+	}
+	return triangles, nil
+}
+
+// towerOutline returns the outline of a day's tower, counter-clockwise from above.
+func (l Layout) towerOutline(yearIndex, weekIdx, dayIdx int) []point2D {
+	round := l.Arrangement.round()
+	if l.TowerShape == TowerSquare {
+		if round != nil {
+			outline := round.footprint(l, yearIndex, weekIdx, dayIdx)
+			return outline[:]
+		}
+		x, y := l.TowerPosition(yearIndex, weekIdx, dayIdx)
+		return []point2D{{X: x, Y: y}, {X: x + l.CellSize, Y: y}, {X: x + l.CellSize, Y: y + l.CellSize}, {X: x, Y: y + l.CellSize}}
+	}
+
+	var center point2D
+	if round != nil {
+		for _, p := range round.footprint(l, yearIndex, weekIdx, dayIdx) {
+			center.X += p.X / 4
+			center.Y += p.Y / 4
+		}
+	} else {
+		x, y := l.TowerPosition(yearIndex, weekIdx, dayIdx)
+		center = point2D{X: x + l.CellSize/2, Y: y + l.CellSize/2}
+	}
+	return regularPolygon(center, l.CellSize/2, l.TowerShape.sides(l.TowerSegments))
+}
+
+// regularPolygon returns the corners of a polygon with the given number of
+// sides inscribed in a circle, counter-clockwise from above. The first corner
+// points along X, so a hexagon has flat sides facing the front and back.
+func regularPolygon(center point2D, radius float64, sides int) []point2D {
+	points := make([]point2D, sides)
+	for i := range points {
+		angle := 2 * math.Pi * float64(i) / float64(sides)
+		points[i] = point2D{X: center.X + radius*math.Cos(angle), Y: center.Y + radius*math.Sin(angle)}
+	}
+	return points
+}
@@ -0,0 +1,161 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestParseTowerShape verifies tower shape name parsing
+func TestParseTowerShape(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    TowerShape
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultTowerShape, false},
+		{"square", "square", TowerSquare, false},
+		{"cylinder", "cylinder", TowerCylinder, false},
+		{"hex", "hex", TowerHex, false},
+		{"case insensitive", "HEX", TowerHex, false},
+		{"unknown", "cone", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTowerShape(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTowerShape(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTowerShape(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConfigValidateTowerShape verifies rejection of unusable tower shapes and segment counts
+func TestConfigValidateTowerShape(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"hex", Config{TowerShape: TowerHex}, false},
+		{"unknown shape", Config{TowerShape: "cone"}, true},
+		{"custom segments", Config{TowerShape: TowerCylinder, TowerSegments: 32}, false},
+		{"fewest segments", Config{TowerShape: TowerCylinder, TowerSegments: 3}, false},
+		{"too few segments", Config{TowerShape: TowerCylinder, TowerSegments: 2}, true},
+		{"negative segments", Config{TowerShape: TowerCylinder, TowerSegments: -8}, true},
+		{"too many segments", Config{TowerShape: TowerCylinder, TowerSegments: 1000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRegularPolygon verifies polygon corners lie on their circle, counter-clockwise
+func TestRegularPolygon(t *testing.T) {
+	center := point2D{X: 10, Y: 20}
+	for _, sides := range []int{3, 6, 16} {
+		points := regularPolygon(center, 2, sides)
+		if len(points) != sides {
+			t.Fatalf("regularPolygon() returned %d points, want %d", len(points), sides)
+		}
+		for _, p := range points {
+			if r := dist(p, center); math.Abs(r-2) > epsilon {
+				t.Errorf("corner %v is %v from the center, want 2", p, r)
+			}
+		}
+		if area := contour(points).signedArea(); area <= 0 {
+			t.Errorf("%d-sided polygon has signed area %v, want counter-clockwise", sides, area)
+		}
+	}
+}
+
+// TestLayoutCreateTowerShapes verifies each shape generates closed towers inside their cells
+func TestLayoutCreateTowerShapes(t *testing.T) {
+	tests := []struct {
+		shape     TowerShape
+		segments  int
+		triangles int
+	}{
+		{TowerSquare, 0, 12},
+		{TowerHex, 0, 4 * 6},
+		{TowerCylinder, 0, 4 * DefaultTowerSegments},
+		{TowerCylinder, 5, 4 * 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.shape), func(t *testing.T) {
+			layout, err := NewLayout(Config{TowerShape: tt.shape, TowerSegments: tt.segments, Gap: 0.5}, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			tower, err := layout.createTower(0, 10, 3, 5)
+			if err != nil {
+				t.Fatalf("createTower() error = %v", err)
+			}
+			if len(tower) != tt.triangles {
+				t.Errorf("createTower() returned %d triangles, want %d", len(tower), tt.triangles)
+			}
+			if !isClosedMesh(tower) {
+				t.Fatal("tower is not closed")
+			}
+			if volume := meshVolume(tower); volume <= 0 {
+				t.Fatalf("tower has volume %v, want outward faces", volume)
+			}
+
+			x, y := layout.TowerPosition(0, 10, 3)
+			for _, tri := range tower {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if v.X < x-epsilon || v.X > x+layout.CellSize+epsilon || v.Y < y-epsilon || v.Y > y+layout.CellSize+epsilon {
+						t.Fatalf("vertex %v is outside the cell at %v, %v", v, x, y)
+					}
+					if v.Z < -epsilon || v.Z > 5+epsilon {
+						t.Fatalf("vertex %v is outside the tower's height", v)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestLayoutCreateRoundTowerShapes verifies shaped towers stand in their footprint on a round base
+func TestLayoutCreateRoundTowerShapes(t *testing.T) {
+	for _, arrangement := range []Arrangement{ArrangementRadial, ArrangementSpiral} {
+		t.Run(string(arrangement), func(t *testing.T) {
+			layout, err := NewLayout(Config{Arrangement: arrangement, TowerShape: TowerCylinder}, 1)
+			if err != nil {
+				t.Fatalf("NewLayout() error = %v", err)
+			}
+			tower, err := layout.createTower(0, 20, 4, 5)
+			if err != nil {
+				t.Fatalf("createTower() error = %v", err)
+			}
+			if !isClosedMesh(tower) {
+				t.Fatal("tower is not closed")
+			}
+
+			var center point2D
+			for _, p := range layout.Arrangement.round().footprint(layout, 0, 20, 4) {
+				center.X += p.X / 4
+				center.Y += p.Y / 4
+			}
+			for _, tri := range tower {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if r := dist(point2D{X: v.X, Y: v.Y}, center); r > layout.CellSize/2+epsilon {
+						t.Fatalf("vertex %v is %v from the footprint's center, want at most %v", v, r, layout.CellSize/2)
+					}
+				}
+			}
+		})
+	}
+}
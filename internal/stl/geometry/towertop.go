@@ -0,0 +1,106 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TowerTop identifies the shape of the top of each tower.
+type TowerTop string
+
+// Supported tower tops.
+const (
+	TowerFlat    TowerTop = "flat"    // Flat tops
+	TowerRounded TowerTop = "rounded" // Domed tops curving down to the sides
+	TowerPyramid TowerTop = "pyramid" // Tops rising to a point in the middle
+)
+
+// DefaultTowerTop is the tower top used when none is configured.
+const DefaultTowerTop = TowerFlat
+
+const (
+	towerCapShare = 0.5 // Height of a rounded or pyramid top as a share of the cell size
+	towerCapSteps = 6   // Rings approximating the curve of a rounded top
+)
+
+// towerTops lists the supported tower tops in the order they are presented to users.
+var towerTops = []TowerTop{TowerFlat, TowerRounded, TowerPyramid}
+
+// TowerTops returns the names of all supported tower tops.
+func TowerTops() []string {
+	names := make([]string, len(towerTops))
+	for i, t := range towerTops {
+		names[i] = string(t)
+	}
+	return names
+}
+
+// ParseTowerTop converts a user supplied tower top name into a TowerTop.
+// Matching is case-insensitive and an empty string selects DefaultTowerTop.
+func ParseTowerTop(name string) (TowerTop, error) {
+	if name == "" {
+		return DefaultTowerTop, nil
+	}
+	for _, t := range towerTops {
+		if strings.EqualFold(name, string(t)) {
+			return t, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported tower top %q (supported: %s)", name, strings.Join(TowerTops(), ", ")), nil)
+}
+
+// capRings returns the share of the outline kept and the share of the cap's
+// height reached by each ring of a cap, from the top of the walls to its peak.
+func (t TowerTop) capRings() (scales, heights []float64) {
+	switch t {
+	case TowerPyramid:
+		return []float64{1, 0}, []float64{0, 1}
+	case TowerRounded:
+		for i := 0; i <= towerCapSteps; i++ {
+			angle := float64(i) * math.Pi / 2 / towerCapSteps
+			scales = append(scales, math.Cos(angle))
+			heights = append(heights, math.Sin(angle))
+		}
+		// The peak is exactly one point, so the cap closes
+		scales[towerCapSteps], heights[towerCapSteps] = 0, 1
+		return scales, heights
+	default:
+		return []float64{1}, []float64{0}
+	}
+}
+
+// createCappedPrism generates a column of the given height standing on the
+// base with the given convex outline, topped by a cap of the given height
+// shrinking the outline towards its center. The cap takes the whole column
+// when the column is shorter than the cap.
+func createCappedPrism(outline []point2D, height float64, top TowerTop, capHeight float64) ([]types.Triangle, error) {
+	if top == TowerFlat {
+		return createPrism(outline, height)
+	}
+	capHeight = math.Min(capHeight, height)
+
+	var center point2D
+	for _, p := range outline {
+		center.X += p.X / float64(len(outline))
+		center.Y += p.Y / float64(len(outline))
+	}
+
+	rings := []outlineRing{towerRing(outline, 0)}
+	scales, heights := top.capRings()
+	for i, scale := range scales {
+		z := height - capHeight + heights[i]*capHeight
+		if z <= rings[len(rings)-1].z {
+			continue
+		}
+		shrunk := make([]point2D, len(outline))
+		for j, p := range outline {
+			shrunk[j] = point2D{X: center.X + scale*(p.X-center.X), Y: center.Y + scale*(p.Y-center.Y)}
+		}
+		rings = append(rings, towerRing(shrunk, z))
+	}
+	return createLoft(rings)
+}
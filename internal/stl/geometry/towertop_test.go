@@ -0,0 +1,115 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestParseTowerTop verifies tower top name parsing
+func TestParseTowerTop(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    TowerTop
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultTowerTop, false},
+		{"flat", "flat", TowerFlat, false},
+		{"rounded", "rounded", TowerRounded, false},
+		{"pyramid", "pyramid", TowerPyramid, false},
+		{"case insensitive", "Rounded", TowerRounded, false},
+		{"unknown", "spire", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTowerTop(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTowerTop(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTowerTop(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCreateCappedPrism verifies capped towers are closed, keep their height and rise to a peak
+func TestCreateCappedPrism(t *testing.T) {
+	square := []point2D{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}
+	flat, err := createCappedPrism(square, 5, TowerFlat, 1)
+	if err != nil {
+		t.Fatalf("createCappedPrism() error = %v", err)
+	}
+	flatVolume := meshVolume(flat)
+
+	tests := []struct {
+		name   string
+		top    TowerTop
+		height float64
+	}{
+		{"rounded", TowerRounded, 5},
+		{"pyramid", TowerPyramid, 5},
+		{"rounded shorter than its cap", TowerRounded, 0.5},
+		{"pyramid shorter than its cap", TowerPyramid, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tower, err := createCappedPrism(square, tt.height, tt.top, 1)
+			if err != nil {
+				t.Fatalf("createCappedPrism() error = %v", err)
+			}
+			if !isClosedMesh(tower) {
+				t.Fatal("tower is not closed")
+			}
+			volume := meshVolume(tower)
+			if volume <= 0 {
+				t.Fatalf("tower has volume %v, want outward faces", volume)
+			}
+			if tt.height == 5 && volume >= flatVolume {
+				t.Errorf("capped tower has volume %v, want less than the flat tower's %v", volume, flatVolume)
+			}
+
+			peak := math.Inf(-1)
+			for _, tri := range tower {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					peak = math.Max(peak, v.Z)
+				}
+			}
+			if math.Abs(peak-tt.height) > epsilon {
+				t.Errorf("tower peaks at %v, want %v", peak, tt.height)
+			}
+			for _, tri := range tower {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if v.Z == peak && (math.Abs(v.X-1) > epsilon || math.Abs(v.Y-1) > epsilon) {
+						t.Fatalf("peak %v is not over the center of the outline", v)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestLayoutCreateTowerTops verifies every shape and top combination generates closed towers
+func TestLayoutCreateTowerTops(t *testing.T) {
+	for _, arrangement := range arrangements {
+		for _, shape := range towerShapes {
+			for _, top := range towerTops {
+				layout, err := NewLayout(Config{Arrangement: arrangement, TowerShape: shape, TowerTop: top}, 1)
+				if err != nil {
+					t.Fatalf("NewLayout() error = %v", err)
+				}
+				tower, err := layout.createTower(0, 5, 2, layout.MinHeight)
+				if err != nil {
+					t.Fatalf("%s %s %s: createTower() error = %v", arrangement, shape, top, err)
+				}
+				if !isClosedMesh(tower) {
+					t.Errorf("%s %s %s: tower is not closed", arrangement, shape, top)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// createVectorText generates the username and year on a front face leaning
+// back by slope by extruding the font's glyph outlines. It places the text
+// exactly where create3DText places its voxels, at scale times its full size.
+func createVectorText(username string, year string, baseWidth float64, baseHeight float64, slope float64, scale float64) ([]types.Triangle, error) {
+	if username == "" {
+		username = "anonymous"
+	}
+
+	f, err := loadOutlineFont()
+	if err != nil {
+		return nil, err
+	}
+
+	usernameTriangles, err := renderVectorText(f, username, usernameJustification, usernameLeftOffset, usernameFontSize*scale, baseWidth, baseHeight, slope)
+	if err != nil {
+		return nil, err
+	}
+	yearTriangles, err := renderVectorText(f, year, yearJustification, yearLeftOffset, yearFontSize*scale, baseWidth, baseHeight, slope)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(usernameTriangles, yearTriangles...), nil
+}
+
+// loadOutlineFont parses the embedded font, falling back to the secondary font.
+func loadOutlineFont() (*sfnt.Font, error) {
+	var lastErr error
+	for _, name := range []string{PrimaryFont, FallbackFont} {
+		fontBytes, err := embeddedAssets.ReadFile("assets/" + name)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		f, err := sfnt.Parse(fontBytes)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return f, nil
+	}
+	return nil, errors.New(errors.IOError, "failed to load any fonts", lastErr)
+}
+
+// renderVectorText places text on the face of a skyline, offset from the left
+// and vertically-aligned, as solid glyphs coming out of the face by voxelDepth.
+// The font size and offsets are in face voxels, as for renderText.
+func renderVectorText(f *sfnt.Font, text string, justification string, leftOffsetPercent float64, fontSize float64, baseWidth float64, baseHeight float64, slope float64) ([]types.Triangle, error) {
+	var buf sfnt.Buffer
+
+	// Load outlines in font units for the best precision, then scale them to
+	// face voxels and on to millimeters
+	unitsPerEm := f.UnitsPerEm()
+	ppem := fixed.I(int(unitsPerEm))
+	toMillimeters := baseWidth / baseWidthVoxelResolution
+	unitScale := fontSize / float64(unitsPerEm) * toMillimeters
+
+	// Lay out the glyphs along the baseline, in font units
+	type placedGlyph struct {
+		segments sfnt.Segments
+		x        float64
+	}
+	var glyphs []placedGlyph
+	var pen float64
+	prev := sfnt.GlyphIndex(0)
+	for i, r := range text {
+		index, err := f.GlyphIndex(&buf, r)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to look up glyph", err)
+		}
+		if i > 0 {
+			if kern, err := f.Kern(&buf, prev, index, ppem, font.HintingNone); err == nil {
+				pen += fixedToFloat(kern)
+			}
+		}
+		segments, err := f.LoadGlyph(&buf, index, ppem, nil)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to load glyph outline", err)
+		}
+		// The buffer is reused by the next glyph, so keep a copy of the outline
+		glyphs = append(glyphs, placedGlyph{segments: append(sfnt.Segments(nil), segments...), x: pen})
+
+		advance, err := f.GlyphAdvance(&buf, index, ppem, font.HintingNone)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to measure glyph", err)
+		}
+		pen += fixedToFloat(advance)
+		prev = index
+	}
+
+	// Anchor the text the same way as the voxel renderer: justified around the
+	// left offset and vertically centered on the face using the font's height
+	faceWidthRes := baseWidthVoxelResolution
+	faceHeightRes := int(float64(faceWidthRes) * baseHeight / baseWidth)
+	var justificationPercent float64
+	switch justification {
+	case "center":
+		justificationPercent = 0.5
+	case "right":
+		justificationPercent = 1.0
+	}
+	originX := float64(faceWidthRes)*leftOffsetPercent*toMillimeters - justificationPercent*pen*unitScale
+	baselineZ := -(float64(faceHeightRes)*0.5 + 0.5*fontSize*72/96) * toMillimeters
+
+	var triangles []types.Triangle
+	for _, glyph := range glyphs {
+		contours := glyphContours(glyph.segments, func(p fixed.Point26_6) point2D {
+			return point2D{
+				X: originX + (glyph.x+fixedToFloat(p.X))*unitScale,
+				Y: baselineZ - fixedToFloat(p.Y)*unitScale, // Glyph Y axis points down
+			}
+		})
+		for _, poly := range nestContours(contours) {
+			solid, err := extrudeOnFace(poly, voxelDepth, baseHeight, slope)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, solid...)
+		}
+	}
+
+	return triangles, nil
+}
+
+// glyphContours flattens a glyph outline into closed contours, approximating
+// each curve with curveSegments straight segments.
+func glyphContours(segments sfnt.Segments, project func(fixed.Point26_6) point2D) []contour {
+	var contours []contour
+	var current contour
+	var last fixed.Point26_6
+	flush := func() {
+		if len(current) >= 3 {
+			contours = append(contours, current)
+		}
+		current = nil
+	}
+
+	for _, s := range segments {
+		switch s.Op {
+		case sfnt.SegmentOpMoveTo:
+			flush()
+			current = append(current, project(s.Args[0]))
+			last = s.Args[0]
+		case sfnt.SegmentOpLineTo:
+			current = append(current, project(s.Args[0]))
+			last = s.Args[0]
+		case sfnt.SegmentOpQuadTo:
+			current = appendQuadratic(current, project(last), project(s.Args[0]), project(s.Args[1]))
+			last = s.Args[1]
+		case sfnt.SegmentOpCubeTo:
+			current = appendCubic(current, project(last), project(s.Args[0]), project(s.Args[1]), project(s.Args[2]))
+			last = s.Args[2]
+		}
+	}
+	flush()
+
+	// Outlines close back on their starting point, which is already the first point
+	for i, c := range contours {
+		if len(c) > 1 && samePoint(c[0], c[len(c)-1]) {
+			contours[i] = c[:len(c)-1]
+		}
+	}
+	return contours
+}
+
+// extrudeOnFace generates a solid from a polygon drawn on the front face of a
+// base of the given height, with X to the right and Y up to the top of the
+// base at zero, coming out of the face by depth. The back of the solid follows
+// a face leaning back by slope.
+func extrudeOnFace(poly polygon, depth float64, baseHeight float64, slope float64) ([]types.Triangle, error) {
+	caps, err := poly.triangulate()
+	if err != nil {
+		return nil, err
+	}
+
+	back := func(p point2D) types.Point3D {
+		return types.Point3D{X: p.X, Y: slope * (baseHeight + p.Y), Z: p.Y}
+	}
+	front := func(p point2D) types.Point3D {
+		return types.Point3D{X: p.X, Y: slope*(baseHeight+p.Y) - depth, Z: p.Y}
+	}
+
+	var triangles []types.Triangle
+	add := func(a, b, c types.Point3D) error {
+		if isZeroVector(vectorCross(vectorSubtract(b, a), vectorSubtract(c, a))) {
+			return nil
+		}
+		normal, err := calculateNormal(a, b, c)
+		if err != nil {
+			return err
+		}
+		triangles = append(triangles, types.Triangle{Normal: normal, V1: a, V2: b, V3: c})
+		return nil
+	}
+
+	// Counter-clockwise triangles face out of the front of the base
+	for _, t := range caps {
+		if err := add(front(t[0]), front(t[1]), front(t[2])); err != nil {
+			return nil, err
+		}
+		if err := add(back(t[0]), back(t[2]), back(t[1])); err != nil {
+			return nil, err
+		}
+	}
+
+	// Side walls face away from the outer contour and into the holes
+	for _, c := range append([]contour{poly.outer}, poly.holes...) {
+		for i, a := range c {
+			b := c[(i+1)%len(c)]
+			if err := add(back(a), back(b), front(b)); err != nil {
+				return nil, err
+			}
+			if err := add(back(a), front(b), front(a)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return triangles, nil
+}
+
+// fixedToFloat converts a 26.6 fixed point value to a float.
+func fixedToFloat(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
@@ -0,0 +1,93 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestCreateVectorText verifies outline text is closed and lighter than voxel text
+func TestCreateVectorText(t *testing.T) {
+	width, _ := CalculateMultiYearDimensions(1)
+
+	vector, err := createVectorText("mona", "2024", width, BaseHeight, 0, 1)
+	if err != nil {
+		t.Fatalf("createVectorText() error = %v", err)
+	}
+	if len(vector) == 0 {
+		t.Fatal("createVectorText() returned no triangles")
+	}
+	if !isClosedMesh(vector) {
+		t.Error("vector text is not a closed mesh")
+	}
+	if volume := meshVolume(vector); volume <= 0 {
+		t.Errorf("vector text volume = %v, want positive", volume)
+	}
+
+	voxel, err := create3DText("mona", "2024", width, BaseHeight, 0, DefaultFaceResolution, 1)
+	if err != nil {
+		t.Fatalf("create3DText() error = %v", err)
+	}
+	// Measure voxel text by the pixels it covers, as merging voxels hides their number
+	voxelSize := width / baseWidthVoxelResolution
+	pixels := int(math.Round(meshVolume(voxel) / (voxelSize * voxelSize * voxelDepth)))
+	if len(vector)*10 > pixels*12 {
+		t.Errorf("vector text has %d triangles, want far fewer than the %d of a voxel per pixel", len(vector), pixels*12)
+	}
+}
+
+// TestVectorTextPlacement verifies outline text covers the same area as voxel text
+func TestVectorTextPlacement(t *testing.T) {
+	width, _ := CalculateMultiYearDimensions(1)
+	bounds := func(name string, create func(string, string, float64, float64, float64) ([]types.Triangle, error)) (minX, maxX, minZ, maxZ float64) {
+		t.Helper()
+		triangles, err := create("mona", "2024", width, BaseHeight, 0)
+		if err != nil {
+			t.Fatalf("%s error = %v", name, err)
+		}
+		minX, minZ = math.Inf(1), math.Inf(1)
+		maxX, maxZ = math.Inf(-1), math.Inf(-1)
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+				minZ, maxZ = math.Min(minZ, v.Z), math.Max(maxZ, v.Z)
+			}
+		}
+		return minX, maxX, minZ, maxZ
+	}
+
+	vMinX, vMaxX, vMinZ, vMaxZ := bounds("createVectorText", func(username, year string, baseWidth, baseHeight, slope float64) ([]types.Triangle, error) {
+		return createVectorText(username, year, baseWidth, baseHeight, slope, 1)
+	})
+	xMinX, xMaxX, xMinZ, xMaxZ := bounds("create3DText", func(username, year string, baseWidth, baseHeight, slope float64) ([]types.Triangle, error) {
+		return create3DText(username, year, baseWidth, baseHeight, slope, DefaultFaceResolution, 1)
+	})
+	tolerance := 2 * width / baseWidthVoxelResolution
+	for _, pair := range [][2]float64{{vMinX, xMinX}, {vMaxX, xMaxX}, {vMinZ, xMinZ}, {vMaxZ, xMaxZ}} {
+		if math.Abs(pair[0]-pair[1]) > tolerance {
+			t.Errorf("vector text bounds x [%v, %v] z [%v, %v], voxel text x [%v, %v] z [%v, %v]",
+				vMinX, vMaxX, vMinZ, vMaxZ, xMinX, xMaxX, xMinZ, xMaxZ)
+			break
+		}
+	}
+}
+
+// TestVectorTextSloped verifies outline text sits against a sloped face
+func TestVectorTextSloped(t *testing.T) {
+	width, _ := CalculateMultiYearDimensions(1)
+	const slope = 0.5
+
+	triangles, err := createVectorText("mona", "2024", width, BaseHeight, slope, 1)
+	if err != nil {
+		t.Fatalf("createVectorText() error = %v", err)
+	}
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			face := slope * (BaseHeight + v.Z)
+			if v.Y > face+epsilon || v.Y < face-voxelDepth-epsilon {
+				t.Fatalf("vertex %v is not within %v of the sloped face at y=%v", v, voxelDepth, face)
+			}
+		}
+	}
+}
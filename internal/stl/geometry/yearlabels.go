@@ -0,0 +1,121 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	yearLabelMaxSize = 3.0 // Largest year label font, in millimeters
+	yearLabelMinSize = 1.5 // Smallest year label font, in millimeters, that still prints legibly
+	yearLabelFill    = 0.8 // Share of the free strip, and of its year's depth, the label may take
+
+	yearDividerWidth  = 0.8 // Thickness of the ridge between years, about two nozzle widths
+	yearDividerHeight = 1.0 // Height of the ridge between years above the top face
+)
+
+// yearSeparation returns the distance between the last row of a year and the
+// first row of the year behind it, which makes room for a divider between them.
+func (l Layout) yearSeparation() float64 {
+	return l.YearSpacing - gridSpan(float64(l.daysPerWeek()), l.CellSize, l.Gap)
+}
+
+// yearLabelStrip returns the left edge and width of the strip of the top face
+// between the towers and the right edge of the base, which holds the year labels.
+func (l Layout) yearLabelStrip() (start, width float64) {
+	start = l.OffsetX + gridSpan(float64(l.weeks()), l.CellSize, l.Gap)
+	return start, l.Width - l.tierClearance() - start
+}
+
+// yearLabelSize returns the font size of the year labels in millimeters.
+func (l Layout) yearLabelSize() float64 {
+	_, width := l.yearLabelStrip()
+	return math.Min(yearLabelMaxSize, width*yearLabelFill)
+}
+
+// validateYearLabels checks that the year labels fit legibly beside the towers.
+func (l Layout) validateYearLabels() error {
+	if !l.YearLabels || l.yearLabelSize() >= yearLabelMinSize {
+		return nil
+	}
+	_, width := l.yearLabelStrip()
+	return errors.New(errors.ValidationError, fmt.Sprintf("the %s right of the towers is too narrow for year labels (at least %s needed)", l.Unit.Format(math.Max(0, width)), l.Unit.Format(yearLabelMinSize/yearLabelFill)), nil)
+}
+
+// CreateYearLabels generates a label for every year on the top face to the
+// right of its towers, given the years oldest first so the last is labeled at
+// the front. The years need not be consecutive. The labels run from the front
+// to the back, like the spine of a book, and read from the right side of the
+// model. Each label stands on its year's tier when the years are stacked.
+func (l Layout) CreateYearLabels(years []int) ([]types.Triangle, error) {
+	if !l.YearLabels {
+		return nil, nil
+	}
+	if len(years) != l.YearCount {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("%d year labels given for %d years", len(years), l.YearCount), nil)
+	}
+
+	f, err := loadRasterFont()
+	if err != nil {
+		return nil, err
+	}
+
+	start, width := l.yearLabelStrip()
+	depth := gridSpan(float64(l.daysPerWeek()), l.CellSize, l.Gap)
+
+	var triangles []types.Triangle
+	for year := 0; year < l.YearCount; year++ {
+		label := strconv.Itoa(years[len(years)-1-year])
+		_, front := l.TowerPosition(year, 0, 0)
+		voxels, err := embossOnTop(start, front, width, depth, func(dc *gg.Context) error {
+			pixel := width / float64(dc.Width())
+			setFontFace(dc, f, l.yearLabelSize()/pixel)
+			// Shrink labels longer than their year is deep
+			if w, _ := dc.MeasureString(label); w*pixel > depth*yearLabelFill {
+				setFontFace(dc, f, l.yearLabelSize()/pixel*depth*yearLabelFill/(w*pixel))
+			}
+			cx, cy := float64(dc.Width())/2, float64(dc.Height())/2
+			dc.RotateAbout(-math.Pi/2, cx, cy)
+			dc.DrawStringAnchored(label, cx, cy, 0.5, 0.5)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if elevation := l.TierElevation(year); elevation > 0 {
+			for i := range voxels {
+				voxels[i].V1.Z += elevation
+				voxels[i].V2.Z += elevation
+				voxels[i].V3.Z += elevation
+			}
+		}
+		triangles = append(triangles, voxels...)
+	}
+	return triangles, nil
+}
+
+// createYearDividers generates a low ridge across the width of the towers in
+// the space between every pair of neighboring years.
+func (l Layout) createYearDividers() ([]types.Triangle, error) {
+	if !l.YearDividers {
+		return nil, nil
+	}
+	span := gridSpan(float64(l.weeks()), l.CellSize, l.Gap)
+
+	var triangles []types.Triangle
+	for year := 1; year < l.YearCount; year++ {
+		_, front := l.TowerPosition(year, 0, 0)
+		ridge, err := createBox(l.OffsetX, front-(l.yearSeparation()+yearDividerWidth)/2, 0, span, yearDividerWidth, yearDividerHeight)
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create year divider", err)
+		}
+		triangles = append(triangles, ridge...)
+	}
+	return triangles, nil
+}
@@ -0,0 +1,134 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestCreateYearLabels verifies each year is labeled beside its own towers, on its tier when stacked
+func TestCreateYearLabels(t *testing.T) {
+	for _, stack := range []bool{false, true} {
+		layout, err := NewLayout(Config{YearLabels: true, Stack: stack}, 3)
+		if err != nil {
+			t.Fatalf("NewLayout() error = %v", err)
+		}
+		labels, err := layout.CreateYearLabels([]int{2022, 2023, 2024})
+		if err != nil {
+			t.Fatalf("CreateYearLabels() error = %v", err)
+		}
+		if len(labels) == 0 {
+			t.Fatalf("CreateYearLabels() returned no triangles")
+		}
+
+		start, width := layout.yearLabelStrip()
+		years := make([]bool, 3)
+		for _, tri := range labels {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				if v.X < start-epsilon || v.X > start+width+epsilon {
+					t.Fatalf("label vertex %v leaves the strip right of the towers", v)
+				}
+				year := int(math.Floor((v.Y - layout.OffsetY + layout.Gap/2) / layout.YearSpacing))
+				if year < 0 || year >= 3 {
+					t.Fatalf("label vertex %v is outside the years", v)
+				}
+				years[year] = true
+				if bottom := layout.TierElevation(year); v.Z < bottom-epsilon || v.Z > bottom+voxelDepth+epsilon {
+					t.Fatalf("label vertex %v of year %d is not on its top face at %v", v, year, bottom)
+				}
+			}
+		}
+		for year, labeled := range years {
+			if !labeled {
+				t.Errorf("stack=%v: year %d has no label", stack, year)
+			}
+		}
+	}
+
+	// Every year needs a label
+	layout, err := NewLayout(Config{YearLabels: true}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if _, err := layout.CreateYearLabels([]int{2012, 2024}); err == nil {
+		t.Error("CreateYearLabels() with fewer years than the layout should fail")
+	}
+
+	// Without year labels nothing is generated
+	layout, err = NewLayout(Config{}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if labels, err := layout.CreateYearLabels([]int{2022, 2023, 2024}); err != nil || len(labels) != 0 {
+		t.Errorf("CreateYearLabels() without year labels = %d triangles, %v, want none", len(labels), err)
+	}
+}
+
+// TestLayoutYearDividers verifies dividers widen the space between years and stand in it
+func TestLayoutYearDividers(t *testing.T) {
+	plain, err := NewLayout(Config{Gap: 0.5}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	layout, err := NewLayout(Config{Gap: 0.5, YearDividers: true}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if got, want := layout.Depth-plain.Depth, 2*(yearDividerWidth+0.5); math.Abs(got-want) > epsilon {
+		t.Errorf("dividers deepen the base by %v, want %v", got, want)
+	}
+	if got, want := layout.yearSeparation(), 2*0.5+yearDividerWidth; math.Abs(got-want) > epsilon {
+		t.Errorf("yearSeparation() = %v, want %v", got, want)
+	}
+
+	dividers, err := layout.createYearDividers()
+	if err != nil {
+		t.Fatalf("createYearDividers() error = %v", err)
+	}
+	if len(dividers) != 2*12 {
+		t.Fatalf("createYearDividers() returned %d triangles, want two boxes", len(dividers))
+	}
+	for _, tri := range dividers {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			year := int(math.Floor((v.Y - layout.OffsetY) / layout.YearSpacing))
+			_, back := layout.TowerPosition(year, 0, 6)
+			if v.Y < back+layout.CellSize+layout.Gap-epsilon || v.Y > back+layout.CellSize+layout.Gap+yearDividerWidth+epsilon {
+				t.Fatalf("divider vertex %v does not stand between years %d and %d", v, year, year+1)
+			}
+		}
+	}
+
+	// A given base depth fits the wider spacing
+	fitted, err := NewLayout(Config{BaseDepth: 80, YearDividers: true}, 3)
+	if err != nil {
+		t.Fatalf("NewLayout() error = %v", err)
+	}
+	if math.Abs(fitted.Depth-80) > epsilon || fitted.OffsetY < gridPadding*fitted.CellSize-epsilon {
+		t.Errorf("fitted layout has depth %v and offset %v, want the grid padded inside 80mm", fitted.Depth, fitted.OffsetY)
+	}
+}
+
+// TestConfigValidateYearLabels verifies year labels and dividers are rejected where they do not fit
+func TestConfigValidateYearLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"labels and dividers", Config{YearLabels: true, YearDividers: true}, false},
+		{"labels on stacked years", Config{YearLabels: true, Stack: true}, false},
+		{"dividers on stacked years", Config{YearDividers: true, Stack: true}, true},
+		{"labels on a round base", Config{YearLabels: true, Arrangement: ArrangementRadial}, true},
+		{"dividers on a round base", Config{YearDividers: true, Arrangement: ArrangementSpiral}, true},
+		{"labels beside narrow padding", Config{YearLabels: true, CellSize: 0.8}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLayout(tt.cfg, 3); (err != nil) != tt.wantErr {
+				t.Errorf("NewLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
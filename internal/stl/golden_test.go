@@ -0,0 +1,180 @@
+package stl
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// updateGolden rewrites the golden files from the current output instead of
+// comparing against them: go test ./internal/stl -run TestGoldenModels -update
+var updateGolden = flag.Bool("update", false, "rewrite golden files")
+
+// goldenPrecision is the grid coordinates are rounded to before hashing, in
+// millimeters, so rounding differences between platforms do not change the hash.
+const goldenPrecision = 1e-4
+
+// goldenModel summarizes a generated model for comparison against a golden file.
+type goldenModel struct {
+	Triangles int            `json:"triangles"`
+	Objects   int            `json:"objects"`
+	Kinds     map[string]int `json:"kinds"` // Triangles per object kind
+	Min       [3]float64     `json:"min"`
+	Max       [3]float64     `json:"max"`
+	Hash      string         `json:"hash"` // SHA-256 of the rounded vertices of every triangle, in order
+}
+
+// summarizeModel builds the golden summary of a model.
+func summarizeModel(model *types.Model) goldenModel {
+	round := func(v float64) float64 {
+		return math.Round(v/goldenPrecision) * goldenPrecision
+	}
+	summary := goldenModel{Triangles: model.TriangleCount(), Objects: len(model.Objects), Kinds: map[string]int{}}
+	minPoint, maxPoint := model.Bounds()
+	summary.Min = [3]float64{round(minPoint.X), round(minPoint.Y), round(minPoint.Z)}
+	summary.Max = [3]float64{round(maxPoint.X), round(maxPoint.Y), round(maxPoint.Z)}
+
+	hash := sha256.New()
+	buf := make([]byte, 8)
+	for _, obj := range model.Objects {
+		summary.Kinds[string(obj.Kind)] += obj.Mesh.Len()
+		for _, t := range obj.Mesh.Triangles() {
+			for _, v := range []types.Point3D{t.V1, t.V2, t.V3} {
+				for _, c := range []float64{v.X, v.Y, v.Z} {
+					binary.LittleEndian.PutUint64(buf, uint64(int64(math.Round(c/goldenPrecision))))
+					hash.Write(buf)
+				}
+			}
+		}
+	}
+	summary.Hash = hex.EncodeToString(hash.Sum(nil))
+	return summary
+}
+
+// fixtureContributions returns the weeks of the fixture contribution calendar for each year.
+func fixtureContributions(startYear, endYear int) [][][]types.ContributionDay {
+	var years [][][]types.ContributionDay
+	for year := startYear; year <= endYear; year++ {
+		response := fixtures.GenerateContributionsResponse("testuser", year)
+		var weeks [][]types.ContributionDay
+		for _, week := range response.User.ContributionsCollection.ContributionCalendar.Weeks {
+			weeks = append(weeks, week.ContributionDays)
+		}
+		years = append(years, weeks)
+	}
+	return years
+}
+
+// TestGoldenModels verifies generated models match their golden files, so
+// changes to the geometry are noticed. Run with -update to accept changes.
+func TestGoldenModels(t *testing.T) {
+	tests := []struct {
+		name      string
+		startYear int
+		endYear   int
+		configure func(cfg *geometry.Config)
+		mirror    bool
+	}{
+		{name: "default", startYear: 2024, endYear: 2024},
+		{name: "sloped-vector", startYear: 2024, endYear: 2024, configure: func(cfg *geometry.Config) {
+			cfg.BaseStyle = geometry.BaseSloped
+			cfg.TextStyle = geometry.TextVector
+		}},
+		{name: "radial-cylinders", startYear: 2024, endYear: 2024, configure: func(cfg *geometry.Config) {
+			cfg.Arrangement = geometry.ArrangementRadial
+			cfg.TowerShape = geometry.TowerCylinder
+			cfg.TowerTop = geometry.TowerRounded
+		}},
+		{name: "stacked-years", startYear: 2022, endYear: 2024, configure: func(cfg *geometry.Config) {
+			cfg.Stack = true
+			cfg.YearLabels = true
+		}},
+		{name: "hollow-months", startYear: 2024, endYear: 2024, configure: func(cfg *geometry.Config) {
+			cfg.Hollow = 2
+			cfg.DrainHole = 4
+			cfg.MonthLabels = geometry.MonthLabelsTop
+		}},
+		{name: "mold", startYear: 2024, endYear: 2024, configure: func(cfg *geometry.Config) {
+			cfg.Mold = true
+			cfg.Gap = 0.5
+		}},
+		{name: "mirrored", startYear: 2024, endYear: 2024, mirror: true},
+		{name: "smooth-surface", startYear: 2023, endYear: 2024, configure: func(cfg *geometry.Config) {
+			cfg.SmoothSurface = true
+			cfg.Stack = true
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := geometry.DefaultConfig()
+			if tt.configure != nil {
+				tt.configure(&cfg)
+			}
+			contributions := fixtureContributions(tt.startYear, tt.endYear)
+			dims, err := calculateDimensions(cfg, len(contributions))
+			if err != nil {
+				t.Fatalf("calculateDimensions() error = %v", err)
+			}
+			opts := Options{Username: "testuser", StartYear: tt.startYear, EndYear: tt.endYear, Geometry: cfg, Mirror: tt.mirror}
+			model, err := buildModel(contributions, dims, findMaxContributionsAcrossYears(contributions), opts, nil)
+			if err != nil {
+				t.Fatalf("buildModel() error = %v", err)
+			}
+			got := summarizeModel(model)
+
+			path := filepath.Join("testdata", "golden", tt.name+".json")
+			if *updateGolden {
+				data, err := json.MarshalIndent(got, "", "  ")
+				if err != nil {
+					t.Fatalf("failed to encode golden file: %v", err)
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("failed to create golden directory: %v", err)
+				}
+				if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+					t.Fatalf("failed to write golden file: %v", err)
+				}
+				return
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			var want goldenModel
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatalf("failed to decode golden file: %v", err)
+			}
+			if got.Triangles != want.Triangles || got.Objects != want.Objects {
+				t.Errorf("model has %d triangles in %d objects, want %d in %d", got.Triangles, got.Objects, want.Triangles, want.Objects)
+			}
+			for kind, count := range want.Kinds {
+				if got.Kinds[kind] != count {
+					t.Errorf("%s has %d triangles, want %d", kind, got.Kinds[kind], count)
+				}
+			}
+			for kind := range got.Kinds {
+				if _, ok := want.Kinds[kind]; !ok {
+					t.Errorf("unexpected %s object with %d triangles", kind, got.Kinds[kind])
+				}
+			}
+			if got.Min != want.Min || got.Max != want.Max {
+				t.Errorf("bounds = %v to %v, want %v to %v", got.Min, got.Max, want.Min, want.Max)
+			}
+			if got.Hash != want.Hash {
+				t.Errorf("geometry hash = %s, want %s", got.Hash, want.Hash)
+			}
+		})
+	}
+}
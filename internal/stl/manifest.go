@@ -0,0 +1,55 @@
+package stl
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// manifest indexes the files written for a model split into one piece per year.
+type manifest struct {
+	Username         string         `json:"username"`
+	Years            string         `json:"years"`
+	Format           Format         `json:"format"`
+	Unit             types.Unit     `json:"unit"`
+	MaxContributions int            `json:"maxContributions"` // Busiest day of the range, which every year's towers are scaled to
+	Pieces           []manifestYear `json:"pieces"`
+}
+
+// manifestYear lists the files written for a single year.
+type manifestYear struct {
+	Year          int      `json:"year"`
+	Contributions int      `json:"contributions"`
+	Files         []string `json:"files"` // Relative to the manifest, so the set can be moved together
+}
+
+// add records the files written for a year, relative to the manifest.
+func (m *manifest) add(year, contributions int, files []string) {
+	piece := manifestYear{Year: year, Contributions: contributions, Files: make([]string, len(files))}
+	for i, file := range files {
+		piece.Files[i] = filepath.Base(file)
+	}
+	m.Pieces = append(m.Pieces, piece)
+}
+
+// manifestFilename returns the file the index of a split model is written to,
+// named after the model's file.
+func manifestFilename(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + "-index.json"
+}
+
+// writeManifest writes the index of a split model as indented JSON.
+func writeManifest(path string, m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.New(errors.IOError, "failed to encode index", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return errors.New(errors.IOError, "failed to write index", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package stl
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestManifestFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"model.stl", "model-index.json"},
+		{"out/mona-2015-2024-github-skyline.3mf", "out/mona-2015-2024-github-skyline-index.json"},
+		{"model", "model-index.json"},
+	}
+
+	for _, tt := range tests {
+		if got := manifestFilename(tt.filename); got != tt.want {
+			t.Errorf("manifestFilename(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	dir := t.TempDir()
+	index := manifest{Username: "mona", Years: "2023-24", Format: FormatSTL, Unit: types.UnitMillimeter, MaxContributions: 42}
+	index.add(2023, 100, []string{filepath.Join(dir, "model-2023.stl")})
+	index.add(2024, 0, []string{filepath.Join(dir, "model-2024-base.stl"), filepath.Join(dir, "model-2024-towers.stl")})
+
+	path := filepath.Join(dir, "model-index.json")
+	if err := writeManifest(path, index); err != nil {
+		t.Fatalf("writeManifest() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read index: %v", err)
+	}
+	var got manifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("index is not valid JSON: %v", err)
+	}
+
+	want := manifest{Username: "mona", Years: "2023-24", Format: FormatSTL, Unit: types.UnitMillimeter, MaxContributions: 42, Pieces: []manifestYear{
+		{Year: 2023, Contributions: 100, Files: []string{"model-2023.stl"}},
+		{Year: 2024, Contributions: 0, Files: []string{"model-2024-base.stl", "model-2024-towers.stl"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("index = %+v, want %+v", got, want)
+	}
+
+	if err := writeManifest(filepath.Join(dir, "missing", "index.json"), index); err == nil {
+		t.Error("writeManifest() expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,48 @@
+package stl
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// indexedMesh is the float32 form of a types.Mesh written by the PLY, AMF and 3MF formats.
+// Vertices that fall together in float32 are stored once and referenced by index.
+type indexedMesh struct {
+	vertices []types.Point3DFloat32
+	faces    [][3]uint32
+}
+
+// buildIndexedMesh converts a mesh to float32, merging vertices that become equal.
+// Vertex order follows the mesh so the output is stable for identical input.
+func buildIndexedMesh(m types.Mesh) (*indexedMesh, error) {
+	if uint64(len(m.Vertices)) > uint64(math.MaxUint32) {
+		return nil, errors.New(errors.ValidationError, "vertex count exceeds valid range for indexed mesh", nil)
+	}
+
+	mesh := &indexedMesh{faces: make([][3]uint32, len(m.Faces))}
+	index := make(map[types.Point3DFloat32]uint32, len(m.Vertices))
+	remap := make([]uint32, len(m.Vertices))
+	for i, v := range m.Vertices {
+		p := v.ToFloat32()
+		idx, ok := index[p]
+		if !ok {
+			idx = uint32(len(mesh.vertices))
+			index[p] = idx
+			mesh.vertices = append(mesh.vertices, p)
+		}
+		remap[i] = idx
+	}
+
+	for i, f := range m.Faces {
+		mesh.faces[i] = [3]uint32{remap[f.V[0]], remap[f.V[1]], remap[f.V[2]]}
+	}
+	return mesh, nil
+}
+
+// formatFloat formats a coordinate using the shortest representation that round-trips to float32.
+func formatFloat(value float32) string {
+	return strconv.FormatFloat(float64(value), 'g', -1, 32)
+}
@@ -0,0 +1,41 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestBuildIndexedMesh(t *testing.T) {
+	mesh, err := buildIndexedMesh(types.NewMesh(createTestQuad()))
+	if err != nil {
+		t.Fatalf("buildIndexedMesh() error = %v", err)
+	}
+	if len(mesh.vertices) != 4 {
+		t.Errorf("buildIndexedMesh() vertices = %d, want 4 shared vertices", len(mesh.vertices))
+	}
+	if len(mesh.faces) != 2 {
+		t.Errorf("buildIndexedMesh() faces = %d, want 2", len(mesh.faces))
+	}
+	if mesh.faces[0][0] != mesh.faces[1][0] {
+		t.Error("buildIndexedMesh() did not reuse the shared vertex index")
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		value float32
+		want  string
+	}{
+		{0, "0"},
+		{2.5, "2.5"},
+		{-10, "-10"},
+		{math.MaxFloat32, "3.4028235e+38"},
+	}
+	for _, tt := range tests {
+		if got := formatFloat(tt.value); got != tt.want {
+			t.Errorf("formatFloat(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
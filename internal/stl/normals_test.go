@@ -0,0 +1,220 @@
+package stl
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stats"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// minAuditArea is twice the area below which a face's normal is too sensitive
+// to rounding to check against its winding.
+const minAuditArea = 1e-9
+
+// auditNormals checks that every face of every object of the model has a
+// normal agreeing with its winding, and that each object faces outward: every
+// closed part of its mesh encloses a positive volume, unless it is the cavity
+// of another part, which orientFaces would otherwise turn over. It returns
+// the problems found.
+func auditNormals(model *types.Model) []string {
+	var problems []string
+	for _, obj := range model.Objects {
+		mesh := obj.Mesh
+		disagree := 0
+		for i, f := range mesh.Faces {
+			normal, area := faceNormal(mesh.Vertices, f.V)
+			if area < minAuditArea || vectorAgrees(normal, f.Normal) {
+				continue
+			}
+			if disagree == 0 {
+				problems = append(problems, fmt.Sprintf("%s face %d has normal %v, want %v from its winding", obj.Name, i, f.Normal, normal))
+			}
+			disagree++
+		}
+		if disagree > 1 {
+			problems = append(problems, fmt.Sprintf("%s has %d faces whose normals disagree with their winding", obj.Name, disagree))
+		}
+
+		faces := append([]types.Face(nil), mesh.Faces...)
+		if flipped := orientFaces(mesh.Vertices, faces); flipped > 0 {
+			problems = append(problems, fmt.Sprintf("%s has %d of %d faces facing inward", obj.Name, flipped, len(faces)))
+		}
+		if volume := signedVolume(mesh); volume <= 0 {
+			problems = append(problems, fmt.Sprintf("%s encloses a volume of %v, want a positive volume", obj.Name, volume))
+		}
+	}
+	return problems
+}
+
+// TestModelNormals audits the normals and winding of models built with each
+// feature that adds or reshapes geometry.
+func TestModelNormals(t *testing.T) {
+	avatar := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := 0; i < 16; i++ {
+		avatar.SetGray(i, i, color.Gray{Y: 0xff})
+	}
+
+	tests := []struct {
+		name      string
+		endYear   int
+		configure func(cfg *geometry.Config)
+		mirror    bool
+		decimate  float64
+	}{
+		{name: "default"},
+		{name: "sloped base", configure: func(cfg *geometry.Config) { cfg.BaseStyle = geometry.BaseSloped }},
+		{name: "vector text", configure: func(cfg *geometry.Config) { cfg.TextStyle = geometry.TextVector }},
+		{name: "rounded edges", configure: func(cfg *geometry.Config) {
+			cfg.CornerRadius = 4
+			cfg.Chamfer = 0.5
+		}},
+		{name: "hollow base", configure: func(cfg *geometry.Config) {
+			cfg.Hollow = 2
+			cfg.DrainHole = 4
+		}},
+		{name: "tower shapes", configure: func(cfg *geometry.Config) {
+			cfg.TowerShape = geometry.TowerHex
+			cfg.TowerTop = geometry.TowerPyramid
+			cfg.Gap = 0.3
+		}},
+		{name: "radial", configure: func(cfg *geometry.Config) {
+			cfg.Arrangement = geometry.ArrangementRadial
+			cfg.TowerShape = geometry.TowerCylinder
+			cfg.TowerTop = geometry.TowerRounded
+		}},
+		{name: "spiral", configure: func(cfg *geometry.Config) { cfg.Arrangement = geometry.ArrangementSpiral }},
+		{name: "back face", configure: func(cfg *geometry.Config) {
+			cfg.BaseHeight = 20
+			cfg.QRCode = "https://github.com/testuser"
+			cfg.Stats = true
+		}},
+		{name: "avatar", configure: func(cfg *geometry.Config) { cfg.Avatar = true }},
+		{name: "labels", endYear: 2024, configure: func(cfg *geometry.Config) {
+			cfg.BaseHeight = 14
+			cfg.MonthLabels = geometry.MonthLabelsFront
+			cfg.YearLabels = true
+			cfg.YearDividers = true
+		}},
+		{name: "stacked", endYear: 2024, configure: func(cfg *geometry.Config) { cfg.Stack = true }},
+		{name: "smooth surface", endYear: 2024, configure: func(cfg *geometry.Config) { cfg.SmoothSurface = true }},
+		{name: "mold", configure: func(cfg *geometry.Config) { cfg.Mold = true }},
+		{name: "mirrored", mirror: true, configure: func(cfg *geometry.Config) { cfg.BaseStyle = geometry.BaseSloped }},
+		{name: "decimated", decimate: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := geometry.DefaultConfig()
+			if tt.configure != nil {
+				tt.configure(&cfg)
+			}
+			endYear := max(tt.endYear, 2023)
+			contributions := fixtureContributions(2023, endYear)
+			dims, err := calculateDimensions(cfg, len(contributions))
+			if err != nil {
+				t.Fatalf("calculateDimensions() error = %v", err)
+			}
+			opts := Options{Username: "testuser", StartYear: 2023, EndYear: endYear, Geometry: cfg, Mirror: tt.mirror, Decimate: tt.decimate, Avatar: avatar}
+			summary := stats.Compute(contributions)
+			model, err := buildModel(contributions, dims, findMaxContributionsAcrossYears(contributions), opts, &summary)
+			if err != nil {
+				t.Fatalf("buildModel() error = %v", err)
+			}
+			for _, problem := range auditNormals(model) {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+// TestAuditNormals verifies the audit catches faces turned inward and normals
+// disagreeing with their winding.
+func TestAuditNormals(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(mesh *types.Mesh)
+	}{
+		{"turned over face", func(mesh *types.Mesh) {
+			f := &mesh.Faces[0]
+			f.V[1], f.V[2] = f.V[2], f.V[1]
+			f.Normal = types.Point3D{X: -f.Normal.X, Y: -f.Normal.Y, Z: -f.Normal.Z}
+		}},
+		{"inside out", func(mesh *types.Mesh) {
+			for i := range mesh.Faces {
+				f := &mesh.Faces[i]
+				f.V[1], f.V[2] = f.V[2], f.V[1]
+				f.Normal = types.Point3D{X: -f.Normal.X, Y: -f.Normal.Y, Z: -f.Normal.Z}
+			}
+		}},
+		{"wrong normal", func(mesh *types.Mesh) {
+			n := mesh.Faces[3].Normal
+			mesh.Faces[3].Normal = types.Point3D{X: n.Y, Y: n.Z, Z: n.X}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := types.NewMesh(createTestCube(t, 0))
+			tt.damage(&mesh)
+			if problems := auditNormals(&types.Model{Objects: []types.ModelObject{{Name: "cube", Mesh: mesh}}}); len(problems) == 0 {
+				t.Error("auditNormals() found no problems with a damaged cube")
+			}
+		})
+	}
+
+	t.Run("intact cube", func(t *testing.T) {
+		if problems := auditNormals(&types.Model{Objects: []types.ModelObject{{Name: "cube", Mesh: types.NewMesh(createTestCube(t, 0))}}}); len(problems) > 0 {
+			t.Errorf("auditNormals() found problems with an intact cube: %v", problems)
+		}
+	})
+}
+
+// TestWrittenSTLNormals audits the normals written to an STL file against the
+// winding of each triangle as read back, after the coordinates are rounded to
+// float32, and checks the whole model encloses a positive volume.
+func TestWrittenSTLNormals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skyline.stl")
+	err := GenerateModel(fixtureContributions(2024, 2024), Options{
+		OutputPath: path,
+		Username:   "testuser",
+		StartYear:  2024,
+		EndYear:    2024,
+		Geometry:   geometry.DefaultConfig(),
+	})
+	if err != nil {
+		t.Fatalf("GenerateModel() error = %v", err)
+	}
+	triangles, err := ReadSTL(path)
+	if err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+
+	disagree, volume := 0, 0.0
+	for i, tri := range triangles {
+		a, b, c := tri.V1, tri.V2, tri.V3
+		volume += (a.X*(b.Y*c.Z-b.Z*c.Y) - a.Y*(b.X*c.Z-b.Z*c.X) + a.Z*(b.X*c.Y-b.Y*c.X)) / 6
+
+		// float32 corners of tiny faces only roughly keep their direction
+		winding, area := faceNormal([]types.Point3D{a, b, c}, [3]uint32{0, 1, 2})
+		if area < 1e-4 {
+			continue
+		}
+		if normal := tri.Normal; normal.X*winding.X+normal.Y*winding.Y+normal.Z*winding.Z < 0.99 {
+			if disagree == 0 {
+				t.Errorf("triangle %d has normal %v, want %v from its winding", i, normal, winding)
+			}
+			disagree++
+		}
+	}
+	if disagree > 1 {
+		t.Errorf("%d of %d triangles have normals disagreeing with their winding", disagree, len(triangles))
+	}
+	if volume <= 0 {
+		t.Errorf("model encloses a volume of %v, want a positive volume", volume)
+	}
+}
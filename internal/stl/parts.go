@@ -0,0 +1,85 @@
+package stl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// modelPart is a group of object kinds written to its own file by --split-parts.
+type modelPart struct {
+	name  string
+	kinds []types.ObjectKind
+}
+
+// modelParts lists the parts a split model is written as, in order.
+var modelParts = []modelPart{
+	{"base", []types.ObjectKind{types.ObjectBase, types.ObjectMold}},
+	{"towers", []types.ObjectKind{types.ObjectTower, types.ObjectSurface, types.ObjectFuture}},
+	{"text", []types.ObjectKind{types.ObjectText, types.ObjectStats, types.ObjectMonths, types.ObjectYears}},
+	{"logo", []types.ObjectKind{types.ObjectLogo}},
+	{"qr", []types.ObjectKind{types.ObjectQR}},
+	{"avatar", []types.ObjectKind{types.ObjectAvatar}},
+}
+
+// isImage reports whether the format is a rendered image rather than a mesh.
+func (f Format) isImage() bool {
+	return f == FormatSVG || f == FormatPNG
+}
+
+// namedModel is one part of a split model.
+type namedModel struct {
+	name  string
+	model *types.Model
+}
+
+// splitModel divides a model into one model per part, keeping every object in
+// the shared coordinate space so the parts line up when imported together.
+// Parts without any triangles, such as omitted text, are left out.
+func splitModel(model *types.Model) []namedModel {
+	var parts []namedModel
+	for _, part := range modelParts {
+		sub := &types.Model{Metadata: model.Metadata, Unit: model.Unit}
+		for _, obj := range model.Objects {
+			for _, kind := range part.kinds {
+				if obj.Kind == kind {
+					sub.Objects = append(sub.Objects, obj)
+				}
+			}
+		}
+		if sub.TriangleCount() > 0 {
+			parts = append(parts, namedModel{name: part.name, model: sub})
+		}
+	}
+	return parts
+}
+
+// partFilename returns the file a part is written to, named after the model's
+// file with the part name before the extension.
+func partFilename(filename, part string) string {
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(filename, ext), part, ext)
+}
+
+// writeParts writes each part of the model to its own file in the given
+// format and returns the files written.
+func writeParts(ctx context.Context, filename string, format Format, model *types.Model, renderOpts render.Options) ([]string, error) {
+	if format.isImage() {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("%s images cannot be split into parts", format), nil)
+	}
+
+	var written []string
+	for _, part := range splitModel(model) {
+		path := partFilename(filename, part.name)
+		if err := WriteModelContext(ctx, path, format, part.model, renderOpts); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
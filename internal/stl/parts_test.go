@@ -0,0 +1,85 @@
+package stl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestPartFilename(t *testing.T) {
+	tests := []struct {
+		filename, part, want string
+	}{
+		{"mona-2024-github-skyline.stl", "base", "mona-2024-github-skyline-base.stl"},
+		{filepath.Join("out", "model.amf"), "logo", filepath.Join("out", "model-logo.amf")},
+		{"model", "text", "model-text"},
+	}
+
+	for _, tt := range tests {
+		if got := partFilename(tt.filename, tt.part); got != tt.want {
+			t.Errorf("partFilename(%q, %q) = %q, want %q", tt.filename, tt.part, got, tt.want)
+		}
+	}
+}
+
+func TestSplitModel(t *testing.T) {
+	triangle := []types.Triangle{{V2: types.Point3D{X: 1}, V3: types.Point3D{Y: 1}}}
+	model := &types.Model{
+		Objects: []types.ModelObject{
+			{Name: "base", Kind: types.ObjectBase, Mesh: types.NewMesh(triangle)},
+			{Name: "tower-1", Kind: types.ObjectTower, Mesh: types.NewMesh(triangle)},
+			{Name: "tower-2", Kind: types.ObjectTower, Mesh: types.NewMesh(triangle)},
+			{Name: "logo", Kind: types.ObjectLogo, Mesh: types.NewMesh(triangle)},
+			{Name: "text", Kind: types.ObjectText},
+		},
+		Unit: types.UnitInch,
+	}
+
+	parts := splitModel(model)
+	var names []string
+	for _, p := range parts {
+		names = append(names, p.name)
+		if p.model.Unit != types.UnitInch {
+			t.Errorf("part %s has unit %q, want the model's unit", p.name, p.model.Unit)
+		}
+	}
+	if len(names) != 3 || names[0] != "base" || names[1] != "towers" || names[2] != "logo" {
+		t.Errorf("splitModel() parts = %v, want [base towers logo] without the empty text", names)
+	}
+	if got := parts[1].model.TriangleCount(); got != 2 {
+		t.Errorf("towers part has %d triangles, want 2", got)
+	}
+}
+
+func TestWriteParts(t *testing.T) {
+	triangle := []types.Triangle{{V2: types.Point3D{X: 1}, V3: types.Point3D{Y: 1}}}
+	model := &types.Model{Objects: []types.ModelObject{
+		{Name: "base", Kind: types.ObjectBase, Mesh: types.NewMesh(triangle)},
+		{Name: "text", Kind: types.ObjectText, Mesh: types.NewMesh(triangle)},
+	}}
+	filename := filepath.Join(t.TempDir(), "model.stl")
+
+	written, err := writeParts(context.Background(), filename, FormatSTL, model, render.DefaultOptions())
+	if err != nil {
+		t.Fatalf("writeParts() error = %v", err)
+	}
+	if len(written) != 2 {
+		t.Fatalf("writeParts() wrote %v, want 2 files", written)
+	}
+	for _, path := range written {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("part %s was not written: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("writeParts() wrote the combined model %s", filename)
+	}
+
+	if _, err := writeParts(context.Background(), filename, FormatPNG, model, render.DefaultOptions()); err == nil {
+		t.Error("writeParts() expected an error for an image format")
+	}
+}
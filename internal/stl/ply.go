@@ -0,0 +1,117 @@
+package stl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// writePLYHeader writes the PLY header describing the vertex and face elements,
+// with a comment for each metadata entry.
+func writePLYHeader(writer *bufio.Writer, format string, mesh *indexedMesh, metadata []types.Metadata) error {
+	var comments strings.Builder
+	for _, m := range metadata {
+		// A comment runs to the end of its line
+		fmt.Fprintf(&comments, "comment %s: %s\n", m.Key, strings.Join(strings.Fields(m.Value), " "))
+	}
+	header := fmt.Sprintf("ply\n"+
+		"format %s 1.0\n"+
+		"comment Generated by GitHub Contributions Skyline Generator\n"+
+		"%s"+
+		"element vertex %d\n"+
+		"property float x\n"+
+		"property float y\n"+
+		"property float z\n"+
+		"element face %d\n"+
+		"property list uchar uint vertex_indices\n"+
+		"end_header\n", format, comments.String(), len(mesh.vertices), len(mesh.faces))
+
+	if _, err := writer.WriteString(header); err != nil {
+		return errors.New(errors.IOError, "failed to write PLY header", err)
+	}
+	return nil
+}
+
+// WritePLYBinary writes triangles to a binary little-endian PLY file.
+//
+// The file contains an ASCII header followed by the deduplicated vertex list
+// (3 x float32 per vertex) and the face list, where each face is a uint8 vertex
+// count (always 3) followed by three uint32 vertex indices.
+func WritePLYBinary(filename string, triangles []types.Triangle) error {
+	return writePLYBinary(filename, types.NewMesh(triangles), nil)
+}
+
+// writePLYBinary writes a mesh to a binary little-endian PLY file, with the
+// metadata in its header.
+func writePLYBinary(filename string, m types.Mesh, metadata []types.Metadata) error {
+	mesh, err := buildIndexedMesh(m)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filename, func(writer *bufio.Writer) error {
+		if err := writePLYHeader(writer, "binary_little_endian", mesh, metadata); err != nil {
+			return err
+		}
+
+		vertexBuffer := make([]byte, 12)
+		for _, v := range mesh.vertices {
+			w := &bufferWriter{buffer: vertexBuffer}
+			w.writePoint3D(v)
+			if _, err := writer.Write(vertexBuffer); err != nil {
+				return errors.New(errors.IOError, "failed to write PLY vertex", err)
+			}
+		}
+
+		faceBuffer := make([]byte, 13)
+		faceBuffer[0] = 3
+		for _, face := range mesh.faces {
+			binary.LittleEndian.PutUint32(faceBuffer[1:], face[0])
+			binary.LittleEndian.PutUint32(faceBuffer[5:], face[1])
+			binary.LittleEndian.PutUint32(faceBuffer[9:], face[2])
+			if _, err := writer.Write(faceBuffer); err != nil {
+				return errors.New(errors.IOError, "failed to write PLY face", err)
+			}
+		}
+		return nil
+	})
+}
+
+// WritePLYASCII writes triangles to an ASCII PLY file.
+// The ASCII variant is larger than the binary one but can be inspected and diffed as text.
+func WritePLYASCII(filename string, triangles []types.Triangle) error {
+	return writePLYASCII(filename, types.NewMesh(triangles), nil)
+}
+
+// writePLYASCII writes a mesh to an ASCII PLY file, with the metadata in its
+// header.
+func writePLYASCII(filename string, m types.Mesh, metadata []types.Metadata) error {
+	mesh, err := buildIndexedMesh(m)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filename, func(writer *bufio.Writer) error {
+		if err := writePLYHeader(writer, "ascii", mesh, metadata); err != nil {
+			return err
+		}
+
+		for _, v := range mesh.vertices {
+			line := formatFloat(v.X) + " " + formatFloat(v.Y) + " " + formatFloat(v.Z) + "\n"
+			if _, err := writer.WriteString(line); err != nil {
+				return errors.New(errors.IOError, "failed to write PLY vertex", err)
+			}
+		}
+
+		for _, face := range mesh.faces {
+			if _, err := fmt.Fprintf(writer, "3 %d %d %d\n", face[0], face[1], face[2]); err != nil {
+				return errors.New(errors.IOError, "failed to write PLY face", err)
+			}
+		}
+		return nil
+	})
+}
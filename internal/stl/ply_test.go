@@ -0,0 +1,128 @@
+package stl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// createTestQuad returns two triangles sharing an edge, forming a unit square.
+func createTestQuad() []types.Triangle {
+	normal := types.Point3D{X: 0, Y: 0, Z: 1}
+	return []types.Triangle{
+		{Normal: normal, V1: types.Point3D{X: 0, Y: 0, Z: 0}, V2: types.Point3D{X: 1, Y: 0, Z: 0}, V3: types.Point3D{X: 1, Y: 1, Z: 0}},
+		{Normal: normal, V1: types.Point3D{X: 0, Y: 0, Z: 0}, V2: types.Point3D{X: 1, Y: 1, Z: 0}, V3: types.Point3D{X: 0, Y: 1, Z: 0}},
+	}
+}
+
+// readPLYHeader reads header lines up to and including end_header.
+func readPLYHeader(t *testing.T, reader *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read PLY header: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		lines = append(lines, line)
+		if line == "end_header" {
+			return lines
+		}
+	}
+}
+
+func TestWritePLYBinary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ply")
+	if err := WritePLYBinary(path, createTestQuad()); err != nil {
+		t.Fatalf("WritePLYBinary() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Cannot open generated PLY file: %v", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			t.Fatalf("Failed to close PLY file: %v", err)
+		}
+	}()
+
+	reader := bufio.NewReader(file)
+	header := readPLYHeader(t, reader)
+	for _, want := range []string{"ply", "format binary_little_endian 1.0", "element vertex 4", "element face 2"} {
+		if !containsLine(header, want) {
+			t.Errorf("PLY header missing %q, got %v", want, header)
+		}
+	}
+
+	var vertex [3]float32
+	if err := binary.Read(reader, binary.LittleEndian, &vertex); err != nil {
+		t.Fatalf("Failed to read first vertex: %v", err)
+	}
+	if vertex != [3]float32{0, 0, 0} {
+		t.Errorf("first vertex = %v, want origin", vertex)
+	}
+
+	// Skip remaining vertices and read the first face
+	if _, err := reader.Discard(3 * 12); err != nil {
+		t.Fatalf("Failed to skip vertices: %v", err)
+	}
+	var count uint8
+	var indices [3]uint32
+	if err := binary.Read(reader, binary.LittleEndian, &count); err != nil {
+		t.Fatalf("Failed to read face vertex count: %v", err)
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &indices); err != nil {
+		t.Fatalf("Failed to read face indices: %v", err)
+	}
+	if count != 3 || indices != [3]uint32{0, 1, 2} {
+		t.Errorf("first face = %d %v, want 3 [0 1 2]", count, indices)
+	}
+}
+
+func TestWritePLYASCII(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ply")
+	if err := WritePLYASCII(path, createTestQuad()); err != nil {
+		t.Fatalf("WritePLYASCII() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cannot read generated PLY file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if !containsLine(lines, "format ascii 1.0") {
+		t.Error("ASCII PLY header missing format line")
+	}
+	want := []string{"0 0 0", "1 0 0", "1 1 0", "0 1 0", "3 0 1 2", "3 0 2 3"}
+	body := lines[len(lines)-len(want):]
+	for i := range want {
+		if body[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, body[i], want[i])
+		}
+	}
+}
+
+func TestWritePLYInvalidPath(t *testing.T) {
+	if err := WritePLYBinary("", createTestQuad()); err == nil {
+		t.Error("expected error for empty filename")
+	}
+	if err := WritePLYASCII("/nonexistent/path/file.ply", createTestQuad()); err == nil {
+		t.Error("expected error for invalid file path")
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
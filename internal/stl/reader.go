@@ -0,0 +1,162 @@
+package stl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// ReadSTL loads the triangles of a binary or ASCII STL file.
+func ReadSTL(filename string) ([]types.Triangle, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open STL file", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return DecodeSTL(file)
+}
+
+// DecodeSTL reads the triangles of a binary or ASCII STL file from r.
+//
+// Binary files are told apart by their size, which the triangle count after
+// the 80-byte header fixes, since their header may also start with "solid"
+// like an ASCII file. Triangles stored without a normal, as some exporters
+// write them, get the normal of their winding.
+func DecodeSTL(r io.Reader) ([]types.Triangle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read STL data", err)
+	}
+
+	var triangles []types.Triangle
+	if len(data) >= 84 && uint64(len(data)) == 84+triangleSize*uint64(binary.LittleEndian.Uint32(data[80:84])) {
+		triangles = decodeSTLBinary(data)
+	} else if fields := bytes.Fields(data[:min(len(data), 512)]); len(fields) > 0 && bytes.EqualFold(fields[0], []byte("solid")) {
+		if triangles, err = decodeSTLASCII(data); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, errors.New(errors.ValidationError, "data is neither a binary nor an ASCII STL file", nil)
+	}
+
+	for i, tri := range triangles {
+		if !tri.V1.IsValid() || !tri.V2.IsValid() || !tri.V3.IsValid() || !tri.Normal.IsValid() {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("STL triangle %d has invalid coordinates", i), nil)
+		}
+		if tri.Normal == (types.Point3D{}) {
+			triangles[i].Normal, _ = faceNormal([]types.Point3D{tri.V1, tri.V2, tri.V3}, [3]uint32{0, 1, 2})
+		}
+	}
+	return triangles, nil
+}
+
+// decodeSTLBinary reads the triangles after the header and triangle count of
+// a binary STL file whose size has been checked against the count.
+func decodeSTLBinary(data []byte) []types.Triangle {
+	triangles := make([]types.Triangle, binary.LittleEndian.Uint32(data[80:84]))
+	for i := range triangles {
+		offset := 84 + triangleSize*i
+		point := func(index int) types.Point3D {
+			c := func(axis int) float64 {
+				return float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+12*index+4*axis:])))
+			}
+			return types.Point3D{X: c(0), Y: c(1), Z: c(2)}
+		}
+		triangles[i] = types.Triangle{Normal: point(0), V1: point(1), V2: point(2), V3: point(3)}
+	}
+	return triangles
+}
+
+// decodeSTLASCII parses the facets of one or more solids in an ASCII STL
+// file, each written on its own lines as:
+//
+//	facet normal nx ny nz
+//	  outer loop
+//	    vertex x y z
+//	    vertex x y z
+//	    vertex x y z
+//	  endloop
+//	endfacet
+//
+// Keywords are matched case-insensitively and solid names are ignored.
+func decodeSTLASCII(data []byte) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	var normal types.Point3D
+	var corners [3]types.Point3D
+	inSolid, inFacet, inLoop, vertices := false, false, false, 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		syntaxError := func(msg string) error {
+			return errors.New(errors.ValidationError, fmt.Sprintf("invalid ASCII STL on line %d: %s", line, msg), nil)
+		}
+		point := func(coordinates []string) (types.Point3D, error) {
+			if len(coordinates) != 3 {
+				return types.Point3D{}, syntaxError(fmt.Sprintf("found %d coordinates, want 3", len(coordinates)))
+			}
+			var c [3]float64
+			for axis, word := range coordinates {
+				value, err := strconv.ParseFloat(word, 64)
+				if err != nil {
+					return types.Point3D{}, syntaxError(fmt.Sprintf("invalid coordinate %q", word))
+				}
+				c[axis] = value
+			}
+			return types.Point3D{X: c[0], Y: c[1], Z: c[2]}, nil
+		}
+
+		var err error
+		switch keyword := strings.ToLower(fields[0]); {
+		case keyword == "solid" && !inSolid:
+			inSolid = true
+		case keyword == "endsolid" && inSolid && !inFacet:
+			inSolid = false
+		case keyword == "facet" && inSolid && !inFacet:
+			if len(fields) < 2 || strings.ToLower(fields[1]) != "normal" {
+				return nil, syntaxError("facet without a normal")
+			}
+			if normal, err = point(fields[2:]); err != nil {
+				return nil, err
+			}
+			inFacet, vertices = true, 0
+		case keyword == "outer" && inFacet && !inLoop && vertices == 0:
+			if len(fields) != 2 || strings.ToLower(fields[1]) != "loop" {
+				return nil, syntaxError("outer without loop")
+			}
+			inLoop = true
+		case keyword == "vertex" && inLoop && vertices < 3:
+			if corners[vertices], err = point(fields[1:]); err != nil {
+				return nil, err
+			}
+			vertices++
+		case keyword == "endloop" && inLoop && vertices == 3:
+			inLoop = false
+		case keyword == "endfacet" && inFacet && !inLoop && vertices == 3:
+			triangles = append(triangles, types.Triangle{Normal: normal, V1: corners[0], V2: corners[1], V3: corners[2]})
+			inFacet = false
+		default:
+			return nil, syntaxError(fmt.Sprintf("unexpected %q", fields[0]))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(errors.IOError, "failed to scan ASCII STL data", err)
+	}
+	if inSolid {
+		return nil, errors.New(errors.ValidationError, "invalid ASCII STL: missing endsolid", nil)
+	}
+	return triangles, nil
+}
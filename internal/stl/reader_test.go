@@ -0,0 +1,114 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// asciiTriangle is the STL triangle of a unit right triangle in the XY plane.
+var asciiTriangle = types.Triangle{
+	Normal: types.Point3D{Z: 1},
+	V1:     types.Point3D{},
+	V2:     types.Point3D{X: 1},
+	V3:     types.Point3D{Y: 1},
+}
+
+func TestReadSTLRoundTrip(t *testing.T) {
+	cube := createTestCube(t, 1.5)
+	path := filepath.Join(t.TempDir(), "cube.stl")
+	if err := WriteSTLBinary(path, cube); err != nil {
+		t.Fatalf("WriteSTLBinary() error = %v", err)
+	}
+
+	got, err := ReadSTL(path)
+	if err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+	// The cube's coordinates and normals are exact in float32
+	if !reflect.DeepEqual(got, cube) {
+		t.Errorf("ReadSTL() = %v, want the written cube %v", got, cube)
+	}
+}
+
+func TestDecodeSTL(t *testing.T) {
+	// A binary file whose header starts like an ASCII one
+	var solidHeader bytes.Buffer
+	solidHeader.WriteString("solid binary")
+	solidHeader.Write(make([]byte, 80-solidHeader.Len()))
+	_ = binary.Write(&solidHeader, binary.LittleEndian, uint32(1))
+	for _, p := range []types.Point3D{asciiTriangle.Normal, asciiTriangle.V1, asciiTriangle.V2, asciiTriangle.V3} {
+		_ = binary.Write(&solidHeader, binary.LittleEndian, p.ToFloat32())
+	}
+	solidHeader.Write([]byte{0, 0})
+
+	tests := []struct {
+		name string
+		data string
+		want []types.Triangle
+	}{
+		{"ascii", `solid triangle
+  facet normal 0 0 1
+    outer loop
+      vertex 0 0 0
+      vertex 1 0 0
+      vertex 0 1 0
+    endloop
+  endfacet
+endsolid triangle
+`, []types.Triangle{asciiTriangle}},
+		{"ascii keywords in capitals", "SOLID\nFACET NORMAL 0 0 1\nOUTER LOOP\nVERTEX 0 0 0\nVERTEX 1 0 0\nVERTEX 0 1 0\nENDLOOP\nENDFACET\nENDSOLID\n", []types.Triangle{asciiTriangle}},
+		{"ascii without normals", "solid\nfacet normal 0 0 0\nouter loop\nvertex 0 0 0\nvertex 1e0 0 0\nvertex 0 1 0\nendloop\nendfacet\nendsolid\n", []types.Triangle{asciiTriangle}},
+		{"ascii solids one after another", "solid a\nendsolid a\nsolid b\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nvertex 0 1 0\nendloop\nendfacet\nendsolid b\n", []types.Triangle{asciiTriangle}},
+		{"empty ascii solid", "solid empty\nendsolid empty\n", nil},
+		{"binary with a solid header", solidHeader.String(), []types.Triangle{asciiTriangle}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeSTL(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("DecodeSTL() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeSTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeSTLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"neither format", "ply\nformat ascii 1.0\n"},
+		{"truncated binary", string(make([]byte, 84+30))},
+		{"missing endsolid", "solid a\n"},
+		{"facet outside a solid", "facet normal 0 0 1\n"},
+		{"missing normal", "solid\nfacet\n"},
+		{"invalid coordinate", "solid\nfacet normal 0 0 1\nouter loop\nvertex 0 zero 0\n"},
+		{"two coordinates", "solid\nfacet normal 0 0 1\nouter loop\nvertex 0 0\n"},
+		{"infinite coordinate", "solid\nfacet normal 0 0 1\nouter loop\nvertex 0 0 Inf\nvertex 1 0 0\nvertex 0 1 0\nendloop\nendfacet\nendsolid\n"},
+		{"missing vertex", "solid\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nendloop\nendfacet\nendsolid\n"},
+		{"unclosed facet", "solid\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nvertex 0 1 0\nendloop\nendsolid\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := DecodeSTL(strings.NewReader(tt.data)); err == nil {
+				t.Errorf("DecodeSTL() = %v, want an error", got)
+			}
+		})
+	}
+
+	if _, err := ReadSTL(filepath.Join(t.TempDir(), "missing.stl")); err == nil {
+		t.Error("ReadSTL() of a missing file error = nil, want an error")
+	}
+}
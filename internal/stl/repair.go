@@ -0,0 +1,290 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// repairWeldDistance is the distance within which vertices are welded into
+// one, in millimeters, far below anything a printer can resolve.
+const repairWeldDistance = 1e-4
+
+// repairReport counts the changes made by repairing a mesh.
+type repairReport struct {
+	WeldedVertices    int // Vertices merged into a nearby vertex
+	RemovedFaces      int // Degenerate and duplicate faces dropped
+	FlippedFaces      int // Faces turned over to agree with their neighbors and face outward
+	RecomputedNormals int // Stored normals replaced by the normal of the face's winding
+}
+
+// add sums the changes of two reports.
+func (r repairReport) add(other repairReport) repairReport {
+	return repairReport{
+		WeldedVertices:    r.WeldedVertices + other.WeldedVertices,
+		RemovedFaces:      r.RemovedFaces + other.RemovedFaces,
+		FlippedFaces:      r.FlippedFaces + other.FlippedFaces,
+		RecomputedNormals: r.RecomputedNormals + other.RecomputedNormals,
+	}
+}
+
+// String lists the changes made, such as "2 vertices welded, 1 face removed".
+func (r repairReport) String() string {
+	var parts []string
+	for _, change := range []struct {
+		count          int
+		singular, verb string
+		plural         string
+	}{
+		{r.WeldedVertices, "vertex", "welded", "vertices"},
+		{r.RemovedFaces, "face", "removed", "faces"},
+		{r.FlippedFaces, "face", "flipped", "faces"},
+		{r.RecomputedNormals, "normal", "recomputed", "normals"},
+	} {
+		switch {
+		case change.count == 1:
+			parts = append(parts, fmt.Sprintf("1 %s %s", change.singular, change.verb))
+		case change.count > 1:
+			parts = append(parts, fmt.Sprintf("%d %s %s", change.count, change.plural, change.verb))
+		}
+	}
+	if len(parts) == 0 {
+		return "nothing to repair"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// repairModel repairs the mesh of every object of the model in place and
+// returns the changes made.
+func repairModel(model *types.Model) repairReport {
+	var report repairReport
+	for i := range model.Objects {
+		mesh, changes := repairMesh(model.Objects[i].Mesh)
+		model.Objects[i].Mesh = mesh
+		report = report.add(changes)
+	}
+	return report
+}
+
+// repairMesh fixes the defects strict slicers and mesh analysis tools reject.
+// Vertices closer than repairWeldDistance are welded, faces left without area
+// and repeated faces are removed, faces are turned over to run the opposite
+// way along every edge to their neighbors and to face out of the solid they
+// enclose, and normals disagreeing with their face's winding are recomputed.
+func repairMesh(mesh types.Mesh) (types.Mesh, repairReport) {
+	var report repairReport
+	vertices, remap := weldVertices(mesh.Vertices, repairWeldDistance)
+	report.WeldedVertices = len(mesh.Vertices) - len(vertices)
+
+	faces := make([]types.Face, 0, len(mesh.Faces))
+	seen := make(map[[3]uint32]bool, len(mesh.Faces))
+	for _, f := range mesh.Faces {
+		v := [3]uint32{remap[f.V[0]], remap[f.V[1]], remap[f.V[2]]}
+		if _, area := faceNormal(vertices, v); area == 0 {
+			report.RemovedFaces++
+			continue
+		}
+		// Rotate the smallest index first, so the same face always has the same key
+		key := v
+		for key[0] > key[1] || key[0] > key[2] {
+			key = [3]uint32{key[1], key[2], key[0]}
+		}
+		if seen[key] {
+			report.RemovedFaces++
+			continue
+		}
+		seen[key] = true
+		faces = append(faces, types.Face{V: v, Normal: f.Normal})
+	}
+
+	report.FlippedFaces = orientFaces(vertices, faces)
+	for i := range faces {
+		if normal, _ := faceNormal(vertices, faces[i].V); !vectorAgrees(normal, faces[i].Normal) {
+			faces[i].Normal = normal
+			report.RecomputedNormals++
+		}
+	}
+	return types.Mesh{Vertices: vertices, Faces: faces}, report
+}
+
+// weldVertices merges vertices closer than distance, keeping the first of
+// each group in place. It returns the remaining vertices and the index each
+// original vertex maps to.
+func weldVertices(vertices []types.Point3D, distance float64) ([]types.Point3D, []uint32) {
+	type cell struct{ x, y, z int64 }
+	cellOf := func(p types.Point3D) cell {
+		return cell{int64(math.Floor(p.X / distance)), int64(math.Floor(p.Y / distance)), int64(math.Floor(p.Z / distance))}
+	}
+
+	var welded []types.Point3D
+	remap := make([]uint32, len(vertices))
+	grid := make(map[cell][]uint32, len(vertices))
+	for i, v := range vertices {
+		c := cellOf(v)
+		match, found := uint32(0), false
+		// A vertex within distance lies in the same cell or one next to it
+	search:
+		for dx := int64(-1); dx <= 1; dx++ {
+			for dy := int64(-1); dy <= 1; dy++ {
+				for dz := int64(-1); dz <= 1; dz++ {
+					for _, j := range grid[cell{c.x + dx, c.y + dy, c.z + dz}] {
+						w := welded[j]
+						if (v.X-w.X)*(v.X-w.X)+(v.Y-w.Y)*(v.Y-w.Y)+(v.Z-w.Z)*(v.Z-w.Z) < distance*distance {
+							match, found = j, true
+							break search
+						}
+					}
+				}
+			}
+		}
+		if !found {
+			match = uint32(len(welded))
+			welded = append(welded, v)
+			grid[c] = append(grid[c], match)
+		}
+		remap[i] = match
+	}
+	return welded, remap
+}
+
+// vectorAgrees reports whether two unit vectors point the same way, to well
+// within the rounding of the geometry that produced them.
+func vectorAgrees(a, b types.Point3D) bool {
+	return a.X*b.X+a.Y*b.Y+a.Z*b.Z > 1-1e-6
+}
+
+// faceNormal returns the unit normal of a face following its winding, and
+// twice its area, which is zero for a face whose corners lie on a line.
+func faceNormal(vertices []types.Point3D, v [3]uint32) (types.Point3D, float64) {
+	a, b, c := vertices[v[0]], vertices[v[1]], vertices[v[2]]
+	u := types.Point3D{X: b.X - a.X, Y: b.Y - a.Y, Z: b.Z - a.Z}
+	w := types.Point3D{X: c.X - a.X, Y: c.Y - a.Y, Z: c.Z - a.Z}
+	n := types.Point3D{X: u.Y*w.Z - u.Z*w.Y, Y: u.Z*w.X - u.X*w.Z, Z: u.X*w.Y - u.Y*w.X}
+	length := math.Sqrt(n.X*n.X + n.Y*n.Y + n.Z*n.Z)
+	if length < 1e-12 {
+		return types.Point3D{}, 0
+	}
+	return types.Point3D{X: n.X / length, Y: n.Y / length, Z: n.Z / length}, length
+}
+
+// orientFaces turns faces over so that neighbors sharing an edge run the
+// opposite way along it, then turns over every closed part enclosing a
+// negative volume, which faces inward, unless it lies inside another part
+// as the cavity of a hollow solid. Only edges between exactly two faces
+// join faces into parts, so solids touching along an edge are oriented
+// separately. Normals are left for the caller to check against the new
+// winding. It returns the number of faces turned over.
+func orientFaces(vertices []types.Point3D, faces []types.Face) int {
+	type edge struct{ a, b uint32 }
+	undirected := func(a, b uint32) edge {
+		if a > b {
+			a, b = b, a
+		}
+		return edge{a, b}
+	}
+	owners := make(map[edge][]int, len(faces)*3/2)
+	for i, f := range faces {
+		for k := 0; k < 3; k++ {
+			e := undirected(f.V[k], f.V[(k+1)%3])
+			owners[e] = append(owners[e], i)
+		}
+	}
+	// runs reports whether face f runs from a to b along one of its edges
+	runs := func(f types.Face, a, b uint32) bool {
+		for k := 0; k < 3; k++ {
+			if f.V[k] == a && f.V[(k+1)%3] == b {
+				return true
+			}
+		}
+		return false
+	}
+	flip := func(i int) {
+		f := &faces[i]
+		f.V[1], f.V[2] = f.V[2], f.V[1]
+	}
+
+	flipped := 0
+	visited := make([]bool, len(faces))
+	for start := range faces {
+		if visited[start] {
+			continue
+		}
+		// Walk the connected part, turning each face to agree with the face it was reached from
+		part := []int{start}
+		visited[start] = true
+		partFlips := 0
+		for next := 0; next < len(part); next++ {
+			f := faces[part[next]]
+			for k := 0; k < 3; k++ {
+				a, b := f.V[k], f.V[(k+1)%3]
+				neighbors := owners[undirected(a, b)]
+				if len(neighbors) != 2 {
+					continue
+				}
+				other := neighbors[0]
+				if other == part[next] {
+					other = neighbors[1]
+				}
+				if visited[other] {
+					continue
+				}
+				visited[other] = true
+				if runs(faces[other], a, b) {
+					flip(other)
+					partFlips++
+				}
+				part = append(part, other)
+			}
+		}
+
+		// A closed part runs both ways along each of its edges, and encloses a
+		// negative volume when it faces inward. The inside of an open part is unknown.
+		balance := make(map[edge]int)
+		volume := 0.0
+		for _, i := range part {
+			f := faces[i]
+			for k := 0; k < 3; k++ {
+				a, b := f.V[k], f.V[(k+1)%3]
+				if a < b {
+					balance[edge{a, b}]++
+				} else {
+					balance[edge{b, a}]--
+				}
+			}
+			a, b, c := vertices[f.V[0]], vertices[f.V[1]], vertices[f.V[2]]
+			volume += a.X*(b.Y*c.Z-b.Z*c.Y) - a.Y*(b.X*c.Z-b.Z*c.X) + a.Z*(b.X*c.Y-b.Y*c.X)
+		}
+		closed := true
+		for _, n := range balance {
+			if n != 0 {
+				closed = false
+				break
+			}
+		}
+		if closed && volume < 0 && !enclosed(vertices, faces, part) {
+			for _, i := range part {
+				flip(i)
+			}
+			partFlips = len(part) - partFlips
+		}
+		flipped += partFlips
+	}
+	return flipped
+}
+
+// enclosed reports whether the part of the faces lies inside the rest of them.
+func enclosed(vertices []types.Point3D, faces []types.Face, part []int) bool {
+	inPart := make(map[int]bool, len(part))
+	for _, i := range part {
+		inPart[i] = true
+	}
+	rest := types.Mesh{Vertices: vertices}
+	for i, f := range faces {
+		if !inPart[i] {
+			rest.Faces = append(rest.Faces, f)
+		}
+	}
+	return rest.Contains(vertices[faces[part[0]].V[0]])
+}
@@ -0,0 +1,119 @@
+package stl
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// flipTriangle reverses the winding of a triangle, leaving its normal alone.
+func flipTriangle(t types.Triangle) types.Triangle {
+	t.V2, t.V3 = t.V3, t.V2
+	return t
+}
+
+func TestRepairMesh(t *testing.T) {
+	cube := createTestCube(t, 0)
+	withFace := func(extra ...types.Triangle) []types.Triangle {
+		return append(append([]types.Triangle(nil), cube...), extra...)
+	}
+
+	nudged := withFace()
+	nudged[0].V1.X += 1e-5
+	flipped := withFace()
+	flipped[3] = flipTriangle(flipped[3])
+	inside := make([]types.Triangle, len(cube))
+	for i, tri := range cube {
+		inside[i] = flipTriangle(tri)
+	}
+	badNormal := withFace()
+	badNormal[5].Normal = types.Point3D{X: 1}
+
+	tests := []struct {
+		name      string
+		triangles []types.Triangle
+		want      repairReport
+	}{
+		{"closed cube", cube, repairReport{}},
+		{"nearly coincident vertex", nudged, repairReport{WeldedVertices: 1}},
+		{"degenerate face", withFace(types.Triangle{V1: cube[0].V1, V2: cube[0].V2, V3: cube[0].V1}), repairReport{RemovedFaces: 1}},
+		{"duplicate face", withFace(cube[4]), repairReport{RemovedFaces: 1}},
+		{"flipped face", flipped, repairReport{FlippedFaces: 1}},
+		{"inside out", inside, repairReport{FlippedFaces: 12}},
+		{"wrong normal", badNormal, repairReport{RecomputedNormals: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh, got := repairMesh(types.NewMesh(tt.triangles))
+			if got != tt.want {
+				t.Errorf("repairMesh() = %+v, want %+v", got, tt.want)
+			}
+			if report := checkMesh(mesh); !report.ok() {
+				t.Errorf("repaired mesh has %s", report)
+			}
+			if volume := signedVolume(mesh); volume < 1-1e-4 || volume > 1+1e-4 {
+				t.Errorf("repaired mesh volume = %v, want 1", volume)
+			}
+			for i := range mesh.Faces {
+				tri := mesh.Triangle(i)
+				normal, _ := faceNormal(mesh.Vertices, mesh.Faces[i].V)
+				if !vectorAgrees(normal, tri.Normal) {
+					t.Errorf("face %d normal %v disagrees with its winding %v", i, tri.Normal, normal)
+				}
+			}
+		})
+	}
+}
+
+// TestRepairMeshKeepsCavity verifies the inward facing walls of a hollow are left alone
+func TestRepairMeshKeepsCavity(t *testing.T) {
+	outer, err := geometry.CreateCube(0, 0, 0, 10, 10, 10)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	inner, err := geometry.CreateCube(2, 2, 2, 2, 2, 2)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	for i := range inner {
+		inner[i] = flipTriangle(inner[i])
+		inner[i].Normal = types.Point3D{X: -inner[i].Normal.X, Y: -inner[i].Normal.Y, Z: -inner[i].Normal.Z}
+	}
+
+	mesh, report := repairMesh(types.NewMesh(append(outer, inner...)))
+	if report != (repairReport{}) {
+		t.Errorf("repairMesh() = %+v, want the cavity left facing inward", report)
+	}
+	if volume := signedVolume(mesh); volume < 992-1e-6 || volume > 992+1e-6 {
+		t.Errorf("repaired mesh volume = %v, want 992", volume)
+	}
+}
+
+func TestRepairReportString(t *testing.T) {
+	tests := []struct {
+		report repairReport
+		want   string
+	}{
+		{repairReport{}, "nothing to repair"},
+		{repairReport{WeldedVertices: 1}, "1 vertex welded"},
+		{repairReport{WeldedVertices: 2, RemovedFaces: 1, FlippedFaces: 3, RecomputedNormals: 4}, "2 vertices welded, 1 face removed, 3 faces flipped, 4 normals recomputed"},
+	}
+	for _, tt := range tests {
+		if got := tt.report.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// signedVolume returns the volume enclosed by a mesh, negative when it faces inward.
+func signedVolume(mesh types.Mesh) float64 {
+	volume := 0.0
+	for i := range mesh.Faces {
+		tri := mesh.Triangle(i)
+		a, b, c := tri.V1, tri.V2, tri.V3
+		volume += a.X*(b.Y*c.Z-b.Z*c.Y) - a.Y*(b.X*c.Z-b.Z*c.X) + a.Z*(b.X*c.Y-b.Y*c.X)
+	}
+	return volume / 6
+}
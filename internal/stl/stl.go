@@ -16,12 +16,16 @@ package stl
 
 import (
 	"bufio"
+	"context"
 	"encoding/binary"
+	"io"
 	"math"
 	"os"
+	"strings"
 
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
 	"github.com/github/gh-skyline/internal/types"
 )
 
@@ -64,15 +68,29 @@ func (w *bufferWriter) writePoint3D(p types.Point3DFloat32) {
 
 // writeSTLHeader writes the 80-byte header to the STL file.
 // The header typically contains version or generator information.
-func writeSTLHeader(writer *bufio.Writer) error {
+func writeSTLHeader(writer *bufio.Writer, metadata []types.Metadata) error {
 	header := make([]byte, 80)
-	copy(header, []byte("Generated by GitHub Contributions Skyline Generator"))
+	copy(header, stlHeader(metadata))
 	if _, err := writer.Write(header); err != nil {
 		return errors.New(errors.IOError, "failed to write STL header", err)
 	}
 	return nil
 }
 
+// stlHeader returns the text of the STL header: the metadata entries as
+// key=value pairs, cut off at the 80 bytes the header holds, or the
+// generator's name without metadata.
+func stlHeader(metadata []types.Metadata) string {
+	if len(metadata) == 0 {
+		return "Generated by GitHub Contributions Skyline Generator"
+	}
+	text := "gh-skyline"
+	for _, m := range metadata {
+		text += " " + m.Key + "=" + strings.Join(strings.Fields(m.Value), " ")
+	}
+	return text[:min(len(text), 80)]
+}
+
 // writeTriangleCount writes the 4-byte unsigned integer indicating
 // the number of triangles in the STL file.
 func writeTriangleCount(writer *bufio.Writer, count uint32) error {
@@ -83,9 +101,11 @@ func writeTriangleCount(writer *bufio.Writer, count uint32) error {
 }
 
 // writeTrianglesData writes all triangles to the STL file using a pre-allocated buffer.
-// Reports progress every 10000 triangles via the logger.
-func writeTrianglesData(writer *bufio.Writer, triangles []types.Triangle) error {
+// Reports progress every 10000 triangles via the logger and the Bar ctx
+// carries, and stops there when ctx is done.
+func writeTrianglesData(ctx context.Context, writer *bufio.Writer, triangles []types.Triangle) error {
 	log := logger.GetLogger()
+	bar := progress.FromContext(ctx)
 	triangleBuffer := make([]byte, triangleSize)
 
 	for i, triangle := range triangles {
@@ -98,9 +118,13 @@ func writeTrianglesData(writer *bufio.Writer, triangles []types.Triangle) error
 		}
 
 		if (i+1)%10000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return errors.New(errors.IOError, "writing STL file canceled", err)
+			}
 			if err := log.Debug("Written %d/%d triangles", i+1, len(triangles)); err != nil {
 				return errors.New(errors.IOError, "failed to log progress", err)
 			}
+			bar.Add(10000)
 		}
 	}
 	return nil
@@ -118,6 +142,13 @@ func writeTrianglesData(writer *bufio.Writer, triangles []types.Triangle) error
 //   - Vertex 3: 3 x float32 (12 bytes)
 //   - Attribute byte count: uint16 (2 bytes, usually 0)
 func WriteSTLBinary(filename string, triangles []types.Triangle) error {
+	return writeSTLBinary(context.Background(), filename, triangles, nil)
+}
+
+// writeSTLBinary writes triangles to a binary STL file whose header holds as
+// much of the metadata as fits. When ctx is done partway through, the partial
+// file is removed.
+func writeSTLBinary(ctx context.Context, filename string, triangles []types.Triangle, metadata []types.Metadata) (err error) {
 	if filename == "" {
 		return errors.New(errors.ValidationError, "STL filename cannot be empty", nil)
 	}
@@ -127,19 +158,41 @@ func WriteSTLBinary(filename string, triangles []types.Triangle) error {
 		return errors.New(errors.IOError, "failed to create STL file", err)
 	}
 	defer func() {
-		if cerr := file.Close(); cerr != nil {
-			err = errors.New(errors.IOError, "failed to close STL file", cerr)
+		// Runs last, once the file is closed
+		if err != nil && ctx.Err() != nil {
+			_ = os.Remove(filename)
 		}
 	}()
-
-	writer := bufio.NewWriterSize(file, bufferSize)
 	defer func() {
-		if ferr := writer.Flush(); ferr != nil {
-			err = errors.New(errors.IOError, "failed to flush writer", ferr)
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close STL file", cerr)
 		}
 	}()
 
-	if err := writeSTLHeader(writer); err != nil {
+	return encodeSTLBinary(ctx, file, triangles, metadata)
+}
+
+// WriteSTLTo writes a model to w in binary STL, such as to stream it to
+// standard output. It gives up when ctx is done, partway through the model.
+func WriteSTLTo(ctx context.Context, w io.Writer, model *types.Model) error {
+	if model == nil {
+		return errors.New(errors.ValidationError, "model cannot be nil", nil)
+	}
+	if err := validateModel(model); err != nil {
+		return errors.Wrap(err, "model validation failed")
+	}
+
+	bar := progress.FromContext(ctx)
+	bar.Start("Writing the model", "triangles", model.TriangleCount())
+	defer bar.Finish()
+	return encodeSTLBinary(ctx, w, model.Triangles(), model.Metadata)
+}
+
+// encodeSTLBinary writes triangles to w in binary STL, with a header holding
+// as much of the metadata as fits, buffering the writes.
+func encodeSTLBinary(ctx context.Context, w io.Writer, triangles []types.Triangle, metadata []types.Metadata) error {
+	writer := bufio.NewWriterSize(w, bufferSize)
+	if err := writeSTLHeader(writer, metadata); err != nil {
 		return err
 	}
 
@@ -154,10 +207,13 @@ func WriteSTLBinary(filename string, triangles []types.Triangle) error {
 		return err
 	}
 
-	if err := writeTrianglesData(writer, triangles); err != nil {
+	if err := writeTrianglesData(ctx, writer, triangles); err != nil {
 		return err
 	}
 
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush writer", err)
+	}
 	return nil
 }
 
@@ -1,11 +1,16 @@
 package stl
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
+	stderrors "errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/github/gh-skyline/internal/render"
 	"github.com/github/gh-skyline/internal/types"
 )
 
@@ -147,3 +152,77 @@ func TestWriteSTLBinary(t *testing.T) {
 	t.Run("handle empty triangle list", testEmptyTriangleList)
 	t.Run("handle nil triangle list", testNilTriangleList)
 }
+
+func TestWriteSTLBinaryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	triangles := make([]types.Triangle, 20000)
+	for i := range triangles {
+		triangles[i] = createTestQuad()[0]
+	}
+
+	path := filepath.Join(t.TempDir(), "canceled.stl")
+	err := writeSTLBinary(ctx, path, triangles, nil)
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("writeSTLBinary() error = %v, want it canceled", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("writeSTLBinary() left the partial file behind: %v", err)
+	}
+}
+
+func TestSTLHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata []types.Metadata
+		want     string
+	}{
+		{"no metadata", nil, "Generated by GitHub Contributions Skyline Generator"},
+		{"metadata", []types.Metadata{{Key: "username", Value: "mona"}, {Key: "years", Value: "2024"}}, "gh-skyline username=mona years=2024"},
+		{"line breaks", []types.Metadata{{Key: "flags", Value: "--stack\n--mirror"}}, "gh-skyline flags=--stack --mirror"},
+		{"cut off", []types.Metadata{{Key: "flags", Value: strings.Repeat("--stack ", 20)}}, "gh-skyline flags=" + strings.Repeat("--stack ", 8)[:63]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stlHeader(tt.metadata)
+			if got != tt.want {
+				t.Errorf("stlHeader() = %q, want %q", got, tt.want)
+			}
+			if len(got) > 80 {
+				t.Errorf("stlHeader() is %d bytes, want at most 80", len(got))
+			}
+		})
+	}
+}
+
+func TestWriteSTLTo(t *testing.T) {
+	model := &types.Model{
+		Objects:  []types.ModelObject{{Name: "cube", Mesh: types.NewMesh(createTestCube(t, 0))}},
+		Metadata: []types.Metadata{{Key: "username", Value: "mona"}},
+	}
+	path := filepath.Join(t.TempDir(), "cube.stl")
+	if err := WriteModel(path, FormatSTL, model, render.DefaultOptions()); err != nil {
+		t.Fatalf("WriteModel() error = %v", err)
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	if err := WriteSTLTo(context.Background(), &got, model); err != nil {
+		t.Fatalf("WriteSTLTo() error = %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("WriteSTLTo() wrote %d bytes differing from the %d of the file WriteModel() writes", got.Len(), len(want))
+	}
+
+	open := &types.Model{Objects: []types.ModelObject{{Name: "quad", Mesh: types.NewMesh(createTestQuad())}}}
+	tests := map[string]*types.Model{"nil model": nil, "open mesh": open}
+	for name, model := range tests {
+		if err := WriteSTLTo(context.Background(), &bytes.Buffer{}, model); err == nil {
+			t.Errorf("WriteSTLTo() with %s error = nil, want an error", name)
+		}
+	}
+}
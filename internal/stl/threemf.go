@@ -0,0 +1,253 @@
+package stl
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/theme"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// 3MF package parts and the namespaces they use.
+const (
+	threeMFModelPath    = "3D/3dmodel.model"
+	threeMFCoreNS       = "http://schemas.microsoft.com/3dmanufacturing/core/2015/02"
+	threeMFModelRelType = "http://schemas.microsoft.com/3dmanufacturing/2013/01/3dmodel"
+
+	threeMFContentTypes = `<?xml version="1.0" encoding="UTF-8"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+ <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+ <Default Extension="model" ContentType="application/vnd.ms-package.3dmanufacturing-3dmodel+xml"/>
+</Types>
+`
+	threeMFRelationships = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+ <Relationship Target="/` + threeMFModelPath + `" Id="rel0" Type="` + threeMFModelRelType + `"/>
+</Relationships>
+`
+)
+
+// threeMFMaterialID is the resource ID of the base materials group.
+const threeMFMaterialID = 1
+
+// materialColors maps each material to the color it is printed in, as
+// #RRGGBB. Towers use the four greens of GitHub's contribution graph.
+var materialColors = []struct {
+	material types.Material
+	color    string
+}{
+	{types.MaterialBase, "#30363D"},
+	{types.MaterialEmboss, "#E6EDF3"},
+	{types.MaterialPanel, "#FFFFFF"},
+	{types.MaterialLevel1, "#9BE9A8"},
+	{types.MaterialLevel2, "#40C463"},
+	{types.MaterialLevel3, "#30A14E"},
+	{types.MaterialLevel4, "#216E39"},
+}
+
+// materialColor returns the color material is printed in, as #RRGGBB: that of
+// its contribution level in palette, when palette is set and the material is
+// of a level, or else fallback.
+func materialColor(material types.Material, fallback string, palette theme.Palette) string {
+	level, ok := material.Level()
+	if !ok || palette.IsZero() {
+		return fallback
+	}
+	c := palette[level]
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// threeMFModel is the root element of the 3D model part of a 3MF package.
+type threeMFModel struct {
+	XMLName   xml.Name          `xml:"model"`
+	Unit      string            `xml:"unit,attr"`
+	Lang      string            `xml:"xml:lang,attr"`
+	Namespace string            `xml:"xmlns,attr"`
+	Metadata  []threeMFMetadata `xml:"metadata"`
+	Resources threeMFResources  `xml:"resources"`
+	Build     []threeMFItem     `xml:"build>item"`
+}
+
+// threeMFMetadata is a named annotation on the model.
+type threeMFMetadata struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// threeMFResources holds the materials and objects the build refers to.
+type threeMFResources struct {
+	Materials *threeMFBaseMaterials `xml:"basematerials,omitempty"`
+	Objects   []threeMFObject       `xml:"object"`
+}
+
+// threeMFBaseMaterials is a group of materials referenced by index.
+type threeMFBaseMaterials struct {
+	ID    int           `xml:"id,attr"`
+	Bases []threeMFBase `xml:"base"`
+}
+
+// threeMFBase is a single named material and its display color.
+type threeMFBase struct {
+	Name  string `xml:"name,attr"`
+	Color string `xml:"displaycolor,attr"`
+}
+
+// threeMFObject is a single named mesh.
+type threeMFObject struct {
+	ID       int               `xml:"id,attr"`
+	Type     string            `xml:"type,attr"`
+	Name     string            `xml:"name,attr,omitempty"`
+	Vertices []threeMFVertex   `xml:"mesh>vertices>vertex"`
+	Faces    []threeMFTriangle `xml:"mesh>triangles>triangle"`
+}
+
+// threeMFVertex is a vertex position in the mesh.
+type threeMFVertex struct {
+	X string `xml:"x,attr"`
+	Y string `xml:"y,attr"`
+	Z string `xml:"z,attr"`
+}
+
+// threeMFTriangle references three vertices by index, counter-clockwise when viewed
+// from outside, and optionally the material it is printed in.
+type threeMFTriangle struct {
+	V1  uint32 `xml:"v1,attr"`
+	V2  uint32 `xml:"v2,attr"`
+	V3  uint32 `xml:"v3,attr"`
+	PID int    `xml:"pid,attr,omitempty"`
+	P1  *int   `xml:"p1,attr"`
+}
+
+// threeMFItem places an object in the build.
+type threeMFItem struct {
+	ObjectID int `xml:"objectid,attr"`
+}
+
+// buildThreeMFModel converts a model into a 3MF model document. Every
+// triangle of an object with a material is tagged with that material, and the
+// materials of the contribution levels take their colors from palette, when
+// it is set.
+func buildThreeMFModel(model *types.Model, palette theme.Palette) (threeMFModel, error) {
+	unit := "millimeter"
+	if model.Unit == types.UnitInch {
+		unit = "inch"
+	}
+	doc := threeMFModel{
+		Unit:      unit,
+		Lang:      "en-US",
+		Namespace: threeMFCoreNS,
+		Metadata:  []threeMFMetadata{{Name: "Application", Value: "GitHub Contributions Skyline Generator"}},
+	}
+	for _, m := range model.Metadata {
+		doc.Metadata = append(doc.Metadata, threeMFMetadata{Name: m.Key, Value: m.Value})
+	}
+
+	// Materials are listed in a fixed order, keeping only those in use
+	used := make(map[types.Material]bool)
+	for _, obj := range model.Objects {
+		if obj.Mesh.Len() > 0 {
+			used[obj.Material] = true
+		}
+	}
+	materialIndex := make(map[types.Material]int)
+	materials := &threeMFBaseMaterials{ID: threeMFMaterialID}
+	for _, mc := range materialColors {
+		if used[mc.material] {
+			materialIndex[mc.material] = len(materials.Bases)
+			materials.Bases = append(materials.Bases, threeMFBase{Name: string(mc.material), Color: materialColor(mc.material, mc.color, palette)})
+		}
+	}
+	if len(materials.Bases) > 0 {
+		doc.Resources.Materials = materials
+	}
+
+	nextID := threeMFMaterialID + 1
+	for _, obj := range model.Objects {
+		if obj.Mesh.Len() == 0 {
+			continue
+		}
+		mesh, err := buildIndexedMesh(obj.Mesh)
+		if err != nil {
+			return threeMFModel{}, errors.Wrap(err, "failed to build 3MF object "+strconv.Quote(obj.Name))
+		}
+
+		object := threeMFObject{ID: nextID, Type: "model", Name: obj.Name}
+		object.Vertices = make([]threeMFVertex, len(mesh.vertices))
+		for i, v := range mesh.vertices {
+			object.Vertices[i] = threeMFVertex{X: formatFloat(v.X), Y: formatFloat(v.Y), Z: formatFloat(v.Z)}
+		}
+		index, hasMaterial := materialIndex[obj.Material]
+		object.Faces = make([]threeMFTriangle, len(mesh.faces))
+		for i, f := range mesh.faces {
+			object.Faces[i] = threeMFTriangle{V1: f[0], V2: f[1], V3: f[2]}
+			if hasMaterial {
+				object.Faces[i].PID, object.Faces[i].P1 = threeMFMaterialID, &index
+			}
+		}
+
+		doc.Resources.Objects = append(doc.Resources.Objects, object)
+		doc.Build = append(doc.Build, threeMFItem{ObjectID: nextID})
+		nextID++
+	}
+	return doc, nil
+}
+
+// Write3MF writes a model to a 3MF package.
+//
+// Each model object becomes its own 3MF object, and objects with a material,
+// such as towers colored by contribution level, have every triangle tagged
+// with a base material so color-capable printers reproduce the heatmap.
+// Objects without triangles are omitted.
+func Write3MF(filename string, model *types.Model) error {
+	return write3MF(filename, model, theme.Palette{})
+}
+
+// write3MF writes a model to a 3MF package as Write3MF does, coloring the
+// contribution levels in palette when it is set.
+func write3MF(filename string, model *types.Model, palette theme.Palette) error {
+	if model == nil {
+		return errors.New(errors.ValidationError, "model cannot be nil", nil)
+	}
+
+	doc, err := buildThreeMFModel(model, palette)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filename, func(writer *bufio.Writer) error {
+		archive := zip.NewWriter(writer)
+		for _, part := range []struct{ name, content string }{
+			{"[Content_Types].xml", threeMFContentTypes},
+			{"_rels/.rels", threeMFRelationships},
+		} {
+			w, err := archive.Create(part.name)
+			if err != nil {
+				return errors.New(errors.IOError, "failed to create 3MF package part", err)
+			}
+			if _, err := w.Write([]byte(part.content)); err != nil {
+				return errors.New(errors.IOError, "failed to write 3MF package part", err)
+			}
+		}
+
+		w, err := archive.Create(threeMFModelPath)
+		if err != nil {
+			return errors.New(errors.IOError, "failed to create 3MF model part", err)
+		}
+		if _, err := w.Write([]byte(xml.Header)); err != nil {
+			return errors.New(errors.IOError, "failed to write 3MF header", err)
+		}
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", " ")
+		if err := encoder.Encode(doc); err != nil {
+			return errors.New(errors.IOError, "failed to encode 3MF model", err)
+		}
+		if err := archive.Close(); err != nil {
+			return errors.New(errors.IOError, "failed to finish 3MF package", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,152 @@
+package stl
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/theme"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// readThreeMFModel opens a 3MF package and decodes its model part.
+func readThreeMFModel(t *testing.T, path string) threeMFModel {
+	t.Helper()
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("3MF file is not a valid package: %v", err)
+	}
+	defer func() { _ = archive.Close() }()
+
+	parts := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		parts[f.Name] = f
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", threeMFModelPath} {
+		if parts[name] == nil {
+			t.Fatalf("3MF package is missing %s", name)
+		}
+	}
+
+	r, err := parts[threeMFModelPath].Open()
+	if err != nil {
+		t.Fatalf("Cannot open 3MF model part: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Cannot read 3MF model part: %v", err)
+	}
+
+	var doc threeMFModel
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("3MF model part is not valid XML: %v", err)
+	}
+	return doc
+}
+
+func TestWrite3MF(t *testing.T) {
+	model := &types.Model{
+		Metadata: []types.Metadata{{Key: "username", Value: "testuser"}},
+		Objects: []types.ModelObject{
+			{Name: "base", Kind: types.ObjectBase, Material: types.MaterialBase, Mesh: types.NewMesh(createTestQuad())},
+			{Name: "text", Kind: types.ObjectText, Material: types.MaterialEmboss},
+			{Name: "tower-0-0", Kind: types.ObjectTower, Material: types.MaterialLevel4, Mesh: types.NewMesh(createTestQuad())},
+			{Name: "tower-0-1", Kind: types.ObjectTower, Material: types.MaterialLevel1, Mesh: types.NewMesh(createTestQuad())},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "test.3mf")
+	if err := Write3MF(path, model); err != nil {
+		t.Fatalf("Write3MF() error = %v", err)
+	}
+	doc := readThreeMFModel(t, path)
+
+	if doc.Unit != "millimeter" {
+		t.Errorf("3MF unit = %q, want millimeter", doc.Unit)
+	}
+	if len(doc.Resources.Objects) != 3 || len(doc.Build) != 3 {
+		t.Fatalf("3MF contains %d objects and %d build items, want 3 (empty objects omitted)",
+			len(doc.Resources.Objects), len(doc.Build))
+	}
+
+	// Only the materials in use are listed, in palette order
+	materials := doc.Resources.Materials
+	if materials == nil {
+		t.Fatal("3MF has no base materials")
+	}
+	var names []string
+	for _, b := range materials.Bases {
+		names = append(names, b.Name)
+	}
+	wantNames := []string{"base", "level-1", "level-4"}
+	if len(names) != len(wantNames) {
+		t.Fatalf("3MF materials = %v, want %v", names, wantNames)
+	}
+	for i := range wantNames {
+		if names[i] != wantNames[i] {
+			t.Fatalf("3MF materials = %v, want %v", names, wantNames)
+		}
+	}
+
+	// Every triangle of the busiest tower uses the darkest green
+	tower := doc.Resources.Objects[1]
+	if tower.Name != "tower-0-0" {
+		t.Fatalf("second object = %q, want tower-0-0", tower.Name)
+	}
+	for i, tri := range tower.Faces {
+		if tri.PID != materials.ID || tri.P1 == nil || materials.Bases[*tri.P1].Name != "level-4" {
+			t.Errorf("tower triangle %d material = %d/%v, want level-4", i, tri.PID, tri.P1)
+		}
+	}
+}
+
+func TestWrite3MFPalette(t *testing.T) {
+	model := &types.Model{
+		Objects: []types.ModelObject{
+			{Name: "base", Kind: types.ObjectBase, Material: types.MaterialBase, Mesh: types.NewMesh(createTestQuad())},
+			{Name: "tower-0-0", Kind: types.ObjectTower, Material: types.MaterialLevel4, Mesh: types.NewMesh(createTestQuad())},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "test.3mf")
+	if err := write3MF(path, model, theme.Halloween); err != nil {
+		t.Fatalf("write3MF() error = %v", err)
+	}
+	doc := readThreeMFModel(t, path)
+
+	want := map[string]string{"base": "#30363D", "level-4": "#03001C"}
+	for _, b := range doc.Resources.Materials.Bases {
+		if b.Color != want[b.Name] {
+			t.Errorf("material %s color = %s, want %s", b.Name, b.Color, want[b.Name])
+		}
+	}
+}
+
+func TestWrite3MFWithoutMaterials(t *testing.T) {
+	model := &types.Model{Unit: types.UnitInch, Objects: []types.ModelObject{{Name: "quad", Mesh: types.NewMesh(createTestQuad())}}}
+
+	path := filepath.Join(t.TempDir(), "plain.3mf")
+	if err := Write3MF(path, model); err != nil {
+		t.Fatalf("Write3MF() error = %v", err)
+	}
+	doc := readThreeMFModel(t, path)
+
+	if doc.Unit != "inch" {
+		t.Errorf("3MF unit = %q, want inch", doc.Unit)
+	}
+	if doc.Resources.Materials != nil {
+		t.Errorf("3MF materials = %v, want none for objects without materials", doc.Resources.Materials)
+	}
+	for _, tri := range doc.Resources.Objects[0].Faces {
+		if tri.PID != 0 || tri.P1 != nil {
+			t.Errorf("triangle has material %d/%v, want none", tri.PID, tri.P1)
+		}
+	}
+
+	if err := Write3MF(path, nil); err == nil {
+		t.Error("Write3MF() expected error for nil model")
+	}
+}
@@ -0,0 +1,46 @@
+package stl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// units lists the supported model units in the order they are presented to users.
+var units = []types.Unit{types.UnitMillimeter, types.UnitInch}
+
+// Units returns the names of all supported model units.
+func Units() []string {
+	names := make([]string, len(units))
+	for i, u := range units {
+		names[i] = string(u)
+	}
+	return names
+}
+
+// ParseUnit converts a user supplied unit name into a types.Unit.
+// Matching is case-insensitive and an empty string selects millimeters.
+func ParseUnit(name string) (types.Unit, error) {
+	if name == "" {
+		return types.UnitMillimeter, nil
+	}
+	for _, u := range units {
+		if strings.EqualFold(name, string(u)) {
+			return u, nil
+		}
+	}
+	return "", errors.New(errors.ValidationError, fmt.Sprintf("unsupported unit %q (supported: %s)", name, strings.Join(Units(), ", ")), nil)
+}
+
+// convertUnits rescales a model generated in millimeters to unit.
+func convertUnits(model *types.Model, unit types.Unit) {
+	if unit == "" {
+		unit = types.UnitMillimeter
+	}
+	if perUnit := unit.Millimeters(); perUnit != 1 {
+		model.Scale(1 / perUnit)
+	}
+	model.Unit = unit
+}
@@ -0,0 +1,65 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    types.Unit
+		wantErr bool
+	}{
+		{"empty selects millimeters", "", types.UnitMillimeter, false},
+		{"millimeters", "mm", types.UnitMillimeter, false},
+		{"inches", "in", types.UnitInch, false},
+		{"case insensitive", "IN", types.UnitInch, false},
+		{"unknown", "cm", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnit(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUnit(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseUnit(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertUnits(t *testing.T) {
+	tests := []struct {
+		name  string
+		unit  types.Unit
+		wantX float64
+		want  types.Unit
+	}{
+		{"default keeps millimeters", "", 254, types.UnitMillimeter},
+		{"millimeters", types.UnitMillimeter, 254, types.UnitMillimeter},
+		{"inches", types.UnitInch, 10, types.UnitInch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &types.Model{Objects: []types.ModelObject{{
+				Mesh: types.NewMesh([]types.Triangle{{V1: types.Point3D{X: 254}}}),
+			}}}
+
+			convertUnits(model, tt.unit)
+
+			if got := model.Objects[0].Mesh.Triangle(0).V1.X; math.Abs(got-tt.wantX) > 1e-9 {
+				t.Errorf("converted X = %v, want %v", got, tt.wantX)
+			}
+			if model.Unit != tt.want {
+				t.Errorf("model unit = %q, want %q", model.Unit, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package stl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// meshReport counts the defects found in a mesh. A closed, consistently wound
+// mesh has none of them.
+type meshReport struct {
+	OpenEdges       int // Edges bordering a single face, the rim of a hole
+	FlippedEdges    int // Edges whose faces run the same way along them, where the winding turns over
+	DuplicateFaces  int // Faces repeating another face with the same winding
+	DegenerateFaces int // Faces without area, with two or more corners at the same vertex
+}
+
+// ok reports whether the mesh is free of defects.
+func (r meshReport) ok() bool {
+	return r == meshReport{}
+}
+
+// watertight reports whether the mesh is closed and consistently wound, so
+// slicers can tell its inside from its outside. Duplicate and degenerate faces
+// are left out, as they add nothing to the surface.
+func (r meshReport) watertight() bool {
+	return r.OpenEdges == 0 && r.FlippedEdges == 0
+}
+
+// String lists the defects found, such as "3 open edges, 1 duplicate face".
+func (r meshReport) String() string {
+	var parts []string
+	for _, defect := range []struct {
+		count int
+		name  string
+	}{
+		{r.OpenEdges, "open edge"},
+		{r.FlippedEdges, "inconsistently wound edge"},
+		{r.DuplicateFaces, "duplicate face"},
+		{r.DegenerateFaces, "degenerate face"},
+	} {
+		switch {
+		case defect.count == 1:
+			parts = append(parts, "1 "+defect.name)
+		case defect.count > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", defect.count, defect.name))
+		}
+	}
+	if len(parts) == 0 {
+		return "no defects"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// meshEdge is an edge between two vertices of a mesh, running from a to b.
+type meshEdge struct {
+	a, b uint32
+}
+
+// checkMesh looks for the defects that make a mesh unprintable. The mesh may be
+// made of several closed solids that touch or overlap, as a model's objects
+// are, so an edge shared by four faces is fine as long as every face running
+// along it one way is matched by one running back.
+func checkMesh(mesh types.Mesh) meshReport {
+	var report meshReport
+	edges := make(map[meshEdge]int, len(mesh.Faces)*3/2)
+	faces := make(map[[3]uint32]bool, len(mesh.Faces))
+	for _, f := range mesh.Faces {
+		a, b, c := f.V[0], f.V[1], f.V[2]
+		if a == b || b == c || c == a {
+			report.DegenerateFaces++
+			continue
+		}
+
+		// Rotate the smallest index first, so the same face always has the same key
+		key := [3]uint32{a, b, c}
+		for key[0] > key[1] || key[0] > key[2] {
+			key = [3]uint32{key[1], key[2], key[0]}
+		}
+		if faces[key] {
+			report.DuplicateFaces++
+			continue
+		}
+		faces[key] = true
+
+		for _, e := range []meshEdge{{a, b}, {b, c}, {c, a}} {
+			// Count each edge under its lower vertex first, up for a to b and down for b to a
+			if e.a < e.b {
+				edges[e]++
+			} else {
+				edges[meshEdge{e.b, e.a}]--
+			}
+		}
+	}
+
+	for _, balance := range edges {
+		if balance == 0 {
+			continue
+		}
+		// An edge crossed once is a hole; one crossed twice the same way is where the winding flips
+		if balance == 1 || balance == -1 {
+			report.OpenEdges++
+		} else {
+			report.FlippedEdges++
+		}
+	}
+	return report
+}
+
+// validateModel checks every object of the model for defects before it is
+// written. Each defective object is logged with the defects found, and the
+// first object that is not watertight fails the model, as slicers reject or
+// misprint open meshes.
+func validateModel(model *types.Model) error {
+	log := logger.GetLogger()
+	var broken *types.ModelObject
+	var brokenReport meshReport
+	for i, obj := range model.Objects {
+		report := checkMesh(obj.Mesh)
+		if report.ok() {
+			continue
+		}
+		if err := log.Warning("Mesh of %s has %s", obj.Name, report); err != nil {
+			return errors.Wrap(err, "failed to log warning message")
+		}
+		if broken == nil && !report.watertight() {
+			broken, brokenReport = &model.Objects[i], report
+		}
+	}
+	if broken != nil {
+		return errors.New(errors.STLError, fmt.Sprintf("mesh of %s is not watertight (%s)", broken.Name, brokenReport), nil)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package stl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// createTestCube returns a closed cube with outward facing triangles.
+func createTestCube(t *testing.T, x float64) []types.Triangle {
+	t.Helper()
+	cube, err := geometry.CreateCube(x, 0, 0, 1, 1, 1)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	return cube
+}
+
+func TestCheckMesh(t *testing.T) {
+	cube := createTestCube(t, 0)
+	flipped := append([]types.Triangle(nil), cube...)
+	flipped[0].V2, flipped[0].V3 = flipped[0].V3, flipped[0].V2
+
+	tests := []struct {
+		name      string
+		triangles []types.Triangle
+		want      meshReport
+	}{
+		{"closed cube", cube, meshReport{}},
+		{"touching cubes", append(createTestCube(t, 0), createTestCube(t, 1)...), meshReport{}},
+		{"missing face", cube[1:], meshReport{OpenEdges: 3}},
+		{"flipped face", flipped, meshReport{FlippedEdges: 3}},
+		{"duplicate face", append(append([]types.Triangle(nil), cube...), cube[0]), meshReport{DuplicateFaces: 1}},
+		{"degenerate face", append(append([]types.Triangle(nil), cube...), types.Triangle{V1: cube[0].V1, V2: cube[0].V1, V3: cube[0].V2}), meshReport{DegenerateFaces: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkMesh(types.NewMesh(tt.triangles)); got != tt.want {
+				t.Errorf("checkMesh() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeshReportString(t *testing.T) {
+	tests := []struct {
+		report meshReport
+		want   string
+	}{
+		{meshReport{}, "no defects"},
+		{meshReport{OpenEdges: 1}, "1 open edge"},
+		{meshReport{OpenEdges: 3, DuplicateFaces: 1}, "3 open edges, 1 duplicate face"},
+		{meshReport{FlippedEdges: 2, DegenerateFaces: 4}, "2 inconsistently wound edges, 4 degenerate faces"},
+	}
+	for _, tt := range tests {
+		if got := tt.report.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestValidateModel(t *testing.T) {
+	cube := createTestCube(t, 0)
+
+	valid := &types.Model{Objects: []types.ModelObject{
+		{Name: "base", Mesh: types.NewMesh(cube)},
+		{Name: "extra", Mesh: types.NewMesh(append(append([]types.Triangle(nil), cube...), cube[0]))},
+	}}
+	if err := validateModel(valid); err != nil {
+		t.Errorf("validateModel() error = %v, want duplicate faces only warned about", err)
+	}
+
+	broken := &types.Model{Objects: []types.ModelObject{
+		{Name: "base", Mesh: types.NewMesh(cube)},
+		{Name: "tower-1-2", Mesh: types.NewMesh(cube[2:])},
+	}}
+	err := validateModel(broken)
+	if err == nil {
+		t.Fatal("validateModel() error = nil, want an error for the open tower")
+	}
+	if !strings.Contains(err.Error(), "tower-1-2") || !strings.Contains(err.Error(), "open edge") {
+		t.Errorf("validateModel() error = %v, want the object and its defects named", err)
+	}
+}
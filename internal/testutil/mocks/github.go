@@ -2,7 +2,11 @@
 package mocks
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/github/gh-skyline/internal/testutil/fixtures"
@@ -11,15 +15,16 @@ import (
 
 // MockGitHubClient implements both GitHubClientInterface and APIClient interfaces
 type MockGitHubClient struct {
-	Username string
-	JoinYear int
-	MockData *types.ContributionsResponse
-	Response interface{} // Generic response field for testing
-	Err      error       // Error to return if needed
+	Username  string
+	JoinYear  int
+	AvatarURL string
+	MockData  *types.ContributionsResponse
+	Response  interface{} // Generic response field for testing
+	Err       error       // Error to return if needed
 }
 
 // GetAuthenticatedUser implements GitHubClientInterface
-func (m *MockGitHubClient) GetAuthenticatedUser() (string, error) {
+func (m *MockGitHubClient) GetAuthenticatedUser(_ context.Context) (string, error) {
 	if m.Err != nil {
 		return "", m.Err
 	}
@@ -30,7 +35,7 @@ func (m *MockGitHubClient) GetAuthenticatedUser() (string, error) {
 }
 
 // GetUserJoinYear implements GitHubClientInterface
-func (m *MockGitHubClient) GetUserJoinYear(_ string) (int, error) {
+func (m *MockGitHubClient) GetUserJoinYear(_ context.Context, _ string) (int, error) {
 	if m.Err != nil {
 		return 0, m.Err
 	}
@@ -41,7 +46,7 @@ func (m *MockGitHubClient) GetUserJoinYear(_ string) (int, error) {
 }
 
 // FetchContributions implements GitHubClientInterface
-func (m *MockGitHubClient) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+func (m *MockGitHubClient) FetchContributions(_ context.Context, username string, year int) (*types.ContributionsResponse, error) {
 	if m.Err != nil {
 		return nil, m.Err
 	}
@@ -49,8 +54,8 @@ func (m *MockGitHubClient) FetchContributions(username string, year int) (*types
 	return fixtures.GenerateContributionsResponse(username, year), nil
 }
 
-// Do implements APIClient
-func (m *MockGitHubClient) Do(_ string, _ map[string]interface{}, response interface{}) error {
+// DoWithContext implements APIClient
+func (m *MockGitHubClient) DoWithContext(_ context.Context, _ string, variables map[string]interface{}, response interface{}) error {
 	if m.Err != nil {
 		return m.Err
 	}
@@ -63,17 +68,60 @@ func (m *MockGitHubClient) Do(_ string, _ map[string]interface{}, response inter
 	}:
 		v.Viewer.Login = m.Username
 	case *struct {
-		User struct {
+		User *struct {
 			CreatedAt time.Time `json:"createdAt"`
 		} `json:"user"`
 	}:
+		v.User = &struct {
+			CreatedAt time.Time `json:"createdAt"`
+		}{}
 		if m.JoinYear > 0 {
 			v.User.CreatedAt = time.Date(m.JoinYear, 1, 1, 0, 0, 0, 0, time.UTC)
 		}
+	case *struct {
+		User struct {
+			AvatarURL string `json:"avatarUrl"`
+		} `json:"user"`
+	}:
+		v.User.AvatarURL = m.AvatarURL
 	case *types.ContributionsResponse:
 		// Always use generated mock data instead of empty response
 		mockResp := fixtures.GenerateContributionsResponse(m.Username, time.Now().Year())
 		*v = *mockResp
+	case *struct {
+		User map[string]json.RawMessage `json:"user"`
+	}:
+		// Answer each year's alias of a batched query with generated mock data
+		login, err := json.Marshal(m.Username)
+		if err != nil {
+			return err
+		}
+		v.User = map[string]json.RawMessage{"login": login}
+		for name := range variables {
+			year, err := strconv.Atoi(strings.TrimPrefix(name, "from"))
+			if err != nil || !strings.HasPrefix(name, "from") {
+				continue
+			}
+			collection, err := json.Marshal(fixtures.GenerateContributionsResponse(m.Username, year).User.ContributionsCollection)
+			if err != nil {
+				return err
+			}
+			v.User[fmt.Sprintf("y%d", year)] = collection
+		}
 	}
 	return nil
 }
+
+// GraphQLFunc implements APIClient by answering each query with the JSON data
+// the function returns for it, decoded into the response as the GitHub
+// GraphQL API's data would be.
+type GraphQLFunc func(query string, variables map[string]interface{}) (string, error)
+
+// DoWithContext implements APIClient
+func (f GraphQLFunc) DoWithContext(_ context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	data, err := f(query, variables)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), response)
+}
@@ -0,0 +1,130 @@
+// Package theme defines the palettes the four contribution levels are colored
+// in, both in the preview and in the exports holding colors, and reads custom
+// palettes from files.
+package theme
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Palette holds the colors of the four contribution levels of GitHub's
+// calendar, from the fewest contributions to the most.
+type Palette [4]color.RGBA
+
+// IsZero reports whether p holds no colors, standing for the default colors
+// of wherever it is used.
+func (p Palette) IsZero() bool {
+	return p == Palette{}
+}
+
+// Built-in palettes.
+var (
+	// GitHubDark is the palette of GitHub's calendar in its dark theme, which
+	// reads well on the dark background of most terminals.
+	GitHubDark = Palette{rgb(0x0e4429), rgb(0x006d32), rgb(0x26a641), rgb(0x39d353)}
+
+	// GitHubLight is the palette of GitHub's calendar in its light theme.
+	GitHubLight = Palette{rgb(0x9be9a8), rgb(0x40c463), rgb(0x30a14e), rgb(0x216e39)}
+
+	// Halloween is the palette GitHub's calendar takes on around Halloween.
+	Halloween = Palette{rgb(0xffee4a), rgb(0xffc501), rgb(0xfe9600), rgb(0x03001c)}
+
+	// Winter is a palette of icy blues.
+	Winter = Palette{rgb(0xb6e3ff), rgb(0x54aeff), rgb(0x0969da), rgb(0x0a3069)}
+)
+
+// builtins names the built-in palettes in the order they are presented to
+// users.
+var builtins = []struct {
+	name    string
+	palette Palette
+}{
+	{"github-dark", GitHubDark},
+	{"github-light", GitHubLight},
+	{"halloween", Halloween},
+	{"winter", Winter},
+}
+
+// Names returns the names of all built-in palettes.
+func Names() []string {
+	names := make([]string, len(builtins))
+	for i, b := range builtins {
+		names[i] = b.name
+	}
+	return names
+}
+
+// Parse returns the built-in palette named value, matched case-insensitively,
+// or else reads value as the path of a palette file.
+func Parse(value string) (Palette, error) {
+	for _, b := range builtins {
+		if strings.EqualFold(value, b.name) {
+			return b.palette, nil
+		}
+	}
+	if _, err := os.Stat(value); err != nil {
+		return Palette{}, errors.New(errors.ValidationError, fmt.Sprintf("unknown theme %q: not a palette file nor a built-in theme (%s)", value, strings.Join(Names(), ", ")), nil)
+	}
+	return Load(value)
+}
+
+// paletteFile is the content of a palette file.
+type paletteFile struct {
+	Levels []string `yaml:"levels"` // Colors of the levels as #rrggbb, fewest contributions first
+}
+
+// Load reads a palette file: YAML, or JSON, with the colors of the four levels
+// listed under levels, such as
+//
+//	levels: ["#ffee4a", "#ffc501", "#fe9600", "#03001c"]
+func Load(path string) (Palette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Palette{}, errors.New(errors.IOError, fmt.Sprintf("failed to read palette file %s", path), err)
+	}
+	var file paletteFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Palette{}, errors.New(errors.ValidationError, fmt.Sprintf("palette file %s is not a YAML mapping", path), err)
+	}
+	if len(file.Levels) != len(Palette{}) {
+		return Palette{}, errors.New(errors.ValidationError, fmt.Sprintf("palette file %s lists %d levels, want %d", path, len(file.Levels), len(Palette{})), nil)
+	}
+
+	var palette Palette
+	for i, value := range file.Levels {
+		c, err := parseColor(value)
+		if err != nil {
+			return Palette{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid level %d in palette file %s", i+1, path), err)
+		}
+		palette[i] = c
+	}
+	return palette, nil
+}
+
+// parseColor parses an opaque color written as #rgb or #rrggbb.
+func parseColor(value string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid color %q, expected #rgb or #rrggbb", value), nil)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid color %q", value), err)
+	}
+	return rgb(uint32(v)), nil
+}
+
+// rgb returns the opaque color written as 0xrrggbb.
+func rgb(v uint32) color.RGBA {
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
@@ -0,0 +1,111 @@
+package theme
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want Palette
+	}{
+		{"github-dark", GitHubDark},
+		{"github-light", GitHubLight},
+		{"Halloween", Halloween},
+		{"WINTER", Winter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.name)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse("autumn"); err == nil {
+		t.Error("Parse() expected error for an unknown theme")
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "palette.yml")
+	if err := os.WriteFile(path, []byte("levels: ['#fff', '#ff0000', '#00ff00', '#0000FF']\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Parse(path)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", path, err)
+	}
+	want := Palette{
+		{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		{R: 0xff, A: 0xff},
+		{G: 0xff, A: 0xff},
+		{B: 0xff, A: 0xff},
+	}
+	if got != want {
+		t.Errorf("Parse(%q) = %v, want %v", path, got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"yaml", "levels:\n  - '#ffee4a'\n  - '#ffc501'\n  - '#fe9600'\n  - '#03001c'\n", false},
+		{"json", `{"levels": ["#ffee4a", "#ffc501", "#fe9600", "#03001c"]}`, false},
+		{"too few levels", "levels: ['#ffee4a', '#ffc501', '#fe9600']\n", true},
+		{"no levels", "colors: ['#ffee4a']\n", true},
+		{"invalid color", "levels: ['#ffee4a', '#ffc501', '#fe9600', 'black']\n", true},
+		{"translucent color", "levels: ['#ffee4a', '#ffc501', '#fe9600', '#03001c80']\n", true},
+		{"not a mapping", "- '#ffee4a'\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "palette")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != Halloween {
+				t.Errorf("Load() = %v, want %v", got, Halloween)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yml")); err == nil {
+		t.Error("Load() expected error for a missing file")
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if len(names) != len(builtins) {
+		t.Fatalf("Names() = %v, want %d names", names, len(builtins))
+	}
+	for _, name := range names {
+		if _, err := Parse(name); err != nil {
+			t.Errorf("Parse(%q) error = %v", name, err)
+		}
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	if !(Palette{}).IsZero() {
+		t.Error("IsZero() = false for the zero palette")
+	}
+	if (Palette{3: color.RGBA{A: 0xff}}).IsZero() {
+		t.Error("IsZero() = true for a palette with a color")
+	}
+}
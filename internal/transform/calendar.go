@@ -0,0 +1,25 @@
+package transform
+
+import (
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Calendar arranges a year of daily counts, keyed by YYYY-MM-DD date, into a
+// [week][day] grid shaped like GitHub's contribution calendar: weeks run
+// Sunday to Saturday, with the first and last weeks cut short at the ends of
+// the year. Days without a count have no contributions.
+func Calendar(year int, counts map[string]int) [][]types.ContributionDay {
+	var weeks [][]types.ContributionDay
+	var week []types.ContributionDay
+	for date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Sunday && len(week) > 0 {
+			weeks = append(weeks, week)
+			week = nil
+		}
+		key := date.Format("2006-01-02")
+		week = append(week, types.ContributionDay{Date: key, ContributionCount: counts[key]})
+	}
+	return append(weeks, week)
+}
@@ -0,0 +1,51 @@
+package transform
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	tests := []struct {
+		name          string
+		year          int
+		wantWeeks     int
+		wantFirstWeek int // Days in the first week, cut short by the weekday the year starts on
+	}{
+		{"starts on a Sunday", 2023, 53, 7},
+		{"leap year starting on a Monday", 2024, 53, 6},
+		{"starts on a Saturday", 2022, 53, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts := map[string]int{time.Date(tt.year, time.March, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02"): 4}
+			grid := Calendar(tt.year, counts)
+			if len(grid) != tt.wantWeeks {
+				t.Errorf("Calendar() has %d weeks, want %d", len(grid), tt.wantWeeks)
+			}
+			if len(grid[0]) != tt.wantFirstWeek {
+				t.Errorf("Calendar() first week has %d days, want %d", len(grid[0]), tt.wantFirstWeek)
+			}
+
+			days, total := 0, 0
+			for _, week := range grid {
+				for i, day := range week {
+					date, err := time.Parse("2006-01-02", day.Date)
+					if err != nil || date.Year() != tt.year {
+						t.Fatalf("Calendar() day %q is not in %d", day.Date, tt.year)
+					}
+					if i > 0 && date.Weekday() == time.Sunday {
+						t.Errorf("week starting %s runs past Saturday", week[0].Date)
+					}
+					days++
+					total += day.ContributionCount
+				}
+			}
+			wantDays := time.Date(tt.year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Sub(time.Date(tt.year, time.January, 1, 0, 0, 0, 0, time.UTC)).Hours() / 24
+			if days != int(wantDays) || total != 4 {
+				t.Errorf("Calendar() has %d days and %d contributions, want %v and 4", days, total, wantDays)
+			}
+		})
+	}
+}
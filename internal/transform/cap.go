@@ -0,0 +1,49 @@
+package transform
+
+import (
+	"math"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Percentile returns the contribution count of the days with contributions
+// across all years at the given percentile, from 0 to 100, by the nearest-rank
+// method: the smallest count at least that share of the active days do not
+// exceed. Days without contributions are left out, so quiet stretches do not
+// lower it. Without active days it returns 0.
+func Percentile(years [][][]types.ContributionDay, percentile float64) int {
+	var counts []int
+	for _, grid := range years {
+		for _, week := range grid {
+			for _, day := range week {
+				if day.ContributionCount > 0 {
+					counts = append(counts, day.ContributionCount)
+				}
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return 0
+	}
+	sort.Ints(counts)
+	rank := int(math.Ceil(percentile / 100 * float64(len(counts))))
+	return counts[min(max(rank, 1), len(counts))-1]
+}
+
+// Cap returns a copy of grid with each day's contribution count clamped to at
+// most limit, so a few outlying days do not flatten the rest of the skyline.
+// A limit of zero or less returns an unmodified copy.
+func Cap(grid [][]types.ContributionDay, limit int) [][]types.ContributionDay {
+	capped := make([][]types.ContributionDay, len(grid))
+	for i, week := range grid {
+		capped[i] = append([]types.ContributionDay(nil), week...)
+		if limit <= 0 {
+			continue
+		}
+		for j := range capped[i] {
+			capped[i][j].ContributionCount = min(capped[i][j].ContributionCount, limit)
+		}
+	}
+	return capped
+}
@@ -0,0 +1,66 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestPercentile(t *testing.T) {
+	days := func(counts ...int) [][][]types.ContributionDay {
+		week := make([]types.ContributionDay, len(counts))
+		for i, count := range counts {
+			week[i] = types.ContributionDay{ContributionCount: count}
+		}
+		return [][][]types.ContributionDay{{week}}
+	}
+	// One import day among ninety-nine ordinary ones
+	ordinary := make([]int, 0, 100)
+	for i := 1; i <= 99; i++ {
+		ordinary = append(ordinary, 1+i%5)
+	}
+
+	tests := []struct {
+		name       string
+		years      [][][]types.ContributionDay
+		percentile float64
+		want       int
+	}{
+		{"no contributions", days(0, 0, 0), 99, 0},
+		{"import day", days(append(ordinary, 500)...), 99, 5},
+		{"all days", days(append(ordinary, 500)...), 100, 500},
+		{"median", days(1, 2, 3, 4, 5), 50, 3},
+		{"zero days left out", days(0, 0, 0, 0, 2, 8), 50, 2},
+		{"lowest", days(4, 9), 0, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.years, tt.percentile); got != tt.want {
+				t.Errorf("Percentile(%v) = %d, want %d", tt.percentile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCap(t *testing.T) {
+	grid := [][]types.ContributionDay{
+		{{Date: "2024-01-01", ContributionCount: 3}, {Date: "2024-01-02", ContributionCount: 500}},
+		{{Date: "2024-01-07", ContributionCount: 0}},
+	}
+	want := [][]types.ContributionDay{
+		{{Date: "2024-01-01", ContributionCount: 3}, {Date: "2024-01-02", ContributionCount: 5}},
+		{{Date: "2024-01-07", ContributionCount: 0}},
+	}
+
+	if got := Cap(grid, 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cap() = %v, want %v", got, want)
+	}
+	if grid[0][1].ContributionCount != 500 {
+		t.Error("Cap() modified its input")
+	}
+	if got := Cap(grid, 0); !reflect.DeepEqual(got, grid) {
+		t.Errorf("Cap() with no limit = %v, want %v", got, grid)
+	}
+}
@@ -0,0 +1,52 @@
+// Package transform provides data transformations applied to contribution
+// grids before they are previewed or turned into geometry.
+package transform
+
+import (
+	"math"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Smooth returns a copy of grid with each day's contribution count replaced by
+// the moving average over a window of days centered on it, in calendar order.
+// Future days are left untouched and excluded from the averages, and the
+// window is truncated at the edges of the grid. A window of one day or less
+// returns an unmodified copy.
+func Smooth(grid [][]types.ContributionDay, window int) [][]types.ContributionDay {
+	smoothed := make([][]types.ContributionDay, len(grid))
+	var days []*types.ContributionDay
+	now := time.Now()
+	for i, week := range grid {
+		smoothed[i] = append([]types.ContributionDay(nil), week...)
+		for j := range smoothed[i] {
+			if !smoothed[i][j].IsAfter(now) {
+				days = append(days, &smoothed[i][j])
+			}
+		}
+	}
+	if window <= 1 {
+		return smoothed
+	}
+
+	counts := make([]int, len(days))
+	for i, day := range days {
+		counts[i] = day.ContributionCount
+	}
+
+	// Running sums make each average O(1) regardless of the window size
+	prefix := make([]int, len(counts)+1)
+	for i, count := range counts {
+		prefix[i+1] = prefix[i] + count
+	}
+
+	before := window / 2
+	after := window - 1 - before
+	for i, day := range days {
+		lo := max(0, i-before)
+		hi := min(len(counts), i+after+1)
+		day.ContributionCount = int(math.Round(float64(prefix[hi]-prefix[lo]) / float64(hi-lo)))
+	}
+	return smoothed
+}
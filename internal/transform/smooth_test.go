@@ -0,0 +1,79 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// createGrid builds a grid of consecutive past days with the given counts, seven per week.
+func createGrid(counts ...int) [][]types.ContributionDay {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var grid [][]types.ContributionDay
+	for i, count := range counts {
+		if i%7 == 0 {
+			grid = append(grid, nil)
+		}
+		day := types.ContributionDay{ContributionCount: count, Date: start.AddDate(0, 0, i).Format("2006-01-02")}
+		grid[len(grid)-1] = append(grid[len(grid)-1], day)
+	}
+	return grid
+}
+
+// flatten returns the counts of grid in calendar order.
+func flatten(grid [][]types.ContributionDay) []int {
+	var counts []int
+	for _, week := range grid {
+		for _, day := range week {
+			counts = append(counts, day.ContributionCount)
+		}
+	}
+	return counts
+}
+
+func TestSmooth(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []int
+		window int
+		want   []int
+	}{
+		{"disabled", []int{0, 9, 0}, 0, []int{0, 9, 0}},
+		{"single day window", []int{0, 9, 0}, 1, []int{0, 9, 0}},
+		{"spike spreads to neighbors", []int{0, 0, 9, 0, 0}, 3, []int{0, 3, 3, 3, 0}},
+		{"edges use truncated window", []int{6, 0, 0}, 3, []int{3, 2, 0}},
+		{"even window", []int{0, 8, 0, 0}, 2, []int{0, 4, 4, 0}},
+		{"across weeks", []int{0, 0, 0, 0, 0, 0, 0, 14, 0}, 3, []int{0, 0, 0, 0, 0, 0, 5, 5, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := createGrid(tt.counts...)
+			got := flatten(Smooth(grid, tt.window))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Smooth() returned %d days, want %d", len(got), len(tt.want))
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Smooth() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+			if flatten(grid)[0] != tt.counts[0] {
+				t.Error("Smooth() modified the input grid")
+			}
+		})
+	}
+}
+
+func TestSmoothSkipsFutureDays(t *testing.T) {
+	grid := createGrid(0, 9)
+	future := types.ContributionDay{ContributionCount: 0, Date: time.Now().AddDate(1, 0, 0).Format("2006-01-02")}
+	grid[0] = append(grid[0], future)
+
+	got := flatten(Smooth(grid, 3))
+	if got[0] != 5 || got[1] != 5 || got[2] != 0 {
+		t.Errorf("Smooth() = %v, want future day excluded from averages", got)
+	}
+}
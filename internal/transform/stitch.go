@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"strconv"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Stitch makes consecutive years of [week][day] grids, the first of them
+// startYear, meet at the turn of each year without overlapping or leaving a
+// gap. A calendar can list days of the week that straddles December 31 and
+// January 1 that belong to the neighboring year, which would then be counted
+// in both years, or only in the wrong one. Each day is kept in the grid of its
+// own year: a day its own grid also lists is dropped from the other, and a day
+// only listed by another grid is moved to its own, before or after its days.
+// Days of years outside the range are dropped. Grids holding only their own
+// days are returned as they are, and the others are regrouped into weeks
+// starting on DefaultWeekStart, as GitHub's calendar is.
+func Stitch(years [][][]types.ContributionDay, startYear int) [][][]types.ContributionDay {
+	listed := make(map[string]bool)
+	for i, grid := range years {
+		for _, week := range grid {
+			for _, day := range week {
+				if year, ok := dayYear(day); ok && year == startYear+i {
+					listed[day.Date] = true
+				}
+			}
+		}
+	}
+
+	// Days listed in the wrong grid, by the index of their own year's grid
+	moved := make(map[int][]types.ContributionDay)
+	stray := make(map[int]bool)
+	for i, grid := range years {
+		for _, week := range grid {
+			for _, day := range week {
+				year, ok := dayYear(day)
+				if !ok || year == startYear+i {
+					continue
+				}
+				stray[i] = true
+				own := year - startYear
+				if own >= 0 && own < len(years) && !listed[day.Date] {
+					moved[own] = append(moved[own], day)
+					listed[day.Date] = true
+				}
+			}
+		}
+	}
+	if len(stray) == 0 {
+		return years
+	}
+
+	stitched := make([][][]types.ContributionDay, len(years))
+	for i, grid := range years {
+		if !stray[i] && len(moved[i]) == 0 {
+			stitched[i] = grid
+			continue
+		}
+		var before, days, after []types.ContributionDay
+		for _, week := range grid {
+			for _, day := range week {
+				if year, ok := dayYear(day); !ok || year == startYear+i {
+					days = append(days, day)
+				}
+			}
+		}
+		for _, day := range moved[i] {
+			if len(days) > 0 && day.Date > days[len(days)-1].Date {
+				after = append(after, day)
+			} else {
+				before = append(before, day)
+			}
+		}
+		all := append(append(before, days...), after...)
+		stitched[i] = WeekStart([][]types.ContributionDay{all}, DefaultWeekStart)
+	}
+	return stitched
+}
+
+// dayYear returns the year of a day's date, or false when it has no valid
+// date.
+func dayYear(day types.ContributionDay) (int, bool) {
+	if len(day.Date) != len("2006-01-02") {
+		return 0, false
+	}
+	year, err := strconv.Atoi(day.Date[:4])
+	return year, err == nil
+}
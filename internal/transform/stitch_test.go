@@ -0,0 +1,83 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestStitch(t *testing.T) {
+	day := func(date string, count int) types.ContributionDay {
+		return types.ContributionDay{Date: date, ContributionCount: count}
+	}
+	// 2023 ends on a Sunday, so 2023-12-31 starts the week that straddles the new year
+	year2023 := Calendar(2023, map[string]int{"2023-12-30": 1, "2023-12-31": 2})
+	year2024 := Calendar(2024, map[string]int{"2024-01-01": 3, "2024-01-06": 4})
+	padded2023 := append(Calendar(2023, map[string]int{"2023-12-30": 1, "2023-12-31": 2})[:52:52], []types.ContributionDay{
+		day("2023-12-31", 2), day("2024-01-01", 3), day("2024-01-02", 0), day("2024-01-03", 0), day("2024-01-04", 0), day("2024-01-05", 0), day("2024-01-06", 4),
+	})
+	short2023 := append(Calendar(2023, map[string]int{"2023-12-30": 1})[:52:52], nil)
+	padded2024 := append([][]types.ContributionDay{{day("2023-12-31", 2)}}, year2024...)
+
+	tests := []struct {
+		name  string
+		years [][][]types.ContributionDay
+	}{
+		{"calendars meeting at the new year", [][][]types.ContributionDay{year2023, year2024}},
+		{"week padded past the new year", [][][]types.ContributionDay{padded2023, year2024}},
+		{"week padded before the new year", [][][]types.ContributionDay{year2023, padded2024}},
+		{"day only listed in the next year", [][][]types.ContributionDay{short2023, padded2024}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Stitch(tt.years, 2023)
+			if !reflect.DeepEqual(got, [][][]types.ContributionDay{year2023, year2024}) {
+				t.Errorf("Stitch() ends 2023 with %v and starts 2024 with %v, want %v and %v",
+					got[0][len(got[0])-1], got[1][0], year2023[len(year2023)-1], year2024[0])
+			}
+		})
+	}
+}
+
+func TestStitchYears(t *testing.T) {
+	// Stitched years list every day of the range exactly once
+	counts := map[string]int{"2019-12-31": 1, "2020-01-01": 2, "2020-12-31": 3, "2021-01-01": 4}
+	var years [][][]types.ContributionDay
+	for year := 2019; year <= 2021; year++ {
+		// Pad the weeks straddling the turn of the year to whole weeks with the
+		// neighboring years' days, including those of 2018 and 2022
+		grid := Calendar(year, counts)
+		previous, next := Calendar(year-1, counts), Calendar(year+1, counts)
+		grid[0] = append(append([]types.ContributionDay{}, previous[len(previous)-1]...), grid[0]...)
+		grid[len(grid)-1] = append(append([]types.ContributionDay{}, grid[len(grid)-1]...), next[0]...)
+		years = append(years, grid)
+	}
+
+	stitched := Stitch(years, 2019)
+	for i, grid := range stitched {
+		if want := Calendar(2019+i, counts); !reflect.DeepEqual(grid, want) {
+			t.Errorf("Stitch() year %d is not laid out as its calendar", 2019+i)
+		}
+	}
+	seen := make(map[string]int)
+	for _, grid := range stitched {
+		for _, week := range grid {
+			for _, day := range week {
+				seen[day.Date]++
+				if want := counts[day.Date]; day.ContributionCount != want {
+					t.Errorf("%s counts %d, want %d", day.Date, day.ContributionCount, want)
+				}
+			}
+		}
+	}
+	if len(seen) != 365+366+365 {
+		t.Errorf("Stitch() lists %d days, want every day of 2019-2021", len(seen))
+	}
+	for date, times := range seen {
+		if times != 1 {
+			t.Errorf("Stitch() lists %s %d times, want once", date, times)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package transform
+
+import "github.com/github/gh-skyline/internal/types"
+
+// Sum adds up several [week][day] grids of the same year, such as those of the
+// members of a team, day by day. The result is shaped like the first grid and
+// days missing from it are left out, so it should cover at least the days of
+// the others.
+func Sum(grids ...[][]types.ContributionDay) [][]types.ContributionDay {
+	if len(grids) == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, grid := range grids {
+		for _, week := range grid {
+			for _, day := range week {
+				counts[day.Date] += day.ContributionCount
+			}
+		}
+	}
+
+	sum := make([][]types.ContributionDay, len(grids[0]))
+	for i, week := range grids[0] {
+		sum[i] = make([]types.ContributionDay, len(week))
+		for j, day := range week {
+			sum[i][j] = types.ContributionDay{Date: day.Date, ContributionCount: counts[day.Date]}
+		}
+	}
+	return sum
+}
@@ -0,0 +1,52 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestSum(t *testing.T) {
+	day := func(date string, count int) types.ContributionDay {
+		return types.ContributionDay{Date: date, ContributionCount: count}
+	}
+	alice := [][]types.ContributionDay{
+		{day("2024-01-01", 1), day("2024-01-02", 0)},
+		{day("2024-01-07", 4)},
+	}
+	bob := [][]types.ContributionDay{
+		{day("2024-01-01", 2), day("2024-01-02", 3)},
+		{day("2024-01-07", 0)},
+	}
+
+	tests := []struct {
+		name  string
+		grids [][][]types.ContributionDay
+		want  [][]types.ContributionDay
+	}{
+		{"none", nil, nil},
+		{"one", [][][]types.ContributionDay{alice}, alice},
+		{"two", [][][]types.ContributionDay{alice, bob}, [][]types.ContributionDay{
+			{day("2024-01-01", 3), day("2024-01-02", 3)},
+			{day("2024-01-07", 4)},
+		}},
+		{"shorter grid", [][][]types.ContributionDay{alice, bob[:1]}, [][]types.ContributionDay{
+			{day("2024-01-01", 3), day("2024-01-02", 3)},
+			{day("2024-01-07", 4)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sum(tt.grids...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The grids summed are left as they were
+	if alice[0][0].ContributionCount != 1 {
+		t.Error("Sum() modified its input")
+	}
+}
@@ -0,0 +1,28 @@
+package transform
+
+import (
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Weekdays returns a copy of grid without its Saturdays and Sundays, for a
+// skyline of the working week. Weeks left without days, such as a first week
+// holding only a Saturday, are dropped. Days without a valid date are kept.
+func Weekdays(grid [][]types.ContributionDay) [][]types.ContributionDay {
+	var weeks [][]types.ContributionDay
+	for _, week := range grid {
+		var kept []types.ContributionDay
+		for _, day := range week {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err == nil && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+				continue
+			}
+			kept = append(kept, day)
+		}
+		if len(kept) > 0 {
+			weeks = append(weeks, kept)
+		}
+	}
+	return weeks
+}
@@ -0,0 +1,43 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWeekdays(t *testing.T) {
+	tests := []struct {
+		name      string
+		grid      [][]types.ContributionDay
+		wantWeeks int
+		wantDays  int
+	}{
+		{"year starting on a Monday", Calendar(2024, nil), 53, 262},
+		{"year starting on a Saturday", Calendar(2022, nil), 52, 260},
+		{"undated day", [][]types.ContributionDay{{{Date: "2024-01-06"}, {Date: "unknown"}}}, 1, 1},
+		{"weekend only", [][]types.ContributionDay{{{Date: "2024-01-06"}, {Date: "2024-01-07"}}}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Weekdays(tt.grid)
+			days := 0
+			for _, week := range got {
+				if len(week) > 5 {
+					t.Errorf("week of %d days, want at most 5", len(week))
+				}
+				for _, day := range week {
+					days++
+					if date, err := time.Parse("2006-01-02", day.Date); err == nil && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+						t.Errorf("Weekdays() kept %s, a %s", day.Date, date.Weekday())
+					}
+				}
+			}
+			if len(got) != tt.wantWeeks || days != tt.wantDays {
+				t.Errorf("Weekdays() = %d weeks of %d days, want %d of %d", len(got), days, tt.wantWeeks, tt.wantDays)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// DefaultWeekStart is the first day of the week in GitHub's contribution calendar.
+const DefaultWeekStart = time.Sunday
+
+// weekStarts lists the supported first days of the week in the order they are presented to users.
+var weekStarts = []time.Weekday{time.Sunday, time.Monday}
+
+// WeekStarts returns the names of all supported first days of the week.
+func WeekStarts() []string {
+	names := make([]string, len(weekStarts))
+	for i, d := range weekStarts {
+		names[i] = strings.ToLower(d.String())
+	}
+	return names
+}
+
+// ParseWeekStart converts a user supplied day name into the first day of the week.
+// Matching is case-insensitive and an empty string selects DefaultWeekStart.
+func ParseWeekStart(name string) (time.Weekday, error) {
+	if name == "" {
+		return DefaultWeekStart, nil
+	}
+	for _, d := range weekStarts {
+		if strings.EqualFold(name, d.String()) {
+			return d, nil
+		}
+	}
+	return DefaultWeekStart, errors.New(errors.ValidationError, fmt.Sprintf("unsupported week start %q (supported: %s)", name, strings.Join(WeekStarts(), ", ")), nil)
+}
+
+// WeekStart returns a copy of grid with its days regrouped into weeks that
+// begin on start, keeping them in calendar order. As in GitHub's calendar,
+// the first and last weeks hold only the days of the grid that fall in them.
+// Days without a valid date stay in the week of the day before them.
+func WeekStart(grid [][]types.ContributionDay, start time.Weekday) [][]types.ContributionDay {
+	var weeks [][]types.ContributionDay
+	for _, week := range grid {
+		for _, day := range week {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if len(weeks) == 0 || (err == nil && date.Weekday() == start && len(weeks[len(weeks)-1]) > 0) {
+				weeks = append(weeks, nil)
+			}
+			weeks[len(weeks)-1] = append(weeks[len(weeks)-1], day)
+		}
+	}
+	return weeks
+}
+
+// MergeEndWeeks returns grid with its first and last weeks merged into its
+// first week when it spans more than weeks weeks and the two together hold no
+// more than a week of days. Regrouped into weeks starting on Monday, a leap
+// year beginning on a Sunday, such as 2012, spans 54 weeks with a single day
+// in each of the first and last, which this keeps to 53. The days of the last
+// week follow those of the first, so the first week still starts the year.
+// Other grids are returned as they are.
+func MergeEndWeeks(grid [][]types.ContributionDay, weeks int) [][]types.ContributionDay {
+	if len(grid) <= weeks || len(grid) < 2 {
+		return grid
+	}
+	first, last := grid[0], grid[len(grid)-1]
+	if len(first)+len(last) > 7 {
+		return grid
+	}
+	merged := make([][]types.ContributionDay, 0, len(grid)-1)
+	merged = append(merged, append(append([]types.ContributionDay(nil), first...), last...))
+	return append(merged, grid[1:len(grid)-1]...)
+}
@@ -0,0 +1,117 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseWeekStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Weekday
+		wantErr bool
+	}{
+		{"empty selects default", "", DefaultWeekStart, false},
+		{"sunday", "sunday", time.Sunday, false},
+		{"monday", "monday", time.Monday, false},
+		{"case insensitive", "Monday", time.Monday, false},
+		{"unsupported day", "saturday", DefaultWeekStart, true},
+		{"unknown", "someday", DefaultWeekStart, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWeekStart(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeekStart(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWeekStart(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	// createGrid starts on Sunday, January 1st 2023
+	counts := make([]int, 15)
+	for i := range counts {
+		counts[i] = i
+	}
+	grid := createGrid(counts...)
+
+	tests := []struct {
+		name  string
+		start time.Weekday
+		want  [][]int
+	}{
+		{"sunday keeps the weeks", time.Sunday, [][]int{{0, 1, 2, 3, 4, 5, 6}, {7, 8, 9, 10, 11, 12, 13}, {14}}},
+		{"monday moves each sunday to the week before", time.Monday, [][]int{{0}, {1, 2, 3, 4, 5, 6, 7}, {8, 9, 10, 11, 12, 13, 14}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WeekStart(grid, tt.start)
+			if len(got) != len(tt.want) {
+				t.Fatalf("WeekStart() returned %d weeks, want %d", len(got), len(tt.want))
+			}
+			for i, week := range got {
+				if len(week) != len(tt.want[i]) {
+					t.Fatalf("week %d has %d days, want %d", i, len(week), len(tt.want[i]))
+				}
+				for j, day := range week {
+					if day.ContributionCount != tt.want[i][j] {
+						t.Errorf("week %d day %d = %d, want %d", i, j, day.ContributionCount, tt.want[i][j])
+					}
+				}
+			}
+		})
+	}
+
+	// The grid itself is left untouched
+	if len(grid[0]) != 7 || grid[1][0].ContributionCount != 7 {
+		t.Error("WeekStart() modified its input")
+	}
+}
+
+func TestWeekStartUndatedDays(t *testing.T) {
+	grid := [][]types.ContributionDay{{{Date: "2023-01-01"}, {}, {Date: "2023-01-02"}, {}}}
+	got := WeekStart(grid, time.Monday)
+	if len(got) != 2 || len(got[0]) != 2 || len(got[1]) != 2 {
+		t.Errorf("WeekStart() = %v, want undated days kept with the day before them", got)
+	}
+}
+
+func TestMergeEndWeeks(t *testing.T) {
+	// 2012 is a leap year starting on a Sunday
+	monday := WeekStart(Calendar(2012, nil), time.Monday)
+	if len(monday) != 54 {
+		t.Fatalf("WeekStart() spread 2012 over %d weeks, want 54", len(monday))
+	}
+
+	got := MergeEndWeeks(monday, 53)
+	if len(got) != 53 {
+		t.Fatalf("MergeEndWeeks() returned %d weeks, want 53", len(got))
+	}
+	if len(got[0]) != 2 || got[0][0].Date != "2012-01-01" || got[0][1].Date != "2012-12-31" {
+		t.Errorf("MergeEndWeeks() first week = %v, want January 1st then December 31st", got[0])
+	}
+	if got[1][0].Date != "2012-01-02" || got[52][6].Date != "2012-12-30" {
+		t.Errorf("MergeEndWeeks() moved the full weeks: %v ... %v", got[1], got[52])
+	}
+	if len(monday) != 54 || len(monday[0]) != 1 {
+		t.Error("MergeEndWeeks() modified its input")
+	}
+
+	// Grids that already fit, or whose end weeks hold more than a week, are kept
+	if sunday := Calendar(2012, nil); len(MergeEndWeeks(sunday, 53)) != len(sunday) {
+		t.Error("MergeEndWeeks() merged a grid that fits")
+	}
+	full := [][]types.ContributionDay{make([]types.ContributionDay, 4), make([]types.ContributionDay, 7), make([]types.ContributionDay, 4)}
+	if got := MergeEndWeeks(full, 2); len(got) != 3 {
+		t.Errorf("MergeEndWeeks() merged end weeks of %d days", len(full[0])+len(full[2]))
+	}
+}
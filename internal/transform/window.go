@@ -0,0 +1,46 @@
+package transform
+
+import (
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Window crops a [week][day] grid to the days from from to to, inclusive, such
+// as the months of a --year range. Weeks left without days are dropped, so the
+// grid starts and ends with the window rather than with the year.
+func Window(weeks [][]types.ContributionDay, from, to time.Time) [][]types.ContributionDay {
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+	var cropped [][]types.ContributionDay
+	for _, week := range weeks {
+		var kept []types.ContributionDay
+		for _, day := range week {
+			if day.Date >= first && day.Date <= last {
+				kept = append(kept, day)
+			}
+		}
+		if len(kept) > 0 {
+			cropped = append(cropped, kept)
+		}
+	}
+	return cropped
+}
+
+// Until crops a [week][day] grid to the days up to now, leaving out those
+// still to come. Weeks left without days are dropped, so the grid ends with
+// the current week.
+func Until(weeks [][]types.ContributionDay, now time.Time) [][]types.ContributionDay {
+	var cropped [][]types.ContributionDay
+	for _, week := range weeks {
+		var kept []types.ContributionDay
+		for _, day := range week {
+			if !day.IsAfter(now) {
+				kept = append(kept, day)
+			}
+		}
+		if len(kept) > 0 {
+			cropped = append(cropped, kept)
+		}
+	}
+	return cropped
+}
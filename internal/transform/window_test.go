@@ -0,0 +1,83 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWindow(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	year := Calendar(2024, map[string]int{"2024-01-31": 2, "2024-02-01": 3, "2024-06-30": 5})
+
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		wantWeeks int
+		wantFirst types.ContributionDay
+		wantLast  types.ContributionDay
+	}{
+		{"whole year", date(2024, time.January, 1), date(2024, time.December, 31), len(year),
+			types.ContributionDay{Date: "2024-01-01"}, types.ContributionDay{Date: "2024-12-31"}},
+		{"first half", date(2024, time.January, 1), date(2024, time.June, 30), 27,
+			types.ContributionDay{Date: "2024-01-01"}, types.ContributionDay{Date: "2024-06-30", ContributionCount: 5}},
+		{"mid week start", date(2024, time.February, 1), date(2024, time.February, 29), 5,
+			types.ContributionDay{Date: "2024-02-01", ContributionCount: 3}, types.ContributionDay{Date: "2024-02-29"}},
+		{"outside", date(2025, time.January, 1), date(2025, time.March, 31), 0,
+			types.ContributionDay{}, types.ContributionDay{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Window(year, tt.from, tt.to)
+			if len(got) != tt.wantWeeks {
+				t.Fatalf("Window() has %d weeks, want %d", len(got), tt.wantWeeks)
+			}
+			if len(got) == 0 {
+				return
+			}
+			first, lastWeek := got[0][0], got[len(got)-1]
+			if !reflect.DeepEqual(first, tt.wantFirst) || !reflect.DeepEqual(lastWeek[len(lastWeek)-1], tt.wantLast) {
+				t.Errorf("Window() runs %v to %v, want %v to %v", first, lastWeek[len(lastWeek)-1], tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestUntil(t *testing.T) {
+	year := Calendar(2024, map[string]int{"2024-03-05": 4, "2024-03-07": 1})
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantWeeks int
+		wantLast  string
+	}{
+		{"mid week", time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC), 10, "2024-03-06"},
+		{"year over", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), len(year), "2024-12-31"},
+		{"before the year", time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC), 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Until(year, tt.now)
+			if len(got) != tt.wantWeeks {
+				t.Fatalf("Until() has %d weeks, want %d", len(got), tt.wantWeeks)
+			}
+			if len(got) == 0 {
+				return
+			}
+			lastWeek := got[len(got)-1]
+			if last := lastWeek[len(lastWeek)-1]; last.Date != tt.wantLast {
+				t.Errorf("Until() ends with %s, want %s", last.Date, tt.wantLast)
+			}
+		})
+	}
+	if got := Until(year, time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)); got[9][2].ContributionCount != 4 {
+		t.Errorf("Until() changed the count of 2024-03-05 to %d, want 4", got[9][2].ContributionCount)
+	}
+}
@@ -0,0 +1,122 @@
+package types
+
+import "math"
+
+// Face is a triangle of a Mesh. V holds the indices of its corners in the
+// mesh's vertex list, counter-clockwise when seen from outside the mesh.
+type Face struct {
+	V      [3]uint32
+	Normal Point3D
+}
+
+// Mesh is an indexed triangle mesh. Vertices shared between faces are stored
+// once and referenced by index, so transforming a mesh touches every vertex
+// once, and exporters of indexed formats can write the vertex list as is.
+// The zero value is an empty mesh ready to use.
+type Mesh struct {
+	Vertices []Point3D
+	Faces    []Face
+
+	index map[Point3D]uint32 // Position of every vertex, built on first Add
+}
+
+// NewMesh builds an indexed mesh from triangles, sharing vertices at the same
+// position. Vertices are numbered in order of first appearance, so identical
+// triangles give identical meshes.
+func NewMesh(triangles []Triangle) Mesh {
+	var m Mesh
+	m.Add(triangles...)
+	return m
+}
+
+// Add appends triangles to the mesh, reusing the vertices already in it.
+func (m *Mesh) Add(triangles ...Triangle) {
+	if m.index == nil {
+		m.index = make(map[Point3D]uint32, len(m.Vertices)+len(triangles))
+		for i, v := range m.Vertices {
+			if _, ok := m.index[v]; !ok {
+				m.index[v] = uint32(i)
+			}
+		}
+	}
+	lookup := func(p Point3D) uint32 {
+		if i, ok := m.index[p]; ok {
+			return i
+		}
+		i := uint32(len(m.Vertices))
+		m.index[p] = i
+		m.Vertices = append(m.Vertices, p)
+		return i
+	}
+
+	for _, t := range triangles {
+		m.Faces = append(m.Faces, Face{V: [3]uint32{lookup(t.V1), lookup(t.V2), lookup(t.V3)}, Normal: t.Normal})
+	}
+}
+
+// Len returns the number of faces in the mesh.
+func (m Mesh) Len() int {
+	return len(m.Faces)
+}
+
+// Triangle returns face i as a standalone triangle.
+func (m Mesh) Triangle(i int) Triangle {
+	f := m.Faces[i]
+	return Triangle{Normal: f.Normal, V1: m.Vertices[f.V[0]], V2: m.Vertices[f.V[1]], V3: m.Vertices[f.V[2]]}
+}
+
+// Triangles expands the mesh into a list of standalone triangles, in face order.
+func (m Mesh) Triangles() []Triangle {
+	triangles := make([]Triangle, len(m.Faces))
+	for i := range m.Faces {
+		triangles[i] = m.Triangle(i)
+	}
+	return triangles
+}
+
+// Transform moves every vertex of the mesh by fn. The vertex index is dropped,
+// as vertices may no longer be where it says.
+func (m *Mesh) Transform(fn func(Point3D) Point3D) {
+	for i, v := range m.Vertices {
+		m.Vertices[i] = fn(v)
+	}
+	m.index = nil
+}
+
+// Contains reports whether p lies inside the mesh, which must be closed, by
+// counting the faces a ray from p crosses. The ray points in an odd direction
+// so it does not run along the faces and edges of axis-aligned meshes.
+func (m Mesh) Contains(p Point3D) bool {
+	sub := func(a, b Point3D) Point3D { return Point3D{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z} }
+	dot := func(a, b Point3D) float64 { return a.X*b.X + a.Y*b.Y + a.Z*b.Z }
+	cross := func(a, b Point3D) Point3D {
+		return Point3D{X: a.Y*b.Z - a.Z*b.Y, Y: a.Z*b.X - a.X*b.Z, Z: a.X*b.Y - a.Y*b.X}
+	}
+
+	dir := Point3D{X: 0.8017, Y: 0.4621, Z: 0.3791}
+	crossings := 0
+	for _, f := range m.Faces {
+		// Möller-Trumbore ray and triangle intersection
+		v1, v2, v3 := m.Vertices[f.V[0]], m.Vertices[f.V[1]], m.Vertices[f.V[2]]
+		e1, e2 := sub(v2, v1), sub(v3, v1)
+		h := cross(dir, e2)
+		det := dot(e1, h)
+		if math.Abs(det) < 1e-12 {
+			continue
+		}
+		s := sub(p, v1)
+		u := dot(s, h) / det
+		if u < 0 || u > 1 {
+			continue
+		}
+		q := cross(s, e1)
+		v := dot(dir, q) / det
+		if v < 0 || u+v > 1 {
+			continue
+		}
+		if dot(e2, q)/det > 0 {
+			crossings++
+		}
+	}
+	return crossings%2 == 1
+}
@@ -0,0 +1,103 @@
+package types
+
+import "testing"
+
+// testQuad returns two triangles sharing an edge, with the given normal.
+func testQuad(normal Point3D) []Triangle {
+	a, b, c, d := Point3D{X: 0}, Point3D{X: 1}, Point3D{X: 1, Y: 1}, Point3D{Y: 1}
+	return []Triangle{
+		{Normal: normal, V1: a, V2: b, V3: c},
+		{Normal: normal, V1: a, V2: c, V3: d},
+	}
+}
+
+func TestNewMesh(t *testing.T) {
+	quad := testQuad(Point3D{Z: 1})
+	mesh := NewMesh(quad)
+
+	if len(mesh.Vertices) != 4 {
+		t.Errorf("NewMesh() vertices = %d, want 4 shared vertices", len(mesh.Vertices))
+	}
+	if mesh.Len() != 2 {
+		t.Errorf("NewMesh() faces = %d, want 2", mesh.Len())
+	}
+	if mesh.Faces[0].V != [3]uint32{0, 1, 2} || mesh.Faces[1].V != [3]uint32{0, 2, 3} {
+		t.Errorf("NewMesh() faces = %v, want vertices numbered in order of first appearance", mesh.Faces)
+	}
+
+	got := mesh.Triangles()
+	if len(got) != len(quad) {
+		t.Fatalf("Triangles() returned %d triangles, want %d", len(got), len(quad))
+	}
+	for i := range quad {
+		if got[i] != quad[i] {
+			t.Errorf("Triangles()[%d] = %+v, want %+v", i, got[i], quad[i])
+		}
+	}
+
+	var empty Mesh
+	if empty.Len() != 0 || len(empty.Triangles()) != 0 {
+		t.Error("zero Mesh is not empty")
+	}
+}
+
+func TestMeshAddAndTransform(t *testing.T) {
+	mesh := NewMesh(testQuad(Point3D{Z: 1})[:1])
+	mesh.Add(testQuad(Point3D{Z: 1})[1])
+	if len(mesh.Vertices) != 4 || mesh.Len() != 2 {
+		t.Errorf("Add() gave %d vertices and %d faces, want 4 and 2", len(mesh.Vertices), mesh.Len())
+	}
+
+	mesh.Transform(func(p Point3D) Point3D { return Point3D{X: p.X + 1, Y: p.Y, Z: p.Z} })
+	if got := mesh.Triangle(1).V3; got != (Point3D{X: 1, Y: 1}) {
+		t.Errorf("Transform() moved shared vertex to %v, want {1 1 0}", got)
+	}
+
+	// Vertices added after a transform are matched against their new positions
+	mesh.Add(Triangle{V1: Point3D{X: 1}, V2: Point3D{X: 2}, V3: Point3D{X: 9}})
+	if len(mesh.Vertices) != 5 {
+		t.Errorf("Add() after Transform() gave %d vertices, want 5", len(mesh.Vertices))
+	}
+}
+
+func TestModelMesh(t *testing.T) {
+	model := &Model{
+		Objects: []ModelObject{
+			{Name: "a", Mesh: NewMesh(testQuad(Point3D{Z: 1})[:1])},
+			{Name: "b", Mesh: NewMesh(testQuad(Point3D{Z: 1})[1:])},
+		},
+	}
+
+	mesh := model.Mesh()
+	if len(mesh.Vertices) != 4 || mesh.Len() != 2 {
+		t.Errorf("Mesh() gave %d vertices and %d faces, want vertices shared between objects", len(mesh.Vertices), mesh.Len())
+	}
+	if mesh.Triangle(1) != model.Objects[1].Mesh.Triangle(0) {
+		t.Error("Mesh() did not keep object order")
+	}
+}
+
+func TestMeshContains(t *testing.T) {
+	// A tetrahedron with outward facing triangles
+	a, b, c, d := Point3D{}, Point3D{X: 1}, Point3D{Y: 1}, Point3D{Z: 1}
+	mesh := NewMesh([]Triangle{
+		{V1: a, V2: c, V3: b},
+		{V1: a, V2: b, V3: d},
+		{V1: a, V2: d, V3: c},
+		{V1: b, V2: c, V3: d},
+	})
+
+	tests := []struct {
+		p    Point3D
+		want bool
+	}{
+		{Point3D{X: 0.1, Y: 0.1, Z: 0.1}, true},
+		{Point3D{X: 0.5, Y: 0.5, Z: 0.5}, false},
+		{Point3D{X: -1, Y: 0.1, Z: 0.1}, false},
+	}
+	for _, tt := range tests {
+		if got := mesh.Contains(tt.p); got != tt.want {
+			t.Errorf("Contains(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
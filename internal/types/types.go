@@ -4,6 +4,7 @@ package types //nolint:revive // package name is appropriate for this internal m
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"time"
 )
@@ -41,7 +42,10 @@ type ContributionsResponse struct {
 	User struct {
 		Login                   string `json:"login"`
 		ContributionsCollection struct {
-			ContributionCalendar struct {
+			// Contributions to repositories the viewer cannot see, which the
+			// calendar counts when the user shares them on their profile
+			RestrictedContributionsCount int `json:"restrictedContributionsCount"`
+			ContributionCalendar         struct {
 				TotalContributions int `json:"totalContributions"`
 				Weeks              []struct {
 					ContributionDays []ContributionDay `json:"contributionDays"`
@@ -126,3 +130,190 @@ func (t Triangle) ToFloat32() TriangleFloat32 {
 		V3:     t.V3.ToFloat32(),
 	}
 }
+
+// ObjectKind identifies which component of the model an object belongs to.
+type ObjectKind string
+
+// Object kinds produced by the model generator.
+const (
+	ObjectBase    ObjectKind = "base"    // The plinth the skyline stands on
+	ObjectTower   ObjectKind = "tower"   // A single contribution column
+	ObjectSurface ObjectKind = "surface" // Continuous surface over a year's contributions, in place of its towers
+	ObjectFuture  ObjectKind = "future"  // Low dots marking the days of a year still to come
+	ObjectText    ObjectKind = "text"    // Embossed username and year
+	ObjectLogo    ObjectKind = "logo"    // Embossed GitHub logo
+	ObjectQR      ObjectKind = "qr"      // Embossed QR code on the back face
+	ObjectStats   ObjectKind = "stats"   // Embossed statistics on the back face
+	ObjectAvatar  ObjectKind = "avatar"  // Lithophane panel of the user's avatar
+	ObjectMonths  ObjectKind = "months"  // Embossed month labels along the front
+	ObjectYears   ObjectKind = "years"   // Embossed year labels beside each year's towers
+	ObjectMold    ObjectKind = "mold"    // Block holding the negative of the base and towers
+)
+
+// Material identifies the color an object is printed in by multi-material formats.
+type Material string
+
+// Materials assigned by the model generator. Towers use GitHub's four
+// contribution levels, from the fewest contributions to the most.
+const (
+	MaterialBase   Material = "base"    // The plinth
+	MaterialEmboss Material = "emboss"  // Embossed text, logo, QR code and statistics
+	MaterialPanel  Material = "panel"   // Translucent lithophane panel
+	MaterialLevel1 Material = "level-1" // Fewest contributions
+	MaterialLevel2 Material = "level-2"
+	MaterialLevel3 Material = "level-3"
+	MaterialLevel4 Material = "level-4" // Most contributions
+)
+
+// Level returns the contribution level of m, from 0 for the fewest
+// contributions to 3 for the most, and whether m is of a level at all.
+func (m Material) Level() (int, bool) {
+	switch m {
+	case MaterialLevel1:
+		return 0, true
+	case MaterialLevel2:
+		return 1, true
+	case MaterialLevel3:
+		return 2, true
+	case MaterialLevel4:
+		return 3, true
+	default:
+		return 0, false
+	}
+}
+
+// Metadata is a key/value annotation attached to a model or one of its objects.
+// Metadata is kept in slices rather than maps so that exported files are stable.
+type Metadata struct {
+	Key   string
+	Value string
+}
+
+// ModelObject is a named mesh that forms one component of a model,
+// such as the base, a single contribution tower, the text or the logo.
+type ModelObject struct {
+	Name     string
+	Kind     ObjectKind
+	Material Material // Color of every triangle in the object, empty when unassigned
+	Mesh     Mesh
+	Metadata []Metadata
+}
+
+// Unit is the length unit of a model's coordinates.
+type Unit string
+
+// Supported model units.
+const (
+	UnitMillimeter Unit = "mm" // Millimeters, the unit geometry is generated in
+	UnitInch       Unit = "in" // Inches, for imperial workflows and CNC tooling
+)
+
+// Millimeters returns the length of one unit in millimeters.
+// The empty Unit is treated as millimeters.
+func (u Unit) Millimeters() float64 {
+	if u == UnitInch {
+		return 25.4
+	}
+	return 1
+}
+
+// ToMillimeters converts a length in u to millimeters.
+func (u Unit) ToMillimeters(value float64) float64 {
+	return value * u.Millimeters()
+}
+
+// Format formats a length in millimeters in u, followed by its symbol, as
+// messages to users give it, such as 3.5mm or 0.1378in.
+func (u Unit) Format(millimeters float64) string {
+	if u == "" {
+		u = UnitMillimeter
+	}
+	return fmt.Sprintf("%.4g%s", millimeters/u.Millimeters(), u)
+}
+
+// Model is a 3D model composed of objects, in the order they were generated.
+// Coordinates are in millimeters unless Unit says otherwise.
+type Model struct {
+	Objects  []ModelObject
+	Metadata []Metadata
+	Unit     Unit
+}
+
+// TriangleCount returns the total number of triangles across all objects.
+func (m *Model) TriangleCount() int {
+	count := 0
+	for _, obj := range m.Objects {
+		count += obj.Mesh.Len()
+	}
+	return count
+}
+
+// Triangles returns the triangles of all objects flattened into a single slice,
+// preserving object order. Formats without object support write this list.
+func (m *Model) Triangles() []Triangle {
+	triangles := make([]Triangle, 0, m.TriangleCount())
+	for _, obj := range m.Objects {
+		triangles = append(triangles, obj.Mesh.Triangles()...)
+	}
+	return triangles
+}
+
+// Mesh returns the meshes of all objects merged into a single mesh, preserving
+// object order. Vertices shared between objects are stored once.
+func (m *Model) Mesh() Mesh {
+	var mesh Mesh
+	for _, obj := range m.Objects {
+		for i := range obj.Mesh.Faces {
+			mesh.Add(obj.Mesh.Triangle(i))
+		}
+	}
+	return mesh
+}
+
+// Bounds returns the minimum and maximum corners of the axis-aligned box
+// enclosing every vertex in the model. An empty model has zero bounds.
+func (m *Model) Bounds() (minPoint, maxPoint Point3D) {
+	first := true
+	for _, obj := range m.Objects {
+		for _, v := range obj.Mesh.Vertices {
+			if first {
+				minPoint, maxPoint = v, v
+				first = false
+				continue
+			}
+			minPoint = Point3D{X: math.Min(minPoint.X, v.X), Y: math.Min(minPoint.Y, v.Y), Z: math.Min(minPoint.Z, v.Z)}
+			maxPoint = Point3D{X: math.Max(maxPoint.X, v.X), Y: math.Max(maxPoint.Y, v.Y), Z: math.Max(maxPoint.Z, v.Z)}
+		}
+	}
+	return minPoint, maxPoint
+}
+
+// Mirror reflects the model left to right, across the vertical plane through
+// the center of its bounds, so it keeps its place. Reflecting a triangle turns
+// it inside out, so each triangle's winding is reversed and its normal
+// reflected to keep it facing outward.
+func (m *Model) Mirror() {
+	minPoint, maxPoint := m.Bounds()
+	center := minPoint.X + maxPoint.X
+	for i := range m.Objects {
+		mesh := &m.Objects[i].Mesh
+		mesh.Transform(func(p Point3D) Point3D {
+			return Point3D{X: center - p.X, Y: p.Y, Z: p.Z}
+		})
+		for j := range mesh.Faces {
+			f := &mesh.Faces[j]
+			f.V[1], f.V[2] = f.V[2], f.V[1]
+			f.Normal.X = -f.Normal.X
+		}
+	}
+}
+
+// Scale multiplies every vertex in the model by factor, about the origin.
+// Normals are unchanged because the scaling is uniform.
+func (m *Model) Scale(factor float64) {
+	for i := range m.Objects {
+		m.Objects[i].Mesh.Transform(func(p Point3D) Point3D {
+			return Point3D{X: p.X * factor, Y: p.Y * factor, Z: p.Z * factor}
+		})
+	}
+}
@@ -413,3 +413,142 @@ func TestPoint3DFloat32Construction(t *testing.T) {
 		})
 	}
 }
+
+// TestModelTriangles tests flattening of model objects into a triangle list
+func TestModelTriangles(t *testing.T) {
+	first := Triangle{V1: Point3D{X: 1}}
+	second := Triangle{V1: Point3D{X: 2}}
+	third := Triangle{V1: Point3D{X: 3}}
+
+	model := &Model{
+		Objects: []ModelObject{
+			{Name: "base", Kind: ObjectBase, Mesh: NewMesh([]Triangle{first})},
+			{Name: "empty", Kind: ObjectText},
+			{Name: "tower", Kind: ObjectTower, Mesh: NewMesh([]Triangle{second, third})},
+		},
+	}
+
+	if got := model.TriangleCount(); got != 3 {
+		t.Errorf("TriangleCount() = %d, want 3", got)
+	}
+
+	triangles := model.Triangles()
+	want := []Triangle{first, second, third}
+	if len(triangles) != len(want) {
+		t.Fatalf("Triangles() returned %d triangles, want %d", len(triangles), len(want))
+	}
+	for i := range want {
+		if triangles[i] != want[i] {
+			t.Errorf("Triangles()[%d] = %v, want %v", i, triangles[i], want[i])
+		}
+	}
+
+	empty := &Model{}
+	if got := empty.Triangles(); len(got) != 0 {
+		t.Errorf("Triangles() on empty model returned %d triangles", len(got))
+	}
+}
+
+func TestModelBoundsAndScale(t *testing.T) {
+	model := &Model{
+		Objects: []ModelObject{
+			{Name: "a", Mesh: NewMesh([]Triangle{{V1: Point3D{X: -1, Y: 2, Z: 0}, V2: Point3D{X: 4, Y: 0, Z: 1}, V3: Point3D{X: 0, Y: 5, Z: -2}}})},
+			{Name: "b", Mesh: NewMesh([]Triangle{{Normal: Point3D{Z: 1}, V1: Point3D{X: 2, Y: 1, Z: 3}}})},
+		},
+	}
+
+	minPoint, maxPoint := model.Bounds()
+	if minPoint != (Point3D{X: -1, Y: 0, Z: -2}) || maxPoint != (Point3D{X: 4, Y: 5, Z: 3}) {
+		t.Errorf("Bounds() = %v, %v, want {-1 0 -2}, {4 5 3}", minPoint, maxPoint)
+	}
+
+	model.Scale(2)
+	minPoint, maxPoint = model.Bounds()
+	if minPoint != (Point3D{X: -2, Y: 0, Z: -4}) || maxPoint != (Point3D{X: 8, Y: 10, Z: 6}) {
+		t.Errorf("Bounds() after Scale(2) = %v, %v", minPoint, maxPoint)
+	}
+	if n := model.Objects[1].Mesh.Triangle(0).Normal; n != (Point3D{Z: 1}) {
+		t.Errorf("Scale() changed normal to %v", n)
+	}
+
+	empty := &Model{}
+	if minPoint, maxPoint := empty.Bounds(); minPoint != (Point3D{}) || maxPoint != (Point3D{}) {
+		t.Errorf("Bounds() on empty model = %v, %v, want zero", minPoint, maxPoint)
+	}
+}
+
+func TestModelMirror(t *testing.T) {
+	model := &Model{
+		Objects: []ModelObject{
+			{Name: "a", Mesh: NewMesh([]Triangle{{Normal: Point3D{X: 0.6, Z: 0.8}, V1: Point3D{X: 1, Y: 0, Z: 0}, V2: Point3D{X: 3, Y: 0, Z: 0}, V3: Point3D{X: 3, Y: 2, Z: 1}}})},
+			{Name: "b", Mesh: NewMesh([]Triangle{{V1: Point3D{X: 5, Y: 1, Z: 1}, V2: Point3D{X: 5, Y: 1, Z: 1}, V3: Point3D{X: 5, Y: 1, Z: 1}}})},
+		},
+	}
+
+	model.Mirror()
+	minPoint, maxPoint := model.Bounds()
+	if minPoint.X != 1 || maxPoint.X != 5 {
+		t.Errorf("Bounds() after Mirror() span X %v to %v, want the model kept in place from 1 to 5", minPoint.X, maxPoint.X)
+	}
+
+	// The winding is reversed, so the reflected triangle still faces outward
+	want := Triangle{Normal: Point3D{X: -0.6, Z: 0.8}, V1: Point3D{X: 5, Y: 0, Z: 0}, V2: Point3D{X: 3, Y: 2, Z: 1}, V3: Point3D{X: 3, Y: 0, Z: 0}}
+	if got := model.Objects[0].Mesh.Triangle(0); got != want {
+		t.Errorf("Mirror() = %+v, want %+v", got, want)
+	}
+	if got := model.Objects[1].Mesh.Triangle(0).V1; got != (Point3D{X: 1, Y: 1, Z: 1}) {
+		t.Errorf("Mirror() moved %v, want {1 1 1}", got)
+	}
+}
+
+func TestUnitToMillimeters(t *testing.T) {
+	tests := []struct {
+		unit Unit
+		want float64
+	}{
+		{"", 2},
+		{UnitMillimeter, 2},
+		{UnitInch, 50.8},
+	}
+	for _, tt := range tests {
+		if got := tt.unit.ToMillimeters(2); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Unit(%q).ToMillimeters(2) = %v, want %v", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestUnitFormat(t *testing.T) {
+	tests := []struct {
+		unit Unit
+		want string
+	}{
+		{"", "142.5mm"},
+		{UnitMillimeter, "142.5mm"},
+		{UnitInch, "5.61in"},
+	}
+	for _, tt := range tests {
+		if got := tt.unit.Format(142.5); got != tt.want {
+			t.Errorf("Unit(%q).Format(142.5) = %q, want %q", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestMaterialLevel(t *testing.T) {
+	tests := []struct {
+		material Material
+		want     int
+		ok       bool
+	}{
+		{MaterialLevel1, 0, true},
+		{MaterialLevel2, 1, true},
+		{MaterialLevel3, 2, true},
+		{MaterialLevel4, 3, true},
+		{MaterialBase, 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.material.Level(); got != tt.want || ok != tt.ok {
+			t.Errorf("%q.Level() = %d, %v, want %d, %v", tt.material, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -3,15 +3,24 @@ package utils //nolint:revive // package name is appropriate for this internal m
 
 import (
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// Constants for GitHub launch year and default output file format
+// GitHubLaunchYear is the first year there are contributions of.
+const GitHubLaunchYear = 2008
+
+// outputFileFormat is the default output file name, from the user, the period
+// covered and the extension.
+const outputFileFormat = "%s-%s-github-skyline%s"
+
+// Placeholders in an output path replaced with the user and the period the
+// output covers.
 const (
-	githubLaunchYear = 2008
-	outputFileFormat = "%s-%s-github-skyline.stl"
+	UserPlaceholder = "{user}"
+	YearPlaceholder = "{year}"
 )
 
 // ParseYearRange parses whether a year is a single year or a range of years.
@@ -44,8 +53,8 @@ func ParseYearRange(yearRange string) (startYear, endYear int, err error) {
 // the start year is not greater than the end year.
 func validateYearRange(startYear, endYear int) error {
 	currentYear := time.Now().Year()
-	if startYear < githubLaunchYear || endYear > currentYear {
-		return fmt.Errorf("years must be between %d and %d", githubLaunchYear, currentYear)
+	if startYear < GitHubLaunchYear || endYear > currentYear {
+		return fmt.Errorf("years must be between %d and %d", GitHubLaunchYear, currentYear)
 	}
 	if startYear > endYear {
 		return fmt.Errorf("start year cannot be after end year")
@@ -62,15 +71,79 @@ func FormatYearRange(startYear, endYear int) string {
 	return fmt.Sprintf("%04d-%02d", startYear, endYear%100)
 }
 
+// IsMonthRange reports whether a --year value is a range of months, such as
+// 2024-01:2024-06, rather than of years.
+func IsMonthRange(value string) bool {
+	return strings.Contains(value, ":")
+}
+
+// ParseMonthRange parses a range of months given as YYYY-MM:YYYY-MM into the
+// first day of the first month and the last day of the last month, in UTC.
+// The months must lie between GitHub's launch and the current month.
+func ParseMonthRange(value string) (from, to time.Time, err error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid month range format, want YYYY-MM:YYYY-MM")
+	}
+	from, err = time.Parse("2006-01", strings.TrimSpace(parts[0]))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid first month %q, want YYYY-MM", parts[0])
+	}
+	last, err := time.Parse("2006-01", strings.TrimSpace(parts[1]))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid last month %q, want YYYY-MM", parts[1])
+	}
+	if last.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("first month cannot be after last month")
+	}
+	now := time.Now().UTC()
+	if from.Year() < GitHubLaunchYear || last.After(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("months must be between %d-01 and %s", GitHubLaunchYear, now.Format("2006-01"))
+	}
+	return from, last.AddDate(0, 1, -1), nil
+}
+
+// FormatMonthRange returns the label of a range of months, from the month of
+// from to that of to: YYYY.MM-MM within a year, or YYYY.MM-YYYY.MM across
+// years.
+func FormatMonthRange(from, to time.Time) string {
+	if from.Year() == to.Year() {
+		return fmt.Sprintf("%04d.%02d-%02d", from.Year(), from.Month(), to.Month())
+	}
+	return fmt.Sprintf("%04d.%02d-%04d.%02d", from.Year(), from.Month(), to.Year(), to.Month())
+}
+
 // GenerateOutputFilename creates a consistent filename for the STL output
 func GenerateOutputFilename(user string, startYear, endYear int, output string) string {
+	return GenerateOutputFilenameWithExt(user, startYear, endYear, output, ".stl")
+}
+
+// GenerateOutputFilenameWithExt creates a consistent filename for output with the given
+// extension (including the leading dot), appending the extension to a user supplied
+// output path that does not already end with it.
+func GenerateOutputFilenameWithExt(user string, startYear, endYear int, output, ext string) string {
+	return GenerateOutputFilenameForPeriod(user, FormatYearRange(startYear, endYear), output, ext)
+}
+
+// GenerateOutputFilenameForPeriod creates a filename for output as
+// GenerateOutputFilenameWithExt does, naming the period covered with a label
+// such as FormatMonthRange returns in place of the years. UserPlaceholder and
+// YearPlaceholder in a user supplied output path are replaced with the user
+// and the period.
+func GenerateOutputFilenameForPeriod(user, period, output, ext string) string {
 	if output != "" {
-		// Ensure the filename ends with .stl
-		if !strings.HasSuffix(strings.ToLower(output), ".stl") {
-			return output + ".stl"
+		output = strings.NewReplacer(UserPlaceholder, user, YearPlaceholder, period).Replace(output)
+		// Ensure the filename ends with the expected extension
+		if !strings.HasSuffix(strings.ToLower(output), strings.ToLower(ext)) {
+			return output + ext
 		}
 		return output
 	}
-	yearStr := FormatYearRange(startYear, endYear)
-	return fmt.Sprintf(outputFileFormat, user, yearStr)
+	return fmt.Sprintf(outputFileFormat, user, period, ext)
+}
+
+// OutputInDir returns an output path naming files in dir as they are named by
+// default, for GenerateOutputFilenameForPeriod to complete.
+func OutputInDir(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf(outputFileFormat, UserPlaceholder, YearPlaceholder, ""))
 }
@@ -1,6 +1,9 @@
 package utils //nolint:revive // package name is appropriate for this internal module
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestParseYearRange(t *testing.T) {
 	tests := []struct {
@@ -173,3 +176,91 @@ func TestGenerateOutputFilename(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateOutputFilenameWithExt(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		ext    string
+		want   string
+	}{
+		{"default name", "", ".ply", "testuser-2024-github-skyline.ply"},
+		{"appends extension", "myoutput", ".ply", "myoutput.ply"},
+		{"keeps extension", "myoutput.PLY", ".ply", "myoutput.PLY"},
+		{"different extension", "myoutput.stl", ".ply", "myoutput.stl.ply"},
+		{"template", "models/{user}-{year}.ply", ".ply", "models/testuser-2024.ply"},
+		{"output directory", OutputInDir("models"), ".ply", "models/testuser-2024-github-skyline.ply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateOutputFilenameWithExt("testuser", 2024, 2024, tt.output, tt.ext)
+			if got != tt.want {
+				t.Errorf("GenerateOutputFilenameWithExt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMonthRange(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	nextMonth := time.Now().UTC().AddDate(0, 1, 0).Format("2006-01")
+	tests := []struct {
+		name     string
+		value    string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{"half year", "2024-01:2024-06", date(2024, time.January, 1), date(2024, time.June, 30), false},
+		{"across years", "2023-10:2024-03", date(2023, time.October, 1), date(2024, time.March, 31), false},
+		{"one month", "2024-02:2024-02", date(2024, time.February, 1), date(2024, time.February, 29), false},
+		{"reversed", "2024-06:2024-01", time.Time{}, time.Time{}, true},
+		{"before GitHub", "2007-12:2008-02", time.Time{}, time.Time{}, true},
+		{"future month", "2024-01:" + nextMonth, time.Time{}, time.Time{}, true},
+		{"not a month", "2024-13:2024-14", time.Time{}, time.Time{}, true},
+		{"missing end", "2024-01:", time.Time{}, time.Time{}, true},
+		{"too many parts", "2024-01:2024-02:2024-03", time.Time{}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParseMonthRange(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMonthRange(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("ParseMonthRange(%q) = %v, %v, want %v, %v", tt.value, from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+
+	if !IsMonthRange("2024-01:2024-06") || IsMonthRange("2020-2024") {
+		t.Error("IsMonthRange() does not tell month ranges from year ranges")
+	}
+}
+
+func TestFormatMonthRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     string
+	}{
+		{"within a year", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC), "2024.01-06"},
+		{"across years", time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), "2023.10-2024.03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatMonthRange(tt.from, tt.to); got != tt.want {
+				t.Errorf("FormatMonthRange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := GenerateOutputFilenameForPeriod("mona", "2024.01-06", "", ".stl"); got != "mona-2024.01-06-github-skyline.stl" {
+		t.Errorf("GenerateOutputFilenameForPeriod() = %q", got)
+	}
+}
@@ -0,0 +1,118 @@
+// Package viewer serves a generated model to the browser on the local
+// machine, with a WebGL page for turning it around and zooming in on it before
+// it is written to a file.
+package viewer
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// page is the viewer, a single page with no dependencies fetching the model
+// from the path modelPath.
+//
+//go:embed viewer.html
+var page []byte
+
+// modelPath is the path the model's meshes are served at.
+const modelPath = "/model.bin"
+
+// shutdownTimeout bounds how long requests in flight are waited for once the
+// viewer is stopped.
+const shutdownTimeout = 5 * time.Second
+
+// Handler returns an HTTP handler serving the viewer page at the root and the
+// meshes of model at modelPath, encoded as Encode does.
+func Handler(model *types.Model) (http.Handler, error) {
+	encoded, err := Encode(model)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(page)
+	})
+	mux.HandleFunc("GET "+modelPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(encoded)
+	})
+	return mux, nil
+}
+
+// Serve serves the viewer of model on a free port of the loopback interface,
+// calling ready with the viewer's address once it is listening, until ctx is
+// done.
+func Serve(ctx context.Context, model *types.Model, ready func(url string) error) error {
+	handler, err := Handler(model)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.New(errors.NetworkError, "failed to start the viewer", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: shutdownTimeout}
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(listener) }()
+
+	if err := ready(fmt.Sprintf("http://%s/", listener.Addr())); err != nil {
+		_ = server.Close()
+		return err
+	}
+
+	select {
+	case err := <-served:
+		return errors.New(errors.NetworkError, "the viewer stopped", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return errors.New(errors.NetworkError, "failed to stop the viewer", err)
+	}
+	return nil
+}
+
+// Encode packs the meshes of a model for the viewer page, in little-endian
+// order: the number of objects as a uint32, then for each object its color as
+// four bytes of RGBA, its number of triangles as a uint32 and, for each
+// triangle, its normal and three vertices as twelve float32s.
+func Encode(model *types.Model) ([]byte, error) {
+	if model == nil {
+		return nil, errors.New(errors.ValidationError, "model cannot be nil", nil)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(4 + len(model.Objects)*8 + model.TriangleCount()*12*4)
+	write := func(data any) { _ = binary.Write(&buf, binary.LittleEndian, data) }
+
+	write(uint32(len(model.Objects)))
+	for _, obj := range model.Objects {
+		c := render.ObjectColor(obj.Kind)
+		write([4]uint8{c.R, c.G, c.B, c.A})
+		triangles := obj.Mesh.Triangles()
+		write(uint32(len(triangles)))
+		values := make([]float32, 0, len(triangles)*12)
+		for _, t := range triangles {
+			for _, p := range []types.Point3D{t.Normal, t.V1, t.V2, t.V3} {
+				values = append(values, float32(p.X), float32(p.Y), float32(p.Z))
+			}
+		}
+		write(values)
+	}
+	return buf.Bytes(), nil
+}
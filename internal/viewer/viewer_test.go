@@ -0,0 +1,120 @@
+package viewer
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/render"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// createTestModel returns a model with a base and a single tower standing on it.
+func createTestModel(t *testing.T) *types.Model {
+	t.Helper()
+	base, err := geometry.CreateCuboidBase(20, 10)
+	if err != nil {
+		t.Fatalf("CreateCuboidBase() error = %v", err)
+	}
+	tower, err := geometry.CreateColumn(5, 5, 10, 2.5)
+	if err != nil {
+		t.Fatalf("CreateColumn() error = %v", err)
+	}
+	return &types.Model{Objects: []types.ModelObject{
+		{Name: "base", Kind: types.ObjectBase, Mesh: types.NewMesh(base)},
+		{Name: "tower", Kind: types.ObjectTower, Mesh: types.NewMesh(tower)},
+	}}
+}
+
+func TestEncode(t *testing.T) {
+	model := createTestModel(t)
+	data, err := Encode(model)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if want := 4 + len(model.Objects)*8 + model.TriangleCount()*12*4; len(data) != want {
+		t.Fatalf("Encode() returned %d bytes, want %d", len(data), want)
+	}
+	if got := binary.LittleEndian.Uint32(data); got != uint32(len(model.Objects)) {
+		t.Errorf("Encode() object count = %d, want %d", got, len(model.Objects))
+	}
+
+	base := render.ObjectColor(types.ObjectBase)
+	if got := [4]uint8(data[4:8]); got != [4]uint8{base.R, base.G, base.B, base.A} {
+		t.Errorf("Encode() base color = %v, want %v", got, base)
+	}
+	if got := binary.LittleEndian.Uint32(data[8:]); got != uint32(model.Objects[0].Mesh.Len()) {
+		t.Errorf("Encode() base triangle count = %d, want %d", got, model.Objects[0].Mesh.Len())
+	}
+	first := model.Objects[0].Mesh.Triangle(0)
+	if got := math.Float32frombits(binary.LittleEndian.Uint32(data[12+3*4:])); got != float32(first.V1.X) {
+		t.Errorf("Encode() first vertex X = %v, want %v", got, first.V1.X)
+	}
+
+	if _, err := Encode(nil); err == nil {
+		t.Error("Encode(nil) returned no error")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler, err := Handler(createTestModel(t))
+	if err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	tests := []struct {
+		path        string
+		wantStatus  int
+		wantType    string
+		wantContent string
+	}{
+		{"/", http.StatusOK, "text/html; charset=utf-8", modelPath},
+		{modelPath, http.StatusOK, "application/octet-stream", ""},
+		{"/missing", http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+			if tt.wantType != "" && rec.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("GET %s Content-Type = %q, want %q", tt.path, rec.Header().Get("Content-Type"), tt.wantType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantContent) {
+				t.Errorf("GET %s body does not contain %q", tt.path, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var body []byte
+	err := Serve(ctx, createTestModel(t), func(url string) error {
+		defer cancel()
+		resp, err := http.Get(url + strings.TrimPrefix(modelPath, "/"))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err = io.ReadAll(resp.Body)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	if len(body) == 0 {
+		t.Error("Serve() served an empty model")
+	}
+}
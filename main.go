@@ -4,6 +4,8 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	_ "time/tzdata" // Embed the time zone database for --timezone on systems without one
 
 	"github.com/github/gh-skyline/cmd"
 )
@@ -22,7 +24,8 @@ func main() {
 
 func start() exitCode {
 	exitCode := exitOK
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
 	if err := cmd.Execute(ctx); err != nil {
 		exitCode = exitError
@@ -0,0 +1,116 @@
+// Package geometry is the public face of the mesh generation behind gh skyline,
+// for Go tools that want skyline-style geometry without running the CLI.
+//
+// Everything is built from triangles in millimeters, in a right-handed
+// coordinate system with X running left to right, Y front to back and Z
+// upward. Functions return triangles with outward facing normals, so their
+// results can be combined and written as one model with WriteSTL:
+//
+//	cube, err := geometry.CreateCube(0, 0, 0, 10, 10, 10)
+//	if err != nil {
+//		return err
+//	}
+//	return geometry.WriteSTL("cube.stl", cube)
+//
+// The identifiers in this package are a stable API: they keep their names,
+// signatures and meaning across releases, while the internal packages they
+// are built on remain free to change.
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Point is a position or direction in 3D space.
+type Point = types.Point3D
+
+// Triangle is a face with its outward facing normal and three vertices,
+// listed counter-clockwise when seen from outside.
+type Triangle = types.Triangle
+
+// ContributionDay is a day of a contribution calendar, with its date in
+// YYYY-MM-DD form and its number of contributions.
+type ContributionDay = types.ContributionDay
+
+// Dimensions of a standard skyline, in millimeters.
+const (
+	BaseHeight float64 = geometry.BaseHeight // Height of the base below the towers
+	MinHeight  float64 = geometry.MinHeight  // Height of the tower of the quietest day with contributions
+	MaxHeight  float64 = geometry.MaxHeight  // Height of the tower of the busiest day
+	CellSize   float64 = geometry.CellSize   // Width and depth of each day's tower
+	GridSize   int     = geometry.GridSize   // Number of weeks in a year's grid
+)
+
+// CreateQuad returns the two triangles of the quadrilateral with the given
+// corners, listed counter-clockwise when seen from outside.
+// Returns an error if the corners are degenerate or not finite.
+func CreateQuad(v1, v2, v3, v4 Point) ([]Triangle, error) {
+	return geometry.CreateQuad(v1, v2, v3, v4)
+}
+
+// CreateCube returns the twelve triangles of a box whose front bottom left
+// corner is at (x, y, z), extending width along X, height along Y and depth
+// along Z.
+func CreateCube(x, y, z, width, height, depth float64) ([]Triangle, error) {
+	return geometry.CreateCube(x, y, z, width, height, depth)
+}
+
+// CreateColumn returns a square column of the given size with its front left
+// corner at (x, y), standing on the top of a standard base at Z = 0 and
+// rising height above it.
+func CreateColumn(x, y, height, size float64) ([]Triangle, error) {
+	return geometry.CreateColumn(x, y, height, size)
+}
+
+// CreateCuboidBase returns a standard base of the given width and depth, with
+// its front left corner at the origin and its top at Z = 0, BaseHeight above
+// its bottom.
+func CreateCuboidBase(width, depth float64) ([]Triangle, error) {
+	return geometry.CreateCuboidBase(width, depth)
+}
+
+// CreateText returns the username and year embossed in voxels on the front
+// face of a base of the given width and height, as on a skyline.
+func CreateText(username, year string, baseWidth, baseHeight float64) ([]Triangle, error) {
+	return geometry.Create3DText(username, year, baseWidth, baseHeight)
+}
+
+// CreateLogo returns the GitHub logo embossed on the front face of a base of
+// the given width and height, as on a skyline.
+func CreateLogo(baseWidth, baseHeight float64) ([]Triangle, error) {
+	return geometry.GenerateImageGeometry(baseWidth, baseHeight)
+}
+
+// CreateContributionGeometry returns the towers of a year's contributions,
+// given as weeks of days, standing on a standard base. The yearIndex places
+// the year behind the previous ones in a model of several years, and the
+// towers are scaled so maxContrib contributions reach MaxHeight.
+func CreateContributionGeometry(weeks [][]ContributionDay, yearIndex, maxContrib int) ([]Triangle, error) {
+	return geometry.CreateContributionGeometry(weeks, yearIndex, maxContrib)
+}
+
+// NormalizeContribution returns the height of the tower for count
+// contributions when maxCount reaches MaxHeight: 0 for no contributions, or
+// a height between MinHeight and MaxHeight.
+func NormalizeContribution(count, maxCount int) float64 {
+	return geometry.NormalizeContribution(count, maxCount)
+}
+
+// CalculateMultiYearDimensions returns the width and depth of a standard base
+// holding the given number of years.
+func CalculateMultiYearDimensions(yearCount int) (width, depth float64) {
+	return geometry.CalculateMultiYearDimensions(yearCount)
+}
+
+// WriteSTL writes the triangles to filename as a binary STL file.
+func WriteSTL(filename string, triangles []Triangle) error {
+	return stl.WriteSTLBinary(filename, triangles)
+}
+
+// ReadSTL loads the triangles of a binary or ASCII STL file, such as an
+// external mesh to combine with skyline geometry.
+func ReadSTL(filename string) ([]Triangle, error) {
+	return stl.ReadSTL(filename)
+}
@@ -0,0 +1,171 @@
+package geometry_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/pkg/geometry"
+)
+
+// TestPrimitives verifies each primitive returns its valid triangles
+func TestPrimitives(t *testing.T) {
+	tests := []struct {
+		name      string
+		create    func() ([]geometry.Triangle, error)
+		wantCount int
+	}{
+		{"quad", func() ([]geometry.Triangle, error) {
+			return geometry.CreateQuad(geometry.Point{}, geometry.Point{X: 1}, geometry.Point{X: 1, Y: 1}, geometry.Point{Y: 1})
+		}, 2},
+		{"cube", func() ([]geometry.Triangle, error) { return geometry.CreateCube(1, 2, 3, 4, 5, 6) }, 12},
+		{"column", func() ([]geometry.Triangle, error) { return geometry.CreateColumn(0, 0, 10, geometry.CellSize) }, 12},
+		{"base", func() ([]geometry.Triangle, error) { return geometry.CreateCuboidBase(100, 20) }, 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := tt.create()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if len(triangles) != tt.wantCount {
+				t.Errorf("got %d triangles, want %d", len(triangles), tt.wantCount)
+			}
+			for i, tri := range triangles {
+				if err := tri.Validate(); err != nil {
+					t.Errorf("triangle %d is invalid: %v", i, err)
+				}
+			}
+		})
+	}
+
+	if _, err := geometry.CreateCube(0, 0, 0, -1, 1, 1); err == nil {
+		t.Error("CreateCube() with a negative width error = nil, want an error")
+	}
+}
+
+// TestEmbossing verifies text and the logo are embossed on the front face
+func TestEmbossing(t *testing.T) {
+	width, _ := geometry.CalculateMultiYearDimensions(1)
+	tests := []struct {
+		name   string
+		create func() ([]geometry.Triangle, error)
+	}{
+		{"text", func() ([]geometry.Triangle, error) {
+			return geometry.CreateText("mona", "2024", width, geometry.BaseHeight)
+		}},
+		{"logo", func() ([]geometry.Triangle, error) { return geometry.CreateLogo(width, geometry.BaseHeight) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := tt.create()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if len(triangles) == 0 {
+				t.Fatal("got no triangles")
+			}
+			for _, tri := range triangles {
+				for _, v := range []geometry.Point{tri.V1, tri.V2, tri.V3} {
+					if v.X < 0 || v.X > width || v.Y > 0 || v.Z > 0 || v.Z < -geometry.BaseHeight {
+						t.Fatalf("vertex %v lies off the front face of a %v wide base", v, width)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestCreateContributionGeometry verifies the busiest day reaches MaxHeight
+func TestCreateContributionGeometry(t *testing.T) {
+	weeks := [][]geometry.ContributionDay{{
+		{Date: "2024-01-01", ContributionCount: 0},
+		{Date: "2024-01-02", ContributionCount: 3},
+		{Date: "2024-01-03", ContributionCount: 10},
+	}}
+	triangles, err := geometry.CreateContributionGeometry(weeks, 0, 10)
+	if err != nil {
+		t.Fatalf("CreateContributionGeometry() error = %v", err)
+	}
+	if len(triangles) != 24 {
+		t.Errorf("got %d triangles, want two towers of 12", len(triangles))
+	}
+	top := 0.0
+	for _, tri := range triangles {
+		top = max(top, tri.V1.Z, tri.V2.Z, tri.V3.Z)
+	}
+	if top != geometry.MaxHeight {
+		t.Errorf("towers reach %v, want %v", top, geometry.MaxHeight)
+	}
+	if got := geometry.NormalizeContribution(10, 10); got != geometry.MaxHeight {
+		t.Errorf("NormalizeContribution(10, 10) = %v, want %v", got, geometry.MaxHeight)
+	}
+}
+
+// TestWriteSTL verifies the triangles are written as a binary STL file and
+// read back unchanged
+func TestWriteSTL(t *testing.T) {
+	cube, err := geometry.CreateCube(0, 0, 0, 1, 1, 1)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "cube.stl")
+	if err := geometry.WriteSTL(path, cube); err != nil {
+		t.Fatalf("WriteSTL() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat STL file: %v", err)
+	}
+	if want := int64(84 + 50*len(cube)); info.Size() != want {
+		t.Errorf("STL file is %d bytes, want %d", info.Size(), want)
+	}
+
+	read, err := geometry.ReadSTL(path)
+	if err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+	if !reflect.DeepEqual(read, cube) {
+		t.Errorf("ReadSTL() = %v, want the written cube %v", read, cube)
+	}
+}
+
+// Example builds a single year's skyline from the public primitives.
+func Example() {
+	weeks := [][]geometry.ContributionDay{{
+		{Date: "2024-01-01", ContributionCount: 2},
+		{Date: "2024-01-02", ContributionCount: 5},
+	}}
+	width, depth := geometry.CalculateMultiYearDimensions(1)
+
+	base, err := geometry.CreateCuboidBase(width, depth)
+	if err != nil {
+		log.Fatal(err)
+	}
+	towers, err := geometry.CreateContributionGeometry(weeks, 0, 5)
+	if err != nil {
+		log.Fatal(err)
+	}
+	text, err := geometry.CreateText("mona", "2024", width, geometry.BaseHeight)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dir, err := os.MkdirTemp("", "skyline")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	model := append(append(base, towers...), text...)
+	if err := geometry.WriteSTL(filepath.Join(dir, "skyline.stl"), model); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(base), len(towers))
+	// Output: 12 24
+}